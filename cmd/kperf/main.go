@@ -22,7 +22,12 @@ import (
 )
 
 func main() {
-	if err := core.NewPerfCommand().Execute(); err != nil {
+	cmd := core.NewPerfCommand()
+	// installed as kn-perf, kperf runs as kn plugin, i.e. kn perf
+	if core.IsPluginBinary(os.Args[0]) {
+		cmd = core.NewPluginCommand(os.Args[1:])
+	}
+	if err := cmd.Execute(); err != nil {
 		log.Println("failed to execute kperf command:", err)
 		os.Exit(1)
 	}
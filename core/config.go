@@ -0,0 +1,96 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// applyConfig sets the flags of cmd which are not given on the command line from environment variables
+// and the config file. A flag is looked up in the environment variable KPERF_<COMMAND PATH>_<FLAG>,
+// e.g. KPERF_SERVICE_GENERATE_NUMBER, then in KPERF_<FLAG>, e.g. KPERF_NAMESPACE for all commands, then
+// in the config file under the command path and for global flags like the hooks at the top level of the
+// config file, e.g. for 'kperf service generate':
+//
+//	pre-hook: ./snapshot-prometheus.sh
+//	service:
+//	  generate:
+//	    number: 100
+//	    namespace-prefix: ktest
+//	    namespace-range: 1,10
+func applyConfig(cmd *cobra.Command) error {
+	path := strings.Fields(cmd.CommandPath())
+	if len(path) < 2 {
+		return nil
+	}
+	prefix := strings.Join(path[1:], ".")
+
+	var err error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed {
+			return
+		}
+		source, value, found := lookupConfig(cmd, prefix, flag.Name)
+		if !found {
+			return
+		}
+		if setErr := cmd.Flags().Set(flag.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value for %s: %s", source, setErr)
+		}
+	})
+	return err
+}
+
+// lookupConfig returns where the value of a flag was found and the value itself
+func lookupConfig(cmd *cobra.Command, prefix, name string) (string, string, bool) {
+	for _, env := range []string{configEnv(prefix + "." + name), configEnv(name)} {
+		if value, ok := os.LookupEnv(env); ok {
+			return "environment variable " + env, value, true
+		}
+	}
+	key := prefix + "." + name
+	if viper.IsSet(key) {
+		return key + " in config file", configValue(viper.Get(key)), true
+	}
+	// global flags like the hooks can also be set for all commands at the top level
+	if cmd.Root().PersistentFlags().Lookup(name) != nil && viper.IsSet(name) {
+		return name + " in config file", configValue(viper.Get(name)), true
+	}
+	return "", "", false
+}
+
+// configEnv returns the environment variable name of a config key, e.g. KPERF_SERVICE_GENERATE_SVC_PREFIX
+// for service.generate.svc-prefix
+func configEnv(key string) string {
+	return "KPERF_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+}
+
+// configValue converts a config file value to its flag representation, lists become comma separated
+func configValue(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		values := make([]string, 0, len(list))
+		for _, v := range list {
+			values = append(values, fmt.Sprint(v))
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg/testutil"
+)
+
+const testConfig = `
+service:
+  generate:
+    number: 100
+    namespace: ktest
+    check-ready: true
+    svc-prefix:
+    - a
+    - b
+`
+
+type testArgs struct {
+	number     int
+	namespace  string
+	checkReady bool
+	svcPrefix  string
+}
+
+func newTestCommand(args *testArgs) *cobra.Command {
+	rootCmd := &cobra.Command{Use: "kperf"}
+	serviceCmd := &cobra.Command{Use: "service"}
+	generateCmd := &cobra.Command{
+		Use: "generate",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return nil
+		},
+	}
+	generateCmd.Flags().IntVarP(&args.number, "number", "n", 0, "")
+	generateCmd.MarkFlagRequired("number")
+	generateCmd.Flags().StringVarP(&args.namespace, "namespace", "", "", "")
+	generateCmd.Flags().BoolVarP(&args.checkReady, "check-ready", "", false, "")
+	generateCmd.Flags().StringVarP(&args.svcPrefix, "svc-prefix", "", "", "")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		return applyConfig(cmd)
+	}
+	serviceCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(serviceCmd)
+	return rootCmd
+}
+
+func TestApplyConfig(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	assert.NilError(t, viper.ReadConfig(bytes.NewBufferString(testConfig)))
+
+	t.Run("config values are used for flags not given", func(t *testing.T) {
+		args := &testArgs{}
+		_, err := testutil.ExecuteCommand(newTestCommand(args), "service", "generate")
+		assert.NilError(t, err)
+		assert.Equal(t, 100, args.number)
+		assert.Equal(t, "ktest", args.namespace)
+		assert.Equal(t, true, args.checkReady)
+		assert.Equal(t, "a,b", args.svcPrefix)
+	})
+
+	t.Run("flags override config values", func(t *testing.T) {
+		args := &testArgs{}
+		_, err := testutil.ExecuteCommand(newTestCommand(args), "service", "generate", "-n", "5", "--namespace", "other")
+		assert.NilError(t, err)
+		assert.Equal(t, 5, args.number)
+		assert.Equal(t, "other", args.namespace)
+	})
+
+	t.Run("invalid config value", func(t *testing.T) {
+		viper.Set("service.generate.number", "many")
+		args := &testArgs{}
+		_, err := testutil.ExecuteCommand(newTestCommand(args), "service", "generate")
+		assert.ErrorContains(t, err, "invalid value for service.generate.number in config file")
+	})
+}
+
+func TestApplyConfigFromEnv(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	assert.NilError(t, viper.ReadConfig(bytes.NewBufferString(testConfig)))
+
+	defer os.Unsetenv("KPERF_SERVICE_GENERATE_NUMBER")
+	defer os.Unsetenv("KPERF_NAMESPACE")
+	defer os.Unsetenv("KPERF_SVC_PREFIX")
+	os.Setenv("KPERF_SERVICE_GENERATE_NUMBER", "7")
+	os.Setenv("KPERF_NAMESPACE", "from-env")
+	os.Setenv("KPERF_SVC_PREFIX", "x")
+
+	args := &testArgs{}
+	_, err := testutil.ExecuteCommand(newTestCommand(args), "service", "generate", "--svc-prefix", "from-flag")
+	assert.NilError(t, err)
+	assert.Equal(t, 7, args.number)
+	assert.Equal(t, "from-env", args.namespace)
+	assert.Equal(t, "from-flag", args.svcPrefix)
+	assert.Equal(t, true, args.checkReady)
+
+	os.Setenv("KPERF_SERVICE_GENERATE_NUMBER", "seven")
+	_, err = testutil.ExecuteCommand(newTestCommand(&testArgs{}), "service", "generate")
+	assert.ErrorContains(t, err, "invalid value for environment variable KPERF_SERVICE_GENERATE_NUMBER")
+}
+
+func TestConfigFromStdin(t *testing.T) {
+	defer func() {
+		cfgFile = ""
+		cfgErr = nil
+		configStdin = os.Stdin
+		viper.Reset()
+	}()
+	configStdin = bytes.NewBufferString("version:\n  pre-hook: exit 4\n")
+	cmd := NewPerfCommand()
+	_, err := testutil.ExecuteCommand(cmd, "--config", "-", "version")
+	assert.ErrorContains(t, err, "pre hook \"exit 4\" failed")
+}
+
+func TestConfigFlag(t *testing.T) {
+	defer func() {
+		cfgFile = ""
+		cfgErr = nil
+		viper.Reset()
+	}()
+	cmd := NewPerfCommand()
+	_, err := testutil.ExecuteCommand(cmd, "--config", "../test/asset/not_exist.yaml", "version")
+	assert.ErrorContains(t, err, "failed to read config file ../test/asset/not_exist.yaml")
+}
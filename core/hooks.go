@@ -0,0 +1,80 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	hookPre  = "pre"
+	hookPost = "post"
+)
+
+// hooks are shell commands run before and after a kperf command, e.g. to snapshot Prometheus
+type hooks struct {
+	Pre   string
+	Post  string
+	start time.Time
+}
+
+// run executes the hook of the given kind with sh. The run metadata is passed in environment variables:
+// KPERF_HOOK, KPERF_COMMAND, KPERF_RUN_START, KPERF_RUN_END (post hook only) and KPERF_FLAG_<NAME>
+// for every flag given on the command line or in the config file.
+func (h *hooks) run(kind string, cmd *cobra.Command) error {
+	command := h.Pre
+	if kind == hookPre {
+		h.start = time.Now()
+	} else {
+		command = h.Post
+	}
+	if command == "" {
+		return nil
+	}
+
+	env := append(os.Environ(),
+		"KPERF_HOOK="+kind,
+		"KPERF_COMMAND="+strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		"KPERF_RUN_START="+h.start.UTC().Format(time.RFC3339),
+	)
+	if kind == hookPost {
+		env = append(env, "KPERF_RUN_END="+time.Now().UTC().Format(time.RFC3339))
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		env = append(env, hookFlagEnv(flag.Name)+"="+flag.Value.String())
+	})
+
+	fmt.Printf("Running %s hook: %s\n", kind, command)
+	hook := exec.Command("sh", "-c", command)
+	hook.Env = env
+	hook.Stdout = cmd.OutOrStdout()
+	hook.Stderr = cmd.ErrOrStderr()
+	if err := hook.Run(); err != nil {
+		return fmt.Errorf("%s hook %q failed: %s", kind, command, err)
+	}
+	return nil
+}
+
+// hookFlagEnv returns the environment variable name of a flag, e.g. KPERF_FLAG_SVC_PREFIX for svc-prefix
+func hookFlagEnv(name string) string {
+	return configEnv("flag." + name)
+}
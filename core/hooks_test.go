@@ -0,0 +1,61 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestHooks(t *testing.T) {
+	t.Run("run pre and post hooks with run metadata", func(t *testing.T) {
+		dir := t.TempDir()
+		pre := filepath.Join(dir, "pre")
+		post := filepath.Join(dir, "post")
+
+		cmd := NewPerfCommand()
+		_, err := testutil.ExecuteCommand(cmd,
+			"--pre-hook", "echo $KPERF_HOOK $KPERF_COMMAND $KPERF_RUN_START > "+pre,
+			"--post-hook", "echo $KPERF_HOOK $KPERF_COMMAND $KPERF_RUN_END $KPERF_FLAG_PRE_HOOK > "+post,
+			"version")
+		assert.NilError(t, err)
+
+		data, err := ioutil.ReadFile(pre)
+		assert.NilError(t, err)
+		assert.Check(t, strings.HasPrefix(string(data), "pre version 20"), "unexpected pre hook output %q", data)
+
+		data, err = ioutil.ReadFile(post)
+		assert.NilError(t, err)
+		assert.Check(t, strings.HasPrefix(string(data), "post version 20"), "unexpected post hook output %q", data)
+		assert.Check(t, strings.Contains(string(data), "echo"), "expected flags in the environment, got %q", data)
+	})
+
+	t.Run("failing pre hook aborts the command", func(t *testing.T) {
+		cmd := NewPerfCommand()
+		_, err := testutil.ExecuteCommand(cmd, "--pre-hook", "exit 3", "version")
+		assert.ErrorContains(t, err, "pre hook \"exit 3\" failed: exit status 3")
+	})
+}
+
+func TestHookFlagEnv(t *testing.T) {
+	assert.Equal(t, "KPERF_FLAG_SVC_PREFIX", hookFlagEnv("svc-prefix"))
+	assert.Equal(t, "KPERF_FLAG_OUTPUT", hookFlagEnv("output"))
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// PluginName is the command kperf is invoked with as kn plugin, i.e. kn perf
+	PluginName = "perf"
+	// PluginBinary is the name kn discovers the plugin by on the PATH or in its plugins directory
+	PluginBinary = "kn-" + PluginName
+)
+
+// IsPluginBinary returns true if kperf is invoked as kn plugin, i.e. the executable is named kn-perf
+func IsPluginBinary(executable string) bool {
+	name := strings.TrimSuffix(filepath.Base(executable), ".exe")
+	return name == PluginBinary
+}
+
+// NewPluginCommand returns the kperf command as the perf command of kn, so that the usage reads kn perf, and the
+// args given to the kn plugin are executed by kperf
+func NewPluginCommand(args []string) *cobra.Command {
+	perfCmd := NewPerfCommand()
+	perfCmd.Use = PluginName
+	knCmd := &cobra.Command{
+		Use:          "kn",
+		SilenceUsage: true,
+	}
+	knCmd.AddCommand(perfCmd)
+	knCmd.SetArgs(append([]string{PluginName}, args...))
+	return knCmd
+}
+
+// KnPlugin describes kperf as kn plugin, it implements the plugin interface of kn, so that a kn distribution can
+// inline kperf instead of discovering the kn-perf binary
+type KnPlugin struct{}
+
+// Name is the name of the plugin as used by kn plugin list
+func (KnPlugin) Name() string {
+	return PluginBinary
+}
+
+// Description is the short description of the plugin shown by kn help
+func (KnPlugin) Description() (string, error) {
+	return NewPerfCommand().Short, nil
+}
+
+// CommandParts are the kn commands the plugin is invoked with
+func (KnPlugin) CommandParts() []string {
+	return []string{PluginName}
+}
+
+// Path is empty for an inlined plugin
+func (KnPlugin) Path() string {
+	return ""
+}
+
+// Execute runs kperf with the args given to kn perf
+func (KnPlugin) Execute(args []string) error {
+	return NewPluginCommand(args).Execute()
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIsPluginBinary(t *testing.T) {
+	assert.Assert(t, IsPluginBinary("/home/user/.config/kn/plugins/kn-perf"))
+	assert.Assert(t, IsPluginBinary("/usr/local/bin/kn-perf.exe"))
+	assert.Assert(t, !IsPluginBinary("/usr/local/bin/kperf"))
+	assert.Assert(t, !IsPluginBinary("kn-perf-old"))
+}
+
+func TestNewPluginCommand(t *testing.T) {
+	t.Run("usage reads kn perf", func(t *testing.T) {
+		cmd := NewPluginCommand([]string{"service", "measure", "--help"})
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, strings.Contains(out.String(), "Usage:\n  kn perf service measure [flags]\n"), "invalid usage %q", out.String())
+	})
+
+	t.Run("refuse mutating command in read-only mode", func(t *testing.T) {
+		cmd := NewPluginCommand([]string{"--read-only", "eventing", "clean", "--namespace", "ns"})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		assert.ErrorContains(t, cmd.Execute(), "'eventing clean' changes the cluster and is refused in read-only mode")
+	})
+
+	t.Run("kn flags", func(t *testing.T) {
+		cmd := NewPluginCommand(nil)
+		perfCmd, _, err := cmd.Find([]string{PluginName})
+		assert.NilError(t, err)
+		for _, flag := range []string{"kubeconfig", "context"} {
+			assert.Assert(t, perfCmd.PersistentFlags().Lookup(flag) != nil, "missing flag --%s", flag)
+		}
+	})
+}
+
+func TestKnPlugin(t *testing.T) {
+	plugin := KnPlugin{}
+	assert.Equal(t, "kn-perf", plugin.Name())
+	assert.DeepEqual(t, []string{"perf"}, plugin.CommandParts())
+	description, err := plugin.Description()
+	assert.NilError(t, err)
+	assert.Equal(t, "A CLI to help with Knative performance test", description)
+	assert.Equal(t, "", plugin.Path())
+}
@@ -15,11 +15,33 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"knative.dev/kperf/pkg/command/attest"
+	"knative.dev/kperf/pkg/command/autoscaler"
+	"knative.dev/kperf/pkg/command/calibrate"
+	"knative.dev/kperf/pkg/command/clean"
+	"knative.dev/kperf/pkg/command/compare"
+	"knative.dev/kperf/pkg/command/domainmapping"
+	"knative.dev/kperf/pkg/command/eventing"
+	"knative.dev/kperf/pkg/command/exporter"
+	"knative.dev/kperf/pkg/command/featurematrix"
+	"knative.dev/kperf/pkg/command/function"
+	"knative.dev/kperf/pkg/command/ingress"
+	"knative.dev/kperf/pkg/command/namespace"
+	"knative.dev/kperf/pkg/command/report"
+	"knative.dev/kperf/pkg/command/revision"
 	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
 	"knative.dev/kperf/pkg/command/version"
+	"knative.dev/kperf/pkg/command/webhook"
+	"knative.dev/kperf/pkg/command/wizard"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
@@ -27,29 +49,148 @@ import (
 	"knative.dev/kperf/pkg"
 )
 
-var cfgFile string
+var (
+	cfgFile string
+	// cfgErr is the error reading the config file given by --config
+	cfgErr error
+	// configStdin is read for --config -
+	configStdin io.Reader = os.Stdin
+)
 
 // rootCmd represents the base command when called without any subcommands
 
 func NewPerfCommand(params ...pkg.PerfParams) *cobra.Command {
 	p := &pkg.PerfParams{}
-	p.Initialize()
+	initErr := p.Initialize()
+	h := &hooks{}
+	auditDir := ""
+	meta := []string{}
 
-	rootCmd := &cobra.Command{
+	var rootCmd *cobra.Command
+	rootCmd = &cobra.Command{
 		Use:   "kperf",
 		Short: "A CLI to help with Knative performance test",
 		Long:  `A CLI to help with Knative performance test.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfgErr != nil {
+				return cfgErr
+			}
+			if err := applyConfig(cmd); err != nil {
+				return err
+			}
+			metadata, err := utils.ParseMetadata(meta)
+			if err != nil {
+				return fmt.Errorf("invalid --meta: %s", err)
+			}
+			utils.SetRunMetadata(metadata)
+			if err := utils.ValidateOutputName(p.Output.Name); err != nil {
+				return err
+			}
+			if p.ReadOnly && cmd.Annotations[pkg.MutatingAnnotation] == "true" {
+				return fmt.Errorf("'%s' changes the cluster and is refused in read-only mode", strings.TrimPrefix(cmd.CommandPath(), rootCmd.CommandPath()+" "))
+			}
+			if p.QPS < 0 || p.Burst < 0 {
+				return fmt.Errorf("--qps and --burst must not be negative")
+			}
+			if err := p.ValidateAuth(); err != nil {
+				return err
+			}
+			if p.KubeCfgPath != "" || p.Context != "" || p.APIServer != "" || p.ProxyURL != "" || p.QPS > 0 || p.Burst > 0 || p.AuthProvider != "" {
+				if err := p.Reinitialize(); err != nil {
+					return fmt.Errorf("failed to create clients for the API server: %s", err)
+				}
+			} else if pluginErr := (&pkg.CredentialPluginError{}); errors.As(initErr, &pluginErr) {
+				// only reported, the commands which don't talk to the cluster, e.g. report, still work
+				fmt.Printf("failed to create clients for the API server: %s\n", pluginErr)
+			}
+			if auditDir != "" {
+				if err := openAuditLog(p, auditDir); err != nil {
+					return err
+				}
+			}
+			return h.run(hookPre, cmd)
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if p.Audit != nil {
+				p.Audit.Close()
+				p.Audit = nil
+			}
+			return h.run(hookPost, cmd)
+		},
 	}
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file holding the command arguments, - to read it from stdin (default is $KPERF_CONFIG or $HOME/.kperf.yaml)")
+	rootCmd.PersistentFlags().StringVar(&h.Pre, "pre-hook", "", "Shell command to run before the command, the run metadata is passed in KPERF_* environment variables")
+	rootCmd.PersistentFlags().StringVar(&h.Post, "post-hook", "", "Shell command to run after the command succeeded, the run metadata is passed in KPERF_* environment variables")
+	rootCmd.PersistentFlags().StringVar(&p.KubeCfgPath, "kubeconfig", "", "kubectl configuration file (default is $KUBECONFIG or $HOME/.kube/config)")
+	rootCmd.PersistentFlags().StringVar(&p.Context, "context", "", "Name of the kubeconfig context to use (default is the current context of the kubeconfig)")
+	rootCmd.PersistentFlags().StringVar(&p.APIServer, "api-server", "", "Address of the Kubernetes API server, overrides the server of the kubeconfig")
+	rootCmd.PersistentFlags().StringVar(&p.ProxyURL, "proxy-url", "", "Proxy for the Kubernetes API server requests, e.g. http://bastion:3128 or socks5://localhost:1080 (default is $HTTPS_PROXY respecting $NO_PROXY)")
+	rootCmd.PersistentFlags().Float32Var(&p.QPS, "qps", 0, "Maximum queries per second to the Kubernetes API server on the client side (default is the client-go default of 5)")
+	rootCmd.PersistentFlags().IntVar(&p.Burst, "burst", 0, "Maximum burst of queries to the Kubernetes API server on the client side (default is the client-go default of 10)")
+	rootCmd.PersistentFlags().StringVar(&p.AuthProvider, "auth-provider", "", "Authenticate with the token helper of a managed cloud or the service account of the pod kperf runs in instead of the kubeconfig user, one of "+strings.Join(pkg.AuthProviders, ", "))
+	rootCmd.PersistentFlags().StringVar(&p.AuthCluster, "auth-cluster", "", "Name of the EKS cluster the token of --auth-provider eks is requested for")
+	rootCmd.PersistentFlags().StringVar(&p.AuthRegion, "auth-region", "", "AWS region of the EKS cluster of --auth-provider eks (default is the region of the AWS CLI configuration)")
+	rootCmd.PersistentFlags().BoolVar(&p.ReadOnly, "read-only", false, "Refuse commands and API server requests which change the cluster, e.g. to measure production clusters safely")
+	rootCmd.PersistentFlags().StringArrayVar(&meta, "meta", nil, "Metadata key=value recorded in the JSON, CSV and HTML files of the run, e.g. --meta team=serving --meta build=1234, can be repeated")
+	rootCmd.PersistentFlags().StringVar(&p.Output.Name, "output-name", "", "Name the result files start with instead of the timestamp, e.g. nightly or nightly-{timestamp}, {timestamp} is replaced by the timestamp of the run")
+	rootCmd.PersistentFlags().BoolVar(&p.Output.Overwrite, "overwrite", false, "Replace existing result files, they are refused by default so that the results of earlier runs are kept")
+	rootCmd.PersistentFlags().StringVar(&auditDir, "audit-dir", "", "Directory to write the operations log of the run to, which records every resource created, modified or deleted")
 	cobra.OnInitialize(initConfig)
 	rootCmd.AddCommand(service.NewServiceCmd(p))
-	rootCmd.AddCommand(version.NewVersionCommand())
+	rootCmd.AddCommand(eventing.NewEventingCmd(p))
+	rootCmd.AddCommand(domainmapping.NewDomainMappingCmd(p))
+	rootCmd.AddCommand(namespace.NewNamespaceCmd(p))
+	rootCmd.AddCommand(function.NewFunctionCmd(p))
+	rootCmd.AddCommand(autoscaler.NewAutoscalerCmd(p))
+	rootCmd.AddCommand(ingress.NewIngressCmd(p))
+	rootCmd.AddCommand(revision.NewRevisionCmd(p))
+	rootCmd.AddCommand(webhook.NewWebhookCmd(p))
+	rootCmd.AddCommand(clean.NewCleanCmd(p))
+	rootCmd.AddCommand(compare.NewCompareCommand(p))
+	rootCmd.AddCommand(wizard.NewInitCommand(p))
+	rootCmd.AddCommand(calibrate.NewCalibrateCommand(p))
+	rootCmd.AddCommand(featurematrix.NewFeatureMatrixCommand(p))
+	rootCmd.AddCommand(attest.NewAttestCommand(p))
+	rootCmd.AddCommand(report.NewReportCmd(p))
+	rootCmd.AddCommand(exporter.NewExporterCmd(p))
+	rootCmd.AddCommand(version.NewVersionCommand(p))
 	rootCmd.InitDefaultHelpCmd()
 	return rootCmd
 }
 
+// openAuditLog creates the operations log of the run in dir, the API server requests changing the cluster
+// are recorded in it from now on
+func openAuditLog(p *pkg.PerfParams, dir string) error {
+	outputLocation, err := utils.CheckOutputLocation(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check operations log location: %s", err)
+	}
+	path := filepath.Join(outputLocation, utils.OutputFileName(p.Output, time.Now().Format(utils.DateFormatString), "operations.csv"))
+	if err := utils.CheckOverwrite(path, p.Output.Overwrite); err != nil {
+		return fmt.Errorf("failed to create operations log: %s", err)
+	}
+	p.Audit, err = pkg.NewAuditLog(path)
+	if err != nil {
+		return fmt.Errorf("failed to create operations log: %s", err)
+	}
+	fmt.Printf("Operations log saved in CSV file %s\n", path)
+	return nil
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	if cfgFile != "" {
+	if cfgFile == "" {
+		cfgFile = os.Getenv(configEnv("config"))
+	}
+	if cfgFile == "-" {
+		// Read the config file from stdin, e.g. piped scenario files
+		viper.SetConfigType("yaml")
+		cfgErr = nil
+		if err := viper.ReadConfig(configStdin); err != nil {
+			cfgErr = fmt.Errorf("failed to read config file from stdin: %s", err)
+		}
+		return
+	} else if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
 	} else {
@@ -67,7 +208,10 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
+	cfgErr = nil
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	} else if cfgFile != "" {
+		cfgErr = fmt.Errorf("failed to read config file %s: %s", cfgFile, err)
 	}
 }
@@ -17,6 +17,7 @@ package core
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/spf13/viper"
 	"gotest.tools/v3/assert"
 
+	"knative.dev/kperf/pkg/command/utils"
 	"knative.dev/kperf/pkg/testutil"
 )
 
@@ -33,6 +35,22 @@ func TestNewPerfCommand(t *testing.T) {
 			"help",
 			"version",
 			"service",
+			"eventing",
+			"domainmapping",
+			"namespace",
+			"function",
+			"autoscaler",
+			"ingress",
+			"revision",
+			"webhook",
+			"clean",
+			"compare",
+			"init",
+			"calibrate",
+			"feature-matrix",
+			"attest",
+			"report",
+			"exporter",
 		}
 
 		cmd := NewPerfCommand()
@@ -58,6 +76,73 @@ func TestNewPerfCommand(t *testing.T) {
 		assert.NilError(t, err)
 	})
 
+	t.Run("refuse mutating command in read-only mode", func(t *testing.T) {
+		cmd := NewPerfCommand()
+		_, err := testutil.ExecuteCommand(cmd, "--read-only", "service", "generate", "-n", "1", "-b", "1", "-c", "1", "-i", "1", "--namespace", "ns")
+		assert.ErrorContains(t, err, "'service generate' changes the cluster and is refused in read-only mode")
+
+		cmd = NewPerfCommand()
+		_, err = testutil.ExecuteCommand(cmd, "--read-only", "eventing", "clean", "--namespace", "ns")
+		assert.ErrorContains(t, err, "'eventing clean' changes the cluster and is refused in read-only mode")
+
+		cmd = NewPerfCommand()
+		_, err = testutil.ExecuteCommand(cmd, "--read-only", "version")
+		assert.NilError(t, err)
+	})
+
+	t.Run("refuse negative qps and burst", func(t *testing.T) {
+		cmd := NewPerfCommand()
+		_, err := testutil.ExecuteCommand(cmd, "--qps", "-1", "version")
+		assert.ErrorContains(t, err, "--qps and --burst must not be negative")
+	})
+
+	t.Run("record run metadata", func(t *testing.T) {
+		defer utils.SetRunMetadata(nil)
+		cmd := NewPerfCommand()
+		_, err := testutil.ExecuteCommand(cmd, "--meta", "team=serving", "--meta", "build=1234", "version")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]string{"team": "serving", "build": "1234"}, utils.RunMetadata())
+
+		cmd = NewPerfCommand()
+		_, err = testutil.ExecuteCommand(cmd, "--meta", "team", "version")
+		assert.ErrorContains(t, err, "invalid --meta: expected metadata like team=serving")
+	})
+
+	t.Run("write operations log with audit-dir", func(t *testing.T) {
+		dir := t.TempDir()
+		cmd := NewPerfCommand()
+		_, err := testutil.ExecuteCommand(cmd, "--audit-dir", dir, "version")
+		assert.NilError(t, err)
+		matches, err := filepath.Glob(filepath.Join(dir, "*_operations.csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+
+		cmd = NewPerfCommand()
+		_, err = testutil.ExecuteCommand(cmd, "--audit-dir", filepath.Join(dir, "missing"), "version")
+		assert.ErrorContains(t, err, "failed to check operations log location")
+	})
+
+	t.Run("name output files with output-name", func(t *testing.T) {
+		dir := t.TempDir()
+		cmd := NewPerfCommand()
+		_, err := testutil.ExecuteCommand(cmd, "--output-name", "nightly", "--audit-dir", dir, "version")
+		assert.NilError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "nightly_operations.csv"))
+		assert.NilError(t, err)
+
+		cmd = NewPerfCommand()
+		_, err = testutil.ExecuteCommand(cmd, "--output-name", "nightly", "--audit-dir", dir, "version")
+		assert.ErrorContains(t, err, "nightly_operations.csv already exists, replace it with --overwrite")
+
+		cmd = NewPerfCommand()
+		_, err = testutil.ExecuteCommand(cmd, "--output-name", "nightly", "--overwrite", "--audit-dir", dir, "version")
+		assert.NilError(t, err)
+
+		cmd = NewPerfCommand()
+		_, err = testutil.ExecuteCommand(cmd, "--output-name", "runs/nightly", "version")
+		assert.ErrorContains(t, err, "--output-name must be a file name without directories")
+	})
+
 	t.Run("run unknown command", func(t *testing.T) {
 		cmd := NewPerfCommand()
 		_, err := testutil.ExecuteCommand(cmd, "test-command")
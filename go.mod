@@ -4,11 +4,16 @@ go 1.16
 
 require (
 	bou.ke/monkey v1.0.2
+	github.com/google/go-cmp v0.5.7
 	github.com/kevinburke/go-bindata v3.23.0+incompatible
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/montanaflynn/stats v0.6.5
 	github.com/spf13/cobra v1.3.0
+	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.10.1
+	github.com/xitongsys/parquet-go v1.6.2
+	golang.org/x/oauth2 v0.0.0-20211104180415-d3ed0bb246c8
+	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
 	gotest.tools/v3 v3.0.3
 	k8s.io/api v0.22.5
 	k8s.io/apimachinery v0.22.5
@@ -17,4 +22,5 @@ require (
 	knative.dev/networking v0.0.0-20220315020002-1890039ae107
 	knative.dev/pkg v0.0.0-20220315095603-616f1ab878c5
 	knative.dev/serving v0.30.1-0.20220315121703-b5996a729dc5
+	sigs.k8s.io/yaml v1.3.0
 )
@@ -49,6 +49,8 @@ function go_build() {
   echo "🚧 Compile"
   go_pre_build
   go build -mod=mod -ldflags "$(build_flags)" -o kperf ./cmd/kperf/...
+  # kperf runs as kn plugin when invoked as kn-perf
+  ln -sf kperf kn-perf
 }
 
 function go_test() {
@@ -0,0 +1,186 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditLog is the operations log of a run, it records every API server request which creates, modifies or
+// deletes a resource. The entries are written as they happen, so the log is complete even if the run fails.
+type AuditLog struct {
+	lock   sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+}
+
+// AuditEntry is a resource created, modified or deleted by kperf
+type AuditEntry struct {
+	Time         time.Time
+	Operation    string
+	Method       string
+	GroupVersion string
+	Resource     string
+	Namespace    string
+	Name         string
+	StatusCode   int
+	Error        string
+}
+
+// NewAuditLog creates the operations log in the CSV file path
+func NewAuditLog(path string) (*AuditLog, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	l := &AuditLog{file: file, writer: csv.NewWriter(file)}
+	if err := l.write([]string{"time", "operation", "method", "group_version", "resource", "namespace", "name", "status_code", "error"}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Record writes the entry to the operations log
+func (l *AuditLog) Record(e AuditEntry) error {
+	return l.write([]string{e.Time.UTC().Format(time.RFC3339Nano), e.Operation, e.Method, e.GroupVersion, e.Resource,
+		e.Namespace, e.Name, strconv.Itoa(e.StatusCode), e.Error})
+}
+
+// Close closes the file of the operations log
+func (l *AuditLog) Close() error {
+	return l.file.Close()
+}
+
+func (l *AuditLog) write(row []string) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if err := l.writer.Write(row); err != nil {
+		return err
+	}
+	l.writer.Flush()
+	return l.writer.Error()
+}
+
+// auditRecorder records the API server requests which change the cluster in the audit log of the params, dry run
+// requests change nothing and are not recorded. The audit log is checked for every request as the clients are
+// created before the flags are parsed.
+type auditRecorder struct {
+	params *PerfParams
+	next   http.RoundTripper
+}
+
+func (a *auditRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if a.params.Audit == nil || isDryRun(req) {
+		return a.next.RoundTrip(req)
+	}
+	var operation string
+	switch req.Method {
+	case http.MethodPost:
+		operation = "create"
+	case http.MethodPut, http.MethodPatch:
+		operation = "update"
+	case http.MethodDelete:
+		operation = "delete"
+	default:
+		return a.next.RoundTrip(req)
+	}
+
+	entry := parseResourcePath(req.URL.Path)
+	entry.Time = time.Now()
+	entry.Operation = operation
+	entry.Method = req.Method
+	if operation == "delete" && entry.Name == "" {
+		entry.Operation = "deletecollection"
+	}
+	resp, err := a.next.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.StatusCode = resp.StatusCode
+		if operation == "create" && entry.Name == "" {
+			// the name of a created resource is only in the body, and generated if the resource has a generateName
+			entry.Name = createdName(resp)
+		}
+	}
+	if recordErr := a.params.Audit.Record(entry); recordErr != nil {
+		fmt.Printf("failed to write operations log: %s\n", recordErr)
+	}
+	return resp, err
+}
+
+// parseResourcePath returns the entry of the resource of an API path like
+// /apis/serving.knative.dev/v1/namespaces/ns/services/name
+func parseResourcePath(path string) AuditEntry {
+	entry := AuditEntry{}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		entry.GroupVersion = parts[1]
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		entry.GroupVersion = parts[1] + "/" + parts[2]
+		parts = parts[3:]
+	default:
+		entry.Resource = path
+		return entry
+	}
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		entry.Namespace = parts[1]
+		parts = parts[2:]
+	}
+	if len(parts) > 0 {
+		entry.Resource = parts[0]
+	}
+	if len(parts) > 1 {
+		entry.Name = parts[1]
+	}
+	if len(parts) > 2 {
+		entry.Resource += "/" + strings.Join(parts[2:], "/")
+	}
+	return entry
+}
+
+// createdName reads the name of the created resource from the response and restores the body
+func createdName(resp *http.Response) string {
+	if resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return ""
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var object struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return ""
+	}
+	return object.Metadata.Name
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAuditRecorder(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodDelete:
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Success"}`))
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fallthrough
+		default:
+			w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm-x7k2p","namespace":"ns-1"}}`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "config")
+	assert.NilError(t, ioutil.WriteFile(kubeconfig, []byte(fmt.Sprintf(testKubeconfig, server.URL, "token")), 0600))
+	p := &PerfParams{KubeCfgPath: kubeconfig}
+	assert.NilError(t, p.Initialize())
+
+	// the audit log is opened after the clients are created like by the --audit-dir flag
+	path := filepath.Join(dir, "operations.csv")
+	audit, err := NewAuditLog(path)
+	assert.NilError(t, err)
+	p.Audit = audit
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{GenerateName: "cm-"}}
+	created, err := p.ClientSet.CoreV1().ConfigMaps("ns-1").Create(context.TODO(), cm, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "cm-x7k2p", created.Name)
+	_, err = p.ClientSet.CoreV1().ConfigMaps("ns-1").Create(context.TODO(), cm, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	assert.NilError(t, err)
+	_, err = p.ClientSet.CoreV1().ConfigMaps("ns-1").Get(context.TODO(), "cm-x7k2p", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.NilError(t, p.ClientSet.CoreV1().ConfigMaps("ns-1").Delete(context.TODO(), "cm-x7k2p", metav1.DeleteOptions{}))
+	assert.NilError(t, audit.Close())
+
+	file, err := os.Open(path)
+	assert.NilError(t, err)
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	assert.NilError(t, err)
+	assert.Equal(t, 3, len(rows))
+	assert.DeepEqual(t, []string{"time", "operation", "method", "group_version", "resource", "namespace", "name", "status_code", "error"}, rows[0])
+	assert.DeepEqual(t, []string{"create", "POST", "v1", "configmaps", "ns-1", "cm-x7k2p", "201", ""}, rows[1][1:])
+	assert.DeepEqual(t, []string{"delete", "DELETE", "v1", "configmaps", "ns-1", "cm-x7k2p", "200", ""}, rows[2][1:])
+}
+
+func TestParseResourcePath(t *testing.T) {
+	for _, tc := range []struct {
+		path     string
+		expected AuditEntry
+	}{
+		{"/api/v1/namespaces", AuditEntry{GroupVersion: "v1", Resource: "namespaces"}},
+		{"/api/v1/namespaces/ns-1", AuditEntry{GroupVersion: "v1", Resource: "namespaces", Name: "ns-1"}},
+		{"/apis/serving.knative.dev/v1/namespaces/ns-1/services", AuditEntry{GroupVersion: "serving.knative.dev/v1", Resource: "services", Namespace: "ns-1"}},
+		{"/apis/apps/v1/namespaces/ns-1/deployments/d-1/scale", AuditEntry{GroupVersion: "apps/v1", Resource: "deployments/scale", Namespace: "ns-1", Name: "d-1"}},
+		{"/version", AuditEntry{Resource: "/version"}},
+	} {
+		assert.DeepEqual(t, tc.expected, parseResourcePath(tc.path))
+	}
+}
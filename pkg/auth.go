@@ -0,0 +1,155 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	// the auth provider plugins of the kubeconfigs, e.g. oidc, are available to all commands
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+)
+
+const (
+	AuthGKE       = "gke"
+	AuthEKS       = "eks"
+	AuthAKS       = "aks"
+	AuthInCluster = "in-cluster"
+
+	// execAPIVersion is the version of the ExecCredential the token helpers of the clouds return
+	execAPIVersion = "client.authentication.k8s.io/v1beta1"
+	// aksServerID is the application ID of the Azure Kubernetes Service AAD server every AKS cluster trusts
+	aksServerID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+	// azureFederatedTokenEnv is set in the pods using Azure workload identity
+	azureFederatedTokenEnv = "AZURE_FEDERATED_TOKEN_FILE"
+)
+
+// AuthProviders are the values of --auth-provider
+var AuthProviders = []string{AuthGKE, AuthEKS, AuthAKS, AuthInCluster}
+
+// lookPath finds the credential plugins, it is replaced in tests
+var lookPath = exec.LookPath
+
+// inClusterConfig returns the config of the service account of the pod kperf runs in, it is replaced in tests
+var inClusterConfig = rest.InClusterConfig
+
+// ValidateAuth checks the auth provider and that the flags it requires are given
+func (params *PerfParams) ValidateAuth() error {
+	switch params.AuthProvider {
+	case "", AuthGKE, AuthAKS, AuthInCluster:
+	case AuthEKS:
+		if params.AuthCluster == "" {
+			return fmt.Errorf("--auth-provider eks requires --auth-cluster with the name of the EKS cluster")
+		}
+	default:
+		return fmt.Errorf("unknown --auth-provider %s, valid are %s", params.AuthProvider, strings.Join(AuthProviders, ", "))
+	}
+	if params.AuthProvider != AuthEKS && params.AuthRegion != "" {
+		return fmt.Errorf("--auth-region can only be given with --auth-provider eks")
+	}
+	return nil
+}
+
+// authExec returns the token helper of the managed cloud. The helpers pick up the workload identity of the pod when
+// kperf runs in-cluster, e.g. the GKE metadata server, the IRSA web identity token of EKS or the federated token of AKS.
+func (params *PerfParams) authExec() *clientcmdapi.ExecConfig {
+	config := &clientcmdapi.ExecConfig{APIVersion: execAPIVersion, InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode}
+	switch params.AuthProvider {
+	case AuthGKE:
+		config.Command = "gke-gcloud-auth-plugin"
+		config.InstallHint = "install it with 'gcloud components install gke-gcloud-auth-plugin', see https://cloud.google.com/kubernetes-engine/docs/how-to/cluster-access-for-kubectl"
+	case AuthEKS:
+		config.Command = "aws"
+		config.Args = []string{"eks", "get-token", "--cluster-name", params.AuthCluster}
+		if params.AuthRegion != "" {
+			config.Args = append(config.Args, "--region", params.AuthRegion)
+		}
+		config.InstallHint = "install the AWS CLI, see https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html"
+	case AuthAKS:
+		login := "azurecli"
+		if os.Getenv(azureFederatedTokenEnv) != "" {
+			login = "workloadidentity"
+		}
+		config.Command = "kubelogin"
+		config.Args = []string{"get-token", "--login", login, "--server-id", aksServerID}
+		config.InstallHint = "install it with 'az aks install-cli', see https://azure.github.io/kubelogin"
+	}
+	return config
+}
+
+// authConfig returns the config with the credentials of the auth provider. in-cluster uses the service account of
+// the pod kperf runs in, the cloud providers replace the credentials of the kubeconfig user by their token helper.
+func (params *PerfParams) authConfig(config *rest.Config) (*rest.Config, error) {
+	switch params.AuthProvider {
+	case "":
+	case AuthInCluster:
+		c, err := inClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("--auth-provider in-cluster requires kperf to run in a pod with a service account: %s", err)
+		}
+		if params.APIServer != "" {
+			c.Host = params.APIServer
+		}
+		return c, nil
+	default:
+		config = rest.CopyConfig(config)
+		config.BearerToken = ""
+		config.BearerTokenFile = ""
+		config.Username = ""
+		config.Password = ""
+		config.AuthProvider = nil
+		config.CertFile = ""
+		config.KeyFile = ""
+		config.CertData = nil
+		config.KeyData = nil
+		config.ExecProvider = params.authExec()
+	}
+	if err := checkCredentialPlugin(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// checkCredentialPlugin fails early with the install hint when the exec credential plugin of the config is missing,
+// instead of failing with the first API server request
+func checkCredentialPlugin(config *rest.Config) error {
+	if config.ExecProvider == nil {
+		return nil
+	}
+	if _, err := lookPath(config.ExecProvider.Command); err != nil {
+		hint := config.ExecProvider.InstallHint
+		if hint == "" {
+			hint = "install it or fix the exec section of the kubeconfig user"
+		}
+		return &CredentialPluginError{Command: config.ExecProvider.Command, Hint: hint}
+	}
+	return nil
+}
+
+// CredentialPluginError is returned when the exec credential plugin of the kubeconfig user or of the auth provider
+// is not installed
+type CredentialPluginError struct {
+	Command string
+	Hint    string
+}
+
+func (e *CredentialPluginError) Error() string {
+	return fmt.Sprintf("credential plugin %s is not installed: %s", e.Command, e.Hint)
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/rest"
+)
+
+const testExecKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://api.cluster:6443
+users:
+- name: test
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: missing-auth-plugin
+      installHint: install missing-auth-plugin
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`
+
+func TestValidateAuth(t *testing.T) {
+	assert.NilError(t, (&PerfParams{}).ValidateAuth())
+	assert.NilError(t, (&PerfParams{AuthProvider: AuthGKE}).ValidateAuth())
+	assert.NilError(t, (&PerfParams{AuthProvider: AuthEKS, AuthCluster: "demo", AuthRegion: "eu-west-1"}).ValidateAuth())
+	assert.ErrorContains(t, (&PerfParams{AuthProvider: AuthEKS}).ValidateAuth(), "requires --auth-cluster")
+	assert.ErrorContains(t, (&PerfParams{AuthProvider: AuthAKS, AuthRegion: "eu-west-1"}).ValidateAuth(), "--auth-region")
+	assert.ErrorContains(t, (&PerfParams{AuthProvider: "openshift"}).ValidateAuth(), "unknown --auth-provider openshift")
+}
+
+func TestAuthConfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NilError(t, ioutil.WriteFile(kubeconfig, []byte(fmt.Sprintf(testKubeconfig, "https://api.cluster:6443", "token")), 0600))
+	origLookPath := lookPath
+	defer func() { lookPath = origLookPath }()
+	installed := map[string]bool{"gke-gcloud-auth-plugin": true, "aws": true, "kubelogin": true}
+	lookPath = func(file string) (string, error) {
+		if !installed[file] {
+			return "", fmt.Errorf("executable file not found in $PATH")
+		}
+		return "/usr/bin/" + file, nil
+	}
+
+	t.Run("eks token helper replaces the kubeconfig user", func(t *testing.T) {
+		p := &PerfParams{KubeCfgPath: kubeconfig, AuthProvider: AuthEKS, AuthCluster: "demo", AuthRegion: "eu-west-1"}
+		config, err := p.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, "https://api.cluster:6443", config.Host)
+		assert.Equal(t, "", config.BearerToken)
+		assert.Equal(t, "aws", config.ExecProvider.Command)
+		assert.DeepEqual(t, []string{"eks", "get-token", "--cluster-name", "demo", "--region", "eu-west-1"}, config.ExecProvider.Args)
+	})
+
+	t.Run("aks workload identity", func(t *testing.T) {
+		t.Setenv(azureFederatedTokenEnv, "/var/run/secrets/azure/tokens/azure-identity-token")
+		p := &PerfParams{KubeCfgPath: kubeconfig, AuthProvider: AuthAKS}
+		config, err := p.RestConfig()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"get-token", "--login", "workloadidentity", "--server-id", aksServerID}, config.ExecProvider.Args)
+	})
+
+	t.Run("missing token helper", func(t *testing.T) {
+		delete(installed, "gke-gcloud-auth-plugin")
+		defer func() { installed["gke-gcloud-auth-plugin"] = true }()
+		p := &PerfParams{KubeCfgPath: kubeconfig, AuthProvider: AuthGKE}
+		_, err := p.RestConfig()
+		assert.ErrorContains(t, err, "credential plugin gke-gcloud-auth-plugin is not installed: install it with 'gcloud components install gke-gcloud-auth-plugin'")
+	})
+
+	t.Run("missing plugin of the kubeconfig user", func(t *testing.T) {
+		execKubeconfig := filepath.Join(t.TempDir(), "config")
+		assert.NilError(t, ioutil.WriteFile(execKubeconfig, []byte(testExecKubeconfig), 0600))
+		p := &PerfParams{KubeCfgPath: execKubeconfig}
+		err := p.Initialize()
+		pluginErr := &CredentialPluginError{}
+		assert.Assert(t, errors.As(err, &pluginErr), err)
+		assert.Equal(t, "missing-auth-plugin", pluginErr.Command)
+		assert.Equal(t, "install missing-auth-plugin", pluginErr.Hint)
+	})
+
+	t.Run("in-cluster", func(t *testing.T) {
+		origInClusterConfig := inClusterConfig
+		defer func() { inClusterConfig = origInClusterConfig }()
+		inClusterConfig = func() (*rest.Config, error) {
+			return &rest.Config{Host: "https://10.96.0.1:443", BearerToken: "service-account-token"}, nil
+		}
+		// the kubeconfig isn't loaded
+		p := &PerfParams{KubeCfgPath: filepath.Join(t.TempDir(), "missing"), AuthProvider: AuthInCluster}
+		assert.NilError(t, p.Initialize())
+		config, err := p.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, "https://10.96.0.1:443", config.Host)
+
+		inClusterConfig = func() (*rest.Config, error) {
+			return nil, rest.ErrNotInCluster
+		}
+		p = &PerfParams{AuthProvider: AuthInCluster}
+		_, err = p.RestConfig()
+		assert.ErrorContains(t, err, "requires kperf to run in a pod")
+	})
+}
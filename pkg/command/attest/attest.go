@@ -0,0 +1,245 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/command/version"
+)
+
+const (
+	StatementType = "https://in-toto.io/Statement/v0.1"
+	PredicateType = "https://slsa.dev/provenance/v0.2"
+	BuildType     = "https://knative.dev/kperf/benchmark@v1"
+
+	DefaultBuilderID = "https://github.com/knative-sandbox/kperf"
+
+	OutputFilename = "attestation.intoto"
+)
+
+// NewAttestCommand implements 'kperf attest' command
+func NewAttestCommand(p *pkg.PerfParams) *cobra.Command {
+	attestArgs := pkg.AttestArgs{}
+	attestCommand := &cobra.Command{
+		Use:   "attest",
+		Short: "Attest the results of a benchmark run",
+		Long: `Attest the results of a benchmark run for release qualification
+
+The attestation is an in-toto statement whose subject is the JSON result of the run, identified by its digest. Its
+SLSA provenance predicate records the inputs of the run, a snapshot of the environment with the kperf, Go, Kubernetes
+and Knative versions, and the digests of the other files of the run, so that the results can be verified
+independently. The attestation is not signed, sign it with the tooling of the release process.
+
+For example:
+# To attest the measurement of a run together with its raw timestamps and the config it was generated with
+kperf attest --run /tmp/20210117104747_ksvc_creation_time.json --material /tmp/20210117104747_raw_ksvc_creation_time.csv --material kperf.yaml --parameter range=0,499 --parameter concurrency=20
+
+# To verify that the files of a run match their attestation
+kperf attest --verify /tmp/20210117104900_attestation.intoto.json
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if attestArgs.Run == "" && attestArgs.Verify == "" {
+				return errors.New("'kperf attest' requires --run or --verify")
+			}
+			if attestArgs.Run != "" && attestArgs.Verify != "" {
+				return errors.New("--run and --verify can't be combined")
+			}
+			_, err := parseParameters(attestArgs.Parameters)
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if attestArgs.Verify != "" {
+				return VerifyAttestation(attestArgs.Verify, cmd.OutOrStdout())
+			}
+			return AttestRun(p, attestArgs, cmd.OutOrStdout())
+		},
+	}
+
+	attestCommand.Flags().StringVarP(&attestArgs.Run, "run", "", "", "JSON result of the run to attest, e.g. the ksvc_creation_time.json file of 'kperf service measure'")
+	attestCommand.Flags().StringArrayVarP(&attestArgs.Materials, "material", "", nil, "Other file of the run to attest by its digest, e.g. the raw timestamps or the config file. Can be given several times")
+	attestCommand.Flags().StringArrayVarP(&attestArgs.Parameters, "parameter", "", nil, "Input of the run like range=0,499, recorded as parameter of the invocation. Can be given several times")
+	attestCommand.Flags().StringVarP(&attestArgs.BuilderID, "builder-id", "", DefaultBuilderID, "ID of the builder which ran the benchmark, e.g. the URL of the CI job")
+	attestCommand.Flags().StringVarP(&attestArgs.Verify, "verify", "", "", "Attestation to verify the digests of the files of the run against, the files are read from the paths they were attested with")
+	attestCommand.Flags().StringVarP(&attestArgs.Output, "output", "o", ".", "Attestation location")
+	return attestCommand
+}
+
+// AttestRun writes the attestation of the results of a run
+func AttestRun(params *pkg.PerfParams, inputs pkg.AttestArgs, out io.Writer) error {
+	parameters, err := parseParameters(inputs.Parameters)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(inputs.Run)
+	if err != nil {
+		return fmt.Errorf("failed to read run %s", err)
+	}
+	// the results of every kperf command hold the Knative versions and the ID of the run, if any
+	var run struct {
+		KnativeInfo pkg.KnativeInfo
+		RunID       string
+	}
+	if err := json.Unmarshal(data, &run); err != nil {
+		return fmt.Errorf("failed to parse run %s, expected the JSON result of a kperf command: %s", inputs.Run, err)
+	}
+	info, err := os.Stat(inputs.Run)
+	if err != nil {
+		return fmt.Errorf("failed to read run %s", err)
+	}
+
+	materials := make([]pkg.AttestationSubject, 0, len(inputs.Materials))
+	for _, path := range inputs.Materials {
+		material, err := digest(path)
+		if err != nil {
+			return err
+		}
+		materials = append(materials, material)
+	}
+	attestation := pkg.Attestation{
+		Type:          StatementType,
+		Subject:       []pkg.AttestationSubject{{Name: inputs.Run, Digest: sha256Digest(data)}},
+		PredicateType: PredicateType,
+		Predicate: pkg.BenchmarkProvenance{
+			Builder:   pkg.ProvenanceBuilder{ID: inputs.BuilderID},
+			BuildType: BuildType,
+			Invocation: pkg.ProvenanceInvocation{
+				Parameters:  parameters,
+				Environment: environment(params, run.KnativeInfo, out),
+			},
+			Metadata: pkg.ProvenanceMetadata{
+				BuildInvocationID: run.RunID,
+				BuildFinishedOn:   info.ModTime().UTC().Format(time.RFC3339),
+			},
+			Materials: materials,
+		},
+	}
+
+	outputLocation, err := utils.CheckOutputLocation(inputs.Output)
+	if err != nil {
+		fmt.Fprintf(out, "failed to check attestation output location: %s\n", err)
+	}
+	jsonPath := filepath.Join(outputLocation, utils.OutputFileName(params.Output, time.Now().Format(utils.DateFormatString), OutputFilename+".json"))
+	jsonData, err := json.MarshalIndent(attestation, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to generate attestation %s", err)
+	}
+	if err := utils.GenerateJSONFile(jsonData, jsonPath, params.Output.Overwrite); err != nil {
+		return fmt.Errorf("failed to generate attestation file %s", err)
+	}
+	fmt.Fprintf(out, "Attestation of run %s (sha256:%s) saved in JSON file %s\n", inputs.Run, attestation.Subject[0].Digest["sha256"], jsonPath)
+	return nil
+}
+
+// VerifyAttestation checks that the subject and the materials of an attestation have the attested digests
+func VerifyAttestation(path string, out io.Writer) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read attestation %s", err)
+	}
+	attestation := pkg.Attestation{}
+	if err := json.Unmarshal(data, &attestation); err != nil {
+		return fmt.Errorf("failed to parse attestation %s", err)
+	}
+	if attestation.Type != StatementType || attestation.PredicateType != PredicateType || len(attestation.Subject) == 0 {
+		return fmt.Errorf("%s is not an attestation of a kperf run, expected an in-toto statement with a %s predicate", path, PredicateType)
+	}
+
+	failed := 0
+	for _, attested := range append(attestation.Subject, attestation.Predicate.Materials...) {
+		actual, err := digest(attested.Name)
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "%s: %s\n", attested.Name, err)
+			failed++
+		case actual.Digest["sha256"] != attested.Digest["sha256"]:
+			fmt.Fprintf(out, "%s: digest sha256:%s doesn't match the attested sha256:%s\n", attested.Name, actual.Digest["sha256"], attested.Digest["sha256"])
+			failed++
+		default:
+			fmt.Fprintf(out, "%s: OK\n", attested.Name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files don't match the attestation %s", failed, len(attestation.Subject)+len(attestation.Predicate.Materials), path)
+	}
+	return nil
+}
+
+// environment takes the snapshot of the environment of the run. The Kubernetes version is read from the cluster if
+// it can be reached, the Knative versions are the ones recorded in the run.
+func environment(params *pkg.PerfParams, knative pkg.KnativeInfo, out io.Writer) pkg.BenchmarkEnvironment {
+	env := pkg.BenchmarkEnvironment{
+		KperfVersion:     version.Version,
+		KperfGitRevision: version.GitRevision,
+		GoVersion:        runtime.Version(),
+		Platform:         runtime.GOOS + "/" + runtime.GOARCH,
+		Knative:          knative,
+	}
+	if params.ClientSet == nil {
+		return env
+	}
+	serverVersion, err := params.ClientSet.Discovery().ServerVersion()
+	if err != nil {
+		fmt.Fprintf(out, "failed to get Kubernetes version and skip it: %s\n", err)
+		return env
+	}
+	env.KubernetesVersion = serverVersion.GitVersion
+	return env
+}
+
+// digest returns the file attested by its sha256 digest
+func digest(path string) (pkg.AttestationSubject, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return pkg.AttestationSubject{}, fmt.Errorf("failed to read %s", err)
+	}
+	return pkg.AttestationSubject{Name: path, Digest: sha256Digest(data)}, nil
+}
+
+func sha256Digest(data []byte) map[string]string {
+	sum := sha256.Sum256(data)
+	return map[string]string{"sha256": hex.EncodeToString(sum[:])}
+}
+
+// parseParameters parses the inputs of a run like range=0,499
+func parseParameters(parameters []string) (map[string]string, error) {
+	if len(parameters) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(parameters))
+	for _, parameter := range parameters {
+		kv := strings.SplitN(parameter, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("expected parameter like range=0,499, given %s", parameter)
+		}
+		parsed[kv[0]] = kv[1]
+	}
+	return parsed, nil
+}
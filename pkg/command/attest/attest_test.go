@@ -0,0 +1,137 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+const runJSON = `{"Result":{"Percentile95":5},"KnativeInfo":{"ServingVersion":"1.3.0","EventingVersion":"1.3.0","IngressController":"Istio","IngressVersion":"1.12.0"},"RunID":"demo"}`
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// readAttestation reads the only attestation written to dir
+func readAttestation(t *testing.T, dir string) (string, pkg.Attestation) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*_attestation.intoto.json"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(matches))
+	data, err := ioutil.ReadFile(matches[0])
+	assert.NilError(t, err)
+	attestation := pkg.Attestation{}
+	assert.NilError(t, json.Unmarshal(data, &attestation))
+	return matches[0], attestation
+}
+
+func TestNewAttestCommand(t *testing.T) {
+	dir := t.TempDir()
+	run := writeTestFile(t, dir, "ksvc_creation_time.json", runJSON)
+	raw := writeTestFile(t, dir, "raw_ksvc_creation_time.csv", "svc_name,svc_namespace\nksvc-0,ns\n")
+	client := k8sfake.NewSimpleClientset()
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &k8sversion.Info{GitVersion: "v1.22.5"}
+	p := &pkg.PerfParams{ClientSet: client}
+
+	t.Run("incompleted or wrong args for attest", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewAttestCommand(p))
+		assert.ErrorContains(t, err, "'kperf attest' requires --run or --verify")
+
+		_, err = testutil.ExecuteCommand(NewAttestCommand(p), "--run", run, "--verify", run)
+		assert.ErrorContains(t, err, "--run and --verify can't be combined")
+
+		_, err = testutil.ExecuteCommand(NewAttestCommand(p), "--run", run, "--parameter", "concurrency")
+		assert.ErrorContains(t, err, "expected parameter like range=0,499, given concurrency")
+
+		_, err = testutil.ExecuteCommand(NewAttestCommand(p), "--run", raw, "--output", t.TempDir())
+		assert.ErrorContains(t, err, "failed to parse run "+raw+", expected the JSON result of a kperf command")
+
+		_, err = testutil.ExecuteCommand(NewAttestCommand(p), "--run", run, "--material", filepath.Join(dir, "missing.csv"), "--output", t.TempDir())
+		assert.ErrorContains(t, err, "failed to read")
+	})
+
+	t.Run("attest and verify a run", func(t *testing.T) {
+		output := t.TempDir()
+		out, err := testutil.ExecuteCommand(NewAttestCommand(p), "--run", run, "--material", raw, "--parameter", "range=0,499",
+			"--parameter", "concurrency=20", "--builder-id", "https://prow.knative.dev/job/1", "--output", output)
+		assert.NilError(t, err)
+
+		path, attestation := readAttestation(t, output)
+		assert.Assert(t, strings.Contains(out, "Attestation of run "+run+" (sha256:"), out)
+		assert.Equal(t, StatementType, attestation.Type)
+		assert.Equal(t, PredicateType, attestation.PredicateType)
+		assert.DeepEqual(t, []pkg.AttestationSubject{{Name: run, Digest: map[string]string{
+			"sha256": "cf33c77cfe39008ca86c92717246e426c691e1ef67c32306003b5d8dd84426ae",
+		}}}, attestation.Subject)
+		predicate := attestation.Predicate
+		assert.Equal(t, "https://prow.knative.dev/job/1", predicate.Builder.ID)
+		assert.Equal(t, BuildType, predicate.BuildType)
+		assert.DeepEqual(t, map[string]string{"range": "0,499", "concurrency": "20"}, predicate.Invocation.Parameters)
+		env := predicate.Invocation.Environment
+		assert.Equal(t, runtime.Version(), env.GoVersion)
+		assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, env.Platform)
+		assert.Equal(t, "v1.22.5", env.KubernetesVersion)
+		assert.DeepEqual(t, pkg.KnativeInfo{ServingVersion: "1.3.0", EventingVersion: "1.3.0", IngressController: "Istio", IngressVersion: "1.12.0"}, env.Knative)
+		assert.Equal(t, "demo", predicate.Metadata.BuildInvocationID)
+		assert.Equal(t, 1, len(predicate.Materials))
+		assert.Equal(t, raw, predicate.Materials[0].Name)
+
+		out, err = testutil.ExecuteCommand(NewAttestCommand(p), "--verify", path)
+		assert.NilError(t, err)
+		assert.Equal(t, run+": OK\n"+raw+": OK\n", out)
+
+		// a changed material fails the verification
+		assert.NilError(t, ioutil.WriteFile(raw, []byte("svc_name,svc_namespace\nksvc-0,other\n"), 0644))
+		defer ioutil.WriteFile(raw, []byte("svc_name,svc_namespace\nksvc-0,ns\n"), 0644)
+		out, err = testutil.ExecuteCommand(NewAttestCommand(p), "--verify", path)
+		assert.ErrorContains(t, err, "1 of 2 files don't match the attestation "+path)
+		assert.Assert(t, strings.Contains(out, run+": OK\n"+raw+": digest sha256:"), out)
+
+		// removed files fail the verification as well
+		assert.NilError(t, os.Remove(run))
+		defer writeTestFile(t, dir, "ksvc_creation_time.json", runJSON)
+		_, err = testutil.ExecuteCommand(NewAttestCommand(p), "--verify", path)
+		assert.ErrorContains(t, err, "2 of 2 files don't match the attestation "+path)
+	})
+
+	t.Run("attest without a cluster", func(t *testing.T) {
+		output := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewAttestCommand(&pkg.PerfParams{}), "--run", run, "--output", output)
+		assert.NilError(t, err)
+		_, attestation := readAttestation(t, output)
+		assert.Equal(t, "", attestation.Predicate.Invocation.Environment.KubernetesVersion)
+		assert.Assert(t, attestation.Predicate.Invocation.Parameters == nil)
+	})
+
+	t.Run("verify a file which is no attestation", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewAttestCommand(p), "--verify", run)
+		assert.ErrorContains(t, err, "is not an attestation of a kperf run")
+	})
+}
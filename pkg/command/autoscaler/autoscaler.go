@@ -0,0 +1,34 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewAutoscalerCmd(p *pkg.PerfParams) *cobra.Command {
+	var autoscalerCmd = &cobra.Command{
+		Use:   "autoscaler",
+		Short: "Knative autoscaler benchmark",
+		Long: `Knative Serving autoscaler benchmark. For example:
+
+kperf autoscaler benchmark --namespace ns --svc ksvc --shape step --max-qps 200 - to measure how the replicas of the Knative Service follow a load step`,
+	}
+	autoscalerCmd.AddCommand(NewAutoscalerBenchmarkCommand(p))
+
+	autoscalerCmd.InitDefaultHelpCmd()
+	return autoscalerCmd
+}
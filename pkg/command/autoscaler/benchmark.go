@@ -0,0 +1,438 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	BenchmarkOutputFilename = "autoscaler_benchmark"
+
+	ShapeStep  = "step"
+	ShapeRamp  = "ramp"
+	ShapeSpike = "spike"
+	ShapeSine  = "sine"
+
+	// defaultTarget is the target concurrency per replica of the autoscaler without annotation and container
+	// concurrency
+	defaultTarget = 100
+
+	// idlePollInterval is the interval to check the rate of the load shape again while it is 0
+	idlePollInterval = 100 * time.Millisecond
+)
+
+// Shapes are the load shapes of the benchmark
+var Shapes = []string{ShapeStep, ShapeRamp, ShapeSpike, ShapeSine}
+
+func NewAutoscalerBenchmarkCommand(p *pkg.PerfParams) *cobra.Command {
+	benchmarkArgs := pkg.AutoscalerBenchmarkArgs{}
+	benchmarkCommand := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Drive a load shape against a Knative Service and measure how its replicas follow",
+		Long: `Drive a load shape against a Knative Service and measure how accurately the autoscaler provisions its replicas
+
+The rate of the requests follows the shape between --min-qps and --max-qps:
+  step:  --max-qps in the middle third of the duration, --min-qps before and after
+  ramp:  rising linearly from --min-qps to --max-qps over the duration
+  spike: --max-qps for a tenth of the duration after 40% of it, --min-qps otherwise
+  sine:  oscillating between --min-qps and --max-qps with the period given by --period
+
+The replicas are sampled every --interval. The ideal replicas are the ones needed to serve the average requests in flight
+since the previous sample at the target concurrency of the service, the desired replicas the ones the autoscaler asked
+for and the actual replicas the ready ones. The over- and under-provisioning are the areas between the actual and the
+ideal replicas, the time to scale the time the actual replicas took to catch up with the ideal ones, and the pod churn
+the number of pods started and terminated during the load.
+
+For example:
+# To measure how the replicas of Knative Service ksvc in namespace ns follow a step from 10 to 200 requests per second
+kperf autoscaler benchmark --namespace ns --svc ksvc --shape step --min-qps 10 --max-qps 200 --duration 3m --connections 50
+
+# To measure them under a sine load with a period of 2 minutes
+kperf autoscaler benchmark --namespace ns --svc ksvc --shape sine --max-qps 200 --period 2m --duration 6m --connections 50
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'autoscaler benchmark' requires flag(s)")
+			}
+			if benchmarkArgs.Namespace == "" || benchmarkArgs.Service == "" {
+				return fmt.Errorf("--namespace and --svc are required to select the service")
+			}
+			if !validShape(benchmarkArgs.Shape) {
+				return fmt.Errorf("expected --shape to be one of %s, given %s", strings.Join(Shapes, ","), benchmarkArgs.Shape)
+			}
+			if benchmarkArgs.MinQPS < 0 || benchmarkArgs.MaxQPS < 1 || benchmarkArgs.MinQPS > benchmarkArgs.MaxQPS {
+				return fmt.Errorf("expected 0 <= --min-qps <= --max-qps and --max-qps >= 1, given %d and %d", benchmarkArgs.MinQPS, benchmarkArgs.MaxQPS)
+			}
+			if benchmarkArgs.Duration <= 0 || benchmarkArgs.Interval <= 0 || benchmarkArgs.Period <= 0 {
+				return fmt.Errorf("--duration, --interval and --period must be greater than 0")
+			}
+			if benchmarkArgs.Connections < 1 {
+				return fmt.Errorf("connections must be at least 1")
+			}
+			return pkg.ValidateRunID(benchmarkArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return BenchmarkAutoscaler(p, benchmarkArgs)
+		},
+	}
+
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.Namespace, "namespace", "", "", "Service namespace")
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.Service, "svc", "", "", "Name of the Knative Service to drive the load against")
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.Shape, "shape", "", ShapeStep, "Shape of the load, one of "+strings.Join(Shapes, ","))
+	benchmarkCommand.Flags().IntVarP(&benchmarkArgs.MinQPS, "min-qps", "", 0, "Lowest requests per second of the load shape")
+	benchmarkCommand.Flags().IntVarP(&benchmarkArgs.MaxQPS, "max-qps", "", 100, "Highest requests per second of the load shape")
+	benchmarkCommand.Flags().DurationVarP(&benchmarkArgs.Period, "period", "", time.Minute, "Period of the sine load shape")
+	benchmarkCommand.Flags().DurationVarP(&benchmarkArgs.Duration, "duration", "", 3*time.Minute, "Duration of the load")
+	benchmarkCommand.Flags().DurationVarP(&benchmarkArgs.Interval, "interval", "", time.Second, "Interval to sample the replicas of the service")
+	benchmarkCommand.Flags().IntVarP(&benchmarkArgs.Connections, "connections", "", 10, "Number of concurrent connections to the service, bounding the requests in flight")
+	benchmarkCommand.Flags().DurationVarP(&benchmarkArgs.RequestTimeout, "timeout", "", 30*time.Second, "Timeout of a single request")
+	benchmarkCommand.Flags().BoolVarP(&benchmarkArgs.ResolvableDomain, "resolvable", "", false, "If Service endpoint resolvable url")
+	benchmarkCommand.Flags().BoolVarP(&benchmarkArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(benchmarkCommand.Flags(), &benchmarkArgs.OutputFormats)
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	return benchmarkCommand
+}
+
+func validShape(shape string) bool {
+	for _, s := range Shapes {
+		if s == shape {
+			return true
+		}
+	}
+	return false
+}
+
+// shapeQPS returns the requests per second of the load shape at the offset since the start of the load
+func shapeQPS(shape string, offset, duration, period time.Duration, minQPS, maxQPS float64) float64 {
+	progress := float64(offset) / float64(duration)
+	switch shape {
+	case ShapeStep:
+		if progress >= 1.0/3 && progress < 2.0/3 {
+			return maxQPS
+		}
+	case ShapeRamp:
+		return minQPS + (maxQPS-minQPS)*math.Min(progress, 1)
+	case ShapeSpike:
+		if progress >= 0.4 && progress < 0.5 {
+			return maxQPS
+		}
+	case ShapeSine:
+		return minQPS + (maxQPS-minQPS)*(1-math.Cos(2*math.Pi*float64(offset)/float64(period)))/2
+	}
+	return minQPS
+}
+
+// serviceTarget returns the target concurrency per replica of the autoscaler for the service: the target annotation,
+// else the container concurrency, else the default of the autoscaler
+func serviceTarget(svc *servingv1.Service) (float64, error) {
+	if key, value, ok := autoscaling.TargetAnnotation.Get(svc.Spec.Template.Annotations); ok {
+		target, err := strconv.ParseFloat(value, 64)
+		if err != nil || target <= 0 {
+			return 0, fmt.Errorf("invalid %s %q", key, value)
+		}
+		return target, nil
+	}
+	if cc := svc.Spec.Template.Spec.ContainerConcurrency; cc != nil && *cc > 0 {
+		return float64(*cc), nil
+	}
+	return defaultTarget, nil
+}
+
+// BenchmarkAutoscaler drives the load shape against the Knative Service and samples how its replicas follow
+func BenchmarkAutoscaler(params *pkg.PerfParams, inputs pkg.AutoscalerBenchmarkArgs) error {
+	ctx := context.Background()
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	svc, err := ksvcClient.Services(inputs.Namespace).Get(ctx, inputs.Service, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get Knative Service %s in namespace %s: %s", inputs.Service, inputs.Namespace, err)
+	}
+	target, err := serviceTarget(svc)
+	if err != nil {
+		return err
+	}
+	endpoint, err := service.ResolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
+	if err != nil {
+		return fmt.Errorf("failed to get the cluster endpoint: %w", err)
+	}
+
+	fmt.Printf("Driving %s load of %d to %d requests per second for %s against Knative Service %s in namespace %s\n",
+		inputs.Shape, inputs.MinQPS, inputs.MaxQPS, inputs.Duration, svc.Name, svc.Namespace)
+	result, err := runBenchmark(ctx, params, inputs, svc, endpoint, target)
+	if err != nil {
+		return err
+	}
+	result.KnativeInfo = measure.GetKnativeInfo(ctx, params, measure.DefaultLogger)
+
+	rows := [][]string{{"offset", "qps", "concurrency", "ideal", "desired", "actual"}}
+	for _, s := range result.Samples {
+		rows = append(rows, []string{fmt.Sprintf("%f", s.Offset), fmt.Sprintf("%f", s.QPS), fmt.Sprintf("%f", s.Concurrency),
+			strconv.Itoa(s.Ideal), strconv.Itoa(s.Desired), strconv.Itoa(s.Actual)})
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] %fs: QPS %f Concurrency %f Ideal %d Desired %d Actual %d\n", s.Offset, s.QPS, s.Concurrency, s.Ideal, s.Desired, s.Actual)
+		}
+	}
+	writeBenchmark(os.Stdout, result)
+
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	return report.Write(os.Stdout, outputLocation, time.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    BenchmarkOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// runBenchmark sends the requests at the rate of the load shape, samples the replicas of the deployment of the service
+// every interval and counts the pods started and terminated meanwhile
+func runBenchmark(ctx context.Context, params *pkg.PerfParams, inputs pkg.AutoscalerBenchmarkArgs, svc *servingv1.Service, endpoint string, target float64) (pkg.AutoscalerBenchmarkResult, error) {
+	result := pkg.AutoscalerBenchmarkResult{ServiceName: svc.Name, ServiceNamespace: svc.Namespace, Shape: inputs.Shape, Target: target}
+	selector := labels.SelectorFromSet(labels.Set{serving.ServiceLabelKey: svc.Name}).String()
+	pods, err := params.ClientSet.CoreV1().Pods(svc.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return result, fmt.Errorf("failed to list pods: %w", err)
+	}
+	podWatcher, err := params.ClientSet.CoreV1().Pods(svc.Namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:   selector,
+		ResourceVersion: pods.ResourceVersion,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to watch pods: %w", err)
+	}
+	defer podWatcher.Stop()
+
+	var m sync.Mutex
+	podWatchDone := make(chan struct{})
+	go func() {
+		defer close(podWatchDone)
+		for event := range podWatcher.ResultChan() {
+			if _, ok := event.Object.(*corev1.Pod); !ok {
+				continue
+			}
+			m.Lock()
+			switch event.Type {
+			case watch.Added:
+				result.PodsStarted++
+			case watch.Deleted:
+				result.PodsTerminated++
+			}
+			m.Unlock()
+		}
+	}()
+
+	start := time.Now()
+	loadCtx, cancel := context.WithTimeout(ctx, inputs.Duration)
+	defer cancel()
+
+	// the requests are paced by the rate of the shape, a request is dropped if all connections are busy
+	tokens := make(chan struct{}, inputs.Connections)
+	go func() {
+		defer close(tokens)
+		for {
+			qps := shapeQPS(inputs.Shape, time.Since(start), inputs.Duration, inputs.Period, float64(inputs.MinQPS), float64(inputs.MaxQPS))
+			wait := idlePollInterval
+			if qps > 0 {
+				wait = time.Duration(float64(time.Second) / qps)
+			}
+			select {
+			case <-loadCtx.Done():
+				return
+			case <-time.After(wait):
+			}
+			if qps > 0 {
+				select {
+				case tokens <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	// busy is the sum of the durations of the requests finished since the previous sample in seconds, divided by the
+	// interval it is the average number of requests in flight
+	busy := 0.0
+	samplerDone := make(chan struct{})
+	go func() {
+		defer close(samplerDone)
+		ticker := time.NewTicker(inputs.Interval)
+		defer ticker.Stop()
+		last := start
+		for {
+			select {
+			case <-loadCtx.Done():
+				return
+			case now := <-ticker.C:
+				desired, actual := deploymentReplicas(ctx, params, svc.Namespace, selector)
+				m.Lock()
+				concurrency := busy / now.Sub(last).Seconds()
+				busy = 0
+				m.Unlock()
+				last = now
+				offset := now.Sub(start)
+				result.Samples = append(result.Samples, pkg.AutoscalerSample{
+					Offset:      offset.Seconds(),
+					QPS:         shapeQPS(inputs.Shape, offset, inputs.Duration, inputs.Period, float64(inputs.MinQPS), float64(inputs.MaxQPS)),
+					Concurrency: concurrency,
+					Ideal:       int(math.Ceil(concurrency / target)),
+					Desired:     desired,
+					Actual:      actual,
+				})
+			}
+		}
+	}()
+
+	client := http.Client{Timeout: inputs.RequestTimeout}
+	pool.ForEach(loadCtx, inputs.Connections, inputs.Connections, func(ctx context.Context, i int) {
+		for range tokens {
+			requestStart := time.Now()
+			err := sendRequest(loadCtx, client, endpoint, svc)
+			if loadCtx.Err() != nil {
+				// requests interrupted by the end of the load are not counted
+				return
+			}
+			if err != nil && inputs.Verbose {
+				fmt.Printf("[Verbose] Service %s: request failed: %s\n", svc.Name, err)
+			}
+			m.Lock()
+			busy += time.Since(requestStart).Seconds()
+			result.Requests++
+			if err != nil {
+				result.Failed++
+			}
+			m.Unlock()
+		}
+	})
+	<-samplerDone
+	podWatcher.Stop()
+	<-podWatchDone
+
+	summarizeProvisioning(&result, inputs.Interval)
+	result.PodChurn = result.PodsStarted + result.PodsTerminated
+	return result, nil
+}
+
+// deploymentReplicas returns the replicas desired by the autoscaler and the ready replicas of the deployment of the
+// service, 0 if it can't be read
+func deploymentReplicas(ctx context.Context, params *pkg.PerfParams, namespace, selector string) (int, int) {
+	deployments, err := params.ClientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil || len(deployments.Items) == 0 {
+		return 0, 0
+	}
+	desired, actual := 0, 0
+	for _, d := range deployments.Items {
+		if d.Spec.Replicas != nil {
+			desired += int(*d.Spec.Replicas)
+		}
+		actual += int(d.Status.ReadyReplicas)
+	}
+	return desired, actual
+}
+
+// sendRequest sends a single GET request to the service
+func sendRequest(ctx context.Context, client http.Client, endpoint string, svc *servingv1.Service) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if svc.Status.URL != nil {
+		req.Host = svc.Status.URL.URL().Host
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// summarizeProvisioning computes the areas between the actual and the ideal replicas of the samples, and the times
+// the actual replicas took to catch up with the ideal ones after the service became under-provisioned. An
+// under-provisioning which lasted until the end of the load is not counted as scale up.
+func summarizeProvisioning(result *pkg.AutoscalerBenchmarkResult, interval time.Duration) {
+	var under bool
+	var underSince float64
+	var times []float64
+	for _, s := range result.Samples {
+		diff := float64(s.Actual - s.Ideal)
+		if diff > 0 {
+			result.OverProvisioned += diff * interval.Seconds()
+		} else {
+			result.UnderProvisioned += -diff * interval.Seconds()
+		}
+		switch {
+		case s.Actual < s.Ideal && !under:
+			under, underSince = true, s.Offset
+		case s.Actual >= s.Ideal && under:
+			under = false
+			times = append(times, s.Offset-underSince)
+		}
+	}
+	result.ScaleUps = len(times)
+	for _, t := range times {
+		result.TimeToScale += t
+		result.TimeToScaleMax = math.Max(result.TimeToScaleMax, t)
+	}
+	if len(times) > 0 {
+		result.TimeToScale /= float64(len(times))
+	}
+}
+
+func writeBenchmark(out io.Writer, result pkg.AutoscalerBenchmarkResult) {
+	fmt.Fprintf(out, "-------- Measurement --------\n")
+	fmt.Fprintf(out, "Autoscaler Benchmark Measurement:\n")
+	fmt.Fprintf(out, "Service: %s/%s | Shape: %s Target: %f\n", result.ServiceNamespace, result.ServiceName, result.Shape, result.Target)
+	fmt.Fprintf(out, "Requests: %d | Failed: %d\n", result.Requests, result.Failed)
+	fmt.Fprintf(out, "Over-Provisioned: %f replica seconds | Under-Provisioned: %f replica seconds\n", result.OverProvisioned, result.UnderProvisioned)
+	fmt.Fprintf(out, "Scale Ups: %d | Time To Scale Average: %fs Max: %fs\n", result.ScaleUps, result.TimeToScale, result.TimeToScaleMax)
+	fmt.Fprintf(out, "Pod Churn: %d | Started: %d Terminated: %d\n", result.PodChurn, result.PodsStarted, result.PodsTerminated)
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewAutoscalerBenchmarkCommand(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+	}
+
+	t.Run("incompleted or wrong args for autoscaler benchmark", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewAutoscalerBenchmarkCommand(p))
+		assert.ErrorContains(t, err, "'autoscaler benchmark' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewAutoscalerBenchmarkCommand(p), "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "--namespace and --svc are required to select the service")
+
+		_, err = testutil.ExecuteCommand(NewAutoscalerBenchmarkCommand(p), "--namespace", "ns-1", "--svc", "ksvc-1", "--shape", "square")
+		assert.ErrorContains(t, err, "expected --shape to be one of step,ramp,spike,sine, given square")
+
+		_, err = testutil.ExecuteCommand(NewAutoscalerBenchmarkCommand(p), "--namespace", "ns-1", "--svc", "ksvc-1", "--min-qps", "20", "--max-qps", "10")
+		assert.ErrorContains(t, err, "expected 0 <= --min-qps <= --max-qps and --max-qps >= 1, given 20 and 10")
+
+		_, err = testutil.ExecuteCommand(NewAutoscalerBenchmarkCommand(p), "--namespace", "ns-1", "--svc", "ksvc-1", "--interval", "0s")
+		assert.ErrorContains(t, err, "--duration, --interval and --period must be greater than 0")
+
+		_, err = testutil.ExecuteCommand(NewAutoscalerBenchmarkCommand(p), "--namespace", "ns-1", "--svc", "ksvc-1")
+		assert.ErrorContains(t, err, "failed to get Knative Service ksvc-1 in namespace ns-1")
+	})
+
+	t.Run("benchmark the autoscaler", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(5 * time.Millisecond)
+			w.Write([]byte("hello"))
+		}))
+		defer server.Close()
+		url, err := apis.ParseURL(server.URL)
+		assert.NilError(t, err)
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+		svc.Spec.Template.Annotations = map[string]string{"autoscaling.knative.dev/target": "1"}
+		_, err = fakeServing.Services("ns-1").Create(context.Background(), svc, metav1.CreateOptions{})
+		assert.NilError(t, err)
+		svc.Status.URL = url
+		_, err = fakeServing.Services("ns-1").UpdateStatus(context.Background(), svc, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+		replicas := int32(2)
+		_, err = client.AppsV1().Deployments("ns-1").Create(context.Background(), &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001-deployment", Namespace: "ns-1", Labels: map[string]string{serving.ServiceLabelKey: "ksvc-1"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+		}, metav1.CreateOptions{})
+		assert.NilError(t, err)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			client.CoreV1().Pods("ns-1").Create(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-pod",
+				Namespace: "ns-1", Labels: map[string]string{serving.ServiceLabelKey: "ksvc-1"}}}, metav1.CreateOptions{})
+		}()
+
+		outputDir := t.TempDir()
+		_, err = testutil.ExecuteCommand(NewAutoscalerBenchmarkCommand(p), "--namespace", "ns-1", "--svc", "ksvc-1", "--resolvable",
+			"--shape", "ramp", "--min-qps", "50", "--max-qps", "100", "--duration", "300ms", "--interval", "50ms", "--output", outputDir)
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+BenchmarkOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Equal(t, "offset,qps,concurrency,ideal,desired,actual", lines[0])
+		assert.Assert(t, len(lines) > 3, string(data))
+		assert.Assert(t, strings.HasSuffix(lines[1], ",2,1"), lines[1])
+	})
+}
+
+func TestShapeQPS(t *testing.T) {
+	duration := 90 * time.Second
+	for _, tc := range []struct {
+		shape  string
+		offset time.Duration
+		qps    float64
+	}{
+		{ShapeStep, 0, 10},
+		{ShapeStep, 30 * time.Second, 100},
+		{ShapeStep, 60 * time.Second, 10},
+		{ShapeRamp, 0, 10},
+		{ShapeRamp, 45 * time.Second, 55},
+		{ShapeRamp, 90 * time.Second, 100},
+		{ShapeSpike, 35 * time.Second, 10},
+		{ShapeSpike, 36 * time.Second, 100},
+		{ShapeSpike, 45 * time.Second, 10},
+		{ShapeSine, 0, 10},
+		{ShapeSine, 15 * time.Second, 55},
+		{ShapeSine, 30 * time.Second, 100},
+	} {
+		qps := shapeQPS(tc.shape, tc.offset, duration, time.Minute, 10, 100)
+		assert.Assert(t, qps > tc.qps-0.001 && qps < tc.qps+0.001, "%s at %s: expected %f, got %f", tc.shape, tc.offset, tc.qps, qps)
+	}
+}
+
+func TestServiceTarget(t *testing.T) {
+	svc := &servingv1.Service{}
+	target, err := serviceTarget(svc)
+	assert.NilError(t, err)
+	assert.Equal(t, 100.0, target)
+
+	cc := int64(10)
+	svc.Spec.Template.Spec.ContainerConcurrency = &cc
+	target, err = serviceTarget(svc)
+	assert.NilError(t, err)
+	assert.Equal(t, 10.0, target)
+
+	svc.Spec.Template.Annotations = map[string]string{"autoscaling.knative.dev/target": "2.5"}
+	target, err = serviceTarget(svc)
+	assert.NilError(t, err)
+	assert.Equal(t, 2.5, target)
+
+	svc.Spec.Template.Annotations = map[string]string{"autoscaling.knative.dev/target": "x"}
+	_, err = serviceTarget(svc)
+	assert.ErrorContains(t, err, "invalid autoscaling.knative.dev/target \"x\"")
+}
+
+func TestSummarizeProvisioning(t *testing.T) {
+	result := &pkg.AutoscalerBenchmarkResult{Samples: []pkg.AutoscalerSample{
+		{Offset: 1, Ideal: 1, Actual: 1},
+		{Offset: 2, Ideal: 3, Actual: 1},
+		{Offset: 3, Ideal: 3, Actual: 2},
+		{Offset: 4, Ideal: 3, Actual: 3},
+		{Offset: 5, Ideal: 1, Actual: 3},
+		{Offset: 6, Ideal: 4, Actual: 3},
+		{Offset: 7, Ideal: 4, Actual: 4},
+		{Offset: 8, Ideal: 5, Actual: 4},
+	}}
+	summarizeProvisioning(result, time.Second)
+	assert.Equal(t, 2.0, result.OverProvisioned)
+	assert.Equal(t, 5.0, result.UnderProvisioned)
+	assert.Equal(t, 2, result.ScaleUps)
+	assert.Equal(t, 1.5, result.TimeToScale)
+	assert.Equal(t, 2.0, result.TimeToScaleMax)
+}
@@ -0,0 +1,214 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calibrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/compare"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	OutputFilename = "noise_profile"
+
+	// thresholdStdDevs is the number of standard deviations of the run medians a change has to
+	// exceed not to be considered noise
+	thresholdStdDevs = 2
+)
+
+// NewCalibrateCommand implements 'kperf calibrate' command
+func NewCalibrateCommand(p *pkg.PerfParams) *cobra.Command {
+	calibrateArgs := pkg.CalibrateArgs{}
+	calibrateCommand := &cobra.Command{
+		Use:   "calibrate",
+		Short: "Estimate the run-to-run noise of the cluster",
+		Long: `Run a small standard workload several times to estimate the run-to-run variance of every phase
+
+Every run generates Knative Services, waits for them to be ready, measures and cleans them. The spread of the per run
+medians is saved as a noise profile, which can be passed to 'kperf compare --noise-profile' to only report changes
+above the noise as regressions.
+
+For example:
+# To calibrate with 5 runs of 10 Knative Services in namespace ktest
+kperf calibrate --runs 5 --number 10 --namespace ktest --output /tmp
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if calibrateArgs.Runs < 2 {
+				return fmt.Errorf("at least 2 runs are required to estimate the noise, given %d", calibrateArgs.Runs)
+			}
+			if calibrateArgs.Number < 1 {
+				return fmt.Errorf("at least 1 Knative Service per run is required, given %d", calibrateArgs.Number)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Calibrate(p, calibrateArgs)
+		},
+	}
+
+	calibrateCommand.Flags().IntVarP(&calibrateArgs.Runs, "runs", "", 5, "Number of runs of the standard workload")
+	calibrateCommand.Flags().IntVarP(&calibrateArgs.Number, "number", "n", 10, "Number of Knative Services created in each run")
+	calibrateCommand.Flags().IntVarP(&calibrateArgs.Concurrency, "concurrency", "c", 10, "Number of multiple Knative Services to make and measure at a time")
+	calibrateCommand.Flags().StringVarP(&calibrateArgs.Namespace, "namespace", "", service.DefaultNamespace, "Namespace name. The Knative Services will be created in the namespace")
+	calibrateCommand.Flags().StringVarP(&calibrateArgs.SvcPrefix, "svc-prefix", "", "kperf-calibrate", "Knative Service name prefix. The Knative Services of run i will be svcPrefix-ri-0, svcPrefix-ri-1 and etc.")
+	calibrateCommand.Flags().DurationVarP(&calibrateArgs.Timeout, "timeout", "", 5*time.Minute, "Duration to wait for each Knative Service to be ready")
+	calibrateCommand.Flags().StringVarP(&calibrateArgs.Output, "output", "o", ".", "Noise profile location")
+	report.AddFlag(calibrateCommand.Flags(), &calibrateArgs.OutputFormats)
+	return calibrateCommand
+}
+
+// Calibrate runs the standard workload and saves the noise profile
+func Calibrate(params *pkg.PerfParams, inputs pkg.CalibrateArgs) error {
+	runMedians := make([]map[string]float64, 0, inputs.Runs)
+	for i := 0; i < inputs.Runs; i++ {
+		fmt.Printf("-------- Calibration run %d/%d --------\n", i+1, inputs.Runs)
+		samples, err := RunWorkload(params, inputs, fmt.Sprintf("%s-r%d", inputs.SvcPrefix, i))
+		if err != nil {
+			return fmt.Errorf("calibration run %d failed: %w", i+1, err)
+		}
+		medians := map[string]float64{}
+		for phase, values := range samples {
+			medians[phase], _ = stats.Median(values)
+		}
+		runMedians = append(runMedians, medians)
+	}
+
+	profile := buildNoiseProfile(runMedians)
+	profile.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	profile.ServicesPerRun = inputs.Number
+	profile.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	phases := make([]string, 0, len(profile.Phases))
+	for phase := range profile.Phases {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	fmt.Printf("-------- Noise Profile --------\n")
+	rows := [][]string{{"phase", "mean", "std_dev", "coefficient_of_variation", "threshold_percent"}}
+	for _, phase := range phases {
+		noise := profile.Phases[phase]
+		fmt.Printf("%s: Mean: %fs StdDev: %fs CV: %.2f%% Threshold: %.2f%%\n", phase, noise.Mean, noise.StdDev, noise.CoefficientOfVariation*100, noise.ThresholdPercent)
+		rows = append(rows, []string{phase,
+			fmt.Sprintf("%f", noise.Mean),
+			fmt.Sprintf("%f", noise.StdDev),
+			fmt.Sprintf("%f", noise.CoefficientOfVariation),
+			fmt.Sprintf("%f", noise.ThresholdPercent),
+		})
+	}
+
+	outputLocation, err := utils.CheckOutputLocation(inputs.Output)
+	if err != nil {
+		return err
+	}
+	return report.Write(os.Stdout, outputLocation, time.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    OutputFilename,
+		Rows:    rows,
+		Result:  profile,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// RunWorkload generates, measures and cleans one batch of inputs.Number Knative Services named with svcPrefix and
+// returns the durations of every measured phase
+func RunWorkload(params *pkg.PerfParams, inputs pkg.CalibrateArgs, svcPrefix string) (map[string][]float64, error) {
+	err := service.GenerateServices(params, pkg.GenerateArgs{
+		Number:      inputs.Number,
+		Interval:    time.Second,
+		Batch:       inputs.Number,
+		Concurrency: inputs.Concurrency,
+		Namespace:   inputs.Namespace,
+		SvcPrefix:   svcPrefix,
+		CheckReady:  true,
+		Timeout:     inputs.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer service.CleanServices(params, pkg.CleanArgs{
+		Namespace:   inputs.Namespace,
+		SvcPrefix:   svcPrefix,
+		Concurrency: inputs.Concurrency,
+	})
+
+	dir, err := ioutil.TempDir("", "kperf-calibrate")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	err = service.MeasureServices(params, pkg.MeasureArgs{
+		SvcRange:    fmt.Sprintf("0,%d", inputs.Number-1),
+		Namespace:   inputs.Namespace,
+		SvcPrefix:   svcPrefix,
+		Concurrency: inputs.Concurrency,
+		Output:      dir,
+	}, service.MeasureServicesOptions{NamespaceChanged: true})
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*_ksvc_creation_time.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, errors.New("no Knative Service was measured")
+	}
+	samples, _, err := compare.ReadSamples(matches[0])
+	return samples, err
+}
+
+// buildNoiseProfile computes the spread of the per run medians of the phases present in all runs
+func buildNoiseProfile(runMedians []map[string]float64) pkg.NoiseProfile {
+	profile := pkg.NoiseProfile{Runs: len(runMedians), Phases: map[string]pkg.PhaseNoise{}}
+	if len(runMedians) == 0 {
+		return profile
+	}
+	for phase := range runMedians[0] {
+		values := make([]float64, 0, len(runMedians))
+		for _, medians := range runMedians {
+			if v, ok := medians[phase]; ok {
+				values = append(values, v)
+			}
+		}
+		if len(values) != len(runMedians) {
+			continue
+		}
+		noise := pkg.PhaseNoise{}
+		noise.Mean, _ = stats.Mean(values)
+		noise.StdDev, _ = stats.StandardDeviationSample(values)
+		if noise.Mean != 0 {
+			noise.CoefficientOfVariation = noise.StdDev / noise.Mean
+			noise.ThresholdPercent = thresholdStdDevs * noise.CoefficientOfVariation * 100
+		}
+		profile.Phases[phase] = noise
+	}
+	return profile
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calibrate
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	fakenetworkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	autoscalingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
+	autoscalingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1/fake"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewCalibrateCommand(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ns-1",
+		},
+	}
+	client := k8sfake.NewSimpleClientset(ns)
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeAutoscaling := &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: &client.Fake}
+	fakeNetworking := &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: &client.Fake}
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+			return fakeAutoscaling, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return fakeNetworking, nil
+		},
+	}
+
+	t.Run("incompleted or wrong args for calibrate", func(t *testing.T) {
+		cmd := NewCalibrateCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "--runs", "1")
+		assert.ErrorContains(t, err, "at least 2 runs are required to estimate the noise, given 1")
+
+		cmd = NewCalibrateCommand(p)
+		_, err = testutil.ExecuteCommand(cmd, "--number", "0")
+		assert.ErrorContains(t, err, "at least 1 Knative Service per run is required, given 0")
+
+		cmd = NewCalibrateCommand(p)
+		_, err = testutil.ExecuteCommand(cmd, "--namespace", "ns-2")
+		assert.ErrorContains(t, err, "calibration run 1 failed: namespace ns-2 not found, please create one")
+	})
+
+}
+
+func TestBuildNoiseProfile(t *testing.T) {
+	profile := buildNoiseProfile([]map[string]float64{
+		{"overall_ready": 10, "revision_ready": 0, "route_ready": 5},
+		{"overall_ready": 12, "revision_ready": 0},
+		{"overall_ready": 14, "revision_ready": 0},
+	})
+	assert.Equal(t, 3, profile.Runs)
+	assert.Equal(t, 2, len(profile.Phases), "phases missing in a run should be skipped")
+
+	noise := profile.Phases["overall_ready"]
+	assert.Equal(t, 12.0, noise.Mean)
+	assert.Equal(t, 2.0, noise.StdDev)
+	assert.Equal(t, 2.0/12, noise.CoefficientOfVariation)
+	assert.Equal(t, thresholdStdDevs*noise.CoefficientOfVariation*100, noise.ThresholdPercent)
+
+	assert.Equal(t, 0.0, profile.Phases["revision_ready"].ThresholdPercent)
+	assert.Equal(t, 0, len(buildNoiseProfile(nil).Phases))
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewCleanCmd(p *pkg.PerfParams) *cobra.Command {
+	var cleanCmd = &cobra.Command{
+		Use:   "clean",
+		Short: "Clean resources generated by kperf",
+		Long: `Clean resources generated by kperf across all kinds and namespaces. For example:
+
+kperf clean expired - to delete the generated resources whose time to live has passed`,
+	}
+	cleanCmd.AddCommand(NewCleanExpiredCommand(p))
+
+	cleanCmd.InitDefaultHelpCmd()
+	return cleanCmd
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/domainmapping"
+	"knative.dev/kperf/pkg/command/eventing"
+	"knative.dev/kperf/pkg/generator"
+)
+
+// reaper lists the resources of a kind stamped with an expiry label and deletes them
+type reaper struct {
+	kind   string
+	list   func(ctx context.Context) ([]metav1.Object, error)
+	delete func(ctx context.Context, namespace, name string) error
+}
+
+func NewCleanExpiredCommand(p *pkg.PerfParams) *cobra.Command {
+	cleanArgs := pkg.CleanExpiredArgs{}
+	cleanExpiredCommand := &cobra.Command{
+		Use:   "expired",
+		Short: "Clean expired resources generated by kperf",
+		Long: `Clean the resources generated by kperf with a time to live (--ttl) which has passed, in all namespaces
+
+Knative Services, Brokers, Triggers, DomainMappings and ClusterDomainClaims labeled with ` + pkg.ExpiresAtLabel + `
+are deleted once they expired, so resources of forgotten runs don't pile up in shared clusters. Run it periodically,
+e.g. from a CronJob, to reap them automatically.
+
+For example:
+# To list the expired resources without deleting them
+kperf clean expired --dry-run
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CleanExpired(p, cleanArgs, time.Now())
+		},
+	}
+
+	cleanExpiredCommand.Flags().IntVarP(&cleanArgs.Concurrency, "concurrency", "c", 10, "Number of multiple resources to delete at a time")
+	cleanExpiredCommand.Flags().BoolVarP(&cleanArgs.DryRun, "dry-run", "", false, "Only print the expired resources instead of deleting them")
+	return cleanExpiredCommand
+}
+
+// CleanExpired used to delete the resources generated by kperf which expired at now. Kinds which are not
+// installed in the cluster, e.g. Brokers without Knative Eventing, are skipped.
+func CleanExpired(params *pkg.PerfParams, inputs pkg.CleanExpiredArgs, now time.Time) error {
+	reapers, err := newReapers(params)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	expiredCount := 0
+	for _, r := range reapers {
+		objs, err := r.list(ctx)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("failed to list %ss and skip: %s\n", r.kind, err)
+			continue
+		}
+		matchedNsNameList := [][2]string{}
+		for _, obj := range objs {
+			if pkg.Expired(obj.GetLabels(), now) {
+				matchedNsNameList = append(matchedNsNameList, [2]string{obj.GetNamespace(), obj.GetName()})
+			}
+		}
+		expiredCount += len(matchedNsNameList)
+		if len(matchedNsNameList) == 0 {
+			continue
+		}
+		if inputs.DryRun {
+			for _, nsName := range matchedNsNameList {
+				fmt.Printf("Expired %s %s%s\n", r.kind, nsName[1], inNamespace(nsName[0]))
+			}
+			continue
+		}
+		reaper := r
+		generator.NewBatchCleaner(matchedNsNameList, inputs.Concurrency, func(namespace, name string) {
+			fmt.Printf("Delete expired %s %s%s\n", reaper.kind, name, inNamespace(namespace))
+			if err := reaper.delete(ctx, namespace, name); err != nil && !apierrors.IsNotFound(err) {
+				fmt.Printf("Failed to delete expired %s %s%s\n", reaper.kind, name, inNamespace(namespace))
+			}
+		}).Clean()
+	}
+	if expiredCount == 0 {
+		fmt.Println("No expired resource found for cleaning")
+	}
+	return nil
+}
+
+// newReapers returns the reapers of the kinds kperf generates, Triggers are deleted before their Brokers
+func newReapers(params *pkg.PerfParams) ([]reaper, error) {
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	networkingClient, err := params.NewNetworkingClient()
+	if err != nil {
+		return nil, err
+	}
+	listOptions := metav1.ListOptions{LabelSelector: pkg.ExpiresAtLabel}
+
+	return []reaper{
+		dynamicReaper(dynamicClient, "Trigger", eventing.TriggerGVR),
+		dynamicReaper(dynamicClient, "Broker", eventing.BrokerGVR),
+		dynamicReaper(dynamicClient, "DomainMapping", domainmapping.DomainMappingGVR),
+		{
+			kind: "ClusterDomainClaim",
+			list: func(ctx context.Context) ([]metav1.Object, error) {
+				claimList, err := networkingClient.ClusterDomainClaims().List(ctx, listOptions)
+				if err != nil {
+					return nil, err
+				}
+				objs := []metav1.Object{}
+				for i := range claimList.Items {
+					objs = append(objs, &claimList.Items[i])
+				}
+				return objs, nil
+			},
+			delete: func(ctx context.Context, namespace, name string) error {
+				return networkingClient.ClusterDomainClaims().Delete(ctx, name, metav1.DeleteOptions{})
+			},
+		},
+		{
+			kind: "Knative Service",
+			list: func(ctx context.Context) ([]metav1.Object, error) {
+				svcList, err := ksvcClient.Services(metav1.NamespaceAll).List(ctx, listOptions)
+				if err != nil {
+					return nil, err
+				}
+				objs := []metav1.Object{}
+				for i := range svcList.Items {
+					objs = append(objs, &svcList.Items[i])
+				}
+				return objs, nil
+			},
+			delete: func(ctx context.Context, namespace, name string) error {
+				return ksvcClient.Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+		},
+	}, nil
+}
+
+func dynamicReaper(client dynamic.Interface, kind string, gvr schema.GroupVersionResource) reaper {
+	return reaper{
+		kind: kind,
+		list: func(ctx context.Context) ([]metav1.Object, error) {
+			list, err := client.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: pkg.ExpiresAtLabel})
+			if err != nil {
+				return nil, err
+			}
+			objs := []metav1.Object{}
+			for i := range list.Items {
+				objs = append(objs, &list.Items[i])
+			}
+			return objs, nil
+		},
+		delete: func(ctx context.Context, namespace, name string) error {
+			return client.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+	}
+}
+
+func inNamespace(namespace string) string {
+	if namespace == "" {
+		return ""
+	}
+	return " in namespace " + namespace
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	networkingv1alpha1fake "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/domainmapping"
+	"knative.dev/kperf/pkg/command/eventing"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+var testNow = time.Unix(1640995200, 0)
+
+func newTestObject(gvr schema.GroupVersionResource, kind, ns, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	obj.SetKind(kind)
+	obj.SetNamespace(ns)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+// newTestPerfParams returns PerfParams backed by fake clients which record the deleted Knative Services and
+// ClusterDomainClaims and list the given ones
+func newTestPerfParams(services []servingv1.Service, claims []netv1alpha1.ClusterDomainClaim, objects ...runtime.Object) (*pkg.PerfParams, *dynamicfake.FakeDynamicClient, *[]string) {
+	client := k8sfake.NewSimpleClientset()
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeNetworking := &networkingv1alpha1fake.FakeNetworkingV1alpha1{Fake: &client.Fake}
+	fakeDynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		eventing.BrokerGVR:             "BrokerList",
+		eventing.TriggerGVR:            "TriggerList",
+		domainmapping.DomainMappingGVR: "DomainMappingList",
+	}, objects...)
+
+	deleted := []string{}
+	client.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &servingv1.ServiceList{Items: services}, nil
+	})
+	client.PrependReactor("list", "clusterdomainclaims", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &netv1alpha1.ClusterDomainClaimList{Items: claims}, nil
+	})
+	client.PrependReactor("delete", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		deleted = append(deleted, action.GetResource().Resource+"/"+action.(clienttesting.DeleteAction).GetName())
+		return true, nil, nil
+	})
+
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return fakeNetworking, nil
+		},
+		NewDynamicClient: func() (dynamic.Interface, error) {
+			return fakeDynamic, nil
+		},
+	}, fakeDynamic, &deleted
+}
+
+func TestCleanExpired(t *testing.T) {
+	expired := pkg.ExpiryLabels(time.Minute, testNow.Add(-time.Hour))
+	alive := pkg.ExpiryLabels(time.Hour, testNow)
+	services := []servingv1.Service{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-0", Namespace: "ns-1", Labels: expired}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1", Labels: alive}},
+	}
+	claims := []netv1alpha1.ClusterDomainClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: "kperf-0.example.com", Labels: expired}},
+	}
+	objects := []runtime.Object{
+		newTestObject(eventing.BrokerGVR, "Broker", "ns-1", "broker-0", expired),
+		newTestObject(eventing.TriggerGVR, "Trigger", "ns-1", "broker-0-trigger-0", alive),
+		newTestObject(domainmapping.DomainMappingGVR, "DomainMapping", "ns-2", "kperf-0.example.com", expired),
+		newTestObject(domainmapping.DomainMappingGVR, "DomainMapping", "ns-2", "other.example.com", nil),
+	}
+
+	t.Run("delete expired resources", func(t *testing.T) {
+		p, fakeDynamic, deleted := newTestPerfParams(services, claims, objects...)
+
+		err := CleanExpired(p, pkg.CleanExpiredArgs{Concurrency: 1}, testNow)
+		assert.NilError(t, err)
+
+		sort.Strings(*deleted)
+		assert.DeepEqual(t, []string{"clusterdomainclaims/kperf-0.example.com", "services/ksvc-0"}, *deleted)
+		brokers, err := fakeDynamic.Resource(eventing.BrokerGVR).Namespace("ns-1").List(context.TODO(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 0, len(brokers.Items))
+		triggers, err := fakeDynamic.Resource(eventing.TriggerGVR).Namespace("ns-1").List(context.TODO(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(triggers.Items))
+		dms, err := fakeDynamic.Resource(domainmapping.DomainMappingGVR).Namespace("ns-2").List(context.TODO(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(dms.Items))
+		assert.Equal(t, "other.example.com", dms.Items[0].GetName())
+	})
+
+	t.Run("keep expired resources in dry run", func(t *testing.T) {
+		p, fakeDynamic, deleted := newTestPerfParams(services, claims, objects...)
+
+		err := CleanExpired(p, pkg.CleanExpiredArgs{Concurrency: 1, DryRun: true}, testNow)
+		assert.NilError(t, err)
+
+		assert.Equal(t, 0, len(*deleted))
+		brokers, err := fakeDynamic.Resource(eventing.BrokerGVR).Namespace("ns-1").List(context.TODO(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(brokers.Items))
+	})
+}
+
+func TestNewCleanExpiredCommand(t *testing.T) {
+	p, _, _ := newTestPerfParams(nil, nil)
+
+	_, err := testutil.ExecuteCommand(NewCleanExpiredCommand(p), "--dry-run")
+	assert.NilError(t, err)
+}
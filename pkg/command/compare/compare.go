@@ -0,0 +1,260 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	VerdictRegression  = "regression"
+	VerdictImprovement = "improvement"
+	VerdictNoChange    = "no change"
+
+	OutputFilename = "compare"
+)
+
+// NewCompareCommand implements 'kperf compare' command
+func NewCompareCommand(p *pkg.PerfParams) *cobra.Command {
+	compareArgs := pkg.CompareArgs{}
+	compareCommand := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare the measurements of two runs",
+		Long: `Compare the measurements of two runs metric by metric
+
+The baseline is either the measurement of a run of your own or a reference baseline of the community for a Knative
+version and an environment, to see whether your cluster is unusually slow.
+
+The per service samples of the measurement CSV files are compared with a statistical significance test, so small
+differences which are within the run-to-run noise are not reported as regressions.
+
+For example:
+# To compare a run with a baseline run
+kperf compare --base /tmp/20210117104747_ksvc_creation_time.csv --current /tmp/20210118104747_ksvc_creation_time.csv --alpha 0.01
+
+# To compare a run with a reference baseline fetched from a URL
+kperf compare --reference https://example.com/knative-1.14-gke-n2.csv --current /tmp/20210118104747_ksvc_creation_time.csv
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if compareArgs.Base == "" && compareArgs.Reference == "" {
+				return fmt.Errorf("either --base or --reference is required")
+			}
+			if compareArgs.Base != "" && compareArgs.Reference != "" {
+				return fmt.Errorf("--base and --reference can't be combined")
+			}
+			if compareArgs.Alpha <= 0 || compareArgs.Alpha >= 1 {
+				return fmt.Errorf("alpha must be between 0 and 1, given %v", compareArgs.Alpha)
+			}
+			if compareArgs.Test != TestMannWhitney && compareArgs.Test != TestWelch {
+				return fmt.Errorf("unsupported test %q, expected one of %s, %s", compareArgs.Test, TestMannWhitney, TestWelch)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CompareRuns(p, compareArgs)
+		},
+	}
+
+	compareCommand.Flags().StringVarP(&compareArgs.Base, "base", "", "", "Measurement CSV file of the baseline run")
+	compareCommand.Flags().StringVarP(&compareArgs.Reference, "reference", "", "", fmt.Sprintf("Reference baseline to compare with instead of --base, a http(s) URL of a measurement CSV file or one of the shipped references %v", References()))
+	compareCommand.Flags().StringVarP(&compareArgs.Current, "current", "", "", "Measurement CSV file of the run to compare with the baseline")
+	compareCommand.MarkFlagRequired("current")
+	compareCommand.Flags().Float64VarP(&compareArgs.Alpha, "alpha", "", 0.05, "Significance level, differences with a p-value above are reported as no change")
+	compareCommand.Flags().StringVarP(&compareArgs.Test, "test", "", TestMannWhitney, "Significance test, one of mann-whitney,t-test")
+	compareCommand.Flags().StringVarP(&compareArgs.NoiseProfile, "noise-profile", "", "", "Noise profile saved by 'kperf calibrate', significant changes below its per metric threshold are reported as no change")
+	compareCommand.Flags().StringVarP(&compareArgs.Output, "output", "o", ".", "Compare result location")
+	report.AddFlag(compareCommand.Flags(), &compareArgs.OutputFormats)
+	return compareCommand
+}
+
+// CompareRuns compares the samples of every metric present in both measurement files
+func CompareRuns(params *pkg.PerfParams, inputs pkg.CompareArgs) error {
+	base := inputs.Base
+	readBase := ReadSamples
+	if inputs.Reference != "" {
+		base = inputs.Reference
+		readBase = ReadReference
+	}
+	baseSamples, baseMetrics, err := readBase(base)
+	if err != nil {
+		return err
+	}
+	currentSamples, _, err := ReadSamples(inputs.Current)
+	if err != nil {
+		return err
+	}
+
+	profile := pkg.NoiseProfile{}
+	if inputs.NoiseProfile != "" {
+		profile, err = ReadNoiseProfile(inputs.NoiseProfile)
+		if err != nil {
+			return err
+		}
+	}
+
+	result := pkg.CompareResult{Base: base, Current: inputs.Current, Test: inputs.Test, Alpha: inputs.Alpha}
+	for _, metric := range baseMetrics {
+		current, ok := currentSamples[metric]
+		if !ok {
+			continue
+		}
+		result.Metrics = append(result.Metrics, compareMetric(metric, baseSamples[metric], current, inputs.Test, inputs.Alpha, profile.Phases[metric].ThresholdPercent))
+	}
+	if len(result.Metrics) == 0 {
+		return errors.New("no common metric found to compare")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "METRIC\tBASE MEDIAN\tCURRENT MEDIAN\tDELTA\tTHRESHOLD\tP-VALUE\tVERDICT\n")
+	for _, m := range result.Metrics {
+		fmt.Fprintf(w, "%s\t%fs\t%fs\t%+.2f%%\t%.2f%%\t%.4f\t%s\n", m.Metric, m.BaseMedian, m.CurrentMedian, m.DeltaPercent, m.ThresholdPercent, m.PValue, m.Verdict)
+	}
+	w.Flush()
+
+	rows := [][]string{{"metric", "base_count", "current_count", "base_median", "current_median", "delta_percent", "threshold_percent", "p_value", "significant", "verdict"}}
+	for _, m := range result.Metrics {
+		rows = append(rows, []string{m.Metric,
+			strconv.Itoa(m.BaseCount),
+			strconv.Itoa(m.CurrentCount),
+			fmt.Sprintf("%f", m.BaseMedian),
+			fmt.Sprintf("%f", m.CurrentMedian),
+			fmt.Sprintf("%f", m.DeltaPercent),
+			fmt.Sprintf("%f", m.ThresholdPercent),
+			fmt.Sprintf("%f", m.PValue),
+			strconv.FormatBool(m.Significant),
+			m.Verdict,
+		})
+	}
+
+	current := time.Now()
+	outputLocation, err := utils.CheckOutputLocation(inputs.Output)
+	if err != nil {
+		fmt.Printf("failed to check compare output location: %s\n", err)
+	}
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    OutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// compareMetric compares the samples of a metric. A change is only reported if it is statistically significant
+// and the relative change of the median is at least thresholdPercent.
+func compareMetric(metric string, base, current []float64, test string, alpha, thresholdPercent float64) pkg.MetricComparison {
+	comparison := pkg.MetricComparison{
+		Metric:           metric,
+		BaseCount:        len(base),
+		CurrentCount:     len(current),
+		ThresholdPercent: thresholdPercent,
+		Verdict:          VerdictNoChange,
+	}
+	comparison.BaseMedian, _ = stats.Median(base)
+	comparison.CurrentMedian, _ = stats.Median(current)
+	if comparison.BaseMedian != 0 {
+		comparison.DeltaPercent = (comparison.CurrentMedian - comparison.BaseMedian) / comparison.BaseMedian * 100
+	}
+
+	if test == TestWelch {
+		comparison.PValue = WelchTTest(base, current)
+	} else {
+		comparison.PValue = MannWhitneyU(base, current)
+	}
+	comparison.Significant = comparison.PValue < alpha
+	if !comparison.Significant || math.Abs(comparison.DeltaPercent) < thresholdPercent {
+		return comparison
+	}
+
+	baseCenter, currentCenter := comparison.BaseMedian, comparison.CurrentMedian
+	if baseCenter == currentCenter {
+		baseCenter, _ = stats.Mean(base)
+		currentCenter, _ = stats.Mean(current)
+	}
+	if currentCenter > baseCenter {
+		comparison.Verdict = VerdictRegression
+	} else if currentCenter < baseCenter {
+		comparison.Verdict = VerdictImprovement
+	}
+	return comparison
+}
+
+// ReadNoiseProfile reads a noise profile saved by 'kperf calibrate'
+func ReadNoiseProfile(path string) (pkg.NoiseProfile, error) {
+	profile := pkg.NoiseProfile{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return profile, fmt.Errorf("failed to read noise profile %s", err)
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return profile, fmt.Errorf("failed to parse noise profile %s", err)
+	}
+	return profile, nil
+}
+
+// ReadSamples reads a measurement CSV file with one row per service and returns the samples of every
+// numeric column by column name together with the column names in file order
+func ReadSamples(path string) (map[string][]float64, []string, error) {
+	rows, err := utils.ReadCSVFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseSamples(rows, path)
+}
+
+// parseSamples returns the samples of every numeric column of the rows of a measurement CSV file
+func parseSamples(rows [][]string, source string) (map[string][]float64, []string, error) {
+	if len(rows) < 2 {
+		return nil, nil, fmt.Errorf("no samples found in %s", source)
+	}
+
+	samples := map[string][]float64{}
+	metrics := []string{}
+	for col, name := range rows[0] {
+		values := make([]float64, 0, len(rows)-1)
+		numeric := true
+		for _, row := range rows[1:] {
+			if col >= len(row) {
+				numeric = false
+				break
+			}
+			v, err := strconv.ParseFloat(row[col], 64)
+			if err != nil {
+				numeric = false
+				break
+			}
+			values = append(values, v)
+		}
+		if numeric {
+			samples[name] = values
+			metrics = append(metrics, name)
+		}
+	}
+	return samples, metrics, nil
+}
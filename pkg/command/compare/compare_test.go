@@ -0,0 +1,178 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+const (
+	baseCSV = `svc_name,svc_namespace,revision_ready,overall_ready
+ktest-0,ktest,10,20
+ktest-1,ktest,11,21
+ktest-2,ktest,10,20
+ktest-3,ktest,12,22
+ktest-4,ktest,11,21
+ktest-5,ktest,10,20
+`
+	currentCSV = `svc_name,svc_namespace,revision_ready,overall_ready
+ktest-0,ktest,10,30
+ktest-1,ktest,12,31
+ktest-2,ktest,11,30
+ktest-3,ktest,10,32
+ktest-4,ktest,11,31
+ktest-5,ktest,12,30
+`
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestNewCompareCommand(t *testing.T) {
+	dir := t.TempDir()
+	base := writeTestFile(t, dir, "base.csv", baseCSV)
+	current := writeTestFile(t, dir, "current.csv", currentCSV)
+
+	t.Run("incompleted or wrong args for compare", func(t *testing.T) {
+		cmd := NewCompareCommand(&pkg.PerfParams{})
+		_, err := testutil.ExecuteCommand(cmd, "--base", base)
+		assert.ErrorContains(t, err, "required flag(s) \"current\" not set")
+
+		cmd = NewCompareCommand(&pkg.PerfParams{})
+		_, err = testutil.ExecuteCommand(cmd, "--base", base, "--current", current, "--alpha", "1.5")
+		assert.ErrorContains(t, err, "alpha must be between 0 and 1, given 1.5")
+
+		cmd = NewCompareCommand(&pkg.PerfParams{})
+		_, err = testutil.ExecuteCommand(cmd, "--base", base, "--current", current, "--test", "z-test")
+		assert.ErrorContains(t, err, "unsupported test \"z-test\"")
+
+		cmd = NewCompareCommand(&pkg.PerfParams{})
+		_, err = testutil.ExecuteCommand(cmd, "--base", filepath.Join(dir, "missing.csv"), "--current", current)
+		assert.ErrorContains(t, err, "failed to open csv file")
+
+		cmd = NewCompareCommand(&pkg.PerfParams{})
+		_, err = testutil.ExecuteCommand(cmd, "--current", current)
+		assert.ErrorContains(t, err, "either --base or --reference is required")
+
+		cmd = NewCompareCommand(&pkg.PerfParams{})
+		_, err = testutil.ExecuteCommand(cmd, "--base", base, "--reference", "knative-1.14-gke-n2", "--current", current)
+		assert.ErrorContains(t, err, "--base and --reference can't be combined")
+
+		cmd = NewCompareCommand(&pkg.PerfParams{})
+		_, err = testutil.ExecuteCommand(cmd, "--reference", "knative-0.1-unknown", "--current", current)
+		assert.ErrorContains(t, err, "unsupported reference \"knative-0.1-unknown\"")
+	})
+
+	t.Run("compare runs as expected", func(t *testing.T) {
+		cmd := NewCompareCommand(&pkg.PerfParams{})
+		_, err := testutil.ExecuteCommand(cmd, "--base", base, "--current", current, "--output", dir)
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(dir, "*_"+OutputFilename+".json"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+	})
+
+	t.Run("compare runs with output format", func(t *testing.T) {
+		output := t.TempDir()
+		cmd := NewCompareCommand(&pkg.PerfParams{})
+		_, err := testutil.ExecuteCommand(cmd, "--base", base, "--current", current, "--output", output, "--output-format", "csv")
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(output, "*_"+OutputFilename+".*"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		assert.Assert(t, strings.HasSuffix(matches[0], "_"+OutputFilename+".csv"), matches[0])
+	})
+
+	t.Run("compare runs with reference", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, baseCSV)
+		}))
+		defer server.Close()
+
+		output := t.TempDir()
+		cmd := NewCompareCommand(&pkg.PerfParams{})
+		_, err := testutil.ExecuteCommand(cmd, "--reference", server.URL+"/knative-1.14-kind.csv", "--current", current, "--output", output)
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(output, "*_"+OutputFilename+".json"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		result := pkg.CompareResult{}
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, server.URL+"/knative-1.14-kind.csv", result.Base)
+	})
+
+	t.Run("compare runs with noise profile", func(t *testing.T) {
+		profile := writeTestFile(t, dir, "profile.json", `{"Runs":5,"Phases":{"overall_ready":{"Mean":20,"StdDev":2,"CoefficientOfVariation":0.1,"ThresholdPercent":20}}}`)
+		cmd := NewCompareCommand(&pkg.PerfParams{})
+		_, err := testutil.ExecuteCommand(cmd, "--base", base, "--current", current, "--noise-profile", profile, "--output", t.TempDir())
+		assert.NilError(t, err)
+
+		cmd = NewCompareCommand(&pkg.PerfParams{})
+		_, err = testutil.ExecuteCommand(cmd, "--base", base, "--current", current, "--noise-profile", base)
+		assert.ErrorContains(t, err, "failed to parse noise profile")
+	})
+}
+
+func TestCompareMetric(t *testing.T) {
+	base := []float64{10, 11, 10, 12, 11, 10}
+	for _, test := range []string{TestMannWhitney, TestWelch} {
+		noise := compareMetric("revision_ready", base, []float64{10, 12, 11, 10, 11, 12}, test, 0.05, 0)
+		assert.Equal(t, VerdictNoChange, noise.Verdict, "test %s", test)
+		assert.Check(t, !noise.Significant)
+
+		regression := compareMetric("overall_ready", base, []float64{30, 31, 30, 32, 31, 30}, test, 0.05, 0)
+		assert.Equal(t, VerdictRegression, regression.Verdict, "test %s", test)
+		assert.Equal(t, 6, regression.CurrentCount)
+
+		improvement := compareMetric("overall_ready", []float64{30, 31, 30, 32, 31, 30}, base, test, 0.05, 0)
+		assert.Equal(t, VerdictImprovement, improvement.Verdict, "test %s", test)
+
+		withinNoise := compareMetric("overall_ready", base, []float64{30, 31, 30, 32, 31, 30}, test, 0.05, 250)
+		assert.Equal(t, VerdictNoChange, withinNoise.Verdict, "test %s", test)
+		assert.Check(t, withinNoise.Significant)
+		assert.Equal(t, 250.0, withinNoise.ThresholdPercent)
+	}
+}
+
+func TestReadSamples(t *testing.T) {
+	dir := t.TempDir()
+	samples, metrics, err := ReadSamples(writeTestFile(t, dir, "base.csv", baseCSV))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"revision_ready", "overall_ready"}, metrics)
+	assert.DeepEqual(t, []float64{20, 21, 20, 22, 21, 20}, samples["overall_ready"])
+
+	_, _, err = ReadSamples(writeTestFile(t, dir, "empty.csv", "svc_name,overall_ready\n"))
+	assert.ErrorContains(t, err, "no samples found")
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"knative.dev/kperf/pkg/command/utils"
+)
+
+const (
+	// ReferencesDir is the directory of the reference baselines shipped with kperf, every baseline is a
+	// measurement CSV file named by the Knative version and the environment it was measured in
+	ReferencesDir = "templates/references"
+
+	referenceFetchTimeout = 30 * time.Second
+)
+
+// References returns the names of the reference baselines shipped with kperf
+func References() []string {
+	files, err := utils.AssetDir(ReferencesDir)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, f := range files {
+		if strings.HasSuffix(f, ".csv") {
+			names = append(names, strings.TrimSuffix(f, ".csv"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadReference reads the samples of a reference baseline, either shipped with kperf and given by its name,
+// e.g. knative-1.14-gke-n2, or fetched from a http(s) URL
+func ReadReference(reference string) (map[string][]float64, []string, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(reference, "http://") || strings.HasPrefix(reference, "https://") {
+		data, err = fetchReference(reference)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		data, err = utils.Asset(path.Join(ReferencesDir, reference+".csv"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unsupported reference %q, expected a http(s) URL or one of the shipped references %v", reference, References())
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	// the run metadata above the header of a measurement CSV file is skipped
+	reader.Comment = '#'
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read reference %s: %s", reference, err)
+	}
+	return parseSamples(rows, reference)
+}
+
+func fetchReference(url string) ([]byte, error) {
+	client := http.Client{Timeout: referenceFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reference %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch reference %s: unexpected response status %s", url, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reference %s", err)
+	}
+	return data, nil
+}
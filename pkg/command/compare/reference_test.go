@@ -0,0 +1,59 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReadReference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/knative-1.14-kind.csv" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, baseCSV)
+	}))
+	defer server.Close()
+
+	t.Run("fetch reference from URL", func(t *testing.T) {
+		samples, metrics, err := ReadReference(server.URL + "/knative-1.14-kind.csv")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"revision_ready", "overall_ready"}, metrics)
+		assert.DeepEqual(t, []float64{20, 21, 20, 22, 21, 20}, samples["overall_ready"])
+	})
+
+	t.Run("fetch missing reference", func(t *testing.T) {
+		_, _, err := ReadReference(server.URL + "/missing.csv")
+		assert.ErrorContains(t, err, "unexpected response status 404 Not Found")
+	})
+
+	t.Run("unknown shipped reference", func(t *testing.T) {
+		_, _, err := ReadReference("knative-0.1-unknown")
+		assert.ErrorContains(t, err, "unsupported reference \"knative-0.1-unknown\"")
+	})
+}
+
+func TestReferences(t *testing.T) {
+	for _, name := range References() {
+		_, _, err := ReadReference(name)
+		assert.NilError(t, err, "shipped reference %s", name)
+	}
+}
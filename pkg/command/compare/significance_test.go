@@ -0,0 +1,52 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compare
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	p := MannWhitneyU([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10})
+	assert.Assert(t, math.Abs(p-0.012186) < 1e-4, "unexpected p-value %f", p)
+
+	p = MannWhitneyU([]float64{1, 2, 3}, []float64{1, 2, 3})
+	assert.Assert(t, p > 0.9, "unexpected p-value %f", p)
+
+	assert.Equal(t, 1.0, MannWhitneyU([]float64{5, 5, 5}, []float64{5, 5}))
+	assert.Equal(t, 1.0, MannWhitneyU(nil, []float64{1}))
+}
+
+func TestWelchTTest(t *testing.T) {
+	p := WelchTTest([]float64{1, 2, 3, 4, 5}, []float64{2, 3, 4, 5, 6})
+	assert.Assert(t, math.Abs(p-0.346594) < 1e-4, "unexpected p-value %f", p)
+
+	p = WelchTTest([]float64{10, 11, 10, 11, 10, 11}, []float64{20, 21, 20, 21, 20, 21})
+	assert.Assert(t, p < 1e-6, "unexpected p-value %f", p)
+
+	assert.Equal(t, 1.0, WelchTTest([]float64{5, 5}, []float64{5, 5}))
+	assert.Equal(t, 0.0, WelchTTest([]float64{5, 5}, []float64{6, 6}))
+	assert.Equal(t, 1.0, WelchTTest([]float64{5}, []float64{6, 6}))
+}
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	// I_x(1, 1) is the CDF of the uniform distribution
+	assert.Assert(t, math.Abs(regularizedIncompleteBeta(0.3, 1, 1)-0.3) < 1e-9)
+	assert.Equal(t, 0.0, regularizedIncompleteBeta(0, 2, 3))
+	assert.Equal(t, 1.0, regularizedIncompleteBeta(1, 2, 3))
+}
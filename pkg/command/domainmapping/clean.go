@@ -0,0 +1,104 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/generator"
+)
+
+func NewDomainMappingCleanCommand(p *pkg.PerfParams) *cobra.Command {
+	cleanArgs := pkg.DomainMappingCleanArgs{}
+	cleanCommand := &cobra.Command{
+		Use:   "clean",
+		Short: "clean Knative DomainMappings",
+		Long: `clean Knative DomainMapping workload together with the ClusterDomainClaims created by kperf
+
+For example:
+# To clean Knative DomainMapping workload
+kperf domainmapping clean --namespace-prefix testns / --namespace nsname
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CleanDomainMappings(p, cleanArgs)
+		},
+	}
+
+	cleanCommand.Flags().StringVarP(&cleanArgs.NamespacePrefix, "namespace-prefix", "", "", "Namespace prefix. The DomainMappings in namespaces with the prefix will be cleaned.")
+	cleanCommand.Flags().StringVarP(&cleanArgs.NamespaceRange, "namespace-range", "", "", "")
+	cleanCommand.Flags().StringVarP(&cleanArgs.Namespace, "namespace", "", "", "Namespace name. The DomainMappings in the namespace will be cleaned.")
+	cleanCommand.Flags().StringVarP(&cleanArgs.DomainPrefix, "domain-prefix", "", DefaultDomainPrefix, "Domain prefix. The DomainMappings with the prefix will be cleaned.")
+	cleanCommand.Flags().IntVarP(&cleanArgs.Concurrency, "concurrency", "c", 10, "Number of multiple DomainMappings to clean at a time")
+
+	return cleanCommand
+}
+
+// CleanDomainMappings used to clean Knative DomainMapping workload and the ClusterDomainClaims created for it
+func CleanDomainMappings(params *pkg.PerfParams, inputs pkg.DomainMappingCleanArgs) error {
+	ctx := context.Background()
+	nsNameList, err := service.GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return err
+	}
+	networkingClient, err := params.NewNetworkingClient()
+	if err != nil {
+		return err
+	}
+
+	matchedNsNameList := [][2]string{}
+	cleanDomainMapping := func(namespace, name string) {
+		fmt.Printf("Delete DomainMapping %s in namespace %s\n", name, namespace)
+		if err := dynamicClient.Resource(DomainMappingGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			fmt.Printf("Failed to delete DomainMapping %s in namespace %s\n", name, namespace)
+		}
+		claim, err := networkingClient.ClusterDomainClaims().Get(ctx, name, metav1.GetOptions{})
+		if err != nil || claim.Labels[domainClaimLabel] != "true" || claim.Spec.Namespace != namespace {
+			return
+		}
+		fmt.Printf("Delete ClusterDomainClaim %s\n", name)
+		if err := networkingClient.ClusterDomainClaims().Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			fmt.Printf("Failed to delete ClusterDomainClaim %s\n", name)
+		}
+	}
+	for _, ns := range nsNameList {
+		dmList, err := dynamicClient.Resource(DomainMappingGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for _, dm := range dmList.Items {
+				if strings.HasPrefix(dm.GetName(), inputs.DomainPrefix+"-") {
+					matchedNsNameList = append(matchedNsNameList, [2]string{ns, dm.GetName()})
+				}
+			}
+		}
+	}
+	if len(matchedNsNameList) > 0 {
+		generator.NewBatchCleaner(matchedNsNameList, inputs.Concurrency, cleanDomainMapping).Clean()
+	} else {
+		fmt.Println("No domainmapping found for cleaning")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestCleanDomainMappingsFunc(t *testing.T) {
+	p, fakeDynamic := newTestPerfParams([]string{"test-kperf-1"},
+		newTestDomainMapping("test-kperf-1", "kperf-0.example.com", nil),
+		newTestDomainMapping("test-kperf-1", "other-0.example.com", nil))
+	networkingClient, _ := p.NewNetworkingClient()
+	_, err := networkingClient.ClusterDomainClaims().Create(context.TODO(), &netv1alpha1.ClusterDomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "kperf-0.example.com", Labels: map[string]string{domainClaimLabel: "true"}},
+		Spec:       netv1alpha1.ClusterDomainClaimSpec{Namespace: "test-kperf-1"},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	err = CleanDomainMappings(p, pkg.DomainMappingCleanArgs{Namespace: "test-kperf-1", DomainPrefix: "kperf", Concurrency: 1})
+	assert.NilError(t, err)
+
+	dms, err := fakeDynamic.Resource(DomainMappingGVR).Namespace("test-kperf-1").List(context.TODO(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(dms.Items))
+	assert.Equal(t, "other-0.example.com", dms.Items[0].GetName())
+
+	_, err = networkingClient.ClusterDomainClaims().Get(context.TODO(), "kperf-0.example.com", metav1.GetOptions{})
+	assert.Check(t, apierrors.IsNotFound(err), "expected the ClusterDomainClaim to be deleted, got %v", err)
+}
+
+func TestNewDomainMappingCleanCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for domainmapping clean", func(t *testing.T) {
+		p, _ := newTestPerfParams(nil)
+
+		_, err := testutil.ExecuteCommand(NewDomainMappingCleanCommand(p))
+		assert.ErrorContains(t, err, "both namespace and namespace-prefix are empty")
+
+		_, err = testutil.ExecuteCommand(NewDomainMappingCleanCommand(p), "--namespace-prefix", "test-kperf", "--namespace-range", "1")
+		assert.ErrorContains(t, err, "expected range like 1,500, given 1")
+	})
+
+	t.Run("clean domainmappings as expected", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"test-kperf-1"})
+
+		_, err := testutil.ExecuteCommand(NewDomainMappingCleanCommand(p), "--namespace", "test-kperf-1")
+		assert.NilError(t, err)
+	})
+}
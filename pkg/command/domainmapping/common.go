@@ -0,0 +1,46 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+)
+
+const (
+	DefaultDomainPrefix = "kperf"
+	DefaultDomain       = "example.com"
+
+	// domainClaimLabel marks the ClusterDomainClaims created by kperf
+	domainClaimLabel = "kperf.knative.dev/domainmapping"
+)
+
+var DomainMappingGVR = v1beta1.SchemeGroupVersion.WithResource("domainmappings")
+
+// domainMappingName returns the name of the DomainMapping with the index, which is the mapped domain
+func domainMappingName(prefix string, index int, domain string) string {
+	return fmt.Sprintf("%s-%d.%s", prefix, index, domain)
+}
+
+func toDomainMapping(obj *unstructured.Unstructured) (*v1beta1.DomainMapping, error) {
+	dm := &v1beta1.DomainMapping{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, dm); err != nil {
+		return nil, fmt.Errorf("failed to convert DomainMapping %s: %w", obj.GetName(), err)
+	}
+	return dm, nil
+}
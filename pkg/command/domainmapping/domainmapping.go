@@ -0,0 +1,37 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewDomainMappingCmd(p *pkg.PerfParams) *cobra.Command {
+	var domainMappingCmd = &cobra.Command{
+		Use:   "domainmapping",
+		Short: "Knative DomainMapping load test",
+		Long: `Knative Serving DomainMapping load test and measurement. For example:
+
+kperf domainmapping generate -n 10 -i 1 -b 5 --namespace ns --svc-prefix ksvc - to map 10 domains to the Knative Services with prefix ksvc
+kperf domainmapping measure --namespace ns - to measure the DomainMappings`,
+	}
+	domainMappingCmd.AddCommand(NewDomainMappingGenerateCommand(p))
+	domainMappingCmd.AddCommand(NewDomainMappingMeasureCommand(p))
+	domainMappingCmd.AddCommand(NewDomainMappingCleanCommand(p))
+
+	domainMappingCmd.InitDefaultHelpCmd()
+	return domainMappingCmd
+}
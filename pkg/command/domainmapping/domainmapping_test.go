@@ -0,0 +1,106 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	networkingv1alpha1fake "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestNewDomainMappingCmd(t *testing.T) {
+	cmd := NewDomainMappingCmd(nil)
+	assert.Check(t, cmd.HasSubCommands(), "cmd domainmapping should have subcommands")
+
+	_, _, err := cmd.Find([]string{"generate"})
+	assert.NilError(t, err, "domainmapping command should have generate subcommand")
+
+	_, _, err = cmd.Find([]string{"measure"})
+	assert.NilError(t, err, "domainmapping command should have measure subcommand")
+
+	_, _, err = cmd.Find([]string{"clean"})
+	assert.NilError(t, err, "domainmapping command should have clean subcommand")
+}
+
+func TestDomainMappingName(t *testing.T) {
+	assert.Equal(t, "kperf-3.example.com", domainMappingName("kperf", 3, "example.com"))
+}
+
+// newTestPerfParams returns PerfParams backed by fake clients holding the namespaces and the given
+// DomainMapping objects
+func newTestPerfParams(namespaces []string, objects ...runtime.Object) (*pkg.PerfParams, *dynamicfake.FakeDynamicClient) {
+	nsObjects := []runtime.Object{}
+	for _, ns := range namespaces {
+		nsObjects = append(nsObjects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	}
+	client := k8sfake.NewSimpleClientset(nsObjects...)
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeNetworking := &networkingv1alpha1fake.FakeNetworkingV1alpha1{Fake: &client.Fake}
+	fakeDynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		DomainMappingGVR: "DomainMappingList",
+	}, objects...)
+
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return fakeNetworking, nil
+		},
+		NewDynamicClient: func() (dynamic.Interface, error) {
+			return fakeDynamic, nil
+		},
+	}, fakeDynamic
+}
+
+// newTestDomainMapping returns a DomainMapping created at 2022-01-01T00:00:00Z with the conditions which became
+// true after the given number of seconds
+func newTestDomainMapping(ns, name string, conditions map[apis.ConditionType]int) *unstructured.Unstructured {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dm := &v1beta1.DomainMapping{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "DomainMapping"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, CreationTimestamp: metav1.NewTime(created)},
+	}
+	for conditionType, seconds := range conditions {
+		dm.Status.Conditions = append(dm.Status.Conditions, apis.Condition{
+			Type:               conditionType,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Duration(seconds) * time.Second))},
+		})
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dm)
+	if err != nil {
+		panic(err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/generator"
+)
+
+func NewDomainMappingGenerateCommand(p *pkg.PerfParams) *cobra.Command {
+	generateArgs := pkg.DomainMappingGenerateArgs{}
+
+	generateCommand := &cobra.Command{
+		Use:   "generate",
+		Short: "generate Knative DomainMappings",
+		Long: `generate Knative DomainMapping workload
+
+The DomainMappings are named <domain-prefix>-<index>.<domain> and target the Knative Services with the service
+prefix in their namespace in turn.
+
+For example:
+# To generate 100 DomainMappings for the Knative Services with prefix ksvc
+kperf domainmapping generate -n 100 --interval 10 --batch 10 --svc-prefix ksvc (--namespace-prefix testns/ --namespace nsname)
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			if flags.Changed("namespace-prefix") && flags.Changed("namespace") {
+				return errors.New("expected either namespace with prefix & range or only namespace name")
+			}
+			return pkg.ValidateRunID(generateArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GenerateDomainMappings(p, generateArgs)
+		},
+	}
+	generateCommand.Flags().IntVarP(&generateArgs.Number, "number", "n", 0, "Total number of DomainMappings to be created")
+	generateCommand.MarkFlagRequired("number")
+	generateCommand.Flags().IntVarP(&generateArgs.Interval, "interval", "i", 0, "Interval for each batch generation")
+	generateCommand.MarkFlagRequired("interval")
+	generateCommand.Flags().IntVarP(&generateArgs.Batch, "batch", "b", 0, "Number of DomainMappings each time to be created")
+	generateCommand.MarkFlagRequired("batch")
+	generateCommand.Flags().IntVarP(&generateArgs.Concurrency, "concurrency", "c", 10, "Number of multiple DomainMappings to make at a time")
+
+	generateCommand.Flags().StringVarP(&generateArgs.NamespacePrefix, "namespace-prefix", "", "", "Namespace prefix. The DomainMappings will be created in the namespaces with the prefix")
+	generateCommand.Flags().StringVarP(&generateArgs.NamespaceRange, "namespace-range", "", "", "")
+	generateCommand.Flags().StringVarP(&generateArgs.Namespace, "namespace", "", "", "Namespace name. The DomainMappings will be created in the namespace")
+
+	generateCommand.Flags().StringVarP(&generateArgs.SvcPrefix, "svc-prefix", "", "", "Knative Service name prefix. The DomainMappings target the Knative Services with the prefix in their namespace")
+	generateCommand.MarkFlagRequired("svc-prefix")
+	generateCommand.Flags().StringVarP(&generateArgs.DomainPrefix, "domain-prefix", "", DefaultDomainPrefix, "Domain prefix. The DomainMappings will be <domain-prefix>-0.<domain>,<domain-prefix>-1.<domain> and etc.")
+	generateCommand.Flags().StringVarP(&generateArgs.Domain, "domain", "", DefaultDomain, "Domain the mapped domains are subdomains of")
+	generateCommand.Flags().BoolVarP(&generateArgs.DomainClaims, "domain-claims", "", false, "Create a ClusterDomainClaim for each DomainMapping, required unless autocreate-cluster-domain-claims is enabled in the config-network ConfigMap")
+	generateCommand.Flags().DurationVarP(&generateArgs.TTL, "ttl", "", 0, "Time to live of the generated DomainMappings and ClusterDomainClaims, after which 'kperf clean expired' deletes them, 0 to never expire")
+	generateCommand.Flags().StringVarP(&generateArgs.RunID, "run-id", "", "", "ID of the run the generated DomainMappings and ClusterDomainClaims are labeled with as "+pkg.RunIDLabel+". A new ID is generated by default")
+	return generateCommand
+}
+
+// GenerateDomainMappings used to generate Knative DomainMapping workload
+func GenerateDomainMappings(params *pkg.PerfParams, inputs pkg.DomainMappingGenerateArgs) error {
+	ctx := context.Background()
+	nsNameList, err := service.GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	services := map[string][]string{}
+	for _, ns := range nsNameList {
+		svcList, err := ksvcClient.Services(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list Knative Services in namespace %s: %w", ns, err)
+		}
+		for _, svc := range svcList.Items {
+			if strings.HasPrefix(svc.Name, inputs.SvcPrefix) {
+				services[ns] = append(services[ns], svc.Name)
+			}
+		}
+		if len(services[ns]) == 0 {
+			return fmt.Errorf("no Knative Service with prefix %s found in namespace %s", inputs.SvcPrefix, ns)
+		}
+		sort.Strings(services[ns])
+	}
+
+	if inputs.RunID == "" {
+		inputs.RunID = pkg.NewRunID(time.Now())
+	}
+	fmt.Printf("Run ID %s\n", inputs.RunID)
+
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return err
+	}
+	var networkingClient networkingv1alpha1.NetworkingV1alpha1Interface
+	if inputs.DomainClaims {
+		networkingClient, err = params.NewNetworkingClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	createDomainMappingFunc := func(ns string, index int) (string, string) {
+		name := domainMappingName(inputs.DomainPrefix, index, inputs.Domain)
+		// the indexes are distributed over the namespaces, so every len(nsNameList)th index is in ns
+		target := services[ns][(index/len(nsNameList))%len(services[ns])]
+		if networkingClient != nil {
+			if err := createDomainClaim(networkingClient, ns, name, inputs.TTL, inputs.RunID); err != nil {
+				fmt.Printf("failed to create ClusterDomainClaim %s : %s\n", name, err)
+				return ns, name
+			}
+		}
+		fmt.Printf("Creating DomainMapping %s for ksvc %s in namespace %s\n", name, target, ns)
+		if err := createDomainMapping(dynamicClient, ns, name, target, inputs.TTL, inputs.RunID); err != nil {
+			fmt.Printf("failed to create DomainMapping %s in namespace %s : %s\n", name, ns, err)
+		}
+		return ns, name
+	}
+	generator.NewBatchGenerator(time.Duration(inputs.Interval)*time.Second, inputs.Number, inputs.Batch, inputs.Concurrency, nsNameList, createDomainMappingFunc, func(ns, name string) error { return nil }).
+		WithClock(params.Clock).Generate()
+	return nil
+}
+
+func createDomainMapping(client dynamic.Interface, ns, name, target string, ttl time.Duration, runID string) error {
+	dm := &v1beta1.DomainMapping{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.SchemeGroupVersion.String(),
+			Kind:       "DomainMapping",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    pkg.GeneratedLabels(runID, ttl, time.Now()),
+		},
+		Spec: v1beta1.DomainMappingSpec{
+			Ref: duckv1.KReference{
+				APIVersion: servingv1.SchemeGroupVersion.String(),
+				Kind:       "Service",
+				Name:       target,
+				Namespace:  ns,
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(dm)
+	if err != nil {
+		return err
+	}
+	_, err = client.Resource(DomainMappingGVR).Namespace(ns).Create(context.TODO(), &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+	return err
+}
+
+// createDomainClaim claims the domain for the namespace, an existing claim of the domain for the same namespace is kept
+func createDomainClaim(client networkingv1alpha1.NetworkingV1alpha1Interface, ns, name string, ttl time.Duration, runID string) error {
+	labels := map[string]string{domainClaimLabel: "true"}
+	for k, v := range pkg.GeneratedLabels(runID, ttl, time.Now()) {
+		labels[k] = v
+	}
+	claim := &netv1alpha1.ClusterDomainClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: netv1alpha1.ClusterDomainClaimSpec{Namespace: ns},
+	}
+	_, err := client.ClusterDomainClaims().Create(context.TODO(), claim, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := client.ClusterDomainClaims().Get(context.TODO(), name, metav1.GetOptions{})
+		if getErr == nil && existing.Spec.Namespace == ns {
+			return nil
+		}
+	}
+	return err
+}
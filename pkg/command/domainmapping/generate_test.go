@@ -0,0 +1,87 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewDomainMappingGenerateCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for domainmapping generate", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"test-kperf-1"})
+		listServices(p, "other-0")
+
+		_, err := testutil.ExecuteCommand(NewDomainMappingGenerateCommand(p))
+		assert.ErrorContains(t, err, "required flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewDomainMappingGenerateCommand(p), "-n", "1", "-i", "1", "-b", "1", "--svc-prefix", "ksvc", "--namespace-prefix", "test-kperf", "--namespace", "test-kperf-1")
+		assert.ErrorContains(t, err, "expected either namespace with prefix & range or only namespace name")
+
+		_, err = testutil.ExecuteCommand(NewDomainMappingGenerateCommand(p), "-n", "1", "-i", "1", "-b", "1", "--svc-prefix", "ksvc", "--namespace", "test-kperf-1")
+		assert.ErrorContains(t, err, "no Knative Service with prefix ksvc found in namespace test-kperf-1")
+	})
+
+	t.Run("generate domainmappings as expected", func(t *testing.T) {
+		p, fakeDynamic := newTestPerfParams([]string{"test-kperf-1"})
+		listServices(p, "ksvc-1", "ksvc-0", "other-0")
+
+		_, err := testutil.ExecuteCommand(NewDomainMappingGenerateCommand(p), "-n", "3", "-i", "1", "-b", "3", "--svc-prefix", "ksvc", "--namespace", "test-kperf-1", "--domain-claims")
+		assert.NilError(t, err)
+
+		dms, err := fakeDynamic.Resource(DomainMappingGVR).Namespace("test-kperf-1").List(context.TODO(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 3, len(dms.Items))
+		targets := map[string]string{}
+		for i := range dms.Items {
+			dm, err := toDomainMapping(&dms.Items[i])
+			assert.NilError(t, err)
+			targets[dm.Name] = dm.Spec.Ref.Name
+		}
+		assert.DeepEqual(t, map[string]string{
+			"kperf-0.example.com": "ksvc-0",
+			"kperf-1.example.com": "ksvc-1",
+			"kperf-2.example.com": "ksvc-0",
+		}, targets)
+
+		networkingClient, _ := p.NewNetworkingClient()
+		claim, err := networkingClient.ClusterDomainClaims().Get(context.TODO(), "kperf-1.example.com", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "test-kperf-1", claim.Spec.Namespace)
+		assert.Equal(t, "true", claim.Labels[domainClaimLabel])
+	})
+}
+
+// listServices makes the fake serving client list Knative Services with the names in every namespace
+func listServices(p *pkg.PerfParams, names ...string) {
+	ksvcClient, _ := p.NewServingClient()
+	ksvcClient.(*servingv1fake.FakeServingV1).PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		list := &servingv1.ServiceList{}
+		for _, name := range names {
+			list.Items = append(list.Items, servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: action.GetNamespace()}})
+		}
+		return true, list, nil
+	})
+}
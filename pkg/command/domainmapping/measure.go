@@ -0,0 +1,260 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
+)
+
+const DomainMappingOutputFilename = "domainmapping_creation_time"
+
+func NewDomainMappingMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	measureArgs := pkg.DomainMappingMeasureArgs{}
+	measureCommand := &cobra.Command{
+		Use:   "measure",
+		Short: "Measure Knative DomainMappings",
+		Long: `Measure Knative DomainMapping creation time
+
+The time until a DomainMapping is ready is broken down into the time until its domain is claimed, its reference
+resolved, its certificate provisioned and its ingress ready.
+
+For example:
+# To measure the DomainMappings with prefix kperf in namespace ns
+kperf domainmapping measure --domain-prefix kperf --namespace ns
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'domainmapping measure' requires flag(s)")
+			}
+			return pkg.ValidateRunID(measureArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureDomainMappings(p, measureArgs)
+		},
+	}
+
+	measureCommand.Flags().StringVarP(&measureArgs.Namespace, "namespace", "", "", "DomainMapping namespace")
+	measureCommand.Flags().StringVarP(&measureArgs.DomainPrefix, "domain-prefix", "", DefaultDomainPrefix, "DomainMapping domain prefix")
+	measureCommand.Flags().BoolVarP(&measureArgs.Verbose, "verbose", "v", false, "DomainMapping verbose result")
+	measureCommand.Flags().StringVarP(&measureArgs.NamespaceRange, "namespace-range", "", "", "DomainMapping namespace range")
+	measureCommand.Flags().StringVarP(&measureArgs.NamespacePrefix, "namespace-prefix", "", "", "DomainMapping namespace prefix")
+	measureCommand.Flags().StringVarP(&measureArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(measureCommand.Flags(), &measureArgs.OutputFormats)
+	measureCommand.Flags().StringVarP(&measureArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	return measureCommand
+}
+
+// MeasureDomainMappings used to measure the time until DomainMappings are ready and their certificates provisioned
+func MeasureDomainMappings(params *pkg.PerfParams, inputs pkg.DomainMappingMeasureArgs) error {
+	ctx := context.Background()
+	nsNameList, err := service.GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client %s\n", err)
+	}
+
+	domainMappings := []*v1beta1.DomainMapping{}
+	for _, ns := range nsNameList {
+		dmList, err := dynamicClient.Resource(DomainMappingGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list domainmappings under namespace %s error:%v", ns, err)
+		}
+		for i := range dmList.Items {
+			if !strings.HasPrefix(dmList.Items[i].GetName(), inputs.DomainPrefix+"-") {
+				continue
+			}
+			dm, err := toDomainMapping(&dmList.Items[i])
+			if err != nil {
+				return err
+			}
+			domainMappings = append(domainMappings, dm)
+		}
+	}
+	if len(domainMappings) == 0 {
+		return errors.New("no domainmapping found to measure")
+	}
+
+	result := pkg.DomainMappingMeasureResult{}
+	rows := make([][]string, 0)
+	rawRows := make([][]string, 0)
+	for _, dm := range domainMappings {
+		created := dm.CreationTimestamp.Time
+		ready, isReady := conditionTime(dm, v1beta1.DomainMappingConditionReady)
+		domainClaimed, claimed := conditionTime(dm, v1beta1.DomainMappingConditionDomainClaimed)
+		referenceResolved, resolved := conditionTime(dm, v1beta1.DomainMappingConditionReferenceResolved)
+		certificateProvisioned, provisioned := conditionTime(dm, v1beta1.DomainMappingConditionCertificateProvisioned)
+		ingressReady, ingress := conditionTime(dm, v1beta1.DomainMappingConditionIngressReady)
+		if !isReady || !claimed || !resolved || !provisioned || !ingress {
+			fmt.Printf("domainmapping %s/%s not ready and skip measuring\n", dm.Namespace, dm.Name)
+			result.DomainMapping.NotReadyCount++
+			continue
+		}
+		readyDuration := ready.Sub(created)
+		domainClaimedDuration := domainClaimed.Sub(created)
+		referenceResolvedDuration := referenceResolved.Sub(created)
+		certificateProvisionedDuration := certificateProvisioned.Sub(created)
+		ingressReadyDuration := ingressReady.Sub(created)
+
+		result.DomainMapping.ReadyCount++
+		result.Sums.ReadySum += readyDuration.Seconds()
+		result.Sums.DomainClaimedSum += domainClaimedDuration.Seconds()
+		result.Sums.ReferenceResolvedSum += referenceResolvedDuration.Seconds()
+		result.Sums.CertificateProvisionedSum += certificateProvisionedDuration.Seconds()
+		result.Sums.IngressReadySum += ingressReadyDuration.Seconds()
+		result.ReadyTime = append(result.ReadyTime, readyDuration.Seconds())
+
+		rows = append(rows, []string{dm.Name, dm.Namespace,
+			fmt.Sprintf("%d", int(domainClaimedDuration.Seconds())),
+			fmt.Sprintf("%d", int(referenceResolvedDuration.Seconds())),
+			fmt.Sprintf("%d", int(certificateProvisionedDuration.Seconds())),
+			fmt.Sprintf("%d", int(ingressReadyDuration.Seconds())),
+			fmt.Sprintf("%d", int(readyDuration.Seconds())),
+		})
+		rawRows = append(rawRows, []string{dm.Name, dm.Namespace, dm.Spec.Ref.Name,
+			dm.CreationTimestamp.String(),
+			domainClaimed.String(),
+			referenceResolved.String(),
+			certificateProvisioned.String(),
+			ingressReady.String(),
+			ready.String(),
+		})
+
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] DomainMapping %s: DomainMapping Ready Duration is %s/%fs\n", dm.Name, readyDuration, readyDuration.Seconds())
+			fmt.Printf("[Verbose] DomainMapping %s: - Domain Claimed Duration is %s/%fs\n", dm.Name, domainClaimedDuration, domainClaimedDuration.Seconds())
+			fmt.Printf("[Verbose] DomainMapping %s: - Reference Resolved Duration is %s/%fs\n", dm.Name, referenceResolvedDuration, referenceResolvedDuration.Seconds())
+			fmt.Printf("[Verbose] DomainMapping %s: - Certificate Provisioned Duration is %s/%fs\n", dm.Name, certificateProvisionedDuration, certificateProvisionedDuration.Seconds())
+			fmt.Printf("[Verbose] DomainMapping %s: - Ingress Ready Duration is %s/%fs\n", dm.Name, ingressReadyDuration, ingressReadyDuration.Seconds())
+		}
+	}
+
+	sortRows(rows)
+	sortRows(rawRows)
+	rows = append([][]string{{"domainmapping_name", "domainmapping_namespace", "domain_claimed", "reference_resolved",
+		"certificate_provisioned", "ingress_ready", "domainmapping_ready"}}, rows...)
+	rawRows = append([][]string{{"domainmapping_name", "domainmapping_namespace", "svc_name",
+		"domainmapping_created",
+		"domain_claimed",
+		"reference_resolved",
+		"certificate_provisioned",
+		"ingress_ready",
+		"domainmapping_ready"}}, rawRows...)
+
+	result.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Basic Information:\n")
+	fmt.Printf("  - Knative Versions:\n")
+	fmt.Printf("    Serving: %v\n", result.KnativeInfo.ServingVersion)
+	fmt.Printf("  - Ingress Controller: %s\n", result.KnativeInfo.IngressController)
+	fmt.Printf("DomainMappings Total: %d | Ready: %d NotReady: %d\n", result.DomainMapping.ReadyCount+result.DomainMapping.NotReadyCount,
+		result.DomainMapping.ReadyCount, result.DomainMapping.NotReadyCount)
+	if result.DomainMapping.ReadyCount == 0 {
+		return nil
+	}
+
+	count := float64(result.DomainMapping.ReadyCount)
+	result.Result.AverageDomainClaimed = result.Sums.DomainClaimedSum / count
+	result.Result.AverageReferenceResolved = result.Sums.ReferenceResolvedSum / count
+	result.Result.AverageCertificateProvisioned = result.Sums.CertificateProvisionedSum / count
+	result.Result.AverageIngressReady = result.Sums.IngressReadySum / count
+	fmt.Printf("- Domain Claimed Duration:\n")
+	fmt.Printf("  Total: %fs\n", result.Sums.DomainClaimedSum)
+	fmt.Printf("  Average: %fs\n", result.Result.AverageDomainClaimed)
+	fmt.Printf("- Reference Resolved Duration:\n")
+	fmt.Printf("  Total: %fs\n", result.Sums.ReferenceResolvedSum)
+	fmt.Printf("  Average: %fs\n", result.Result.AverageReferenceResolved)
+	fmt.Printf("- Certificate Provisioned Duration:\n")
+	fmt.Printf("  Total: %fs\n", result.Sums.CertificateProvisionedSum)
+	fmt.Printf("  Average: %fs\n", result.Result.AverageCertificateProvisioned)
+	fmt.Printf("- Ingress Ready Duration:\n")
+	fmt.Printf("  Total: %fs\n", result.Sums.IngressReadySum)
+	fmt.Printf("  Average: %fs\n", result.Result.AverageIngressReady)
+
+	fmt.Printf("\n-----------------------------\n")
+	fmt.Printf("Overall DomainMapping Ready Measurement:\n")
+	result.Result.OverallTotal = result.Sums.ReadySum
+	fmt.Printf("Total: %fs\n", result.Result.OverallTotal)
+	result.Result.OverallAverage = result.Sums.ReadySum / count
+	fmt.Printf("Average: %fs\n", result.Result.OverallAverage)
+	result.Result.OverallMedian, _ = stats.Median(result.ReadyTime)
+	fmt.Printf("Median: %fs\n", result.Result.OverallMedian)
+	result.Result.OverallMin, _ = stats.Min(result.ReadyTime)
+	fmt.Printf("Min: %fs\n", result.Result.OverallMin)
+	result.Result.OverallMax, _ = stats.Max(result.ReadyTime)
+	fmt.Printf("Max: %fs\n", result.Result.OverallMax)
+	result.Result.P50, _ = stats.Percentile(result.ReadyTime, 50)
+	fmt.Printf("Percentile50: %fs\n", result.Result.P50)
+	result.Result.P90, _ = stats.Percentile(result.ReadyTime, 90)
+	fmt.Printf("Percentile90: %fs\n", result.Result.P90)
+	result.Result.P95, _ = stats.Percentile(result.ReadyTime, 95)
+	fmt.Printf("Percentile95: %fs\n", result.Result.P95)
+	result.Result.P98, _ = stats.Percentile(result.ReadyTime, 98)
+	fmt.Printf("Percentile98: %fs\n", result.Result.P98)
+	result.Result.P99, _ = stats.Percentile(result.ReadyTime, 99)
+	fmt.Printf("Percentile99: %fs\n", result.Result.P99)
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    DomainMappingOutputFilename,
+		Rows:    rows,
+		RawRows: rawRows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// conditionTime returns the time the condition of the DomainMapping became true
+func conditionTime(dm *v1beta1.DomainMapping, conditionType apis.ConditionType) (time.Time, bool) {
+	condition := dm.Status.GetCondition(conditionType)
+	if condition == nil || !condition.IsTrue() {
+		return time.Time{}, false
+	}
+	return condition.LastTransitionTime.Inner.Time, true
+}
+
+func sortRows(rows [][]string) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i][1] != rows[j][1] {
+			return rows[i][1] < rows[j][1]
+		}
+		return rows[i][0] < rows[j][0]
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domainmapping
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving/v1beta1"
+
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewDomainMappingMeasureCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for domainmapping measure", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"test-kperf-1"})
+
+		_, err := testutil.ExecuteCommand(NewDomainMappingMeasureCommand(p))
+		assert.ErrorContains(t, err, "'domainmapping measure' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewDomainMappingMeasureCommand(p), "--namespace-prefix", "test-kperf", "--namespace-range", "1")
+		assert.ErrorContains(t, err, "expected range like 1,500, given 1")
+
+		_, err = testutil.ExecuteCommand(NewDomainMappingMeasureCommand(p), "--namespace", "test-kperf-1")
+		assert.ErrorContains(t, err, "no domainmapping found to measure")
+	})
+
+	t.Run("measure domainmappings as expected", func(t *testing.T) {
+		ready := newTestDomainMapping("test-kperf-1", "kperf-0.example.com", map[apis.ConditionType]int{
+			v1beta1.DomainMappingConditionDomainClaimed:          1,
+			v1beta1.DomainMappingConditionReferenceResolved:      1,
+			v1beta1.DomainMappingConditionCertificateProvisioned: 3,
+			v1beta1.DomainMappingConditionIngressReady:           4,
+			v1beta1.DomainMappingConditionReady:                  4,
+		})
+		notReady := newTestDomainMapping("test-kperf-1", "kperf-1.example.com", map[apis.ConditionType]int{
+			v1beta1.DomainMappingConditionDomainClaimed: 1,
+		})
+		p, _ := newTestPerfParams([]string{"test-kperf-1"}, ready, notReady)
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewDomainMappingMeasureCommand(p), "--namespace", "test-kperf-1", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+
+		for _, suffix := range []string{"raw_" + DomainMappingOutputFilename + ".csv", DomainMappingOutputFilename + ".csv", DomainMappingOutputFilename + ".json", DomainMappingOutputFilename + ".html"} {
+			matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+suffix))
+			assert.NilError(t, err)
+			assert.Check(t, len(matches) > 0, "expected output file *_%s", suffix)
+		}
+	})
+}
+
+func TestConditionTime(t *testing.T) {
+	dm, err := toDomainMapping(newTestDomainMapping("ns", "kperf-0.example.com", map[apis.ConditionType]int{
+		v1beta1.DomainMappingConditionCertificateProvisioned: 3,
+	}))
+	assert.NilError(t, err)
+
+	provisioned, ok := conditionTime(dm, v1beta1.DomainMappingConditionCertificateProvisioned)
+	assert.Check(t, ok)
+	assert.Equal(t, 3.0, provisioned.Sub(dm.CreationTimestamp.Time).Seconds())
+
+	_, ok = conditionTime(dm, v1beta1.DomainMappingConditionReady)
+	assert.Check(t, !ok, "missing condition should not be returned")
+}
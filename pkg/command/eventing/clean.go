@@ -0,0 +1,123 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/generator"
+)
+
+func NewEventingCleanCommand(p *pkg.PerfParams) *cobra.Command {
+	cleanArgs := pkg.EventingCleanArgs{}
+	cleanCommand := &cobra.Command{
+		Use:   "clean",
+		Short: "clean Knative Eventing Brokers and Triggers",
+		Long: `clean Knative Eventing Broker and Trigger workload
+
+For example:
+# To clean Knative Eventing workload
+kperf eventing clean --namespace-prefix testns / --namespace nsname
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CleanBrokers(p, cleanArgs)
+		},
+	}
+
+	cleanCommand.Flags().StringVarP(&cleanArgs.NamespacePrefix, "namespace-prefix", "", "", "Namespace prefix. The Brokers in namespaces with the prefix will be cleaned.")
+	cleanCommand.Flags().StringVarP(&cleanArgs.NamespaceRange, "namespace-range", "", "", "")
+	cleanCommand.Flags().StringVarP(&cleanArgs.Namespace, "namespace", "", "", "Namespace name. The Brokers in the namespace will be cleaned.")
+	cleanCommand.Flags().StringVarP(&cleanArgs.BrokerPrefix, "broker-prefix", "", "broker", "Broker name prefix. The Brokers with the prefix and their Triggers will be cleaned.")
+	cleanCommand.Flags().IntVarP(&cleanArgs.Concurrency, "concurrency", "c", 10, "Number of multiple Brokers to clean at a time")
+
+	return cleanCommand
+}
+
+// CleanBrokers used to clean Knative Eventing Broker and Trigger workload together with the
+// subscriber Knative Services created by kperf
+func CleanBrokers(params *pkg.PerfParams, inputs pkg.EventingCleanArgs) error {
+	ctx := context.Background()
+	nsNameList, err := service.GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return err
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+
+	matchedNsNameList := [][2]string{}
+	cleanBroker := func(namespace, name string) {
+		triggerList, err := dynamicClient.Resource(TriggerGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for i := range triggerList.Items {
+				trigger := &triggerList.Items[i]
+				if triggerBroker(trigger) != name {
+					continue
+				}
+				fmt.Printf("Delete Trigger %s in namespace %s\n", trigger.GetName(), namespace)
+				if err := dynamicClient.Resource(TriggerGVR).Namespace(namespace).Delete(ctx, trigger.GetName(), metav1.DeleteOptions{}); err != nil {
+					fmt.Printf("Failed to delete Trigger %s in namespace %s\n", trigger.GetName(), namespace)
+				}
+			}
+		}
+		fmt.Printf("Delete Broker %s in namespace %s\n", name, namespace)
+		if err := dynamicClient.Resource(BrokerGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			fmt.Printf("Failed to delete Broker %s in namespace %s\n", name, namespace)
+		}
+	}
+	for _, ns := range nsNameList {
+		brokerList, err := dynamicClient.Resource(BrokerGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			for _, b := range brokerList.Items {
+				if strings.HasPrefix(b.GetName(), inputs.BrokerPrefix) {
+					matchedNsNameList = append(matchedNsNameList, [2]string{ns, b.GetName()})
+				}
+			}
+		}
+	}
+	if len(matchedNsNameList) > 0 {
+		generator.NewBatchCleaner(matchedNsNameList, inputs.Concurrency, cleanBroker).Clean()
+	} else {
+		fmt.Println("No broker found for cleaning")
+	}
+
+	for _, ns := range nsNameList {
+		svcList, err := ksvcClient.Services(ns).List(ctx, metav1.ListOptions{LabelSelector: subscriberLabel + "=true"})
+		if err != nil {
+			continue
+		}
+		for _, svc := range svcList.Items {
+			fmt.Printf("Delete subscriber ksvc %s in namespace %s\n", svc.Name, ns)
+			if err := ksvcClient.Services(ns).Delete(ctx, svc.Name, metav1.DeleteOptions{}); err != nil {
+				fmt.Printf("Failed to delete subscriber ksvc %s in namespace %s\n", svc.Name, ns)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestCleanBrokersFunc(t *testing.T) {
+	p, fakeDynamic := newTestPerfParams([]string{"test-kperf-1"},
+		newTestBroker("test-kperf-1", "broker-0"),
+		newTestTrigger("test-kperf-1", "broker-0-trigger-0", "broker-0"),
+		newTestBroker("test-kperf-1", "other-0"),
+		newTestTrigger("test-kperf-1", "other-0-trigger-0", "other-0"))
+
+	err := CleanBrokers(p, pkg.EventingCleanArgs{Namespace: "test-kperf-1", BrokerPrefix: "broker", Concurrency: 1})
+	assert.NilError(t, err)
+
+	brokers, err := fakeDynamic.Resource(BrokerGVR).Namespace("test-kperf-1").List(context.TODO(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(brokers.Items))
+	assert.Equal(t, "other-0", brokers.Items[0].GetName())
+
+	triggers, err := fakeDynamic.Resource(TriggerGVR).Namespace("test-kperf-1").List(context.TODO(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(triggers.Items))
+	assert.Equal(t, "other-0-trigger-0", triggers.Items[0].GetName())
+}
+
+func TestNewEventingCleanCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for eventing clean", func(t *testing.T) {
+		p, _ := newTestPerfParams(nil)
+		cmd := NewEventingCleanCommand(p)
+
+		_, err := testutil.ExecuteCommand(cmd)
+		assert.ErrorContains(t, err, "both namespace and namespace-prefix are empty")
+
+		_, err = testutil.ExecuteCommand(cmd, "--namespace-prefix", "test-kperf", "--namespace-range", "1")
+		assert.ErrorContains(t, err, "expected range like 1,500, given 1")
+	})
+
+	t.Run("clean brokers as expected", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"test-kperf-1"})
+		cmd := NewEventingCleanCommand(p)
+
+		_, err := testutil.ExecuteCommand(cmd, "--namespace", "test-kperf-1")
+		assert.NilError(t, err)
+	})
+}
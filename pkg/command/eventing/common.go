@@ -0,0 +1,104 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	DefaultNamespace       = "default"
+	DefaultBrokerClass     = "MTChannelBasedBroker"
+	DefaultSubscriber      = "kperf-event-display"
+	DefaultSubscriberImage = "gcr.io/knative-releases/knative.dev/eventing/cmd/event_display"
+
+	brokerClassAnnotation = "eventing.knative.dev/broker.class"
+	subscriberLabel       = "kperf.knative.dev/subscriber"
+
+	conditionReady              = "Ready"
+	conditionSubscriberResolved = "SubscriberResolved"
+	conditionSubscriptionReady  = "SubscriptionReady"
+)
+
+var (
+	BrokerGVR  = schema.GroupVersionResource{Group: "eventing.knative.dev", Version: "v1", Resource: "brokers"}
+	TriggerGVR = schema.GroupVersionResource{Group: "eventing.knative.dev", Version: "v1", Resource: "triggers"}
+)
+
+// generateNamespaces returns the namespaces to create resources in, either the single namespace,
+// the namespaces prefix-start...prefix-end or the default namespace
+func generateNamespaces(namespace, namespaceRange, namespacePrefix string) ([]string, error) {
+	if namespacePrefix == "" && namespace == "" {
+		return []string{DefaultNamespace}, nil
+	}
+	if namespacePrefix == "" {
+		return []string{namespace}, nil
+	}
+	r := strings.Split(namespaceRange, ",")
+	if len(r) != 2 {
+		return nil, fmt.Errorf("expected range like 1,500, given %s\n", namespaceRange)
+	}
+	start, err := strconv.Atoi(r[0])
+	if err != nil {
+		return nil, err
+	}
+	end, err := strconv.Atoi(r[1])
+	if err != nil {
+		return nil, err
+	}
+	if start <= 0 || end <= 0 || start > end {
+		return nil, errors.New("failed to parse namespace range")
+	}
+	nsNameList := []string{}
+	for i := start; i <= end; i++ {
+		nsNameList = append(nsNameList, fmt.Sprintf("%s-%d", namespacePrefix, i))
+	}
+	return nsNameList, nil
+}
+
+// getConditionTime returns the last transition time of the condition with the given type.
+// The second return value is false if the condition is not present or its status is not True.
+func getConditionTime(obj *unstructured.Unstructured, conditionType string) (time.Time, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		if condition["status"] != "True" {
+			return time.Time{}, false
+		}
+		transitionTime, _ := condition["lastTransitionTime"].(string)
+		t, err := time.Parse(time.RFC3339, transitionTime)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// triggerBroker returns the name of the broker a trigger is subscribed to
+func triggerBroker(trigger *unstructured.Unstructured) string {
+	broker, _, _ := unstructured.NestedString(trigger.Object, "spec", "broker")
+	return broker
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+// NewEventingCmd represents the eventing command
+func NewEventingCmd(p *pkg.PerfParams) *cobra.Command {
+	var eventingCmd = &cobra.Command{
+		Use:   "eventing",
+		Short: "Knative Eventing load test",
+		Long: `Knative Eventing Broker and Trigger load test and measurement. For example:
+
+kperf eventing generate -n 10 -i 1 -b 5 --triggers 2 - to generate 10 Brokers with 2 Triggers each
+kperf eventing measure --broker-prefix broker --namespace default - to measure the Brokers and Triggers
+kperf eventing latency --namespace default --image ko.local/kperf - to measure the event delivery latency
+kperf eventing kafka -n 5 --image ko.local/kperf --bootstrap-servers kafka:9092 - to measure the delivery throughput of KafkaSources`,
+	}
+	eventingCmd.AddCommand(NewEventingGenerateCommand(p))
+	eventingCmd.AddCommand(NewEventingMeasureCommand(p))
+	eventingCmd.AddCommand(NewEventingCleanCommand(p))
+	eventingCmd.AddCommand(NewEventingLatencyCommand(p))
+	eventingCmd.AddCommand(NewEventingLatencySenderCommand())
+	eventingCmd.AddCommand(NewEventingLatencyReceiverCommand())
+	eventingCmd.AddCommand(NewEventingKafkaCommand(p))
+	eventingCmd.AddCommand(NewEventingKafkaReceiverCommand())
+
+	eventingCmd.InitDefaultHelpCmd()
+	return eventingCmd
+}
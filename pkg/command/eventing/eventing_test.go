@@ -0,0 +1,150 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestNewEventingCmd(t *testing.T) {
+	cmd := NewEventingCmd(nil)
+	assert.Check(t, cmd.HasSubCommands(), "cmd eventing should have subcommands")
+
+	_, _, err := cmd.Find([]string{"generate"})
+	assert.NilError(t, err, "eventing command should have generate subcommand")
+
+	_, _, err = cmd.Find([]string{"measure"})
+	assert.NilError(t, err, "eventing command should have measure subcommand")
+
+	_, _, err = cmd.Find([]string{"clean"})
+	assert.NilError(t, err, "eventing command should have clean subcommand")
+
+	_, _, err = cmd.Find([]string{"latency"})
+	assert.NilError(t, err, "eventing command should have latency subcommand")
+
+	_, _, err = cmd.Find([]string{"kafka"})
+	assert.NilError(t, err, "eventing command should have kafka subcommand")
+}
+
+func TestGetConditionTime(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True", "lastTransitionTime": "2022-01-01T00:00:10Z"},
+				map[string]interface{}{"type": "SubscriptionReady", "status": "False", "lastTransitionTime": "2022-01-01T00:00:05Z"},
+			},
+		},
+	}}
+
+	readyTime, ok := getConditionTime(obj, conditionReady)
+	assert.Check(t, ok)
+	assert.Equal(t, "2022-01-01T00:00:10Z", readyTime.UTC().Format("2006-01-02T15:04:05Z07:00"))
+
+	_, ok = getConditionTime(obj, conditionSubscriptionReady)
+	assert.Check(t, !ok, "condition with status False should not be returned")
+
+	_, ok = getConditionTime(obj, conditionSubscriberResolved)
+	assert.Check(t, !ok, "missing condition should not be returned")
+}
+
+func TestGenerateNamespaces(t *testing.T) {
+	nsList, err := generateNamespaces("", "", "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{DefaultNamespace}, nsList)
+
+	nsList, err = generateNamespaces("ns", "", "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"ns"}, nsList)
+
+	nsList, err = generateNamespaces("", "1,2", "ns")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"ns-1", "ns-2"}, nsList)
+
+	_, err = generateNamespaces("", "2,1", "ns")
+	assert.ErrorContains(t, err, "failed to parse namespace range")
+
+	_, err = generateNamespaces("", "1", "ns")
+	assert.ErrorContains(t, err, "expected range like 1,500, given 1")
+}
+
+// newTestPerfParams returns PerfParams backed by fake clients holding the namespaces and the
+// given Broker and Trigger objects
+func newTestPerfParams(namespaces []string, objects ...runtime.Object) (*pkg.PerfParams, *dynamicfake.FakeDynamicClient) {
+	nsObjects := []runtime.Object{}
+	for _, ns := range namespaces {
+		nsObjects = append(nsObjects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	}
+	client := k8sfake.NewSimpleClientset(nsObjects...)
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeDynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		BrokerGVR:  "BrokerList",
+		TriggerGVR: "TriggerList",
+	}, objects...)
+
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewDynamicClient: func() (dynamic.Interface, error) {
+			return fakeDynamic, nil
+		},
+	}, fakeDynamic
+}
+
+func newTestBroker(ns, name string, conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": BrokerGVR.GroupVersion().String(),
+		"kind":       "Broker",
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         ns,
+			"creationTimestamp": "2022-01-01T00:00:00Z",
+		},
+		"status": map[string]interface{}{"conditions": conditions},
+	}}
+}
+
+func newTestTrigger(ns, name, broker string, conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": TriggerGVR.GroupVersion().String(),
+		"kind":       "Trigger",
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         ns,
+			"creationTimestamp": "2022-01-01T00:00:00Z",
+		},
+		"spec":   map[string]interface{}{"broker": broker},
+		"status": map[string]interface{}{"conditions": conditions},
+	}}
+}
+
+func readyCondition(conditionType, lastTransitionTime string) interface{} {
+	return map[string]interface{}{"type": conditionType, "status": "True", "lastTransitionTime": lastTransitionTime}
+}
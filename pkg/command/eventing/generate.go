@@ -0,0 +1,240 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/generator"
+)
+
+func NewEventingGenerateCommand(p *pkg.PerfParams) *cobra.Command {
+	generateArgs := pkg.EventingGenerateArgs{}
+
+	generateCommand := &cobra.Command{
+		Use:   "generate",
+		Short: "generate Knative Eventing Brokers and Triggers",
+		Long: `generate Knative Eventing Broker and Trigger workload
+For example:
+# To generate 100 Brokers with 5 Triggers each
+kperf eventing generate -n 100 --interval 10 --batch 10 --triggers 5 (--namespace-prefix testns/ --namespace nsname)
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			if flags.Changed("namespace-prefix") && flags.Changed("namespace") {
+				return errors.New("expected either namespace with prefix & range or only namespace name")
+			}
+			return pkg.ValidateRunID(generateArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GenerateBrokers(p, generateArgs)
+		},
+	}
+	generateCommand.Flags().IntVarP(&generateArgs.Number, "number", "n", 0, "Total number of Brokers to be created")
+	generateCommand.MarkFlagRequired("number")
+	generateCommand.Flags().IntVarP(&generateArgs.Interval, "interval", "i", 0, "Interval for each batch generation")
+	generateCommand.MarkFlagRequired("interval")
+	generateCommand.Flags().IntVarP(&generateArgs.Batch, "batch", "b", 0, "Number of Brokers each time to be created")
+	generateCommand.MarkFlagRequired("batch")
+	generateCommand.Flags().IntVarP(&generateArgs.Concurrency, "concurrency", "c", 10, "Number of multiple Brokers to make at a time")
+	generateCommand.Flags().IntVarP(&generateArgs.TriggersPerBroker, "triggers", "t", 1, "Number of Triggers to be created for each Broker")
+
+	generateCommand.Flags().StringVarP(&generateArgs.NamespacePrefix, "namespace-prefix", "", "", "Namespace prefix. The Brokers will be created in the namespaces with the prefix")
+	generateCommand.Flags().StringVarP(&generateArgs.NamespaceRange, "namespace-range", "", "", "")
+	generateCommand.Flags().StringVarP(&generateArgs.Namespace, "namespace", "", "", "Namespace name. The Brokers will be created in the namespace")
+
+	generateCommand.Flags().StringVarP(&generateArgs.BrokerPrefix, "broker-prefix", "", "broker", "Broker name prefix. The Brokers will be broker-0,broker-1 and etc. and their Triggers broker-0-trigger-0 and etc.")
+	generateCommand.Flags().StringVarP(&generateArgs.BrokerClass, "broker-class", "", DefaultBrokerClass, "Broker class of the generated Brokers")
+	generateCommand.Flags().StringVarP(&generateArgs.Subscriber, "subscriber", "", DefaultSubscriber, "Knative Service the Triggers deliver to, created in each namespace if missing")
+	generateCommand.Flags().StringVarP(&generateArgs.SubscriberImage, "subscriber-image", "", DefaultSubscriberImage, "Image of the subscriber Knative Service if it has to be created")
+	generateCommand.Flags().BoolVarP(&generateArgs.CleanupOnFailure, "cleanup-on-failure", "", false, "Delete the created Brokers, Triggers and subscriber Knative Services if the generation fails, panics or is interrupted")
+	generateCommand.Flags().DurationVarP(&generateArgs.TTL, "ttl", "", 0, "Time to live of the generated Brokers, Triggers and subscriber Knative Services, after which 'kperf clean expired' deletes them, 0 to never expire")
+	generateCommand.Flags().StringVarP(&generateArgs.RunID, "run-id", "", "", "ID of the run the generated Brokers, Triggers and subscriber Knative Services are labeled with as "+pkg.RunIDLabel+". A new ID is generated by default")
+	return generateCommand
+}
+
+// GenerateBrokers used to generate Knative Eventing Broker and Trigger workload
+func GenerateBrokers(params *pkg.PerfParams, inputs pkg.EventingGenerateArgs) error {
+	nsNameList, err := generateNamespaces(inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+
+	// Check if namespace exists, in NOT, return error
+	for _, ns := range nsNameList {
+		_, err := params.ClientSet.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
+		if err != nil && apierrors.IsNotFound(err) {
+			return fmt.Errorf("namespace %s not found, please create one", ns)
+		} else if err != nil {
+			return fmt.Errorf("failed to get namespace: %w", err)
+		}
+	}
+
+	if inputs.RunID == "" {
+		inputs.RunID = pkg.NewRunID(time.Now())
+	}
+	fmt.Printf("Run ID %s\n", inputs.RunID)
+
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return err
+	}
+	var cleanup *generator.Cleanup
+	if inputs.CleanupOnFailure {
+		cleanup = generator.NewCleanup()
+		defer cleanup.HandleSignals()()
+		defer func() {
+			if r := recover(); r != nil {
+				cleanup.Run()
+				panic(r)
+			}
+		}()
+	}
+	if err := ensureSubscribers(params, nsNameList, inputs.Subscriber, inputs.SubscriberImage, inputs.TTL, inputs.RunID, cleanup); err != nil {
+		if cleanup != nil {
+			cleanup.Run()
+		}
+		return err
+	}
+
+	createBrokerFunc := func(ns string, index int) (string, string) {
+		name := fmt.Sprintf("%s-%d", inputs.BrokerPrefix, index)
+		fmt.Printf("Creating Broker %s in namespace %s\n", name, ns)
+		if err := createBroker(dynamicClient, ns, name, inputs.BrokerClass, inputs.TTL, inputs.RunID); err != nil {
+			fmt.Printf("failed to create Broker %s in namespace %s : %s\n", name, ns, err)
+			return ns, name
+		}
+		addCleanup(cleanup, dynamicClient, BrokerGVR, "Broker", ns, name)
+		for j := 0; j < inputs.TriggersPerBroker; j++ {
+			triggerName := fmt.Sprintf("%s-trigger-%d", name, j)
+			if err := createTrigger(dynamicClient, ns, triggerName, name, inputs.Subscriber, inputs.TTL, inputs.RunID); err != nil {
+				fmt.Printf("failed to create Trigger %s in namespace %s : %s\n", triggerName, ns, err)
+				continue
+			}
+			addCleanup(cleanup, dynamicClient, TriggerGVR, "Trigger", ns, triggerName)
+		}
+		return ns, name
+	}
+	batchGenerator := generator.NewBatchGenerator(time.Duration(inputs.Interval)*time.Second, inputs.Number, inputs.Batch, inputs.Concurrency, nsNameList, createBrokerFunc, func(ns, name string) error { return nil }).
+		WithClock(params.Clock)
+	if cleanup != nil {
+		batchGenerator.WithAbort(cleanup.Run)
+	}
+	batchGenerator.Generate()
+	return nil
+}
+
+// addCleanup adds a created Broker or Trigger to the cleanup of a failed run if the cleanup is enabled
+func addCleanup(cleanup *generator.Cleanup, client dynamic.Interface, gvr schema.GroupVersionResource, kind, ns, name string) {
+	if cleanup == nil {
+		return
+	}
+	cleanup.Add(kind, ns, name, func() error {
+		return client.Resource(gvr).Namespace(ns).Delete(context.Background(), name, metav1.DeleteOptions{})
+	})
+}
+
+// ensureSubscribers creates the subscriber Knative Service in the namespaces where it doesn't exist yet
+func ensureSubscribers(params *pkg.PerfParams, nsNameList []string, name, image string, ttl time.Duration, runID string, cleanup *generator.Cleanup) error {
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	for _, ns := range nsNameList {
+		_, err := ksvcClient.Services(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get subscriber Knative Service %s in namespace %s: %w", name, ns, err)
+		}
+		labels := map[string]string{subscriberLabel: "true"}
+		for k, v := range pkg.GeneratedLabels(runID, ttl, time.Now()) {
+			labels[k] = v
+		}
+		service := &servingv1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    labels,
+			},
+		}
+		service.Spec.Template.Spec.Containers = []corev1.Container{{Image: image}}
+		fmt.Printf("Creating subscriber Knative Service %s in namespace %s\n", name, ns)
+		if _, err := ksvcClient.Services(ns).Create(context.TODO(), service, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create subscriber Knative Service %s in namespace %s: %w", name, ns, err)
+		}
+		if cleanup != nil {
+			namespace := ns
+			cleanup.Add("subscriber Knative Service", namespace, name, func() error {
+				return ksvcClient.Services(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+			})
+		}
+	}
+	return nil
+}
+
+func createBroker(client dynamic.Interface, ns, name, class string, ttl time.Duration, runID string) error {
+	broker := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": BrokerGVR.GroupVersion().String(),
+		"kind":       "Broker",
+		"metadata": map[string]interface{}{
+			"name":        name,
+			"namespace":   ns,
+			"annotations": map[string]interface{}{brokerClassAnnotation: class},
+		},
+	}}
+	broker.SetLabels(pkg.GeneratedLabels(runID, ttl, time.Now()))
+	_, err := client.Resource(BrokerGVR).Namespace(ns).Create(context.TODO(), broker, metav1.CreateOptions{})
+	return err
+}
+
+func createTrigger(client dynamic.Interface, ns, name, broker, subscriber string, ttl time.Duration, runID string) error {
+	trigger := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": TriggerGVR.GroupVersion().String(),
+		"kind":       "Trigger",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": ns,
+		},
+		"spec": map[string]interface{}{
+			"broker": broker,
+			"subscriber": map[string]interface{}{
+				"ref": map[string]interface{}{
+					"apiVersion": servingv1.SchemeGroupVersion.String(),
+					"kind":       "Service",
+					"name":       subscriber,
+				},
+			},
+		},
+	}}
+	trigger.SetLabels(pkg.GeneratedLabels(runID, ttl, time.Now()))
+	_, err := client.Resource(TriggerGVR).Namespace(ns).Create(context.TODO(), trigger, metav1.CreateOptions{})
+	return err
+}
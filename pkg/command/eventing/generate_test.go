@@ -0,0 +1,90 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewEventingGenerateCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for eventing generate", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"test-kperf-1"})
+		cmd := NewEventingGenerateCommand(p)
+
+		_, err := testutil.ExecuteCommand(cmd)
+		assert.ErrorContains(t, err, "required flag(s)")
+
+		_, err = testutil.ExecuteCommand(cmd, "-n", "1", "-i", "1", "-b", "1", "--namespace-prefix", "test-kperf", "--namespace", "test-kperf-1")
+		assert.ErrorContains(t, err, "expected either namespace with prefix & range or only namespace name")
+
+		cmd = NewEventingGenerateCommand(p)
+		_, err = testutil.ExecuteCommand(cmd, "-n", "1", "-i", "1", "-b", "1", "--namespace", "test-kperf-2")
+		assert.ErrorContains(t, err, "namespace test-kperf-2 not found, please create one")
+	})
+
+	t.Run("generate brokers and triggers as expected", func(t *testing.T) {
+		p, fakeDynamic := newTestPerfParams([]string{"test-kperf-1"})
+		cmd := NewEventingGenerateCommand(p)
+
+		_, err := testutil.ExecuteCommand(cmd, "-n", "2", "-i", "1", "-b", "2", "-t", "2", "--namespace", "test-kperf-1", "--ttl", "1h")
+		assert.NilError(t, err)
+
+		brokers, err := fakeDynamic.Resource(BrokerGVR).Namespace("test-kperf-1").List(context.TODO(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 2, len(brokers.Items))
+		assert.Equal(t, DefaultBrokerClass, brokers.Items[0].GetAnnotations()[brokerClassAnnotation])
+		assert.Check(t, pkg.Expired(brokers.Items[0].GetLabels(), time.Now().Add(time.Hour)), "expected the broker to expire after 1h")
+
+		triggers, err := fakeDynamic.Resource(TriggerGVR).Namespace("test-kperf-1").List(context.TODO(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 4, len(triggers.Items))
+
+		ksvcClient, _ := p.NewServingClient()
+		svc, err := ksvcClient.Services("test-kperf-1").Get(context.TODO(), DefaultSubscriber, metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "true", svc.Labels[subscriberLabel])
+		assert.Check(t, pkg.Expired(svc.Labels, time.Now().Add(time.Hour)), "expected the subscriber to expire after 1h")
+	})
+	t.Run("delete the created subscribers if the generation fails", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"test-kperf-1", "test-kperf-2"})
+		p.ClientSet.(*k8sfake.Clientset).PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() == "test-kperf-2" {
+				return true, nil, errors.New("quota exceeded")
+			}
+			return false, nil, nil
+		})
+		cmd := NewEventingGenerateCommand(p)
+
+		_, err := testutil.ExecuteCommand(cmd, "-n", "2", "-i", "1", "-b", "2", "--namespace-prefix", "test-kperf", "--namespace-range", "1,2", "--cleanup-on-failure")
+		assert.ErrorContains(t, err, "failed to create subscriber Knative Service kperf-event-display in namespace test-kperf-2: quota exceeded")
+
+		ksvcClient, _ := p.NewServingClient()
+		_, err = ksvcClient.Services("test-kperf-1").Get(context.TODO(), DefaultSubscriber, metav1.GetOptions{})
+		assert.Check(t, apierrors.IsNotFound(err), "expected the subscriber to be deleted, got %v", err)
+	})
+}
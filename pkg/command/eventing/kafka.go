@@ -0,0 +1,522 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	KafkaOutputFilename = "eventing_kafka"
+
+	DefaultKafkaProducerImage = "quay.io/strimzi/kafka:0.32.0-kafka-3.3.1"
+
+	// kafkaName prefixes the names of the KafkaSources or KafkaChannels, their topics and producers
+	kafkaName     = "kperf-kafka"
+	kafkaReceiver = "kperf-kafka-receiver"
+
+	kafkaProducerContainer = "producer"
+	// kafkaProducerPerfTest is the producer benchmark of the Kafka distribution in the producer image
+	kafkaProducerPerfTest = "/opt/kafka/bin/kafka-producer-perf-test.sh"
+)
+
+var (
+	KafkaSourceGVR  = schema.GroupVersionResource{Group: "sources.knative.dev", Version: "v1beta1", Resource: "kafkasources"}
+	KafkaChannelGVR = schema.GroupVersionResource{Group: "messaging.knative.dev", Version: "v1beta1", Resource: "kafkachannels"}
+
+	// readJobLogs returns the logs of the producer containers of the pods of a Job
+	readJobLogs = func(ctx context.Context, client kubernetes.Interface, ns, job string) (string, error) {
+		selector := labels.SelectorFromSet(labels.Set{"job-name": job}).String()
+		podList, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return "", err
+		}
+		logs := strings.Builder{}
+		for _, pod := range podList.Items {
+			data, err := client.CoreV1().Pods(ns).GetLogs(pod.Name, &corev1.PodLogOptions{Container: kafkaProducerContainer}).DoRaw(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to read logs of pod %s: %w", pod.Name, err)
+			}
+			logs.Write(data)
+		}
+		return logs.String(), nil
+	}
+)
+
+func NewEventingKafkaCommand(p *pkg.PerfParams) *cobra.Command {
+	kafkaArgs := pkg.EventingKafkaArgs{}
+	kafkaCommand := &cobra.Command{
+		Use:   "kafka",
+		Short: "Measure the readiness and the delivery throughput of KafkaSources or KafkaChannels",
+		Long: `Measure the readiness of KafkaSources or KafkaChannels and the throughput of the messages delivered by them
+
+A receiver Knative Service is deployed with the kperf image given by --image as sink of N KafkaSources, each
+consuming its own topic named like the KafkaSource. For every KafkaSource a Job produces messages at the given rate to
+its topic with the producer benchmark of Kafka, the topics have to exist or be created automatically by the brokers.
+With --channel N KafkaChannels are subscribed by the receiver instead, and a sender Knative Service per channel sends
+the messages to it.
+
+The readiness is the time since the creation of a KafkaSource or KafkaChannel until it is ready. The throughput is the
+number of messages delivered per second from the first until the last delivery, and the consumer lag the number of
+produced messages which weren't delivered at the end of the drain.
+
+For example:
+# To produce 100 messages per second for 1 minute to each of 5 KafkaSources in namespace ns
+kperf eventing kafka -n 5 --namespace ns --image ko.local/kperf --bootstrap-servers my-cluster-kafka-bootstrap.kafka:9092 --rate 100 --duration 1m
+
+# To send the messages through 5 KafkaChannels instead
+kperf eventing kafka -n 5 --namespace ns --image ko.local/kperf --channel
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'eventing kafka' requires flag(s)")
+			}
+			if kafkaArgs.Image == "" {
+				return fmt.Errorf("--image is required to deploy the receiver")
+			}
+			if !kafkaArgs.Channel && len(kafkaArgs.BootstrapServers) == 0 {
+				return fmt.Errorf("--bootstrap-servers is required to produce the messages to the topics of the KafkaSources")
+			}
+			if kafkaArgs.Count < 1 {
+				return fmt.Errorf("number must be at least 1")
+			}
+			if kafkaArgs.Rate < 1 {
+				return fmt.Errorf("rate must be at least 1")
+			}
+			if kafkaArgs.Duration <= 0 {
+				return fmt.Errorf("duration must be greater than 0")
+			}
+			if kafkaArgs.MessageSize < 1 {
+				return fmt.Errorf("message size must be at least 1")
+			}
+			return pkg.ValidateRunID(kafkaArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureKafka(p, kafkaArgs)
+		},
+	}
+
+	kafkaCommand.Flags().IntVarP(&kafkaArgs.Count, "number", "n", 1, "Number of KafkaSources or KafkaChannels")
+	kafkaCommand.Flags().StringVarP(&kafkaArgs.Namespace, "namespace", "", DefaultNamespace, "Namespace to deploy the benchmark to")
+	kafkaCommand.Flags().BoolVarP(&kafkaArgs.Channel, "channel", "", false, "Send the messages through KafkaChannels and Subscriptions instead of KafkaSources")
+	kafkaCommand.Flags().StringSliceVarP(&kafkaArgs.BootstrapServers, "bootstrap-servers", "", nil, "Bootstrap servers of the Kafka cluster the KafkaSources consume, e.g. my-cluster-kafka-bootstrap.kafka:9092")
+	kafkaCommand.Flags().IntVarP(&kafkaArgs.Rate, "rate", "", 10, "Messages produced per second to every KafkaSource or KafkaChannel")
+	kafkaCommand.Flags().DurationVarP(&kafkaArgs.Duration, "duration", "", time.Minute, "Duration to produce messages for")
+	kafkaCommand.Flags().IntVarP(&kafkaArgs.MessageSize, "message-size", "", 100, "Size of the messages produced to the topics of the KafkaSources in bytes")
+	kafkaCommand.Flags().StringVarP(&kafkaArgs.ProducerImage, "producer-image", "", DefaultKafkaProducerImage, "Kafka image of the producer Jobs, it has to contain "+kafkaProducerPerfTest)
+	kafkaCommand.Flags().StringVarP(&kafkaArgs.Image, "image", "", "", "kperf image run by the receiver and the senders to the KafkaChannels, e.g. built with 'ko build ./cmd/kperf'")
+	kafkaCommand.Flags().DurationVarP(&kafkaArgs.Drain, "drain", "", 10*time.Second, "Time to wait for messages in flight after the producers finished")
+	kafkaCommand.Flags().DurationVarP(&kafkaArgs.Timeout, "timeout", "", 5*time.Minute, "Timeout for the benchmark resources to become ready")
+	kafkaCommand.Flags().BoolVarP(&kafkaArgs.Keep, "keep", "", false, "Keep the benchmark resources after the measurement")
+	kafkaCommand.Flags().BoolVarP(&kafkaArgs.Verbose, "verbose", "v", false, "Kafka verbose result")
+	kafkaCommand.Flags().StringVarP(&kafkaArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(kafkaCommand.Flags(), &kafkaArgs.OutputFormats)
+	kafkaCommand.Flags().StringVarP(&kafkaArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	return kafkaCommand
+}
+
+// MeasureKafka deploys the receiver and the KafkaSources or KafkaChannels delivering to it, produces messages to them
+// and measures their readiness and the delivery of the messages
+func MeasureKafka(params *pkg.PerfParams, inputs pkg.EventingKafkaArgs) error {
+	ctx := context.Background()
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client %s\n", err)
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return fmt.Errorf("failed to create serving client %s\n", err)
+	}
+	ns := inputs.Namespace
+
+	var deletions []func() error
+	if !inputs.Keep {
+		defer func() {
+			for i := len(deletions) - 1; i >= 0; i-- {
+				if err := deletions[i](); err != nil && !apierrors.IsNotFound(err) {
+					fmt.Printf("failed to delete benchmark resource and skip %s\n", err)
+				}
+			}
+		}()
+	}
+	deleteResource := func(gvr schema.GroupVersionResource, name string) func() error {
+		return func() error {
+			return dynamicClient.Resource(gvr).Namespace(ns).Delete(context.Background(), name, metav1.DeleteOptions{})
+		}
+	}
+	deleteService := func(name string) func() error {
+		return func() error {
+			return ksvcClient.Services(ns).Delete(context.Background(), name, metav1.DeleteOptions{})
+		}
+	}
+	deleteJob := func(name string) func() error {
+		return func() error {
+			propagation := metav1.DeletePropagationBackground
+			return params.ClientSet.BatchV1().Jobs(ns).Delete(context.Background(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		}
+	}
+
+	fmt.Printf("Creating receiver Knative Service %s in namespace %s\n", kafkaReceiver, ns)
+	if err := createLatencyService(ctx, ksvcClient, ns, kafkaReceiver, inputs.Image, []string{"eventing", "kafka-receiver"}); err != nil {
+		return err
+	}
+	deletions = append(deletions, deleteService(kafkaReceiver))
+	if err := service.WaitServiceReady(ctx, ksvcClient, ns, kafkaReceiver, inputs.Timeout); err != nil {
+		return err
+	}
+
+	gvr := KafkaSourceGVR
+	if inputs.Channel {
+		gvr = KafkaChannelGVR
+	}
+	sources := make([]pkg.KafkaSourceMeasurement, inputs.Count)
+	for i := range sources {
+		name := fmt.Sprintf("%s-%d", kafkaName, i)
+		sources[i] = pkg.KafkaSourceMeasurement{Name: name, Namespace: ns}
+		if inputs.Channel {
+			fmt.Printf("Creating KafkaChannel %s with Subscription %s in namespace %s\n", name, name, ns)
+			err = createKafkaChannel(ctx, dynamicClient, ns, name)
+			deletions = append(deletions, deleteResource(KafkaChannelGVR, name), deleteResource(SubscriptionGVR, name))
+		} else {
+			fmt.Printf("Creating KafkaSource %s for topic %s in namespace %s\n", name, name, ns)
+			err = createKafkaSource(ctx, dynamicClient, ns, name, inputs.BootstrapServers)
+			deletions = append(deletions, deleteResource(KafkaSourceGVR, name))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	sinks := make([]string, len(sources))
+	err = wait.PollImmediate(latencyPollInterval, inputs.Timeout, func() (bool, error) {
+		for i := range sources {
+			if sinks[i] != "" {
+				continue
+			}
+			obj, err := dynamicClient.Resource(gvr).Namespace(ns).Get(ctx, sources[i].Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			ready, ok := getConditionTime(obj, conditionReady)
+			if !ok {
+				return false, nil
+			}
+			if inputs.Channel {
+				subscription, err := dynamicClient.Resource(SubscriptionGVR).Namespace(ns).Get(ctx, sources[i].Name, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				if _, ok := getConditionTime(subscription, conditionReady); !ok {
+					return false, nil
+				}
+			}
+			sources[i].Ready = ready.Sub(obj.GetCreationTimestamp().Time).Seconds()
+			sinks[i], _, _ = unstructured.NestedString(obj.Object, "status", "address", "url")
+			if !inputs.Channel {
+				// KafkaSources are no addressables, the messages are produced to their topics
+				sinks[i] = sources[i].Name
+			}
+			if sinks[i] == "" {
+				return false, nil
+			}
+			if inputs.Verbose {
+				fmt.Printf("[Verbose] %s %s: Ready Duration is %fs\n", strings.TrimSuffix(gvr.Resource, "s"), sources[i].Name, sources[i].Ready)
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for the %s to be ready: %s", gvr.Resource, err)
+	}
+
+	for i, source := range sources {
+		producer := source.Name + "-producer"
+		if inputs.Channel {
+			fmt.Printf("Creating sender Knative Service %s, sending %d message(s) per second for %s to %s\n", producer, inputs.Rate, inputs.Duration, sinks[i])
+			err = createLatencyService(ctx, ksvcClient, ns, producer, inputs.Image, []string{"eventing", "latency-sender",
+				"--sink", sinks[i], "--source", source.Name, "--rate", strconv.Itoa(inputs.Rate), "--duration", inputs.Duration.String()})
+			deletions = append(deletions, deleteService(producer))
+		} else {
+			fmt.Printf("Creating producer Job %s, producing %d message(s) per second for %s to topic %s\n", producer, inputs.Rate, inputs.Duration, source.Name)
+			err = createKafkaProducer(ctx, params.ClientSet, ns, producer, source.Name, inputs)
+			deletions = append(deletions, deleteJob(producer))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	finished := make([]bool, len(sources))
+	err = wait.PollImmediate(latencyPollInterval, inputs.Duration+inputs.Timeout, func() (bool, error) {
+		for i := range sources {
+			if finished[i] {
+				continue
+			}
+			producer := sources[i].Name + "-producer"
+			if inputs.Channel {
+				logs, err := readPodLogs(ctx, params.ClientSet, ns, producer)
+				if err != nil {
+					return false, nil
+				}
+				sent, failed, ok := parseSentLog(logs)
+				if !ok {
+					return false, nil
+				}
+				sources[i].Produced = sent - failed
+			} else {
+				logs, err := readJobLogs(ctx, params.ClientSet, ns, producer)
+				if err != nil {
+					return false, nil
+				}
+				produced, ok := parseProducerLog(logs)
+				if !ok {
+					return false, nil
+				}
+				sources[i].Produced = produced
+			}
+			finished[i] = true
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for the producers to finish: %s", err)
+	}
+	fmt.Printf("Producers finished, waiting %s for messages in flight\n", inputs.Drain)
+	time.Sleep(inputs.Drain)
+
+	logs, err := readPodLogs(ctx, params.ClientSet, ns, kafkaReceiver)
+	if err != nil {
+		return fmt.Errorf("failed to read the receiver logs: %s", err)
+	}
+	result := summarizeKafka(sources, parseKafkaDeliveries(logs))
+	result.KnativeInfo = measure.GetKnativeInfo(ctx, params, measure.DefaultLogger)
+
+	rows := [][]string{{"name", "namespace", "ready", "produced", "delivered", "consumer_lag"}}
+	for _, s := range result.Sources {
+		rows = append(rows, []string{s.Name, s.Namespace, fmt.Sprintf("%f", s.Ready), strconv.Itoa(s.Produced),
+			strconv.Itoa(s.Delivered), strconv.Itoa(s.ConsumerLag)})
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] %s %s: Produced %d, Delivered %d, Consumer Lag %d\n", strings.TrimSuffix(gvr.Resource, "s"),
+				s.Name, s.Produced, s.Delivered, s.ConsumerLag)
+		}
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Kafka Delivery Measurement:\n")
+	readiness := make([]float64, 0, len(result.Sources))
+	for _, s := range result.Sources {
+		readiness = append(readiness, s.Ready)
+	}
+	sort.Float64s(readiness)
+	fmt.Printf("Ready: %d | Min: %fs Max: %fs\n", len(readiness), readiness[0], readiness[len(readiness)-1])
+	fmt.Printf("Produced: %d | Delivered: %d Consumer Lag: %d\n", result.Produced, result.Delivered, result.ConsumerLag)
+	fmt.Printf("Throughput: %f messages/s\n", result.Throughput)
+	fmt.Printf("Latency Percentile50: %fs Percentile95: %fs Percentile99: %fs Max: %fs\n", result.Latency.P50,
+		result.Latency.P95, result.Latency.P99, result.Latency.Max)
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    KafkaOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// summarizeKafka counts the messages delivered per KafkaSource or KafkaChannel and computes the throughput and the
+// latencies of the first delivery of every message. Duplicated deliveries are not counted.
+func summarizeKafka(sources []pkg.KafkaSourceMeasurement, deliveries []kafkaDelivery) pkg.EventingKafkaResult {
+	result := pkg.EventingKafkaResult{Sources: sources}
+	received := map[string][]float64{}
+	delivered := map[string]int{}
+	var first, last time.Time
+	for _, d := range deliveries {
+		key := d.source + "/" + d.id
+		if _, ok := received[key]; !ok {
+			delivered[d.source]++
+			if first.IsZero() || d.received.Before(first) {
+				first = d.received
+			}
+			if d.received.After(last) {
+				last = d.received
+			}
+		}
+		received[key] = append(received[key], d.latency)
+	}
+	for i := range result.Sources {
+		s := &result.Sources[i]
+		s.Delivered = delivered[s.Name]
+		if lag := s.Produced - s.Delivered; lag > 0 {
+			s.ConsumerLag = lag
+		}
+		result.Produced += s.Produced
+		result.Delivered += s.Delivered
+		result.ConsumerLag += s.ConsumerLag
+	}
+	if window := last.Sub(first).Seconds(); window > 0 {
+		result.Throughput = float64(result.Delivered) / window
+	}
+	result.Latency = summarizeLatency(result.Produced, 0, received)
+	return result
+}
+
+// parseProducerLog returns the number of messages sent by the producer benchmark of Kafka when it finished. The
+// last return value is false if the producer didn't finish yet. The benchmark logs its progress and finishes with
+// a line like:
+// 6000 records sent, 99.983336 records/sec (0.01 MB/sec), 2.45 ms avg latency, 240.00 ms max latency, 2 ms 50th, ...
+func parseProducerLog(logs string) (int, bool) {
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.Contains(line, " records sent, ") || !strings.Contains(line, " 50th") {
+			continue
+		}
+		sent, err := strconv.Atoi(strings.Fields(line)[0])
+		if err != nil {
+			continue
+		}
+		return sent, true
+	}
+	return 0, false
+}
+
+func createKafkaSource(ctx context.Context, client dynamic.Interface, ns, name string, bootstrapServers []string) error {
+	servers := make([]interface{}, 0, len(bootstrapServers))
+	for _, s := range bootstrapServers {
+		servers = append(servers, s)
+	}
+	source := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": KafkaSourceGVR.GroupVersion().String(),
+		"kind":       "KafkaSource",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": ns,
+		},
+		"spec": map[string]interface{}{
+			"consumerGroup":    name,
+			"bootstrapServers": servers,
+			"topics":           []interface{}{name},
+			"sink":             kafkaSink(),
+		},
+	}}
+	if _, err := client.Resource(KafkaSourceGVR).Namespace(ns).Create(ctx, source, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create KafkaSource %s in namespace %s: %s", name, ns, err)
+	}
+	return nil
+}
+
+func createKafkaChannel(ctx context.Context, client dynamic.Interface, ns, name string) error {
+	channel := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": KafkaChannelGVR.GroupVersion().String(),
+		"kind":       "KafkaChannel",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": ns,
+		},
+	}}
+	if _, err := client.Resource(KafkaChannelGVR).Namespace(ns).Create(ctx, channel, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create KafkaChannel %s in namespace %s: %s", name, ns, err)
+	}
+	subscription := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": SubscriptionGVR.GroupVersion().String(),
+		"kind":       "Subscription",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": ns,
+		},
+		"spec": map[string]interface{}{
+			"channel": map[string]interface{}{
+				"apiVersion": KafkaChannelGVR.GroupVersion().String(),
+				"kind":       "KafkaChannel",
+				"name":       name,
+			},
+			"subscriber": kafkaSink(),
+		},
+	}}
+	if _, err := client.Resource(SubscriptionGVR).Namespace(ns).Create(ctx, subscription, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create Subscription %s in namespace %s: %s", name, ns, err)
+	}
+	return nil
+}
+
+// createKafkaProducer creates a Job producing rate messages per second for the duration to the topic
+func createKafkaProducer(ctx context.Context, client kubernetes.Interface, ns, name, topic string, inputs pkg.EventingKafkaArgs) error {
+	backoffLimit := int32(0)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    kafkaProducerContainer,
+						Image:   inputs.ProducerImage,
+						Command: []string{kafkaProducerPerfTest},
+						Args: []string{
+							"--topic", topic,
+							"--num-records", strconv.Itoa(int(float64(inputs.Rate) * inputs.Duration.Seconds())),
+							"--throughput", strconv.Itoa(inputs.Rate),
+							"--record-size", strconv.Itoa(inputs.MessageSize),
+							"--producer-props", "bootstrap.servers=" + strings.Join(inputs.BootstrapServers, ","),
+						},
+					}},
+				},
+			},
+		},
+	}
+	if _, err := client.BatchV1().Jobs(ns).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create producer Job %s in namespace %s: %s", name, ns, err)
+	}
+	return nil
+}
+
+func kafkaSink() map[string]interface{} {
+	return map[string]interface{}{
+		"ref": map[string]interface{}{
+			"apiVersion": servingv1.SchemeGroupVersion.String(),
+			"kind":       "Service",
+			"name":       kafkaReceiver,
+		},
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// kafkaReceivedLog starts the log lines of the receiver which are read by 'eventing kafka'
+const kafkaReceivedLog = "kperf-kafka received"
+
+// kafkaDelivery is a message delivered to the receiver of 'eventing kafka'
+type kafkaDelivery struct {
+	// source is the name of the KafkaSource or KafkaChannel the message was produced to
+	source   string
+	id       string
+	latency  float64
+	received time.Time
+}
+
+// NewEventingKafkaReceiverCommand runs in the sink Knative Service deployed by 'eventing kafka'
+func NewEventingKafkaReceiverCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    "kafka-receiver",
+		Short:  "Receive CloudEvents for 'eventing kafka'",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return http.ListenAndServe(":"+agentPort(), kafkaReceiverHandler(cmd.OutOrStdout(), time.Now))
+		},
+	}
+}
+
+// kafkaReceiverHandler logs the source, the id, the delivery latency and the receive time of every received
+// CloudEvent
+func kafkaReceiverHandler(log io.Writer, now func() time.Time) http.HandlerFunc {
+	var m sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Ce-Id") == "" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		produced, err := time.Parse(time.RFC3339Nano, r.Header.Get("Ce-Time"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid event time: %s", err), http.StatusBadRequest)
+			return
+		}
+		received := now()
+		m.Lock()
+		fmt.Fprintf(log, "%s %s %s %f %d\n", kafkaReceivedLog, kafkaSourceName(r.Header.Get("Ce-Source")),
+			r.Header.Get("Ce-Id"), received.Sub(produced).Seconds(), received.UnixNano())
+		m.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// kafkaSourceName returns the name of the KafkaSource or KafkaChannel of the CloudEvents source. KafkaSources set
+// the source like /apis/v1/namespaces/ns/kafkasources/name#topic, the topics are named like the KafkaSources. The
+// senders to the KafkaChannels set the name of the channel as source.
+func kafkaSourceName(source string) string {
+	if i := strings.LastIndex(source, "#"); i >= 0 {
+		return source[i+1:]
+	}
+	return source
+}
+
+// parseKafkaDeliveries returns the deliveries logged by the receiver
+func parseKafkaDeliveries(logs string) []kafkaDelivery {
+	deliveries := []kafkaDelivery{}
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.HasPrefix(line, kafkaReceivedLog+" ") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, kafkaReceivedLog))
+		if len(fields) != 4 {
+			continue
+		}
+		latency, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			continue
+		}
+		received, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		deliveries = append(deliveries, kafkaDelivery{source: fields[0], id: fields[1], latency: latency, received: time.Unix(0, received)})
+	}
+	return deliveries
+}
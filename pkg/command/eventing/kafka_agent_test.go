@@ -0,0 +1,67 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestKafkaReceiverHandler(t *testing.T) {
+	produced := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	received := produced.Add(250 * time.Millisecond)
+	log := &bytes.Buffer{}
+	handler := kafkaReceiverHandler(log, func() time.Time { return received })
+
+	newRequest := func(source, eventTime string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Ce-Id", "partition:0/offset:7")
+		req.Header.Set("Ce-Source", source)
+		req.Header.Set("Ce-Time", eventTime)
+		return req
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, newRequest("/apis/v1/namespaces/ns-1/kafkasources/kperf-kafka-0#kperf-kafka-0", produced.Format(time.RFC3339Nano)))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	recorder = httptest.NewRecorder()
+	handler(recorder, newRequest("kperf-kafka-1", produced.Format(time.RFC3339Nano)))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+	deliveries := parseKafkaDeliveries(log.String() + "unrelated line\n" + kafkaReceivedLog + " kperf-kafka-0 1 x 1\n")
+	assert.Equal(t, 2, len(deliveries))
+	for i, source := range []string{"kperf-kafka-0", "kperf-kafka-1"} {
+		assert.Equal(t, source, deliveries[i].source)
+		assert.Equal(t, "partition:0/offset:7", deliveries[i].id)
+		assert.Equal(t, 0.25, deliveries[i].latency)
+		assert.Check(t, received.Equal(deliveries[i].received))
+	}
+
+	recorder = httptest.NewRecorder()
+	handler(recorder, newRequest("kperf-kafka-1", "yesterday"))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// probes are not logged
+	log.Reset()
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "", log.String())
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewEventingKafkaCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for eventing kafka", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"ns-1"})
+
+		_, err := testutil.ExecuteCommand(NewEventingKafkaCommand(p))
+		assert.ErrorContains(t, err, "'eventing kafka' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewEventingKafkaCommand(p), "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "--image is required to deploy the receiver")
+
+		_, err = testutil.ExecuteCommand(NewEventingKafkaCommand(p), "--image", "kperf")
+		assert.ErrorContains(t, err, "--bootstrap-servers is required to produce the messages to the topics of the KafkaSources")
+
+		_, err = testutil.ExecuteCommand(NewEventingKafkaCommand(p), "--image", "kperf", "--channel", "-n", "0")
+		assert.ErrorContains(t, err, "number must be at least 1")
+
+		_, err = testutil.ExecuteCommand(NewEventingKafkaCommand(p), "--image", "kperf", "--channel", "--message-size", "0")
+		assert.ErrorContains(t, err, "message size must be at least 1")
+	})
+
+	for _, channel := range []bool{false, true} {
+		channel := channel
+		name := "measure the delivery of KafkaSources"
+		if channel {
+			name = "measure the delivery of KafkaChannels"
+		}
+		t.Run(name, func(t *testing.T) {
+			p, fakeDynamic := newTestPerfParams([]string{"ns-1"})
+			p.ClientSet.(*k8sfake.Clientset).PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				svc := &servingv1.Service{}
+				svc.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+				return true, svc, nil
+			})
+			for _, resource := range []string{"kafkasources", "kafkachannels", "subscriptions"} {
+				fakeDynamic.PrependReactor("get", resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+					name := action.(clienttesting.GetAction).GetName()
+					obj := newTestBroker("ns-1", name, readyCondition(conditionReady, "2022-01-01T00:00:04Z"))
+					unstructured.SetNestedField(obj.Object, "http://"+name+".ns-1.svc.cluster.local", "status", "address", "url")
+					return true, obj, nil
+				})
+			}
+			restorePodLogs, restoreJobLogs := readPodLogs, readJobLogs
+			defer func() { readPodLogs, readJobLogs = restorePodLogs, restoreJobLogs }()
+			readJobLogs = func(ctx context.Context, client kubernetes.Interface, ns, job string) (string, error) {
+				return "2 records sent, 2.0 records/sec (0.00 MB/sec), 2.00 ms avg latency, 3.00 ms max latency, 2 ms 50th, 3 ms 95th, 3 ms 99th, 3 ms 99.9th.\n", nil
+			}
+			readPodLogs = func(ctx context.Context, client kubernetes.Interface, ns, svc string) (string, error) {
+				if svc != kafkaReceiver {
+					return latencySentLog + " 2 0\n", nil
+				}
+				return kafkaReceivedLog + " kperf-kafka-0 1 0.100000 1000000000\n" +
+					kafkaReceivedLog + " kperf-kafka-0 2 0.200000 2000000000\n" +
+					kafkaReceivedLog + " kperf-kafka-1 1 0.300000 3000000000\n", nil
+			}
+
+			outputDir := t.TempDir()
+			args := []string{"-n", "2", "--namespace", "ns-1", "--image", "kperf", "--drain", "0s", "--output", outputDir}
+			if channel {
+				args = append(args, "--channel")
+			} else {
+				args = append(args, "--bootstrap-servers", "kafka:9092")
+			}
+			_, err := testutil.ExecuteCommand(NewEventingKafkaCommand(p), args...)
+			assert.NilError(t, err)
+
+			matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+KafkaOutputFilename+".csv"))
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(matches))
+			data, err := ioutil.ReadFile(matches[0])
+			assert.NilError(t, err)
+			assert.Equal(t, "name,namespace,ready,produced,delivered,consumer_lag\n"+
+				"kperf-kafka-0,ns-1,4.000000,2,2,0\nkperf-kafka-1,ns-1,4.000000,2,1,1\n", string(data))
+
+			// the benchmark resources are deleted after the measurement
+			sourceGVR := KafkaSourceGVR
+			if channel {
+				sourceGVR = KafkaChannelGVR
+			}
+			_, err = fakeDynamic.Tracker().Get(sourceGVR, "ns-1", "kperf-kafka-0")
+			assert.ErrorContains(t, err, "not found")
+			jobs, err := p.ClientSet.BatchV1().Jobs("ns-1").List(context.Background(), metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, 0, len(jobs.Items))
+		})
+	}
+}
+
+func TestSummarizeKafka(t *testing.T) {
+	sources := []pkg.KafkaSourceMeasurement{{Name: "kperf-kafka-0", Produced: 3}, {Name: "kperf-kafka-1", Produced: 1}}
+	deliveries := []kafkaDelivery{
+		{source: "kperf-kafka-0", id: "1", latency: 1, received: time.Unix(10, 0)},
+		{source: "kperf-kafka-0", id: "1", latency: 4, received: time.Unix(12, 0)},
+		{source: "kperf-kafka-0", id: "2", latency: 2, received: time.Unix(11, 0)},
+		{source: "kperf-kafka-1", id: "1", latency: 3, received: time.Unix(14, 0)},
+	}
+	result := summarizeKafka(sources, deliveries)
+	assert.Equal(t, 4, result.Produced)
+	assert.Equal(t, 3, result.Delivered)
+	assert.Equal(t, 1, result.ConsumerLag)
+	assert.Equal(t, 1, result.Sources[0].ConsumerLag)
+	assert.Equal(t, 0, result.Sources[1].ConsumerLag)
+	assert.Equal(t, 0.75, result.Throughput)
+	assert.Equal(t, 1, result.Latency.Duplicates)
+	assert.Equal(t, 3.0, result.Latency.Max)
+}
+
+func TestParseProducerLog(t *testing.T) {
+	_, ok := parseProducerLog("500 records sent, 100.0 records/sec (0.01 MB/sec), 2.1 ms avg latency, 150.0 ms max latency.\n")
+	assert.Check(t, !ok, "producer should not be finished")
+
+	produced, ok := parseProducerLog(strings.Join([]string{
+		"500 records sent, 100.0 records/sec (0.01 MB/sec), 2.1 ms avg latency, 150.0 ms max latency.",
+		"6000 records sent, 99.983336 records/sec (0.01 MB/sec), 2.45 ms avg latency, 240.00 ms max latency, 2 ms 50th, 4 ms 95th, 10 ms 99th, 30 ms 99.9th.",
+	}, "\n"))
+	assert.Check(t, ok)
+	assert.Equal(t, 6000, produced)
+}
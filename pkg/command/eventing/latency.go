@@ -0,0 +1,460 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	LatencyOutputFilename = "eventing_latency"
+
+	// latencyName is the name of the Broker or Channel and of the Trigger or Subscription of the benchmark
+	latencyName     = "kperf-latency"
+	latencySender   = "kperf-latency-sender"
+	latencyReceiver = "kperf-latency-receiver"
+
+	// latencyPollInterval is the interval to poll the readiness of the resources and the sender logs
+	latencyPollInterval = 2 * time.Second
+)
+
+var (
+	ChannelGVR      = schema.GroupVersionResource{Group: "messaging.knative.dev", Version: "v1", Resource: "channels"}
+	SubscriptionGVR = schema.GroupVersionResource{Group: "messaging.knative.dev", Version: "v1", Resource: "subscriptions"}
+
+	// readPodLogs returns the logs of the user containers of the pods of a Knative Service
+	readPodLogs = func(ctx context.Context, client kubernetes.Interface, ns, svc string) (string, error) {
+		selector := labels.SelectorFromSet(labels.Set{serving.ServiceLabelKey: svc}).String()
+		podList, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return "", err
+		}
+		logs := strings.Builder{}
+		for _, pod := range podList.Items {
+			data, err := client.CoreV1().Pods(ns).GetLogs(pod.Name, &corev1.PodLogOptions{Container: "user-container"}).DoRaw(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to read logs of pod %s: %w", pod.Name, err)
+			}
+			logs.Write(data)
+		}
+		return logs.String(), nil
+	}
+)
+
+func NewEventingLatencyCommand(p *pkg.PerfParams) *cobra.Command {
+	latencyArgs := pkg.EventingLatencyArgs{}
+	latencyCommand := &cobra.Command{
+		Use:   "latency",
+		Short: "Measure the event delivery latency of Knative Eventing",
+		Long: `Measure the end-to-end delivery latency of CloudEvents sent through a Broker or a Channel
+
+A sender and a receiver Knative Service are deployed with the kperf image given by --image. The sender pushes
+events at the given rate through a Broker and Trigger, or a Channel and Subscription with --channel, to the
+receiver, which logs the latency of every event. The latency percentiles and the dropped events are read from
+the logs. The latency is computed from the clocks of the sender and the receiver nodes.
+
+For example:
+# To send 100 events per second for 1 minute through a Broker in namespace ns
+kperf eventing latency --namespace ns --image ko.local/kperf --rate 100 --duration 1m
+
+# To send the events through an in-memory Channel instead
+kperf eventing latency --namespace ns --image ko.local/kperf --channel
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'eventing latency' requires flag(s)")
+			}
+			if latencyArgs.Image == "" {
+				return fmt.Errorf("--image is required to deploy the sender and the receiver")
+			}
+			if latencyArgs.Rate < 1 {
+				return fmt.Errorf("rate must be at least 1")
+			}
+			if latencyArgs.Duration <= 0 {
+				return fmt.Errorf("duration must be greater than 0")
+			}
+			return pkg.ValidateRunID(latencyArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureEventLatency(p, latencyArgs)
+		},
+	}
+
+	latencyCommand.Flags().StringVarP(&latencyArgs.Namespace, "namespace", "", DefaultNamespace, "Namespace to deploy the benchmark to")
+	latencyCommand.Flags().StringVarP(&latencyArgs.Image, "image", "", "", "kperf image run by the sender and the receiver, e.g. built with 'ko build ./cmd/kperf'")
+	latencyCommand.Flags().StringVarP(&latencyArgs.BrokerClass, "broker-class", "", DefaultBrokerClass, "Broker class")
+	latencyCommand.Flags().BoolVarP(&latencyArgs.Channel, "channel", "", false, "Send the events through a Channel and Subscription instead of a Broker and Trigger")
+	latencyCommand.Flags().IntVarP(&latencyArgs.Rate, "rate", "", 10, "Events sent per second")
+	latencyCommand.Flags().DurationVarP(&latencyArgs.Duration, "duration", "", time.Minute, "Duration to send events for")
+	latencyCommand.Flags().DurationVarP(&latencyArgs.Drain, "drain", "", 10*time.Second, "Time to wait for events in flight after the sender finished")
+	latencyCommand.Flags().DurationVarP(&latencyArgs.Timeout, "timeout", "", 5*time.Minute, "Timeout for the benchmark resources to become ready")
+	latencyCommand.Flags().BoolVarP(&latencyArgs.Keep, "keep", "", false, "Keep the benchmark resources after the measurement")
+	latencyCommand.Flags().BoolVarP(&latencyArgs.Verbose, "verbose", "v", false, "Event verbose result")
+	latencyCommand.Flags().StringVarP(&latencyArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(latencyCommand.Flags(), &latencyArgs.OutputFormats)
+	latencyCommand.Flags().StringVarP(&latencyArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	return latencyCommand
+}
+
+// MeasureEventLatency deploys the sender, the receiver and a Broker or Channel between them, and measures the
+// delivery latency of the events sent
+func MeasureEventLatency(params *pkg.PerfParams, inputs pkg.EventingLatencyArgs) error {
+	ctx := context.Background()
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client %s\n", err)
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return fmt.Errorf("failed to create serving client %s\n", err)
+	}
+	ns := inputs.Namespace
+
+	var deletions []func() error
+	if !inputs.Keep {
+		defer func() {
+			for i := len(deletions) - 1; i >= 0; i-- {
+				if err := deletions[i](); err != nil && !apierrors.IsNotFound(err) {
+					fmt.Printf("failed to delete benchmark resource and skip %s\n", err)
+				}
+			}
+		}()
+	}
+	deleteResource := func(gvr schema.GroupVersionResource, name string) func() error {
+		return func() error {
+			return dynamicClient.Resource(gvr).Namespace(ns).Delete(context.Background(), name, metav1.DeleteOptions{})
+		}
+	}
+	deleteService := func(name string) func() error {
+		return func() error {
+			return ksvcClient.Services(ns).Delete(context.Background(), name, metav1.DeleteOptions{})
+		}
+	}
+
+	fmt.Printf("Creating receiver Knative Service %s in namespace %s\n", latencyReceiver, ns)
+	if err := createLatencyService(ctx, ksvcClient, ns, latencyReceiver, inputs.Image, []string{"eventing", "latency-receiver"}); err != nil {
+		return err
+	}
+	deletions = append(deletions, deleteService(latencyReceiver))
+
+	sinkGVR, subscriptionGVR := BrokerGVR, TriggerGVR
+	if inputs.Channel {
+		sinkGVR, subscriptionGVR = ChannelGVR, SubscriptionGVR
+		fmt.Printf("Creating Channel %s with Subscription %s in namespace %s\n", latencyName, latencyName, ns)
+		err = createLatencyChannel(ctx, dynamicClient, ns)
+	} else {
+		fmt.Printf("Creating Broker %s with Trigger %s in namespace %s\n", latencyName, latencyName, ns)
+		err = createLatencyBroker(ctx, dynamicClient, ns, inputs.BrokerClass)
+	}
+	deletions = append(deletions, deleteResource(sinkGVR, latencyName), deleteResource(subscriptionGVR, latencyName))
+	if err != nil {
+		return err
+	}
+
+	var sink string
+	err = wait.PollImmediate(latencyPollInterval, inputs.Timeout, func() (bool, error) {
+		if ready, err := latencyServiceReady(ctx, ksvcClient, ns, latencyReceiver); err != nil || !ready {
+			return false, err
+		}
+		if ready, err := latencyResourceReady(ctx, dynamicClient, subscriptionGVR, ns); err != nil || !ready {
+			return false, err
+		}
+		obj, err := dynamicClient.Resource(sinkGVR).Namespace(ns).Get(ctx, latencyName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if _, ok := getConditionTime(obj, conditionReady); !ok {
+			return false, nil
+		}
+		sink, _, _ = unstructured.NestedString(obj.Object, "status", "address", "url")
+		return sink != "", nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for the receiver and %s %s to be ready: %s", strings.TrimSuffix(sinkGVR.Resource, "s"), latencyName, err)
+	}
+
+	fmt.Printf("Creating sender Knative Service %s in namespace %s, sending %d event(s) per second for %s to %s\n",
+		latencySender, ns, inputs.Rate, inputs.Duration, sink)
+	err = createLatencyService(ctx, ksvcClient, ns, latencySender, inputs.Image, []string{"eventing", "latency-sender",
+		"--sink", sink, "--rate", strconv.Itoa(inputs.Rate), "--duration", inputs.Duration.String()})
+	if err != nil {
+		return err
+	}
+	deletions = append(deletions, deleteService(latencySender))
+
+	var sent, sendErrors int
+	err = wait.PollImmediate(latencyPollInterval, inputs.Duration+inputs.Timeout, func() (bool, error) {
+		logs, err := readPodLogs(ctx, params.ClientSet, ns, latencySender)
+		if err != nil {
+			return false, nil
+		}
+		var ok bool
+		sent, sendErrors, ok = parseSentLog(logs)
+		return ok, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for the sender to finish: %s", err)
+	}
+	fmt.Printf("Sender finished, waiting %s for events in flight\n", inputs.Drain)
+	time.Sleep(inputs.Drain)
+
+	logs, err := readPodLogs(ctx, params.ClientSet, ns, latencyReceiver)
+	if err != nil {
+		return fmt.Errorf("failed to read the receiver logs: %s", err)
+	}
+	received := parseReceivedLogs(logs)
+	result := summarizeLatency(sent, sendErrors, received)
+	result.KnativeInfo = measure.GetKnativeInfo(ctx, params, measure.DefaultLogger)
+
+	ids := make([]string, 0, len(received))
+	for id := range received {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+	rows := [][]string{{"event_id", "latency", "deliveries"}}
+	for _, id := range ids {
+		rows = append(rows, []string{id, fmt.Sprintf("%f", received[id][0]), strconv.Itoa(len(received[id]))})
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Event %s: Delivery Latency is %fs, delivered %d time(s)\n", id, received[id][0], len(received[id]))
+		}
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Event Delivery Measurement:\n")
+	fmt.Printf("Sent: %d | Received: %d Dropped: %d Duplicates: %d SendErrors: %d\n", result.Sent, result.Received,
+		result.Dropped, result.Duplicates, result.SendErrors)
+	fmt.Printf("Min: %fs\n", result.Min)
+	fmt.Printf("Mean: %fs\n", result.Mean)
+	fmt.Printf("Percentile50: %fs\n", result.P50)
+	fmt.Printf("Percentile90: %fs\n", result.P90)
+	fmt.Printf("Percentile95: %fs\n", result.P95)
+	fmt.Printf("Percentile99: %fs\n", result.P99)
+	fmt.Printf("Max: %fs\n", result.Max)
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    LatencyOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// createLatencyService creates a Knative Service running the kperf image with the args. It is kept at a
+// single replica, so that no logs are lost to a scale down.
+func createLatencyService(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, ns, name, image string, args []string) error {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	svc.Spec.Template.Annotations = map[string]string{
+		autoscaling.MinScaleAnnotationKey: "1",
+		autoscaling.MaxScaleAnnotationKey: "1",
+	}
+	svc.Spec.Template.Spec.Containers = []corev1.Container{{Image: image, Args: args}}
+	if _, err := ksvcClient.Services(ns).Create(ctx, svc, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create Knative Service %s in namespace %s: %s", name, ns, err)
+	}
+	return nil
+}
+
+func createLatencyBroker(ctx context.Context, client dynamic.Interface, ns, class string) error {
+	if err := createBroker(client, ns, latencyName, class, 0, ""); err != nil {
+		return fmt.Errorf("failed to create Broker %s in namespace %s: %s", latencyName, ns, err)
+	}
+	trigger := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": TriggerGVR.GroupVersion().String(),
+		"kind":       "Trigger",
+		"metadata": map[string]interface{}{
+			"name":      latencyName,
+			"namespace": ns,
+		},
+		"spec": map[string]interface{}{
+			"broker": latencyName,
+			"filter": map[string]interface{}{
+				"attributes": map[string]interface{}{"type": latencyEventType},
+			},
+			"subscriber": latencySubscriber(),
+		},
+	}}
+	if _, err := client.Resource(TriggerGVR).Namespace(ns).Create(ctx, trigger, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create Trigger %s in namespace %s: %s", latencyName, ns, err)
+	}
+	return nil
+}
+
+func createLatencyChannel(ctx context.Context, client dynamic.Interface, ns string) error {
+	channel := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ChannelGVR.GroupVersion().String(),
+		"kind":       "Channel",
+		"metadata": map[string]interface{}{
+			"name":      latencyName,
+			"namespace": ns,
+		},
+	}}
+	if _, err := client.Resource(ChannelGVR).Namespace(ns).Create(ctx, channel, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create Channel %s in namespace %s: %s", latencyName, ns, err)
+	}
+	subscription := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": SubscriptionGVR.GroupVersion().String(),
+		"kind":       "Subscription",
+		"metadata": map[string]interface{}{
+			"name":      latencyName,
+			"namespace": ns,
+		},
+		"spec": map[string]interface{}{
+			"channel": map[string]interface{}{
+				"apiVersion": ChannelGVR.GroupVersion().String(),
+				"kind":       "Channel",
+				"name":       latencyName,
+			},
+			"subscriber": latencySubscriber(),
+		},
+	}}
+	if _, err := client.Resource(SubscriptionGVR).Namespace(ns).Create(ctx, subscription, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create Subscription %s in namespace %s: %s", latencyName, ns, err)
+	}
+	return nil
+}
+
+func latencySubscriber() map[string]interface{} {
+	return map[string]interface{}{
+		"ref": map[string]interface{}{
+			"apiVersion": servingv1.SchemeGroupVersion.String(),
+			"kind":       "Service",
+			"name":       latencyReceiver,
+		},
+	}
+}
+
+func latencyServiceReady(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, ns, name string) (bool, error) {
+	svc, err := ksvcClient.Services(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return svc.IsReady(), nil
+}
+
+func latencyResourceReady(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, ns string) (bool, error) {
+	obj, err := client.Resource(gvr).Namespace(ns).Get(ctx, latencyName, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	_, ok := getConditionTime(obj, conditionReady)
+	return ok, nil
+}
+
+// parseSentLog returns the number of sent and failed events logged by the sender when it finished.
+// The last return value is false if the sender didn't finish yet.
+func parseSentLog(logs string) (int, int, bool) {
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.HasPrefix(line, latencySentLog+" ") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, latencySentLog))
+		if len(fields) != 2 {
+			continue
+		}
+		sent, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		failed, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		return sent, failed, true
+	}
+	return 0, 0, false
+}
+
+// parseReceivedLogs returns the latencies logged by the receiver by event id. An event delivered more than
+// once has a latency for every delivery.
+func parseReceivedLogs(logs string) map[string][]float64 {
+	received := map[string][]float64{}
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.HasPrefix(line, latencyReceivedLog+" ") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, latencyReceivedLog))
+		if len(fields) != 2 {
+			continue
+		}
+		latency, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		received[fields[0]] = append(received[fields[0]], latency)
+	}
+	return received
+}
+
+// summarizeLatency computes the latency percentiles of the first delivery of every event, and the dropped
+// and duplicated events. Events the sender failed to send are not counted as dropped.
+func summarizeLatency(sent, sendErrors int, received map[string][]float64) pkg.EventingLatencyResult {
+	result := pkg.EventingLatencyResult{Sent: sent, SendErrors: sendErrors, Received: len(received)}
+	latencies := make([]float64, 0, len(received))
+	for _, l := range received {
+		latencies = append(latencies, l[0])
+		result.Duplicates += len(l) - 1
+	}
+	if dropped := sent - sendErrors - len(received); dropped > 0 {
+		result.Dropped = dropped
+	}
+	if len(latencies) > 0 {
+		result.Min, _ = stats.Min(latencies)
+		result.Mean, _ = stats.Mean(latencies)
+		result.P50, _ = stats.Percentile(latencies, 50)
+		result.P90, _ = stats.Percentile(latencies, 90)
+		result.P95, _ = stats.Percentile(latencies, 95)
+		result.P99, _ = stats.Percentile(latencies, 99)
+		result.Max, _ = stats.Max(latencies)
+	}
+	return result
+}
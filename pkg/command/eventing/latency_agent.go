@@ -0,0 +1,168 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	latencyEventType   = "dev.knative.kperf.latency"
+	latencyEventSource = "kperf-latency-sender"
+
+	// latencySentLog and latencyReceivedLog start the log lines of the sender and the receiver which are
+	// read by 'eventing latency'
+	latencySentLog     = "kperf-latency sent"
+	latencyReceivedLog = "kperf-latency received"
+)
+
+// NewEventingLatencySenderCommand runs in the sender Knative Service deployed by 'eventing latency'
+func NewEventingLatencySenderCommand() *cobra.Command {
+	var (
+		sink     string
+		source   string
+		rate     int
+		duration time.Duration
+	)
+	senderCommand := &cobra.Command{
+		Use:    "latency-sender",
+		Short:  "Send CloudEvents for 'eventing latency'",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rate < 1 {
+				return fmt.Errorf("rate must be at least 1")
+			}
+			go func() {
+				sent, failed := sendLatencyEvents(&http.Client{Timeout: 30 * time.Second}, sink, source, rate, duration, cmd.OutOrStdout())
+				fmt.Fprintf(cmd.OutOrStdout(), "%s %d %d\n", latencySentLog, sent, failed)
+			}()
+			// the sender only answers the probes of Knative
+			return http.ListenAndServe(":"+agentPort(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+		},
+	}
+	senderCommand.Flags().StringVarP(&sink, "sink", "", "", "URL to send the events to")
+	senderCommand.Flags().StringVarP(&source, "source", "", latencyEventSource, "CloudEvents source of the events")
+	senderCommand.Flags().IntVarP(&rate, "rate", "", 10, "Events sent per second")
+	senderCommand.Flags().DurationVarP(&duration, "duration", "", time.Minute, "Duration to send events for")
+	return senderCommand
+}
+
+// NewEventingLatencyReceiverCommand runs in the receiver Knative Service deployed by 'eventing latency'
+func NewEventingLatencyReceiverCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    "latency-receiver",
+		Short:  "Receive CloudEvents for 'eventing latency'",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return http.ListenAndServe(":"+agentPort(), latencyReceiverHandler(cmd.OutOrStdout(), time.Now))
+		},
+	}
+}
+
+// agentPort returns the port Knative expects the container to listen on
+func agentPort() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return port
+	}
+	return "8080"
+}
+
+// sendLatencyEvents sends rate events per second to the sink for the duration and returns the number of sent
+// and failed events. The events carry their send time in the CloudEvents time attribute.
+func sendLatencyEvents(client *http.Client, sink, source string, rate int, duration time.Duration, log io.Writer) (int, int) {
+	var (
+		m            sync.Mutex
+		sent, failed int
+	)
+	group := sync.WaitGroup{}
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+	deadline := time.After(duration)
+	for sequence := 1; ; sequence++ {
+		select {
+		case <-deadline:
+			group.Wait()
+			return sent, failed
+		case <-ticker.C:
+		}
+		group.Add(1)
+		go func(sequence int) {
+			defer group.Done()
+			err := sendLatencyEvent(client, sink, source, sequence)
+			m.Lock()
+			defer m.Unlock()
+			sent++
+			if err != nil {
+				failed++
+				fmt.Fprintf(log, "failed to send event %d: %s\n", sequence, err)
+			}
+		}(sequence)
+	}
+}
+
+// sendLatencyEvent sends a single CloudEvent in binary content mode
+func sendLatencyEvent(client *http.Client, sink, source string, sequence int) error {
+	req, err := http.NewRequest(http.MethodPost, sink, strings.NewReader(fmt.Sprintf(`{"sequence":%d}`, sequence)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", strconv.Itoa(sequence))
+	req.Header.Set("Ce-Source", source)
+	req.Header.Set("Ce-Type", latencyEventType)
+	req.Header.Set("Ce-Time", time.Now().UTC().Format(time.RFC3339Nano))
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// latencyReceiverHandler logs the id and the delivery latency of every received latency event
+func latencyReceiverHandler(log io.Writer, now func() time.Time) http.HandlerFunc {
+	var m sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Ce-Type") != latencyEventType {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		sent, err := time.Parse(time.RFC3339Nano, r.Header.Get("Ce-Time"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid event time: %s", err), http.StatusBadRequest)
+			return
+		}
+		latency := now().Sub(sent)
+		m.Lock()
+		fmt.Fprintf(log, "%s %s %f\n", latencyReceivedLog, r.Header.Get("Ce-Id"), latency.Seconds())
+		m.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
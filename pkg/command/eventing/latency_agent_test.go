@@ -0,0 +1,82 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSendLatencyEvents(t *testing.T) {
+	var (
+		m     sync.Mutex
+		types []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		defer m.Unlock()
+		types = append(types, r.Header.Get("Ce-Type"))
+		if len(types) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	log := &bytes.Buffer{}
+	sent, failed := sendLatencyEvents(server.Client(), server.URL, latencyEventSource, 100, 55*time.Millisecond, log)
+	assert.Assert(t, sent > 0)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, sent, len(types))
+	assert.Equal(t, latencyEventType, types[0])
+	assert.Assert(t, bytes.Contains(log.Bytes(), []byte("unexpected response status 500 Internal Server Error")))
+}
+
+func TestLatencyReceiverHandler(t *testing.T) {
+	sent := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	log := &bytes.Buffer{}
+	handler := latencyReceiverHandler(log, func() time.Time { return sent.Add(250 * time.Millisecond) })
+
+	newRequest := func(eventType, eventTime string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Ce-Id", "7")
+		req.Header.Set("Ce-Type", eventType)
+		req.Header.Set("Ce-Time", eventTime)
+		return req
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, newRequest(latencyEventType, sent.Format(time.RFC3339Nano)))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, latencyReceivedLog+" 7 0.250000\n", log.String())
+
+	recorder = httptest.NewRecorder()
+	handler(recorder, newRequest(latencyEventType, "yesterday"))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// probes and other events are not logged
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	handler(recorder, newRequest("dev.knative.other", sent.Format(time.RFC3339Nano)))
+	assert.Equal(t, latencyReceivedLog+" 7 0.250000\n", log.String())
+}
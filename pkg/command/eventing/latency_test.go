@@ -0,0 +1,135 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewEventingLatencyCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for eventing latency", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"ns-1"})
+
+		_, err := testutil.ExecuteCommand(NewEventingLatencyCommand(p))
+		assert.ErrorContains(t, err, "'eventing latency' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewEventingLatencyCommand(p), "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "--image is required to deploy the sender and the receiver")
+
+		_, err = testutil.ExecuteCommand(NewEventingLatencyCommand(p), "--image", "kperf", "--rate", "0")
+		assert.ErrorContains(t, err, "rate must be at least 1")
+
+		_, err = testutil.ExecuteCommand(NewEventingLatencyCommand(p), "--image", "kperf", "--duration", "0s")
+		assert.ErrorContains(t, err, "duration must be greater than 0")
+	})
+
+	for _, channel := range []bool{false, true} {
+		channel := channel
+		name := "measure latency through a broker"
+		if channel {
+			name = "measure latency through a channel"
+		}
+		t.Run(name, func(t *testing.T) {
+			p, fakeDynamic := newTestPerfParams([]string{"ns-1"})
+			p.ClientSet.(*k8sfake.Clientset).PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				svc := &servingv1.Service{}
+				svc.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+				return true, svc, nil
+			})
+			for _, resource := range []string{"brokers", "triggers", "channels", "subscriptions"} {
+				fakeDynamic.PrependReactor("get", resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+					obj := newTestBroker("ns-1", latencyName, readyCondition(conditionReady, "2022-01-01T00:00:10Z"))
+					unstructured.SetNestedField(obj.Object, "http://sink.ns-1.svc.cluster.local", "status", "address", "url")
+					return true, obj, nil
+				})
+			}
+			restore := readPodLogs
+			defer func() { readPodLogs = restore }()
+			readPodLogs = func(ctx context.Context, client kubernetes.Interface, ns, svc string) (string, error) {
+				if svc == latencySender {
+					return latencySentLog + " 3 0\n", nil
+				}
+				return latencyReceivedLog + " 1 0.100000\n" + latencyReceivedLog + " 2 0.200000\n" + latencyReceivedLog + " 2 0.300000\n", nil
+			}
+
+			outputDir := t.TempDir()
+			args := []string{"--namespace", "ns-1", "--image", "kperf", "--drain", "0s", "--output", outputDir}
+			if channel {
+				args = append(args, "--channel")
+			}
+			_, err := testutil.ExecuteCommand(NewEventingLatencyCommand(p), args...)
+			assert.NilError(t, err)
+
+			matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+LatencyOutputFilename+".csv"))
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(matches))
+
+			// the benchmark resources are deleted after the measurement
+			_, err = p.ClientSet.(*k8sfake.Clientset).Tracker().Get(servingv1.SchemeGroupVersion.WithResource("services"), "ns-1", latencySender)
+			assert.ErrorContains(t, err, "not found")
+			sinkGVR := BrokerGVR
+			if channel {
+				sinkGVR = ChannelGVR
+			}
+			_, err = fakeDynamic.Tracker().Get(sinkGVR, "ns-1", latencyName)
+			assert.ErrorContains(t, err, "not found")
+		})
+	}
+}
+
+func TestParseSentLog(t *testing.T) {
+	_, _, ok := parseSentLog("failed to send event 1: timeout\n")
+	assert.Check(t, !ok, "sender should not be finished")
+
+	sent, failed, ok := parseSentLog("failed to send event 1: timeout\n" + latencySentLog + " 600 1\n")
+	assert.Check(t, ok)
+	assert.Equal(t, 600, sent)
+	assert.Equal(t, 1, failed)
+}
+
+func TestParseReceivedLogs(t *testing.T) {
+	received := parseReceivedLogs(latencyReceivedLog + " 1 0.100000\n" + latencyReceivedLog + " 1 0.500000\n" +
+		latencyReceivedLog + " 2 x\nunrelated line\n")
+	assert.DeepEqual(t, map[string][]float64{"1": {0.1, 0.5}}, received)
+}
+
+func TestSummarizeLatency(t *testing.T) {
+	result := summarizeLatency(5, 1, map[string][]float64{"1": {1, 5}, "2": {2}, "3": {3}})
+	assert.Equal(t, 3, result.Received)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.Equal(t, 1, result.Dropped)
+	assert.Equal(t, 1.0, result.Min)
+	assert.Equal(t, 3.0, result.Max)
+	assert.Equal(t, 2.0, result.Mean)
+
+	result = summarizeLatency(0, 0, map[string][]float64{})
+	assert.Equal(t, 0, result.Dropped)
+	assert.Equal(t, 0.0, result.P99)
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
+)
+
+// MeasureOutputFilename is the name of the files the measurement of the Brokers and Triggers is written to
+const MeasureOutputFilename = "broker_creation_time"
+
+func NewEventingMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	measureArgs := pkg.EventingMeasureArgs{}
+	measureCommand := &cobra.Command{
+		Use:   "measure",
+		Short: "Measure Knative Eventing Brokers and Triggers",
+		Long: `Measure Knative Eventing Broker and Trigger creation time
+
+For example:
+# To measure the Brokers with prefix broker and their Triggers in namespace ns
+kperf eventing measure --broker-prefix broker --namespace ns
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'eventing measure' requires flag(s)")
+			}
+			return pkg.ValidateRunID(measureArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureBrokers(p, measureArgs)
+		},
+	}
+
+	measureCommand.Flags().StringVarP(&measureArgs.Namespace, "namespace", "", "", "Broker namespace")
+	measureCommand.Flags().StringVarP(&measureArgs.BrokerPrefix, "broker-prefix", "", "broker", "Broker name prefix")
+	measureCommand.Flags().BoolVarP(&measureArgs.Verbose, "verbose", "v", false, "Broker verbose result")
+	measureCommand.Flags().StringVarP(&measureArgs.NamespaceRange, "namespace-range", "", "", "Broker namespace range")
+	measureCommand.Flags().StringVarP(&measureArgs.NamespacePrefix, "namespace-prefix", "", "", "Broker namespace prefix")
+	measureCommand.Flags().StringVarP(&measureArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(measureCommand.Flags(), &measureArgs.OutputFormats)
+	measureCommand.Flags().StringVarP(&measureArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	return measureCommand
+}
+
+// MeasureBrokers used to measure the time until Brokers, their Triggers and the Trigger subscribers are ready
+func MeasureBrokers(params *pkg.PerfParams, inputs pkg.EventingMeasureArgs) error {
+	ctx := context.Background()
+	nsNameList, err := service.GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := params.NewDynamicClient()
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client %s\n", err)
+	}
+
+	brokers := []unstructured.Unstructured{}
+	triggers := map[string][]unstructured.Unstructured{}
+	for _, ns := range nsNameList {
+		brokerList, err := dynamicClient.Resource(BrokerGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list brokers under namespace %s error:%v", ns, err)
+		}
+		for _, b := range brokerList.Items {
+			if strings.HasPrefix(b.GetName(), inputs.BrokerPrefix) {
+				brokers = append(brokers, b)
+			}
+		}
+		triggerList, err := dynamicClient.Resource(TriggerGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list triggers under namespace %s error:%v", ns, err)
+		}
+		for _, t := range triggerList.Items {
+			key := ns + "/" + triggerBroker(&t)
+			triggers[key] = append(triggers[key], t)
+		}
+	}
+	if len(brokers) == 0 {
+		return errors.New("no broker found to measure")
+	}
+
+	result := pkg.EventingMeasureResult{}
+	rows := make([][]string, 0)
+	rawRows := make([][]string, 0)
+	for i := range brokers {
+		broker := &brokers[i]
+		brokerCreated := broker.GetCreationTimestamp().Time
+		brokerReady, ok := getConditionTime(broker, conditionReady)
+		if !ok {
+			fmt.Printf("broker %s/%s not ready and skip measuring\n", broker.GetNamespace(), broker.GetName())
+			result.Broker.NotReadyCount++
+			result.Trigger.NotReadyCount += len(triggers[broker.GetNamespace()+"/"+broker.GetName()])
+			continue
+		}
+		brokerReadyDuration := brokerReady.Sub(brokerCreated)
+		result.Broker.ReadyCount++
+		result.Sums.BrokerReadySum += brokerReadyDuration.Seconds()
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Broker %s: Broker Ready Duration is %s/%fs\n", broker.GetName(), brokerReadyDuration, brokerReadyDuration.Seconds())
+		}
+
+		for j := range triggers[broker.GetNamespace()+"/"+broker.GetName()] {
+			trigger := &triggers[broker.GetNamespace()+"/"+broker.GetName()][j]
+			triggerCreated := trigger.GetCreationTimestamp().Time
+			triggerReady, ready := getConditionTime(trigger, conditionReady)
+			subscriberResolved, resolved := getConditionTime(trigger, conditionSubscriberResolved)
+			subscriptionReady, subscribed := getConditionTime(trigger, conditionSubscriptionReady)
+			if !ready || !resolved || !subscribed {
+				fmt.Printf("trigger %s/%s not ready and skip measuring\n", trigger.GetNamespace(), trigger.GetName())
+				result.Trigger.NotReadyCount++
+				continue
+			}
+			triggerReadyDuration := triggerReady.Sub(triggerCreated)
+			subscriberResolvedDuration := subscriberResolved.Sub(triggerCreated)
+			subscriptionReadyDuration := subscriptionReady.Sub(triggerCreated)
+
+			result.Trigger.ReadyCount++
+			result.Sums.TriggerReadySum += triggerReadyDuration.Seconds()
+			result.Sums.TriggerSubscriberResolvedSum += subscriberResolvedDuration.Seconds()
+			result.Sums.TriggerSubscriptionReadySum += subscriptionReadyDuration.Seconds()
+			result.TriggerReadyTime = append(result.TriggerReadyTime, triggerReadyDuration.Seconds())
+
+			rows = append(rows, []string{trigger.GetName(), trigger.GetNamespace(),
+				fmt.Sprintf("%d", int(brokerReadyDuration.Seconds())),
+				fmt.Sprintf("%d", int(triggerReadyDuration.Seconds())),
+				fmt.Sprintf("%d", int(subscriberResolvedDuration.Seconds())),
+				fmt.Sprintf("%d", int(subscriptionReadyDuration.Seconds())),
+			})
+			rawRows = append(rawRows, []string{trigger.GetName(), trigger.GetNamespace(), broker.GetName(),
+				broker.GetCreationTimestamp().String(),
+				brokerReady.String(),
+				trigger.GetCreationTimestamp().String(),
+				subscriberResolved.String(),
+				subscriptionReady.String(),
+				triggerReady.String(),
+			})
+
+			if inputs.Verbose {
+				fmt.Printf("[Verbose] Trigger %s: Trigger Ready Duration is %s/%fs\n", trigger.GetName(), triggerReadyDuration, triggerReadyDuration.Seconds())
+				fmt.Printf("[Verbose] Trigger %s: - Trigger Subscriber Resolved Duration is %s/%fs\n", trigger.GetName(), subscriberResolvedDuration, subscriberResolvedDuration.Seconds())
+				fmt.Printf("[Verbose] Trigger %s: - Trigger Subscription Ready Duration is %s/%fs\n", trigger.GetName(), subscriptionReadyDuration, subscriptionReadyDuration.Seconds())
+			}
+		}
+	}
+
+	sortRows(rows)
+	sortRows(rawRows)
+	rows = append([][]string{{"trigger_name", "trigger_namespace", "broker_ready", "trigger_ready",
+		"trigger_subscriber_resolved", "trigger_subscription_ready"}}, rows...)
+	rawRows = append([][]string{{"trigger_name", "trigger_namespace", "broker_name",
+		"broker_created",
+		"broker_ready",
+		"trigger_created",
+		"trigger_subscriber_resolved",
+		"trigger_subscription_ready",
+		"trigger_ready"}}, rawRows...)
+
+	result.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	brokerTotal := result.Broker.ReadyCount + result.Broker.NotReadyCount
+	triggerTotal := result.Trigger.ReadyCount + result.Trigger.NotReadyCount
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Basic Information:\n")
+	fmt.Printf("  - Knative Versions:\n")
+	fmt.Printf("    Serving: %v\n", result.KnativeInfo.ServingVersion)
+	fmt.Printf("    Eventing: %v\n", result.KnativeInfo.EventingVersion)
+	fmt.Printf("Brokers Total: %d | Ready: %d NotReady: %d\n", brokerTotal, result.Broker.ReadyCount, result.Broker.NotReadyCount)
+	fmt.Printf("Triggers Total: %d | Ready: %d NotReady: %d\n", triggerTotal, result.Trigger.ReadyCount, result.Trigger.NotReadyCount)
+	if result.Broker.ReadyCount > 0 {
+		result.Result.AverageBrokerReady = result.Sums.BrokerReadySum / float64(result.Broker.ReadyCount)
+		fmt.Printf("Broker Ready Duration:\n")
+		fmt.Printf("Total: %fs\n", result.Sums.BrokerReadySum)
+		fmt.Printf("Average: %fs\n", result.Result.AverageBrokerReady)
+	}
+	if result.Trigger.ReadyCount == 0 {
+		return nil
+	}
+
+	result.Result.AverageTriggerReady = result.Sums.TriggerReadySum / float64(result.Trigger.ReadyCount)
+	result.Result.AverageTriggerSubscriberResolved = result.Sums.TriggerSubscriberResolvedSum / float64(result.Trigger.ReadyCount)
+	result.Result.AverageTriggerSubscriptionReady = result.Sums.TriggerSubscriptionReadySum / float64(result.Trigger.ReadyCount)
+	fmt.Printf("\nTrigger Ready Duration:\n")
+	fmt.Printf("Total: %fs\n", result.Sums.TriggerReadySum)
+	fmt.Printf("Average: %fs\n", result.Result.AverageTriggerReady)
+	fmt.Printf("- Trigger Subscriber Resolved Duration:\n")
+	fmt.Printf("  Total: %fs\n", result.Sums.TriggerSubscriberResolvedSum)
+	fmt.Printf("  Average: %fs\n", result.Result.AverageTriggerSubscriberResolved)
+	fmt.Printf("- Trigger Subscription Ready Duration:\n")
+	fmt.Printf("  Total: %fs\n", result.Sums.TriggerSubscriptionReadySum)
+	fmt.Printf("  Average: %fs\n", result.Result.AverageTriggerSubscriptionReady)
+
+	fmt.Printf("\n-----------------------------\n")
+	fmt.Printf("Overall Trigger Ready Measurement:\n")
+	result.Result.OverallTotal = result.Sums.TriggerReadySum
+	fmt.Printf("Total: %fs\n", result.Result.OverallTotal)
+	result.Result.OverallAverage = result.Result.AverageTriggerReady
+	fmt.Printf("Average: %fs\n", result.Result.OverallAverage)
+	result.Result.OverallMedian, _ = stats.Median(result.TriggerReadyTime)
+	fmt.Printf("Median: %fs\n", result.Result.OverallMedian)
+	result.Result.OverallMin, _ = stats.Min(result.TriggerReadyTime)
+	fmt.Printf("Min: %fs\n", result.Result.OverallMin)
+	result.Result.OverallMax, _ = stats.Max(result.TriggerReadyTime)
+	fmt.Printf("Max: %fs\n", result.Result.OverallMax)
+	result.Result.P50, _ = stats.Percentile(result.TriggerReadyTime, 50)
+	fmt.Printf("Percentile50: %fs\n", result.Result.P50)
+	result.Result.P90, _ = stats.Percentile(result.TriggerReadyTime, 90)
+	fmt.Printf("Percentile90: %fs\n", result.Result.P90)
+	result.Result.P95, _ = stats.Percentile(result.TriggerReadyTime, 95)
+	fmt.Printf("Percentile95: %fs\n", result.Result.P95)
+	result.Result.P98, _ = stats.Percentile(result.TriggerReadyTime, 98)
+	fmt.Printf("Percentile98: %fs\n", result.Result.P98)
+	result.Result.P99, _ = stats.Percentile(result.TriggerReadyTime, 99)
+	fmt.Printf("Percentile99: %fs\n", result.Result.P99)
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    MeasureOutputFilename,
+		Rows:    rows,
+		RawRows: rawRows,
+		Result:  result,
+		Phases:  true,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+func sortRows(rows [][]string) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i][1] != rows[j][1] {
+			return rows[i][1] < rows[j][1]
+		}
+		return rows[i][0] < rows[j][0]
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventing
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewEventingMeasureCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for eventing measure", func(t *testing.T) {
+		p, _ := newTestPerfParams([]string{"test-kperf-1"})
+		cmd := NewEventingMeasureCommand(p)
+
+		_, err := testutil.ExecuteCommand(cmd)
+		assert.ErrorContains(t, err, "'eventing measure' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(cmd, "--namespace-prefix", "test-kperf", "--namespace-range", "1")
+		assert.ErrorContains(t, err, "expected range like 1,500, given 1")
+
+		cmd = NewEventingMeasureCommand(p)
+		_, err = testutil.ExecuteCommand(cmd, "--namespace", "test-kperf-1")
+		assert.ErrorContains(t, err, "no broker found to measure")
+	})
+
+	t.Run("measure brokers and triggers as expected", func(t *testing.T) {
+		broker := newTestBroker("test-kperf-1", "broker-0", readyCondition(conditionReady, "2022-01-01T00:00:02Z"))
+		trigger := newTestTrigger("test-kperf-1", "broker-0-trigger-0", "broker-0",
+			readyCondition(conditionReady, "2022-01-01T00:00:05Z"),
+			readyCondition(conditionSubscriberResolved, "2022-01-01T00:00:03Z"),
+			readyCondition(conditionSubscriptionReady, "2022-01-01T00:00:04Z"))
+		notReadyBroker := newTestBroker("test-kperf-1", "broker-1")
+		p, _ := newTestPerfParams([]string{"test-kperf-1"}, broker, trigger, notReadyBroker)
+
+		outputDir := t.TempDir()
+		cmd := NewEventingMeasureCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "--namespace", "test-kperf-1", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+
+		for _, suffix := range []string{"raw_broker_creation_time.csv", "broker_creation_time.csv", "broker_creation_time.json", "broker_creation_time.html"} {
+			matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+suffix))
+			assert.NilError(t, err)
+			assert.Check(t, len(matches) > 0, "expected output file *_%s", suffix)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewExporterCmd(p *pkg.PerfParams) *cobra.Command {
+	var exporterCmd = &cobra.Command{
+		Use:   "exporter",
+		Short: "Run kperf in-cluster as a Prometheus exporter",
+		Long: `Run kperf in-cluster as a Prometheus exporter of the Knative Service ready latency. For example:
+
+kperf exporter install - to deploy the exporter measuring the new services of all namespaces`,
+	}
+	exporterCmd.AddCommand(NewExporterInstallCommand(p))
+
+	exporterCmd.InitDefaultHelpCmd()
+	return exporterCmd
+}
@@ -0,0 +1,366 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+)
+
+const (
+	// DefaultNamespace is the namespace the exporter is installed to
+	DefaultNamespace = "kperf-system"
+
+	exporterName  = "kperf-exporter"
+	dashboardName = "kperf-exporter-dashboard"
+	// dashboardLabel makes the Grafana sidecar load the dashboard from the ConfigMap
+	dashboardLabel = "grafana_dashboard"
+	dashboardAsset = "templates/grafana_dashboard.json"
+)
+
+// exporterResource is a resource of the exporter, which is created or updated if it already exists
+type exporterResource struct {
+	kind   string
+	name   string
+	object runtime.Object
+	create func(ctx context.Context) error
+	// update replaces the existing resource, resources without update are kept as they are
+	update func(ctx context.Context) error
+}
+
+func NewExporterInstallCommand(p *pkg.PerfParams) *cobra.Command {
+	installArgs := pkg.ExporterInstallArgs{}
+	installCommand := &cobra.Command{
+		Use:   "install",
+		Short: "Install the kperf exporter in the cluster",
+		Long: `Install kperf in-cluster as a long-running Deployment which measures every new Knative Service of all
+namespaces as soon as it is ready, like 'kperf service measure --follow', and exports the service ready latency
+SLIs as Prometheus metrics on /metrics.
+
+The exporter runs with a ServiceAccount bound to a ClusterRole which only reads the Knative resources and Pods. A
+Service exposes the metrics, and the pods are annotated with prometheus.io/scrape. The Grafana dashboard of the SLIs
+is installed as ConfigMap labeled ` + dashboardLabel + `, which the Grafana sidecar picks up. Installing again
+updates the exporter.
+
+For example:
+# To install the exporter with the kperf image built by ko
+kperf exporter install --image $(ko build ./cmd/kperf)
+
+# To only print the manifests, e.g. to apply them with GitOps
+kperf exporter install --image ko.local/kperf --dry-run > kperf-exporter.yaml
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if installArgs.Image == "" {
+				return fmt.Errorf("--image is required to deploy the exporter")
+			}
+			if installArgs.MetricsPort < 1 || installArgs.MetricsPort > 65535 {
+				return fmt.Errorf("--metrics-port must be between 1 and 65535, given %d", installArgs.MetricsPort)
+			}
+			if installArgs.Concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1, given %d", installArgs.Concurrency)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return InstallExporter(p, installArgs, cmd.OutOrStdout())
+		},
+	}
+
+	installCommand.Flags().StringVarP(&installArgs.Namespace, "namespace", "", DefaultNamespace, "Namespace to install the exporter to, it's created if it doesn't exist")
+	installCommand.Flags().StringVarP(&installArgs.Image, "image", "", "", "kperf image run by the exporter, e.g. built with 'ko build ./cmd/kperf'")
+	installCommand.Flags().IntVarP(&installArgs.MetricsPort, "metrics-port", "", 9090, "Port to serve the Prometheus metrics on")
+	installCommand.Flags().StringVarP(&installArgs.Selector, "selector", "", "", "Only measure the services matching the label selector, e.g. app=demo")
+	installCommand.Flags().IntVarP(&installArgs.Concurrency, "concurrency", "c", 10, "Number of services the exporter measures at a time")
+	installCommand.Flags().BoolVarP(&installArgs.DryRun, "dry-run", "", false, "Only print the manifests of the exporter as YAML instead of installing them")
+	return installCommand
+}
+
+// InstallExporter creates the resources of the exporter, or prints their manifests for a dry run
+func InstallExporter(params *pkg.PerfParams, inputs pkg.ExporterInstallArgs, out io.Writer) error {
+	resources, err := exporterResources(params.ClientSet, inputs)
+	if err != nil {
+		return err
+	}
+	if inputs.DryRun {
+		for _, r := range resources {
+			manifest, err := yaml.Marshal(r.object)
+			if err != nil {
+				return fmt.Errorf("failed to write the manifest of %s %s: %s", r.kind, r.name, err)
+			}
+			fmt.Fprintf(out, "---\n%s", manifest)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	for _, r := range resources {
+		err := r.create(ctx)
+		switch {
+		case err == nil:
+			fmt.Fprintf(out, "Created %s %s\n", r.kind, r.name)
+		case apierrors.IsAlreadyExists(err) && r.update == nil:
+			fmt.Fprintf(out, "Kept existing %s %s\n", r.kind, r.name)
+		case apierrors.IsAlreadyExists(err):
+			if err := r.update(ctx); err != nil {
+				return fmt.Errorf("failed to update %s %s: %s", r.kind, r.name, err)
+			}
+			fmt.Fprintf(out, "Updated %s %s\n", r.kind, r.name)
+		default:
+			return fmt.Errorf("failed to create %s %s: %s", r.kind, r.name, err)
+		}
+	}
+	fmt.Fprintf(out, "Exporter installed in namespace %s, the metrics are served by service %s on port %d\n",
+		inputs.Namespace, exporterName, inputs.MetricsPort)
+	return nil
+}
+
+// exporterResources returns the resources of the exporter in the order they are created
+func exporterResources(client kubernetes.Interface, inputs pkg.ExporterInstallArgs) ([]exporterResource, error) {
+	dashboard, err := utils.Asset(dashboardAsset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Grafana dashboard: %s", err)
+	}
+	ns := inputs.Namespace
+	labels := map[string]string{"app.kubernetes.io/name": exporterName}
+	objectMeta := metav1.ObjectMeta{Name: exporterName, Namespace: ns, Labels: labels}
+
+	namespace := &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: ns},
+	}
+	serviceAccount := &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: objectMeta,
+	}
+	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: exporterName, Labels: labels},
+		Rules:      exporterRules(),
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: exporterName, Labels: labels},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: exporterName},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: exporterName, Namespace: ns}},
+	}
+	deployment := exporterDeployment(inputs, objectMeta)
+	service := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: objectMeta,
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Name:       "metrics",
+				Port:       int32(inputs.MetricsPort),
+				TargetPort: intstr.FromString("metrics"),
+			}},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: dashboardName, Namespace: ns, Labels: map[string]string{
+			"app.kubernetes.io/name": exporterName,
+			dashboardLabel:           "1",
+		}},
+		Data: map[string]string{exporterName + ".json": string(dashboard)},
+	}
+
+	return []exporterResource{
+		{
+			kind:   "Namespace",
+			name:   ns,
+			object: namespace,
+			create: func(ctx context.Context) error {
+				_, err := client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+				return err
+			},
+		},
+		{
+			kind:   "ServiceAccount",
+			name:   exporterName,
+			object: serviceAccount,
+			create: func(ctx context.Context) error {
+				_, err := client.CoreV1().ServiceAccounts(ns).Create(ctx, serviceAccount, metav1.CreateOptions{})
+				return err
+			},
+		},
+		{
+			kind:   "ClusterRole",
+			name:   exporterName,
+			object: clusterRole,
+			create: func(ctx context.Context) error {
+				_, err := client.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{})
+				return err
+			},
+			update: func(ctx context.Context) error {
+				existing, err := client.RbacV1().ClusterRoles().Get(ctx, exporterName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				clusterRole.ResourceVersion = existing.ResourceVersion
+				_, err = client.RbacV1().ClusterRoles().Update(ctx, clusterRole, metav1.UpdateOptions{})
+				return err
+			},
+		},
+		{
+			kind:   "ClusterRoleBinding",
+			name:   exporterName,
+			object: clusterRoleBinding,
+			create: func(ctx context.Context) error {
+				_, err := client.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBinding, metav1.CreateOptions{})
+				return err
+			},
+			update: func(ctx context.Context) error {
+				existing, err := client.RbacV1().ClusterRoleBindings().Get(ctx, exporterName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				clusterRoleBinding.ResourceVersion = existing.ResourceVersion
+				_, err = client.RbacV1().ClusterRoleBindings().Update(ctx, clusterRoleBinding, metav1.UpdateOptions{})
+				return err
+			},
+		},
+		{
+			kind:   "Deployment",
+			name:   exporterName,
+			object: deployment,
+			create: func(ctx context.Context) error {
+				_, err := client.AppsV1().Deployments(ns).Create(ctx, deployment, metav1.CreateOptions{})
+				return err
+			},
+			update: func(ctx context.Context) error {
+				existing, err := client.AppsV1().Deployments(ns).Get(ctx, exporterName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				deployment.ResourceVersion = existing.ResourceVersion
+				_, err = client.AppsV1().Deployments(ns).Update(ctx, deployment, metav1.UpdateOptions{})
+				return err
+			},
+		},
+		{
+			kind:   "Service",
+			name:   exporterName,
+			object: service,
+			create: func(ctx context.Context) error {
+				_, err := client.CoreV1().Services(ns).Create(ctx, service, metav1.CreateOptions{})
+				return err
+			},
+			update: func(ctx context.Context) error {
+				existing, err := client.CoreV1().Services(ns).Get(ctx, exporterName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				// the cluster IP of a service is immutable
+				service.ResourceVersion = existing.ResourceVersion
+				service.Spec.ClusterIP = existing.Spec.ClusterIP
+				_, err = client.CoreV1().Services(ns).Update(ctx, service, metav1.UpdateOptions{})
+				return err
+			},
+		},
+		{
+			kind:   "ConfigMap",
+			name:   dashboardName,
+			object: configMap,
+			create: func(ctx context.Context) error {
+				_, err := client.CoreV1().ConfigMaps(ns).Create(ctx, configMap, metav1.CreateOptions{})
+				return err
+			},
+			update: func(ctx context.Context) error {
+				existing, err := client.CoreV1().ConfigMaps(ns).Get(ctx, dashboardName, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				configMap.ResourceVersion = existing.ResourceVersion
+				_, err = client.CoreV1().ConfigMaps(ns).Update(ctx, configMap, metav1.UpdateOptions{})
+				return err
+			},
+		},
+	}, nil
+}
+
+// exporterRules only allow to read the resources 'service measure --follow' measures
+func exporterRules() []rbacv1.PolicyRule {
+	read := []string{"get", "list", "watch"}
+	return []rbacv1.PolicyRule{
+		{APIGroups: []string{"serving.knative.dev"}, Resources: []string{"services", "configurations", "revisions", "routes"}, Verbs: read},
+		{APIGroups: []string{"autoscaling.internal.knative.dev"}, Resources: []string{"podautoscalers"}, Verbs: read},
+		{APIGroups: []string{"networking.internal.knative.dev"}, Resources: []string{"serverlessservices", "ingresses", "certificates"}, Verbs: read},
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: read},
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: read},
+	}
+}
+
+// exporterDeployment runs 'service measure --follow' for all namespaces with the metrics served on the metrics port
+func exporterDeployment(inputs pkg.ExporterInstallArgs, objectMeta metav1.ObjectMeta) *appsv1.Deployment {
+	port := strconv.Itoa(inputs.MetricsPort)
+	args := []string{"service", "measure", "--follow",
+		"--metrics-addr", ":" + port,
+		"--concurrency", strconv.Itoa(inputs.Concurrency)}
+	if inputs.Selector != "" {
+		args = append(args, "--selector", inputs.Selector)
+	}
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: objectMeta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: objectMeta.Labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: objectMeta.Labels,
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   port,
+						"prometheus.io/path":   "/metrics",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: exporterName,
+					Containers: []corev1.Container{{
+						Name:  "exporter",
+						Image: inputs.Image,
+						Args:  args,
+						Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: int32(inputs.MetricsPort)}},
+						ReadinessProbe: &corev1.Probe{
+							Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{
+								Path: "/metrics",
+								Port: intstr.FromString("metrics"),
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestExporterInstallCommand(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{name: "image is required", args: []string{}, err: "--image is required to deploy the exporter"},
+		{name: "invalid metrics port", args: []string{"--image", "kperf", "--metrics-port", "0"}, err: "--metrics-port must be between 1 and 65535, given 0"},
+		{name: "invalid concurrency", args: []string{"--image", "kperf", "--concurrency", "0"}, err: "--concurrency must be at least 1, given 0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &pkg.PerfParams{ClientSet: k8sfake.NewSimpleClientset()}
+			_, err := testutil.ExecuteCommand(NewExporterInstallCommand(p), tc.args...)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestInstallExporter(t *testing.T) {
+	ctx := context.Background()
+	inputs := pkg.ExporterInstallArgs{
+		Namespace:   DefaultNamespace,
+		Image:       "ko.local/kperf:v1",
+		MetricsPort: 9090,
+		Selector:    "app=demo",
+		Concurrency: 10,
+	}
+
+	t.Run("install and update the exporter", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		p := &pkg.PerfParams{ClientSet: client}
+		out := &bytes.Buffer{}
+		assert.NilError(t, InstallExporter(p, inputs, out))
+		assert.Assert(t, strings.Contains(out.String(), "Created Deployment kperf-exporter\n"))
+
+		deployment, err := client.AppsV1().Deployments(DefaultNamespace).Get(ctx, "kperf-exporter", metav1.GetOptions{})
+		assert.NilError(t, err)
+		container := deployment.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, "ko.local/kperf:v1", container.Image)
+		assert.DeepEqual(t, []string{"service", "measure", "--follow", "--metrics-addr", ":9090", "--concurrency", "10",
+			"--selector", "app=demo"}, container.Args)
+		assert.Equal(t, "kperf-exporter", deployment.Spec.Template.Spec.ServiceAccountName)
+		assert.Equal(t, "9090", deployment.Spec.Template.Annotations["prometheus.io/port"])
+
+		binding, err := client.RbacV1().ClusterRoleBindings().Get(ctx, "kperf-exporter", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, DefaultNamespace, binding.Subjects[0].Namespace)
+		dashboard, err := client.CoreV1().ConfigMaps(DefaultNamespace).Get(ctx, "kperf-exporter-dashboard", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "1", dashboard.Labels["grafana_dashboard"])
+		assert.Assert(t, strings.Contains(dashboard.Data["kperf-exporter.json"], "kperf_ksvc_phase_duration_seconds_bucket"))
+
+		// installing again updates the exporter and keeps the namespace and the service account
+		out.Reset()
+		updated := inputs
+		updated.Image = "ko.local/kperf:v2"
+		assert.NilError(t, InstallExporter(p, updated, out))
+		assert.Assert(t, strings.Contains(out.String(), "Kept existing Namespace kperf-system\n"))
+		assert.Assert(t, strings.Contains(out.String(), "Kept existing ServiceAccount kperf-exporter\n"))
+		assert.Assert(t, strings.Contains(out.String(), "Updated Deployment kperf-exporter\n"))
+		deployment, err = client.AppsV1().Deployments(DefaultNamespace).Get(ctx, "kperf-exporter", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "ko.local/kperf:v2", deployment.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("print the manifests for a dry run", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		out := &bytes.Buffer{}
+		dryRun := inputs
+		dryRun.DryRun = true
+		assert.NilError(t, InstallExporter(&pkg.PerfParams{ClientSet: client}, dryRun, out))
+		for _, kind := range []string{"Namespace", "ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Deployment", "Service", "ConfigMap"} {
+			assert.Assert(t, strings.Contains(out.String(), "\nkind: "+kind+"\n"), "missing %s manifest", kind)
+		}
+		assert.Equal(t, 7, strings.Count(out.String(), "---\n"))
+		assert.Equal(t, 0, len(client.Actions()))
+	})
+}
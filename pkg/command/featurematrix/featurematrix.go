@@ -0,0 +1,328 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featurematrix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/calibrate"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	OutputFilename = "feature_matrix"
+
+	// FeaturesConfigMap is the ConfigMap of Knative Serving holding the feature flags
+	FeaturesConfigMap = "config-features"
+	// BaselineName is the name of the run with the feature flags the cluster is configured with
+	BaselineName = "baseline"
+
+	// impactPhase is the phase the impact of the feature flags is reported for in the table
+	impactPhase = measure.PhaseOverallReady
+)
+
+// NewFeatureMatrixCommand implements 'kperf feature-matrix' command
+func NewFeatureMatrixCommand(p *pkg.PerfParams) *cobra.Command {
+	matrixArgs := pkg.FeatureMatrixArgs{}
+	matrixCommand := &cobra.Command{
+		Use:   "feature-matrix",
+		Short: "Benchmark the performance impact of Knative Serving feature flags",
+		Long: `Run the same workload with feature flags of Knative Serving toggled one at a time
+
+The first run is the baseline with the feature flags the cluster is configured with. For every --feature the flag is
+set in the config-features ConfigMap, and after --settle the run generates Knative Services, waits for them to be
+ready, measures and cleans them. A flag without a value is toggled, i.e. set to disabled if it is enabled and to
+enabled otherwise. The feature flags of the cluster are restored afterwards, also if kperf is interrupted, and the
+change of the median and the 95th percentile of every run to the baseline is reported in one table.
+
+For example:
+# To benchmark multi-container and the queue-proxy resource defaults with 10 Knative Services in namespace ktest
+kperf feature-matrix --feature multi-container=disabled --feature queueproxy.resource-defaults --number 10 --namespace ktest --output /tmp
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(matrixArgs.Features) == 0 {
+				return errors.New("at least 1 --feature is required")
+			}
+			if _, err := parseFeatures(matrixArgs.Features); err != nil {
+				return err
+			}
+			if matrixArgs.Number < 1 {
+				return fmt.Errorf("at least 1 Knative Service per run is required, given %d", matrixArgs.Number)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunFeatureMatrix(cmd.Context(), p, matrixArgs)
+		},
+	}
+
+	matrixCommand.Flags().StringArrayVarP(&matrixArgs.Features, "feature", "", nil, "Feature flag of the config-features ConfigMap to benchmark as name=value, e.g. multi-container=disabled, or only its name to toggle it. Can be given several times, every flag is benchmarked in its own run")
+	matrixCommand.Flags().IntVarP(&matrixArgs.Number, "number", "n", 10, "Number of Knative Services created in each run")
+	matrixCommand.Flags().IntVarP(&matrixArgs.Concurrency, "concurrency", "c", 10, "Number of multiple Knative Services to make and measure at a time")
+	matrixCommand.Flags().StringVarP(&matrixArgs.Namespace, "namespace", "", service.DefaultNamespace, "Namespace name. The Knative Services will be created in the namespace")
+	matrixCommand.Flags().StringVarP(&matrixArgs.SvcPrefix, "svc-prefix", "", "kperf-matrix", "Knative Service name prefix. The Knative Services of run i will be svcPrefix-i-0, svcPrefix-i-1 and etc.")
+	matrixCommand.Flags().DurationVarP(&matrixArgs.Timeout, "timeout", "", 5*time.Minute, "Duration to wait for each Knative Service to be ready")
+	matrixCommand.Flags().DurationVarP(&matrixArgs.Settle, "settle", "", 10*time.Second, "Duration to wait after a feature flag was changed, so that the Knative Serving controllers pick it up")
+	matrixCommand.Flags().StringVarP(&matrixArgs.ServingNamespace, "serving-namespace", "", measure.DefaultControlPlaneNamespace, "Namespace of Knative Serving holding the config-features ConfigMap")
+	matrixCommand.Flags().StringVarP(&matrixArgs.Output, "output", "o", ".", "Feature matrix location")
+	report.AddFlag(matrixCommand.Flags(), &matrixArgs.OutputFormats)
+	return matrixCommand
+}
+
+// feature is a feature flag to benchmark, the flag is toggled if the value is empty
+type feature struct {
+	name  string
+	value string
+}
+
+// parseFeatures parses the feature flags given as name=value or name
+func parseFeatures(flags []string) ([]feature, error) {
+	features := make([]feature, 0, len(flags))
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		f := feature{name: strings.TrimSpace(parts[0])}
+		if len(parts) == 2 {
+			f.value = strings.TrimSpace(parts[1])
+			if f.value == "" {
+				return nil, fmt.Errorf("expected a feature flag like multi-container=disabled or multi-container, given %q", flag)
+			}
+		}
+		if f.name == "" {
+			return nil, fmt.Errorf("expected a feature flag like multi-container=disabled or multi-container, given %q", flag)
+		}
+		features = append(features, f)
+	}
+	return features, nil
+}
+
+// toggle returns the opposite of the value of a feature flag, flags which are not enabled are enabled
+func toggle(value string) string {
+	if value == "enabled" {
+		return "disabled"
+	}
+	return "enabled"
+}
+
+// RunFeatureMatrix runs the workload with the baseline feature flags and with every feature flag toggled, and saves
+// the feature matrix. On SIGINT or SIGTERM the runs stop and the baseline feature flags are restored.
+func RunFeatureMatrix(ctx context.Context, params *pkg.PerfParams, inputs pkg.FeatureMatrixArgs) error {
+	ctx, cancel := utils.InterruptContext(ctx)
+	defer cancel()
+	features, err := parseFeatures(inputs.Features)
+	if err != nil {
+		return err
+	}
+	configMaps := params.ClientSet.CoreV1().ConfigMaps(inputs.ServingNamespace)
+	configMap, err := configMaps.Get(ctx, FeaturesConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %s", inputs.ServingNamespace, FeaturesConfigMap, err)
+	}
+	baseline := copyFlags(configMap.Data)
+	defer func() {
+		// ctx is cancelled if kperf is interrupted, the flags are restored regardless
+		if err := setFeatureFlags(context.Background(), configMaps, baseline); err != nil {
+			fmt.Printf("failed to restore the feature flags in ConfigMap %s/%s: %s\n", inputs.ServingNamespace, FeaturesConfigMap, err)
+			return
+		}
+		fmt.Printf("Restored the feature flags in ConfigMap %s/%s\n", inputs.ServingNamespace, FeaturesConfigMap)
+	}()
+
+	runs := []pkg.FeatureRun{{Name: BaselineName}}
+	for _, f := range features {
+		value := f.value
+		if value == "" {
+			value = toggle(baseline[f.name])
+		}
+		runs = append(runs, pkg.FeatureRun{Name: f.name + "=" + value, Feature: f.name, Value: value})
+	}
+
+	var c clock.Clock = clock.RealClock{}
+	if params.Clock != nil {
+		c = params.Clock
+	}
+	for i := range runs {
+		run := &runs[i]
+		if ctx.Err() != nil {
+			return fmt.Errorf("feature matrix interrupted before run %s", run.Name)
+		}
+		fmt.Printf("-------- Feature matrix run %d/%d: %s --------\n", i+1, len(runs), run.Name)
+		if run.Feature != "" {
+			flags := copyFlags(baseline)
+			flags[run.Feature] = run.Value
+			if err := setFeatureFlags(ctx, configMaps, flags); err != nil {
+				return fmt.Errorf("failed to set feature flag %s: %s", run.Name, err)
+			}
+			select {
+			case <-c.After(inputs.Settle):
+			case <-ctx.Done():
+				return fmt.Errorf("feature matrix interrupted before run %s", run.Name)
+			}
+		}
+		samples, err := calibrate.RunWorkload(params, pkg.CalibrateArgs{
+			Number:      inputs.Number,
+			Concurrency: inputs.Concurrency,
+			Namespace:   inputs.Namespace,
+			Timeout:     inputs.Timeout,
+		}, fmt.Sprintf("%s-%d", inputs.SvcPrefix, i))
+		if err != nil {
+			return fmt.Errorf("feature matrix run %s failed: %w", run.Name, err)
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("feature matrix interrupted in run %s", run.Name)
+		}
+		run.Medians, run.P95 = phaseStatistics(samples)
+	}
+	for i := 1; i < len(runs); i++ {
+		runs[i].Impact = impact(runs[0].Medians, runs[i].Medians)
+	}
+
+	matrix := pkg.FeatureMatrix{
+		CreatedAt:      c.Now().UTC().Format(time.RFC3339),
+		ServicesPerRun: inputs.Number,
+		KnativeInfo:    measure.GetKnativeInfo(ctx, params, measure.DefaultLogger),
+		Baseline:       baseline,
+		Runs:           runs,
+	}
+	fmt.Printf("-------- Feature Matrix --------\n")
+	writeTable(os.Stdout, matrix, render.NewOptions(os.Stdout, false, false))
+	return saveFeatureMatrix(params.Output, c.Now(), inputs.Output, inputs.OutputFormats, matrix)
+}
+
+func copyFlags(flags map[string]string) map[string]string {
+	copied := make(map[string]string, len(flags))
+	for name, value := range flags {
+		copied[name] = value
+	}
+	return copied
+}
+
+// setFeatureFlags replaces the feature flags of the latest config-features ConfigMap
+func setFeatureFlags(ctx context.Context, configMaps corev1client.ConfigMapInterface, flags map[string]string) error {
+	configMap, err := configMaps.Get(ctx, FeaturesConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	configMap.Data = copyFlags(flags)
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// phaseStatistics returns the median and the 95th percentile of every measured phase
+func phaseStatistics(samples map[string][]float64) (map[string]float64, map[string]float64) {
+	medians := map[string]float64{}
+	p95 := map[string]float64{}
+	for phase, values := range samples {
+		medians[phase], _ = stats.Median(values)
+		p95[phase], _ = stats.Percentile(values, 95)
+	}
+	return medians, p95
+}
+
+// impact returns the relative change of the medians of every phase to the baseline in percent, phases which took
+// no time in the baseline are left out
+func impact(baseline, medians map[string]float64) map[string]float64 {
+	changes := map[string]float64{}
+	for phase, median := range medians {
+		if base, ok := baseline[phase]; ok && base != 0 {
+			changes[phase] = (median - base) / base * 100
+		}
+	}
+	return changes
+}
+
+// change formats the relative change of a statistic to the baseline
+func change(value, base float64) string {
+	if base == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f%%", (value-base)/base*100)
+}
+
+// writeTable writes the overall ready median and 95th percentile of every run and their change to the baseline
+func writeTable(w io.Writer, matrix pkg.FeatureMatrix, options render.Options) {
+	if len(matrix.Runs) == 0 {
+		return
+	}
+	base := matrix.Runs[0]
+	var longest float64
+	for _, run := range matrix.Runs {
+		if run.Medians[impactPhase] > longest {
+			longest = run.Medians[impactPhase]
+		}
+	}
+	rows := make([]*render.Row, 0, len(matrix.Runs))
+	for i, run := range matrix.Runs {
+		median, p95 := run.Medians[impactPhase], run.P95[impactPhase]
+		values := []string{fmt.Sprintf("%fs", median), "-", fmt.Sprintf("%fs", p95), "-"}
+		if i > 0 {
+			values[1], values[3] = change(median, base.Medians[impactPhase]), change(p95, base.P95[impactPhase])
+		}
+		row := &render.Row{Name: run.Name, Values: values}
+		if longest > 0 {
+			row.Bar = median / longest
+		}
+		rows = append(rows, row)
+	}
+	render.Table(w, []string{"CONFIGURATION", "MEDIAN", "MEDIAN CHANGE", "P95", "P95 CHANGE"}, rows, options)
+}
+
+// saveFeatureMatrix writes the median, the 95th percentile and the impact of every phase of every run as rows and the
+// whole matrix as result in the output formats, current is the time the file names are stamped with
+func saveFeatureMatrix(outputOptions pkg.OutputOptions, current time.Time, output string, formats []string, matrix pkg.FeatureMatrix) error {
+	outputLocation, err := utils.CheckOutputLocation(output)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{"configuration", "phase", "median", "percentile95", "impact_percent"}}
+	for _, run := range matrix.Runs {
+		phases := make([]string, 0, len(run.Medians))
+		for phase := range run.Medians {
+			phases = append(phases, phase)
+		}
+		sort.Strings(phases)
+		for _, phase := range phases {
+			impact := ""
+			if value, ok := run.Impact[phase]; ok {
+				impact = fmt.Sprintf("%.2f", value)
+			}
+			rows = append(rows, []string{run.Name, phase, fmt.Sprintf("%f", run.Medians[phase]), fmt.Sprintf("%f", run.P95[phase]), impact})
+		}
+	}
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), outputOptions, report.Report{
+		Name:    OutputFilename,
+		Rows:    rows,
+		Result:  matrix,
+		Formats: formats,
+	})
+}
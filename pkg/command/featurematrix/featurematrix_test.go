@@ -0,0 +1,178 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featurematrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func newFeaturesConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: FeaturesConfigMap, Namespace: "knative-serving"},
+		Data:       map[string]string{"multi-container": "enabled"},
+	}
+}
+
+func TestNewFeatureMatrixCommand(t *testing.T) {
+	p := &pkg.PerfParams{ClientSet: k8sfake.NewSimpleClientset()}
+
+	_, err := testutil.ExecuteCommand(NewFeatureMatrixCommand(p))
+	assert.ErrorContains(t, err, "at least 1 --feature is required")
+
+	_, err = testutil.ExecuteCommand(NewFeatureMatrixCommand(p), "--feature", "=enabled")
+	assert.ErrorContains(t, err, `expected a feature flag like multi-container=disabled or multi-container, given "=enabled"`)
+
+	_, err = testutil.ExecuteCommand(NewFeatureMatrixCommand(p), "--feature", "multi-container", "--number", "0")
+	assert.ErrorContains(t, err, "at least 1 Knative Service per run is required, given 0")
+
+	_, err = testutil.ExecuteCommand(NewFeatureMatrixCommand(p), "--feature", "multi-container")
+	assert.ErrorContains(t, err, "failed to get ConfigMap knative-serving/config-features")
+}
+
+func TestRunFeatureMatrix(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(newFeaturesConfigMap())
+	p := &pkg.PerfParams{ClientSet: client}
+
+	// the run fails as the namespace doesn't exist, the feature flags are restored anyway
+	err := RunFeatureMatrix(context.Background(), p, pkg.FeatureMatrixArgs{
+		Features:         []string{"multi-container", "tag-header-based-routing=enabled"},
+		Number:           1,
+		Namespace:        "ns-2",
+		SvcPrefix:        "kperf-matrix",
+		ServingNamespace: "knative-serving",
+		Output:           t.TempDir(),
+	})
+	assert.ErrorContains(t, err, "feature matrix run baseline failed: namespace ns-2 not found, please create one")
+	configMap, err := client.CoreV1().ConfigMaps("knative-serving").Get(context.Background(), FeaturesConfigMap, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"multi-container": "enabled"}, configMap.Data)
+}
+
+func TestRunFeatureMatrixInterrupted(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(newFeaturesConfigMap())
+	p := &pkg.PerfParams{ClientSet: client}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := RunFeatureMatrix(ctx, p, pkg.FeatureMatrixArgs{
+		Features:         []string{"multi-container"},
+		Number:           1,
+		Namespace:        "ns-1",
+		SvcPrefix:        "kperf-matrix",
+		ServingNamespace: "knative-serving",
+		Output:           t.TempDir(),
+	})
+	assert.ErrorContains(t, err, "feature matrix interrupted before run baseline")
+	configMap, err := client.CoreV1().ConfigMaps("knative-serving").Get(context.Background(), FeaturesConfigMap, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"multi-container": "enabled"}, configMap.Data)
+}
+
+func TestParseFeatures(t *testing.T) {
+	features, err := parseFeatures([]string{"multi-container", " queueproxy.resource-defaults = enabled "})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []feature{{name: "multi-container"}, {name: "queueproxy.resource-defaults", value: "enabled"}}, features, cmp.AllowUnexported(feature{}))
+
+	_, err = parseFeatures([]string{"multi-container="})
+	assert.ErrorContains(t, err, `given "multi-container="`)
+}
+
+func TestToggle(t *testing.T) {
+	assert.Equal(t, "disabled", toggle("enabled"))
+	assert.Equal(t, "enabled", toggle("disabled"))
+	assert.Equal(t, "enabled", toggle("allowed"))
+	assert.Equal(t, "enabled", toggle(""))
+}
+
+func TestImpact(t *testing.T) {
+	changes := impact(map[string]float64{"overall_ready": 10, "certificate_ready": 0}, map[string]float64{"overall_ready": 12, "certificate_ready": 1, "route_ready": 3})
+	assert.DeepEqual(t, map[string]float64{"overall_ready": 20}, changes)
+	assert.Equal(t, "+20.00%", change(12, 10))
+	assert.Equal(t, "-50.00%", change(5, 10))
+	assert.Equal(t, "-", change(5, 0))
+}
+
+func newTestMatrix() pkg.FeatureMatrix {
+	return pkg.FeatureMatrix{
+		ServicesPerRun: 10,
+		Baseline:       map[string]string{"multi-container": "enabled"},
+		Runs: []pkg.FeatureRun{
+			{Name: BaselineName, Medians: map[string]float64{"overall_ready": 10, "revision_ready": 4}, P95: map[string]float64{"overall_ready": 20, "revision_ready": 8}},
+			{Name: "multi-container=disabled", Feature: "multi-container", Value: "disabled",
+				Medians: map[string]float64{"overall_ready": 8, "revision_ready": 5}, P95: map[string]float64{"overall_ready": 25, "revision_ready": 8},
+				Impact: map[string]float64{"overall_ready": -20, "revision_ready": 25}},
+		},
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	out := &bytes.Buffer{}
+	writeTable(out, newTestMatrix(), render.Options{})
+	lines := strings.Split(out.String(), "\n")
+	assert.Assert(t, strings.HasPrefix(lines[0], "CONFIGURATION                 MEDIAN  MEDIAN CHANGE         P95  P95 CHANGE"), out.String())
+	assert.Assert(t, strings.HasPrefix(lines[1], "baseline                  10.000000s              -  20.000000s           -"), out.String())
+	assert.Assert(t, strings.HasPrefix(lines[2], "multi-container=disabled   8.000000s        -20.00%  25.000000s     +25.00%"), out.String())
+}
+
+func TestSaveFeatureMatrix(t *testing.T) {
+	output := t.TempDir()
+	current := time.Date(2022, 3, 4, 5, 6, 7, 0, time.Local)
+	assert.NilError(t, saveFeatureMatrix(pkg.OutputOptions{}, current, output, nil, newTestMatrix()))
+
+	matches, err := filepath.Glob(filepath.Join(output, "20220304050607_feature_matrix.csv"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(matches))
+	content, err := ioutil.ReadFile(matches[0])
+	assert.NilError(t, err)
+	assert.Equal(t, `configuration,phase,median,percentile95,impact_percent
+baseline,overall_ready,10.000000,20.000000,
+baseline,revision_ready,4.000000,8.000000,
+multi-container=disabled,overall_ready,8.000000,25.000000,-20.00
+multi-container=disabled,revision_ready,5.000000,8.000000,25.00
+`, string(content))
+
+	matches, err = filepath.Glob(filepath.Join(output, "*_feature_matrix.json"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(matches))
+	content, err = ioutil.ReadFile(matches[0])
+	assert.NilError(t, err)
+	var matrix pkg.FeatureMatrix
+	assert.NilError(t, json.Unmarshal(content, &matrix))
+	assert.DeepEqual(t, newTestMatrix(), matrix)
+
+	output = t.TempDir()
+	assert.NilError(t, saveFeatureMatrix(pkg.OutputOptions{}, time.Now(), output, []string{"json"}, newTestMatrix()))
+	matches, err = filepath.Glob(filepath.Join(output, "*_feature_matrix.*"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(matches))
+	assert.Assert(t, strings.HasSuffix(matches[0], "_feature_matrix.json"), matches[0])
+}
@@ -0,0 +1,325 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	DeployOutputFilename = "function_deploy_time"
+
+	// FunctionLabel holds the name of the function a Knative Service was deployed for, it is set by the func CLI
+	FunctionLabel = "function.knative.dev/name"
+)
+
+// runFunc runs the func CLI with the arguments in the directory and returns its combined output
+var runFunc = func(ctx context.Context, funcPath, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, funcPath, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+func NewFunctionDeployCommand(p *pkg.PerfParams) *cobra.Command {
+	deployArgs := pkg.FunctionDeployArgs{}
+	functionDeployCommand := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy Knative Functions and measure the build, deploy and ready time",
+		Long: `Deploy Knative Functions and measure the time to build, deploy and get them ready
+
+Every function is created from the template of the runtime with the func CLI, built and pushed to the registry
+given by --registry, and deployed as Knative Service. The build is the duration of 'func build', the deploy the
+duration of 'func deploy', which waits for the service itself, and the ready the time since then until the service
+is ready. With --image the build is skipped, the Knative Services are created with the prebuilt image instead, so
+that the deployment can be measured without the func CLI and a registry.
+
+The Knative Services are labeled with the run ID, clean them up with 'kperf service clean --run-id'.
+
+For example:
+# To build and deploy 10 Go functions from the http template in namespace ns, 2 at a time
+kperf function deploy -n 10 -c 2 --namespace ns --registry quay.io/user
+
+# To deploy 10 functions of a prebuilt image
+kperf function deploy -n 10 --namespace ns --image quay.io/user/func:latest
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'function deploy' requires flag(s)")
+			}
+			if deployArgs.Count < 1 {
+				return fmt.Errorf("number must be at least 1")
+			}
+			if deployArgs.Concurrency < 1 {
+				return fmt.Errorf("concurrency must be at least 1")
+			}
+			if deployArgs.Namespace == "" {
+				return fmt.Errorf("--namespace is required to deploy the functions")
+			}
+			if deployArgs.Image == "" && deployArgs.Registry == "" {
+				return fmt.Errorf("either --registry to build the functions or --image to deploy a prebuilt image is required")
+			}
+			return pkg.ValidateRunID(deployArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return DeployFunctions(p, deployArgs)
+		},
+	}
+
+	functionDeployCommand.Flags().IntVarP(&deployArgs.Count, "number", "n", 0, "Total number of Knative Functions to be deployed")
+	functionDeployCommand.MarkFlagRequired("number")
+	functionDeployCommand.Flags().IntVarP(&deployArgs.Concurrency, "concurrency", "c", 1, "Number of functions built and deployed at the same time")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.Namespace, "namespace", "", "", "Namespace to deploy the functions to")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.NamePrefix, "name-prefix", "", "func", "Function name prefix. The functions will be namePrefix-0,namePrefix-1,namePrefix-2......")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.Runtime, "runtime", "", "go", "Language runtime of the functions, e.g. go, node, python, quarkus, rust, springboot or typescript")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.Template, "template", "", "http", "Template of the functions, e.g. http or cloudevents")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.Registry, "registry", "", "", "Registry the images of the functions are pushed to, e.g. quay.io/user")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.Image, "image", "", "", "Prebuilt image of the functions, the build is skipped and the func CLI isn't used")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.FuncPath, "func-path", "", "func", "Path of the func CLI")
+	functionDeployCommand.Flags().DurationVarP(&deployArgs.Timeout, "timeout", "", 10*time.Minute, "Duration to wait for a function to be built, deployed and ready")
+	functionDeployCommand.Flags().BoolVarP(&deployArgs.Verbose, "verbose", "v", false, "Function verbose result")
+	functionDeployCommand.Flags().StringVarP(&deployArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(functionDeployCommand.Flags(), &deployArgs.OutputFormats)
+	functionDeployCommand.Flags().StringVarP(&deployArgs.RunID, "run-id", "", "", "ID of the run the Knative Services of the functions are labeled with as "+pkg.RunIDLabel+", so that 'kperf service clean --run-id' removes exactly them. A new ID is generated by default")
+	return functionDeployCommand
+}
+
+// DeployFunctions deploys the Knative Functions and measures the time to build, deploy and get them ready
+func DeployFunctions(params *pkg.PerfParams, inputs pkg.FunctionDeployArgs) error {
+	ctx := context.Background()
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	var clk clock.PassiveClock = clock.RealClock{}
+	if params.Clock != nil {
+		clk = params.Clock
+	}
+	if inputs.RunID == "" {
+		inputs.RunID = pkg.NewRunID(clk.Now())
+	}
+	fmt.Printf("Run ID %s, clean up the run with 'kperf service clean --run-id %s'\n", inputs.RunID, inputs.RunID)
+
+	workDir := ""
+	if inputs.Image == "" {
+		workDir, err = os.MkdirTemp("", "kperf-function-")
+		if err != nil {
+			return fmt.Errorf("failed to create the directory of the function sources: %s", err)
+		}
+		defer os.RemoveAll(workDir)
+	}
+
+	result := pkg.FunctionResult{}
+	var m sync.Mutex
+	pool.ForEach(ctx, inputs.Concurrency, inputs.Count, func(ctx context.Context, i int) {
+		name := fmt.Sprintf("%s-%d", inputs.NamePrefix, i)
+		measurement, err := deployFunction(ctx, ksvcClient, clk, inputs, workDir, name)
+		if err != nil {
+			fmt.Printf("failed to deploy function %s in namespace %s and skip: %s\n", name, inputs.Namespace, err)
+			return
+		}
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Function %s: Total Duration is %fs\n", name, measurement.Total)
+			fmt.Printf("[Verbose] Function %s: - Build Duration is %fs\n", name, measurement.Build)
+			fmt.Printf("[Verbose] Function %s: - Deploy Duration is %fs\n", name, measurement.Deploy)
+			fmt.Printf("[Verbose] Function %s: - Ready Duration is %fs\n", name, measurement.Ready)
+		}
+		m.Lock()
+		result.Measurement = append(result.Measurement, measurement)
+		m.Unlock()
+	})
+	sort.Slice(result.Measurement, func(i, j int) bool {
+		return result.Measurement[i].ServiceName < result.Measurement[j].ServiceName
+	})
+	result.KnativeInfo = measure.GetKnativeInfo(ctx, params, measure.DefaultLogger)
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Function Deploy Measurement:\n")
+	fmt.Printf("Total: %d | Deployed: %d Failed: %d\n", inputs.Count, len(result.Measurement), inputs.Count-len(result.Measurement))
+	writeFunctionPhases(os.Stdout, result.Measurement)
+
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	if err := report.Write(os.Stdout, outputLocation, clk.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    DeployOutputFilename,
+		Rows:    functionRows(result.Measurement),
+		Result:  result,
+		Phases:  true,
+		Formats: inputs.OutputFormats,
+	}); err != nil {
+		return err
+	}
+	if len(result.Measurement) < inputs.Count {
+		return fmt.Errorf("failed to deploy %d of %d function(s)", inputs.Count-len(result.Measurement), inputs.Count)
+	}
+	return nil
+}
+
+// deployFunction builds and deploys the function with the func CLI, or creates its Knative Service with the prebuilt
+// image, and waits until the service is ready
+func deployFunction(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, clk clock.PassiveClock, inputs pkg.FunctionDeployArgs, workDir, name string) (pkg.FunctionMeasurement, error) {
+	ctx, cancel := context.WithTimeout(ctx, inputs.Timeout)
+	defer cancel()
+	measurement := pkg.FunctionMeasurement{ServiceName: name, ServiceNamespace: inputs.Namespace}
+	labels := pkg.GeneratedLabels(inputs.RunID, 0, clk.Now())
+
+	if inputs.Image != "" {
+		fmt.Printf("Creating Knative Service of function %s in namespace %s\n", name, inputs.Namespace)
+		start := clk.Now()
+		if err := createFunctionService(ctx, ksvcClient, inputs.Namespace, name, inputs.Image, labels); err != nil {
+			return measurement, err
+		}
+		measurement.Deploy = clk.Since(start).Seconds()
+	} else {
+		if out, err := runFunc(ctx, inputs.FuncPath, workDir, "create", "--language", inputs.Runtime, "--template", inputs.Template, name); err != nil {
+			return measurement, funcError("create", err, out)
+		}
+		dir := filepath.Join(workDir, name)
+		fmt.Printf("Building function %s\n", name)
+		start := clk.Now()
+		if out, err := runFunc(ctx, inputs.FuncPath, dir, "build", "--registry", inputs.Registry); err != nil {
+			return measurement, funcError("build", err, out)
+		}
+		measurement.Build = clk.Since(start).Seconds()
+
+		fmt.Printf("Deploying function %s in namespace %s\n", name, inputs.Namespace)
+		start = clk.Now()
+		if out, err := runFunc(ctx, inputs.FuncPath, dir, "deploy", "--build=false", "--registry", inputs.Registry, "--namespace", inputs.Namespace); err != nil {
+			return measurement, funcError("deploy", err, out)
+		}
+		measurement.Deploy = clk.Since(start).Seconds()
+		if err := labelFunctionService(ctx, ksvcClient, inputs.Namespace, name, labels); err != nil {
+			return measurement, err
+		}
+	}
+
+	start := clk.Now()
+	if err := service.WaitServiceReady(ctx, ksvcClient, inputs.Namespace, name, inputs.Timeout); err != nil {
+		return measurement, err
+	}
+	measurement.Ready = clk.Since(start).Seconds()
+	measurement.Total = measurement.Build + measurement.Deploy + measurement.Ready
+	return measurement, nil
+}
+
+// funcError returns the error of the func CLI command with its output, which explains the failure
+func funcError(command string, err error, out []byte) error {
+	return fmt.Errorf("'func %s' failed: %s: %s", command, err, strings.TrimSpace(string(out)))
+}
+
+// createFunctionService creates the Knative Service of the function with the prebuilt image like the func CLI does
+func createFunctionService(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, name, image string, labels map[string]string) error {
+	svc := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{FunctionLabel: name},
+		},
+	}
+	for k, v := range labels {
+		svc.Labels[k] = v
+	}
+	svc.Spec.Template.Spec.Containers = []corev1.Container{{
+		Image: image,
+		Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+	}}
+	_, err := ksvcClient.Services(namespace).Create(ctx, svc, metav1.CreateOptions{})
+	return err
+}
+
+// labelFunctionService labels the Knative Service deployed by the func CLI, the labels of the Service metadata don't
+// roll out a new revision
+func labelFunctionService(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, name string, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+	if err != nil {
+		return err
+	}
+	if _, err := ksvcClient.Services(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to label Knative Service %s of the function: %s", name, err)
+	}
+	return nil
+}
+
+// functionRows returns the rows of the CSV file, the phases keyed by the name and namespace of the service
+func functionRows(measurements []pkg.FunctionMeasurement) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "build", "deploy", "ready", "total"}}
+	for _, r := range measurements {
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace,
+			fmt.Sprintf("%f", r.Build),
+			fmt.Sprintf("%f", r.Deploy),
+			fmt.Sprintf("%f", r.Ready),
+			fmt.Sprintf("%f", r.Total),
+		})
+	}
+	return rows
+}
+
+// writeFunctionPhases writes the average, the percentiles and the maximum of the durations of every phase
+func writeFunctionPhases(out io.Writer, measurements []pkg.FunctionMeasurement) {
+	if len(measurements) == 0 {
+		return
+	}
+	phases := []struct {
+		name     string
+		duration func(pkg.FunctionMeasurement) float64
+	}{
+		{"Build", func(m pkg.FunctionMeasurement) float64 { return m.Build }},
+		{"Deploy", func(m pkg.FunctionMeasurement) float64 { return m.Deploy }},
+		{"Ready", func(m pkg.FunctionMeasurement) float64 { return m.Ready }},
+		{"Total", func(m pkg.FunctionMeasurement) float64 { return m.Total }},
+	}
+	for _, phase := range phases {
+		durations := make([]float64, 0, len(measurements))
+		for _, m := range measurements {
+			durations = append(durations, phase.duration(m))
+		}
+		average, _ := stats.Mean(durations)
+		p50, _ := stats.Percentile(durations, 50)
+		p95, _ := stats.Percentile(durations, 95)
+		maximum, _ := stats.Max(durations)
+		fmt.Fprintf(out, "%s: Average: %fs Percentile50: %fs Percentile95: %fs Max: %fs\n", phase.name, average, p50, p95, maximum)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+// newTestPerfParams returns PerfParams backed by a fake serving client which marks the created services ready
+func newTestPerfParams() (*pkg.PerfParams, *servingv1fake.FakeServingV1) {
+	client := k8sfake.NewSimpleClientset()
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeServing.PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		svc := action.(clienttesting.CreateAction).GetObject().(*servingv1.Service)
+		svc.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+		return false, nil, nil
+	})
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+	}, fakeServing
+}
+
+func TestDeployFunctions(t *testing.T) {
+	t.Run("prebuilt image", func(t *testing.T) {
+		p, fakeServing := newTestPerfParams()
+		output := t.TempDir()
+		cmd := NewFunctionDeployCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "-n", "2", "--namespace", "ns", "--image", "quay.io/user/func:latest",
+			"--run-id", "run-1", "--output", output)
+		assert.NilError(t, err)
+
+		svc, err := fakeServing.Services("ns").Get(context.Background(), "func-1", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "quay.io/user/func:latest", svc.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, "func-1", svc.Labels[FunctionLabel])
+		assert.Equal(t, "run-1", svc.Labels[pkg.RunIDLabel])
+
+		files, err := filepath.Glob(filepath.Join(output, "run-1", "*_"+DeployOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(files))
+		data, err := ioutil.ReadFile(files[0])
+		assert.NilError(t, err)
+		assert.Assert(t, strings.HasPrefix(string(data), "svc_name,svc_namespace,build,deploy,ready,total\n"), string(data))
+		assert.Equal(t, 3, strings.Count(string(data), "\n"))
+	})
+
+	t.Run("built with the func CLI", func(t *testing.T) {
+		p, fakeServing := newTestPerfParams()
+		var m sync.Mutex
+		var commands []string
+		oldRunFunc := runFunc
+		defer func() { runFunc = oldRunFunc }()
+		runFunc = func(ctx context.Context, funcPath, dir string, args ...string) ([]byte, error) {
+			m.Lock()
+			commands = append(commands, strings.Join(args, " "))
+			m.Unlock()
+			if args[0] == "deploy" {
+				svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: filepath.Base(dir), Namespace: "ns",
+					Labels: map[string]string{FunctionLabel: filepath.Base(dir)}}}
+				_, err := fakeServing.Services("ns").Create(ctx, svc, metav1.CreateOptions{})
+				return nil, err
+			}
+			return nil, nil
+		}
+		cmd := NewFunctionDeployCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "-n", "1", "--namespace", "ns", "--registry", "quay.io/user",
+			"--runtime", "python", "--run-id", "run-1", "--output", t.TempDir())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{
+			"create --language python --template http func-0",
+			"build --registry quay.io/user",
+			"deploy --build=false --registry quay.io/user --namespace ns",
+		}, commands)
+
+		svc, err := fakeServing.Services("ns").Get(context.Background(), "func-0", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "run-1", svc.Labels[pkg.RunIDLabel])
+		assert.Equal(t, "func-0", svc.Labels[FunctionLabel])
+	})
+
+	t.Run("failed build", func(t *testing.T) {
+		p, _ := newTestPerfParams()
+		oldRunFunc := runFunc
+		defer func() { runFunc = oldRunFunc }()
+		runFunc = func(ctx context.Context, funcPath, dir string, args ...string) ([]byte, error) {
+			if args[0] == "build" {
+				return []byte("Error: registry unreachable\n"), fmt.Errorf("exit status 1")
+			}
+			return nil, nil
+		}
+		cmd := NewFunctionDeployCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "-n", "1", "--namespace", "ns", "--registry", "quay.io/user", "--output", t.TempDir())
+		assert.ErrorContains(t, err, "failed to deploy 1 of 1 function(s)")
+		assert.Error(t, funcError("build", fmt.Errorf("exit status 1"), []byte("Error: registry unreachable\n")),
+			"'func build' failed: exit status 1: Error: registry unreachable")
+	})
+
+	t.Run("registry or image required", func(t *testing.T) {
+		p, _ := newTestPerfParams()
+		cmd := NewFunctionDeployCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "-n", "1", "--namespace", "ns")
+		assert.ErrorContains(t, err, "either --registry to build the functions or --image to deploy a prebuilt image is required")
+	})
+}
+
+func TestFunctionRows(t *testing.T) {
+	rows := functionRows([]pkg.FunctionMeasurement{{ServiceName: "func-0", ServiceNamespace: "ns", Build: 1, Deploy: 2, Ready: 0.5, Total: 3.5}})
+	assert.DeepEqual(t, [][]string{
+		{"svc_name", "svc_namespace", "build", "deploy", "ready", "total"},
+		{"func-0", "ns", "1.000000", "2.000000", "0.500000", "3.500000"},
+	}, rows)
+}
@@ -0,0 +1,35 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewFunctionCmd(p *pkg.PerfParams) *cobra.Command {
+	var functionCmd = &cobra.Command{
+		Use:   "function",
+		Short: "Knative Function load test",
+		Long: `Knative Functions load test and measurement. For example:
+
+kperf function deploy -n 10 --namespace ns --registry quay.io/user - to build and deploy 10 Go functions and measure their build, deploy and ready time
+kperf function deploy -n 10 --namespace ns --image quay.io/user/func:latest - to deploy 10 functions of a prebuilt image`,
+	}
+	functionCmd.AddCommand(NewFunctionDeployCommand(p))
+
+	functionCmd.InitDefaultHelpCmd()
+	return functionCmd
+}
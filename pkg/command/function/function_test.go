@@ -0,0 +1,29 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNewFunctionCmd(t *testing.T) {
+	cmd := NewFunctionCmd(nil)
+	assert.Check(t, cmd.HasSubCommands(), "cmd function should have subcommands")
+
+	_, _, err := cmd.Find([]string{"deploy"})
+	assert.NilError(t, err, "function command should have deploy subcommand")
+}
@@ -0,0 +1,448 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	BenchmarkOutputFilename = "ingress_benchmark"
+
+	// firstRequestInterval is the interval of the requests sent until the first one through the ingress succeeds
+	firstRequestInterval = 100 * time.Millisecond
+)
+
+// contextParams returns the params with the clients for the cluster of the kubeconfig context
+var contextParams = func(params *pkg.PerfParams, context string) (*pkg.PerfParams, error) {
+	return params.ForContext(context)
+}
+
+func NewIngressBenchmarkCommand(p *pkg.PerfParams) *cobra.Command {
+	benchmarkArgs := pkg.IngressBenchmarkArgs{}
+	benchmarkCommand := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Benchmark the ingress of Knative Serving",
+		Long: `Create the same set of Knative services and measure how fast the installed ingress programs them and serves requests
+
+The ingress, i.e. Istio, Kourier, Contour or the Gateway API, is detected from the config-network ConfigMap. For every
+service the programming latency is the time from the creation of its KIngress until the ingress reported its load
+balancer ready, and the first request latency the time from the creation of the service until the first request
+through the ingress succeeded. Once all services are reachable, --requests requests are sent to each of them over
+--connections connections to measure the data-path latency. The services keep a min-scale of 1, so that no request
+waits for a cold start, and are deleted afterwards unless --keep is given.
+
+With --contexts the same services are benchmarked in the cluster of every kubeconfig context one after the other,
+and the ingresses of the clusters are compared in one table.
+
+For example:
+# To benchmark the ingress of the current cluster with 10 Knative Services in namespace ktest
+kperf ingress benchmark --namespace ktest --number 10
+
+# To compare the ingresses of the clusters of the contexts kourier and istio
+kperf ingress benchmark --namespace ktest --number 10 --contexts kourier,istio
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if benchmarkArgs.Namespace == "" {
+				return fmt.Errorf("'ingress benchmark' requires --namespace")
+			}
+			if benchmarkArgs.Number < 1 {
+				return fmt.Errorf("--number must be at least 1, given %d", benchmarkArgs.Number)
+			}
+			if benchmarkArgs.Concurrency < 1 || benchmarkArgs.Connections < 1 {
+				return fmt.Errorf("--concurrency and --connections must be at least 1")
+			}
+			if benchmarkArgs.Requests < 0 {
+				return fmt.Errorf("--requests must not be negative, given %d", benchmarkArgs.Requests)
+			}
+			seen := map[string]bool{}
+			for _, c := range benchmarkArgs.Contexts {
+				if c == "" {
+					return fmt.Errorf("--contexts must not contain an empty context")
+				}
+				if seen[c] {
+					return fmt.Errorf("--contexts contains the context %s twice", c)
+				}
+				seen[c] = true
+			}
+			return pkg.ValidateRunID(benchmarkArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return BenchmarkIngress(p, benchmarkArgs)
+		},
+	}
+
+	benchmarkCommand.Flags().IntVarP(&benchmarkArgs.Number, "number", "n", 10, "Number of Knative Services created in every cluster")
+	benchmarkCommand.Flags().IntVarP(&benchmarkArgs.Concurrency, "concurrency", "c", 10, "Number of Knative Services to create and measure at a time")
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.Namespace, "namespace", "", "", "Namespace the Knative Services are created in")
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.SvcPrefix, "svc-prefix", "", "kperf-ingress", "Knative Service name prefix. The Knative Services will be svcPrefix-0, svcPrefix-1 and etc.")
+	benchmarkCommand.Flags().IntVarP(&benchmarkArgs.Requests, "requests", "", 100, "Number of requests sent to each Knative Service to measure the data-path latency")
+	benchmarkCommand.Flags().IntVarP(&benchmarkArgs.Connections, "connections", "", 10, "Number of concurrent connections the requests are sent over")
+	benchmarkCommand.Flags().DurationVarP(&benchmarkArgs.Timeout, "timeout", "", 5*time.Minute, "Duration to wait for each Knative Service to be ready and reachable through the ingress")
+	benchmarkCommand.Flags().DurationVarP(&benchmarkArgs.RequestTimeout, "request-timeout", "", 30*time.Second, "Timeout of a single request")
+	benchmarkCommand.Flags().BoolVarP(&benchmarkArgs.ResolvableDomain, "resolvable", "", false, "If Service endpoint resolvable url")
+	benchmarkCommand.Flags().BoolVarP(&benchmarkArgs.Keep, "keep", "", false, "Keep the Knative Services after the benchmark, 'kperf service clean --run-id' removes them")
+	benchmarkCommand.Flags().StringSliceVarP(&benchmarkArgs.Contexts, "contexts", "", nil, "Comma separated kubeconfig contexts of the clusters to benchmark one after the other")
+	benchmarkCommand.Flags().BoolVarP(&benchmarkArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(benchmarkCommand.Flags(), &benchmarkArgs.OutputFormats)
+	benchmarkCommand.Flags().StringVarP(&benchmarkArgs.RunID, "run-id", "", "", "ID of the run the Knative Services are labeled with, the results are written to the subdirectory of the output location named by it. A new ID is generated by default")
+	return benchmarkCommand
+}
+
+// BenchmarkIngress benchmarks the ingress of the current cluster, or of the clusters of the contexts one after the
+// other, and writes the comparison of the clusters
+func BenchmarkIngress(params *pkg.PerfParams, inputs pkg.IngressBenchmarkArgs) error {
+	if inputs.RunID == "" {
+		inputs.RunID = pkg.NewRunID(time.Now())
+	}
+	fmt.Printf("Run ID %s, clean up the run with 'kperf service clean --run-id %s'\n", inputs.RunID, inputs.RunID)
+
+	result := pkg.IngressBenchmarkResult{}
+	failed := make([]string, 0)
+	if len(inputs.Contexts) == 0 {
+		cluster, err := benchmarkCluster(params, inputs)
+		if err != nil {
+			return err
+		}
+		result.Clusters = append(result.Clusters, cluster)
+	}
+	for _, c := range inputs.Contexts {
+		fmt.Printf("\n======== Cluster %s ========\n", c)
+		cp, err := contextParams(params, c)
+		var cluster pkg.IngressClusterResult
+		if err == nil {
+			cluster, err = benchmarkCluster(cp, inputs)
+		}
+		if err != nil {
+			fmt.Printf("failed to benchmark the ingress of cluster %s: %s\n", c, err)
+			failed = append(failed, c)
+			continue
+		}
+		cluster.Context = c
+		result.Clusters = append(result.Clusters, cluster)
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	writeComparison(os.Stdout, result, render.NewOptions(os.Stdout, false, false))
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	if err := report.Write(os.Stdout, outputLocation, time.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    BenchmarkOutputFilename,
+		Rows:    comparisonRows(result),
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	}); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to benchmark the ingress of cluster(s) %s", strings.Join(failed, ","))
+	}
+	return nil
+}
+
+// ingressService returns the Knative Service created in every cluster, a min-scale of 1 keeps its pod warm
+func ingressService(inputs pkg.IngressBenchmarkArgs, index int) *servingv1.Service {
+	svc := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", inputs.SvcPrefix, index),
+			Namespace: inputs.Namespace,
+			Labels:    pkg.GeneratedLabels(inputs.RunID, 0, time.Now()),
+		},
+	}
+	svc.Spec.Template.Annotations = map[string]string{autoscaling.MinScaleAnnotationKey: "1"}
+	svc.Spec.Template.Spec.Containers = []corev1.Container{{
+		Image: service.ServiceImage,
+		Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+	}}
+	return svc
+}
+
+// ingressTarget is where the requests to a reachable service are sent to
+type ingressTarget struct {
+	endpoint string
+	host     string
+}
+
+// benchmarkCluster creates the services in the cluster, measures the programming of the ingress for each of them
+// and the data-path latency once all of them are reachable, and deletes them unless they are kept
+func benchmarkCluster(params *pkg.PerfParams, inputs pkg.IngressBenchmarkArgs) (pkg.IngressClusterResult, error) {
+	ctx := context.Background()
+	cluster := pkg.IngressClusterResult{Services: inputs.Number}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return cluster, err
+	}
+	networkingClient, err := params.NewNetworkingClient()
+	if err != nil {
+		return cluster, err
+	}
+	cluster.KnativeInfo = measure.GetKnativeInfo(ctx, params, measure.DefaultLogger)
+	fmt.Printf("Benchmarking ingress %s %s with %d Knative Service(s) in namespace %s\n", cluster.KnativeInfo.IngressController,
+		cluster.KnativeInfo.IngressVersion, inputs.Number, inputs.Namespace)
+
+	client := http.Client{Timeout: inputs.RequestTimeout}
+	var m sync.Mutex
+	created := []string{}
+	targets := []ingressTarget{}
+	var programming, firstRequest []float64
+	pool.ForEach(ctx, inputs.Concurrency, inputs.Number, func(ctx context.Context, i int) {
+		svc := ingressService(inputs, i)
+		fmt.Printf("Creating Knative Service %s in namespace %s\n", svc.Name, svc.Namespace)
+		start := time.Now()
+		if _, err := ksvcClient.Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{}); err != nil {
+			fmt.Printf("failed to create Knative Service %s in namespace %s: %s\n", svc.Name, svc.Namespace, err)
+			return
+		}
+		m.Lock()
+		created = append(created, svc.Name)
+		m.Unlock()
+		measurement, target, err := measureService(ctx, params, ksvcClient, networkingClient, client, inputs, svc.Name, start)
+		if err != nil {
+			fmt.Printf("failed to measure Knative Service %s in namespace %s: %s\n", svc.Name, svc.Namespace, err)
+			return
+		}
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Service %s: programmed after %fs, first request after %fs\n", svc.Name, measurement.Programming, measurement.FirstRequest)
+		}
+		m.Lock()
+		defer m.Unlock()
+		cluster.Measurement = append(cluster.Measurement, measurement)
+		targets = append(targets, target)
+		if measurement.Programming >= 0 {
+			programming = append(programming, measurement.Programming)
+		}
+		firstRequest = append(firstRequest, measurement.FirstRequest)
+	})
+	if !inputs.Keep {
+		defer func() {
+			for _, name := range created {
+				if err := ksvcClient.Services(inputs.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+					fmt.Printf("failed to delete Knative Service %s in namespace %s: %s\n", name, inputs.Namespace, err)
+				}
+			}
+		}()
+	}
+	cluster.Failed = inputs.Number - len(cluster.Measurement)
+	sort.Slice(cluster.Measurement, func(i, j int) bool {
+		return cluster.Measurement[i].ServiceName < cluster.Measurement[j].ServiceName
+	})
+	cluster.Programming = ingressLatency(programming)
+	cluster.FirstRequest = ingressLatency(firstRequest)
+	if len(targets) == 0 {
+		return cluster, fmt.Errorf("no Knative Service became reachable through ingress %s", cluster.KnativeInfo.IngressController)
+	}
+
+	fmt.Printf("Sending %d request(s) to each of %d Knative Service(s) over %d connection(s)\n", inputs.Requests, len(targets), inputs.Connections)
+	latencies := []float64{}
+	pool.ForEach(ctx, inputs.Connections, len(targets)*inputs.Requests, func(ctx context.Context, i int) {
+		latency, err := sendRequest(ctx, client, targets[i%len(targets)])
+		m.Lock()
+		defer m.Unlock()
+		cluster.DataPath.Requests++
+		if err != nil {
+			cluster.DataPath.Errors++
+			if inputs.Verbose {
+				fmt.Printf("[Verbose] request failed: %s\n", err)
+			}
+			return
+		}
+		latencies = append(latencies, latency)
+	})
+	if len(latencies) > 0 {
+		cluster.DataPath.P50, _ = stats.Percentile(latencies, 50)
+		cluster.DataPath.P95, _ = stats.Percentile(latencies, 95)
+		cluster.DataPath.P99, _ = stats.Percentile(latencies, 99)
+	}
+	return cluster, nil
+}
+
+// measureService waits until the service is ready and reachable through the ingress, and returns the programming
+// latency of its KIngress, -1 if the KIngress couldn't be read, with the latency of the first successful request
+func measureService(ctx context.Context, params *pkg.PerfParams, ksvcClient servingv1client.ServingV1Interface,
+	networkingClient networkingv1alpha1.NetworkingV1alpha1Interface, client http.Client, inputs pkg.IngressBenchmarkArgs,
+	name string, start time.Time) (pkg.IngressMeasurement, ingressTarget, error) {
+	measurement := pkg.IngressMeasurement{ServiceName: name, ServiceNamespace: inputs.Namespace, Programming: -1}
+	target := ingressTarget{}
+	if err := service.WaitServiceReady(ctx, ksvcClient, inputs.Namespace, name, inputs.Timeout); err != nil {
+		return measurement, target, err
+	}
+	svc, err := ksvcClient.Services(inputs.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return measurement, target, err
+	}
+	target.endpoint, err = service.ResolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
+	if err != nil {
+		return measurement, target, fmt.Errorf("failed to get the cluster endpoint: %w", err)
+	}
+	if svc.Status.URL != nil {
+		target.host = svc.Status.URL.URL().Host
+	}
+
+	// the KIngress is named like the Route of the service
+	if ing, err := networkingClient.Ingresses(inputs.Namespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		fmt.Printf("failed to get the KIngress of Knative Service %s in namespace %s: %s\n", name, inputs.Namespace, err)
+	} else if programmed := programmedTime(ing); !programmed.IsZero() {
+		measurement.Programming = programmed.Sub(ing.CreationTimestamp.Time).Seconds()
+	}
+
+	err = wait.PollImmediate(firstRequestInterval, inputs.Timeout, func() (bool, error) {
+		_, err := sendRequest(ctx, client, target)
+		return err == nil, nil
+	})
+	if err != nil {
+		return measurement, target, fmt.Errorf("not reachable through the ingress after %s", inputs.Timeout)
+	}
+	measurement.FirstRequest = time.Since(start).Seconds()
+	return measurement, target, nil
+}
+
+// programmedTime returns when the ingress reported the load balancer of the KIngress ready, or the KIngress ready if
+// the ingress doesn't report its load balancer
+func programmedTime(ing *netv1alpha1.Ingress) time.Time {
+	for _, t := range []apis.ConditionType{netv1alpha1.IngressConditionLoadBalancerReady, netv1alpha1.IngressConditionReady} {
+		if c := ing.Status.GetCondition(t); c != nil && c.IsTrue() {
+			return c.LastTransitionTime.Inner.Time
+		}
+	}
+	return time.Time{}
+}
+
+// sendRequest sends a single request through the ingress and returns its latency in seconds
+func sendRequest(ctx context.Context, client http.Client, target ingressTarget) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	if target.host != "" {
+		req.Host = target.host
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return time.Since(start).Seconds(), nil
+}
+
+// ingressLatency returns the percentiles and the maximum of the durations
+func ingressLatency(durations []float64) pkg.IngressLatency {
+	latency := pkg.IngressLatency{}
+	if len(durations) == 0 {
+		return latency
+	}
+	latency.P50, _ = stats.Percentile(durations, 50)
+	latency.P95, _ = stats.Percentile(durations, 95)
+	latency.P99, _ = stats.Percentile(durations, 99)
+	latency.Max, _ = stats.Max(durations)
+	return latency
+}
+
+// clusterName returns the name of the cluster in the comparison, the current context is named current
+func clusterName(cluster pkg.IngressClusterResult) string {
+	if cluster.Context == "" {
+		return "current"
+	}
+	return cluster.Context
+}
+
+// comparisonRows returns the rows of the CSV file with a row for every cluster
+func comparisonRows(result pkg.IngressBenchmarkResult) [][]string {
+	rows := [][]string{{"context", "ingress", "version", "services", "failed", "programming_p50", "programming_p99",
+		"first_request_p50", "first_request_p99", "data_path_requests", "data_path_errors", "data_path_p50", "data_path_p95", "data_path_p99"}}
+	for _, c := range result.Clusters {
+		rows = append(rows, []string{clusterName(c), c.KnativeInfo.IngressController, c.KnativeInfo.IngressVersion,
+			fmt.Sprintf("%d", c.Services),
+			fmt.Sprintf("%d", c.Failed),
+			fmt.Sprintf("%f", c.Programming.P50),
+			fmt.Sprintf("%f", c.Programming.P99),
+			fmt.Sprintf("%f", c.FirstRequest.P50),
+			fmt.Sprintf("%f", c.FirstRequest.P99),
+			fmt.Sprintf("%d", c.DataPath.Requests),
+			fmt.Sprintf("%d", c.DataPath.Errors),
+			fmt.Sprintf("%f", c.DataPath.P50),
+			fmt.Sprintf("%f", c.DataPath.P95),
+			fmt.Sprintf("%f", c.DataPath.P99),
+		})
+	}
+	return rows
+}
+
+// writeComparison writes the ingress of every cluster with its programming and data-path latencies, the bars compare
+// the data-path P99 of the clusters
+func writeComparison(w io.Writer, result pkg.IngressBenchmarkResult, options render.Options) {
+	var longest float64
+	for _, c := range result.Clusters {
+		if c.DataPath.P99 > longest {
+			longest = c.DataPath.P99
+		}
+	}
+	rows := make([]*render.Row, 0, len(result.Clusters))
+	for _, c := range result.Clusters {
+		row := &render.Row{Name: clusterName(c), Values: []string{
+			strings.TrimSpace(c.KnativeInfo.IngressController + " " + c.KnativeInfo.IngressVersion),
+			fmt.Sprintf("%d/%d", c.Services-c.Failed, c.Services),
+			fmt.Sprintf("%.3fs", c.Programming.P50),
+			fmt.Sprintf("%.3fs", c.Programming.P99),
+			fmt.Sprintf("%.3fs", c.FirstRequest.P50),
+			fmt.Sprintf("%.4fs", c.DataPath.P50),
+			fmt.Sprintf("%.4fs", c.DataPath.P99),
+			fmt.Sprintf("%d", c.DataPath.Errors),
+		}}
+		if longest > 0 {
+			row.Bar = c.DataPath.P99 / longest
+		}
+		rows = append(rows, row)
+	}
+	render.Table(w, []string{"CLUSTER", "INGRESS", "SERVICES", "PROGRAMMING P50", "PROGRAMMING P99", "FIRST REQUEST P50",
+		"DATA-PATH P50", "DATA-PATH P99", "ERRORS"}, rows, options)
+}
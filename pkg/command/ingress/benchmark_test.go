@@ -0,0 +1,188 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	networkingv1alpha1fake "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestIngressLatency(t *testing.T) {
+	assert.Equal(t, pkg.IngressLatency{}, ingressLatency(nil))
+	latency := ingressLatency([]float64{1, 2, 3, 4})
+	assert.Equal(t, 4.0, latency.Max)
+	assert.Assert(t, latency.P50 >= 2 && latency.P50 <= 3, latency.P50)
+	assert.Assert(t, latency.P99 > latency.P50 && latency.P99 <= 4, latency.P99)
+}
+
+func TestProgrammedTime(t *testing.T) {
+	created := time.Now().Add(-time.Minute)
+	ing := &netv1alpha1.Ingress{}
+	assert.Check(t, programmedTime(ing).IsZero())
+
+	ing.Status.Conditions = []apis.Condition{{Type: netv1alpha1.IngressConditionReady, Status: corev1.ConditionTrue,
+		LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(2 * time.Second))}}}
+	assert.Equal(t, created.Add(2*time.Second).Unix(), programmedTime(ing).Unix())
+
+	// the load balancer ready condition is preferred
+	ing.Status.Conditions = append(ing.Status.Conditions, apis.Condition{Type: netv1alpha1.IngressConditionLoadBalancerReady,
+		Status: corev1.ConditionTrue, LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Second))}})
+	assert.Equal(t, created.Add(time.Second).Unix(), programmedTime(ing).Unix())
+}
+
+func TestWriteComparison(t *testing.T) {
+	result := pkg.IngressBenchmarkResult{Clusters: []pkg.IngressClusterResult{
+		{Context: "kourier", KnativeInfo: pkg.KnativeInfo{IngressController: "Kourier", IngressVersion: "1.8.0"}, Services: 2,
+			Programming: pkg.IngressLatency{P50: 1, P99: 2}, DataPath: pkg.IngressDataPath{Requests: 10, P50: 0.01, P99: 0.02}},
+		{Context: "istio", KnativeInfo: pkg.KnativeInfo{IngressController: "Istio", IngressVersion: "1.15.0"}, Services: 2, Failed: 1,
+			Programming: pkg.IngressLatency{P50: 3, P99: 4}, DataPath: pkg.IngressDataPath{Requests: 5, Errors: 1, P50: 0.02, P99: 0.04}},
+	}}
+	out := &bytes.Buffer{}
+	writeComparison(out, result, render.Options{})
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("Kourier 1.8.0")), out.String())
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("1/2")), out.String())
+
+	rows := comparisonRows(result)
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, "istio", rows[2][0])
+	assert.Equal(t, "1", rows[2][4])
+	assert.Equal(t, "0.040000", rows[2][13])
+}
+
+func TestBenchmarkIngress(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+	url, err := apis.ParseURL(server.URL)
+	assert.NilError(t, err)
+
+	client := k8sfake.NewSimpleClientset()
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeNetworking := &networkingv1alpha1fake.FakeNetworkingV1alpha1{Fake: &client.Fake}
+	// the KIngresses are programmed a second after their creation
+	created := time.Now()
+	for i := 0; i < 2; i++ {
+		ing := &netv1alpha1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("kperf-ingress-%d", i), Namespace: "ns-1",
+			CreationTimestamp: metav1.NewTime(created)}}
+		ing.Status.Conditions = []apis.Condition{{Type: netv1alpha1.IngressConditionLoadBalancerReady, Status: corev1.ConditionTrue,
+			LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Second))}}}
+		_, err := fakeNetworking.Ingresses("ns-1").Create(context.Background(), ing, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	// the services are ready and served by the test server as soon as they are created
+	client.PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		svc := action.(clienttesting.CreateAction).GetObject().(*servingv1.Service)
+		svc.Status.URL = url
+		svc.Status.Conditions = []apis.Condition{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+		return false, nil, nil
+	})
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return fakeNetworking, nil
+		},
+	}
+
+	t.Run("current cluster", func(t *testing.T) {
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewIngressBenchmarkCommand(p), "--namespace", "ns-1", "--number", "2", "--requests", "5",
+			"--resolvable", "--run-id", "ingress-1", "--output", outputDir)
+		assert.NilError(t, err)
+		// the first request of each service and the requests of the data path
+		assert.Equal(t, int32(12), atomic.LoadInt32(&requests))
+
+		// the services are deleted
+		for _, name := range []string{"kperf-ingress-0", "kperf-ingress-1"} {
+			_, err := fakeServing.Services("ns-1").Get(context.Background(), name, metav1.GetOptions{})
+			assert.Check(t, apierrors.IsNotFound(err), name)
+		}
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "ingress-1", "*_"+BenchmarkOutputFilename+".json"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		result := pkg.IngressBenchmarkResult{}
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, 1, len(result.Clusters))
+		assert.Equal(t, pkg.IngressLatency{P50: 1, P95: 1, P99: 1, Max: 1}, result.Clusters[0].Programming)
+		assert.Equal(t, 10, result.Clusters[0].DataPath.Requests)
+		assert.Equal(t, 0, result.Clusters[0].DataPath.Errors)
+		assert.Equal(t, 2, len(result.Clusters[0].Measurement))
+	})
+
+	t.Run("contexts", func(t *testing.T) {
+		orig := contextParams
+		defer func() { contextParams = orig }()
+		contextParams = func(params *pkg.PerfParams, context string) (*pkg.PerfParams, error) {
+			if context == "missing" {
+				return nil, fmt.Errorf("context %q does not exist", context)
+			}
+			return params, nil
+		}
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewIngressBenchmarkCommand(p), "--namespace", "ns-1", "--number", "1", "--requests", "1",
+			"--resolvable", "--contexts", "kourier,missing", "--run-id", "ingress-2", "--output", outputDir)
+		assert.ErrorContains(t, err, "failed to benchmark the ingress of cluster(s) missing")
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "ingress-2", "*_"+BenchmarkOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		assert.Check(t, bytes.Contains(data, []byte("\nkourier,")), string(data))
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewIngressBenchmarkCommand(p), "--number", "1")
+		assert.ErrorContains(t, err, "requires --namespace")
+		_, err = testutil.ExecuteCommand(NewIngressBenchmarkCommand(p), "--namespace", "ns-1", "--contexts", "a,a")
+		assert.ErrorContains(t, err, "twice")
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewIngressCmd(p *pkg.PerfParams) *cobra.Command {
+	var ingressCmd = &cobra.Command{
+		Use:   "ingress",
+		Short: "Knative ingress benchmark",
+		Long: `Knative Serving ingress benchmark. For example:
+
+kperf ingress benchmark --namespace ns --number 10 --contexts kourier,istio - to compare the ingress of two clusters with the same services`,
+	}
+	ingressCmd.AddCommand(NewIngressBenchmarkCommand(p))
+
+	ingressCmd.InitDefaultHelpCmd()
+	return ingressCmd
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/generator"
+)
+
+func NewNamespaceCleanCommand(p *pkg.PerfParams) *cobra.Command {
+	cleanArgs := pkg.NamespaceCleanArgs{}
+	cleanCommand := &cobra.Command{
+		Use:   "clean",
+		Short: "clean namespaces",
+		Long: `clean the namespaces generated by kperf together with everything in them
+
+For example:
+# To clean the namespaces of a run
+kperf namespace clean --run-id 20220321091502-8c1d
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return pkg.ValidateRunID(cleanArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CleanNamespaces(p, cleanArgs)
+		},
+	}
+
+	cleanCommand.Flags().StringVarP(&cleanArgs.NamespacePrefix, "namespace-prefix", "", DefaultNamespacePrefix, "Namespace prefix. The namespaces with the prefix will be cleaned.")
+	cleanCommand.Flags().StringVarP(&cleanArgs.RunID, "run-id", "", "", "ID of the run whose namespaces will be cleaned instead of the ones with the prefix")
+	cleanCommand.Flags().IntVarP(&cleanArgs.Concurrency, "concurrency", "c", 10, "Number of multiple namespaces to clean at a time")
+	return cleanCommand
+}
+
+// CleanNamespaces used to clean the generated namespaces
+func CleanNamespaces(params *pkg.PerfParams, inputs pkg.NamespaceCleanArgs) error {
+	ctx := context.Background()
+	namespaces, err := listNamespaces(ctx, params, inputs.NamespacePrefix, inputs.RunID)
+	if err != nil {
+		return err
+	}
+	if len(namespaces) == 0 {
+		fmt.Println("No namespace found for cleaning")
+		return nil
+	}
+	matchedNsNameList := make([][2]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		matchedNsNameList = append(matchedNsNameList, [2]string{"", ns.Name})
+	}
+	generator.NewBatchCleaner(matchedNsNameList, inputs.Concurrency, func(_, name string) {
+		fmt.Printf("Delete namespace %s\n", name)
+		if err := params.ClientSet.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			fmt.Printf("Failed to delete namespace %s\n", name)
+		}
+	}).Clean()
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewNamespaceCleanCommand(t *testing.T) {
+	p, client := newTestPerfParams([]runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kperf-ns-0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kperf-ns-1", Labels: map[string]string{pkg.RunIDLabel: "run-1"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-0", Labels: map[string]string{pkg.RunIDLabel: "run-1"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	})
+	exists := func(name string) bool {
+		_, err := client.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		return !apierrors.IsNotFound(err)
+	}
+
+	_, err := testutil.ExecuteCommand(NewNamespaceCleanCommand(p), "--run-id", "run-1")
+	assert.NilError(t, err)
+	assert.Check(t, exists("kperf-ns-0"))
+	assert.Check(t, !exists("kperf-ns-1"))
+	assert.Check(t, !exists("other-0"))
+
+	_, err = testutil.ExecuteCommand(NewNamespaceCleanCommand(p))
+	assert.NilError(t, err)
+	assert.Check(t, !exists("kperf-ns-0"))
+	assert.Check(t, exists("default"))
+}
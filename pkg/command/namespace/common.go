@@ -0,0 +1,79 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"knative.dev/kperf/pkg"
+)
+
+const (
+	DefaultNamespacePrefix = "kperf-ns"
+
+	// WebhookReadyAnnotation is the annotation generate --probe-webhook records the duration from the creation of a
+	// namespace until the Knative webhooks admitted a Knative Service in it with
+	WebhookReadyAnnotation = "kperf.knative.dev/webhook-ready-after"
+)
+
+// namespaceName returns the name of the generated namespace with the index
+func namespaceName(prefix string, index int) string {
+	return fmt.Sprintf("%s-%d", prefix, index)
+}
+
+// parseLabels parses the labels given as key=value, the keys and values must be valid label keys and values
+func parseLabels(pairs []string) (map[string]string, error) {
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("expected a label like istio-injection=enabled, given %q", pair)
+		}
+		if errs := validation.IsQualifiedName(parts[0]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", parts[0], strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(parts[1]); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label value %q: %s", parts[1], strings.Join(errs, "; "))
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels, nil
+}
+
+// listNamespaces returns the generated namespaces, the ones of the run if the run ID is given and the ones with the
+// prefix otherwise, sorted by name
+func listNamespaces(ctx context.Context, params *pkg.PerfParams, prefix, runID string) ([]corev1.Namespace, error) {
+	nsList, err := params.ClientSet.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: pkg.RunSelector("", runID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaces := []corev1.Namespace{}
+	for _, ns := range nsList.Items {
+		if runID != "" || strings.HasPrefix(ns.Name, prefix+"-") {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/generator"
+)
+
+const (
+	// webhookProbeName is the name of the Knative Service created with dry run to probe the Knative webhooks
+	webhookProbeName = "kperf-webhook-probe"
+	// webhookProbeInterval is the interval of the dry run requests until the Knative webhooks admit the first one
+	webhookProbeInterval = 100 * time.Millisecond
+)
+
+func NewNamespaceGenerateCommand(p *pkg.PerfParams) *cobra.Command {
+	generateArgs := pkg.NamespaceGenerateArgs{}
+
+	generateCommand := &cobra.Command{
+		Use:   "generate",
+		Short: "generate namespaces",
+		Long: `generate namespaces with the labels the downstream controllers select them by
+
+The namespaces are named <namespace-prefix>-<index> and labeled with the --label labels, e.g. istio-injection=enabled
+for the Istio sidecar injector or the label of the namespace-wildcard-cert-selector of the config-network ConfigMap
+for the wildcard certificates of Knative. With --probe-webhook a Knative Service is created with dry run in every new
+namespace until the Knative webhooks admit it, and the duration is recorded in the annotation
+kperf.knative.dev/webhook-ready-after of the namespace for 'kperf namespace measure'.
+
+For example:
+# To generate 50 namespaces injected by Istio, 10 every 5 seconds
+kperf namespace generate -n 50 --interval 5 --batch 10 --label istio-injection=enabled --probe-webhook
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := parseLabels(generateArgs.Labels); err != nil {
+				return err
+			}
+			return pkg.ValidateRunID(generateArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GenerateNamespaces(p, generateArgs)
+		},
+	}
+	generateCommand.Flags().IntVarP(&generateArgs.Number, "number", "n", 0, "Total number of namespaces to be created")
+	generateCommand.MarkFlagRequired("number")
+	generateCommand.Flags().IntVarP(&generateArgs.Interval, "interval", "i", 0, "Interval for each batch generation")
+	generateCommand.MarkFlagRequired("interval")
+	generateCommand.Flags().IntVarP(&generateArgs.Batch, "batch", "b", 0, "Number of namespaces each time to be created")
+	generateCommand.MarkFlagRequired("batch")
+	generateCommand.Flags().IntVarP(&generateArgs.Concurrency, "concurrency", "c", 10, "Number of multiple namespaces to make at a time")
+	generateCommand.Flags().StringVarP(&generateArgs.NamespacePrefix, "namespace-prefix", "", DefaultNamespacePrefix, "Namespace prefix. The namespaces will be <namespace-prefix>-0, <namespace-prefix>-1 and etc.")
+	generateCommand.Flags().StringArrayVarP(&generateArgs.Labels, "label", "", nil, "Label key=value of the namespaces, e.g. istio-injection=enabled, can be repeated")
+	generateCommand.Flags().BoolVarP(&generateArgs.ProbeWebhook, "probe-webhook", "", false, "Create a Knative Service with dry run in every new namespace until the Knative webhooks admit it, and record the duration")
+	generateCommand.Flags().DurationVarP(&generateArgs.Timeout, "timeout", "", time.Minute, "Duration to probe the Knative webhooks in a namespace")
+	generateCommand.Flags().StringVarP(&generateArgs.RunID, "run-id", "", "", "ID of the run the generated namespaces are labeled with as "+pkg.RunIDLabel+". A new ID is generated by default")
+	return generateCommand
+}
+
+// GenerateNamespaces used to generate namespaces in batches
+func GenerateNamespaces(params *pkg.PerfParams, inputs pkg.NamespaceGenerateArgs) error {
+	labels, err := parseLabels(inputs.Labels)
+	if err != nil {
+		return err
+	}
+	var ksvcClient servingv1client.ServingV1Interface
+	if inputs.ProbeWebhook {
+		ksvcClient, err = params.NewServingClient()
+		if err != nil {
+			return err
+		}
+	}
+	if inputs.RunID == "" {
+		inputs.RunID = pkg.NewRunID(time.Now())
+	}
+	fmt.Printf("Run ID %s, clean up the run with 'kperf namespace clean --run-id %s'\n", inputs.RunID, inputs.RunID)
+
+	createNamespaceFunc := func(_ string, index int) (string, string) {
+		name := namespaceName(inputs.NamespacePrefix, index)
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: pkg.GeneratedLabels(inputs.RunID, 0, time.Now())}}
+		for k, v := range labels {
+			namespace.Labels[k] = v
+		}
+		fmt.Printf("Creating namespace %s\n", name)
+		start := time.Now()
+		if _, err := params.ClientSet.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{}); err != nil {
+			fmt.Printf("failed to create namespace %s : %s\n", name, err)
+			return "", name
+		}
+		if ksvcClient != nil {
+			if err := probeWebhook(context.TODO(), params, ksvcClient, name, start, inputs.Timeout); err != nil {
+				fmt.Printf("failed to probe the Knative webhooks in namespace %s : %s\n", name, err)
+			}
+		}
+		return "", name
+	}
+	generator.NewBatchGenerator(time.Duration(inputs.Interval)*time.Second, inputs.Number, inputs.Batch, inputs.Concurrency, []string{""}, createNamespaceFunc, func(ns, name string) error { return nil }).
+		WithClock(params.Clock).Generate()
+	return nil
+}
+
+// probeWebhook creates a Knative Service with dry run in the namespace until the Knative webhooks admit it, and
+// records the duration since the namespace was created at start in the annotation of the namespace
+func probeWebhook(ctx context.Context, params *pkg.PerfParams, ksvcClient servingv1client.ServingV1Interface, namespace string, start time.Time, timeout time.Duration) error {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: webhookProbeName, Namespace: namespace}}
+	svc.Spec.Template.Spec.Containers = []corev1.Container{{Image: service.ServiceImage}}
+	var lastErr error
+	err := wait.PollImmediate(webhookProbeInterval, timeout, func() (bool, error) {
+		_, lastErr = ksvcClient.Services(namespace).Create(ctx, svc, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("not admitted after %s: %v", timeout, lastErr)
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{
+		"annotations": map[string]string{WebhookReadyAnnotation: time.Since(start).String()},
+	}})
+	if err != nil {
+		return err
+	}
+	_, err = params.ClientSet.CoreV1().Namespaces().Patch(ctx, namespace, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
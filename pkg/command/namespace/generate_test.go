@@ -0,0 +1,70 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewNamespaceGenerateCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for namespace generate", func(t *testing.T) {
+		p, _ := newTestPerfParams(nil)
+
+		_, err := testutil.ExecuteCommand(NewNamespaceGenerateCommand(p))
+		assert.ErrorContains(t, err, "required flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewNamespaceGenerateCommand(p), "-n", "1", "-i", "1", "-b", "1", "--label", "istio-injection")
+		assert.ErrorContains(t, err, "expected a label like istio-injection=enabled")
+	})
+
+	t.Run("generate namespaces as expected", func(t *testing.T) {
+		p, client := newTestPerfParams(nil)
+		// the Knative webhooks reject the first Knative Service, the dry run Knative Services are not stored
+		probes := 0
+		client.PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			probes++
+			if probes == 1 {
+				return true, nil, errors.New("failed calling webhook")
+			}
+			return true, action.(clienttesting.CreateAction).GetObject(), nil
+		})
+
+		_, err := testutil.ExecuteCommand(NewNamespaceGenerateCommand(p), "-n", "2", "-i", "1", "-b", "2", "-c", "1", "--label", "istio-injection=enabled",
+			"--probe-webhook", "--run-id", "ns-1")
+		assert.NilError(t, err)
+		assert.Equal(t, 3, probes)
+
+		for _, name := range []string{"kperf-ns-0", "kperf-ns-1"} {
+			ns, err := client.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, "enabled", ns.Labels["istio-injection"])
+			assert.Equal(t, "ns-1", ns.Labels[pkg.RunIDLabel])
+			ready, err := time.ParseDuration(ns.Annotations[WebhookReadyAnnotation])
+			assert.NilError(t, err, name)
+			assert.Check(t, ready > 0, name)
+		}
+	})
+}
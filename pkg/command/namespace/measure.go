@@ -0,0 +1,291 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/networking/pkg/apis/networking"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/apis"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	NamespaceOutputFilename = "namespace_setup_time"
+
+	// ProbeServiceAccount is ready when the service account controller created the default ServiceAccount
+	ProbeServiceAccount = "serviceaccount"
+	// ProbeIstioRootCert is ready when istiod distributed the root certificate the injected sidecars start with
+	ProbeIstioRootCert = "istio-ca-root-cert"
+	// ProbeWildcardCertificate is ready when the wildcard certificate of the namespace requested by Knative is ready,
+	// e.g. issued by cert-manager
+	ProbeWildcardCertificate = "wildcard-certificate"
+	// ProbeWebhook is ready when the Knative webhooks admitted a Knative Service in the namespace, it is recorded by
+	// generate --probe-webhook
+	ProbeWebhook = "knative-webhook"
+
+	defaultServiceAccount  = "default"
+	istioRootCertConfigMap = "istio-ca-root-cert"
+)
+
+// Probes are the probes measured by default in the order they are reported
+var Probes = []string{ProbeServiceAccount, ProbeIstioRootCert, ProbeWildcardCertificate, ProbeWebhook}
+
+// CertificateGVR is the resource of the certificates Knative requests, e.g. from cert-manager
+var CertificateGVR = netv1alpha1.SchemeGroupVersion.WithResource("certificates")
+
+func NewNamespaceMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	measureArgs := pkg.NamespaceMeasureArgs{}
+	measureCommand := &cobra.Command{
+		Use:   "measure",
+		Short: "Measure the setup of namespaces",
+		Long: `Measure how long the downstream controllers took to set up the generated namespaces
+
+For every namespace the time from its creation until each probe was ready is measured:
+  serviceaccount        the default ServiceAccount was created
+  istio-ca-root-cert    istiod distributed its root certificate, which the injected sidecars need to start
+  wildcard-certificate  the wildcard certificate of the namespace requested by Knative is ready, e.g. from cert-manager
+  knative-webhook       the Knative webhooks admitted a Knative Service, recorded by 'namespace generate --probe-webhook'
+A probe is missing in a namespace if it never became ready, e.g. because the controller isn't installed or the
+namespace isn't selected by it. The durations have the second precision of the timestamps of the API server, except
+the one of knative-webhook.
+
+For example:
+# To measure the namespaces with prefix kperf-ns
+kperf namespace measure --namespace-prefix kperf-ns
+
+# To measure only the default ServiceAccount and the Istio root certificate of the namespaces of a run
+kperf namespace measure --run-id 20220321091502-8c1d --probes serviceaccount,istio-ca-root-cert
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			for _, probe := range measureArgs.Probes {
+				if !isProbe(probe) {
+					return fmt.Errorf("unknown probe %s, expected one of %v", probe, Probes)
+				}
+			}
+			return pkg.ValidateRunID(measureArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureNamespaces(p, measureArgs)
+		},
+	}
+
+	measureCommand.Flags().StringVarP(&measureArgs.NamespacePrefix, "namespace-prefix", "", DefaultNamespacePrefix, "Namespace prefix. The namespaces with the prefix will be measured")
+	measureCommand.Flags().StringSliceVarP(&measureArgs.Probes, "probes", "", Probes, "Comma separated probes to measure")
+	measureCommand.Flags().BoolVarP(&measureArgs.Verbose, "verbose", "v", false, "Namespace verbose result")
+	measureCommand.Flags().StringVarP(&measureArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(measureCommand.Flags(), &measureArgs.OutputFormats)
+	measureCommand.Flags().StringVarP(&measureArgs.RunID, "run-id", "", "", "ID of the run whose namespaces are measured instead of the ones with the prefix, the results are written to the subdirectory of the output location named by it")
+	return measureCommand
+}
+
+func isProbe(probe string) bool {
+	for _, p := range Probes {
+		if p == probe {
+			return true
+		}
+	}
+	return false
+}
+
+// MeasureNamespaces used to measure the time until the probes are ready in the generated namespaces
+func MeasureNamespaces(params *pkg.PerfParams, inputs pkg.NamespaceMeasureArgs) error {
+	ctx := context.Background()
+	namespaces, err := listNamespaces(ctx, params, inputs.NamespacePrefix, inputs.RunID)
+	if err != nil {
+		return err
+	}
+	if len(namespaces) == 0 {
+		return errors.New("no namespace found to measure")
+	}
+	var dynamicClient dynamic.Interface
+	for _, probe := range inputs.Probes {
+		if probe == ProbeWildcardCertificate {
+			dynamicClient, err = params.NewDynamicClient()
+			if err != nil {
+				return fmt.Errorf("failed to create dynamic client %s\n", err)
+			}
+		}
+	}
+
+	result := pkg.NamespaceSetupResult{Namespaces: len(namespaces)}
+	durations := map[string][]float64{}
+	for i := range namespaces {
+		ns := &namespaces[i]
+		setup := pkg.NamespaceSetup{Namespace: ns.Name, Probes: map[string]float64{}}
+		for _, probe := range inputs.Probes {
+			ready, err := probeDuration(ctx, params, dynamicClient, ns, probe)
+			if err != nil {
+				if inputs.Verbose {
+					fmt.Printf("[Verbose] Namespace %s: %s not ready: %s\n", ns.Name, probe, err)
+				}
+				continue
+			}
+			setup.Probes[probe] = ready.Seconds()
+			durations[probe] = append(durations[probe], ready.Seconds())
+			if inputs.Verbose {
+				fmt.Printf("[Verbose] Namespace %s: %s ready after %fs\n", ns.Name, probe, ready.Seconds())
+			}
+		}
+		result.Measurement = append(result.Measurement, setup)
+	}
+	for _, probe := range inputs.Probes {
+		result.Probes = append(result.Probes, probeStatistics(probe, durations[probe], len(namespaces)))
+	}
+	result.KnativeInfo = measure.GetKnativeInfo(ctx, params, measure.DefaultLogger)
+
+	fmt.Printf("-------- Measurement --------\n")
+	writeProbes(os.Stdout, result, render.NewOptions(os.Stdout, false, false))
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	return report.Write(os.Stdout, outputLocation, time.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    NamespaceOutputFilename,
+		Rows:    setupRows(result, inputs.Probes),
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// probeDuration returns the duration from the creation of the namespace until the probe was ready, or an error if
+// it isn't ready
+func probeDuration(ctx context.Context, params *pkg.PerfParams, dynamicClient dynamic.Interface, ns *corev1.Namespace, probe string) (time.Duration, error) {
+	created := ns.CreationTimestamp.Time
+	switch probe {
+	case ProbeServiceAccount:
+		sa, err := params.ClientSet.CoreV1().ServiceAccounts(ns.Name).Get(ctx, defaultServiceAccount, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return sa.CreationTimestamp.Sub(created), nil
+	case ProbeIstioRootCert:
+		cm, err := params.ClientSet.CoreV1().ConfigMaps(ns.Name).Get(ctx, istioRootCertConfigMap, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return cm.CreationTimestamp.Sub(created), nil
+	case ProbeWildcardCertificate:
+		return wildcardCertificateReady(ctx, dynamicClient, ns)
+	case ProbeWebhook:
+		value, ok := ns.Annotations[WebhookReadyAnnotation]
+		if !ok {
+			return 0, fmt.Errorf("annotation %s not found, generate the namespace with --probe-webhook", WebhookReadyAnnotation)
+		}
+		return time.ParseDuration(value)
+	}
+	return 0, fmt.Errorf("unknown probe %s", probe)
+}
+
+// wildcardCertificateReady returns the duration from the creation of the namespace until the wildcard certificate
+// Knative requested for it was ready
+func wildcardCertificateReady(ctx context.Context, dynamicClient dynamic.Interface, ns *corev1.Namespace) (time.Duration, error) {
+	list, err := dynamicClient.Resource(CertificateGVR).Namespace(ns.Name).List(ctx, metav1.ListOptions{LabelSelector: networking.WildcardCertDomainLabelKey})
+	if err != nil {
+		return 0, err
+	}
+	if len(list.Items) == 0 {
+		return 0, apierrors.NewNotFound(CertificateGVR.GroupResource(), "wildcard certificate")
+	}
+	cert := &netv1alpha1.Certificate{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[0].Object, cert); err != nil {
+		return 0, fmt.Errorf("failed to convert Certificate %s: %w", list.Items[0].GetName(), err)
+	}
+	condition := cert.Status.GetCondition(apis.ConditionReady)
+	if condition == nil || !condition.IsTrue() {
+		return 0, fmt.Errorf("certificate %s is not ready", cert.Name)
+	}
+	return condition.LastTransitionTime.Inner.Sub(ns.CreationTimestamp.Time), nil
+}
+
+// probeStatistics returns the statistics of the durations of the probe over the namespaces
+func probeStatistics(probe string, durations []float64, namespaces int) pkg.NamespaceProbeResult {
+	result := pkg.NamespaceProbeResult{Probe: probe, Ready: len(durations), Missing: namespaces - len(durations)}
+	if len(durations) == 0 {
+		return result
+	}
+	result.Average, _ = stats.Mean(durations)
+	result.P50, _ = stats.Percentile(durations, 50)
+	result.P95, _ = stats.Percentile(durations, 95)
+	result.Max, _ = stats.Max(durations)
+	return result
+}
+
+// setupRows returns the rows of the CSV file with a row for every namespace and a column for every probe, the
+// columns of the probes which are missing in a namespace are empty
+func setupRows(result pkg.NamespaceSetupResult, probes []string) [][]string {
+	rows := [][]string{append([]string{"namespace"}, probes...)}
+	for _, setup := range result.Measurement {
+		row := []string{setup.Namespace}
+		for _, probe := range probes {
+			value := ""
+			if seconds, ok := setup.Probes[probe]; ok {
+				value = fmt.Sprintf("%f", seconds)
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// writeProbes writes the statistics of every probe, the bars compare the 95th percentiles and probes missing in a
+// namespace are highlighted
+func writeProbes(w io.Writer, result pkg.NamespaceSetupResult, options render.Options) {
+	fmt.Fprintf(w, "Namespaces Total: %d\n", result.Namespaces)
+	var longest float64
+	for _, p := range result.Probes {
+		if p.P95 > longest {
+			longest = p.P95
+		}
+	}
+	rows := make([]*render.Row, 0, len(result.Probes))
+	for _, p := range result.Probes {
+		row := &render.Row{Name: p.Probe, Values: []string{
+			fmt.Sprintf("%d", p.Ready),
+			fmt.Sprintf("%d", p.Missing),
+			fmt.Sprintf("%.3fs", p.Average),
+			fmt.Sprintf("%.3fs", p.P50),
+			fmt.Sprintf("%.3fs", p.P95),
+			fmt.Sprintf("%.3fs", p.Max),
+		}}
+		if longest > 0 {
+			row.Bar = p.P95 / longest
+		}
+		if p.Missing > 0 {
+			row.Style = render.Red
+		}
+		rows = append(rows, row)
+	}
+	render.Table(w, []string{"PROBE", "READY", "MISSING", "AVERAGE", "P50", "P95", "MAX"}, rows, options)
+}
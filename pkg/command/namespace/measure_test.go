@@ -0,0 +1,124 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/networking/pkg/apis/networking"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/apis"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+// created is the creation time of the namespaces of the tests
+var created = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestObjectMeta returns the meta of an object created the given number of seconds after the namespaces
+func newTestObjectMeta(ns, name string, seconds int) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: ns, CreationTimestamp: metav1.NewTime(created.Add(time.Duration(seconds) * time.Second))}
+}
+
+// newTestCertificate returns the wildcard certificate of the namespace which became ready after the given number of
+// seconds
+func newTestCertificate(t *testing.T, ns string, seconds int) *unstructured.Unstructured {
+	cert := &netv1alpha1.Certificate{
+		TypeMeta:   metav1.TypeMeta{APIVersion: netv1alpha1.SchemeGroupVersion.String(), Kind: "Certificate"},
+		ObjectMeta: newTestObjectMeta(ns, ns+".example.com", 1),
+	}
+	cert.Labels = map[string]string{networking.WildcardCertDomainLabelKey: "example.com"}
+	cert.Status.Conditions = []apis.Condition{{Type: apis.ConditionReady, Status: corev1.ConditionTrue,
+		LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Duration(seconds) * time.Second))}}}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cert)
+	assert.NilError(t, err)
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func TestNewNamespaceMeasureCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for namespace measure", func(t *testing.T) {
+		p, _ := newTestPerfParams(nil)
+
+		_, err := testutil.ExecuteCommand(NewNamespaceMeasureCommand(p), "--probes", "unknown")
+		assert.ErrorContains(t, err, "unknown probe unknown")
+
+		_, err = testutil.ExecuteCommand(NewNamespaceMeasureCommand(p))
+		assert.ErrorContains(t, err, "no namespace found to measure")
+	})
+
+	t.Run("measure namespaces as expected", func(t *testing.T) {
+		injected := &corev1.Namespace{ObjectMeta: newTestObjectMeta("", "kperf-ns-0", 0)}
+		injected.Labels = map[string]string{pkg.RunIDLabel: "run-1"}
+		injected.Annotations = map[string]string{WebhookReadyAnnotation: "1.5s"}
+		plain := &corev1.Namespace{ObjectMeta: newTestObjectMeta("", "kperf-ns-1", 0)}
+		plain.Labels = map[string]string{pkg.RunIDLabel: "run-1"}
+		p, _ := newTestPerfParams([]runtime.Object{
+			injected, plain,
+			&corev1.ServiceAccount{ObjectMeta: newTestObjectMeta("kperf-ns-0", defaultServiceAccount, 1)},
+			&corev1.ServiceAccount{ObjectMeta: newTestObjectMeta("kperf-ns-1", defaultServiceAccount, 3)},
+			&corev1.ConfigMap{ObjectMeta: newTestObjectMeta("kperf-ns-0", istioRootCertConfigMap, 2)},
+		}, newTestCertificate(t, "kperf-ns-0", 5), newTestCertificate(t, "kperf-ns-1", 7))
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewNamespaceMeasureCommand(p), "--run-id", "run-1", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "run-1", "*_"+NamespaceOutputFilename+".json"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		result := pkg.NamespaceSetupResult{}
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, 2, result.Namespaces)
+		assert.DeepEqual(t, []pkg.NamespaceProbeResult{
+			{Probe: ProbeServiceAccount, Ready: 2, Average: 2, P50: 1, P95: 2, Max: 3},
+			{Probe: ProbeIstioRootCert, Ready: 1, Missing: 1, Average: 2, P50: 2, P95: 2, Max: 2},
+			{Probe: ProbeWildcardCertificate, Ready: 2, Average: 6, P50: 5, P95: 6, Max: 7},
+			{Probe: ProbeWebhook, Ready: 1, Missing: 1, Average: 1.5, P50: 1.5, P95: 1.5, Max: 1.5},
+		}, result.Probes)
+
+		matches, err = filepath.Glob(filepath.Join(outputDir, "run-1", "*_"+NamespaceOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err = ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		assert.Check(t, bytes.Contains(data, []byte("\nkperf-ns-1,3.000000,,7.000000,\n")), string(data))
+	})
+}
+
+func TestWriteProbes(t *testing.T) {
+	result := pkg.NamespaceSetupResult{Namespaces: 2, Probes: []pkg.NamespaceProbeResult{
+		{Probe: ProbeServiceAccount, Ready: 2, P50: 1, P95: 2, Max: 2},
+		{Probe: ProbeIstioRootCert, Ready: 1, Missing: 1, P50: 4, P95: 4, Max: 4},
+	}}
+	out := &bytes.Buffer{}
+	writeProbes(out, result, render.Options{})
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("Namespaces Total: 2")), out.String())
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("istio-ca-root-cert")), out.String())
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("4.000s")), out.String())
+}
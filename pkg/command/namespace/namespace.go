@@ -0,0 +1,37 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewNamespaceCmd(p *pkg.PerfParams) *cobra.Command {
+	var namespaceCmd = &cobra.Command{
+		Use:   "namespace",
+		Short: "Namespace creation benchmark",
+		Long: `Namespace creation benchmark and measurement. For example:
+
+kperf namespace generate -n 50 -i 5 -b 10 --label istio-injection=enabled - to create 50 namespaces injected by Istio
+kperf namespace measure --namespace-prefix kperf-ns - to measure how long the controllers took to set them up`,
+	}
+	namespaceCmd.AddCommand(NewNamespaceGenerateCommand(p))
+	namespaceCmd.AddCommand(NewNamespaceMeasureCommand(p))
+	namespaceCmd.AddCommand(NewNamespaceCleanCommand(p))
+
+	namespaceCmd.InitDefaultHelpCmd()
+	return namespaceCmd
+}
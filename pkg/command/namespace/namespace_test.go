@@ -0,0 +1,71 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestNewNamespaceCmd(t *testing.T) {
+	cmd := NewNamespaceCmd(nil)
+	assert.Check(t, cmd.HasSubCommands(), "cmd namespace should have subcommands")
+
+	for _, sub := range []string{"generate", "measure", "clean"} {
+		_, _, err := cmd.Find([]string{sub})
+		assert.NilError(t, err, "namespace command should have %s subcommand", sub)
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := parseLabels([]string{"istio-injection=enabled", "example.com/wildcard="})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"istio-injection": "enabled", "example.com/wildcard": ""}, labels)
+
+	_, err = parseLabels([]string{"istio-injection"})
+	assert.ErrorContains(t, err, "expected a label like istio-injection=enabled")
+	_, err = parseLabels([]string{"-invalid=true"})
+	assert.ErrorContains(t, err, "invalid label key")
+	_, err = parseLabels([]string{"key=not valid"})
+	assert.ErrorContains(t, err, "invalid label value")
+}
+
+func newTestPerfParams(objects []runtime.Object, dynamicObjects ...runtime.Object) (*pkg.PerfParams, *k8sfake.Clientset) {
+	client := k8sfake.NewSimpleClientset(objects...)
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeDynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		CertificateGVR: "CertificateList",
+	}, dynamicObjects...)
+
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewDynamicClient: func() (dynamic.Interface, error) {
+			return fakeDynamic, nil
+		},
+	}, client
+}
@@ -0,0 +1,190 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/compare"
+	"knative.dev/kperf/pkg/command/utils"
+	reportwriter "knative.dev/kperf/pkg/report"
+)
+
+const (
+	DiffOutputFilename = "diff"
+)
+
+// NewDiffCommand implements 'kperf report diff' command
+func NewDiffCommand(p *pkg.PerfParams) *cobra.Command {
+	diffArgs := pkg.DiffArgs{}
+	diffCommand := &cobra.Command{
+		Use:   "diff OLD NEW",
+		Short: "Print the changes between the measure results of two runs",
+		Long: `Print the changes between two measure result JSON files saved by 'kperf service measure'
+
+Every duration of the results, i.e. the average of each phase and the overall percentiles, is compared. An increase
+of at least the threshold is a regression and the command fails, so it can be used in CI jobs to detect
+regressions of the Knative control plane between versions.
+
+For example:
+# To compare the measure result of a new Knative version with the previous version and fail on increases of 20% or more
+kperf report diff /tmp/20210117104747_ksvc_creation_time.json /tmp/20210118104747_ksvc_creation_time.json --threshold 20
+`,
+		Args: cobra.ExactArgs(2),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if diffArgs.Threshold < 0 {
+				return fmt.Errorf("threshold must not be negative, given %v", diffArgs.Threshold)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			diffArgs.Old = args[0]
+			diffArgs.New = args[1]
+			return DiffResults(p, diffArgs)
+		},
+	}
+
+	diffCommand.Flags().Float64VarP(&diffArgs.Threshold, "threshold", "", 10, "Relative increase in percent from which a change is a regression")
+	diffCommand.Flags().StringVarP(&diffArgs.Output, "output", "o", ".", "Diff result location")
+	reportwriter.AddFlag(diffCommand.Flags(), &diffArgs.OutputFormats)
+	return diffCommand
+}
+
+// DiffResults compares two measure results and returns an error if any duration regressed
+func DiffResults(params *pkg.PerfParams, inputs pkg.DiffArgs) error {
+	oldResult, err := readMeasureResult(inputs.Old)
+	if err != nil {
+		return err
+	}
+	newResult, err := readMeasureResult(inputs.New)
+	if err != nil {
+		return err
+	}
+
+	result := pkg.DiffResult{
+		Old:            inputs.Old,
+		New:            inputs.New,
+		Threshold:      inputs.Threshold,
+		OldKnativeInfo: oldResult.KnativeInfo,
+		NewKnativeInfo: newResult.KnativeInfo,
+		Metrics:        diffMetrics(oldResult.Result, newResult.Result, inputs.Threshold),
+	}
+
+	fmt.Printf("Old: %s (Serving %s)\n", inputs.Old, oldResult.KnativeInfo.ServingVersion)
+	fmt.Printf("New: %s (Serving %s)\n", inputs.New, newResult.KnativeInfo.ServingVersion)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "METRIC\tOLD\tNEW\tDELTA\tDELTA %%\tVERDICT\n")
+	regressions := 0
+	for _, m := range result.Metrics {
+		fmt.Fprintf(w, "%s\t%fs\t%fs\t%+fs\t%+.2f%%\t%s\n", m.Metric, m.Old, m.New, m.Delta, m.DeltaPercent, m.Verdict)
+		if m.Verdict == compare.VerdictRegression {
+			regressions++
+		}
+	}
+	w.Flush()
+
+	rows := [][]string{{"metric", "old", "new", "delta", "delta_percent", "verdict"}}
+	for _, m := range result.Metrics {
+		rows = append(rows, []string{m.Metric,
+			fmt.Sprintf("%f", m.Old),
+			fmt.Sprintf("%f", m.New),
+			fmt.Sprintf("%f", m.Delta),
+			fmt.Sprintf("%f", m.DeltaPercent),
+			m.Verdict,
+		})
+	}
+
+	current := time.Now()
+	outputLocation, err := utils.CheckOutputLocation(inputs.Output)
+	if err != nil {
+		fmt.Printf("failed to check diff output location: %s\n", err)
+	}
+	if err := reportwriter.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, reportwriter.Report{
+		Name:    DiffOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	}); err != nil {
+		return err
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("%d regression(s) of at least %.2f%% found", regressions, inputs.Threshold)
+	}
+	return nil
+}
+
+func readMeasureResult(path string) (pkg.MeasureResult, error) {
+	result := pkg.MeasureResult{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return result, fmt.Errorf("failed to read measure result %s: %s", path, err)
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("failed to parse measure result %s: %s", path, err)
+	}
+	return result, nil
+}
+
+// diffMetrics compares every duration of the results in the order of the result fields. The metrics are
+// named after their JSON keys, so they match the keys of the measure result files. Total is skipped as it
+// is the sum over all services and depends on the number of services.
+func diffMetrics(oldResult, newResult pkg.Result, threshold float64) []pkg.MetricDiff {
+	var metrics []pkg.MetricDiff
+	oldValue := reflect.ValueOf(oldResult)
+	newValue := reflect.ValueOf(newResult)
+	resultType := oldValue.Type()
+	for i := 0; i < resultType.NumField(); i++ {
+		field := resultType.Field(i)
+		if field.Type.Kind() != reflect.Float64 {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		if name == "Total" {
+			continue
+		}
+		metrics = append(metrics, diffMetric(name, oldValue.Field(i).Float(), newValue.Field(i).Float(), threshold))
+	}
+	return metrics
+}
+
+// diffMetric compares a single duration, the relative change is zero if the old duration is zero
+func diffMetric(name string, oldValue, newValue, threshold float64) pkg.MetricDiff {
+	m := pkg.MetricDiff{Metric: name, Old: oldValue, New: newValue, Delta: newValue - oldValue, Verdict: compare.VerdictNoChange}
+	if oldValue != 0 {
+		m.DeltaPercent = m.Delta / oldValue * 100
+	}
+	switch {
+	case oldValue == 0:
+	case m.Delta > 0 && m.DeltaPercent >= threshold:
+		m.Verdict = compare.VerdictRegression
+	case m.Delta < 0 && m.DeltaPercent <= -threshold:
+		m.Verdict = compare.VerdictImprovement
+	}
+	return m
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/compare"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func writeMeasureResult(t *testing.T, dir, name string, result pkg.Result) string {
+	data, err := json.Marshal(pkg.MeasureResult{Result: result})
+	assert.NilError(t, err)
+	path := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestNewDiffCommand(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := writeMeasureResult(t, dir, "old.json", pkg.Result{AverageRevisionReadySum: 10, P95: 20, OverallTotal: 100})
+	samePath := writeMeasureResult(t, dir, "same.json", pkg.Result{AverageRevisionReadySum: 10.5, P95: 19, OverallTotal: 300})
+	slowerPath := writeMeasureResult(t, dir, "slower.json", pkg.Result{AverageRevisionReadySum: 10, P95: 30, OverallTotal: 100})
+
+	t.Run("incompleted or wrong args for report diff", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewDiffCommand(&pkg.PerfParams{}), oldPath)
+		assert.ErrorContains(t, err, "accepts 2 arg(s), received 1")
+
+		_, err = testutil.ExecuteCommand(NewDiffCommand(&pkg.PerfParams{}), oldPath, samePath, "--threshold", "-1")
+		assert.ErrorContains(t, err, "threshold must not be negative")
+
+		_, err = testutil.ExecuteCommand(NewDiffCommand(&pkg.PerfParams{}), oldPath, filepath.Join(dir, "missing.json"))
+		assert.ErrorContains(t, err, "failed to read measure result")
+	})
+
+	t.Run("diff without regression", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewDiffCommand(&pkg.PerfParams{}), oldPath, samePath, "--output", dir)
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(dir, "*_"+DiffOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+	})
+
+	t.Run("diff with regression", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewDiffCommand(&pkg.PerfParams{}), oldPath, slowerPath, "--threshold", "20", "--output", t.TempDir())
+		assert.ErrorContains(t, err, "1 regression(s) of at least 20.00% found")
+	})
+}
+
+func TestDiffMetrics(t *testing.T) {
+	metrics := diffMetrics(pkg.Result{AverageRevisionReadySum: 10, P95: 20, P99: 4, OverallTotal: 100},
+		pkg.Result{AverageRevisionReadySum: 12, P95: 15, P99: 4.2, OverallTotal: 1000}, 10)
+
+	verdicts := map[string]pkg.MetricDiff{}
+	for _, m := range metrics {
+		verdicts[m.Metric] = m
+	}
+	_, found := verdicts["Total"]
+	assert.Check(t, !found, "Total should not be compared")
+	assert.Equal(t, "AverageConfigurationDuration", metrics[0].Metric)
+	assert.Equal(t, compare.VerdictRegression, verdicts["AverageRevisionDuration"].Verdict)
+	assert.Equal(t, 2.0, verdicts["AverageRevisionDuration"].Delta)
+	assert.Equal(t, 20.0, verdicts["AverageRevisionDuration"].DeltaPercent)
+	assert.Equal(t, compare.VerdictImprovement, verdicts["Percentile95"].Verdict)
+	assert.Equal(t, compare.VerdictNoChange, verdicts["Percentile99"].Verdict)
+	assert.Equal(t, compare.VerdictNoChange, verdicts["AverageConfigurationDuration"].Verdict)
+}
@@ -0,0 +1,38 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"github.com/spf13/cobra"
+
+	"knative.dev/kperf/pkg"
+)
+
+// NewReportCmd represents the report command
+func NewReportCmd(p *pkg.PerfParams) *cobra.Command {
+	var reportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "Analyze measurement results",
+		Long: `Analyze the measurement results saved by kperf. For example:
+
+kperf report diff old.json new.json - to print the changes of the measure result of two runs
+kperf report sla --input run.json --targets p50=10s,p99=60s - to render an SLA document of a run`,
+	}
+	reportCmd.AddCommand(NewDiffCommand(p))
+	reportCmd.AddCommand(NewSLACommand(p))
+
+	reportCmd.InitDefaultHelpCmd()
+	return reportCmd
+}
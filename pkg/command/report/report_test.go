@@ -0,0 +1,34 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestNewReportCmd(t *testing.T) {
+	cmd := NewReportCmd(&pkg.PerfParams{})
+	assert.Check(t, cmd.HasSubCommands(), "cmd report should have subcommands")
+
+	_, _, err := cmd.Find([]string{"diff"})
+	assert.NilError(t, err, "report command should have diff subcommand")
+
+	_, _, err = cmd.Find([]string{"sla"})
+	assert.NilError(t, err, "report command should have sla subcommand")
+}
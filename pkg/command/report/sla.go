@@ -0,0 +1,293 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	reportwriter "knative.dev/kperf/pkg/report"
+)
+
+const (
+	SLAOutputFilename = "sla"
+)
+
+// slaPercentiles are the percentiles a target can be given for, p50 is the median of the phases
+var slaPercentiles = []string{"p50", "p90", "p95", "p99", "max"}
+
+// slaPhases are the phases of the measure result in the order of the measurement CSV file, the overall ready one first
+var slaPhases = []string{measure.PhaseOverallReady, "configuration_ready", "revision_ready", "deployment_created", "pod_scheduled",
+	"containers_ready", "queue-proxy_started", "user-container_started", "route_ready", "kpa_active", "sks_ready",
+	"sks_activator_endpoints_populated", "sks_endpoints_populated", "ingress_ready", "ingress_config_ready",
+	"ingress_lb_ready", "certificate_ready"}
+
+// NewSLACommand implements 'kperf report sla' command
+func NewSLACommand(p *pkg.PerfParams) *cobra.Command {
+	slaArgs := pkg.SLAArgs{}
+	slaCommand := &cobra.Command{
+		Use:   "sla",
+		Short: "Render an SLA document from a measure result",
+		Long: `Render a short SLA document in Markdown from a measure result JSON file saved by 'kperf service measure'
+
+Every target is checked against the percentiles of the durations of the overall ready phase and of every other phase
+of the result, and reported as met or missed with the margin to the target, so that the document can be pasted into
+platform review docs. A target like p95=30s applies to all phases, one like revision_ready.p95=5s only to the
+revision_ready phase and overrides the general one. The percentiles p50, p90, p95 and p99 and the max can be targeted.
+
+For example:
+# To render the SLA of the median and the P99 of the service readiness
+kperf report sla --input /tmp/20210117104747_ksvc_creation_time.json --targets p50=10s,p99=60s
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if slaArgs.Input == "" {
+				return fmt.Errorf("'report sla' requires --input")
+			}
+			if len(slaArgs.Targets) == 0 {
+				return fmt.Errorf("'report sla' requires --targets")
+			}
+			_, err := parseSLATargets(slaArgs.Targets)
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RenderSLA(p, slaArgs)
+		},
+	}
+
+	slaCommand.Flags().StringVarP(&slaArgs.Input, "input", "i", "", "Measure result JSON file saved by 'kperf service measure'")
+	slaCommand.Flags().StringToStringVarP(&slaArgs.Targets, "targets", "", nil, "Comma separated targets like p50=10s,p99=60s, optionally for a single phase like revision_ready.p95=5s")
+	slaCommand.Flags().StringVarP(&slaArgs.Title, "title", "", "Knative Service readiness SLA", "Title of the SLA document")
+	slaCommand.Flags().StringVarP(&slaArgs.Output, "output", "o", ".", "SLA document location")
+	reportwriter.AddFlag(slaCommand.Flags(), &slaArgs.OutputFormats)
+	return slaCommand
+}
+
+// slaTarget is a target of a percentile, for all phases if phase is empty
+type slaTarget struct {
+	phase      string
+	percentile string
+	target     time.Duration
+}
+
+// parseSLATargets parses the targets given as [phase.]percentile=duration
+func parseSLATargets(targets map[string]string) ([]slaTarget, error) {
+	parsed := make([]slaTarget, 0, len(targets))
+	for key, value := range targets {
+		t := slaTarget{percentile: key}
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			t.phase, t.percentile = key[:i], key[i+1:]
+			if !contains(slaPhases, t.phase) {
+				return nil, fmt.Errorf("unknown phase %q in --targets, expected one of %s", t.phase, strings.Join(slaPhases, ","))
+			}
+		}
+		if !contains(slaPercentiles, t.percentile) {
+			return nil, fmt.Errorf("unknown percentile %q in --targets, expected one of %s", t.percentile, strings.Join(slaPercentiles, ","))
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q for %s in --targets: %s", value, key, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("target of %s in --targets must be positive, given %s", key, value)
+		}
+		t.target = d
+		parsed = append(parsed, t)
+	}
+	return parsed, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderSLA checks the targets against the measure result and writes the SLA document to stdout and the output location
+func RenderSLA(params *pkg.PerfParams, inputs pkg.SLAArgs) error {
+	targets, err := parseSLATargets(inputs.Targets)
+	if err != nil {
+		return err
+	}
+	measured, err := readMeasureResult(inputs.Input)
+	if err != nil {
+		return err
+	}
+
+	result := pkg.SLAResult{
+		Title:       inputs.Title,
+		Input:       inputs.Input,
+		RunID:       measured.RunID,
+		KnativeInfo: measured.KnativeInfo,
+		Service:     measured.Service,
+		Objectives:  slaObjectives(measured.Result, targets),
+	}
+	for _, o := range result.Objectives {
+		if o.Met {
+			result.Met++
+		} else {
+			result.Missed++
+		}
+	}
+	if len(result.Objectives) == 0 {
+		return fmt.Errorf("no phase of measure result %s has the durations of the targets", inputs.Input)
+	}
+
+	var document strings.Builder
+	writeSLA(&document, result)
+	fmt.Print(document.String())
+
+	current := time.Now()
+	outputLocation, err := utils.CheckOutputLocation(inputs.Output)
+	if err != nil {
+		fmt.Printf("failed to check SLA output location: %s\n", err)
+	}
+	mdPath := filepath.Join(outputLocation, utils.OutputFileName(params.Output, current.Format(utils.DateFormatString), SLAOutputFilename+".md"))
+	if err := utils.WriteFile(mdPath, []byte(document.String()), params.Output.Overwrite); err != nil {
+		return err
+	}
+	fmt.Printf("SLA document saved in Markdown file %s\n", mdPath)
+
+	rows := [][]string{{"phase", "percentile", "target", "observed", "margin", "margin_percent", "met"}}
+	for _, o := range result.Objectives {
+		rows = append(rows, []string{o.Phase, o.Percentile,
+			fmt.Sprintf("%f", o.Target),
+			fmt.Sprintf("%f", o.Observed),
+			fmt.Sprintf("%f", o.Margin),
+			fmt.Sprintf("%f", o.MarginPercent),
+			strconv.FormatBool(o.Met),
+		})
+	}
+	return reportwriter.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, reportwriter.Report{
+		Name:    SLAOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// slaPhaseValues returns the durations of the percentiles of every phase of the result, the phases without
+// statistics are skipped
+func slaPhaseValues(result pkg.Result) map[string]map[string]float64 {
+	values := map[string]map[string]float64{}
+	if result.OverallMax > 0 {
+		values[measure.PhaseOverallReady] = map[string]float64{"p50": result.P50, "p90": result.P90, "p95": result.P95,
+			"p99": result.P99, "max": result.OverallMax}
+	}
+	for phase, s := range result.Phases {
+		values[phase] = map[string]float64{"p50": s.Median, "p90": s.P90, "p95": s.P95, "p99": s.P99, "max": s.Max}
+	}
+	return values
+}
+
+// slaObjectives checks the targets against every phase in the order of slaPhases, the phases unknown to kperf
+// sorted by name after them. A target of a single phase overrides the general target of the same percentile.
+func slaObjectives(result pkg.Result, targets []slaTarget) []pkg.SLAObjective {
+	values := slaPhaseValues(result)
+	phases := []string{}
+	for _, phase := range slaPhases {
+		if _, ok := values[phase]; ok {
+			phases = append(phases, phase)
+		}
+	}
+	others := []string{}
+	for phase := range values {
+		if !contains(slaPhases, phase) {
+			others = append(others, phase)
+		}
+	}
+	sort.Strings(others)
+	phases = append(phases, others...)
+
+	objectives := []pkg.SLAObjective{}
+	for _, phase := range phases {
+		for _, percentile := range slaPercentiles {
+			target := time.Duration(0)
+			for _, t := range targets {
+				if t.percentile != percentile || (t.phase != "" && t.phase != phase) {
+					continue
+				}
+				if t.phase == phase || target == 0 {
+					target = t.target
+				}
+			}
+			if target == 0 {
+				continue
+			}
+			observed := values[phase][percentile]
+			margin := target.Seconds() - observed
+			objectives = append(objectives, pkg.SLAObjective{
+				Phase:         phase,
+				Percentile:    percentile,
+				Target:        target.Seconds(),
+				Observed:      observed,
+				Margin:        margin,
+				MarginPercent: margin / target.Seconds() * 100,
+				Met:           observed <= target.Seconds(),
+			})
+		}
+	}
+	return objectives
+}
+
+// writeSLA writes the SLA document in Markdown, the verdict first and a table of the objectives after it
+func writeSLA(out io.Writer, result pkg.SLAResult) {
+	fmt.Fprintf(out, "# %s\n\n", result.Title)
+	if result.Missed == 0 {
+		fmt.Fprintf(out, "**All %d objectives met.**\n\n", result.Met)
+	} else {
+		fmt.Fprintf(out, "**%d of %d objectives missed.**\n\n", result.Missed, result.Met+result.Missed)
+	}
+	fmt.Fprintf(out, "- Source: `%s`\n", result.Input)
+	if result.RunID != "" {
+		fmt.Fprintf(out, "- Run ID: `%s`\n", result.RunID)
+	}
+	if result.KnativeInfo.ServingVersion != "" {
+		fmt.Fprintf(out, "- Knative Serving: %s, ingress %s %s\n", result.KnativeInfo.ServingVersion,
+			result.KnativeInfo.IngressController, result.KnativeInfo.IngressVersion)
+	}
+	fmt.Fprintf(out, "- Services: %d ready, %d not ready, %d failed\n\n", result.Service.ReadyCount, result.Service.NotReadyCount,
+		result.Service.FailCount)
+
+	fmt.Fprintf(out, "| Phase | Objective | Target | Observed | Margin | Status |\n")
+	fmt.Fprintf(out, "|---|---|---|---|---|---|\n")
+	for _, o := range result.Objectives {
+		status := "met"
+		if !o.Met {
+			status = "**missed**"
+		}
+		fmt.Fprintf(out, "| %s | %s | %s | %s | %+.2fs (%+.0f%%) | %s |\n", o.Phase, o.Percentile, formatSLADuration(o.Target),
+			formatSLADuration(o.Observed), o.Margin, o.MarginPercent, status)
+	}
+	fmt.Fprintf(out, "\nThe margin is the target minus the observed duration, a negative margin misses the target.\n")
+}
+
+// formatSLADuration formats seconds like 1.5s or 120ms
+func formatSLADuration(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond).String()
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestParseSLATargets(t *testing.T) {
+	targets, err := parseSLATargets(map[string]string{"p99": "60s", "revision_ready.p95": "5s"})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(targets))
+
+	for _, c := range []struct {
+		targets map[string]string
+		err     string
+	}{
+		{map[string]string{"p42": "1s"}, "unknown percentile \"p42\""},
+		{map[string]string{"unknown_phase.p95": "1s"}, "unknown phase \"unknown_phase\""},
+		{map[string]string{"p95": "fast"}, "invalid duration \"fast\" for p95"},
+		{map[string]string{"p95": "0s"}, "target of p95 in --targets must be positive"},
+	} {
+		_, err := parseSLATargets(c.targets)
+		assert.ErrorContains(t, err, c.err)
+	}
+}
+
+func TestSLAObjectives(t *testing.T) {
+	result := pkg.Result{P50: 4, P95: 20, P99: 70, OverallMax: 90, Phases: map[string]pkg.PhaseStatistics{
+		"revision_ready": {Median: 2, P95: 6, P99: 8, Max: 9},
+		"pod_scheduled":  {Median: 0.5, P95: 1, P99: 1.5, Max: 2},
+	}}
+	targets := []slaTarget{
+		{percentile: "p50", target: 10 * time.Second},
+		{percentile: "p99", target: 60 * time.Second},
+		{phase: "revision_ready", percentile: "p99", target: 5 * time.Second},
+	}
+	objectives := slaObjectives(result, targets)
+	missed := -10.0
+	assert.DeepEqual(t, []pkg.SLAObjective{
+		{Phase: "overall_ready", Percentile: "p50", Target: 10, Observed: 4, Margin: 6, MarginPercent: 60, Met: true},
+		{Phase: "overall_ready", Percentile: "p99", Target: 60, Observed: 70, Margin: -10, MarginPercent: missed / 60 * 100, Met: false},
+		{Phase: "revision_ready", Percentile: "p50", Target: 10, Observed: 2, Margin: 8, MarginPercent: 80, Met: true},
+		{Phase: "revision_ready", Percentile: "p99", Target: 5, Observed: 8, Margin: -3, MarginPercent: -60, Met: false},
+		{Phase: "pod_scheduled", Percentile: "p50", Target: 10, Observed: 0.5, Margin: 9.5, MarginPercent: 95, Met: true},
+		{Phase: "pod_scheduled", Percentile: "p99", Target: 60, Observed: 1.5, Margin: 58.5, MarginPercent: 97.5, Met: true},
+	}, objectives)
+
+	out := &bytes.Buffer{}
+	writeSLA(out, pkg.SLAResult{Title: "Readiness SLA", Input: "run.json", Met: 4, Missed: 2, Objectives: objectives})
+	assert.Assert(t, strings.HasPrefix(out.String(), "# Readiness SLA\n\n**2 of 6 objectives missed.**"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "| overall_ready | p99 | 1m0s | 1m10s | -10.00s (-17%) | **missed** |"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "| pod_scheduled | p50 | 10s | 500ms | +9.50s (+95%) | met |"), out.String())
+}
+
+func TestNewSLACommand(t *testing.T) {
+	dir := t.TempDir()
+	input := writeMeasureResult(t, dir, "run.json", pkg.Result{P50: 4, P99: 30, OverallMax: 40})
+
+	t.Run("incompleted or wrong args for report sla", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewSLACommand(&pkg.PerfParams{}), "--targets", "p50=10s")
+		assert.ErrorContains(t, err, "'report sla' requires --input")
+
+		_, err = testutil.ExecuteCommand(NewSLACommand(&pkg.PerfParams{}), "--input", input)
+		assert.ErrorContains(t, err, "'report sla' requires --targets")
+
+		_, err = testutil.ExecuteCommand(NewSLACommand(&pkg.PerfParams{}), "--input", input, "--targets", "p42=10s")
+		assert.ErrorContains(t, err, "unknown percentile")
+
+		_, err = testutil.ExecuteCommand(NewSLACommand(&pkg.PerfParams{}), "--input", filepath.Join(dir, "missing.json"), "--targets", "p50=10s")
+		assert.ErrorContains(t, err, "failed to read measure result")
+	})
+
+	t.Run("render sla document", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewSLACommand(&pkg.PerfParams{}), "--input", input, "--targets", "p50=10s,p99=60s", "--output", dir)
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(dir, "*_"+SLAOutputFilename+".md"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(data), "**All 2 objectives met.**"), string(data))
+	})
+
+	t.Run("render sla document with output format", func(t *testing.T) {
+		output := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewSLACommand(&pkg.PerfParams{}), "--input", input, "--targets", "p50=10s", "--output", output, "--output-format", "csv")
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(output, "*_"+SLAOutputFilename+".*"))
+		assert.NilError(t, err)
+		assert.Equal(t, 2, len(matches))
+		for _, m := range matches {
+			assert.Assert(t, strings.HasSuffix(m, ".md") || strings.HasSuffix(m, ".csv"), m)
+		}
+	})
+
+	t.Run("no durations of the targets", func(t *testing.T) {
+		empty := writeMeasureResult(t, dir, "empty.json", pkg.Result{})
+		_, err := testutil.ExecuteCommand(NewSLACommand(&pkg.PerfParams{}), "--input", empty, "--targets", "p50=10s", "--output", dir)
+		assert.ErrorContains(t, err, "no phase of measure result")
+	})
+}
@@ -0,0 +1,398 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	GCOutputFilename = "revision_gc"
+
+	// GCConfigMap is the ConfigMap of Knative Serving holding the settings of the garbage collection of revisions
+	GCConfigMap = "config-gc"
+
+	minNonActiveRevisionsKey     = "min-non-active-revisions"
+	maxNonActiveRevisionsKey     = "max-non-active-revisions"
+	retainSinceCreateTimeKey     = "retain-since-create-time"
+	retainSinceLastActiveTimeKey = "retain-since-last-active-time"
+	disabled                     = "disabled"
+)
+
+// NewRevisionGCMeasureCommand implements 'kperf revision gc-measure' command
+func NewRevisionGCMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	gcArgs := pkg.RevisionGCArgs{}
+	gcCommand := &cobra.Command{
+		Use:   "gc-measure",
+		Short: "Measure the garbage collection of old revisions",
+		Long: `Create many revisions per Knative Service and measure how fast the serving controller garbage-collects the old ones
+
+The garbage collection is disabled in the config-gc ConfigMap while --number Knative Services with --revisions
+revisions each are created, all of the traffic goes to the latest revision. Then max-non-active-revisions is set to
+every value of --max-revisions from the largest to the smallest, with min-non-active-revisions set to 0 and the
+time based retention disabled, so that only the number of revisions decides which ones are collected. For every
+setting the lag from the change of the ConfigMap until every service is down to the setting is reported next to the
+number of revisions the services had and the number collected. The settings of the cluster are restored afterwards,
+and the Knative Services are deleted unless --keep is given.
+
+For example:
+# To measure the garbage collection of 5 Knative Services with 30 revisions each in namespace ktest
+kperf revision gc-measure --namespace ktest --number 5 --revisions 30 --max-revisions 20,10,0
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if gcArgs.Namespace == "" {
+				return fmt.Errorf("'revision gc-measure' requires --namespace")
+			}
+			if gcArgs.Number < 1 || gcArgs.Concurrency < 1 {
+				return fmt.Errorf("--number and --concurrency must be at least 1")
+			}
+			if gcArgs.Revisions < 2 {
+				return fmt.Errorf("--revisions must be at least 2, given %d", gcArgs.Revisions)
+			}
+			if len(gcArgs.MaxRevisions) == 0 {
+				return fmt.Errorf("at least 1 --max-revisions setting is required")
+			}
+			for _, max := range gcArgs.MaxRevisions {
+				if max < 0 {
+					return fmt.Errorf("--max-revisions must not be negative, given %d", max)
+				}
+				if max >= gcArgs.Revisions-1 {
+					return fmt.Errorf("--max-revisions %d collects none of the %d non-active revisions of a service, lower it or raise --revisions", max, gcArgs.Revisions-1)
+				}
+			}
+			return pkg.ValidateRunID(gcArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureRevisionGC(p, gcArgs)
+		},
+	}
+
+	gcCommand.Flags().StringVarP(&gcArgs.Namespace, "namespace", "", "", "Namespace the Knative Services are created in")
+	gcCommand.Flags().StringVarP(&gcArgs.SvcPrefix, "svc-prefix", "", "kperf-gc", "Knative Service name prefix. The Knative Services will be svcPrefix-0, svcPrefix-1 and etc.")
+	gcCommand.Flags().IntVarP(&gcArgs.Number, "number", "n", 5, "Number of Knative Services created")
+	gcCommand.Flags().IntVarP(&gcArgs.Concurrency, "concurrency", "c", 5, "Number of Knative Services to create revisions for at a time")
+	gcCommand.Flags().IntVarP(&gcArgs.Revisions, "revisions", "", 20, "Number of revisions of each Knative Service, named <service>-rev-<n>")
+	gcCommand.Flags().IntSliceVarP(&gcArgs.MaxRevisions, "max-revisions", "", []int{10, 5, 1}, "Comma separated max-non-active-revisions settings measured one after the other from the largest to the smallest")
+	gcCommand.Flags().DurationVarP(&gcArgs.Timeout, "timeout", "", 5*time.Minute, "Duration to wait for the revisions of each Knative Service to be created, and for the garbage collection of each setting")
+	gcCommand.Flags().DurationVarP(&gcArgs.Interval, "interval", "", time.Second, "Interval the revisions are counted in while they are garbage-collected")
+	gcCommand.Flags().StringVarP(&gcArgs.ServingNamespace, "serving-namespace", "", measure.DefaultControlPlaneNamespace, "Namespace of Knative Serving holding the config-gc ConfigMap")
+	gcCommand.Flags().BoolVarP(&gcArgs.Keep, "keep", "", false, "Keep the Knative Services after the measurement, 'kperf service clean --run-id' removes them")
+	gcCommand.Flags().StringVarP(&gcArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(gcCommand.Flags(), &gcArgs.OutputFormats)
+	gcCommand.Flags().StringVarP(&gcArgs.RunID, "run-id", "", "", "ID of the run the Knative Services are labeled with, the results are written to the subdirectory of the output location named by it. A new ID is generated by default")
+	return gcCommand
+}
+
+// MeasureRevisionGC creates the revisions of the services, measures their garbage collection for every
+// max-non-active-revisions setting and writes the result
+func MeasureRevisionGC(params *pkg.PerfParams, inputs pkg.RevisionGCArgs) error {
+	ctx := context.Background()
+	if inputs.RunID == "" {
+		inputs.RunID = pkg.NewRunID(time.Now())
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	configMaps := params.ClientSet.CoreV1().ConfigMaps(inputs.ServingNamespace)
+	configMap, err := configMaps.Get(ctx, GCConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %s", inputs.ServingNamespace, GCConfigMap, err)
+	}
+	baseline := copySettings(configMap.Data)
+	defer func() {
+		if err := setGCSettings(ctx, configMaps, baseline); err != nil {
+			fmt.Printf("failed to restore the garbage collection settings in ConfigMap %s/%s: %s\n", inputs.ServingNamespace, GCConfigMap, err)
+			return
+		}
+		fmt.Printf("Restored the garbage collection settings in ConfigMap %s/%s\n", inputs.ServingNamespace, GCConfigMap)
+	}()
+	if err := setGCSettings(ctx, configMaps, gcSettings(baseline, disabled)); err != nil {
+		return fmt.Errorf("failed to disable the garbage collection of revisions: %s", err)
+	}
+	fmt.Printf("Run ID %s, clean up the run with 'kperf service clean --run-id %s'\n", inputs.RunID, inputs.RunID)
+
+	var m sync.Mutex
+	created := []string{}
+	pool.ForEach(ctx, inputs.Concurrency, inputs.Number, func(ctx context.Context, i int) {
+		svc := gcService(inputs, i)
+		fmt.Printf("Creating Knative Service %s with %d revisions in namespace %s\n", svc.Name, inputs.Revisions, svc.Namespace)
+		if _, err := ksvcClient.Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{}); err != nil {
+			fmt.Printf("failed to create Knative Service %s in namespace %s: %s\n", svc.Name, svc.Namespace, err)
+			return
+		}
+		m.Lock()
+		created = append(created, svc.Name)
+		m.Unlock()
+		if err := service.CreateRevisions(ctx, ksvcClient, svc.Namespace, svc.Name, inputs.Revisions, inputs.Timeout); err != nil {
+			fmt.Printf("failed to create the revisions of Knative Service %s in namespace %s: %s\n", svc.Name, svc.Namespace, err)
+		}
+	})
+	if !inputs.Keep {
+		defer func() {
+			for _, name := range created {
+				if err := ksvcClient.Services(inputs.Namespace).Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil {
+					fmt.Printf("failed to delete Knative Service %s in namespace %s: %s\n", name, inputs.Namespace, err)
+				}
+			}
+		}()
+	}
+	if len(created) == 0 {
+		return fmt.Errorf("no Knative Service was created in namespace %s", inputs.Namespace)
+	}
+	sort.Strings(created)
+
+	settings := append([]int(nil), inputs.MaxRevisions...)
+	sort.Sort(sort.Reverse(sort.IntSlice(settings)))
+	result := pkg.RevisionGCResult{
+		CreatedAt:           time.Now().UTC().Format(time.RFC3339),
+		KnativeInfo:         measure.GetKnativeInfo(ctx, params, measure.DefaultLogger),
+		Services:            len(created),
+		RevisionsPerService: inputs.Revisions,
+	}
+	timedOut := []string{}
+	for _, max := range settings {
+		fmt.Printf("-------- max-non-active-revisions %d --------\n", max)
+		setting, err := measureSetting(ctx, ksvcClient, configMaps, baseline, inputs, created, max)
+		if err != nil {
+			return err
+		}
+		if setting.TimedOut {
+			timedOut = append(timedOut, strconv.Itoa(max))
+		}
+		result.Settings = append(result.Settings, setting)
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	writeSettings(os.Stdout, result, render.NewOptions(os.Stdout, false, false))
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	if err := report.Write(os.Stdout, outputLocation, time.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    GCOutputFilename,
+		Rows:    settingRows(result),
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	}); err != nil {
+		return err
+	}
+	if len(timedOut) > 0 {
+		return fmt.Errorf("the revisions were not garbage-collected within %s for max-non-active-revisions %s", inputs.Timeout, strings.Join(timedOut, ","))
+	}
+	return nil
+}
+
+// gcService returns the Knative Service whose revisions are garbage-collected, its first revision is named like the
+// ones service.CreateRevisions creates
+func gcService(inputs pkg.RevisionGCArgs, index int) *servingv1.Service {
+	name := fmt.Sprintf("%s-%d", inputs.SvcPrefix, index)
+	svc := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: inputs.Namespace,
+			Labels:    pkg.GeneratedLabels(inputs.RunID, 0, time.Now()),
+		},
+	}
+	svc.Spec.Template.Name = service.RevisionName(name, 1)
+	svc.Spec.Template.Spec.Containers = []corev1.Container{{
+		Image: service.ServiceImage,
+		Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+	}}
+	return svc
+}
+
+// measureSetting sets max-non-active-revisions and counts the revisions of the services until each of them is down
+// to the setting, plus its latest revision which receives the traffic, or the timeout is over
+func measureSetting(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, configMaps corev1client.ConfigMapInterface,
+	baseline map[string]string, inputs pkg.RevisionGCArgs, services []string, max int) (pkg.RevisionGCSetting, error) {
+	setting := pkg.RevisionGCSetting{MaxNonActiveRevisions: max}
+	counts := make(map[string]int, len(services))
+	for _, name := range services {
+		count, err := countRevisions(ctx, ksvcClient, inputs.Namespace, name, inputs.Revisions)
+		if err != nil {
+			return setting, err
+		}
+		counts[name] = count
+		setting.Revisions += count
+	}
+	expected := max + 1
+	if err := setGCSettings(ctx, configMaps, gcSettings(baseline, strconv.Itoa(max))); err != nil {
+		return setting, fmt.Errorf("failed to set max-non-active-revisions %d: %s", max, err)
+	}
+	start := time.Now()
+	lags := map[string]float64{}
+	err := wait.PollImmediate(inputs.Interval, inputs.Timeout, func() (bool, error) {
+		for _, name := range services {
+			if _, done := lags[name]; done {
+				continue
+			}
+			count, err := countRevisions(ctx, ksvcClient, inputs.Namespace, name, inputs.Revisions)
+			if err != nil {
+				return false, err
+			}
+			counts[name] = count
+			if count <= expected {
+				lags[name] = time.Since(start).Seconds()
+			}
+		}
+		return len(lags) == len(services), nil
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		return setting, fmt.Errorf("failed to count the revisions: %s", err)
+	}
+	setting.TimedOut = err != nil
+	for _, name := range services {
+		setting.Remaining += counts[name]
+	}
+	setting.Collected = setting.Revisions - setting.Remaining
+
+	durations := make([]float64, 0, len(lags))
+	for _, lag := range lags {
+		durations = append(durations, lag)
+	}
+	if len(durations) > 0 {
+		setting.ServiceLagP50, _ = stats.Percentile(durations, 50)
+		setting.ServiceLagMax, _ = stats.Max(durations)
+	}
+	setting.Lag = setting.ServiceLagMax
+	if setting.TimedOut {
+		setting.Lag = inputs.Timeout.Seconds()
+	}
+	fmt.Printf("Collected %d of %d revisions after %fs, %d remaining\n", setting.Collected, setting.Revisions, setting.Lag, setting.Remaining)
+	return setting, nil
+}
+
+// countRevisions returns the number of the n generated revisions of the service which were not garbage-collected yet
+func countRevisions(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, name string, n int) (int, error) {
+	count := 0
+	for i := 1; i <= n; i++ {
+		_, err := ksvcClient.Revisions(namespace).Get(ctx, service.RevisionName(name, i), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// gcSettings returns the settings of the cluster with max-non-active-revisions set to max. Only the number of
+// revisions decides which ones are collected, min-non-active-revisions is 0 and the time based retention disabled.
+func gcSettings(baseline map[string]string, max string) map[string]string {
+	settings := copySettings(baseline)
+	settings[minNonActiveRevisionsKey] = "0"
+	settings[maxNonActiveRevisionsKey] = max
+	settings[retainSinceCreateTimeKey] = disabled
+	settings[retainSinceLastActiveTimeKey] = disabled
+	return settings
+}
+
+func copySettings(settings map[string]string) map[string]string {
+	copied := make(map[string]string, len(settings))
+	for name, value := range settings {
+		copied[name] = value
+	}
+	return copied
+}
+
+// setGCSettings replaces the settings of the latest config-gc ConfigMap
+func setGCSettings(ctx context.Context, configMaps corev1client.ConfigMapInterface, settings map[string]string) error {
+	configMap, err := configMaps.Get(ctx, GCConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	configMap.Data = copySettings(settings)
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// settingRows returns the rows of the CSV file with a row for every max-non-active-revisions setting
+func settingRows(result pkg.RevisionGCResult) [][]string {
+	rows := [][]string{{"max_non_active_revisions", "revisions", "collected", "remaining", "lag", "service_lag_p50", "service_lag_max", "timed_out"}}
+	for _, s := range result.Settings {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", s.MaxNonActiveRevisions),
+			fmt.Sprintf("%d", s.Revisions),
+			fmt.Sprintf("%d", s.Collected),
+			fmt.Sprintf("%d", s.Remaining),
+			fmt.Sprintf("%f", s.Lag),
+			fmt.Sprintf("%f", s.ServiceLagP50),
+			fmt.Sprintf("%f", s.ServiceLagMax),
+			fmt.Sprintf("%t", s.TimedOut),
+		})
+	}
+	return rows
+}
+
+// writeSettings writes the garbage collection of every setting, the bars compare the lags and settings which timed
+// out are highlighted
+func writeSettings(w io.Writer, result pkg.RevisionGCResult, options render.Options) {
+	fmt.Fprintf(w, "%d Knative Service(s) with %d revisions each\n", result.Services, result.RevisionsPerService)
+	var longest float64
+	for _, s := range result.Settings {
+		if s.Lag > longest {
+			longest = s.Lag
+		}
+	}
+	rows := make([]*render.Row, 0, len(result.Settings))
+	for _, s := range result.Settings {
+		row := &render.Row{Name: fmt.Sprintf("%d", s.MaxNonActiveRevisions), Values: []string{
+			fmt.Sprintf("%d", s.Revisions),
+			fmt.Sprintf("%d", s.Collected),
+			fmt.Sprintf("%d", s.Remaining),
+			fmt.Sprintf("%.3fs", s.Lag),
+			fmt.Sprintf("%.3fs", s.ServiceLagP50),
+			fmt.Sprintf("%.3fs", s.ServiceLagMax),
+		}}
+		if longest > 0 {
+			row.Bar = s.Lag / longest
+		}
+		if s.TimedOut {
+			row.Style = render.Red
+		}
+		rows = append(rows, row)
+	}
+	render.Table(w, []string{"MAX NON-ACTIVE", "REVISIONS", "COLLECTED", "REMAINING", "LAG", "SERVICE LAG P50", "SERVICE LAG MAX"}, rows, options)
+}
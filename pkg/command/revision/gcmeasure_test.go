@@ -0,0 +1,179 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestGCSettings(t *testing.T) {
+	baseline := map[string]string{minNonActiveRevisionsKey: "20", "_example": "docs"}
+	assert.DeepEqual(t, map[string]string{
+		"_example":                   "docs",
+		minNonActiveRevisionsKey:     "0",
+		maxNonActiveRevisionsKey:     "5",
+		retainSinceCreateTimeKey:     disabled,
+		retainSinceLastActiveTimeKey: disabled,
+	}, gcSettings(baseline, "5"))
+	// the baseline is not changed
+	assert.Equal(t, "20", baseline[minNonActiveRevisionsKey])
+}
+
+func TestWriteSettings(t *testing.T) {
+	result := pkg.RevisionGCResult{Services: 2, RevisionsPerService: 10, Settings: []pkg.RevisionGCSetting{
+		{MaxNonActiveRevisions: 5, Revisions: 20, Collected: 8, Remaining: 12, Lag: 2, ServiceLagP50: 1.5, ServiceLagMax: 2},
+		{MaxNonActiveRevisions: 1, Revisions: 12, Collected: 6, Remaining: 6, Lag: 60, TimedOut: true},
+	}}
+	out := &bytes.Buffer{}
+	writeSettings(out, result, render.Options{})
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("2 Knative Service(s) with 10 revisions each")), out.String())
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("60.000s")), out.String())
+
+	rows := settingRows(result)
+	assert.Equal(t, 3, len(rows))
+	assert.DeepEqual(t, []string{"5", "20", "8", "12", "2.000000", "1.500000", "2.000000", "false"}, rows[1])
+	assert.Equal(t, "true", rows[2][7])
+}
+
+func TestMeasureRevisionGC(t *testing.T) {
+	revisions := servingv1.SchemeGroupVersion.WithResource("revisions")
+	client := k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: GCConfigMap, Namespace: "knative-serving"},
+		Data:       map[string]string{minNonActiveRevisionsKey: "20"},
+	})
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	// the revision of the current template is created as soon as the service is read
+	fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		get := action.(clienttesting.GetAction)
+		obj, err := client.Tracker().Get(get.GetResource(), get.GetNamespace(), get.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		svc := obj.(*servingv1.Service).DeepCopy()
+		svc.Status.LatestCreatedRevisionName = svc.Spec.Template.Name
+		revision := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: svc.Spec.Template.Name, Namespace: svc.Namespace}}
+		if err := client.Tracker().Create(revisions, revision, svc.Namespace); err != nil && !apierrors.IsAlreadyExists(err) {
+			return true, nil, err
+		}
+		return true, svc, nil
+	})
+	// the oldest revisions of the services are collected as soon as max-non-active-revisions is set
+	collect := true
+	client.PrependReactor("update", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		cm := action.(clienttesting.UpdateAction).GetObject().(*corev1.ConfigMap)
+		max, err := strconv.Atoi(cm.Data[maxNonActiveRevisionsKey])
+		if cm.Name != GCConfigMap || err != nil || !collect {
+			return false, nil, nil
+		}
+		for s := 0; s < 2; s++ {
+			for i := 1; i < 4-max; i++ {
+				client.Tracker().Delete(revisions, "ns-1", service.RevisionName(fmt.Sprintf("kperf-gc-%d", s), i))
+			}
+		}
+		return false, nil, nil
+	})
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+	}
+
+	t.Run("collected", func(t *testing.T) {
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewRevisionGCMeasureCommand(p), "--namespace", "ns-1", "--number", "2", "--revisions", "4",
+			"--max-revisions", "1,2", "--interval", "10ms", "--timeout", "5s", "--run-id", "gc-1", "--output", outputDir)
+		assert.NilError(t, err)
+
+		// the settings of the cluster are restored and the services deleted
+		cm, err := client.CoreV1().ConfigMaps("knative-serving").Get(context.Background(), GCConfigMap, metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]string{minNonActiveRevisionsKey: "20"}, cm.Data)
+		for _, name := range []string{"kperf-gc-0", "kperf-gc-1"} {
+			_, err := client.Tracker().Get(servingv1.SchemeGroupVersion.WithResource("services"), "ns-1", name)
+			assert.Check(t, apierrors.IsNotFound(err), name)
+		}
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "gc-1", "*_"+GCOutputFilename+".json"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		result := pkg.RevisionGCResult{}
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, 2, result.Services)
+		assert.Equal(t, 4, result.RevisionsPerService)
+		assert.Equal(t, 2, len(result.Settings))
+		// the settings are measured from the largest to the smallest
+		assert.Equal(t, 2, result.Settings[0].MaxNonActiveRevisions)
+		assert.Equal(t, 8, result.Settings[0].Revisions)
+		assert.Equal(t, 2, result.Settings[0].Collected)
+		assert.Equal(t, 1, result.Settings[1].MaxNonActiveRevisions)
+		assert.Equal(t, 6, result.Settings[1].Revisions)
+		assert.Equal(t, 2, result.Settings[1].Collected)
+		assert.Equal(t, 4, result.Settings[1].Remaining)
+		assert.Check(t, !result.Settings[1].TimedOut)
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		collect = false
+		defer func() { collect = true }()
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewRevisionGCMeasureCommand(p), "--namespace", "ns-1", "--number", "1", "--revisions", "3",
+			"--max-revisions", "0", "--interval", "10ms", "--timeout", "100ms", "--run-id", "gc-2", "--output", outputDir)
+		assert.ErrorContains(t, err, "not garbage-collected within 100ms for max-non-active-revisions 0")
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "gc-2", "*_"+GCOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		assert.Check(t, bytes.Contains(data, []byte("\n0,3,0,3,0.100000,0.000000,0.000000,true")), string(data))
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewRevisionGCMeasureCommand(p), "--number", "1")
+		assert.ErrorContains(t, err, "requires --namespace")
+		_, err = testutil.ExecuteCommand(NewRevisionGCMeasureCommand(p), "--namespace", "ns-1", "--revisions", "1")
+		assert.ErrorContains(t, err, "--revisions must be at least 2")
+		_, err = testutil.ExecuteCommand(NewRevisionGCMeasureCommand(p), "--namespace", "ns-1", "--revisions", "5", "--max-revisions", "4")
+		assert.ErrorContains(t, err, "collects none of the 4 non-active revisions")
+		_, err = testutil.ExecuteCommand(NewRevisionGCMeasureCommand(p), "--namespace", "ns-1", "--max-revisions", "-1")
+		assert.ErrorContains(t, err, "must not be negative")
+	})
+}
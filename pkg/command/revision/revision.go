@@ -0,0 +1,34 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewRevisionCmd(p *pkg.PerfParams) *cobra.Command {
+	var revisionCmd = &cobra.Command{
+		Use:   "revision",
+		Short: "Knative revision benchmark",
+		Long: `Knative Serving revision benchmark. For example:
+
+kperf revision gc-measure --namespace ns --number 5 --revisions 30 --max-revisions 20,10,0 - to measure how fast old revisions are garbage-collected`,
+	}
+	revisionCmd.AddCommand(NewRevisionGCMeasureCommand(p))
+
+	revisionCmd.InitDefaultHelpCmd()
+	return revisionCmd
+}
@@ -0,0 +1,353 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	ActivatorOutputFilename = "ksvc_activator_overhead"
+)
+
+// activatorPath is a request path of a Knative Service, selected by the target burst capacity of its revision. The
+// activator stays in the path with -1 and is removed from it as soon as pods are ready with 0.
+type activatorPath struct {
+	name          string
+	burstCapacity string
+	mode          netv1alpha1.ServerlessServiceOperationMode
+}
+
+var activatorPaths = []activatorPath{
+	{name: "activator", burstCapacity: "-1", mode: netv1alpha1.SKSOperationModeProxy},
+	{name: "direct", burstCapacity: "0", mode: netv1alpha1.SKSOperationModeServe},
+}
+
+func NewServiceActivatorOverheadCommand(p *pkg.PerfParams) *cobra.Command {
+	activatorArgs := pkg.ActivatorOverheadArgs{}
+	activatorCommand := &cobra.Command{
+		Use:   "activator-overhead",
+		Short: "Measure the latency overhead of the activator path of a Knative service",
+		Long: `Compare the latency and the throughput of the requests to a Knative service through the activator with the
+ones of the requests sent directly to its pods
+
+The service is switched to the activator path with a target burst capacity of -1, which keeps its ServerlessService
+in proxy mode as after a scale from zero, and to the direct path with a target burst capacity of 0, which puts it in
+serve mode. A min-scale of 1 keeps the pods warm on both paths, so that only the extra hop is measured. At each
+concurrency level the given number of connections send requests as fast as possible for the duration. The annotations
+of the service are restored afterwards.
+
+For example:
+# To measure the activator overhead of the Knative Service svc in namespace ns with 1, 10 and 50 connections
+kperf service activator-overhead --svc svc --namespace ns --concurrency 1,10,50 --duration 30s
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if activatorArgs.Svc == "" || activatorArgs.Namespace == "" {
+				return fmt.Errorf("'service activator-overhead' requires --svc and --namespace")
+			}
+			if len(activatorArgs.Concurrency) == 0 {
+				return fmt.Errorf("--concurrency requires at least one level")
+			}
+			for _, c := range activatorArgs.Concurrency {
+				if c < 1 {
+					return fmt.Errorf("--concurrency levels must be at least 1, given %d", c)
+				}
+			}
+			if activatorArgs.Duration <= 0 {
+				return fmt.Errorf("duration must be greater than 0")
+			}
+			return pkg.ValidateRunID(activatorArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureActivatorOverhead(p, activatorArgs)
+		},
+	}
+
+	activatorCommand.Flags().StringVarP(&activatorArgs.Namespace, "namespace", "", "", "Service namespace")
+	activatorCommand.Flags().StringVarP(&activatorArgs.Svc, "svc", "", "", "Service name")
+	activatorCommand.Flags().IntSliceVarP(&activatorArgs.Concurrency, "concurrency", "c", []int{1, 10, 50}, "Comma separated numbers of concurrent connections to measure both paths with")
+	activatorCommand.Flags().DurationVarP(&activatorArgs.Duration, "duration", "", 30*time.Second, "Duration of the load on each path at each concurrency level")
+	activatorCommand.Flags().DurationVarP(&activatorArgs.Settle, "settle", "", 3*time.Minute, "Duration to wait for the service to be ready and its ServerlessService to be in the mode of the path after it was switched")
+	activatorCommand.Flags().DurationVarP(&activatorArgs.RequestTimeout, "timeout", "", 30*time.Second, "Timeout of a single request")
+	activatorCommand.Flags().BoolVarP(&activatorArgs.ResolvableDomain, "resolvable", "", false, "If Service endpoint resolvable url")
+	activatorCommand.Flags().BoolVarP(&activatorArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	activatorCommand.Flags().StringVarP(&activatorArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(activatorCommand.Flags(), &activatorArgs.OutputFormats)
+	activatorCommand.Flags().StringVarP(&activatorArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	return activatorCommand
+}
+
+// MeasureActivatorOverhead measures the requests to a Knative Service through the activator and directly to its pods
+// at each concurrency level, and restores the annotations of the service afterwards
+func MeasureActivatorOverhead(params *pkg.PerfParams, inputs pkg.ActivatorOverheadArgs) error {
+	ctx := context.Background()
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	networkingClient, err := params.NewNetworkingClient()
+	if err != nil {
+		return err
+	}
+	svc, err := ksvcClient.Services(inputs.Namespace).Get(ctx, inputs.Svc, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get service %s/%s: %w", inputs.Namespace, inputs.Svc, err)
+	}
+	endpoint, err := ResolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
+	if err != nil {
+		return fmt.Errorf("failed to get the cluster endpoint: %w", err)
+	}
+	restore := activatorRestorePatch(svc)
+	defer func() {
+		fmt.Printf("Restoring the annotations of service %s/%s\n", inputs.Namespace, inputs.Svc)
+		if _, err := ksvcClient.Services(inputs.Namespace).Patch(ctx, inputs.Svc, types.MergePatchType, restore, metav1.PatchOptions{}); err != nil {
+			fmt.Printf("failed to restore the annotations of service %s/%s: %s\n", inputs.Namespace, inputs.Svc, err)
+		}
+	}()
+
+	client := http.Client{Timeout: inputs.RequestTimeout}
+	paths := map[string][]pkg.ActivatorPath{}
+	for _, path := range activatorPaths {
+		fmt.Printf("Switching service %s/%s to the %s path\n", inputs.Namespace, inputs.Svc, path.name)
+		if err := switchActivatorPath(ctx, ksvcClient, networkingClient, inputs.Namespace, inputs.Svc, path, inputs.Settle); err != nil {
+			return err
+		}
+		for _, concurrency := range inputs.Concurrency {
+			fmt.Printf("Sending requests on the %s path with %d connection(s) for %s\n", path.name, concurrency, inputs.Duration)
+			measurement := runActivatorLevel(ctx, client, endpoint, svc, concurrency, inputs.Duration)
+			if inputs.Verbose {
+				fmt.Printf("[Verbose] %s path, %d connection(s): %d requests, %d errors, %f req/s, P50 %fs, P95 %fs, P99 %fs\n", path.name,
+					concurrency, measurement.Requests, measurement.Errors, measurement.Throughput, measurement.P50, measurement.P95, measurement.P99)
+			}
+			paths[path.name] = append(paths[path.name], measurement)
+		}
+	}
+
+	result := pkg.ActivatorOverheadResult{
+		ServiceName:      inputs.Svc,
+		ServiceNamespace: inputs.Namespace,
+		Levels:           activatorLevels(inputs.Concurrency, paths["activator"], paths["direct"]),
+	}
+	result.KnativeInfo = measure.GetKnativeInfo(ctx, params, measure.DefaultLogger)
+	writeActivatorOverhead(os.Stdout, result)
+
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	return report.Write(os.Stdout, outputLocation, time.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    ActivatorOutputFilename,
+		Rows:    activatorRows(result),
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// activatorPatch returns the merge patch of the revision template annotations, a nil value removes the annotation
+func activatorPatch(annotations map[string]interface{}) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": annotations,
+				},
+			},
+		},
+	})
+}
+
+// activatorRestorePatch returns the patch which restores the annotations the measurement changes
+func activatorRestorePatch(svc *servingv1.Service) []byte {
+	annotations := map[string]interface{}{}
+	for _, key := range []string{autoscaling.TargetBurstCapacityKey, autoscaling.MinScaleAnnotationKey} {
+		if v, ok := svc.Spec.Template.Annotations[key]; ok {
+			annotations[key] = v
+		} else {
+			annotations[key] = nil
+		}
+	}
+	patch, _ := activatorPatch(annotations)
+	return patch
+}
+
+// switchActivatorPath sets the target burst capacity of the path and a min-scale of 1, and waits until the new
+// revision is ready and its ServerlessService is in the mode of the path
+func switchActivatorPath(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, networkingClient networkingv1alpha1.NetworkingV1alpha1Interface,
+	namespace, name string, path activatorPath, timeout time.Duration) error {
+	patch, err := activatorPatch(map[string]interface{}{
+		autoscaling.TargetBurstCapacityKey: path.burstCapacity,
+		autoscaling.MinScaleAnnotationKey:  "1",
+	})
+	if err != nil {
+		return err
+	}
+	updated, err := ksvcClient.Services(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to switch service %s/%s to the %s path: %w", namespace, name, path.name, err)
+	}
+	var mode netv1alpha1.ServerlessServiceOperationMode
+	err = wait.PollImmediate(updatePollInterval, timeout, func() (bool, error) {
+		current, err := ksvcClient.Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if current.Status.ObservedGeneration < updated.Generation || !current.IsReady() ||
+			current.Status.LatestReadyRevisionName != current.Status.LatestCreatedRevisionName {
+			return false, nil
+		}
+		// the ServerlessService of a revision is named like it
+		sks, err := networkingClient.ServerlessServices(namespace).Get(ctx, current.Status.LatestReadyRevisionName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		mode = sks.Spec.Mode
+		return mode == path.mode && sks.IsReady(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("service %s/%s is not on the %s path after %s, its ServerlessService is in mode %q", namespace, name, path.name, timeout, mode)
+	}
+	return nil
+}
+
+// runActivatorLevel sends requests as fast as possible on the given number of connections for the duration
+func runActivatorLevel(ctx context.Context, client http.Client, endpoint string, svc *servingv1.Service, concurrency int, duration time.Duration) pkg.ActivatorPath {
+	loadCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	var m sync.Mutex
+	var samples []loadSample
+	start := time.Now()
+	pool.ForEach(loadCtx, concurrency, concurrency, func(ctx context.Context, i int) {
+		for loadCtx.Err() == nil {
+			sample, _ := sendLoadRequest(loadCtx, client, http.MethodGet, endpoint, svc, "")
+			if loadCtx.Err() != nil {
+				// requests interrupted by the end of the load are not counted
+				return
+			}
+			m.Lock()
+			samples = append(samples, sample)
+			m.Unlock()
+		}
+	})
+	return summarizeActivatorPath(samples, time.Since(start))
+}
+
+// summarizeActivatorPath computes the throughput of the successful requests and their latency percentiles
+func summarizeActivatorPath(samples []loadSample, elapsed time.Duration) pkg.ActivatorPath {
+	path := pkg.ActivatorPath{}
+	latencies := make([]float64, 0, len(samples))
+	for _, s := range samples {
+		path.Requests++
+		if s.failed {
+			path.Errors++
+			continue
+		}
+		latencies = append(latencies, s.latency)
+	}
+	if elapsed > 0 {
+		path.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	}
+	if len(latencies) > 0 {
+		path.Mean, _ = stats.Mean(latencies)
+		path.P50, _ = stats.Percentile(latencies, 50)
+		path.P95, _ = stats.Percentile(latencies, 95)
+		path.P99, _ = stats.Percentile(latencies, 99)
+	}
+	return path
+}
+
+// activatorLevels pairs the measurements of both paths by concurrency level and computes the overhead of the activator
+func activatorLevels(concurrency []int, activator, direct []pkg.ActivatorPath) []pkg.ActivatorLevel {
+	levels := make([]pkg.ActivatorLevel, 0, len(concurrency))
+	for i, c := range concurrency {
+		if i >= len(activator) || i >= len(direct) {
+			break
+		}
+		level := pkg.ActivatorLevel{
+			Concurrency:  c,
+			Activator:    activator[i],
+			Direct:       direct[i],
+			MeanOverhead: activator[i].Mean - direct[i].Mean,
+			P50Overhead:  activator[i].P50 - direct[i].P50,
+			P95Overhead:  activator[i].P95 - direct[i].P95,
+			P99Overhead:  activator[i].P99 - direct[i].P99,
+		}
+		if direct[i].Throughput > 0 {
+			level.ThroughputLoss = 1 - activator[i].Throughput/direct[i].Throughput
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+// activatorRows returns the rows of the CSV file with both paths of a concurrency level side by side
+func activatorRows(result pkg.ActivatorOverheadResult) [][]string {
+	rows := [][]string{{"concurrency", "activator_throughput", "direct_throughput", "throughput_loss", "activator_p50", "direct_p50",
+		"p50_overhead", "activator_p95", "direct_p95", "p95_overhead", "activator_p99", "direct_p99", "p99_overhead", "activator_errors", "direct_errors"}}
+	for _, l := range result.Levels {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", l.Concurrency),
+			fmt.Sprintf("%f", l.Activator.Throughput),
+			fmt.Sprintf("%f", l.Direct.Throughput),
+			fmt.Sprintf("%f", l.ThroughputLoss),
+			fmt.Sprintf("%f", l.Activator.P50),
+			fmt.Sprintf("%f", l.Direct.P50),
+			fmt.Sprintf("%f", l.P50Overhead),
+			fmt.Sprintf("%f", l.Activator.P95),
+			fmt.Sprintf("%f", l.Direct.P95),
+			fmt.Sprintf("%f", l.P95Overhead),
+			fmt.Sprintf("%f", l.Activator.P99),
+			fmt.Sprintf("%f", l.Direct.P99),
+			fmt.Sprintf("%f", l.P99Overhead),
+			fmt.Sprintf("%d", l.Activator.Errors),
+			fmt.Sprintf("%d", l.Direct.Errors),
+		})
+	}
+	return rows
+}
+
+// writeActivatorOverhead writes the overhead of the activator path at each concurrency level
+func writeActivatorOverhead(out io.Writer, result pkg.ActivatorOverheadResult) {
+	fmt.Fprintf(out, "-------- Measurement --------\n")
+	fmt.Fprintf(out, "Activator Overhead of %s/%s:\n", result.ServiceNamespace, result.ServiceName)
+	for _, l := range result.Levels {
+		fmt.Fprintf(out, "Concurrency %d | Throughput: activator %.1f req/s direct %.1f req/s loss %.1f%% | P50 overhead: %fs P95 overhead: %fs P99 overhead: %fs\n",
+			l.Concurrency, l.Activator.Throughput, l.Direct.Throughput, l.ThroughputLoss*100, l.P50Overhead, l.P95Overhead, l.P99Overhead)
+	}
+}
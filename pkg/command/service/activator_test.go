@@ -0,0 +1,173 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	networkingv1alpha1fake "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestActivatorLevels(t *testing.T) {
+	activator := []pkg.ActivatorPath{{Throughput: 80, Mean: 0.012, P50: 0.010, P95: 0.020, P99: 0.030}}
+	direct := []pkg.ActivatorPath{{Throughput: 100, Mean: 0.010, P50: 0.008, P95: 0.015, P99: 0.020}}
+	levels := activatorLevels([]int{10, 50}, activator, direct)
+	assert.Equal(t, 1, len(levels))
+	assert.Equal(t, 10, levels[0].Concurrency)
+	assert.Assert(t, levels[0].P50Overhead > 0.0019 && levels[0].P50Overhead < 0.0021, levels[0].P50Overhead)
+	assert.Assert(t, levels[0].P95Overhead > 0.0049 && levels[0].P95Overhead < 0.0051, levels[0].P95Overhead)
+	assert.Assert(t, levels[0].ThroughputLoss > 0.199 && levels[0].ThroughputLoss < 0.201, levels[0].ThroughputLoss)
+
+	rows := activatorRows(pkg.ActivatorOverheadResult{Levels: levels})
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "10", rows[1][0])
+	assert.Equal(t, "0.200000", rows[1][3])
+}
+
+func TestSummarizeActivatorPath(t *testing.T) {
+	samples := []loadSample{{latency: 0.1}, {latency: 0.3}, {failed: true}, {latency: 0.2}}
+	path := summarizeActivatorPath(samples, 2*time.Second)
+	assert.Equal(t, 4, path.Requests)
+	assert.Equal(t, 1, path.Errors)
+	assert.Equal(t, 1.5, path.Throughput)
+	assert.Assert(t, path.Mean > 0.199 && path.Mean < 0.201, path.Mean)
+	assert.Assert(t, path.P50 > 0.1 && path.P50 <= 0.2, path.P50)
+}
+
+func TestMeasureActivatorOverhead(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+	url, err := apis.ParseURL(server.URL)
+	assert.NilError(t, err)
+
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	svc.Spec.Template.Annotations = map[string]string{autoscaling.MinScaleAnnotationKey: "0"}
+	svc.Status.URL = url
+	svc.Status.Conditions = []apis.Condition{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+	svc.Status.LatestReadyRevisionName = "ksvc-1-00001"
+	svc.Status.LatestCreatedRevisionName = "ksvc-1-00001"
+
+	client := k8sfake.NewSimpleClientset()
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeNetworking := &networkingv1alpha1fake.FakeNetworkingV1alpha1{Fake: &client.Fake}
+	_, err = fakeServing.Services("ns-1").Create(context.Background(), svc, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	// the ServerlessService follows the target burst capacity the service is patched with
+	mode := v1alpha1.SKSOperationModeServe
+	var modes []v1alpha1.ServerlessServiceOperationMode
+	client.PrependReactor("patch", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patched := servingv1.Service{}
+		if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), &patched); err != nil {
+			return true, nil, err
+		}
+		mode = v1alpha1.SKSOperationModeServe
+		if patched.Spec.Template.Annotations[autoscaling.TargetBurstCapacityKey] == "-1" {
+			mode = v1alpha1.SKSOperationModeProxy
+		}
+		return false, nil, nil
+	})
+	client.PrependReactor("get", "serverlessservices", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sks := &v1alpha1.ServerlessService{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001", Namespace: "ns-1"}}
+		sks.Spec.Mode = mode
+		sks.Status.MarkEndpointsReady()
+		modes = append(modes, sks.Spec.Mode)
+		return true, sks, nil
+	})
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return fakeNetworking, nil
+		},
+	}
+
+	outputDir := t.TempDir()
+	_, err = testutil.ExecuteCommand(NewServiceActivatorOverheadCommand(p), "--svc", "ksvc-1", "--namespace", "ns-1", "--resolvable",
+		"--concurrency", "1,2", "--duration", "100ms", "--settle", "5s", "--output", outputDir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []v1alpha1.ServerlessServiceOperationMode{v1alpha1.SKSOperationModeProxy, v1alpha1.SKSOperationModeServe}, modes)
+	assert.Assert(t, atomic.LoadInt32(&requests) > 0)
+
+	// the annotations are restored
+	restored, err := fakeServing.Services("ns-1").Get(context.Background(), "ksvc-1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{autoscaling.MinScaleAnnotationKey: "0"}, restored.Spec.Template.Annotations)
+
+	matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+ActivatorOutputFilename+".json"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(matches))
+	data, err := ioutil.ReadFile(matches[0])
+	assert.NilError(t, err)
+	result := pkg.ActivatorOverheadResult{}
+	assert.NilError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, 2, len(result.Levels))
+	assert.Equal(t, 2, result.Levels[1].Concurrency)
+	assert.Assert(t, result.Levels[0].Activator.Requests > 0 && result.Levels[0].Direct.Requests > 0)
+
+	t.Run("service not switched in time", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		fakeNetworking := &networkingv1alpha1fake.FakeNetworkingV1alpha1{Fake: &client.Fake}
+		_, err := fakeServing.Services("ns-1").Create(context.Background(), svc, metav1.CreateOptions{})
+		assert.NilError(t, err)
+		sks := &v1alpha1.ServerlessService{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001", Namespace: "ns-1"}}
+		sks.Spec.Mode = v1alpha1.SKSOperationModeServe
+		sks.Status.MarkEndpointsReady()
+		_, err = fakeNetworking.ServerlessServices("ns-1").Create(context.Background(), sks, metav1.CreateOptions{})
+		assert.NilError(t, err)
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return fakeNetworking, nil
+			},
+		}
+		_, err = testutil.ExecuteCommand(NewServiceActivatorOverheadCommand(p), "--svc", "ksvc-1", "--namespace", "ns-1", "--resolvable",
+			"--duration", "100ms", "--settle", "100ms", "--output", t.TempDir())
+		assert.ErrorContains(t, err, "service ns-1/ksvc-1 is not on the activator path after 100ms, its ServerlessService is in mode \"Serve\"")
+	})
+}
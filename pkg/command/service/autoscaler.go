@@ -0,0 +1,74 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package service
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/measure"
+)
+
+// addAutoscalerScrapeFlags adds the flags of scraping the autoscaler decisions during scale
+func addAutoscalerScrapeFlags(flags *pflag.FlagSet, args *pkg.AutoscalerScrapeArgs) {
+	flags.BoolVarP(&args.Enabled, "scrape-autoscaler", "", false, "Scrape the desired pods, panic mode and excess burst capacity the Knative Serving autoscaler reports for the scaled services during the run, to tell the time the autoscaler took to decide from the time the pods took to start")
+	flags.StringVarP(&args.Namespace, "autoscaler-namespace", "", measure.DefaultControlPlaneNamespace, "Namespace of the Knative Serving autoscaler pods scraped with --scrape-autoscaler")
+	flags.DurationVarP(&args.Interval, "autoscaler-interval", "", time.Second, "Interval between the scrapes of the autoscaler metrics with --scrape-autoscaler")
+	flags.IntVarP(&args.MetricsPort, "autoscaler-metrics-port", "", measure.DefaultControlPlaneMetricsPort, "Port the autoscaler pods serve their Prometheus metrics on")
+}
+
+// validateAutoscalerScrapeArgs checks the scrape flags if the autoscaler is scraped
+func validateAutoscalerScrapeArgs(args pkg.AutoscalerScrapeArgs) error {
+	if !args.Enabled {
+		return nil
+	}
+	if args.Interval <= 0 {
+		return fmt.Errorf("--autoscaler-interval must be positive, given %s", args.Interval)
+	}
+	if args.MetricsPort <= 0 || args.MetricsPort > 65535 {
+		return fmt.Errorf("--autoscaler-metrics-port must be between 1 and 65535, given %d", args.MetricsPort)
+	}
+	return nil
+}
+
+// writeAutoscalerMetrics writes when the autoscaler first wanted more pods for every revision, the peak of the
+// desired and actual pods, and whether it entered panic mode
+func writeAutoscalerMetrics(out io.Writer, metrics *pkg.AutoscalerMetrics) {
+	fmt.Fprintf(out, "\nAutoscaler Decisions:\n")
+	if len(metrics.Revisions) == 0 {
+		fmt.Fprintf(out, "- no metrics of the scaled services reported by the autoscaler in namespace %s\n", metrics.Namespace)
+	}
+	for _, r := range metrics.Revisions {
+		desired, actual, panicked := 0.0, 0.0, false
+		for _, s := range r.Samples {
+			if s.DesiredPods > desired {
+				desired = s.DesiredPods
+			}
+			if s.ActualPods > actual {
+				actual = s.ActualPods
+			}
+			panicked = panicked || s.PanicMode
+		}
+		scaleUp := "never"
+		if r.FirstScaleUp != nil {
+			scaleUp = fmt.Sprintf("%fs", *r.FirstScaleUp)
+		}
+		fmt.Fprintf(out, "- %s/%s (%s): first scale-up %s | peak desired pods %g actual pods %g | panic mode %t\n",
+			r.ServiceNamespace, r.ServiceName, r.Revision, scaleUp, desired, actual, panicked)
+	}
+}
@@ -17,17 +17,30 @@ package service
 import (
 	"context"
 	"fmt"
-	"strings"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/montanaflynn/stats"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/spf13/cobra"
 
 	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
 	"knative.dev/kperf/pkg/generator"
+	"knative.dev/kperf/pkg/report"
 )
 
+// CleanOutputFilename is the name of the report files of 'service clean' written with --report
+const CleanOutputFilename = "ksvc_clean"
+
 func NewServiceCleanCommand(p *pkg.PerfParams) *cobra.Command {
 	cleanArgs := pkg.CleanArgs{}
 	ksvcCleanCommand := &cobra.Command{
@@ -38,7 +51,43 @@ func NewServiceCleanCommand(p *pkg.PerfParams) *cobra.Command {
 For example:
 # To clean Knative Service workload
 kperf service clean --namespace-prefix testns / --namespace nsname
+
+# To clean the Knative Services with label app=demo in all namespaces
+kperf service clean --selector app=demo
+
+# To clean the Knative Services named like api-<name>-<number> in namespace nsname
+kperf service clean --namespace nsname --svc-regex '^api-[a-z]+-\d+$'
+
+# To clean the Knative Services and namespaces generated by the run with ID 20210117104747-3f9a
+kperf service clean --run-id 20210117104747-3f9a
+
+# To clean the Knative Services with prefix ksvc in namespace nsname created more than 2 hours ago
+kperf service clean --namespace nsname --svc-prefix ksvc --older-than 2h
+
+# To clean the Knative Services in namespace nsname and write the report of the deletions as JSON and YAML to /tmp
+kperf service clean --namespace nsname --report --output-format json,yaml --output /tmp
 `,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := labels.Parse(cleanArgs.Selector); err != nil {
+				return fmt.Errorf("invalid selector %q: %s", cleanArgs.Selector, err)
+			}
+			if _, err := compileSvcRegex(cleanArgs.SvcRegex); err != nil {
+				return err
+			}
+			if err := pkg.ValidateRunID(cleanArgs.RunID); err != nil {
+				return err
+			}
+			if cleanArgs.OlderThan < 0 {
+				return fmt.Errorf("--older-than must not be negative, given %s", cleanArgs.OlderThan)
+			}
+			// with a selector, a regex or a run ID the services are only filtered by the name prefix if it is given
+			// explicitly
+			if (cleanArgs.Selector != "" || cleanArgs.SvcRegex != "" || cleanArgs.RunID != "") && !cmd.Flags().Changed("svc-prefix") {
+				cleanArgs.SvcPrefix = ""
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return CleanServices(p, cleanArgs)
 		},
@@ -48,37 +97,65 @@ kperf service clean --namespace-prefix testns / --namespace nsname
 	ksvcCleanCommand.Flags().StringVarP(&cleanArgs.NamespaceRange, "namespace-range", "", "", "")
 	ksvcCleanCommand.Flags().StringVarP(&cleanArgs.Namespace, "namespace", "", "", "Namespace name. The ksvc in the namespace will be cleaned.")
 	ksvcCleanCommand.Flags().StringVarP(&cleanArgs.SvcPrefix, "svc-prefix", "", "testksvc", "ksvc name prefix. The ksvcs will be svcPrefix1,svcPrefix2,svcPrefix3......")
+	ksvcCleanCommand.Flags().StringVarP(&cleanArgs.Selector, "selector", "l", "", "Label selector of the ksvcs to clean, e.g. app=demo. Without a namespace the ksvcs in all namespaces are cleaned")
+	ksvcCleanCommand.Flags().StringVarP(&cleanArgs.SvcRegex, "svc-regex", "", "", "Regular expression the ksvc names must match, e.g. '^api-[a-z]+-\\d+$'. Without a namespace the ksvcs in all namespaces are cleaned")
+	ksvcCleanCommand.Flags().StringVarP(&cleanArgs.RunID, "run-id", "", "", "ID of the run whose ksvcs and created namespaces are cleaned, as printed by 'kperf service generate'. Without a namespace the ksvcs in all namespaces are cleaned")
+	ksvcCleanCommand.Flags().DurationVarP(&cleanArgs.OlderThan, "older-than", "", 0, "Only clean the ksvcs created longer than this duration ago, e.g. 2h, 0 for all ksvcs")
 	ksvcCleanCommand.Flags().IntVarP(&cleanArgs.Concurrency, "concurrency", "c", 10, "Number of multiple ksvcs to make at a time")
+	ksvcCleanCommand.Flags().BoolVarP(&cleanArgs.Report, "report", "", false, "Write the deleted ksvcs and namespaces, the durations of their Delete calls and the failures to report files")
+	ksvcCleanCommand.Flags().StringVarP(&cleanArgs.Output, "output", "o", ".", "Location of the report files written with --report")
+	report.AddFlag(ksvcCleanCommand.Flags(), &cleanArgs.OutputFormats)
 
 	return ksvcCleanCommand
 }
 
 // CleanServices used to clean Knative Service workload
 func CleanServices(params *pkg.PerfParams, inputs pkg.CleanArgs) error {
-	nsNameList, err := GetNamespaces(context.Background(), params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	re, err := compileSvcRegex(inputs.SvcRegex)
 	if err != nil {
 		return err
 	}
+	selector := pkg.RunSelector(inputs.Selector, inputs.RunID)
+	var nsNameList []string
+	if (selector != "" || re != nil) && inputs.Namespace == "" && inputs.NamespacePrefix == "" {
+		nsNameList = []string{metav1.NamespaceAll}
+	} else {
+		nsNameList, err = GetNamespaces(context.Background(), params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+		if err != nil {
+			return err
+		}
+	}
 
 	ksvcClient, err := params.NewServingClient()
 	if err != nil {
 		return err
 	}
+	var clk clock.Clock = clock.RealClock{}
+	if params.Clock != nil {
+		clk = params.Clock
+	}
+	createdBefore := clk.Now().Add(-inputs.OlderThan)
+	recorder := newCleanRecorder(clk)
 
 	matchedNsNameList := [][2]string{}
 	cleanKsvc := func(namespace, name string) {
 		fmt.Printf("Delete ksvc %s in namespace %s\n", name, namespace)
+		start := clk.Now()
 		err := ksvcClient.Services(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		recorder.service(namespace, name, clk.Since(start), err)
 		if err != nil {
 			fmt.Printf("Failed to delete ksvc %s in namespace %s\n", name, namespace)
 		}
 	}
 	for i := 0; i < len(nsNameList); i++ {
-		svcList, err := ksvcClient.Services(nsNameList[i]).List(context.TODO(), metav1.ListOptions{})
+		svcList, err := ksvcClient.Services(nsNameList[i]).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
 		if err == nil {
 			for j := 0; j < len(svcList.Items); j++ {
-				if strings.HasPrefix(svcList.Items[j].Name, inputs.SvcPrefix) {
-					matchedNsNameList = append(matchedNsNameList, [2]string{nsNameList[i], svcList.Items[j].Name})
+				if inputs.OlderThan > 0 && !svcList.Items[j].CreationTimestamp.Time.Before(createdBefore) {
+					continue
+				}
+				if matchSvcName(svcList.Items[j].Name, []string{inputs.SvcPrefix}, re) {
+					matchedNsNameList = append(matchedNsNameList, [2]string{svcList.Items[j].Namespace, svcList.Items[j].Name})
 				}
 			}
 		}
@@ -88,5 +165,138 @@ func CleanServices(params *pkg.PerfParams, inputs pkg.CleanArgs) error {
 	} else {
 		fmt.Println("No service found for cleaning")
 	}
+	if inputs.RunID != "" {
+		err = cleanRunNamespaces(params, pkg.RunSelector("", inputs.RunID), clk, recorder)
+	}
+	recorder.write(os.Stdout)
+	if inputs.Report {
+		return utilerrors.NewAggregate([]error{err, recorder.save(os.Stdout, params.Output, inputs.Output, inputs.RunID, inputs.OutputFormats)})
+	}
+	return err
+}
+
+// cleanRunNamespaces deletes the namespaces created by the run, which are labeled with its run ID
+func cleanRunNamespaces(params *pkg.PerfParams, selector string, clk clock.PassiveClock, recorder *cleanRecorder) error {
+	nsList, err := params.ClientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces of the run: %w", err)
+	}
+	for _, ns := range nsList.Items {
+		fmt.Printf("Delete namespace %s\n", ns.Name)
+		start := clk.Now()
+		err := params.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), ns.Name, metav1.DeleteOptions{})
+		recorder.namespace(ns.Name, clk.Since(start), err)
+		if err != nil {
+			fmt.Printf("Failed to delete namespace %s\n", ns.Name)
+		}
+	}
 	return nil
 }
+
+// cleanRecorder records the durations of the Delete calls of the cleaned services and namespaces, and why the ones
+// which failed couldn't be deleted. The services are deleted concurrently.
+type cleanRecorder struct {
+	mu         sync.Mutex
+	clock      clock.PassiveClock
+	started    time.Time
+	services   []pkg.CleanedResource
+	namespaces []pkg.CleanedResource
+}
+
+func newCleanRecorder(clk clock.PassiveClock) *cleanRecorder {
+	return &cleanRecorder{clock: clk, started: clk.Now()}
+}
+
+// cleaned returns the cleaned resource with the duration of its Delete call and its error
+func cleaned(namespace, name string, d time.Duration, err error) pkg.CleanedResource {
+	r := pkg.CleanedResource{Name: name, Namespace: namespace, Duration: d.Seconds()}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// service records the Delete call of the service
+func (r *cleanRecorder) service(namespace, name string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services = append(r.services, cleaned(namespace, name, d, err))
+}
+
+// namespace records the Delete call of the namespace
+func (r *cleanRecorder) namespace(name string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.namespaces = append(r.namespaces, cleaned("", name, d, err))
+}
+
+// result returns the cleaned services sorted by namespace and name and the namespaces sorted by name, with the
+// statistics of the durations of the deleted services
+func (r *cleanRecorder) result() pkg.CleanResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := pkg.CleanResult{
+		Duration:   r.clock.Since(r.started).Seconds(),
+		Services:   append([]pkg.CleanedResource{}, r.services...),
+		Namespaces: append([]pkg.CleanedResource(nil), r.namespaces...),
+	}
+	sort.Slice(result.Services, func(i, j int) bool {
+		if result.Services[i].Namespace != result.Services[j].Namespace {
+			return result.Services[i].Namespace < result.Services[j].Namespace
+		}
+		return result.Services[i].Name < result.Services[j].Name
+	})
+	sort.Slice(result.Namespaces, func(i, j int) bool {
+		return result.Namespaces[i].Name < result.Namespaces[j].Name
+	})
+	durations := []float64{}
+	for _, s := range result.Services {
+		if s.Error != "" {
+			result.Failed++
+			continue
+		}
+		durations = append(durations, s.Duration)
+	}
+	result.Deleted = len(durations)
+	if len(durations) > 0 {
+		result.Average, _ = stats.Mean(durations)
+		result.P50, _ = stats.Percentile(durations, 50)
+		result.P95, _ = stats.Percentile(durations, 95)
+		result.P99, _ = stats.Percentile(durations, 99)
+		result.Max, _ = stats.Max(durations)
+	}
+	return result
+}
+
+// write writes the number of deleted and failed services with the statistics of the durations of their Delete calls
+func (r *cleanRecorder) write(out io.Writer) {
+	result := r.result()
+	fmt.Fprintf(out, "\nClean Measurement:\n")
+	if result.Deleted == 0 {
+		fmt.Fprintf(out, "Deleted: 0 Failed: %d\n", result.Failed)
+		return
+	}
+	fmt.Fprintf(out, "Deleted: %d Failed: %d | Average: %fs Percentile50: %fs Percentile95: %fs Percentile99: %fs Max: %fs\n",
+		result.Deleted, result.Failed, result.Average, result.P50, result.P95, result.P99, result.Max)
+}
+
+// save writes the report of the clean in the formats to the report files in the output location of the run
+func (r *cleanRecorder) save(out io.Writer, outputOptions pkg.OutputOptions, output, runID string, formats []string) error {
+	outputLocation, err := utils.RunOutputLocation(output, runID)
+	if err != nil {
+		return err
+	}
+	result := r.result()
+	rows := [][]string{{"svc_name", "svc_namespace", "deleted"}}
+	for _, s := range result.Services {
+		if s.Error == "" {
+			rows = append(rows, []string{s.Name, s.Namespace, fmt.Sprintf("%f", s.Duration)})
+		}
+	}
+	return report.Write(out, outputLocation, r.clock.Now().Format(utils.DateFormatString), outputOptions, report.Report{
+		Name:    CleanOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: formats,
+	})
+}
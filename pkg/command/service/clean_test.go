@@ -15,14 +15,28 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
 	"knative.dev/kperf/pkg/testutil"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
 	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
 )
@@ -127,6 +141,192 @@ func TestNewServiceCleanCommand(t *testing.T) {
 		assert.NilError(t, err)
 	})
 
+	t.Run("clean services by selector", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		var listNamespace, listSelector string
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			listNamespace = action.GetNamespace()
+			listSelector = action.(clienttesting.ListAction).GetListRestrictions().Labels.String()
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "demo-1", Namespace: "ns-1", Labels: map[string]string{"app": "demo"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "other-1", Namespace: "ns-2", Labels: map[string]string{"app": "demo"}}},
+			}}, nil
+		})
+		var deleted []string
+		fakeServing.PrependReactor("delete", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			deleted = append(deleted, action.GetNamespace()+"/"+action.(clienttesting.DeleteAction).GetName())
+			return true, nil, nil
+		})
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceCleanCommand(p), "--selector", "app in (")
+		assert.ErrorContains(t, err, "invalid selector \"app in (\"")
+
+		// the default name prefix is not applied to the services matching the selector
+		_, err = testutil.ExecuteCommand(NewServiceCleanCommand(p), "--selector", "app=demo", "--concurrency", "1")
+		assert.NilError(t, err)
+		assert.Equal(t, "", listNamespace)
+		assert.Equal(t, "app=demo", listSelector)
+		assert.DeepEqual(t, []string{"ns-1/demo-1", "ns-2/other-1"}, deleted)
+
+		deleted = nil
+		_, err = testutil.ExecuteCommand(NewServiceCleanCommand(p), "--selector", "app=demo", "--svc-prefix", "demo")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"ns-1/demo-1"}, deleted)
+	})
+
+	t.Run("clean services by name regex", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		var listNamespace string
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			listNamespace = action.GetNamespace()
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "api-orders-1", Namespace: "ns-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "api-orders-x", Namespace: "ns-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "ns-2"}},
+			}}, nil
+		})
+		var deleted []string
+		fakeServing.PrependReactor("delete", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			deleted = append(deleted, action.GetNamespace()+"/"+action.(clienttesting.DeleteAction).GetName())
+			return true, nil, nil
+		})
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceCleanCommand(p), "--svc-regex", "api-(")
+		assert.ErrorContains(t, err, "invalid --svc-regex \"api-(\"")
+
+		_, err = testutil.ExecuteCommand(NewServiceCleanCommand(p), "--svc-regex", `^api-[a-z]+-\d+$`)
+		assert.NilError(t, err)
+		assert.Equal(t, "", listNamespace)
+		assert.DeepEqual(t, []string{"ns-1/api-orders-1"}, deleted)
+	})
+
+	t.Run("clean services and namespaces by run ID", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1", Labels: map[string]string{pkg.RunIDLabel: "demo"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-2"}},
+		)
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		var selector string
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			selector = action.(clienttesting.ListAction).GetListRestrictions().Labels.String()
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-0", Namespace: "ns-1", Labels: map[string]string{"app": "api", pkg.RunIDLabel: "demo"}}},
+			}}, nil
+		})
+		var deleted []string
+		fakeServing.PrependReactor("delete", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			deleted = append(deleted, action.GetNamespace()+"/"+action.(clienttesting.DeleteAction).GetName())
+			return true, nil, nil
+		})
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceCleanCommand(p), "--run-id", "demo", "--selector", "app=api")
+		assert.NilError(t, err)
+		assert.Equal(t, "app=api,"+pkg.RunIDLabel+"=demo", selector)
+		assert.DeepEqual(t, []string{"ns-1/ksvc-0"}, deleted)
+		_, err = client.CoreV1().Namespaces().Get(context.TODO(), "ns-1", metav1.GetOptions{})
+		assert.Check(t, apierrors.IsNotFound(err), "expected the namespace of the run to be deleted")
+		_, err = client.CoreV1().Namespaces().Get(context.TODO(), "ns-2", metav1.GetOptions{})
+		assert.NilError(t, err)
+	})
+
+	t.Run("clean services older than a duration", func(t *testing.T) {
+		now := time.Now()
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "testksvc-0", Namespace: "ns-1", CreationTimestamp: metav1.NewTime(now.Add(-3 * time.Hour))}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "testksvc-1", Namespace: "ns-1", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}},
+			}}, nil
+		})
+		var deleted []string
+		fakeServing.PrependReactor("delete", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			deleted = append(deleted, action.GetNamespace()+"/"+action.(clienttesting.DeleteAction).GetName())
+			return true, nil, nil
+		})
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+			Clock: clock.NewFakeClock(now),
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceCleanCommand(p), "--namespace", "ns-1", "--older-than", "-1h")
+		assert.ErrorContains(t, err, "--older-than must not be negative")
+
+		_, err = testutil.ExecuteCommand(NewServiceCleanCommand(p), "--namespace", "ns-1", "--older-than", "2h")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"ns-1/testksvc-0"}, deleted)
+	})
+
+	t.Run("write the report of the clean", func(t *testing.T) {
+		now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "testksvc-1", Namespace: "ns-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "testksvc-0", Namespace: "ns-1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "testksvc-2", Namespace: "ns-1"}},
+			}}, nil
+		})
+		fakeServing.PrependReactor("delete", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if action.(clienttesting.DeleteAction).GetName() == "testksvc-2" {
+				return true, nil, errors.New("webhook denied the request")
+			}
+			return true, nil, nil
+		})
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+			Clock: clock.NewFakeClock(now),
+		}
+
+		dir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceCleanCommand(p), "--namespace", "ns-1", "--report", "--output-format", "json,yaml", "--output", dir)
+		assert.NilError(t, err)
+		data, err := ioutil.ReadFile(filepath.Join(dir, now.Format(utils.DateFormatString)+"_"+CleanOutputFilename+".json"))
+		assert.NilError(t, err)
+		var result pkg.CleanResult
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.DeepEqual(t, pkg.CleanResult{
+			Deleted: 2,
+			Failed:  1,
+			Services: []pkg.CleanedResource{
+				{Name: "testksvc-0", Namespace: "ns-1"},
+				{Name: "testksvc-1", Namespace: "ns-1"},
+				{Name: "testksvc-2", Namespace: "ns-1", Error: "webhook denied the request"},
+			},
+		}, result)
+		_, err = os.Stat(filepath.Join(dir, now.Format(utils.DateFormatString)+"_"+CleanOutputFilename+".yaml"))
+		assert.NilError(t, err)
+		_, err = os.Stat(filepath.Join(dir, now.Format(utils.DateFormatString)+"_"+CleanOutputFilename+".csv"))
+		assert.Check(t, os.IsNotExist(err))
+	})
+
 	t.Run("failed to clean services", func(t *testing.T) {
 		ns := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
@@ -175,3 +375,26 @@ func TestNewServiceCleanCommand(t *testing.T) {
 		assert.NilError(t, err)
 	})
 }
+
+func TestCleanRecorder(t *testing.T) {
+	clk := clock.NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	recorder := newCleanRecorder(clk)
+	recorder.service("ns-1", "ksvc-2", 3*time.Second, nil)
+	recorder.service("ns-1", "ksvc-1", time.Second, nil)
+	recorder.service("ns-1", "ksvc-3", time.Second, errors.New("timeout"))
+	recorder.namespace("ns-1", 2*time.Second, nil)
+	clk.Step(5 * time.Second)
+
+	result := recorder.result()
+	assert.Equal(t, 2, result.Deleted)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 5.0, result.Duration)
+	assert.Equal(t, 2.0, result.Average)
+	assert.Equal(t, 3.0, result.Max)
+	assert.Equal(t, "ksvc-1", result.Services[0].Name)
+	assert.DeepEqual(t, []pkg.CleanedResource{{Name: "ns-1", Duration: 2}}, result.Namespaces)
+
+	out := &bytes.Buffer{}
+	recorder.write(out)
+	assert.Equal(t, "\nClean Measurement:\nDeleted: 2 Failed: 1 | Average: 2.000000s Percentile50: 1.000000s Percentile95: 2.000000s Percentile99: 2.000000s Max: 3.000000s\n", out.String())
+}
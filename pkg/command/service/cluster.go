@@ -0,0 +1,204 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	// AllClusters is the group of the services of all clusters merged
+	AllClusters = "<all>"
+
+	ClusterOutputFilename = "ksvc_creation_time_by_cluster"
+)
+
+// contextParams returns the params with the clients for the cluster of the kubeconfig context
+var contextParams = func(params *pkg.PerfParams, context string) (*pkg.PerfParams, error) {
+	return params.ForContext(context)
+}
+
+// validateContexts rejects empty and duplicated contexts
+func validateContexts(contexts []string) error {
+	seen := map[string]bool{}
+	for _, c := range contexts {
+		if c == "" {
+			return fmt.Errorf("--contexts must not contain an empty context")
+		}
+		if seen[c] {
+			return fmt.Errorf("--contexts contains the context %s twice", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// validateMeasureContexts rejects the flags which can't be combined with measuring several clusters. The merged
+// statistics need the per service rows, and a checkpoint holds the services of a single cluster.
+func validateMeasureContexts(inputs pkg.MeasureArgs) error {
+	if len(inputs.Contexts) == 0 {
+		return nil
+	}
+	if err := validateContexts(inputs.Contexts); err != nil {
+		return err
+	}
+	for _, f := range []struct {
+		flag string
+		set  bool
+	}{
+		{"--follow", inputs.Follow},
+		{"--summary-only", inputs.SummaryOnly},
+		{"--checkpoint", inputs.Checkpoint != ""},
+		{"--stream", inputs.Stream},
+	} {
+		if f.set {
+			return fmt.Errorf("--contexts can't be combined with %s", f.flag)
+		}
+	}
+	return nil
+}
+
+// clusterLocation returns the subdirectory of the output location of the cluster of the context, which is created
+// if it doesn't exist. Contexts like arn:aws:eks:eu-west-1:123:cluster/demo are no valid directory names, the
+// characters other than letters, digits, '.', '-' and '_' are replaced by '_'.
+func clusterLocation(outputLocation, context string) (string, error) {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, context)
+	return utils.RunOutputLocation(outputLocation, name)
+}
+
+// GenerateClusters generates the services in the clusters of the kubeconfig contexts one after the other. The
+// services of all clusters are labeled with the same run ID, so that they can be measured and cleaned up together.
+func GenerateClusters(params *pkg.PerfParams, inputs pkg.GenerateArgs) error {
+	if inputs.RunID == "" {
+		var clk clock.PassiveClock = clock.RealClock{}
+		if params.Clock != nil {
+			clk = params.Clock
+		}
+		inputs.RunID = pkg.NewRunID(clk.Now())
+	}
+	failed := make([]string, 0)
+	for _, c := range inputs.Contexts {
+		fmt.Printf("\n======== Cluster %s ========\n", c)
+		clusterInputs := inputs
+		cp, err := contextParams(params, c)
+		if err == nil && inputs.ControlPlane.Enabled {
+			clusterInputs.Output, err = clusterLocation(inputs.Output, c)
+		}
+		if err == nil {
+			err = GenerateServices(cp, clusterInputs)
+		}
+		if err != nil {
+			fmt.Printf("failed to generate services in cluster %s: %s\n", c, err)
+			failed = append(failed, c)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to generate services in cluster(s) %s", strings.Join(failed, ","))
+	}
+	return nil
+}
+
+// MeasureClusters measures the services in the clusters of the kubeconfig contexts one after the other. The results
+// of every cluster are written to the subdirectory of the output location named by its context, the merged results
+// and the comparison of the clusters to the output location.
+func MeasureClusters(params *pkg.PerfParams, inputs pkg.MeasureArgs, options MeasureServicesOptions) error {
+	results := make([]*measure.Result, len(inputs.Contexts))
+	failed := make([]string, 0)
+	for i, c := range inputs.Contexts {
+		fmt.Printf("\n======== Cluster %s ========\n", c)
+		clusterInputs := inputs
+		cp, err := contextParams(params, c)
+		if err == nil {
+			clusterInputs.Output, err = clusterLocation(inputs.Output, c)
+		}
+		if err == nil {
+			results[i], err = measureServices(cp, clusterInputs, options)
+		}
+		if err != nil {
+			fmt.Printf("failed to measure services in cluster %s: %s\n", c, err)
+			failed = append(failed, c)
+		}
+		if results[i] != nil && results[i].Summary.Interrupted > 0 {
+			// the clusters left are not measured after Ctrl-C, the ones measured so far are still merged
+			break
+		}
+	}
+
+	multi := measure.MergeClusters(inputs.Contexts, results)
+	writeClusters(os.Stdout, multi)
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	current := time.Now()
+	if params.Clock != nil {
+		current = params.Clock.Now()
+	}
+	if err := writeClusterFiles(os.Stdout, params.Output, outputLocation, current, inputs.OutputFormats, multi); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to measure services in cluster(s) %s", strings.Join(failed, ","))
+	}
+	return nil
+}
+
+// clusterGroups returns the clusters and all clusters merged as groups, so that they are written like the groups of
+// --group-by
+func clusterGroups(multi pkg.MultiClusterMeasureResult) []pkg.GroupMeasureResult {
+	groups := make([]pkg.GroupMeasureResult, 0, len(multi.Clusters)+1)
+	for _, c := range multi.Clusters {
+		groups = append(groups, pkg.GroupMeasureResult{Group: c.Context, Service: c.Service, Result: c.Result})
+	}
+	return append(groups, pkg.GroupMeasureResult{Group: AllClusters, Service: multi.Service, Result: multi.Result})
+}
+
+// writeClusters writes the Knative versions, the counts and the statistics of every cluster, and the ones of all
+// clusters merged as group AllClusters
+func writeClusters(out io.Writer, multi pkg.MultiClusterMeasureResult) {
+	fmt.Fprintf(out, "\n-------- Multi-Cluster Measurement --------\n")
+	for _, c := range multi.Clusters {
+		fmt.Fprintf(out, "%s: Serving: %s Ingress: %s %s\n", c.Context, c.KnativeInfo.ServingVersion,
+			c.KnativeInfo.IngressController, c.KnativeInfo.IngressVersion)
+	}
+	writeGroups(out, "cluster", clusterGroups(multi))
+}
+
+// writeClusterFiles writes the comparison of the clusters, including all clusters merged, as rows and the
+// multi-cluster result in the output formats
+func writeClusterFiles(out io.Writer, outputOptions pkg.OutputOptions, outputLocation string, current time.Time, formats []string, multi pkg.MultiClusterMeasureResult) error {
+	return report.Write(out, outputLocation, current.Format(utils.DateFormatString), outputOptions, report.Report{
+		Name:    ClusterOutputFilename,
+		Rows:    groupRows("context", clusterGroups(multi)),
+		Result:  multi,
+		Formats: formats,
+	})
+}
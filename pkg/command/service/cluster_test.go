@@ -0,0 +1,168 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	fakenetworkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	autoscalingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
+	autoscalingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1/fake"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+// fakeContexts replaces the clients of the contexts by fake ones, the contexts without fake clients fail
+func fakeContexts(t *testing.T, clusters map[string]*pkg.PerfParams) {
+	original := contextParams
+	contextParams = func(params *pkg.PerfParams, context string) (*pkg.PerfParams, error) {
+		if p, ok := clusters[context]; ok {
+			return p, nil
+		}
+		return nil, errors.New("context \"" + context + "\" does not exist")
+	}
+	t.Cleanup(func() { contextParams = original })
+}
+
+func newClusterParams() *pkg.PerfParams {
+	client := k8sfake.NewSimpleClientset()
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+			return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: &client.Fake}, nil
+		},
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: &client.Fake}, nil
+		},
+	}
+}
+
+func TestValidateMeasureContexts(t *testing.T) {
+	assert.NilError(t, validateMeasureContexts(pkg.MeasureArgs{Follow: true}))
+	assert.NilError(t, validateMeasureContexts(pkg.MeasureArgs{Contexts: []string{"kourier", "istio"}}))
+	assert.ErrorContains(t, validateMeasureContexts(pkg.MeasureArgs{Contexts: []string{"kourier", ""}}), "--contexts must not contain an empty context")
+	assert.ErrorContains(t, validateMeasureContexts(pkg.MeasureArgs{Contexts: []string{"kourier", "kourier"}}), "--contexts contains the context kourier twice")
+	assert.ErrorContains(t, validateMeasureContexts(pkg.MeasureArgs{Contexts: []string{"kourier"}, Follow: true}), "--contexts can't be combined with --follow")
+	assert.ErrorContains(t, validateMeasureContexts(pkg.MeasureArgs{Contexts: []string{"kourier"}, SummaryOnly: true}), "--contexts can't be combined with --summary-only")
+}
+
+func TestClusterLocation(t *testing.T) {
+	dir := t.TempDir()
+	location, err := clusterLocation(dir, "arn:aws:eks:eu-west-1:123:cluster/demo")
+	assert.NilError(t, err)
+	assert.Equal(t, filepath.Join(dir, "arn_aws_eks_eu-west-1_123_cluster_demo"), location)
+	_, err = os.Stat(location)
+	assert.NilError(t, err)
+}
+
+func TestGenerateClusters(t *testing.T) {
+	kourier, istio := newClusterParams(), newClusterParams()
+	fakeContexts(t, map[string]*pkg.PerfParams{"kourier": kourier, "istio": istio})
+
+	_, err := testutil.ExecuteCommand(NewServiceGenerateCommand(&pkg.PerfParams{}), "-n", "1", "-b", "1", "-i", "1",
+		"--namespace", "ns-1", "--create-namespaces", "--run-id", "compare", "--contexts", "kourier,istio,missing")
+	assert.ErrorContains(t, err, "failed to generate services in cluster(s) missing")
+	for _, p := range []*pkg.PerfParams{kourier, istio} {
+		client, _ := p.NewServingClient()
+		svc, err := client.Services("ns-1").Get(context.TODO(), "ksvc-0", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "compare", svc.Labels[pkg.RunIDLabel])
+	}
+}
+
+func TestMeasureClusters(t *testing.T) {
+	fakeContexts(t, map[string]*pkg.PerfParams{"kourier": newClusterParams(), "istio": newClusterParams()})
+	dir := t.TempDir()
+
+	_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(&pkg.PerfParams{}), "--svc-prefix", "ksvc", "--range", "1,2", "--namespace", "ns-1",
+		"--output", dir, "--contexts", "kourier,missing,istio")
+	assert.ErrorContains(t, err, "failed to measure services in cluster(s) missing")
+
+	for _, c := range []string{"kourier", "istio"} {
+		_, err := os.Stat(filepath.Join(dir, c))
+		assert.NilError(t, err)
+	}
+	csvFiles, err := filepath.Glob(filepath.Join(dir, "*_ksvc_creation_time_by_cluster.csv"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(csvFiles))
+	data, err := ioutil.ReadFile(csvFiles[0])
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Assert(t, strings.HasPrefix(lines[1], "kourier,0,0,2,0,0,"))
+	assert.Assert(t, strings.HasPrefix(lines[2], "istio,0,0,2,0,0,"))
+	assert.Assert(t, strings.HasPrefix(lines[3], AllClusters+",0,0,4,0,0,"))
+
+	jsonFiles, err := filepath.Glob(filepath.Join(dir, "*_ksvc_creation_time_by_cluster.json"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(jsonFiles))
+}
+
+func TestMeasureClustersInterrupted(t *testing.T) {
+	kourier := newClusterParams()
+	fakeContexts(t, map[string]*pkg.PerfParams{"kourier": kourier, "istio": newClusterParams()})
+	var interrupt context.CancelFunc
+	original := interruptContext
+	interruptContext = func(parent context.Context) (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(parent)
+		interrupt = cancel
+		return ctx, cancel
+	}
+	t.Cleanup(func() { interruptContext = original })
+	// Ctrl-C while ksvc-2 of the first cluster is measured
+	kourier.ClientSet.(*k8sfake.Clientset).PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.(clienttesting.GetAction).GetName() == "ksvc-2" {
+			interrupt()
+			return true, nil, context.Canceled
+		}
+		return false, nil, nil
+	})
+	dir := t.TempDir()
+
+	_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(&pkg.PerfParams{}), "--svc-prefix", "ksvc", "--range", "1,3", "--namespace", "ns-1",
+		"--concurrency", "1", "--output", dir, "--contexts", "kourier,istio")
+	assert.ErrorContains(t, err, "failed to measure services in cluster(s) kourier")
+
+	// the cluster left is not measured, the partial result of the interrupted one is still written
+	_, err = os.Stat(filepath.Join(dir, "istio"))
+	assert.Assert(t, os.IsNotExist(err))
+	csvFiles, err := filepath.Glob(filepath.Join(dir, "*_ksvc_creation_time_by_cluster.csv"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(csvFiles))
+	data, err := ioutil.ReadFile(csvFiles[0])
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Assert(t, strings.HasPrefix(lines[1], "kourier,0,0,1,0,0,"), lines[1])
+}
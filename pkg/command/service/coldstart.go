@@ -0,0 +1,256 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const (
+	ColdStartOutputFilename = "ksvc_coldstart_time"
+)
+
+func NewServiceColdStartCommand(p *pkg.PerfParams) *cobra.Command {
+	coldStartArgs := pkg.ColdStartArgs{}
+	serviceColdStartCommand := &cobra.Command{
+		Use:   "coldstart",
+		Short: "Measure Knative service cold start latency",
+		Long: `Wait for Knative services to be scaled to zero, send a request to each and measure the time to first byte
+
+The time to first byte is broken down into the time until the Pod is created, the Pod scheduled, the queue-proxy and
+user-container started, and the time the activator takes to forward the request once the Pod is ready.
+
+For example:
+# To measure the cold start latency of the Knative Services with prefix svc in namespace ns
+kperf service coldstart --svc-prefix svc --namespace ns --concurrency 20
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'service coldstart' requires flag(s)")
+			}
+			return pkg.ValidateRunID(coldStartArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureColdStart(p, coldStartArgs)
+		},
+	}
+
+	serviceColdStartCommand.Flags().StringVarP(&coldStartArgs.Namespace, "namespace", "", "", "Service namespace")
+	serviceColdStartCommand.Flags().StringVarP(&coldStartArgs.SvcPrefix, "svc-prefix", "", "", "Service name prefix")
+	serviceColdStartCommand.Flags().BoolVarP(&coldStartArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	serviceColdStartCommand.Flags().StringVarP(&coldStartArgs.NamespaceRange, "namespace-range", "", "", "Service namespace range")
+	serviceColdStartCommand.Flags().StringVarP(&coldStartArgs.NamespacePrefix, "namespace-prefix", "", "", "Service namespace prefix")
+	serviceColdStartCommand.Flags().IntVarP(&coldStartArgs.Concurrency, "concurrency", "c", 10, "Number of workers to do measurement job")
+	serviceColdStartCommand.Flags().StringVarP(&coldStartArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(serviceColdStartCommand.Flags(), &coldStartArgs.OutputFormats)
+	serviceColdStartCommand.Flags().StringVarP(&coldStartArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	serviceColdStartCommand.Flags().BoolVarP(&coldStartArgs.ResolvableDomain, "resolvable", "", false, "If Service endpoint resolvable url")
+	serviceColdStartCommand.Flags().DurationVarP(&coldStartArgs.ScaleToZeroTimeout, "scale-to-zero-timeout", "", 5*time.Minute, "Duration to wait for Knative Service to be scaled to zero")
+	serviceColdStartCommand.Flags().DurationVarP(&coldStartArgs.RequestTimeout, "timeout", "", 2*time.Minute, "Duration to wait for the first response of Knative Service")
+	return serviceColdStartCommand
+}
+
+// MeasureColdStart used to measure the cold start latency of Knative Services scaled to zero
+func MeasureColdStart(params *pkg.PerfParams, inputs pkg.ColdStartArgs) error {
+	ctx := context.Background()
+	nsNameList, err := GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	objs := getServices(ctx, ksvcClient, nsNameList, inputs.SvcPrefix)
+	if len(objs) == 0 {
+		return fmt.Errorf("no service found to measure")
+	}
+
+	result := pkg.ColdStartResult{}
+	var m sync.Mutex
+	pool.ForEach(ctx, inputs.Concurrency, len(objs), func(ctx context.Context, i int) {
+		obj := objs[i]
+		measurement, err := runColdStart(ctx, params, inputs, obj.Namespace, obj.Service)
+		if err != nil {
+			fmt.Printf("failed to measure cold start of service %s/%s and skip: %s\n", obj.Namespace, obj.Service.Name, err)
+			return
+		}
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Service %s: Time To First Byte is %fs\n", measurement.ServiceName, measurement.TimeToFirstByte)
+			fmt.Printf("[Verbose] Service %s: - Pod Created Duration is %fs\n", measurement.ServiceName, measurement.PodCreated)
+			fmt.Printf("[Verbose] Service %s:   - Pod Scheduled Duration is %fs\n", measurement.ServiceName, measurement.PodScheduled)
+			fmt.Printf("[Verbose] Service %s:   - Pod queue-proxy Started Duration is %fs\n", measurement.ServiceName, measurement.QueueProxyStarted)
+			fmt.Printf("[Verbose] Service %s:   - Pod user-container Started Duration is %fs\n", measurement.ServiceName, measurement.UserContainerStarted)
+			fmt.Printf("[Verbose] Service %s: - Activator Forwarding Duration is %fs\n", measurement.ServiceName, measurement.ActivatorForwarding)
+		}
+		m.Lock()
+		result.Measurement = append(result.Measurement, measurement)
+		m.Unlock()
+	})
+
+	sort.Slice(result.Measurement, func(i, j int) bool {
+		if result.Measurement[i].ServiceNamespace != result.Measurement[j].ServiceNamespace {
+			return result.Measurement[i].ServiceNamespace < result.Measurement[j].ServiceNamespace
+		}
+		return result.Measurement[i].ServiceName < result.Measurement[j].ServiceName
+	})
+
+	result.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	rows := [][]string{{"svc_name", "svc_namespace", "time_to_first_byte", "pod_created", "pod_scheduled",
+		"queue-proxy_started", "user-container_started", "activator_forwarding"}}
+	for _, r := range result.Measurement {
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace,
+			fmt.Sprintf("%f", r.TimeToFirstByte),
+			fmt.Sprintf("%f", r.PodCreated),
+			fmt.Sprintf("%f", r.PodScheduled),
+			fmt.Sprintf("%f", r.QueueProxyStarted),
+			fmt.Sprintf("%f", r.UserContainerStarted),
+			fmt.Sprintf("%f", r.ActivatorForwarding),
+		})
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Cold Start Measurement:\n")
+	fmt.Printf("Total: %d | Measured: %d Failed: %d\n", len(objs), len(result.Measurement), len(objs)-len(result.Measurement))
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    ColdStartOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// runColdStart waits for the service to be scaled to zero, sends a single request and breaks the
+// time to first byte down with the timestamps of the Pod which was started for the request
+func runColdStart(ctx context.Context, params *pkg.PerfParams, inputs pkg.ColdStartArgs, namespace string, svc *servingv1.Service) (pkg.ColdStartMeasurement, error) {
+	measurement := pkg.ColdStartMeasurement{ServiceName: svc.Name, ServiceNamespace: namespace}
+	selector := labels.SelectorFromSet(labels.Set{
+		serving.ServiceLabelKey: svc.Name,
+	}).String()
+
+	fmt.Printf("Waiting for service %s/%s to be scaled to zero\n", namespace, svc.Name)
+	err := wait.PollImmediate(2*time.Second, inputs.ScaleToZeroTimeout, func() (bool, error) {
+		podList, err := params.ClientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+		return len(podList.Items) == 0, nil
+	})
+	if err != nil {
+		return measurement, fmt.Errorf("service is not scaled to zero: %w", err)
+	}
+
+	endpoint, err := ResolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
+	if err != nil {
+		return measurement, fmt.Errorf("failed to get the cluster endpoint: %w", err)
+	}
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return measurement, err
+	}
+	if svc.Status.URL != nil {
+		req.Host = svc.Status.URL.URL().Host
+	}
+	var firstByte time.Time
+	trace := &httptrace.ClientTrace{
+		GotFirstResponseByte: func() {
+			firstByte = time.Now()
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
+
+	client := http.Client{Timeout: inputs.RequestTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return measurement, fmt.Errorf("failed to send request to %s: %w", endpoint, err)
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return measurement, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	measurement.TimeToFirstByte = firstByte.Sub(start).Seconds()
+
+	podList, err := params.ClientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil || len(podList.Items) == 0 {
+		fmt.Printf("failed to find the Pod started for service %s/%s, only time to first byte is measured\n", namespace, svc.Name)
+		return measurement, nil
+	}
+	pod := podList.Items[0]
+	for _, p := range podList.Items {
+		if p.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			pod = p
+		}
+	}
+
+	podCreatedTime := pod.GetCreationTimestamp().Time
+	measurement.PodCreated = nonNegativeSeconds(podCreatedTime.Sub(start))
+	if _, cdt := measure.GetPodCondition(&pod.Status, corev1.PodScheduled); cdt != nil {
+		measurement.PodScheduled = nonNegativeSeconds(cdt.LastTransitionTime.Sub(podCreatedTime))
+	}
+	if status, found := measure.GetContainerStatus(pod.Status.ContainerStatuses, measure.QueueProxyContainerName); found && status.State.Running != nil {
+		measurement.QueueProxyStarted = nonNegativeSeconds(status.State.Running.StartedAt.Sub(podCreatedTime))
+	}
+	userContainer := measure.UserContainerName(svc.Spec.Template.Spec.Containers)
+	if status, found := measure.GetContainerStatus(pod.Status.ContainerStatuses, userContainer); found && status.State.Running != nil {
+		measurement.UserContainerStarted = nonNegativeSeconds(status.State.Running.StartedAt.Sub(podCreatedTime))
+	}
+	if _, cdt := measure.GetPodCondition(&pod.Status, corev1.PodReady); cdt != nil {
+		measurement.ActivatorForwarding = nonNegativeSeconds(firstByte.Sub(cdt.LastTransitionTime.Time))
+	}
+	return measurement, nil
+}
+
+// nonNegativeSeconds returns d in seconds. The Kubernetes timestamps only have a precision of seconds,
+// so durations between them and local timestamps can be slightly negative and are reported as zero.
+func nonNegativeSeconds(d time.Duration) float64 {
+	if d < 0 {
+		return 0
+	}
+	return d.Seconds()
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestNewServiceColdStartCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for service coldstart", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		cmd := NewServiceColdStartCommand(p)
+		_, err := testutil.ExecuteCommand(cmd)
+		assert.ErrorContains(t, err, "'service coldstart' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(cmd, "--svc-prefix", "ksvc", "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "no service found to measure")
+	})
+
+	t.Run("measure cold start as expected", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		// the Pod is started when the request arrives like the activator would do
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := metav1.Now()
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "ksvc-1-pod",
+					Namespace:         "ns-1",
+					Labels:            map[string]string{serving.ServiceLabelKey: "ksvc-1"},
+					CreationTimestamp: now,
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: now},
+						{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: now},
+					},
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "queue-proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: now}}},
+						{Name: "user-container", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: now}}},
+					},
+				},
+			}
+			client.CoreV1().Pods("ns-1").Create(context.TODO(), pod, metav1.CreateOptions{})
+			w.Write([]byte("hello"))
+		}))
+		defer server.Close()
+
+		url, err := apis.ParseURL(server.URL)
+		assert.NilError(t, err)
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+		svc.Status.URL = url
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{*svc}}, nil
+		})
+
+		outputDir := t.TempDir()
+		cmd := NewServiceColdStartCommand(p)
+		_, err = testutil.ExecuteCommand(cmd, "--svc-prefix", "ksvc", "--namespace", "ns-1", "--resolvable", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+ColdStartOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+	})
+}
+
+func TestRunColdStart(t *testing.T) {
+	t.Run("service not scaled to zero", func(t *testing.T) {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "ksvc-1-pod",
+			Namespace: "ns-1",
+			Labels:    map[string]string{serving.ServiceLabelKey: "ksvc-1"},
+		}}
+		p := &pkg.PerfParams{ClientSet: k8sfake.NewSimpleClientset(pod)}
+		inputs := pkg.ColdStartArgs{ScaleToZeroTimeout: time.Millisecond}
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+
+		_, err := runColdStart(context.TODO(), p, inputs, "ns-1", svc)
+		assert.ErrorContains(t, err, "service is not scaled to zero")
+	})
+}
+
+func TestNonNegativeSeconds(t *testing.T) {
+	assert.Equal(t, 0.0, nonNegativeSeconds(-time.Second))
+	assert.Equal(t, 1.5, nonNegativeSeconds(1500*time.Millisecond))
+}
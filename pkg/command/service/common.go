@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -88,59 +89,42 @@ func GetNamespaces(ctx context.Context, params *pkg.PerfParams, namespace, names
 	return nsNameList, nil
 }
 
-// Get Knative Serving and Eventing version
-// Returns a map like {"eventing":"0.20.0", "serving":"0.20.0"}
-func GetKnativeVersion(p *pkg.PerfParams) map[string]string {
-	knativeVersion := make(map[string]string)
-	knativeServingNs, err := p.ClientSet.CoreV1().Namespaces().Get(context.TODO(), "knative-serving", metav1.GetOptions{})
-	if err != nil {
-		fmt.Printf("failed to get Knative Serving version: %s\n", err)
-		knativeVersion["serving"] = "Unknown"
-	} else {
-		servingVersion := knativeServingNs.Labels["serving.knative.dev/release"]
-		servingVersion = strings.Trim(servingVersion, "v")
-		knativeVersion["serving"] = servingVersion
+// compileSvcRegex compiles the --svc-regex expression, an empty expression matches every name and is returned as nil
+func compileSvcRegex(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
 	}
-
-	knativeEventingNs, err := p.ClientSet.CoreV1().Namespaces().Get(context.TODO(), "knative-eventing", metav1.GetOptions{})
+	re, err := regexp.Compile(expr)
 	if err != nil {
-		fmt.Printf("failed to get Knative Eventing version: %s\n", err)
-		knativeVersion["eventing"] = "Unknown"
-	} else {
-		eventingVersion := knativeEventingNs.Labels["eventing.knative.dev/release"]
-		eventingVersion = strings.Trim(eventingVersion, "v")
-		knativeVersion["eventing"] = eventingVersion
+		return nil, fmt.Errorf("invalid --svc-regex %q: %s", expr, err)
 	}
-	return knativeVersion
+	return re, nil
 }
 
-// Get Knative ingress controller solution and version
-// Returns a map like {"ingressController":"Istio", "version":"1.7.3"}
-// For now, kperf only support Istio.
-// 1) If it is using Istio, get version from istio deployment labels in istio-system.
-// 2) If it is using other options, put version as "Unknown".
-func GetIngressController(p *pkg.PerfParams) map[string]string {
-	ingressController := make(map[string]string)
-	knativeServingConfig, err := p.ClientSet.CoreV1().ConfigMaps("knative-serving").Get(context.TODO(), "config-network", metav1.GetOptions{})
-	if err != nil {
-		fmt.Printf("failed to get Knative ingress controller info: %s\n", err)
-		ingressController["ingressController"] = "Unknown"
-		ingressController["version"] = "Unknown"
-		return ingressController
+// matchSvcName returns true if the service name has one of the prefixes and matches the expression if there is one
+func matchSvcName(name string, prefixes []string, re *regexp.Regexp) bool {
+	if re != nil && !re.MatchString(name) {
+		return false
 	}
-	ingressClass := knativeServingConfig.Data["ingress.class"]
-	if strings.Contains(ingressClass, "istio") {
-		ingressController["ingressController"] = "Istio"
-		istioVersion, err := p.ClientSet.CoreV1().ConfigMaps("istio-system").Get(context.TODO(), "istio", metav1.GetOptions{})
-		if err != nil {
-			fmt.Printf("failed to get Istio version: %s\n", err)
-			ingressController["version"] = "Unknown"
-			return ingressController
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
 		}
-		ingressController["version"] = istioVersion.Labels["operator.istio.io/version"]
-		return ingressController
 	}
-	ingressController["ingressController"] = "Unknown"
-	ingressController["version"] = "Unknown"
-	return ingressController
+	return false
+}
+
+// splitSvcPrefixes splits a comma separated list of service name prefixes, an empty list is a single empty prefix
+// which matches every name
+func splitSvcPrefixes(prefixes string) []string {
+	var split []string
+	for _, prefix := range strings.Split(prefixes, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			split = append(split, prefix)
+		}
+	}
+	if len(split) == 0 {
+		return []string{""}
+	}
+	return split
 }
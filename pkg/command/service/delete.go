@@ -0,0 +1,374 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/util/wait"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+)
+
+const DeleteOutputFilename = "ksvc_delete_time"
+
+// deleteKinds are the kinds of the resources of a deleted service with their deletion durations and the number of
+// them which existed before the deletion, in the order they are reported, followed by the total
+var deleteKinds = []struct {
+	name     string
+	duration func(m pkg.DeleteMeasurement) float64
+	existing func(m pkg.DeleteMeasurement) int
+}{
+	{"Service", func(m pkg.DeleteMeasurement) float64 { return m.ServiceDeleted }, existing("Service")},
+	{"Revision", func(m pkg.DeleteMeasurement) float64 { return m.RevisionDeleted }, existing("Revision")},
+	{"Deployment", func(m pkg.DeleteMeasurement) float64 { return m.DeploymentDeleted }, existing("Deployment")},
+	{"KPA", func(m pkg.DeleteMeasurement) float64 { return m.KPADeleted }, existing("KPA")},
+	{"SKS", func(m pkg.DeleteMeasurement) float64 { return m.SKSDeleted }, existing("SKS")},
+	{"Ingress", func(m pkg.DeleteMeasurement) float64 { return m.IngressDeleted }, existing("Ingress")},
+	// the total is measured for every deleted service
+	{"Total", func(m pkg.DeleteMeasurement) float64 { return m.Total }, func(m pkg.DeleteMeasurement) int { return 1 }},
+}
+
+// existing returns the number of resources of the kind of a deleted service which existed before the deletion
+func existing(kind string) func(m pkg.DeleteMeasurement) int {
+	return func(m pkg.DeleteMeasurement) int {
+		return m.Existing[kind]
+	}
+}
+
+func NewServiceDeleteMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	deleteArgs := pkg.DeleteMeasureArgs{}
+	serviceDeleteMeasureCommand := &cobra.Command{
+		Use:   "delete-measure",
+		Short: "Delete Knative service and measure the deletion time",
+		Long: `Delete Knative services and measure the time until their resources are gone
+
+The deletion is broken down into the time until the Service, its Revisions, their Deployments, KPAs and SKSs, and
+the Ingress of the Service are gone, i.e. their finalizers are cleared, all relative to the Delete call of
+the Service. The resources are polled every --poll-interval, so the durations are up to that interval late, it is
+reported as the resolution of the measurement.
+
+For example:
+# To measure the deletion of the Knative Services with prefix svc in namespace ns
+kperf service delete-measure --svc-prefix svc --namespace ns
+
+# To measure the deletion of 10 of them, 5 at a time
+kperf service delete-measure --svc-prefix svc --namespace ns --count 10 --concurrency 5
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'service delete-measure' requires flag(s)")
+			}
+			if deleteArgs.Count < 0 {
+				return fmt.Errorf("--count must not be negative, given %d", deleteArgs.Count)
+			}
+			if deleteArgs.PollInterval <= 0 {
+				return fmt.Errorf("--poll-interval must be positive, given %s", deleteArgs.PollInterval)
+			}
+			return pkg.ValidateRunID(deleteArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureDelete(p, deleteArgs)
+		},
+	}
+
+	serviceDeleteMeasureCommand.Flags().StringVarP(&deleteArgs.Namespace, "namespace", "", "", "Service namespace")
+	serviceDeleteMeasureCommand.Flags().StringVarP(&deleteArgs.SvcPrefix, "svc-prefix", "", "", "Service name prefix")
+	serviceDeleteMeasureCommand.Flags().BoolVarP(&deleteArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	serviceDeleteMeasureCommand.Flags().StringVarP(&deleteArgs.NamespaceRange, "namespace-range", "", "", "Service namespace range")
+	serviceDeleteMeasureCommand.Flags().StringVarP(&deleteArgs.NamespacePrefix, "namespace-prefix", "", "", "Service namespace prefix")
+	serviceDeleteMeasureCommand.Flags().IntVarP(&deleteArgs.Count, "count", "", 0, "Number of services to delete, 0 to delete all the services found")
+	serviceDeleteMeasureCommand.Flags().IntVarP(&deleteArgs.Concurrency, "concurrency", "c", 10, "Number of workers to do measurement job")
+	serviceDeleteMeasureCommand.Flags().StringVarP(&deleteArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(serviceDeleteMeasureCommand.Flags(), &deleteArgs.OutputFormats)
+	serviceDeleteMeasureCommand.Flags().StringVarP(&deleteArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	serviceDeleteMeasureCommand.Flags().DurationVarP(&deleteArgs.Timeout, "timeout", "", 5*time.Minute, "Duration to wait for the resources of a Knative Service to be gone")
+	serviceDeleteMeasureCommand.Flags().DurationVarP(&deleteArgs.PollInterval, "poll-interval", "", updatePollInterval, "Interval the resources are polled in until they are gone, the resolution of the deletion durations")
+	return serviceDeleteMeasureCommand
+}
+
+// MeasureDelete used to delete Knative Services and measure the time until their resources are gone
+func MeasureDelete(params *pkg.PerfParams, inputs pkg.DeleteMeasureArgs) error {
+	ctx := context.Background()
+	nsNameList, err := GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	autoscalingClient, err := params.NewAutoscalingClient()
+	if err != nil {
+		return err
+	}
+	networkingClient, err := params.NewNetworkingClient()
+	if err != nil {
+		return err
+	}
+	objs := getServices(ctx, ksvcClient, nsNameList, inputs.SvcPrefix)
+	if len(objs) == 0 {
+		return fmt.Errorf("no service found to delete")
+	}
+	if inputs.Count > 0 && inputs.Count < len(objs) {
+		objs = objs[:inputs.Count]
+	}
+
+	var clk clock.PassiveClock = clock.RealClock{}
+	if params.Clock != nil {
+		clk = params.Clock
+	}
+
+	fmt.Printf("Deleting %d service(s)\n", len(objs))
+	result := pkg.DeleteResult{PollInterval: inputs.PollInterval.Seconds()}
+	var m sync.Mutex
+	pool.ForEach(ctx, inputs.Concurrency, len(objs), func(ctx context.Context, i int) {
+		obj := objs[i]
+		measurement, err := runDelete(ctx, params, clk, ksvcClient, autoscalingClient, networkingClient, inputs, obj.Namespace, obj.Service)
+		if err != nil {
+			fmt.Printf("failed to measure deletion of service %s/%s and skip: %s\n", obj.Namespace, obj.Service.Name, err)
+			return
+		}
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Service %s: Deletion Duration is %fs\n", measurement.ServiceName, measurement.Total)
+			for _, kind := range deleteKinds[:len(deleteKinds)-1] {
+				fmt.Printf("[Verbose] Service %s: - %s Deleted Duration is %fs\n", measurement.ServiceName, kind.name, kind.duration(measurement))
+			}
+		}
+		m.Lock()
+		result.Measurement = append(result.Measurement, measurement)
+		m.Unlock()
+	})
+
+	sort.Slice(result.Measurement, func(i, j int) bool {
+		if result.Measurement[i].ServiceNamespace != result.Measurement[j].ServiceNamespace {
+			return result.Measurement[i].ServiceNamespace < result.Measurement[j].ServiceNamespace
+		}
+		return result.Measurement[i].ServiceName < result.Measurement[j].ServiceName
+	})
+	result.Resources = deleteResourceStats(result.Measurement)
+
+	result.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	rows := [][]string{{"svc_name", "svc_namespace", "revisions", "service_deleted", "revision_deleted",
+		"deployment_deleted", "kpa_deleted", "sks_deleted", "ingress_deleted", "total"}}
+	for _, r := range result.Measurement {
+		row := []string{r.ServiceName, r.ServiceNamespace, strings.Join(r.Revisions, " ")}
+		for _, kind := range deleteKinds {
+			row = append(row, fmt.Sprintf("%f", kind.duration(r)))
+		}
+		rows = append(rows, row)
+	}
+
+	writeDeleteResult(os.Stdout, len(objs), result)
+
+	current := clk.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    DeleteOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// deletedResource is a resource of a deleted service, get returns the NotFound error once it is gone
+type deletedResource struct {
+	kind    string
+	name    string
+	get     func(ctx context.Context) error
+	deleted *float64
+}
+
+// runDelete deletes the service and polls it, the resources of its revisions and its ingress every poll interval until
+// they are gone. The revisions are the ones labeled with the service. The resources which don't exist before the
+// deletion, e.g. the deployment of a revision which failed, are skipped.
+func runDelete(ctx context.Context, params *pkg.PerfParams, clk clock.PassiveClock, ksvcClient servingv1client.ServingV1Interface,
+	autoscalingClient autoscalingv1alpha1.AutoscalingV1alpha1Interface, networkingClient networkingv1alpha1.NetworkingV1alpha1Interface,
+	inputs pkg.DeleteMeasureArgs, namespace string, svc *servingv1.Service) (pkg.DeleteMeasurement, error) {
+	measurement := pkg.DeleteMeasurement{
+		ServiceName:      svc.Name,
+		ServiceNamespace: namespace,
+		Existing:         map[string]int{},
+	}
+	revisions, err := ksvcClient.Revisions(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{serving.ServiceLabelKey: svc.Name}.String(),
+	})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	resources := []deletedResource{{
+		kind: "Service", name: svc.Name, deleted: &measurement.ServiceDeleted,
+		get: func(ctx context.Context) error {
+			_, err := ksvcClient.Services(namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+			return err
+		},
+	}}
+	for _, rev := range revisions.Items {
+		revision := rev.Name
+		measurement.Revisions = append(measurement.Revisions, revision)
+		resources = append(resources, deletedResource{
+			kind: "Revision", name: revision, deleted: &measurement.RevisionDeleted,
+			get: func(ctx context.Context) error {
+				_, err := ksvcClient.Revisions(namespace).Get(ctx, revision, metav1.GetOptions{})
+				return err
+			},
+		}, deletedResource{
+			kind: "Deployment", name: revision + "-deployment", deleted: &measurement.DeploymentDeleted,
+			get: func(ctx context.Context) error {
+				_, err := params.ClientSet.AppsV1().Deployments(namespace).Get(ctx, revision+"-deployment", metav1.GetOptions{})
+				return err
+			},
+		}, deletedResource{
+			kind: "KPA", name: revision, deleted: &measurement.KPADeleted,
+			get: func(ctx context.Context) error {
+				_, err := autoscalingClient.PodAutoscalers(namespace).Get(ctx, revision, metav1.GetOptions{})
+				return err
+			},
+		}, deletedResource{
+			kind: "SKS", name: revision, deleted: &measurement.SKSDeleted,
+			get: func(ctx context.Context) error {
+				_, err := networkingClient.ServerlessServices(namespace).Get(ctx, revision, metav1.GetOptions{})
+				return err
+			},
+		})
+	}
+	sort.Strings(measurement.Revisions)
+	resources = append(resources, deletedResource{
+		kind: "Ingress", name: svc.Name, deleted: &measurement.IngressDeleted,
+		get: func(ctx context.Context) error {
+			_, err := networkingClient.Ingresses(namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+			return err
+		},
+	})
+
+	pending := []deletedResource{}
+	for _, r := range resources {
+		err := r.get(ctx)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return measurement, fmt.Errorf("failed to get %s %s: %w", r.kind, r.name, err)
+		}
+		measurement.Existing[r.kind]++
+		pending = append(pending, r)
+	}
+
+	start := clk.Now()
+	if err := ksvcClient.Services(namespace).Delete(ctx, svc.Name, metav1.DeleteOptions{}); err != nil {
+		return measurement, fmt.Errorf("failed to delete service: %w", err)
+	}
+	err = wait.PollImmediate(inputs.PollInterval, inputs.Timeout, func() (bool, error) {
+		left := []deletedResource{}
+		for _, r := range pending {
+			err := r.get(ctx)
+			if apierrors.IsNotFound(err) {
+				// the duration of the kind is the one of the last resource of the kind gone
+				*r.deleted = clk.Since(start).Seconds()
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			left = append(left, r)
+		}
+		pending = left
+		return len(pending) == 0, nil
+	})
+	if err != nil {
+		names := make([]string, 0, len(pending))
+		for _, r := range pending {
+			names = append(names, r.kind+" "+r.name)
+		}
+		return measurement, fmt.Errorf("%s not deleted: %w", strings.Join(names, ", "), err)
+	}
+	measurement.Total = clk.Since(start).Seconds()
+	return measurement, nil
+}
+
+// deleteResourceStats returns the average, the percentiles and the maximum of the deletion durations of every kind
+// of resource and of the totals, the services without resources of a kind before the deletion are left out of it
+func deleteResourceStats(measurements []pkg.DeleteMeasurement) []pkg.DeleteResourceStats {
+	resources := make([]pkg.DeleteResourceStats, 0, len(deleteKinds))
+	for _, kind := range deleteKinds {
+		s := pkg.DeleteResourceStats{Resource: kind.name}
+		durations := []float64{}
+		for _, m := range measurements {
+			if n := kind.existing(m); n > 0 {
+				s.Deleted += n
+				durations = append(durations, kind.duration(m))
+			}
+		}
+		if len(durations) > 0 {
+			s.Average, _ = stats.Mean(durations)
+			s.P50, _ = stats.Percentile(durations, 50)
+			s.P95, _ = stats.Percentile(durations, 95)
+			s.P99, _ = stats.Percentile(durations, 99)
+			s.Max, _ = stats.Max(durations)
+		}
+		resources = append(resources, s)
+	}
+	return resources
+}
+
+// writeDeleteResult writes the number of measured and failed services of the total and the statistics of the
+// deletion durations with their resolution
+func writeDeleteResult(out io.Writer, total int, result pkg.DeleteResult) {
+	fmt.Fprintf(out, "-------- Measurement --------\n")
+	fmt.Fprintf(out, "Delete Measurement:\n")
+	fmt.Fprintf(out, "Total: %d | Measured: %d Failed: %d | Resolution: %fs\n", total, len(result.Measurement),
+		total-len(result.Measurement), result.PollInterval)
+	writeDeleteResources(out, result.Resources)
+}
+
+// writeDeleteResources writes the number of deleted resources of every kind with the average, the percentiles and
+// the maximum of their deletion durations
+func writeDeleteResources(out io.Writer, resources []pkg.DeleteResourceStats) {
+	for _, r := range resources {
+		if r.Deleted == 0 {
+			fmt.Fprintf(out, "%s: Deleted: 0\n", r.Resource)
+			continue
+		}
+		fmt.Fprintf(out, "%s: Deleted: %d | Average: %fs Percentile50: %fs Percentile95: %fs Percentile99: %fs Max: %fs\n",
+			r.Resource, r.Deleted, r.Average, r.P50, r.P95, r.P99, r.Max)
+	}
+}
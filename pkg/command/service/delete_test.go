@@ -0,0 +1,239 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package service
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	networkingv1alpha1api "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	networkingv1alpha1fake "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	autoscalingv1alpha1api "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
+	autoscalingv1alpha1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1/fake"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+// newDeleteTestParams returns the params with the service ksvc-1 in namespace ns-1 and the resources of its revisions
+// ksvc-1-00001 and ksvc-1-00002. The Knative resources are gone as soon as the service is deleted, except the kinds in
+// kept.
+func newDeleteTestParams(kept ...string) (*pkg.PerfParams, *bool) {
+	client := k8sfake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001-deployment", Namespace: "ns-1"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00002-deployment", Namespace: "ns-1"}},
+	)
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	fakeAutoscaling := &autoscalingv1alpha1fake.FakeAutoscalingV1alpha1{Fake: &client.Fake}
+	fakeNetworking := &networkingv1alpha1fake.FakeNetworkingV1alpha1{Fake: &client.Fake}
+
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	svc.Status.LatestCreatedRevisionName = "ksvc-1-00002"
+	deleted := false
+	client.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &servingv1.ServiceList{Items: []servingv1.Service{*svc}}, nil
+	})
+	client.PrependReactor("list", "revisions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		revisions := &servingv1.RevisionList{}
+		if action.(clienttesting.ListAction).GetListRestrictions().Labels.String() != "serving.knative.dev/service=ksvc-1" {
+			return true, revisions, nil
+		}
+		for _, name := range []string{"ksvc-1-00002", "ksvc-1-00001"} {
+			revisions.Items = append(revisions.Items, servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns-1",
+				Labels: map[string]string{"serving.knative.dev/service": "ksvc-1"}}})
+		}
+		return true, revisions, nil
+	})
+	client.PrependReactor("delete", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		deleted = true
+		deployment := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+		for _, name := range []string{"ksvc-1-00001-deployment", "ksvc-1-00002-deployment"} {
+			if err := client.Tracker().Delete(deployment, "ns-1", name); err != nil && !apierrors.IsNotFound(err) {
+				return true, nil, err
+			}
+		}
+		return true, nil, nil
+	})
+	for resource, obj := range map[string]runtime.Object{
+		"services":           svc,
+		"revisions":          &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001", Namespace: "ns-1"}},
+		"podautoscalers":     &autoscalingv1alpha1api.PodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001", Namespace: "ns-1"}},
+		"serverlessservices": &networkingv1alpha1api.ServerlessService{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001", Namespace: "ns-1"}},
+		"ingresses":          &networkingv1alpha1api.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}},
+	} {
+		resource, obj := resource, obj
+		gone := true
+		for _, k := range kept {
+			if k == resource {
+				gone = false
+			}
+		}
+		client.PrependReactor("get", resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if deleted && gone {
+				return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: resource}, action.(clienttesting.GetAction).GetName())
+			}
+			return true, obj, nil
+		})
+	}
+
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+		NewAutoscalingClient: func() (autoscalingv1alpha1.AutoscalingV1alpha1Interface, error) {
+			return fakeAutoscaling, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return fakeNetworking, nil
+		},
+	}, &deleted
+}
+
+func TestNewServiceDeleteMeasureCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for service delete-measure", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return &servingv1fake.FakeServingV1{Fake: &client.Fake}, nil
+			},
+			NewAutoscalingClient: func() (autoscalingv1alpha1.AutoscalingV1alpha1Interface, error) {
+				return &autoscalingv1alpha1fake.FakeAutoscalingV1alpha1{Fake: &client.Fake}, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return &networkingv1alpha1fake.FakeNetworkingV1alpha1{Fake: &client.Fake}, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceDeleteMeasureCommand(p))
+		assert.ErrorContains(t, err, "'service delete-measure' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewServiceDeleteMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--count", "-1")
+		assert.ErrorContains(t, err, "--count must not be negative, given -1")
+
+		_, err = testutil.ExecuteCommand(NewServiceDeleteMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--poll-interval", "0s")
+		assert.ErrorContains(t, err, "--poll-interval must be positive, given 0s")
+
+		_, err = testutil.ExecuteCommand(NewServiceDeleteMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "no service found to delete")
+	})
+
+	t.Run("measure deletion as expected", func(t *testing.T) {
+		p, deleted := newDeleteTestParams()
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceDeleteMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+		assert.Assert(t, *deleted)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+DeleteOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+	})
+}
+
+func TestRunDelete(t *testing.T) {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	svc.Status.LatestCreatedRevisionName = "ksvc-1-00002"
+
+	newClients := func(p *pkg.PerfParams) (servingv1client.ServingV1Interface, autoscalingv1alpha1.AutoscalingV1alpha1Interface, networkingv1alpha1.NetworkingV1alpha1Interface) {
+		ksvcClient, _ := p.NewServingClient()
+		autoscalingClient, _ := p.NewAutoscalingClient()
+		networkingClient, _ := p.NewNetworkingClient()
+		return ksvcClient, autoscalingClient, networkingClient
+	}
+
+	t.Run("all the resources are gone", func(t *testing.T) {
+		p, _ := newDeleteTestParams()
+		// the resources are gone 2s after the Delete call
+		clk := clock.NewFakeClock(time.Now())
+		p.ClientSet.(*k8sfake.Clientset).PrependReactor("delete", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			clk.Step(2 * time.Second)
+			return false, nil, nil
+		})
+		ksvcClient, autoscalingClient, networkingClient := newClients(p)
+		measurement, err := runDelete(context.Background(), p, clk, ksvcClient, autoscalingClient, networkingClient,
+			pkg.DeleteMeasureArgs{Timeout: time.Second, PollInterval: 10 * time.Millisecond}, "ns-1", svc)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"ksvc-1-00001", "ksvc-1-00002"}, measurement.Revisions)
+		assert.DeepEqual(t, map[string]int{"Service": 1, "Revision": 2, "Deployment": 2, "KPA": 2, "SKS": 2, "Ingress": 1}, measurement.Existing)
+		for _, kind := range deleteKinds {
+			assert.Equal(t, 2.0, kind.duration(measurement), "%s deleted duration", kind.name)
+		}
+	})
+
+	t.Run("the deployment of a revision doesn't exist", func(t *testing.T) {
+		p, _ := newDeleteTestParams()
+		deployment := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+		assert.NilError(t, p.ClientSet.(*k8sfake.Clientset).Tracker().Delete(deployment, "ns-1", "ksvc-1-00001-deployment"))
+		ksvcClient, autoscalingClient, networkingClient := newClients(p)
+		measurement, err := runDelete(context.Background(), p, clock.RealClock{}, ksvcClient, autoscalingClient, networkingClient,
+			pkg.DeleteMeasureArgs{Timeout: time.Second, PollInterval: 10 * time.Millisecond}, "ns-1", svc)
+		assert.NilError(t, err)
+		assert.Equal(t, 1, measurement.Existing["Deployment"])
+		assert.Equal(t, 2, measurement.Existing["Revision"])
+	})
+
+	t.Run("the ingress is not gone", func(t *testing.T) {
+		p, _ := newDeleteTestParams("ingresses")
+		ksvcClient, autoscalingClient, networkingClient := newClients(p)
+		measurement, err := runDelete(context.Background(), p, clock.RealClock{}, ksvcClient, autoscalingClient, networkingClient,
+			pkg.DeleteMeasureArgs{Timeout: 10 * time.Millisecond, PollInterval: time.Millisecond}, "ns-1", svc)
+		assert.ErrorContains(t, err, "Ingress ksvc-1 not deleted")
+		assert.Assert(t, measurement.ServiceDeleted > 0)
+		assert.Equal(t, 0.0, measurement.IngressDeleted)
+		assert.Equal(t, 0.0, measurement.Total)
+	})
+}
+
+func TestDeleteResourceStats(t *testing.T) {
+	measurements := []pkg.DeleteMeasurement{
+		{Existing: map[string]int{"Service": 1, "Revision": 2, "Ingress": 1}, ServiceDeleted: 1, RevisionDeleted: 2, IngressDeleted: 3, Total: 3},
+		{Existing: map[string]int{"Service": 1, "Revision": 1}, ServiceDeleted: 3, RevisionDeleted: 4, Total: 4},
+		// the service was gone at the first poll
+		{Existing: map[string]int{"Service": 1}},
+	}
+	resources := deleteResourceStats(measurements)
+	assert.Equal(t, len(deleteKinds), len(resources))
+	assert.DeepEqual(t, pkg.DeleteResourceStats{Resource: "Service", Deleted: 3, Average: 4.0 / 3, P50: 0.5, P95: 2, P99: 2, Max: 3}, resources[0])
+	assert.Equal(t, 3, resources[1].Deleted)
+	// the deployments didn't exist and are left out
+	assert.DeepEqual(t, pkg.DeleteResourceStats{Resource: "Deployment"}, resources[2])
+	assert.DeepEqual(t, pkg.DeleteResourceStats{Resource: "Ingress", Deleted: 1, Average: 3, P50: 3, P95: 3, P99: 3, Max: 3}, resources[5])
+
+	var out bytes.Buffer
+	writeDeleteResult(&out, 4, pkg.DeleteResult{PollInterval: 1, Resources: resources, Measurement: measurements})
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Total: 4 | Measured: 3 Failed: 1 | Resolution: 1.000000s\n")), out.String())
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Service: Deleted: 3 | Average: 1.333333s Percentile50: 0.500000s Percentile95: 2.000000s Percentile99: 2.000000s Max: 3.000000s\n")), out.String())
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Deployment: Deleted: 0\n")), out.String())
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"knative.dev/kperf/pkg"
+)
+
+const (
+	disruptionEviction   = "eviction"
+	disruptionPreemption = "preemption"
+	disruptionDeletion   = "deletion"
+
+	// podDisruptionTarget is the condition the control plane adds to a pod before it disrupts it, since Kubernetes 1.26
+	podDisruptionTarget corev1.PodConditionType = "DisruptionTarget"
+)
+
+// podDisruptions tracks the pods of a service which are terminated during the load
+type podDisruptions struct {
+	terminating map[string]bool
+}
+
+// newPodDisruptions ignores the pods which are already terminating when the load starts
+func newPodDisruptions(pods []corev1.Pod) *podDisruptions {
+	d := &podDisruptions{terminating: map[string]bool{}}
+	for i := range pods {
+		if podTerminating(&pods[i]) {
+			d.terminating[pods[i].Name] = true
+		}
+	}
+	return d
+}
+
+// observe returns the disruption of the pod of a watch event, or false if the pod didn't start terminating with the
+// event. Pods deleted without a grace period are only seen in the Deleted event.
+func (d *podDisruptions) observe(event watch.Event) (pkg.Disruption, bool) {
+	pod, ok := event.Object.(*corev1.Pod)
+	if !ok || d.terminating[pod.Name] {
+		return pkg.Disruption{}, false
+	}
+	if event.Type != watch.Deleted && !podTerminating(pod) {
+		return pkg.Disruption{}, false
+	}
+	d.terminating[pod.Name] = true
+	return pkg.Disruption{Pod: pod.Name, Cause: disruptionCause(pod)}, true
+}
+
+// podTerminating returns whether the pod is deleted or was evicted by the kubelet, which keeps the failed pod
+func podTerminating(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp != nil || (pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "Evicted")
+}
+
+// disruptionCause classifies why a pod is terminated by the DisruptionTarget condition or the reason of the pod
+// status, all other terminations like scaling down are deletions
+func disruptionCause(pod *corev1.Pod) string {
+	for _, c := range pod.Status.Conditions {
+		if c.Type != podDisruptionTarget || c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Reason {
+		case "PreemptionByScheduler", "PreemptionByKubeScheduler":
+			return disruptionPreemption
+		case "EvictionByEvictionAPI", "TerminationByKubelet", "DeletionByTaintManager":
+			return disruptionEviction
+		}
+	}
+	switch pod.Status.Reason {
+	case "Evicted":
+		return disruptionEviction
+	case "Preempting":
+		return disruptionPreemption
+	}
+	return disruptionDeletion
+}
+
+// attributeDisruptionErrors attributes every failed request which finished within the window after a disruption to
+// the latest such disruption, and computes the errors per disruption
+func attributeDisruptionErrors(measurement *pkg.ServiceLoadResult, samples []loadSample, window time.Duration) {
+	for _, s := range samples {
+		if !s.failed {
+			continue
+		}
+		for i := len(measurement.Disruptions) - 1; i >= 0; i-- {
+			d := &measurement.Disruptions[i]
+			if s.offset >= d.Offset && s.offset <= d.Offset+window.Seconds() {
+				d.Errors++
+				measurement.DisruptionErrors++
+				break
+			}
+		}
+	}
+	if len(measurement.Disruptions) > 0 {
+		measurement.ErrorsPerDisruption = float64(measurement.DisruptionErrors) / float64(len(measurement.Disruptions))
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg"
+)
+
+func newDisruptionTestPod(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "ns-1",
+		Labels:    map[string]string{serving.ServiceLabelKey: "ksvc-1"},
+	}}
+}
+
+// evict marks the pod as disrupted by the reason and deleted
+func evict(pod *corev1.Pod, reason string) *corev1.Pod {
+	evicted := pod.DeepCopy()
+	now := metav1.Now()
+	evicted.DeletionTimestamp = &now
+	evicted.Status.Conditions = append(evicted.Status.Conditions, corev1.PodCondition{
+		Type:   podDisruptionTarget,
+		Status: corev1.ConditionTrue,
+		Reason: reason,
+	})
+	return evicted
+}
+
+func TestDisruptionCause(t *testing.T) {
+	pod := newDisruptionTestPod("pod-1")
+	assert.Equal(t, disruptionDeletion, disruptionCause(pod))
+	assert.Equal(t, disruptionEviction, disruptionCause(evict(pod, "EvictionByEvictionAPI")))
+	assert.Equal(t, disruptionPreemption, disruptionCause(evict(pod, "PreemptionByScheduler")))
+
+	kubeletEvicted := pod.DeepCopy()
+	kubeletEvicted.Status.Phase = corev1.PodFailed
+	kubeletEvicted.Status.Reason = "Evicted"
+	assert.Equal(t, disruptionEviction, disruptionCause(kubeletEvicted))
+	assert.Assert(t, podTerminating(kubeletEvicted))
+}
+
+func TestPodDisruptions(t *testing.T) {
+	terminating := evict(newDisruptionTestPod("pod-0"), "EvictionByEvictionAPI")
+	d := newPodDisruptions([]corev1.Pod{*terminating})
+
+	// pods already terminating when the load starts are ignored
+	_, ok := d.observe(watch.Event{Type: watch.Modified, Object: terminating})
+	assert.Assert(t, !ok)
+	_, ok = d.observe(watch.Event{Type: watch.Modified, Object: newDisruptionTestPod("pod-1")})
+	assert.Assert(t, !ok)
+
+	disruption, ok := d.observe(watch.Event{Type: watch.Modified, Object: evict(newDisruptionTestPod("pod-1"), "PreemptionByScheduler")})
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, pkg.Disruption{Pod: "pod-1", Cause: disruptionPreemption}, disruption)
+	// a pod is only disrupted once
+	_, ok = d.observe(watch.Event{Type: watch.Deleted, Object: newDisruptionTestPod("pod-1")})
+	assert.Assert(t, !ok)
+
+	// pods deleted without grace period are only seen in the Deleted event
+	disruption, ok = d.observe(watch.Event{Type: watch.Deleted, Object: newDisruptionTestPod("pod-2")})
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, pkg.Disruption{Pod: "pod-2", Cause: disruptionDeletion}, disruption)
+}
+
+func TestAttributeDisruptionErrors(t *testing.T) {
+	measurement := pkg.ServiceLoadResult{Disruptions: []pkg.Disruption{
+		{Offset: 1, Pod: "pod-1", Cause: disruptionEviction},
+		{Offset: 3, Pod: "pod-2", Cause: disruptionPreemption},
+	}}
+	attributeDisruptionErrors(&measurement, []loadSample{
+		{offset: 0.5, failed: true},
+		{offset: 1.5, failed: true},
+		{offset: 1.6},
+		{offset: 2.9, failed: true},
+		{offset: 3.5, failed: true},
+		{offset: 6, failed: true},
+	}, 2*time.Second)
+	assert.Equal(t, 2, measurement.Disruptions[0].Errors)
+	// the failures after the second disruption are attributed to it, not to the first one
+	assert.Equal(t, 1, measurement.Disruptions[1].Errors)
+	assert.Equal(t, 3, measurement.DisruptionErrors)
+	assert.Equal(t, 1.5, measurement.ErrorsPerDisruption)
+}
+
+func TestRunLoadDisruptions(t *testing.T) {
+	pod := newDisruptionTestPod("ksvc-1-pod")
+	client := k8sfake.NewSimpleClientset(newLoadTestDeployment(1), pod)
+	p := &pkg.PerfParams{ClientSet: client}
+
+	// the pod is evicted with the first request and the requests fail shortly after
+	var once sync.Once
+	var m sync.Mutex
+	var evicted time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			client.CoreV1().Pods("ns-1").Update(context.TODO(), evict(pod, "EvictionByEvictionAPI"), metav1.UpdateOptions{})
+			m.Lock()
+			evicted = time.Now()
+			m.Unlock()
+		})
+		m.Lock()
+		failing := time.Since(evicted) > 20*time.Millisecond
+		m.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	url, err := apis.ParseURL(server.URL)
+	assert.NilError(t, err)
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	svc.Status.URL = url
+	inputs := pkg.LoadArgs{Duration: 200 * time.Millisecond, Connections: 1, RequestTimeout: time.Second,
+		ResolvableDomain: true, DisruptionWindow: 10 * time.Second}
+
+	measurement, err := runLoad(context.TODO(), p, inputs, "ns-1", svc)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(measurement.Disruptions))
+	assert.Equal(t, "ksvc-1-pod", measurement.Disruptions[0].Pod)
+	assert.Equal(t, disruptionEviction, measurement.Disruptions[0].Cause)
+	assert.Check(t, measurement.Errors > 0)
+	assert.Equal(t, measurement.Errors, measurement.DisruptionErrors)
+	assert.Equal(t, float64(measurement.Errors), measurement.ErrorsPerDisruption)
+}
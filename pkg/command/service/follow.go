@@ -0,0 +1,209 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+)
+
+// followBuckets are the upper bounds in seconds of the histogram buckets of the phase durations
+var followBuckets = []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300}
+
+// validateFollow checks that --follow is only combined with the flags which select services as they are created
+func validateFollow(inputs pkg.MeasureArgs) error {
+	if !inputs.Follow {
+		if inputs.MetricsAddr != "" {
+			return errors.New("--metrics-addr requires --follow")
+		}
+		return nil
+	}
+	if inputs.SvcRange != "" || inputs.NamespacePrefix != "" || inputs.NamespaceRange != "" {
+		return errors.New("--follow watches the services in --namespace or in all namespaces, it can't be combined with --range, --namespace-prefix or --namespace-range")
+	}
+	if inputs.Checkpoint != "" || inputs.Sample != "" || inputs.Limit > 0 {
+		return errors.New("--follow can't be combined with --checkpoint, --sample or --limit")
+	}
+	if inputs.Kind != "" && inputs.Kind != measure.KindService {
+		return fmt.Errorf("--follow only supports --kind %s", measure.KindService)
+	}
+	if inputs.ControlPlane.Enabled {
+		return errors.New("--follow can't be combined with --profile-controlplane")
+	}
+	if inputs.CollectEvents {
+		return errors.New("--follow can't be combined with --collect-events")
+	}
+	if inputs.ImagePulls {
+		return errors.New("--follow can't be combined with --image-pulls")
+	}
+	if inputs.ExtraMetricsCmd != "" {
+		return errors.New("--follow writes no report, it can't be combined with --extra-metrics-cmd")
+	}
+	return nil
+}
+
+// FollowServices measures every newly created service matching the selector, prefix and regex as soon as it is
+// ready until kperf is interrupted. The records are written as JSON lines to stdout, everything else to stderr.
+func FollowServices(params *pkg.PerfParams, inputs pkg.MeasureArgs) error {
+	ctx, cancel := utils.InterruptContext(context.Background())
+	defer cancel()
+	return followServices(ctx, params, inputs, os.Stdout, os.Stderr)
+}
+
+func followServices(ctx context.Context, params *pkg.PerfParams, inputs pkg.MeasureArgs, stream, out io.Writer) error {
+	re, err := compileSvcRegex(inputs.SvcRegex)
+	if err != nil {
+		return err
+	}
+	prefixes := splitSvcPrefixes(inputs.SvcPrefix)
+
+	measurer := measure.NewMeasurer(params, out, log.New(out, "", 0))
+	measurer.Concurrency = inputs.Concurrency
+	measurer.Verbose = inputs.Verbose
+	measurer.Retries = inputs.Retries
+	measurer.RetryBackoff = inputs.RetryBackoff
+	measurer.ServiceTimeout = inputs.ServiceTimeout
+	measurer.Stream = stream
+	measurer.Precision = measure.PrecisionUnit(inputs.Precision)
+	measurer.CreatedAfter, err = parseCreatedAfter(inputs.CreatedAfter, inputs.Since, measurer.Clock.Now())
+	if err != nil {
+		return err
+	}
+
+	metrics := newFollowMetrics()
+	if inputs.MetricsAddr != "" {
+		listener, err := net.Listen("tcp", inputs.MetricsAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on metrics address %s: %w", inputs.MetricsAddr, err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics)
+		server := &http.Server{Handler: mux}
+		go server.Serve(listener)
+		defer server.Close()
+		fmt.Fprintf(out, "Serving Prometheus metrics on http://%s/metrics\n", listener.Addr())
+	}
+
+	namespace := inputs.Namespace
+	if namespace == "" {
+		namespace = "all namespaces"
+	}
+	fmt.Fprintf(out, "Following the new services in %s, press Ctrl-C to stop\n", namespace)
+	err = measurer.Follow(ctx, inputs.Namespace, pkg.RunSelector(inputs.Selector, inputs.RunID), func(name string) bool {
+		return matchSvcName(name, prefixes, re)
+	}, metrics.observe)
+	fmt.Fprintf(out, "Measured %d service(s)\n", metrics.total())
+	return err
+}
+
+// phaseHistogram is a Prometheus histogram of the durations of a phase with the followBuckets
+type phaseHistogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// followMetrics exports the durations of the followed services in the Prometheus text format
+type followMetrics struct {
+	lock     sync.Mutex
+	phases   map[string]*phaseHistogram
+	measured map[string]uint64
+}
+
+func newFollowMetrics() *followMetrics {
+	return &followMetrics{phases: map[string]*phaseHistogram{}, measured: map[string]uint64{}}
+}
+
+// observe adds the durations of the phases of a measured service to the histograms
+func (f *followMetrics) observe(record pkg.MeasureRecord) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.measured[record.ServiceNamespace]++
+	for phase, value := range measure.RecordPhases(record) {
+		h, ok := f.phases[phase]
+		if !ok {
+			h = &phaseHistogram{buckets: make([]uint64, len(followBuckets))}
+			f.phases[phase] = h
+		}
+		for i, bound := range followBuckets {
+			if value <= bound {
+				h.buckets[i]++
+			}
+		}
+		h.sum += value
+		h.count++
+	}
+}
+
+func (f *followMetrics) total() uint64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var total uint64
+	for _, count := range f.measured {
+		total += count
+	}
+	return total
+}
+
+func (f *followMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	f.write(w)
+}
+
+// write writes the metrics in the Prometheus text format, sorted by their labels
+func (f *followMetrics) write(w io.Writer) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	fmt.Fprintf(w, "# HELP kperf_ksvc_measured_total Number of the measured Knative Services.\n")
+	fmt.Fprintf(w, "# TYPE kperf_ksvc_measured_total counter\n")
+	namespaces := make([]string, 0, len(f.measured))
+	for ns := range f.measured {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		fmt.Fprintf(w, "kperf_ksvc_measured_total{namespace=%q} %d\n", ns, f.measured[ns])
+	}
+	fmt.Fprintf(w, "# HELP kperf_ksvc_phase_duration_seconds Duration of the phases of the measured Knative Services.\n")
+	fmt.Fprintf(w, "# TYPE kperf_ksvc_phase_duration_seconds histogram\n")
+	phases := make([]string, 0, len(f.phases))
+	for phase := range f.phases {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	for _, phase := range phases {
+		h := f.phases[phase]
+		for i, bound := range followBuckets {
+			fmt.Fprintf(w, "kperf_ksvc_phase_duration_seconds_bucket{phase=%q,le=%q} %d\n", phase,
+				strconv.FormatFloat(bound, 'f', -1, 64), h.buckets[i])
+		}
+		fmt.Fprintf(w, "kperf_ksvc_phase_duration_seconds_bucket{phase=%q,le=\"+Inf\"} %d\n", phase, h.count)
+		fmt.Fprintf(w, "kperf_ksvc_phase_duration_seconds_sum{phase=%q} %s\n", phase, strconv.FormatFloat(h.sum, 'f', -1, 64))
+		fmt.Fprintf(w, "kperf_ksvc_phase_duration_seconds_count{phase=%q} %d\n", phase, h.count)
+	}
+}
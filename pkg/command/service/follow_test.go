@@ -0,0 +1,109 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/watch"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	fakenetworkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	autoscalingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
+	autoscalingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1/fake"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+)
+
+func TestValidateFollow(t *testing.T) {
+	p := &pkg.PerfParams{}
+	_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--metrics-addr", ":9090")
+	assert.ErrorContains(t, err, "--metrics-addr requires --follow")
+	_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--follow", "--namespace", "ns", "--range", "1,10")
+	assert.ErrorContains(t, err, "it can't be combined with --range")
+	_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--follow", "--sample", "10%")
+	assert.ErrorContains(t, err, "--follow can't be combined with --checkpoint, --sample or --limit")
+	_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--follow", "--kind", "route")
+	assert.ErrorContains(t, err, "--follow only supports --kind service")
+	_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--follow", "--profile-controlplane")
+	assert.ErrorContains(t, err, "--follow can't be combined with --profile-controlplane")
+	_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--follow", "--collect-events")
+	assert.ErrorContains(t, err, "--follow can't be combined with --collect-events")
+
+	_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--follow", "--image-pulls")
+	assert.ErrorContains(t, err, "--follow can't be combined with --image-pulls")
+	_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--follow", "--extra-metrics-cmd", "echo {}")
+	assert.ErrorContains(t, err, "--follow writes no report, it can't be combined with --extra-metrics-cmd")
+	assert.NilError(t, validateFollow(pkg.MeasureArgs{Follow: true, Namespace: "ns", Selector: "app=demo"}))
+}
+
+func TestFollowServices(t *testing.T) {
+	fake := &clienttesting.Fake{}
+	watcher := watch.NewFake()
+	fake.PrependWatchReactor("services", clienttesting.DefaultWatchReactor(watcher, nil))
+	p := &pkg.PerfParams{
+		ClientSet: k8sfake.NewSimpleClientset(),
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return &servingv1fake.FakeServingV1{Fake: fake}, nil
+		},
+		NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+			return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: fake}, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: fake}, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := &bytes.Buffer{}
+	err := followServices(ctx, p, pkg.MeasureArgs{Follow: true, Selector: "app=demo", MetricsAddr: "127.0.0.1:0"}, &bytes.Buffer{}, out)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(out.String(), "Serving Prometheus metrics on http://127.0.0.1:"))
+	assert.Assert(t, strings.Contains(out.String(), "Following the new services in all namespaces"))
+	assert.Assert(t, strings.Contains(out.String(), "Measured 0 service(s)"))
+}
+
+func TestFollowMetrics(t *testing.T) {
+	metrics := newFollowMetrics()
+	metrics.observe(pkg.MeasureRecord{ServiceNamespace: "ns-1", OverallReady: 3})
+	metrics.observe(pkg.MeasureRecord{ServiceNamespace: "ns-1", OverallReady: 12.5})
+	metrics.observe(pkg.MeasureRecord{ServiceNamespace: "ns-2", OverallReady: 400})
+	assert.Equal(t, uint64(3), metrics.total())
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	for _, line := range []string{
+		`kperf_ksvc_measured_total{namespace="ns-1"} 2`,
+		`kperf_ksvc_measured_total{namespace="ns-2"} 1`,
+		`kperf_ksvc_phase_duration_seconds_bucket{phase="overall_ready",le="2"} 0`,
+		`kperf_ksvc_phase_duration_seconds_bucket{phase="overall_ready",le="5"} 1`,
+		`kperf_ksvc_phase_duration_seconds_bucket{phase="overall_ready",le="20"} 2`,
+		`kperf_ksvc_phase_duration_seconds_bucket{phase="overall_ready",le="300"} 2`,
+		`kperf_ksvc_phase_duration_seconds_bucket{phase="overall_ready",le="+Inf"} 3`,
+		`kperf_ksvc_phase_duration_seconds_sum{phase="overall_ready"} 415.5`,
+		`kperf_ksvc_phase_duration_seconds_count{phase="overall_ready"} 3`,
+	} {
+		assert.Assert(t, strings.Contains(body, line+"\n"), "missing %s in\n%s", line, body)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -26,14 +27,19 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/spf13/cobra"
 
 	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
 	"knative.dev/kperf/pkg/generator"
-	knativeapis "knative.dev/pkg/apis"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 )
 
@@ -52,43 +58,125 @@ func NewServiceGenerateCommand(p *pkg.PerfParams) *cobra.Command {
 For example:
 # To generate Knative Service workload
 kperf service generate -n 500 --interval 20 --batch 20 --min-scale 0 --max-scale 5 (--namespace-prefix testns/ --namespace nsname)
+
+# To generate Knative Service workload in new namespaces, which are deleted with the services by the run ID
+kperf service generate -n 500 --interval 20 --batch 20 --namespace-prefix testns --namespace-range 1,5 --create-namespaces --run-id demo
+
+# To generate Knative Service workload spread across namespaces by weight, half of the services in ns-1
+kperf service generate -n 100 --interval 10 --batch 10 --namespace-weights ns-1:50,ns-2:30,ns-3:20
+
+# To generate Knative Service workload in waves of 50 services every 10 seconds
+kperf service generate -n 1000 --batch 50 --interval 10s --namespace nsname
+
+# To generate Knative Service workload from a custom Knative Service template
+kperf service generate -n 500 --interval 20 --batch 20 --template ksvc.yaml
+
+# To generate Knative Service workload scaling on 50 requests per second with a scale down delay of 5 minutes
+kperf service generate -n 500 --interval 20 --batch 20 --metric rps --target 50 --scale-down-delay 5m
+
+# To generate Knative Service workload with 3 revisions each, and the traffic split evenly across them
+kperf service generate -n 100 --interval 10 --batch 10 --revisions 3
+
+# To generate Knative Service workload and sample the control plane pods during the generation
+kperf service generate -n 500 --interval 20 --batch 20 --profile-controlplane --profile-interval 5s
+
+# To generate Knative Service workload, wait for every service to be ready for at most 10 minutes and record its ready duration
+kperf service generate -n 100 --interval 10 --batch 10 --namespace nsname --wait --timeout 10m
+
+# To generate the same Knative Service workload in the clusters of the kubeconfig contexts kourier and istio
+kperf service generate -n 100 --interval 10 --batch 10 --namespace ktest --contexts kourier,istio --run-id compare
 `,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			flags := cmd.Flags()
 			if flags.Changed("namespace-prefix") && flags.Changed("namespace") {
 				return errors.New("expected either namespace with prefix & range or only namespace name")
 			}
-			return nil
+			if flags.Changed("namespace-weights") && (flags.Changed("namespace-prefix") || flags.Changed("namespace")) {
+				return errors.New("--namespace-weights can't be combined with --namespace or --namespace-prefix")
+			}
+			if _, err := parseNamespaceWeights(generateArgs.NamespaceWeights); err != nil {
+				return err
+			}
+			if err := pkg.ValidateRunID(generateArgs.RunID); err != nil {
+				return err
+			}
+			if err := validateControlPlaneProfileArgs(generateArgs.ControlPlane); err != nil {
+				return err
+			}
+			if err := validateContexts(generateArgs.Contexts); err != nil {
+				return err
+			}
+			if generateArgs.Revisions < 1 {
+				return fmt.Errorf("--revisions must be at least 1, given %d", generateArgs.Revisions)
+			}
+			return validateAutoscalingArgs(generateArgs)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(generateArgs.Contexts) > 0 {
+				return GenerateClusters(p, generateArgs)
+			}
 			return GenerateServices(p, generateArgs)
 		},
 	}
 	ksvcGenCommand.Flags().IntVarP(&generateArgs.Number, "number", "n", 0, "Total number of Knative Service to be created")
 	ksvcGenCommand.MarkFlagRequired("number")
-	ksvcGenCommand.Flags().IntVarP(&generateArgs.Interval, "interval", "i", 0, "Interval for each batch generation")
+	ksvcGenCommand.Flags().VarP(utils.NewIntervalValue(0, &generateArgs.Interval), "interval", "i", "Interval between the batches, a duration like 10s or a number of seconds")
 	ksvcGenCommand.MarkFlagRequired("interval")
 	ksvcGenCommand.Flags().IntVarP(&generateArgs.Batch, "batch", "b", 0, "Number of Knative Service each time to be created")
 	ksvcGenCommand.MarkFlagRequired("batch")
 	ksvcGenCommand.Flags().IntVarP(&generateArgs.Concurrency, "concurrency", "c", 10, "Number of multiple Knative Services to make at a time")
 	ksvcGenCommand.Flags().IntVarP(&generateArgs.MinScale, "min-scale", "", 0, "For autoscaling.knative.dev/minScale")
-	ksvcGenCommand.Flags().IntVarP(&generateArgs.MaxScale, "max-scale", "", 0, "For autoscaling.knative.dev/minScale")
+	ksvcGenCommand.Flags().IntVarP(&generateArgs.MaxScale, "max-scale", "", 0, "For autoscaling.knative.dev/maxScale")
+	ksvcGenCommand.Flags().IntVarP(&generateArgs.Target, "target", "", 0, "For autoscaling.knative.dev/target, the per pod target of the autoscaling metric, 0 for the cluster default")
+	ksvcGenCommand.Flags().IntVarP(&generateArgs.TargetUtilization, "target-utilization", "", 0, "For autoscaling.knative.dev/target-utilization-percentage, 0 for the cluster default")
+	ksvcGenCommand.Flags().DurationVarP(&generateArgs.ScaleDownDelay, "scale-down-delay", "", 0, "For autoscaling.knative.dev/scale-down-delay, e.g. 5m, 0 for the cluster default")
+	ksvcGenCommand.Flags().DurationVarP(&generateArgs.Window, "window", "", 0, "For autoscaling.knative.dev/window, the stable window of the autoscaler, e.g. 60s, 0 for the cluster default")
+	ksvcGenCommand.Flags().StringVarP(&generateArgs.Metric, "metric", "", "", "For autoscaling.knative.dev/metric, e.g. concurrency, rps, cpu or memory")
+	ksvcGenCommand.Flags().StringVarP(&generateArgs.Class, "class", "", "", "For autoscaling.knative.dev/class, e.g. kpa.autoscaling.knative.dev or hpa.autoscaling.knative.dev")
 
 	ksvcGenCommand.Flags().StringVarP(&generateArgs.NamespacePrefix, "namespace-prefix", "", "", "Namespace prefix. The Knative Services will be created in the namespaces with the prefix")
 	ksvcGenCommand.Flags().StringVarP(&generateArgs.NamespaceRange, "namespace-range", "", "", "")
 	ksvcGenCommand.Flags().StringVarP(&generateArgs.Namespace, "namespace", "", "", "Namespace name. The Knative Services will be created in the namespace")
+	ksvcGenCommand.Flags().StringSliceVarP(&generateArgs.NamespaceWeights, "namespace-weights", "", nil, "Comma separated namespaces with weights like ns-1:50,ns-2:30,ns-3:20. The Knative Services will be spread across the namespaces by weight instead of evenly")
 
 	ksvcGenCommand.Flags().StringVarP(&generateArgs.SvcPrefix, "svc-prefix", "", "ksvc", "Knative Service name prefix. The Knative Services will be ksvc-1,ksvc-2,ksvc-3 and etc.")
-	ksvcGenCommand.Flags().BoolVarP(&generateArgs.CheckReady, "wait", "", false, "Whether to wait the previous Knative Service to be ready")
-	ksvcGenCommand.Flags().DurationVarP(&generateArgs.Timeout, "timeout", "", 10*time.Minute, "Duration to wait for previous Knative Service to be ready")
+	ksvcGenCommand.Flags().BoolVarP(&generateArgs.CheckReady, "wait", "", false, "Whether to watch every created Knative Service until it is ready and record the duration from its creation, so that a separate measure pass is optional")
+	ksvcGenCommand.Flags().BoolVarP(&generateArgs.MeasureInline, "measure-inline", "", false, "Whether to watch the Knative Services from before the first one is created and record the wall-clock duration until each one is ready as it happens, reported side by side with the duration from the status timestamps")
+	ksvcGenCommand.Flags().DurationVarP(&generateArgs.Timeout, "timeout", "", 10*time.Minute, "Duration to wait for every Knative Service to be ready with --wait or --measure-inline, the generation fails if one isn't ready in time")
+	ksvcGenCommand.Flags().BoolVarP(&generateArgs.CleanupOnFailure, "cleanup-on-failure", "", false, "Delete the created Knative Services and the namespaces created by --create-namespaces if the generation fails, panics or is interrupted")
+	ksvcGenCommand.Flags().StringVarP(&generateArgs.Template, "template", "", "", "Knative Service YAML file used instead of the built-in spec. It's a go-template with the variables {{.Index}}, {{.Name}}, {{.Namespace}}, {{.Prefix}}, {{.MinScale}} and {{.MaxScale}}")
+	ksvcGenCommand.Flags().StringVarP(&generateArgs.RunID, "run-id", "", "", "ID of the run the generated Knative Services are labeled with as "+pkg.RunIDLabel+", so that 'kperf service clean --run-id' removes exactly them. A new ID is generated by default")
+	ksvcGenCommand.Flags().BoolVarP(&generateArgs.CreateNamespaces, "create-namespaces", "", false, "Create the namespaces which don't exist, labeled with the run ID so that 'kperf service clean --run-id' removes them as well")
+	ksvcGenCommand.Flags().IntVarP(&generateArgs.Revisions, "revisions", "", 1, "Number of revisions of each Knative Service, named <service>-rev-<n>, with the traffic split evenly across them")
+	ksvcGenCommand.Flags().StringVarP(&generateArgs.Output, "output", "o", ".", "Location of the control plane profile written with --profile-controlplane and of the ready durations written with --wait and --measure-inline")
+	report.AddFlag(ksvcGenCommand.Flags(), &generateArgs.OutputFormats)
+	addControlPlaneProfileFlags(ksvcGenCommand.Flags(), &generateArgs.ControlPlane)
+	ksvcGenCommand.Flags().StringSliceVarP(&generateArgs.Contexts, "contexts", "", nil, "Comma separated kubeconfig contexts of the clusters to generate the Knative Services in one after the other, labeled with the same run ID")
+	ksvcGenCommand.Flags().DurationVarP(&generateArgs.TTL, "ttl", "", 0, "Time to live of the generated Knative Services, after which 'kperf clean expired' deletes them, 0 to never expire")
 
 	return ksvcGenCommand
 }
 
 // GenerateServices used to generate Knative Service workload
 func GenerateServices(params *pkg.PerfParams, inputs pkg.GenerateArgs) error {
+	if inputs.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, given %s", inputs.Interval)
+	}
 	nsNameList := []string{}
-	if inputs.NamespacePrefix == "" && inputs.Namespace == "" {
+	// the namespaces the services are created in one after the other, each of nsNameList once if not weighted
+	var nsSequence []string
+	if len(inputs.NamespaceWeights) > 0 {
+		weights, err := parseNamespaceWeights(inputs.NamespaceWeights)
+		if err != nil {
+			return err
+		}
+		for _, w := range weights {
+			nsNameList = append(nsNameList, w.namespace)
+		}
+		nsSequence = weightedNamespaces(weights)
+		fmt.Printf("Spreading the Knative Services across the namespaces by weight %s\n", strings.Join(inputs.NamespaceWeights, ","))
+	} else if inputs.NamespacePrefix == "" && inputs.Namespace == "" {
 		nsNameList = []string{DefaultNamespace}
 	} else if inputs.NamespacePrefix != "" {
 		r := strings.Split(inputs.NamespaceRange, ",")
@@ -113,36 +201,160 @@ func GenerateServices(params *pkg.PerfParams, inputs pkg.GenerateArgs) error {
 	} else if inputs.Namespace != "" {
 		nsNameList = append(nsNameList, inputs.Namespace)
 	}
+	if nsSequence == nil {
+		nsSequence = nsNameList
+	}
 
-	// Check if namespace exists, in NOT, return error
+	var tmpl *serviceTemplate
+	if inputs.Template != "" {
+		var err error
+		tmpl, err = loadServiceTemplate(inputs.Template)
+		if err != nil {
+			return err
+		}
+		// render the template once to fail before any service is created
+		if _, err := tmpl.render(serviceTemplateData{Name: inputs.SvcPrefix + "-0", Namespace: nsNameList[0], Prefix: inputs.SvcPrefix,
+			MinScale: inputs.MinScale, MaxScale: inputs.MaxScale}); err != nil {
+			return err
+		}
+	}
+
+	var clk clock.Clock = clock.RealClock{}
+	if params.Clock != nil {
+		clk = params.Clock
+	}
+	if inputs.RunID == "" {
+		inputs.RunID = pkg.NewRunID(clk.Now())
+	}
+	fmt.Printf("Run ID %s, clean up the run with 'kperf service clean --run-id %s'\n", inputs.RunID, inputs.RunID)
+
+	var cleanup *generator.Cleanup
+	if inputs.CleanupOnFailure {
+		cleanup = generator.NewCleanup()
+		defer cleanup.HandleSignals()()
+		defer func() {
+			if r := recover(); r != nil {
+				cleanup.Run()
+				panic(r)
+			}
+		}()
+	}
+	// abort deletes the resources created so far before the generation fails
+	abort := func(err error) error {
+		if cleanup != nil {
+			cleanup.Run()
+		}
+		return err
+	}
+
+	// Check if namespace exists, in NOT, return error or create it
 	for _, ns := range nsNameList {
+		ns := ns
 		_, err := params.ClientSet.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{})
-		if err != nil && apierrors.IsNotFound(err) {
-			return fmt.Errorf("namespace %s not found, please create one", ns)
+		if err != nil && apierrors.IsNotFound(err) && inputs.CreateNamespaces {
+			fmt.Printf("Creating namespace %s\n", ns)
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns, Labels: map[string]string{pkg.RunIDLabel: inputs.RunID}}}
+			if _, err := params.ClientSet.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{}); err != nil {
+				return abort(fmt.Errorf("failed to create namespace %s: %w", ns, err))
+			}
+			if cleanup != nil {
+				cleanup.Add("Namespace", "", ns, func() error {
+					return params.ClientSet.CoreV1().Namespaces().Delete(context.Background(), ns, metav1.DeleteOptions{})
+				})
+			}
+		} else if err != nil && apierrors.IsNotFound(err) {
+			return abort(fmt.Errorf("namespace %s not found, please create one", ns))
 		} else if err != nil {
-			return fmt.Errorf("failed to get namespace: %w", err)
+			return abort(fmt.Errorf("failed to get namespace: %w", err))
 		}
 	}
 
 	ksvcClient, err := params.NewServingClient()
 	if err != nil {
-		return err
+		return abort(err)
+	}
+	waves := newWaveRecorder(inputs.Batch)
+	ready := newReadyRecorder()
+	var inline *inlineRecorder
+	if inputs.MeasureInline {
+		// the watches are started before the first service is created, so that no ready event is missed
+		inline = newInlineRecorder(clk, inputs.RunID)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := inline.start(ctx, ksvcClient, nsNameList); err != nil {
+			return abort(err)
+		}
+	}
+	createKSVC := func(service *servingv1.Service, index int) (string, string) {
+		ns, name := service.GetNamespace(), service.GetName()
+		for k, v := range pkg.GeneratedLabels(inputs.RunID, inputs.TTL, clk.Now()) {
+			if service.Labels == nil {
+				service.Labels = map[string]string{}
+			}
+			service.Labels[k] = v
+		}
+		if inputs.Revisions > 1 {
+			service.Spec.Template.Name = RevisionName(name, 1)
+		}
+		fmt.Printf("Creating Knative Service %s in namespace %s\n", name, ns)
+		start := clk.Now()
+		_, err := ksvcClient.Services(ns).Create(context.TODO(), service, metav1.CreateOptions{})
+		waves.record(index, clk.Since(start), err)
+		if err == nil {
+			ready.create(ns, name, start)
+			if inline != nil {
+				inline.create(ns, name, start)
+			}
+		}
+		if err != nil {
+			fmt.Printf("failed to create Knative Service %s in namespace %s : %s\n", name, ns, err)
+		} else if cleanup != nil {
+			cleanup.Add("Knative Service", ns, name, func() error {
+				return ksvcClient.Services(ns).Delete(context.Background(), name, metav1.DeleteOptions{})
+			})
+		}
+		if err == nil && inputs.Revisions > 1 {
+			if err := addRevisions(context.TODO(), ksvcClient, ns, name, inputs.Revisions, inputs.Timeout); err != nil {
+				fmt.Printf("failed to create the revisions of Knative Service %s in namespace %s : %s\n", name, ns, err)
+			}
+		}
+		return ns, name
 	}
 	createKSVCFunc := func(ns string, index int) (string, string) {
+		name := fmt.Sprintf("%s-%d", inputs.SvcPrefix, index)
+		if tmpl != nil {
+			service, err := tmpl.render(serviceTemplateData{Index: index, Name: name, Namespace: ns, Prefix: inputs.SvcPrefix,
+				MinScale: inputs.MinScale, MaxScale: inputs.MaxScale})
+			if err != nil {
+				fmt.Printf("failed to create Knative Service %s in namespace %s : %s\n", name, ns, err)
+				return ns, name
+			}
+			for k, v := range autoscalingAnnotations(inputs) {
+				if service.Spec.Template.Annotations == nil {
+					service.Spec.Template.Annotations = map[string]string{}
+				}
+				service.Spec.Template.Annotations[k] = v
+			}
+			return createKSVC(service, index)
+		}
 		service := servingv1.Service{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      fmt.Sprintf("%s-%d", inputs.SvcPrefix, index),
+				Name:      name,
 				Namespace: ns,
 			},
 		}
 
+		annotations := map[string]string{
+			"autoscaling.knative.dev/minScale": strconv.Itoa(inputs.MinScale),
+			"autoscaling.knative.dev/maxScale": strconv.Itoa(inputs.MaxScale),
+		}
+		for k, v := range autoscalingAnnotations(inputs) {
+			annotations[k] = v
+		}
 		service.Spec.Template = servingv1.RevisionTemplateSpec{
 			Spec: servingv1.RevisionSpec{},
 			ObjectMeta: metav1.ObjectMeta{
-				Annotations: map[string]string{
-					"autoscaling.knative.dev/minScale": strconv.Itoa(inputs.MinScale),
-					"autoscaling.knative.dev/maxScale": strconv.Itoa(inputs.MaxScale),
-				},
+				Annotations: annotations,
 			},
 		}
 		service.Spec.Template.Spec.Containers = []corev1.Container{
@@ -155,33 +367,96 @@ func GenerateServices(params *pkg.PerfParams, inputs pkg.GenerateArgs) error {
 				},
 			},
 		}
-		fmt.Printf("Creating Knative Service %s in namespace %s\n", service.GetName(), service.GetNamespace())
-		_, err := ksvcClient.Services(ns).Create(context.TODO(), &service, metav1.CreateOptions{})
-		if err != nil {
-			fmt.Printf("failed to create Knative Service %s in namespace %s : %s\n", service.GetName(), service.GetNamespace(), err)
-		}
-		return service.GetNamespace(), service.GetName()
+		return createKSVC(&service, index)
 	}
 	checkServiceStatusReadyFunc := func(ns, name string) error {
-		start := time.Now()
-		for time.Since(start) < inputs.Timeout {
-			svc, _ := ksvcClient.Services(ns).Get(context.TODO(), name, metav1.GetOptions{})
-			conditions := svc.Status.Conditions
-			for i := 0; i < len(conditions); i++ {
-				if conditions[i].Type == knativeapis.ConditionReady && conditions[i].IsTrue() {
-					return nil
-				}
-			}
+		if err := WaitServiceReady(context.TODO(), ksvcClient, ns, name, inputs.Timeout); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return err
 		}
-		fmt.Printf("Error: Knative Service %s in namespace %s is not ready after %s\n", name, ns, inputs.Timeout)
-		return fmt.Errorf("Knative Service %s in namespace %s is not ready after %s ", name, ns, inputs.Timeout)
-
+		fmt.Printf("Knative Service %s in namespace %s is ready after %fs\n", name, ns, ready.ready(ns, name, clk.Now()).Seconds())
+		return nil
 	}
+	var batchGenerator *generator.BatchGenerator
 	if inputs.CheckReady {
-		generator.NewBatchGenerator(time.Duration(inputs.Interval)*time.Second, inputs.Number, inputs.Batch, inputs.Concurrency, nsNameList, createKSVCFunc, checkServiceStatusReadyFunc).Generate()
+		batchGenerator = generator.NewBatchGenerator(inputs.Interval, inputs.Number, inputs.Batch, inputs.Concurrency, nsSequence, createKSVCFunc, checkServiceStatusReadyFunc)
 	} else {
-		generator.NewBatchGenerator(time.Duration(inputs.Interval)*time.Second, inputs.Number, inputs.Batch, inputs.Concurrency, nsNameList, createKSVCFunc, func(ns, name string) error { return nil }).Generate()
+		batchGenerator = generator.NewBatchGenerator(inputs.Interval, inputs.Number, inputs.Batch, inputs.Concurrency, nsSequence, createKSVCFunc, func(ns, name string) error { return nil })
 	}
+	batchGenerator.WithClock(clk)
+	if cleanup != nil {
+		batchGenerator.WithAbort(cleanup.Run)
+	}
+	var stopProfile func() *pkg.ControlPlaneProfile
+	if inputs.ControlPlane.Enabled {
+		stopProfile = measure.StartControlPlaneProfiler(context.Background(), params, inputs.ControlPlane, measure.DefaultLogger)
+	}
+	batchGenerator.Generate()
+	waves.write(os.Stdout)
+	// the files which can't be saved fail the run after all the others are saved
+	errs := []error{}
+	if inputs.CheckReady {
+		ready.write(os.Stdout)
+		if err := ready.save(os.Stdout, params.Output, inputs.Output, inputs.RunID, inputs.OutputFormats, clk.Now()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save the ready durations: %w", err))
+		}
+	}
+	if inline != nil {
+		errs = append(errs, inline.wait(inputs.Timeout))
+		result := inline.result()
+		writeInline(os.Stdout, result)
+		if err := saveInline(os.Stdout, params.Output, inputs.Output, inputs.RunID, inputs.OutputFormats, clk.Now(), result); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save the inline ready durations: %w", err))
+		}
+	}
+	if stopProfile != nil {
+		profile := stopProfile()
+		writeControlPlaneProfile(os.Stdout, profile)
+		if err := saveControlPlaneProfile(os.Stdout, params.Output, profile, inputs.Output, inputs.RunID, clk.Now()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save the control plane profile: %w", err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
 
+// validateAutoscalingArgs checks the autoscaling flags, the ranges are the ones the Knative webhook accepts
+func validateAutoscalingArgs(inputs pkg.GenerateArgs) error {
+	if inputs.Target < 0 {
+		return fmt.Errorf("--target must not be negative, given %d", inputs.Target)
+	}
+	if inputs.TargetUtilization < 0 || inputs.TargetUtilization > 100 {
+		return fmt.Errorf("--target-utilization must be between 1 and 100, given %d", inputs.TargetUtilization)
+	}
+	if inputs.ScaleDownDelay < 0 || inputs.ScaleDownDelay > time.Hour {
+		return fmt.Errorf("--scale-down-delay must be between 0s and 1h, given %s", inputs.ScaleDownDelay)
+	}
+	if inputs.Window != 0 && (inputs.Window < autoscaling.WindowMin || inputs.Window > autoscaling.WindowMax) {
+		return fmt.Errorf("--window must be between %s and %s, given %s", autoscaling.WindowMin, autoscaling.WindowMax, inputs.Window)
+	}
 	return nil
 }
+
+// autoscalingAnnotations returns the autoscaling annotations of the revision template for the autoscaling flags
+// which are set, the cluster defaults apply to the others
+func autoscalingAnnotations(inputs pkg.GenerateArgs) map[string]string {
+	annotations := map[string]string{}
+	if inputs.Target > 0 {
+		annotations[autoscaling.TargetAnnotationKey] = strconv.Itoa(inputs.Target)
+	}
+	if inputs.TargetUtilization > 0 {
+		annotations[autoscaling.TargetUtilizationPercentageKey] = strconv.Itoa(inputs.TargetUtilization)
+	}
+	if inputs.ScaleDownDelay > 0 {
+		annotations[autoscaling.ScaleDownDelayAnnotationKey] = inputs.ScaleDownDelay.String()
+	}
+	if inputs.Window > 0 {
+		annotations[autoscaling.WindowAnnotationKey] = inputs.Window.String()
+	}
+	if inputs.Metric != "" {
+		annotations[autoscaling.MetricAnnotationKey] = inputs.Metric
+	}
+	if inputs.Class != "" {
+		annotations[autoscaling.ClassAnnotationKey] = inputs.Class
+	}
+	return annotations
+}
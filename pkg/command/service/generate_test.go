@@ -16,14 +16,27 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 	"knative.dev/kperf/pkg"
 	"knative.dev/kperf/pkg/testutil"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
 	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
 )
@@ -74,6 +87,36 @@ func TestNewServiceGenerateCommand(t *testing.T) {
 
 		_, err = testutil.ExecuteCommand(cmd, "--namespace-prefix", "test-kperf", "--namespace", "test-kperf")
 		assert.ErrorContains(t, err, "expected either namespace with prefix & range or only namespace name")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace", "test-kperf", "--namespace-weights", "ns-1:50")
+		assert.ErrorContains(t, err, "--namespace-weights can't be combined with --namespace or --namespace-prefix")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace-weights", "ns-1:50,ns-2")
+		assert.ErrorContains(t, err, "expected namespace weight like ns-1:50, given ns-2")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "1", "-i", "0", "--namespace", "test-kperf")
+		assert.ErrorContains(t, err, "interval must be positive, given 0s")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "1", "-i", "soon", "--namespace", "test-kperf")
+		assert.ErrorContains(t, err, "expected a duration like 10s or a number of seconds, given soon")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace", "test-kperf", "--target", "-1")
+		assert.ErrorContains(t, err, "--target must not be negative, given -1")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace", "test-kperf", "--target-utilization", "101")
+		assert.ErrorContains(t, err, "--target-utilization must be between 1 and 100, given 101")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace", "test-kperf", "--scale-down-delay", "2h")
+		assert.ErrorContains(t, err, "--scale-down-delay must be between 0s and 1h, given 2h0m0s")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace", "test-kperf", "--window", "1s")
+		assert.ErrorContains(t, err, "--window must be between 6s and 1h0m0s, given 1s")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace", "test-kperf", "--revisions", "0")
+		assert.ErrorContains(t, err, "--revisions must be at least 1, given 0")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "--namespace", "test-kperf", "--profile-controlplane", "--profile-interval", "0s")
+		assert.ErrorContains(t, err, "--profile-interval must be positive, given 0s")
 	})
 
 	t.Run("generate service as expected with namespace flag", func(t *testing.T) {
@@ -94,12 +137,14 @@ func TestNewServiceGenerateCommand(t *testing.T) {
 		}
 
 		cmd := NewServiceGenerateCommand(p)
-		_, err := testutil.ExecuteCommand(cmd, "-n", "1", "-b", "10", "-i", "10", "--min-scale", "1", "--max-scale", "2", "--namespace", "test-kperf-1")
+		_, err := testutil.ExecuteCommand(cmd, "-n", "1", "-b", "10", "-i", "10", "--min-scale", "1", "--max-scale", "2", "--namespace", "test-kperf-1", "--ttl", "1h")
 		assert.NilError(t, err)
 
 		ksvcClient, _ := p.NewServingClient()
 		svc, _ := ksvcClient.Services("test-kperf-1").Get(context.TODO(), "ksvc-0", metav1.GetOptions{})
 		assert.Equal(t, "ksvc-0", svc.Name)
+		assert.Check(t, !pkg.Expired(svc.Labels, time.Now().Add(59*time.Minute)), "expected the service to expire after 1h")
+		assert.Check(t, pkg.Expired(svc.Labels, time.Now().Add(time.Hour)), "expected the service to expire after 1h")
 		targetAnnotations := make(map[string]string)
 		targetAnnotations["autoscaling.knative.dev/maxScale"] = "2"
 		targetAnnotations["autoscaling.knative.dev/minScale"] = "1"
@@ -107,6 +152,215 @@ func TestNewServiceGenerateCommand(t *testing.T) {
 		assert.DeepEqual(t, targetAnnotations, resultAnnotations)
 	})
 
+	t.Run("generate service in created namespaces labeled with the run ID", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "1", "-i", "1", "--namespace", "test-kperf-1")
+		assert.ErrorContains(t, err, "namespace test-kperf-1 not found, please create one")
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "1", "-i", "1", "--namespace", "test-kperf-1",
+			"--create-namespaces", "--run-id", "demo")
+		assert.NilError(t, err)
+
+		ns, err := client.CoreV1().Namespaces().Get(context.TODO(), "test-kperf-1", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "demo", ns.Labels[pkg.RunIDLabel])
+		svc, err := fakeServing.Services("test-kperf-1").Get(context.TODO(), "ksvc-0", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "demo", svc.Labels[pkg.RunIDLabel])
+	})
+
+	t.Run("delete the created namespaces if the generation fails", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		client.PrependReactor("create", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			ns := action.(clienttesting.CreateAction).GetObject().(*corev1.Namespace)
+			if ns.Name == "test-kperf-2" {
+				return true, nil, errors.New("fake error")
+			}
+			return false, nil, nil
+		})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "1", "-i", "1", "--namespace-prefix", "test-kperf",
+			"--namespace-range", "1,2", "--create-namespaces", "--cleanup-on-failure")
+		assert.ErrorContains(t, err, "failed to create namespace test-kperf-2: fake error")
+		_, err = client.CoreV1().Namespaces().Get(context.TODO(), "test-kperf-1", metav1.GetOptions{})
+		assert.Assert(t, apierrors.IsNotFound(err), "namespace test-kperf-1 created by the failed run is not deleted: %v", err)
+	})
+
+	t.Run("generate service with autoscaling annotations", func(t *testing.T) {
+		ns1 := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-kperf-1",
+			},
+		}
+		client := k8sfake.NewSimpleClientset(ns1)
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		servingClient := func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		}
+
+		p := &pkg.PerfParams{
+			ClientSet:        client,
+			NewServingClient: servingClient,
+		}
+
+		cmd := NewServiceGenerateCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "-n", "1", "-b", "10", "-i", "100ms", "--namespace", "test-kperf-1", "--max-scale", "5",
+			"--target", "50", "--target-utilization", "80", "--scale-down-delay", "5m", "--window", "60s", "--metric", "rps", "--class", "kpa.autoscaling.knative.dev")
+		assert.NilError(t, err)
+
+		ksvcClient, _ := p.NewServingClient()
+		svc, err := ksvcClient.Services("test-kperf-1").Get(context.TODO(), "ksvc-0", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]string{
+			"autoscaling.knative.dev/minScale":                      "0",
+			"autoscaling.knative.dev/maxScale":                      "5",
+			"autoscaling.knative.dev/target":                        "50",
+			"autoscaling.knative.dev/target-utilization-percentage": "80",
+			"autoscaling.knative.dev/scale-down-delay":              "5m0s",
+			"autoscaling.knative.dev/window":                        "1m0s",
+			"autoscaling.knative.dev/metric":                        "rps",
+			"autoscaling.knative.dev/class":                         "kpa.autoscaling.knative.dev",
+		}, svc.Spec.Template.Annotations)
+	})
+
+	t.Run("generate service with revisions and a traffic split", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-kperf-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+		// the revision of the current template is created as soon as the service is read
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			get := action.(clienttesting.GetAction)
+			obj, err := client.Tracker().Get(get.GetResource(), get.GetNamespace(), get.GetName())
+			if err != nil {
+				return true, nil, err
+			}
+			svc := obj.(*servingv1.Service).DeepCopy()
+			svc.Status.LatestCreatedRevisionName = svc.Spec.Template.Name
+			return true, svc, nil
+		})
+
+		_, err := testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "1", "-i", "1", "--namespace", "test-kperf-1", "--revisions", "3")
+		assert.NilError(t, err)
+
+		svc, err := fakeServing.Services("test-kperf-1").Get(context.TODO(), "ksvc-0", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "ksvc-0-rev-3", svc.Spec.Template.Name)
+		assert.DeepEqual(t, []corev1.EnvVar{{Name: revisionEnv, Value: "3"}}, svc.Spec.Template.Spec.Containers[0].Env)
+		assert.DeepEqual(t, []servingv1.TrafficTarget{
+			{RevisionName: "ksvc-0-rev-1", Percent: ptr.Int64(33)},
+			{RevisionName: "ksvc-0-rev-2", Percent: ptr.Int64(33)},
+			{RevisionName: "ksvc-0-rev-3", Percent: ptr.Int64(34)},
+		}, svc.Spec.Traffic)
+	})
+
+	t.Run("generate service and profile the control plane", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-kperf-1"}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "controller-1", Namespace: "knative-serving", Labels: map[string]string{"app": "controller"}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+			NewDynamicClient: func() (dynamic.Interface, error) {
+				return nil, errors.New("metrics-server is not installed")
+			},
+		}
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "1", "-i", "100ms", "--namespace", "test-kperf-1",
+			"--run-id", "demo", "--profile-controlplane", "--profile-interval", "10ms", "--output", outputDir)
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "demo", "*_"+ControlPlaneProfileFilename+".json"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		profile := pkg.ControlPlaneProfile{}
+		assert.NilError(t, json.Unmarshal(data, &profile))
+		assert.Equal(t, 1, len(profile.Pods))
+		assert.Equal(t, "controller", profile.Pods[0].Component)
+		assert.Check(t, len(profile.Pods[0].Samples) > 0)
+	})
+
+	t.Run("generate service from a template", func(t *testing.T) {
+		ns1 := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-kperf-1",
+			},
+		}
+		client := k8sfake.NewSimpleClientset(ns1)
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		servingClient := func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		}
+
+		p := &pkg.PerfParams{
+			ClientSet:        client,
+			NewServingClient: servingClient,
+		}
+
+		template := filepath.Join(t.TempDir(), "ksvc.yaml")
+		err := ioutil.WriteFile(template, []byte(`apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  labels:
+    app: demo
+spec:
+  template:
+    spec:
+      containers:
+      - image: example.com/app:v1
+        env:
+        - name: INDEX
+          value: "{{.Index}}"
+`), 0644)
+		assert.NilError(t, err)
+
+		cmd := NewServiceGenerateCommand(p)
+		_, err = testutil.ExecuteCommand(cmd, "-n", "1", "-b", "10", "-i", "1", "--namespace", "test-kperf-1", "--template", template, "--ttl", "1h", "--target", "10")
+		assert.NilError(t, err)
+
+		ksvcClient, _ := p.NewServingClient()
+		svc, err := ksvcClient.Services("test-kperf-1").Get(context.TODO(), "ksvc-0", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "demo", svc.Labels["app"])
+		assert.Check(t, pkg.Expired(svc.Labels, time.Now().Add(time.Hour)), "expected the service to expire after 1h")
+		container := svc.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, "example.com/app:v1", container.Image)
+		assert.DeepEqual(t, []corev1.EnvVar{{Name: "INDEX", Value: "0"}}, container.Env)
+		// only the autoscaling flags which are set are added to the template
+		assert.DeepEqual(t, map[string]string{"autoscaling.knative.dev/target": "10"}, svc.Spec.Template.Annotations)
+
+		_, err = testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "1", "-b", "10", "-i", "1", "--namespace", "test-kperf-1", "--template", filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorContains(t, err, "failed to read service template")
+	})
+
 	t.Run("generate service as expected with namespace prefix flag", func(t *testing.T) {
 		ns1 := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
@@ -151,6 +405,63 @@ func TestNewServiceGenerateCommand(t *testing.T) {
 		assert.DeepEqual(t, targetAnnotations, resultAnnotations)
 	})
 
+	t.Run("generate service spread across namespaces by weight", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-kperf-1"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-kperf-2"}},
+		)
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceGenerateCommand(p), "-n", "8", "-b", "8", "-i", "100ms",
+			"--namespace-weights", "test-kperf-1:75,test-kperf-2:25")
+		assert.NilError(t, err)
+
+		// every fourth service is created in test-kperf-2
+		for i := 0; i < 8; i++ {
+			ns := "test-kperf-1"
+			if i%4 == 2 {
+				ns = "test-kperf-2"
+			}
+			_, err := fakeServing.Services(ns).Get(context.TODO(), fmt.Sprintf("ksvc-%d", i), metav1.GetOptions{})
+			assert.NilError(t, err, "ksvc-%d is not in %s", i, ns)
+		}
+	})
+
+	t.Run("generate service and wait for it to be ready", func(t *testing.T) {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-kperf-1"}}
+		client := k8sfake.NewSimpleClientset(ns)
+		// the services are ready as soon as they are created
+		client.PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			markServiceReady(action.(clienttesting.CreateAction).GetObject().(*servingv1.Service))
+			return false, nil, nil
+		})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		dir := t.TempDir()
+		cmd := NewServiceGenerateCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "-n", "2", "-b", "2", "-i", "10ms", "--namespace", "test-kperf-1", "--wait", "--timeout", "5s",
+			"--run-id", "demo", "--output", dir)
+		assert.NilError(t, err)
+		files, err := filepath.Glob(filepath.Join(dir, "demo", "*_"+GenerateReadyOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(files))
+		data, err := ioutil.ReadFile(files[0])
+		assert.NilError(t, err)
+		assert.Equal(t, 3, len(strings.Split(strings.TrimSpace(string(data)), "\n")))
+	})
+
 	t.Run("failed to generate service", func(t *testing.T) {
 		ns := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
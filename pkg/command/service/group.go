@@ -0,0 +1,126 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/measure"
+)
+
+// parseGroupBy parses --group-by into the dimension, one of GroupByNamespace, GroupByPrefix, GroupByNode and
+// GroupByLabel, and the label key of GroupByLabel
+func parseGroupBy(groupBy string) (string, string, error) {
+	switch {
+	case groupBy == "" || groupBy == GroupByNamespace || groupBy == GroupByPrefix || groupBy == GroupByNode:
+		return groupBy, "", nil
+	case strings.HasPrefix(groupBy, GroupByLabel+":") && len(groupBy) > len(GroupByLabel)+1:
+		return GroupByLabel, strings.TrimPrefix(groupBy, GroupByLabel+":"), nil
+	}
+	return "", "", fmt.Errorf("unsupported group-by %q, expected one of %s,%s,%s,%s:<key>", groupBy, GroupByNamespace, GroupByPrefix, GroupByNode, GroupByLabel)
+}
+
+// groupKey returns the key grouping the services by the dimension. The prefixes of --svc-prefix are the groups of
+// GroupByPrefix if there are several, otherwise the names are grouped by their prefix before the index.
+func groupKey(dimension, label string, prefixes []string, result *measure.Result) measure.GroupKey {
+	switch dimension {
+	case GroupByNamespace:
+		return measure.NamespaceKey
+	case GroupByNode:
+		return result.NodeKey()
+	case GroupByLabel:
+		return result.LabelKey(label)
+	}
+	if len(prefixes) > 1 {
+		return measure.PrefixKey(prefixes)
+	}
+	return measure.PrefixKey(nil)
+}
+
+// groupColumn returns the name of the group column of the CSV file of the dimension
+func groupColumn(dimension, label string) string {
+	if dimension == GroupByLabel {
+		return "label_" + label
+	}
+	return "svc_" + dimension
+}
+
+// writeGroups writes the counts, the percentiles of the overall ready duration and the averages of the phases of
+// every group
+func writeGroups(out io.Writer, groupBy string, groups []pkg.GroupMeasureResult) {
+	fmt.Fprintf(out, "\nMeasurement by %s:\n", groupBy)
+	for _, g := range groups {
+		deadlineExceeded := ""
+		if g.Service.ProgressDeadlineExceededCount > 0 {
+			deadlineExceeded = fmt.Sprintf(" ProgressDeadlineExceeded: %d", g.Service.ProgressDeadlineExceededCount)
+		}
+		if g.Service.TimedOutCount > 0 {
+			deadlineExceeded += fmt.Sprintf(" TimedOut: %d", g.Service.TimedOutCount)
+		}
+		fmt.Fprintf(out, "%s: Ready: %d NotReady: %d NotFound: %d Fail: %d%s | Average: %fs Percentile50: %fs Percentile95: %fs Percentile99: %fs\n",
+			g.Group, g.Service.ReadyCount, g.Service.NotReadyCount, g.Service.NotFoundCount, g.Service.FailCount,
+			deadlineExceeded, g.Result.OverallAverage, g.Result.P50, g.Result.P95, g.Result.P99)
+		if g.Service.ReadyCount > 0 {
+			fmt.Fprintf(out, "  Average Configuration: %fs Revision: %fs Deployment: %fs Pod Scheduled: %fs Containers Ready: %fs Route: %fs Ingress: %fs\n",
+				g.Result.AverageSvcConfigurationReadySum, g.Result.AverageRevisionReadySum, g.Result.AverageDeploymentCreatedSum,
+				g.Result.AveragePodScheduledSum, g.Result.AverageContainersReadySum, g.Result.AverageSvcRoutesReadySum,
+				g.Result.AverageIngressReadySum)
+		}
+	}
+}
+
+// groupRows returns the rows of the CSV file of the groups, with the counts, the percentiles of the overall ready
+// duration and the averages of the phases
+func groupRows(column string, groups []pkg.GroupMeasureResult) [][]string {
+	rows := [][]string{{column, "ready", "not_ready", "not_found", "fail", "progress_deadline_exceeded", "timed_out", "average", "p50", "p95", "p99",
+		"configuration_ready", "revision_ready", "deployment_created", "pod_scheduled", "containers_ready",
+		"queue-proxy_started", "user-container_started", "route_ready", "kpa_active", "sks_ready",
+		"sks_activator_endpoints_populated", "sks_endpoints_populated", "ingress_ready", "ingress_config_ready",
+		"ingress_lb_ready", "certificate_ready"}}
+	for _, g := range groups {
+		r := g.Result
+		row := []string{g.Group,
+			strconv.Itoa(g.Service.ReadyCount),
+			strconv.Itoa(g.Service.NotReadyCount),
+			strconv.Itoa(g.Service.NotFoundCount),
+			strconv.Itoa(g.Service.FailCount),
+			strconv.Itoa(g.Service.ProgressDeadlineExceededCount),
+			strconv.Itoa(g.Service.TimedOutCount),
+		}
+		for _, v := range []float64{r.OverallAverage, r.P50, r.P95, r.P99,
+			r.AverageSvcConfigurationReadySum, r.AverageRevisionReadySum, r.AverageDeploymentCreatedSum,
+			r.AveragePodScheduledSum, r.AverageContainersReadySum, r.AverageQueueProxyStartedSum,
+			r.AverageUserContrainerStartedSum, r.AverageSvcRoutesReadySum, r.AverageKpaActiveSum, r.AverageSksReadySum,
+			r.AverageSksActivatorEndpointsPopulatedSum, r.AverageSksEndpointsPopulatedSum, r.AverageIngressReadySum,
+			r.AverageIngressNetworkConfiguredSum, r.AverageIngressLoadBalancerReadySum, r.AverageCertificateReadySum} {
+			row = append(row, fmt.Sprintf("%f", v))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// serviceGroupRows returns the group of every ready service for the HTML report
+func serviceGroupRows(key measure.GroupKey, records []pkg.MeasureRecord) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "group"}}
+	for _, r := range records {
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace, key(r.ServiceNamespace, r.ServiceName)})
+	}
+	return rows
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/measure"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	for _, tc := range []struct {
+		groupBy   string
+		dimension string
+		label     string
+	}{
+		{"", "", ""},
+		{"namespace", GroupByNamespace, ""},
+		{"prefix", GroupByPrefix, ""},
+		{"node", GroupByNode, ""},
+		{"label:team", GroupByLabel, "team"},
+		{"label:app.kubernetes.io/part-of", GroupByLabel, "app.kubernetes.io/part-of"},
+	} {
+		dimension, label, err := parseGroupBy(tc.groupBy)
+		assert.NilError(t, err)
+		assert.Equal(t, tc.dimension, dimension)
+		assert.Equal(t, tc.label, label)
+	}
+	for _, groupBy := range []string{"zone", "label", "label:"} {
+		_, _, err := parseGroupBy(groupBy)
+		assert.ErrorContains(t, err, "unsupported group-by")
+	}
+}
+
+func TestGroupOutput(t *testing.T) {
+	groups := []pkg.GroupMeasureResult{
+		{Group: "a", Service: pkg.ServiceCount{ReadyCount: 1},
+			Result: pkg.Result{OverallAverage: 2, P50: 2, P95: 2, P99: 2, AverageSvcConfigurationReadySum: 1}},
+		{Group: measure.NoGroup, Service: pkg.ServiceCount{NotFoundCount: 1}},
+	}
+
+	out := &bytes.Buffer{}
+	writeGroups(out, "label:team", groups)
+	assert.Equal(t, `
+Measurement by label:team:
+a: Ready: 1 NotReady: 0 NotFound: 0 Fail: 0 | Average: 2.000000s Percentile50: 2.000000s Percentile95: 2.000000s Percentile99: 2.000000s
+  Average Configuration: 1.000000s Revision: 0.000000s Deployment: 0.000000s Pod Scheduled: 0.000000s Containers Ready: 0.000000s Route: 0.000000s Ingress: 0.000000s
+<none>: Ready: 0 NotReady: 0 NotFound: 1 Fail: 0 | Average: 0.000000s Percentile50: 0.000000s Percentile95: 0.000000s Percentile99: 0.000000s
+`, out.String())
+
+	rows := groupRows(groupColumn(GroupByLabel, "team"), groups)
+	assert.Equal(t, 3, len(rows))
+	assert.DeepEqual(t, []string{"label_team", "ready", "not_ready", "not_found", "fail", "progress_deadline_exceeded", "timed_out", "average", "p50", "p95", "p99"}, rows[0][:11])
+	assert.DeepEqual(t, []string{"a", "1", "0", "0", "0", "0", "0", "2.000000", "2.000000", "2.000000", "2.000000", "1.000000"}, rows[1][:12])
+	assert.Equal(t, len(rows[0]), len(rows[1]))
+	assert.Equal(t, "svc_namespace", groupColumn(GroupByNamespace, ""))
+}
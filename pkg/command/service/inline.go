@@ -0,0 +1,289 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/report"
+)
+
+// GenerateInlineOutputFilename is the name of the files of the ready durations measured with generate --measure-inline
+const GenerateInlineOutputFilename = "ksvc_generate_inline_ready_time"
+
+// inlineKey is the namespace and the name of a generated service
+type inlineKey struct {
+	namespace string
+	name      string
+}
+
+// inlineObservation is a generated service seen ready by the watch. Status is the duration from the creation
+// timestamp until the last transition of the Ready condition, both set by the cluster with a resolution of a second.
+type inlineObservation struct {
+	readyAt time.Time
+	status  time.Duration
+}
+
+// inlineRecorder measures the readiness of the generated services from watches started before the first service is
+// created. The wall-clock duration is taken between the Create call and the watch event which reports the service
+// ready, so that it is known as it happens instead of being reconstructed from the status timestamps later.
+type inlineRecorder struct {
+	clk      clock.PassiveClock
+	runID    string
+	mu       sync.Mutex
+	created  map[inlineKey]time.Time
+	observed map[inlineKey]inlineObservation
+	changed  chan struct{}
+}
+
+func newInlineRecorder(clk clock.PassiveClock, runID string) *inlineRecorder {
+	return &inlineRecorder{
+		clk:      clk,
+		runID:    runID,
+		created:  map[inlineKey]time.Time{},
+		observed: map[inlineKey]inlineObservation{},
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+// start watches the services of the run in the namespaces until the context is done. The watches are established
+// before start returns. A watch which ends is restarted from a list of the services, the ones which became ready in
+// between are recorded when they are listed.
+func (r *inlineRecorder) start(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespaces []string) error {
+	selector := labels.SelectorFromSet(labels.Set{pkg.RunIDLabel: r.runID}).String()
+	for _, ns := range namespaces {
+		watcher, err := ksvcClient.Services(ns).Watch(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return fmt.Errorf("failed to watch the Knative Services in namespace %s: %w", ns, err)
+		}
+		go r.watch(ctx, ksvcClient, ns, selector, watcher)
+	}
+	return nil
+}
+
+func (r *inlineRecorder) watch(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, selector string, watcher watch.Interface) {
+	for {
+		r.consume(ctx, watcher.ResultChan())
+		watcher.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(updatePollInterval):
+		}
+		svcList, err := ksvcClient.Services(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			watcher = watch.NewEmptyWatch()
+			continue
+		}
+		for i := range svcList.Items {
+			r.observe(&svcList.Items[i])
+		}
+		watcher, err = ksvcClient.Services(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector, ResourceVersion: svcList.ResourceVersion})
+		if err != nil {
+			watcher = watch.NewEmptyWatch()
+		}
+	}
+}
+
+func (r *inlineRecorder) consume(ctx context.Context, events <-chan watch.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if svc, ok := event.Object.(*servingv1.Service); ok {
+				r.observe(svc)
+			}
+		}
+	}
+}
+
+// create records the start of the Create call of the service
+func (r *inlineRecorder) create(namespace, name string, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.created[inlineKey{namespace, name}] = start
+}
+
+// observe records the first time the service of the run is seen ready
+func (r *inlineRecorder) observe(svc *servingv1.Service) {
+	if svc.Labels[pkg.RunIDLabel] != r.runID || !svc.IsReady() {
+		return
+	}
+	now := r.clk.Now()
+	key := inlineKey{svc.Namespace, svc.Name}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.observed[key]; ok {
+		return
+	}
+	var status time.Duration
+	if c := svc.Status.GetCondition(apis.ConditionReady); c != nil && !c.LastTransitionTime.Inner.IsZero() {
+		status = c.LastTransitionTime.Inner.Sub(svc.CreationTimestamp.Time)
+	}
+	r.observed[key] = inlineObservation{readyAt: now, status: status}
+	select {
+	case r.changed <- struct{}{}:
+	default:
+	}
+}
+
+// pending returns the number of created services which weren't seen ready yet
+func (r *inlineRecorder) pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for key := range r.created {
+		if _, ok := r.observed[key]; !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// wait waits until every created service was seen ready, and returns an error when some aren't within the timeout
+func (r *inlineRecorder) wait(timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for {
+		n := r.pending()
+		if n == 0 {
+			return nil
+		}
+		select {
+		case <-r.changed:
+		case <-deadline:
+			return fmt.Errorf("%d Knative Service(s) are not ready after %s", n, timeout)
+		}
+	}
+}
+
+// records returns the ready services sorted by namespace and name
+func (r *inlineRecorder) records() []pkg.InlineReadyMeasurement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	measurements := make([]pkg.InlineReadyMeasurement, 0, len(r.observed))
+	for key, created := range r.created {
+		o, ok := r.observed[key]
+		if !ok {
+			continue
+		}
+		watched := o.readyAt.Sub(created)
+		measurements = append(measurements, pkg.InlineReadyMeasurement{
+			ServiceName:      key.name,
+			ServiceNamespace: key.namespace,
+			Watched:          watched.Seconds(),
+			Status:           o.status.Seconds(),
+			Difference:       (o.status - watched).Seconds(),
+		})
+	}
+	sort.Slice(measurements, func(i, j int) bool {
+		if measurements[i].ServiceNamespace != measurements[j].ServiceNamespace {
+			return measurements[i].ServiceNamespace < measurements[j].ServiceNamespace
+		}
+		return measurements[i].ServiceName < measurements[j].ServiceName
+	})
+	return measurements
+}
+
+// result returns the measurements of the ready services with the statistics of both views and of their difference
+func (r *inlineRecorder) result() pkg.InlineReadyResult {
+	measurements := r.records()
+	result := pkg.InlineReadyResult{Ready: len(measurements), NotReady: r.pending(), Measurement: measurements}
+	watched := make([]float64, 0, len(measurements))
+	status := make([]float64, 0, len(measurements))
+	difference := make([]float64, 0, len(measurements))
+	for _, m := range measurements {
+		watched = append(watched, m.Watched)
+		status = append(status, m.Status)
+		difference = append(difference, m.Difference)
+	}
+	result.Watched = inlineStats(watched)
+	result.Status = inlineStats(status)
+	result.Difference = inlineStats(difference)
+	return result
+}
+
+func inlineStats(durations []float64) pkg.InlineReadyStats {
+	if len(durations) == 0 {
+		return pkg.InlineReadyStats{}
+	}
+	s := pkg.InlineReadyStats{}
+	s.Average, _ = stats.Mean(durations)
+	s.P50, _ = stats.Percentile(durations, 50)
+	s.P95, _ = stats.Percentile(durations, 95)
+	s.Min, _ = stats.Min(durations)
+	s.Max, _ = stats.Max(durations)
+	return s
+}
+
+// writeInline writes the statistics of the watched and the status durations side by side with their difference.
+// A positive difference means the status timestamps report the service ready later than it was seen ready.
+func writeInline(out io.Writer, result pkg.InlineReadyResult) {
+	fmt.Fprintf(out, "\nInline Ready Measurement:\n")
+	fmt.Fprintf(out, "Ready: %d Not Ready: %d\n", result.Ready, result.NotReady)
+	if result.Ready == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%-12s %12s %12s %12s %12s %12s\n", "", "Average", "Percentile50", "Percentile95", "Min", "Max")
+	for _, row := range []struct {
+		name string
+		s    pkg.InlineReadyStats
+	}{
+		{"Watched", result.Watched},
+		{"Status", result.Status},
+		{"Difference", result.Difference},
+	} {
+		fmt.Fprintf(out, "%-12s %11.3fs %11.3fs %11.3fs %11.3fs %11.3fs\n", row.name, row.s.Average, row.s.P50, row.s.P95, row.s.Min, row.s.Max)
+	}
+}
+
+// saveInline writes the watched and the status durations of every service side by side in the formats to the report
+// files in the output location of the run
+func saveInline(out io.Writer, outputOptions pkg.OutputOptions, output, runID string, formats []string, current time.Time, result pkg.InlineReadyResult) error {
+	outputLocation, err := utils.RunOutputLocation(output, runID)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{"svc_name", "svc_namespace", "watched", "status", "difference"}}
+	for _, m := range result.Measurement {
+		rows = append(rows, []string{m.ServiceName, m.ServiceNamespace, fmt.Sprintf("%f", m.Watched),
+			fmt.Sprintf("%f", m.Status), fmt.Sprintf("%f", m.Difference)})
+	}
+	return report.Write(out, outputLocation, current.Format(utils.DateFormatString), outputOptions, report.Report{
+		Name:    GenerateInlineOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: formats,
+	})
+}
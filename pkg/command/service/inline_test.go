@@ -0,0 +1,153 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func inlineService(namespace, name, runID string, created, transition time.Time) *servingv1.Service {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace,
+		Labels: map[string]string{pkg.RunIDLabel: runID}, CreationTimestamp: metav1.NewTime(created)}}
+	svc.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue,
+		LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(transition)}}}
+	return svc
+}
+
+func TestInlineRecorder(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 500000000, time.UTC)
+	clk := clock.NewFakeClock(start)
+	recorder := newInlineRecorder(clk, "demo")
+	recorder.create("ns-1", "ksvc-1", start)
+	recorder.create("ns-1", "ksvc-2", start)
+	recorder.create("ns-2", "ksvc-1", start)
+
+	// the status timestamps have a resolution of a second
+	created := start.Truncate(time.Second)
+	clk.SetTime(start.Add(2 * time.Second))
+	recorder.observe(inlineService("ns-2", "ksvc-1", "demo", created, created.Add(3*time.Second)))
+	clk.SetTime(start.Add(4 * time.Second))
+	recorder.observe(inlineService("ns-1", "ksvc-1", "demo", created, created.Add(4*time.Second)))
+	// seen again later and services of other runs or not ready are ignored
+	clk.SetTime(start.Add(10 * time.Second))
+	recorder.observe(inlineService("ns-1", "ksvc-1", "demo", created, created.Add(4*time.Second)))
+	recorder.observe(inlineService("ns-1", "ksvc-2", "other", created, created.Add(4*time.Second)))
+	notReady := inlineService("ns-1", "ksvc-2", "demo", created, created)
+	notReady.Status.Conditions[0].Status = corev1.ConditionUnknown
+	recorder.observe(notReady)
+
+	assert.Equal(t, 1, recorder.pending())
+	assert.ErrorContains(t, recorder.wait(10*time.Millisecond), "1 Knative Service(s) are not ready after 10ms")
+
+	result := recorder.result()
+	assert.Equal(t, 2, result.Ready)
+	assert.Equal(t, 1, result.NotReady)
+	assert.DeepEqual(t, []pkg.InlineReadyMeasurement{
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Watched: 4, Status: 4, Difference: 0},
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-2", Watched: 2, Status: 3, Difference: 1},
+	}, result.Measurement)
+	assert.Equal(t, 3.0, result.Watched.Average)
+	assert.Equal(t, 3.5, result.Status.Average)
+	assert.Equal(t, 1.0, result.Difference.Max)
+
+	out := &bytes.Buffer{}
+	writeInline(out, result)
+	assert.Assert(t, strings.Contains(out.String(), "Ready: 2 Not Ready: 1"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "Watched            3.000s"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "Status             3.500s"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "Difference         0.500s"), out.String())
+
+	recorder.observe(inlineService("ns-1", "ksvc-2", "demo", created, created.Add(12*time.Second)))
+	assert.NilError(t, recorder.wait(10*time.Millisecond))
+}
+
+func TestGenerateMeasureInline(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-kperf-1"}}
+	client := k8sfake.NewSimpleClientset(ns)
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	// the services become ready shortly after they are created
+	client.PrependReactor("create", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		svc := action.(clienttesting.CreateAction).GetObject().(*servingv1.Service).DeepCopy()
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			markServiceReady(svc)
+			_, _ = fakeServing.Services(svc.Namespace).UpdateStatus(context.Background(), svc, metav1.UpdateOptions{})
+		}()
+		return false, nil, nil
+	})
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+	}
+
+	dir := t.TempDir()
+	cmd := NewServiceGenerateCommand(p)
+	_, err := testutil.ExecuteCommand(cmd, "-n", "2", "-b", "2", "-i", "10ms", "--namespace", "test-kperf-1", "--measure-inline",
+		"--timeout", "5s", "--run-id", "demo", "--output", dir)
+	assert.NilError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "demo", "*_"+GenerateInlineOutputFilename+".json"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(files))
+	data, err := ioutil.ReadFile(files[0])
+	assert.NilError(t, err)
+	result := pkg.InlineReadyResult{}
+	assert.NilError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, 2, result.Ready)
+	assert.Equal(t, 2, len(result.Measurement))
+	for _, m := range result.Measurement {
+		assert.Assert(t, m.Watched > 0, m)
+	}
+
+	t.Run("services not ready within the timeout", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(ns)
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+		cmd := NewServiceGenerateCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "-n", "1", "-b", "1", "-i", "10ms", "--namespace", "test-kperf-1", "--measure-inline",
+			"--timeout", "50ms", "--run-id", "demo", "--output", t.TempDir())
+		assert.ErrorContains(t, err, "1 Knative Service(s) are not ready after 50ms")
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+)
+
+// junitSuites returns a test suite with a test case per measured service, which fails if the service is not
+// ready, and a test suite with a test case per threshold if thresholds are given
+func junitSuites(inputs pkg.MeasureArgs, result *measure.Result) (utils.JUnitTestSuites, error) {
+	checks, err := thresholdChecks(inputs, result.Summary)
+	if err != nil {
+		return utils.JUnitTestSuites{}, err
+	}
+
+	readyTimes := make(map[string]float64, len(result.Records))
+	for _, r := range result.Records {
+		readyTimes[r.ServiceNamespace+"/"+r.ServiceName] = r.OverallReady
+	}
+	failures := make(map[string]pkg.ServiceFailure, len(result.Summary.Failures))
+	for _, f := range result.Summary.Failures {
+		failures[f.ServiceNamespace+"/"+f.ServiceName] = f
+	}
+	names := make([]string, 0, len(result.States))
+	for name := range result.States {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	services := utils.JUnitTestSuite{Name: "ksvc_creation"}
+	for _, name := range names {
+		// the class name is the namespace, so that the services are grouped by namespace
+		parts := strings.SplitN(name, "/", 2)
+		tc := utils.JUnitTestCase{ClassName: parts[0], Name: parts[len(parts)-1], Time: readyTimes[name]}
+		if state := result.States[name]; state != measure.StateReady {
+			tc.Failure = &utils.JUnitFailure{Message: state, Text: fmt.Sprintf("service %s is %s", name, state)}
+			if f, ok := failures[name]; ok {
+				tc.Failure.Text = fmt.Sprintf("service %s is %s, %s: %s", name, state, f.Reason, f.Message)
+			}
+		}
+		services.TestCases = append(services.TestCases, tc)
+	}
+	suites := utils.JUnitTestSuites{Suites: []utils.JUnitTestSuite{services}}
+	if len(checks) == 0 {
+		return suites, nil
+	}
+
+	thresholds := utils.JUnitTestSuite{Name: "ksvc_creation_thresholds"}
+	for _, c := range checks {
+		tc := utils.JUnitTestCase{ClassName: "thresholds", Name: fmt.Sprintf("%s <= %s", c.name, c.max)}
+		switch {
+		case result.Summary.Service.ReadyCount == 0:
+			tc.Failure = &utils.JUnitFailure{Message: "no service is ready", Text: "thresholds can't be checked, no service is ready"}
+		case c.exceeded():
+			tc.Failure = &utils.JUnitFailure{Message: "threshold exceeded", Text: c.String()}
+		}
+		thresholds.TestCases = append(thresholds.TestCases, tc)
+	}
+	suites.Suites = append(suites.Suites, thresholds)
+	return suites, nil
+}
+
+// writeJUnit writes the measured services and the thresholds as JUnit XML file
+func writeJUnit(out io.Writer, outputOptions pkg.OutputOptions, output string, current time.Time, inputs pkg.MeasureArgs, result *measure.Result) error {
+	outputLocation, err := utils.CheckOutputLocation(output)
+	if err != nil {
+		fmt.Fprintf(out, "failed to check measure output location: %s\n", err)
+	}
+	suites, err := junitSuites(inputs, result)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(outputLocation, utils.OutputFileName(outputOptions, current.Format(utils.DateFormatString), "ksvc_creation_time_junit.xml"))
+	if err := utils.GenerateJUnitFile(path, suites, outputOptions.Overwrite); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Measurement saved in JUnit file %s\n", path)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+)
+
+func TestJUnitSuites(t *testing.T) {
+	result := &measure.Result{
+		Summary: pkg.MeasureResult{
+			Service: pkg.ServiceCount{ReadyCount: 1, NotReadyCount: 1},
+			Result:  pkg.Result{OverallAverage: 20, P95: 20},
+		},
+		Records: []pkg.MeasureRecord{{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", OverallReady: 20}},
+		States:  map[string]string{"ns-1/ksvc-2": measure.StateNotReady, "ns-1/ksvc-1": measure.StateReady},
+	}
+
+	t.Run("a test case per service", func(t *testing.T) {
+		suites, err := junitSuites(pkg.MeasureArgs{}, result)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []utils.JUnitTestSuite{{
+			Name: "ksvc_creation",
+			TestCases: []utils.JUnitTestCase{
+				{ClassName: "ns-1", Name: "ksvc-1", Time: 20},
+				{ClassName: "ns-1", Name: "ksvc-2", Failure: &utils.JUnitFailure{Message: "not_ready", Text: "service ns-1/ksvc-2 is not_ready"}},
+			},
+		}}, suites.Suites)
+	})
+
+	t.Run("a test case per threshold", func(t *testing.T) {
+		suites, err := junitSuites(pkg.MeasureArgs{MaxAvgReady: 30 * time.Second, MaxP95Ready: 10 * time.Second}, result)
+		assert.NilError(t, err)
+		assert.Equal(t, 2, len(suites.Suites))
+		assert.DeepEqual(t, utils.JUnitTestSuite{
+			Name: "ksvc_creation_thresholds",
+			TestCases: []utils.JUnitTestCase{
+				{ClassName: "thresholds", Name: "average ready <= 30s"},
+				{ClassName: "thresholds", Name: "p95 ready <= 10s", Failure: &utils.JUnitFailure{Message: "threshold exceeded", Text: "p95 ready 20.000000s > 10s"}},
+			},
+		}, suites.Suites[1])
+	})
+
+	t.Run("thresholds fail without ready services", func(t *testing.T) {
+		suites, err := junitSuites(pkg.MeasureArgs{MaxAvgReady: 30 * time.Second}, &measure.Result{States: map[string]string{"ns-1/ksvc-1": measure.StateNotFound}})
+		assert.NilError(t, err)
+		assert.Equal(t, "no service is ready", suites.Suites[1].TestCases[0].Failure.Message)
+	})
+}
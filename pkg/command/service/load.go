@@ -0,0 +1,496 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const (
+	LoadOutputFilename = "ksvc_load"
+)
+
+// loadSample is a single request sent during the load
+type loadSample struct {
+	latency  float64
+	replicas int
+	failed   bool
+	// offset is the time since the start of the load when the request finished in seconds
+	offset float64
+}
+
+func NewServiceLoadCommand(p *pkg.PerfParams) *cobra.Command {
+	loadArgs := pkg.LoadArgs{}
+	serviceLoadCommand := &cobra.Command{
+		Use:   "load",
+		Short: "Generate HTTP load against Knative services",
+		Long: `Send HTTP requests to Knative services for a duration and measure the request latencies
+
+Each service gets its own connections and request rate. The ready replicas of the service deployment are watched
+during the load, so the latencies are also reported for each replica count the service was scaled to.
+
+The pods of the service are watched as well. Every pod terminated during the load is a disruption, classified as
+eviction, preemption or deletion, e.g. when scaling down. The failed requests which finished within
+--disruption-window after a disruption are attributed to it, to report the errors per disruption for tuning
+PodDisruptionBudgets and the terminationGracePeriodSeconds of the services.
+
+With --validate-max-scale the load is expected to drive the services beyond their max-scale. The most ready and
+desired replicas of every service are checked against its max-scale, and the P95 latency of the requests served at
+the cap is compared with the one below it. The command fails if a service exceeded its max-scale.
+
+For example:
+# To send 100 requests per second for 1 minute to each Knative Service with prefix svc in namespace ns
+kperf service load --svc-prefix svc --namespace ns --qps 100 --duration 1m --connections 10
+
+# To validate that the Knative Services with prefix svc in namespace ns don't scale beyond their max-scale
+kperf service load --svc-prefix svc --namespace ns --qps 0 --duration 3m --connections 50 --validate-max-scale
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'service load' requires flag(s)")
+			}
+			if loadArgs.Connections < 1 {
+				return fmt.Errorf("connections must be at least 1")
+			}
+			if loadArgs.QPS < 0 {
+				return fmt.Errorf("qps must not be negative")
+			}
+			if loadArgs.Duration <= 0 {
+				return fmt.Errorf("duration must be greater than 0")
+			}
+			if loadArgs.DisruptionWindow <= 0 {
+				return fmt.Errorf("--disruption-window must be greater than 0")
+			}
+			return pkg.ValidateRunID(loadArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return LoadServices(p, loadArgs)
+		},
+	}
+
+	serviceLoadCommand.Flags().StringVarP(&loadArgs.Namespace, "namespace", "", "", "Service namespace")
+	serviceLoadCommand.Flags().StringVarP(&loadArgs.SvcPrefix, "svc-prefix", "", "", "Service name prefix")
+	serviceLoadCommand.Flags().BoolVarP(&loadArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	serviceLoadCommand.Flags().StringVarP(&loadArgs.NamespaceRange, "namespace-range", "", "", "Service namespace range")
+	serviceLoadCommand.Flags().StringVarP(&loadArgs.NamespacePrefix, "namespace-prefix", "", "", "Service namespace prefix")
+	serviceLoadCommand.Flags().IntVarP(&loadArgs.QPS, "qps", "", 10, "Requests per second sent to each service, 0 sends requests as fast as possible")
+	serviceLoadCommand.Flags().DurationVarP(&loadArgs.Duration, "duration", "", 30*time.Second, "Duration of the load")
+	serviceLoadCommand.Flags().IntVarP(&loadArgs.Connections, "connections", "", 1, "Number of concurrent connections to each service")
+	serviceLoadCommand.Flags().StringVarP(&loadArgs.Payload, "payload", "", "", "Request body, the requests are sent with POST if set and GET otherwise")
+	serviceLoadCommand.Flags().DurationVarP(&loadArgs.RequestTimeout, "timeout", "", 30*time.Second, "Timeout of a single request")
+	serviceLoadCommand.Flags().StringVarP(&loadArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(serviceLoadCommand.Flags(), &loadArgs.OutputFormats)
+	serviceLoadCommand.Flags().StringVarP(&loadArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	serviceLoadCommand.Flags().BoolVarP(&loadArgs.ResolvableDomain, "resolvable", "", false, "If Service endpoint resolvable url")
+	serviceLoadCommand.Flags().DurationVarP(&loadArgs.DisruptionWindow, "disruption-window", "", 10*time.Second, "Time after a pod of the service was terminated in which failed requests are attributed to its disruption")
+	serviceLoadCommand.Flags().BoolVarP(&loadArgs.ValidateMaxScale, "validate-max-scale", "", false, "Validate that the replicas of the services stay within their max-scale under the load, and report the latency penalty at the cap. Services without max-scale are not validated")
+	return serviceLoadCommand
+}
+
+// LoadServices sends HTTP load to Knative Services and measures the latencies of the requests
+func LoadServices(params *pkg.PerfParams, inputs pkg.LoadArgs) error {
+	ctx := context.Background()
+	nsNameList, err := GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	objs := getServices(ctx, ksvcClient, nsNameList, inputs.SvcPrefix)
+	if len(objs) == 0 {
+		return fmt.Errorf("no service found to load")
+	}
+
+	fmt.Printf("Sending load to %d service(s) for %s\n", len(objs), inputs.Duration)
+	result := pkg.LoadResult{}
+	// all the services are loaded at the same time
+	var m sync.Mutex
+	pool.ForEach(ctx, len(objs), len(objs), func(ctx context.Context, i int) {
+		obj := objs[i]
+		measurement, err := runLoad(ctx, params, inputs, obj.Namespace, obj.Service)
+		if err != nil {
+			fmt.Printf("failed to load service %s/%s and skip: %s\n", obj.Namespace, obj.Service.Name, err)
+			return
+		}
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Service %s: %d requests, %d errors, P50 %fs, P95 %fs, P99 %fs\n", measurement.ServiceName,
+				measurement.Requests, measurement.Errors, measurement.P50, measurement.P95, measurement.P99)
+			for _, r := range measurement.ReplicaLatencies {
+				fmt.Printf("[Verbose] Service %s: - %d ready replica(s): %d requests, P50 %fs, P95 %fs\n", measurement.ServiceName,
+					r.ReadyReplicas, r.Requests, r.P50, r.P95)
+			}
+			for _, d := range measurement.Disruptions {
+				fmt.Printf("[Verbose] Service %s: - %s of pod %s after %fs: %d errors\n", measurement.ServiceName,
+					d.Cause, d.Pod, d.Offset, d.Errors)
+			}
+			if v := measurement.MaxScale; v != nil {
+				fmt.Printf("[Verbose] Service %s: - max-scale %d, max ready replicas %d, max desired replicas %d, P95 below cap %fs, at cap %fs\n",
+					measurement.ServiceName, v.MaxScale, v.MaxReadyReplicas, v.MaxDesiredReplicas, v.BelowCapP95, v.AtCapP95)
+			}
+		}
+		m.Lock()
+		result.Measurement = append(result.Measurement, measurement)
+		m.Unlock()
+	})
+
+	sort.Slice(result.Measurement, func(i, j int) bool {
+		if result.Measurement[i].ServiceNamespace != result.Measurement[j].ServiceNamespace {
+			return result.Measurement[i].ServiceNamespace < result.Measurement[j].ServiceNamespace
+		}
+		return result.Measurement[i].ServiceName < result.Measurement[j].ServiceName
+	})
+
+	result.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	rows := [][]string{{"svc_name", "svc_namespace", "requests", "errors", "min", "mean", "p50", "p90", "p95", "p99", "max",
+		"scale_events", "max_ready_replicas", "disruptions", "disruption_errors", "errors_per_disruption"}}
+	disruptions, disruptionErrors := 0, 0
+	for _, r := range result.Measurement {
+		maxReplicas := 0
+		for _, l := range r.ReplicaLatencies {
+			if l.ReadyReplicas > maxReplicas {
+				maxReplicas = l.ReadyReplicas
+			}
+		}
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace,
+			fmt.Sprintf("%d", r.Requests),
+			fmt.Sprintf("%d", r.Errors),
+			fmt.Sprintf("%f", r.Min),
+			fmt.Sprintf("%f", r.Mean),
+			fmt.Sprintf("%f", r.P50),
+			fmt.Sprintf("%f", r.P90),
+			fmt.Sprintf("%f", r.P95),
+			fmt.Sprintf("%f", r.P99),
+			fmt.Sprintf("%f", r.Max),
+			fmt.Sprintf("%d", len(r.ScaleEvents)),
+			fmt.Sprintf("%d", maxReplicas),
+			fmt.Sprintf("%d", len(r.Disruptions)),
+			fmt.Sprintf("%d", r.DisruptionErrors),
+			fmt.Sprintf("%f", r.ErrorsPerDisruption),
+		})
+		disruptions += len(r.Disruptions)
+		disruptionErrors += r.DisruptionErrors
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Load Measurement:\n")
+	fmt.Printf("Total: %d | Measured: %d Failed: %d\n", len(objs), len(result.Measurement), len(objs)-len(result.Measurement))
+	if disruptions > 0 {
+		fmt.Printf("Disruptions: %d | Errors: %d Errors per disruption: %f\n", disruptions, disruptionErrors,
+			float64(disruptionErrors)/float64(disruptions))
+	}
+	exceeded := 0
+	if inputs.ValidateMaxScale {
+		exceeded = writeMaxScaleSummary(os.Stdout, result.Measurement)
+	}
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+
+	if err := report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    LoadOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	}); err != nil {
+		return err
+	}
+
+	if inputs.ValidateMaxScale && report.Enabled(inputs.OutputFormats, report.FormatCSV) {
+		maxScalePath := filepath.Join(outputLocation, utils.OutputFileName(params.Output, current.Format(utils.DateFormatString), MaxScaleOutputFilename+".csv"))
+		if err := utils.GenerateCSVFile(maxScalePath, maxScaleRows(result.Measurement), params.Output.Overwrite); err != nil {
+			return err
+		}
+		fmt.Printf("Max-scale validation saved in CSV file %s\n", maxScalePath)
+		if exceeded > 0 {
+			return fmt.Errorf("%d service(s) exceeded their max-scale", exceeded)
+		}
+	}
+	return nil
+}
+
+// runLoad sends requests to a single service for the load duration while watching the ready replicas
+// of its deployment, so each request can be attributed to the replica count it was served with
+func runLoad(ctx context.Context, params *pkg.PerfParams, inputs pkg.LoadArgs, namespace string, svc *servingv1.Service) (pkg.ServiceLoadResult, error) {
+	measurement := pkg.ServiceLoadResult{ServiceName: svc.Name, ServiceNamespace: namespace}
+	maxScale := 0
+	if inputs.ValidateMaxScale {
+		var err error
+		maxScale, err = serviceMaxScale(svc)
+		if err != nil {
+			return measurement, err
+		}
+		if maxScale == 0 {
+			fmt.Printf("service %s/%s has no max-scale and skip its validation\n", namespace, svc.Name)
+		}
+	}
+	endpoint, err := ResolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
+	if err != nil {
+		return measurement, fmt.Errorf("failed to get the cluster endpoint: %w", err)
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{
+		serving.ServiceLabelKey: svc.Name,
+	}).String()
+	deployments, err := params.ClientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	// replicas is -1 until the deployment of the service is observed
+	replicas, maxReady, maxDesired := -1, 0, 0
+	observeDesired := func(deployment *appsv1.Deployment) {
+		if deployment.Spec.Replicas != nil && int(*deployment.Spec.Replicas) > maxDesired {
+			maxDesired = int(*deployment.Spec.Replicas)
+		}
+	}
+	if len(deployments.Items) > 0 {
+		replicas = int(deployments.Items[0].Status.ReadyReplicas)
+		maxReady = replicas
+		observeDesired(&deployments.Items[0])
+	}
+	initialReady := replicas
+	watcher, err := params.ClientSet.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:   selector,
+		ResourceVersion: deployments.ResourceVersion,
+	})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to watch deployments: %w", err)
+	}
+	defer watcher.Stop()
+
+	pods, err := params.ClientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to list pods: %w", err)
+	}
+	podWatcher, err := params.ClientSet.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:   selector,
+		ResourceVersion: pods.ResourceVersion,
+	})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to watch pods: %w", err)
+	}
+	defer podWatcher.Stop()
+
+	start := time.Now()
+	var m sync.Mutex
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		for event := range watcher.ResultChan() {
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			deployment, ok := event.Object.(*appsv1.Deployment)
+			if !ok {
+				continue
+			}
+			m.Lock()
+			observeDesired(deployment)
+			ready := int(deployment.Status.ReadyReplicas)
+			if ready > maxReady {
+				maxReady = ready
+			}
+			if ready != replicas {
+				if replicas >= 0 {
+					measurement.ScaleEvents = append(measurement.ScaleEvents, pkg.ScaleEvent{
+						Offset:        time.Since(start).Seconds(),
+						ReadyReplicas: ready,
+					})
+				}
+				replicas = ready
+			}
+			m.Unlock()
+		}
+	}()
+	podWatchDone := make(chan struct{})
+	go func() {
+		defer close(podWatchDone)
+		disruptions := newPodDisruptions(pods.Items)
+		for event := range podWatcher.ResultChan() {
+			disruption, ok := disruptions.observe(event)
+			if !ok {
+				continue
+			}
+			m.Lock()
+			disruption.Offset = time.Since(start).Seconds()
+			measurement.Disruptions = append(measurement.Disruptions, disruption)
+			m.Unlock()
+		}
+	}()
+
+	method := http.MethodGet
+	if inputs.Payload != "" {
+		method = http.MethodPost
+	}
+	client := http.Client{Timeout: inputs.RequestTimeout}
+	loadCtx, cancel := context.WithTimeout(ctx, inputs.Duration)
+	defer cancel()
+
+	// with a QPS the requests are paced by a ticker, otherwise each connection sends as fast as possible
+	var tokens chan struct{}
+	if inputs.QPS > 0 {
+		tokens = make(chan struct{}, inputs.Connections)
+		go func() {
+			ticker := time.NewTicker(time.Second / time.Duration(inputs.QPS))
+			defer ticker.Stop()
+			defer close(tokens)
+			for {
+				select {
+				case <-loadCtx.Done():
+					return
+				case <-ticker.C:
+					select {
+					case tokens <- struct{}{}:
+					default:
+						// all connections are busy, the request is dropped as the rate cannot be kept
+					}
+				}
+			}
+		}()
+	}
+
+	var samples []loadSample
+	pool.ForEach(loadCtx, inputs.Connections, inputs.Connections, func(ctx context.Context, i int) {
+		for {
+			if tokens != nil {
+				if _, ok := <-tokens; !ok {
+					return
+				}
+			} else if loadCtx.Err() != nil {
+				return
+			}
+			sample, err := sendLoadRequest(loadCtx, client, method, endpoint, svc, inputs.Payload)
+			if loadCtx.Err() != nil {
+				// requests interrupted by the end of the load are not counted
+				return
+			}
+			if err != nil && inputs.Verbose {
+				fmt.Printf("[Verbose] Service %s: request failed: %s\n", svc.Name, err)
+			}
+			m.Lock()
+			sample.replicas = replicas
+			sample.offset = time.Since(start).Seconds()
+			samples = append(samples, sample)
+			m.Unlock()
+		}
+	})
+	watcher.Stop()
+	<-watchDone
+	podWatcher.Stop()
+	<-podWatchDone
+
+	summarizeLoad(&measurement, samples)
+	attributeDisruptionErrors(&measurement, samples, inputs.DisruptionWindow)
+	if maxScale > 0 {
+		measurement.MaxScale = validateMaxScale(maxScale, maxReady, maxDesired, measurement.ScaleEvents, initialReady, samples)
+	}
+	return measurement, nil
+}
+
+// sendLoadRequest sends a single request to the service and returns its latency in seconds
+func sendLoadRequest(ctx context.Context, client http.Client, method, endpoint string, svc *servingv1.Service, payload string) (loadSample, error) {
+	sample := loadSample{failed: true}
+	var body io.Reader
+	if payload != "" {
+		body = strings.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return sample, err
+	}
+	if svc.Status.URL != nil {
+		req.Host = svc.Status.URL.URL().Host
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return sample, err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	sample.latency = time.Since(start).Seconds()
+	if resp.StatusCode != http.StatusOK {
+		return sample, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	sample.failed = false
+	return sample, nil
+}
+
+// summarizeLoad computes the latency percentiles of the successful requests, overall and for each
+// ready replica count. Requests sent before the deployment was observed are not attributed to a replica count.
+func summarizeLoad(measurement *pkg.ServiceLoadResult, samples []loadSample) {
+	var latencies []float64
+	byReplicas := map[int][]float64{}
+	for _, s := range samples {
+		measurement.Requests++
+		if s.failed {
+			measurement.Errors++
+			continue
+		}
+		latencies = append(latencies, s.latency)
+		if s.replicas >= 0 {
+			byReplicas[s.replicas] = append(byReplicas[s.replicas], s.latency)
+		}
+	}
+	if len(latencies) > 0 {
+		measurement.Min, _ = stats.Min(latencies)
+		measurement.Mean, _ = stats.Mean(latencies)
+		measurement.P50, _ = stats.Percentile(latencies, 50)
+		measurement.P90, _ = stats.Percentile(latencies, 90)
+		measurement.P95, _ = stats.Percentile(latencies, 95)
+		measurement.P99, _ = stats.Percentile(latencies, 99)
+		measurement.Max, _ = stats.Max(latencies)
+	}
+	for r, l := range byReplicas {
+		replicaLatency := pkg.ReplicaLatency{ReadyReplicas: r, Requests: len(l)}
+		replicaLatency.P50, _ = stats.Percentile(l, 50)
+		replicaLatency.P95, _ = stats.Percentile(l, 95)
+		measurement.ReplicaLatencies = append(measurement.ReplicaLatencies, replicaLatency)
+	}
+	sort.Slice(measurement.ReplicaLatencies, func(i, j int) bool {
+		return measurement.ReplicaLatencies[i].ReadyReplicas < measurement.ReplicaLatencies[j].ReadyReplicas
+	})
+}
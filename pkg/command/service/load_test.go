@@ -0,0 +1,170 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func newLoadTestDeployment(readyReplicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ksvc-1-deployment",
+			Namespace: "ns-1",
+			Labels:    map[string]string{serving.ServiceLabelKey: "ksvc-1"},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: readyReplicas},
+	}
+}
+
+func TestNewServiceLoadCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for service load", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceLoadCommand(p))
+		assert.ErrorContains(t, err, "'service load' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewServiceLoadCommand(p), "--svc-prefix", "ksvc", "--connections", "0")
+		assert.ErrorContains(t, err, "connections must be at least 1")
+
+		_, err = testutil.ExecuteCommand(NewServiceLoadCommand(p), "--svc-prefix", "ksvc", "--qps", "-1")
+		assert.ErrorContains(t, err, "qps must not be negative")
+
+		_, err = testutil.ExecuteCommand(NewServiceLoadCommand(p), "--svc-prefix", "ksvc", "--duration", "0s")
+		assert.ErrorContains(t, err, "duration must be greater than 0")
+
+		_, err = testutil.ExecuteCommand(NewServiceLoadCommand(p), "--svc-prefix", "ksvc", "--disruption-window", "0s")
+		assert.ErrorContains(t, err, "--disruption-window must be greater than 0")
+
+		_, err = testutil.ExecuteCommand(NewServiceLoadCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "no service found to load")
+	})
+
+	t.Run("load service as expected", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}}, newLoadTestDeployment(1))
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("hello"))
+		}))
+		defer server.Close()
+
+		url, err := apis.ParseURL(server.URL)
+		assert.NilError(t, err)
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+		svc.Status.URL = url
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{*svc}}, nil
+		})
+
+		outputDir := t.TempDir()
+		_, err = testutil.ExecuteCommand(NewServiceLoadCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--resolvable",
+			"--qps", "50", "--duration", "200ms", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+LoadOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+	})
+}
+
+func TestRunLoad(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(newLoadTestDeployment(1))
+	p := &pkg.PerfParams{ClientSet: client}
+
+	// the service is scaled to 2 replicas with the first request and receives the payload
+	var once sync.Once
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			body, _ = ioutil.ReadAll(r.Body)
+			client.AppsV1().Deployments("ns-1").UpdateStatus(context.TODO(), newLoadTestDeployment(2), metav1.UpdateOptions{})
+		})
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	url, err := apis.ParseURL(server.URL)
+	assert.NilError(t, err)
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	svc.Status.URL = url
+	inputs := pkg.LoadArgs{QPS: 0, Duration: 200 * time.Millisecond, Connections: 2, Payload: "ping",
+		RequestTimeout: time.Second, ResolvableDomain: true}
+
+	measurement, err := runLoad(context.TODO(), p, inputs, "ns-1", svc)
+	assert.NilError(t, err)
+	assert.Equal(t, "ping", string(body))
+	assert.Check(t, measurement.Requests > 0)
+	assert.Equal(t, 0, measurement.Errors)
+	assert.Equal(t, 1, len(measurement.ScaleEvents))
+	assert.Equal(t, 2, measurement.ScaleEvents[0].ReadyReplicas)
+	assert.Equal(t, 2, measurement.ReplicaLatencies[len(measurement.ReplicaLatencies)-1].ReadyReplicas)
+}
+
+func TestSummarizeLoad(t *testing.T) {
+	measurement := pkg.ServiceLoadResult{}
+	summarizeLoad(&measurement, []loadSample{
+		{latency: 0.1, replicas: 1},
+		{latency: 0.3, replicas: 1},
+		{latency: 0.2, replicas: 2},
+		{latency: 0.4, replicas: -1},
+		{replicas: 2, failed: true},
+	})
+	assert.Equal(t, 5, measurement.Requests)
+	assert.Equal(t, 1, measurement.Errors)
+	assert.Equal(t, 0.1, measurement.Min)
+	assert.Equal(t, 0.4, measurement.Max)
+	assert.Equal(t, 2, len(measurement.ReplicaLatencies))
+	assert.Equal(t, 1, measurement.ReplicaLatencies[0].ReadyReplicas)
+	assert.Equal(t, 2, measurement.ReplicaLatencies[0].Requests)
+	assert.Equal(t, 2, measurement.ReplicaLatencies[1].ReadyReplicas)
+	assert.Equal(t, 1, measurement.ReplicaLatencies[1].Requests)
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/montanaflynn/stats"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const (
+	MaxScaleOutputFilename = "ksvc_max_scale"
+)
+
+// serviceMaxScale returns the max-scale of the revision template of the service, 0 if it isn't bounded
+func serviceMaxScale(svc *servingv1.Service) (int, error) {
+	key, value, ok := autoscaling.MaxScaleAnnotation.Get(svc.Spec.Template.Annotations)
+	if !ok {
+		return 0, nil
+	}
+	maxScale, err := strconv.Atoi(value)
+	if err != nil || maxScale < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return maxScale, nil
+}
+
+// validateMaxScale checks that the ready and the desired replicas of the deployment stayed within the max-scale, and
+// compares the latencies of the requests served at the cap with the ones served below it
+func validateMaxScale(maxScale, maxReady, maxDesired int, scaleEvents []pkg.ScaleEvent, initialReady int, samples []loadSample) *pkg.MaxScaleValidation {
+	v := &pkg.MaxScaleValidation{
+		MaxScale:           maxScale,
+		MaxReadyReplicas:   maxReady,
+		MaxDesiredReplicas: maxDesired,
+		Enforced:           maxReady <= maxScale && maxDesired <= maxScale,
+		CapReached:         -1,
+	}
+	if initialReady >= maxScale {
+		v.CapReached = 0
+	} else {
+		for _, e := range scaleEvents {
+			if e.ReadyReplicas >= maxScale {
+				v.CapReached = e.Offset
+				break
+			}
+		}
+	}
+
+	var below, atCap []float64
+	for _, s := range samples {
+		switch {
+		case s.replicas < 0:
+			// the deployment wasn't observed yet
+		case s.replicas >= maxScale && s.failed:
+			v.AtCapErrors++
+		case s.replicas >= maxScale:
+			atCap = append(atCap, s.latency)
+		case !s.failed:
+			below = append(below, s.latency)
+		}
+	}
+	if len(below) > 0 {
+		v.BelowCapP95, _ = stats.Percentile(below, 95)
+	}
+	if len(atCap) > 0 {
+		v.AtCapP95, _ = stats.Percentile(atCap, 95)
+	}
+	if len(below) > 0 && len(atCap) > 0 {
+		v.Penalty = v.AtCapP95 - v.BelowCapP95
+	}
+	return v
+}
+
+// maxScaleRows returns the rows of the CSV file of the max-scale validation of the services
+func maxScaleRows(measurements []pkg.ServiceLoadResult) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "max_scale", "max_ready_replicas", "max_desired_replicas",
+		"enforced", "cap_reached", "below_cap_p95", "at_cap_p95", "at_cap_errors", "penalty"}}
+	for _, r := range measurements {
+		v := r.MaxScale
+		if v == nil {
+			continue
+		}
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace,
+			strconv.Itoa(v.MaxScale),
+			strconv.Itoa(v.MaxReadyReplicas),
+			strconv.Itoa(v.MaxDesiredReplicas),
+			strconv.FormatBool(v.Enforced),
+			fmt.Sprintf("%f", v.CapReached),
+			fmt.Sprintf("%f", v.BelowCapP95),
+			fmt.Sprintf("%f", v.AtCapP95),
+			strconv.Itoa(v.AtCapErrors),
+			fmt.Sprintf("%f", v.Penalty),
+		})
+	}
+	return rows
+}
+
+// writeMaxScaleSummary writes the number of services which stayed within, exceeded and didn't reach their max-scale,
+// and the average latency penalty of the services which reached it. It returns the number of services which exceeded
+// their max-scale.
+func writeMaxScaleSummary(out io.Writer, measurements []pkg.ServiceLoadResult) int {
+	validated, enforced, exceeded, notReached, atCap := 0, 0, 0, 0, 0
+	penalty := 0.0
+	for _, r := range measurements {
+		v := r.MaxScale
+		if v == nil {
+			continue
+		}
+		validated++
+		if v.Enforced {
+			enforced++
+		} else {
+			exceeded++
+		}
+		if v.CapReached < 0 {
+			notReached++
+		} else {
+			atCap++
+			penalty += v.Penalty
+		}
+	}
+	fmt.Fprintf(out, "Max-Scale Enforcement: Validated: %d | Enforced: %d Exceeded: %d CapNotReached: %d\n",
+		validated, enforced, exceeded, notReached)
+	if atCap > 0 {
+		fmt.Fprintf(out, "Average P95 latency penalty at the cap: %fs\n", penalty/float64(atCap))
+	}
+	if notReached > 0 {
+		fmt.Fprintf(out, "%d service(s) didn't reach their max-scale, increase --qps or --connections to drive the load beyond it\n", notReached)
+	}
+	return exceeded
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg"
+)
+
+func newMaxScaleTestService(annotations map[string]string) *servingv1.Service {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	svc.Spec.Template.Annotations = annotations
+	return svc
+}
+
+func TestServiceMaxScale(t *testing.T) {
+	maxScale, err := serviceMaxScale(newMaxScaleTestService(nil))
+	assert.NilError(t, err)
+	assert.Equal(t, 0, maxScale)
+
+	maxScale, err = serviceMaxScale(newMaxScaleTestService(map[string]string{"autoscaling.knative.dev/max-scale": "5"}))
+	assert.NilError(t, err)
+	assert.Equal(t, 5, maxScale)
+
+	maxScale, err = serviceMaxScale(newMaxScaleTestService(map[string]string{"autoscaling.knative.dev/maxScale": "3"}))
+	assert.NilError(t, err)
+	assert.Equal(t, 3, maxScale)
+
+	_, err = serviceMaxScale(newMaxScaleTestService(map[string]string{"autoscaling.knative.dev/max-scale": "many"}))
+	assert.ErrorContains(t, err, "invalid autoscaling.knative.dev/max-scale \"many\"")
+}
+
+func TestValidateMaxScale(t *testing.T) {
+	samples := []loadSample{
+		{latency: 0.4, replicas: -1},
+		{latency: 0.1, replicas: 1},
+		{latency: 0.2, replicas: 1},
+		{latency: 0.5, replicas: 2},
+		{latency: 0.7, replicas: 2},
+		{replicas: 2, failed: true},
+		{replicas: 1, failed: true},
+	}
+	events := []pkg.ScaleEvent{{Offset: 1.5, ReadyReplicas: 2}}
+
+	v := validateMaxScale(2, 2, 2, events, 1, samples)
+	assert.Assert(t, v.Enforced)
+	assert.Equal(t, 1.5, v.CapReached)
+	assert.Equal(t, 1, v.AtCapErrors)
+	assert.Assert(t, v.BelowCapP95 > 0 && v.BelowCapP95 <= 0.2)
+	assert.Assert(t, v.AtCapP95 >= 0.5 && v.AtCapP95 <= 0.7)
+	assert.Equal(t, v.AtCapP95-v.BelowCapP95, v.Penalty)
+
+	v = validateMaxScale(2, 2, 3, events, 1, samples)
+	assert.Assert(t, !v.Enforced, "the desired replicas exceeded the max-scale")
+
+	v = validateMaxScale(3, 2, 2, events, 1, samples)
+	assert.Equal(t, -1.0, v.CapReached)
+	assert.Equal(t, 0.0, v.Penalty)
+
+	v = validateMaxScale(1, 1, 1, nil, 1, samples)
+	assert.Equal(t, 0.0, v.CapReached)
+}
+
+func TestWriteMaxScaleSummary(t *testing.T) {
+	measurements := []pkg.ServiceLoadResult{
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", MaxScale: &pkg.MaxScaleValidation{MaxScale: 2, MaxReadyReplicas: 2, Enforced: true, CapReached: 1, Penalty: 0.2}},
+		{ServiceName: "ksvc-2", ServiceNamespace: "ns-1", MaxScale: &pkg.MaxScaleValidation{MaxScale: 2, MaxReadyReplicas: 3, CapReached: 2, Penalty: 0.4}},
+		{ServiceName: "ksvc-3", ServiceNamespace: "ns-1", MaxScale: &pkg.MaxScaleValidation{MaxScale: 5, MaxReadyReplicas: 1, Enforced: true, CapReached: -1}},
+		{ServiceName: "ksvc-4", ServiceNamespace: "ns-1"},
+	}
+	out := &bytes.Buffer{}
+	assert.Equal(t, 1, writeMaxScaleSummary(out, measurements))
+	assert.Equal(t, `Max-Scale Enforcement: Validated: 3 | Enforced: 2 Exceeded: 1 CapNotReached: 1
+Average P95 latency penalty at the cap: 0.300000s
+1 service(s) didn't reach their max-scale, increase --qps or --connections to drive the load beyond it
+`, out.String())
+
+	rows := maxScaleRows(measurements)
+	assert.Equal(t, 4, len(rows))
+	assert.DeepEqual(t, []string{"ksvc-2", "ns-1", "2", "3", "0", "false", "2.000000", "0.000000", "0.000000", "0", "0.400000"}, rows[2])
+}
+
+func TestRunLoadMaxScale(t *testing.T) {
+	deployment := newLoadTestDeployment(1)
+	deployment.Spec.Replicas = ptr.Int32(1)
+	client := k8sfake.NewSimpleClientset(deployment)
+	p := &pkg.PerfParams{ClientSet: client}
+
+	// the autoscaler scales the service beyond its max-scale of 2 with the first request
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			scaled := newLoadTestDeployment(3)
+			scaled.Spec.Replicas = ptr.Int32(3)
+			client.AppsV1().Deployments("ns-1").Update(context.TODO(), scaled, metav1.UpdateOptions{})
+		})
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	url, err := apis.ParseURL(server.URL)
+	assert.NilError(t, err)
+	svc := newMaxScaleTestService(map[string]string{"autoscaling.knative.dev/max-scale": "2"})
+	svc.Status.URL = url
+	inputs := pkg.LoadArgs{Duration: 200 * time.Millisecond, Connections: 2, RequestTimeout: time.Second,
+		ResolvableDomain: true, ValidateMaxScale: true}
+
+	measurement, err := runLoad(context.TODO(), p, inputs, "ns-1", svc)
+	assert.NilError(t, err)
+	assert.Assert(t, measurement.MaxScale != nil)
+	assert.Equal(t, 2, measurement.MaxScale.MaxScale)
+	assert.Equal(t, 3, measurement.MaxScale.MaxReadyReplicas)
+	assert.Equal(t, 3, measurement.MaxScale.MaxDesiredReplicas)
+	assert.Assert(t, !measurement.MaxScale.Enforced)
+	assert.Assert(t, measurement.MaxScale.CapReached >= 0)
+
+	inputs.ValidateMaxScale = false
+	measurement, err = runLoad(context.TODO(), p, inputs, "ns-1", svc)
+	assert.NilError(t, err)
+	assert.Assert(t, measurement.MaxScale == nil)
+}
@@ -15,18 +15,37 @@
 package service
 
 import (
-	"context"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/report"
 	"knative.dev/kperf/pkg/testutil"
+	networkingv1alpha1api "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
 	fakenetworkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	autoscalingv1alpha1api "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	autoscalingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
 	autoscalingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1/fake"
 
@@ -64,6 +83,69 @@ func TestNewServiceMeasureCommand(t *testing.T) {
 
 		_, err = testutil.ExecuteCommand(cmd, "--range", "1,y", "--namespace-prefix", "ns", "--namespace-range", "1,2")
 		assert.ErrorContains(t, err, "strconv.Atoi: parsing \"y\": invalid syntax")
+
+		_, err = testutil.ExecuteCommand(cmd, "--range", "1,1", "--namespace", "ns", "--output-format", "xml")
+		assert.ErrorContains(t, err, "unsupported output format \"xml\"")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--max-phase-avg", "build_ready=10s")
+		assert.ErrorContains(t, err, "unknown phase \"build_ready\" in --max-phase-avg")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--resume")
+		assert.ErrorContains(t, err, "--resume requires --checkpoint")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--group-by", "zone")
+		assert.ErrorContains(t, err, "unsupported group-by \"zone\", expected one of namespace,prefix,node,label:<key>")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--group-by", "label:")
+		assert.ErrorContains(t, err, "unsupported group-by \"label:\"")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--selector", "app in (")
+		assert.ErrorContains(t, err, "invalid selector \"app in (\"")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--namespace", "ns", "--svc-regex", "api-(")
+		assert.ErrorContains(t, err, "invalid --svc-regex \"api-(\"")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--retries", "-1")
+		assert.ErrorContains(t, err, "--retries must not be negative, given -1")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--stream")
+		assert.ErrorContains(t, err, "--stream requires --output-format ndjson")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--kind", "revision")
+		assert.ErrorContains(t, err, "unsupported kind \"revision\", expected one of service,configuration,route")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--sample", "150%")
+		assert.ErrorContains(t, err, "expected sample like 10%, between 0% and 100%, given 150%")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--limit", "-1")
+		assert.ErrorContains(t, err, "--limit must not be negative, given -1")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--limit", "1", "--sample-strategy", "smart")
+		assert.ErrorContains(t, err, "unsupported sample strategy \"smart\", expected one of random,first,stratified,namespace")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--summary-format", "json")
+		assert.ErrorContains(t, err, "unsupported summary format \"json\", expected one of table,yaml")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--precision", "us")
+		assert.ErrorContains(t, err, "unsupported precision \"us\", expected one of s,ms")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--color", "--no-color")
+		assert.ErrorContains(t, err, "--color and --no-color can't be combined")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--summary-only", "--output-format", "parquet")
+		assert.ErrorContains(t, err, "--summary-only doesn't keep the per service rows, it can't be combined with --output-format parquet,junit,ndjson, --bulk-format, --clickhouse-url, --bigquery-table or --influx-url")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--clickhouse-url", "http://clickhouse:8123", "--clickhouse-table", "kperf; DROP TABLE kperf")
+		assert.ErrorContains(t, err, `expected ClickHouse table like table or database.table, given "kperf; DROP TABLE kperf"`)
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--summary-only", "--group-by", "namespace")
+		assert.ErrorContains(t, err, "--summary-only can't be combined with --group-by, --checkpoint or --follow")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--summary-only", "--detect-races")
+		assert.ErrorContains(t, err, "--detect-races needs the per service rows, it can't be combined with --summary-only")
+
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--range", "1,1", "--namespace", "ns", "--detect-races", "--race-gap", "-1s")
+		assert.ErrorContains(t, err, "--race-gap must not be negative, given -1s")
 	})
 
 	t.Run("measure service as expected with namespace flag", func(t *testing.T) {
@@ -96,10 +178,76 @@ func TestNewServiceMeasureCommand(t *testing.T) {
 		}
 
 		cmd := NewServiceMeasureCommand(p)
-		_, err := testutil.ExecuteCommand(cmd, "--svc-prefix", "svc", "--namespace", "ns1", "--range", "1,1")
+		_, err := testutil.ExecuteCommand(cmd, "--svc-prefix", "svc", "--namespace", "ns1", "--range", "1,1", "--output", t.TempDir())
 		assert.NilError(t, err)
 	})
 
+	t.Run("measure services with several prefixes", func(t *testing.T) {
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &clienttesting.Fake{}}
+		var requested []string
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			requested = append(requested, action.GetNamespace()+"/"+action.(clienttesting.GetAction).GetName())
+			return true, nil, apierrors.NewNotFound(servingv1.Resource("services"), action.(clienttesting.GetAction).GetName())
+		})
+		p := &pkg.PerfParams{
+			ClientSet: k8sfake.NewSimpleClientset(),
+			NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+				return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: &clienttesting.Fake{}}, nil
+			},
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: &clienttesting.Fake{}}, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--svc-prefix", "web,api", "--namespace", "ns1", "--range", "1,2", "--concurrency", "1", "--output", t.TempDir())
+		assert.NilError(t, err)
+		sort.Strings(requested)
+		assert.DeepEqual(t, []string{"ns1/api-1", "ns1/api-2", "ns1/web-1", "ns1/web-2"}, requested)
+	})
+
+	t.Run("measure services created after a time", func(t *testing.T) {
+		now := time.Now()
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &clienttesting.Fake{}}
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "svc-1", Namespace: "ns1", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "svc-2", Namespace: "ns1", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))}},
+			}}, nil
+		})
+		var requested []string
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			requested = append(requested, action.GetNamespace()+"/"+action.(clienttesting.GetAction).GetName())
+			return true, nil, apierrors.NewNotFound(servingv1.Resource("services"), action.(clienttesting.GetAction).GetName())
+		})
+		p := &pkg.PerfParams{
+			ClientSet: k8sfake.NewSimpleClientset(),
+			NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+				return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: &clienttesting.Fake{}}, nil
+			},
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: &clienttesting.Fake{}}, nil
+			},
+			Clock: clock.NewFakeClock(now),
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--svc-prefix", "svc", "--namespace", "ns1", "--range", "1,3", "--since", "1h", "--output", t.TempDir())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"ns1/svc-2"}, requested)
+
+		requested = nil
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--namespace", "ns1", "--svc-regex", "^svc-",
+			"--created-after", now.Add(-3*time.Hour).UTC().Format(time.RFC3339), "--output", t.TempDir())
+		assert.NilError(t, err)
+		sort.Strings(requested)
+		assert.DeepEqual(t, []string{"ns1/svc-1", "ns1/svc-2"}, requested)
+	})
+
 	t.Run("measure service with output flag", func(t *testing.T) {
 		ns := &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
@@ -131,7 +279,239 @@ func TestNewServiceMeasureCommand(t *testing.T) {
 
 		cmd := NewServiceMeasureCommand(p)
 		_, err := testutil.ExecuteCommand(cmd, "--svc-prefix", "svc", "--namespace", "ns1", "--range", "1,1", "--output", "/tmp1")
+		assert.ErrorContains(t, err, "/tmp1/")
+	})
+
+	t.Run("measure the services of a run", func(t *testing.T) {
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &clienttesting.Fake{}}
+		var selector, listNamespace string
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			selector = action.(clienttesting.ListAction).GetListRestrictions().Labels.String()
+			listNamespace = action.GetNamespace()
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-0", Namespace: "ns1", Labels: map[string]string{pkg.RunIDLabel: "demo"}}},
+			}}, nil
+		})
+		var requested []string
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			requested = append(requested, action.GetNamespace()+"/"+action.(clienttesting.GetAction).GetName())
+			return true, nil, apierrors.NewNotFound(servingv1.Resource("services"), action.(clienttesting.GetAction).GetName())
+		})
+		p := &pkg.PerfParams{
+			ClientSet: k8sfake.NewSimpleClientset(),
+			NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+				return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: &clienttesting.Fake{}}, nil
+			},
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: &clienttesting.Fake{}}, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--run-id", "../demo")
+		assert.ErrorContains(t, err, "invalid run ID \"../demo\"")
+
+		output := t.TempDir()
+		_, err = testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--run-id", "demo", "--output", output)
+		assert.NilError(t, err)
+		assert.Equal(t, pkg.RunIDLabel+"=demo", selector)
+		assert.Equal(t, "", listNamespace)
+		assert.DeepEqual(t, []string{"ns1/ksvc-0"}, requested)
+		_, err = os.Stat(filepath.Join(output, "demo"))
+		assert.NilError(t, err)
+	})
+
+	t.Run("measure service with debug timestamps", func(t *testing.T) {
+		fake := &clienttesting.Fake{}
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		// the timestamps of services which are not ready are written as well
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.Service{ObjectMeta: metav1.ObjectMeta{
+				Name:              "svc-1",
+				Namespace:         "ns1",
+				CreationTimestamp: metav1.NewTime(created),
+			}}, nil
+		})
+		p := &pkg.PerfParams{
+			ClientSet: k8sfake.NewSimpleClientset(),
+			NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+				return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: fake}, nil
+			},
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return &servingv1fake.FakeServingV1{Fake: fake}, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: fake}, nil
+			},
+		}
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--svc-prefix", "svc", "--namespace", "ns1", "--range", "1,1",
+			"--output", outputDir, "--debug-timestamps")
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_ksvc_debug_timestamps.csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		content, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		assert.Equal(t, "svc_name,svc_namespace,kind,name,field,timestamp\nsvc-1,ns1,Service,svc-1,created,2022-01-01T00:00:00Z\n", string(content))
+
+		// the reason why svc-1 isn't measured is written as well
+		matches, err = filepath.Glob(filepath.Join(outputDir, "*_ksvc_creation_failures.csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		content, err = ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		assert.Equal(t, "svc_name,svc_namespace,state,reason,message\n"+
+			"svc-1,ns1,not_ready,NotReady,service ns1/svc-1 not ready (no Ready condition) and skip measuring\n", string(content))
+	})
+
+	t.Run("measure services whose names have no dash", func(t *testing.T) {
+		fake := &clienttesting.Fake{}
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		ready := func(types ...apis.ConditionType) duckv1.Conditions {
+			conditions := duckv1.Conditions{}
+			for _, t := range types {
+				conditions = append(conditions, apis.Condition{Type: t, Status: corev1.ConditionTrue,
+					LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Second))}})
+			}
+			return conditions
+		}
+		// the services selected by labels can have any name
+		fake.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "ns1", Labels: map[string]string{"app": "demo"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "ns1", Labels: map[string]string{"app": "demo"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "api-orders-1", Namespace: "ns1", Labels: map[string]string{"app": "demo"}}},
+			}}, nil
+		})
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: action.(clienttesting.GetAction).GetName(), Namespace: "ns1",
+				CreationTimestamp: metav1.NewTime(created)}}
+			svc.Status.Conditions = ready(servingv1.ServiceConditionConfigurationsReady, servingv1.ServiceConditionRoutesReady, servingv1.ServiceConditionReady)
+			return true, svc, nil
+		})
+		fake.PrependReactor("get", "configurations", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			cfg := &servingv1.Configuration{}
+			cfg.Status.LatestReadyRevisionName = "rev-1"
+			return true, cfg, nil
+		})
+		fake.PrependReactor("get", "revisions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			rev := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+			rev.Status.Conditions = ready(servingv1.RevisionConditionReady)
+			return true, rev, nil
+		})
+		fake.PrependReactor("get", "podautoscalers", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			kpa := &autoscalingv1alpha1api.PodAutoscaler{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+			kpa.Status.Conditions = ready(autoscalingv1alpha1api.PodAutoscalerConditionActive)
+			return true, kpa, nil
+		})
+		fake.PrependReactor("get", "serverlessservices", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			sks := &networkingv1alpha1api.ServerlessService{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+			sks.Status.Conditions = ready(networkingv1alpha1api.ActivatorEndpointsPopulated, networkingv1alpha1api.ServerlessServiceConditionEndspointsPopulated,
+				networkingv1alpha1api.ServerlessServiceConditionReady)
+			return true, sks, nil
+		})
+		fake.PrependReactor("get", "ingresses", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			ingress := &networkingv1alpha1api.Ingress{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+			ingress.Status.Conditions = ready(networkingv1alpha1api.IngressConditionNetworkConfigured, networkingv1alpha1api.IngressConditionLoadBalancerReady)
+			return true, ingress, nil
+		})
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "rev-1-deployment", Namespace: "ns1", CreationTimestamp: metav1.NewTime(created)}}
+		p := &pkg.PerfParams{
+			ClientSet: k8sfake.NewSimpleClientset(deployment),
+			NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+				return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: fake}, nil
+			},
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return &servingv1fake.FakeServingV1{Fake: fake}, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: fake}, nil
+			},
+		}
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--namespace", "ns1", "--selector", "app=demo", "--output", outputDir)
+		assert.NilError(t, err)
+
+		// the rows of the measurement and of the raw timestamps are sorted alike
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*ksvc_creation_time.csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 2, len(matches))
+		for _, match := range matches {
+			content, err := ioutil.ReadFile(match)
+			assert.NilError(t, err)
+			names := []string{}
+			for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n")[1:] {
+				names = append(names, strings.Split(line, ",")[0])
+			}
+			assert.DeepEqual(t, []string{"api-orders-1", "hello", "web"}, names)
+		}
+	})
+
+	t.Run("measure service with events", func(t *testing.T) {
+		fake := &clienttesting.Fake{}
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		// the events of services which are not ready are written as well, svc-1 has no Deployment
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc-1", Namespace: "ns1", CreationTimestamp: metav1.NewTime(created)}}
+			ready := apis.VolatileTime{Inner: metav1.NewTime(created.Add(time.Second))}
+			svc.Status.Conditions = duckv1.Conditions{
+				{Type: servingv1.ServiceConditionConfigurationsReady, Status: corev1.ConditionTrue, LastTransitionTime: ready},
+				{Type: servingv1.ServiceConditionRoutesReady, Status: corev1.ConditionTrue, LastTransitionTime: ready},
+				{Type: servingv1.ServiceConditionReady, Status: corev1.ConditionTrue, LastTransitionTime: ready},
+			}
+			return true, svc, nil
+		})
+		fake.PrependReactor("get", "configurations", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			cfg := &servingv1.Configuration{}
+			cfg.Status.LatestReadyRevisionName = "svc-1-00001"
+			return true, cfg, nil
+		})
+		fake.PrependReactor("get", "revisions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			rev := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: "svc-1-00001", Namespace: "ns1"}}
+			rev.Status.Conditions = duckv1.Conditions{{Type: servingv1.RevisionConditionReady, Status: corev1.ConditionTrue}}
+			return true, rev, nil
+		})
+		event := &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "svc-1-00001.1", Namespace: "ns1"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Revision", Name: "svc-1-00001", Namespace: "ns1"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "InternalError",
+			Message:        "failed to pull image, retrying",
+			Count:          2,
+			FirstTimestamp: metav1.NewTime(created),
+			LastTimestamp:  metav1.NewTime(created.Add(time.Minute)),
+		}
+		p := &pkg.PerfParams{
+			ClientSet: k8sfake.NewSimpleClientset(event),
+			NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+				return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: fake}, nil
+			},
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return &servingv1fake.FakeServingV1{Fake: fake}, nil
+			},
+			NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+				return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: fake}, nil
+			},
+		}
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceMeasureCommand(p), "--svc-prefix", "svc", "--namespace", "ns1", "--range", "1,1",
+			"--output", outputDir, "--collect-events")
+		assert.NilError(t, err)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_raw_ksvc_events.csv"))
 		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		content, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		assert.Equal(t, "svc_name,svc_namespace,kind,name,type,reason,message,count,first_timestamp,last_timestamp\n"+
+			"svc-1,ns1,Revision,svc-1-00001,Warning,InternalError,\"failed to pull image, retrying\",2,2022-01-01T00:00:00Z,2022-01-01T00:01:00Z\n", string(content))
 	})
 
 	t.Run("measure service as expected with namespace prefix flag", func(t *testing.T) {
@@ -170,195 +550,102 @@ func TestNewServiceMeasureCommand(t *testing.T) {
 	})
 }
 
-func TestSortSlice(t *testing.T) {
-	rows := [][]string{{"test-2"}, {"test-1"}}
-	sortSlice(rows)
-	assert.DeepEqual(t, [][]string{{"test-1"}, {"test-2"}}, rows)
+func TestWriteMeasureJSON(t *testing.T) {
+	current := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	out := &bytes.Buffer{}
+	enabled := func(format string) bool {
+		return report.Enabled([]string{report.FormatYAML}, format)
+	}
+	assert.NilError(t, writeMeasureJSON(out, pkg.OutputOptions{}, dir, current, pkg.MeasureResult{}, enabled))
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{filepath.Join(dir, "20220101000000_ksvc_creation_time.yaml")}, files)
+	data, err := ioutil.ReadFile(files[0])
+	assert.NilError(t, err)
+	assert.Assert(t, bytes.Contains(data, []byte("schema: kperf.knative.dev/ksvc_creation_time/v1\n")), string(data))
+
+	// the existing file isn't replaced without --overwrite and isn't reported saved
+	out.Reset()
+	err = writeMeasureJSON(out, pkg.OutputOptions{}, dir, current, pkg.MeasureResult{}, enabled)
+	assert.ErrorContains(t, err, "already exists, replace it with --overwrite")
+	assert.Equal(t, "", out.String())
 }
 
-func TestGetPodCondition(t *testing.T) {
-	t.Run("get pod condition when pod is scheduled", func(t *testing.T) {
-		podCondition := &corev1.PodCondition{
-			Type: corev1.PodScheduled,
-		}
-		podStatus := &corev1.PodStatus{
-			Conditions: []corev1.PodCondition{
-				*podCondition,
-			},
-		}
-		i, condition := getPodCondition(podStatus, corev1.PodScheduled)
-		assert.Equal(t, 0, i)
-		assert.Equal(t, corev1.PodScheduled, condition.Type)
-	})
-
-	t.Run("get pod condition when pod isn't scheduled", func(t *testing.T) {
-		podCondition := &corev1.PodCondition{
-			Type: corev1.PodInitialized,
-		}
-		podStatus := &corev1.PodStatus{
-			Conditions: []corev1.PodCondition{
-				*podCondition,
-			},
-		}
-		i, condition := getPodCondition(podStatus, corev1.PodScheduled)
-		assert.Equal(t, -1, i)
-		assert.Equal(t, (*corev1.PodCondition)(nil), condition)
-	})
-
-	t.Run("get pod condition when pod status is nil", func(t *testing.T) {
-		podCondition := (*corev1.PodCondition)(nil)
-		i, condition := getPodCondition(nil, corev1.PodScheduled)
-		assert.Equal(t, -1, i)
-		assert.Equal(t, podCondition, condition)
-	})
+func TestSampleServices(t *testing.T) {
+	services := make([]types.NamespacedName, 0, 50)
+	for i := 1; i <= 50; i++ {
+		services = append(services, types.NamespacedName{Namespace: "ns", Name: fmt.Sprintf("ksvc-%d", i)})
+	}
+	clk := clock.NewFakePassiveClock(time.Now())
+
+	sampled, err := sampleServices(ioutil.Discard, services, pkg.MeasureArgs{SampleStrategy: measure.SampleRandom}, clk)
+	assert.NilError(t, err)
+	assert.Equal(t, 50, len(sampled))
+
+	sampled, err = sampleServices(ioutil.Discard, services, pkg.MeasureArgs{Sample: "10%", SampleStrategy: measure.SampleFirst}, clk)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, services[:5], sampled)
+
+	sampled, err = sampleServices(ioutil.Discard, services, pkg.MeasureArgs{Sample: "10%", Limit: 3, SampleStrategy: measure.SampleFirst}, clk)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, services[:3], sampled)
+
+	first, err := sampleServices(ioutil.Discard, services, pkg.MeasureArgs{Limit: 5, SampleStrategy: measure.SampleRandom, SampleSeed: 42}, clk)
+	assert.NilError(t, err)
+	second, err := sampleServices(ioutil.Discard, services, pkg.MeasureArgs{Limit: 5, SampleStrategy: measure.SampleRandom, SampleSeed: 42}, clk)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, first, second)
 }
 
-func TestGetContainerStatus(t *testing.T) {
-	t.Run("get container status seccussfully", func(t *testing.T) {
-		var containerStatus []corev1.ContainerStatus
-		container := corev1.ContainerStatus{
-			Name: "user-container",
-		}
-		containerStatus = append(containerStatus, container)
-		s, status := getContainerStatus(containerStatus, "user-container")
-		assert.Equal(t, container.Name, s.Name)
-		assert.Equal(t, true, status)
-	})
+func TestParseCreatedAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
 
-	t.Run("get container status when the condition is not present", func(t *testing.T) {
-		var containerStatus []corev1.ContainerStatus
-		s, status := getContainerStatus(containerStatus, "queue-proxy")
-		assert.Equal(t, (*corev1.ContainerStatus)(nil), s)
-		assert.Equal(t, false, status)
-	})
-}
+	createdAfter, err := parseCreatedAfter("", 0, now)
+	assert.NilError(t, err)
+	assert.Check(t, createdAfter.IsZero())
 
-func TestGetKnativeVersion(t *testing.T) {
-	t.Run("get knative serving and eventing version", func(t *testing.T) {
-		servingNs := &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:   "knative-serving",
-				Labels: map[string]string{"serving.knative.dev/release": "v0.20.0"},
-			},
-		}
-		eventingNs := &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:   "knative-eventing",
-				Labels: map[string]string{"eventing.knative.dev/release": "v0.20.0"},
-			},
-		}
-		client := k8sfake.NewSimpleClientset(servingNs, eventingNs)
-
-		p := &pkg.PerfParams{
-			ClientSet: client,
-		}
-		version := GetKnativeVersion(p)
-		assert.Equal(t, "0.20.0", version["serving"])
-		assert.Equal(t, "0.20.0", version["eventing"])
-	})
+	createdAfter, err = parseCreatedAfter("", time.Hour, now)
+	assert.NilError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), createdAfter)
 
-	t.Run("failed to get knative serving and eventing version", func(t *testing.T) {
-		client := k8sfake.NewSimpleClientset()
-		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
-		servingClient := func() (servingv1client.ServingV1Interface, error) {
-			return fakeServing, nil
-		}
+	createdAfter, err = parseCreatedAfter("2024-05-01T10:00:00Z", 0, now)
+	assert.NilError(t, err)
+	assert.Equal(t, now.Add(-2*time.Hour), createdAfter)
 
-		p := &pkg.PerfParams{
-			ClientSet:        client,
-			NewServingClient: servingClient,
-		}
-		version := GetKnativeVersion(p)
-		assert.Equal(t, "Unknown", version["serving"])
-		assert.Equal(t, "Unknown", version["eventing"])
-	})
+	_, err = parseCreatedAfter("2024-05-01", 0, now)
+	assert.ErrorContains(t, err, "expected created-after like 2024-05-01T10:00:00Z, given 2024-05-01")
+	_, err = parseCreatedAfter("2024-05-01T10:00:00Z", time.Hour, now)
+	assert.ErrorContains(t, err, "--created-after and --since can't be combined")
+	_, err = parseCreatedAfter("", -time.Hour, now)
+	assert.ErrorContains(t, err, "--since must not be negative")
 }
 
-func TestGetIngressController(t *testing.T) {
-	t.Run("get knative ingress controller with version", func(t *testing.T) {
-		servingNs := &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "knative-serving",
-			},
-		}
-
-		istioNs := &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "istio-system",
-			},
-		}
-
-		client := k8sfake.NewSimpleClientset(servingNs, istioNs)
-		p := &pkg.PerfParams{
-			ClientSet: client,
-		}
-
-		configMapKnative := &corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "config-network",
-			},
-			Data: map[string]string{"ingress.class": "istio.ingress.networking.knative.dev"},
-		}
-		p.ClientSet.CoreV1().ConfigMaps("knative-serving").Create(context.TODO(), configMapKnative, metav1.CreateOptions{})
-
-		configMapIstio := &corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:   "istio",
-				Labels: map[string]string{"operator.istio.io/version": "1.7.3"},
-			},
-			Data: map[string]string{"ingress.class": "istio.ingress.networking.knative.dev"},
-		}
-		p.ClientSet.CoreV1().ConfigMaps("istio-system").Create(context.TODO(), configMapIstio, metav1.CreateOptions{})
-
-		ingressController := GetIngressController(p)
-		assert.Equal(t, "Istio", ingressController["ingressController"])
-		assert.Equal(t, "1.7.3", ingressController["version"])
-	})
-
-	t.Run("get knative ingress controller without version", func(t *testing.T) {
-		servingNs := &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "knative-serving",
-			},
-		}
+func TestSortSlice(t *testing.T) {
+	rows := [][]string{{"test-2"}, {"test-1"}}
+	sortSlice(rows)
+	assert.DeepEqual(t, [][]string{{"test-1"}, {"test-2"}}, rows)
 
-		client := k8sfake.NewSimpleClientset(servingNs)
-		p := &pkg.PerfParams{
-			ClientSet: client,
-		}
-		configMapKnative := &corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "config-network",
-			},
-			Data: map[string]string{"ingress.class": "istio.ingress.networking.knative.dev"},
-		}
-		p.ClientSet.CoreV1().ConfigMaps("knative-serving").Create(context.TODO(), configMapKnative, metav1.CreateOptions{})
+	rows = [][]string{{"web", "ns1"}, {"test-10", "ns1"}, {"hello", "ns2"}, {"hello", "ns1"}, {"api-orders-2", "ns1"}}
+	sortSlice(rows)
+	assert.DeepEqual(t, [][]string{{"api-orders-2", "ns1"}, {"test-10", "ns1"}, {"hello", "ns1"}, {"hello", "ns2"}, {"web", "ns1"}}, rows)
+}
 
-		ingressController := GetIngressController(p)
-		assert.Equal(t, "Istio", ingressController["ingressController"])
-		assert.Equal(t, "Unknown", ingressController["version"])
-	})
+func TestTimestamp(t *testing.T) {
+	assert.Equal(t, metav1.Time{}.String(), timestamp(nil))
 
-	t.Run("get unknown knative ingress controller", func(t *testing.T) {
-		servingNs := &corev1.Namespace{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "knative-serving",
-			},
-		}
+	millis := int64(1640995201000)
+	assert.Equal(t, "2022-01-01 00:00:01 +0000 UTC", timestamp(&millis))
+}
 
-		client := k8sfake.NewSimpleClientset(servingNs)
-		p := &pkg.PerfParams{
-			ClientSet: client,
-		}
-		configMapKnative := &corev1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "config-network",
-			},
-		}
-		p.ClientSet.CoreV1().ConfigMaps("knative-serving").Create(context.TODO(), configMapKnative, metav1.CreateOptions{})
+func TestMatchSvcName(t *testing.T) {
+	assert.DeepEqual(t, []string{""}, splitSvcPrefixes(""))
+	assert.DeepEqual(t, []string{"web", "api", "batch"}, splitSvcPrefixes("web, api,,batch"))
 
-		ingressController := GetIngressController(p)
-		assert.Equal(t, "Unknown", ingressController["ingressController"])
-		assert.Equal(t, "Unknown", ingressController["version"])
-	})
+	re, err := compileSvcRegex(`-\d+$`)
+	assert.NilError(t, err)
+	assert.Check(t, matchSvcName("api-1", []string{"web", "api"}, re))
+	assert.Check(t, !matchSvcName("api-x", []string{"web", "api"}, re))
+	assert.Check(t, !matchSvcName("batch-1", []string{"web", "api"}, re))
+	assert.Check(t, matchSvcName("batch-1", []string{""}, nil))
 }
@@ -0,0 +1,116 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+)
+
+const ControlPlaneProfileFilename = "controlplane_profile"
+
+// addControlPlaneProfileFlags adds the flags of the control plane profile shared by generate and measure
+func addControlPlaneProfileFlags(flags *pflag.FlagSet, args *pkg.ControlPlaneProfileArgs) {
+	flags.BoolVarP(&args.Enabled, "profile-controlplane", "", false, "Sample the CPU and memory usage of the Knative Serving controller, autoscaler, activator and webhook pods during the run with metrics-server, to correlate latency spikes with the control plane load")
+	flags.StringVarP(&args.Namespace, "profile-namespace", "", measure.DefaultControlPlaneNamespace, "Namespace of the Knative Serving control plane pods sampled with --profile-controlplane")
+	flags.DurationVarP(&args.Interval, "profile-interval", "", 10*time.Second, "Interval between the samples of the control plane pods with --profile-controlplane")
+	flags.StringSliceVarP(&args.Metrics, "profile-metrics", "", nil, "Comma separated Prometheus metrics scraped from the control plane pods with every sample, e.g. workqueue_depth,go_goroutines. The samples of a metric with different labels are summed up")
+	flags.IntVarP(&args.MetricsPort, "profile-metrics-port", "", measure.DefaultControlPlaneMetricsPort, "Port the control plane pods serve the Prometheus metrics of --profile-metrics on")
+}
+
+// validateControlPlaneProfileArgs checks the profile flags, they require --profile-controlplane
+func validateControlPlaneProfileArgs(args pkg.ControlPlaneProfileArgs) error {
+	if !args.Enabled {
+		if len(args.Metrics) > 0 {
+			return fmt.Errorf("--profile-metrics requires --profile-controlplane")
+		}
+		return nil
+	}
+	if args.Interval <= 0 {
+		return fmt.Errorf("--profile-interval must be positive, given %s", args.Interval)
+	}
+	if args.MetricsPort <= 0 || args.MetricsPort > 65535 {
+		return fmt.Errorf("--profile-metrics-port must be between 1 and 65535, given %d", args.MetricsPort)
+	}
+	return nil
+}
+
+// writeControlPlaneProfile writes the peak usage of every control plane component, summed up over its pods
+func writeControlPlaneProfile(out io.Writer, profile *pkg.ControlPlaneProfile) {
+	type peak struct {
+		pods          int
+		cpuMillicores int64
+		memoryBytes   int64
+	}
+	peaks := map[string]*peak{}
+	for _, pod := range profile.Pods {
+		p, ok := peaks[pod.Component]
+		if !ok {
+			p = &peak{}
+			peaks[pod.Component] = p
+		}
+		p.pods++
+		for _, sample := range pod.Samples {
+			if sample.CPUMillicores != nil && *sample.CPUMillicores > p.cpuMillicores {
+				p.cpuMillicores = *sample.CPUMillicores
+			}
+			if sample.MemoryBytes != nil && *sample.MemoryBytes > p.memoryBytes {
+				p.memoryBytes = *sample.MemoryBytes
+			}
+		}
+	}
+	components := make([]string, 0, len(peaks))
+	for component := range peaks {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	fmt.Fprintf(out, "Control Plane Peak Usage per Pod:\n")
+	if len(components) == 0 {
+		fmt.Fprintf(out, "- no control plane pods found in namespace %s\n", profile.Namespace)
+	}
+	for _, component := range components {
+		p := peaks[component]
+		fmt.Fprintf(out, "- %s (%d pods): CPU %dm Memory %dMi\n", component, p.pods, p.cpuMillicores, p.memoryBytes/(1024*1024))
+	}
+}
+
+// saveControlPlaneProfile writes the profile of a run without a JSON result of its own to a JSON file
+func saveControlPlaneProfile(out io.Writer, outputOptions pkg.OutputOptions, profile *pkg.ControlPlaneProfile, output, runID string, current time.Time) error {
+	outputLocation, err := utils.RunOutputLocation(output, runID)
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	jsonPath := filepath.Join(outputLocation, utils.OutputFileName(outputOptions, current.Format(utils.DateFormatString), ControlPlaneProfileFilename+".json"))
+	if err := utils.GenerateJSONFile(jsonData, jsonPath, outputOptions.Overwrite); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Control plane profile saved in JSON file %s\n", jsonPath)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"knative.dev/pkg/ptr"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestValidateControlPlaneProfileArgs(t *testing.T) {
+	assert.NilError(t, validateControlPlaneProfileArgs(pkg.ControlPlaneProfileArgs{}))
+	assert.ErrorContains(t, validateControlPlaneProfileArgs(pkg.ControlPlaneProfileArgs{Metrics: []string{"go_goroutines"}}),
+		"--profile-metrics requires --profile-controlplane")
+
+	args := pkg.ControlPlaneProfileArgs{Enabled: true, Interval: 10 * time.Second, MetricsPort: 9090}
+	assert.NilError(t, validateControlPlaneProfileArgs(args))
+
+	args.Interval = 0
+	assert.ErrorContains(t, validateControlPlaneProfileArgs(args), "--profile-interval must be positive, given 0s")
+
+	args.Interval, args.MetricsPort = time.Second, 0
+	assert.ErrorContains(t, validateControlPlaneProfileArgs(args), "--profile-metrics-port must be between 1 and 65535, given 0")
+}
+
+func TestWriteControlPlaneProfile(t *testing.T) {
+	profile := &pkg.ControlPlaneProfile{
+		Namespace: "knative-serving",
+		Pods: []pkg.ControlPlanePod{
+			{Name: "activator-1", Component: "activator", Samples: []pkg.ControlPlaneSample{
+				{CPUMillicores: ptr.Int64(100), MemoryBytes: ptr.Int64(32 * 1024 * 1024)},
+				{CPUMillicores: ptr.Int64(300), MemoryBytes: ptr.Int64(16 * 1024 * 1024)},
+			}},
+			{Name: "activator-2", Component: "activator", Samples: []pkg.ControlPlaneSample{
+				{CPUMillicores: ptr.Int64(200), MemoryBytes: ptr.Int64(64 * 1024 * 1024)},
+			}},
+			{Name: "webhook-1", Component: "webhook", Samples: []pkg.ControlPlaneSample{{}}},
+		},
+	}
+	var out bytes.Buffer
+	writeControlPlaneProfile(&out, profile)
+	assert.Equal(t, `Control Plane Peak Usage per Pod:
+- activator (2 pods): CPU 300m Memory 64Mi
+- webhook (1 pods): CPU 0m Memory 0Mi
+`, out.String())
+
+	out.Reset()
+	writeControlPlaneProfile(&out, &pkg.ControlPlaneProfile{Namespace: "knative-serving"})
+	assert.Equal(t, "Control Plane Peak Usage per Pod:\n- no control plane pods found in namespace knative-serving\n", out.String())
+}
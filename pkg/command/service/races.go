@@ -0,0 +1,43 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"knative.dev/kperf/pkg"
+)
+
+// writeRaces writes the number of services by kind of race and the statistics of the gaps between the ready pods
+// and the populated endpoints
+func writeRaces(out io.Writer, report pkg.RaceReport) {
+	fmt.Fprintf(out, "\nInit-Scale Races (gap above %.1fs):\n", report.Gap)
+	fmt.Fprintf(out, "Services: %d | PodReadyFirst: %d EndpointsFirst: %d ReadyBeforeEndpoints: %d\n",
+		report.Services, report.PodReadyFirst, report.EndpointsFirst, report.ReadyBeforeEndpoints)
+	fmt.Fprintf(out, "Pod Ready to Endpoints Populated Gap: Percentile50: %fs Percentile95: %fs Max: %fs\n",
+		report.GapP50, report.GapP95, report.GapMax)
+}
+
+// raceRows returns the rows of the CSV file of the races
+func raceRows(races []pkg.InitScaleRace) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "kinds", "pod_to_endpoints", "revision_to_endpoints"}}
+	for _, r := range races {
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace, strings.Join(r.Kinds, ";"),
+			fmt.Sprintf("%f", r.PodToEndpoints), fmt.Sprintf("%f", r.RevisionToEndpoints)})
+	}
+	return rows
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/measure"
+)
+
+func TestWriteRaces(t *testing.T) {
+	report := pkg.RaceReport{Gap: 5, Services: 3, PodReadyFirst: 1, ReadyBeforeEndpoints: 1, GapP50: 1, GapP95: 6, GapMax: 6,
+		Races: []pkg.InitScaleRace{{ServiceName: "ksvc-1", ServiceNamespace: "ns-1",
+			Kinds: []string{measure.RacePodReadyFirst, measure.RaceReadyBeforeEndpoints}, PodToEndpoints: 6, RevisionToEndpoints: 7}}}
+
+	out := &bytes.Buffer{}
+	writeRaces(out, report)
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Init-Scale Races (gap above 5.0s):")))
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Services: 3 | PodReadyFirst: 1 EndpointsFirst: 0 ReadyBeforeEndpoints: 1")))
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Percentile50: 1.000000s Percentile95: 6.000000s Max: 6.000000s")))
+
+	assert.DeepEqual(t, [][]string{
+		{"svc_name", "svc_namespace", "kinds", "pod_to_endpoints", "revision_to_endpoints"},
+		{"ksvc-1", "ns-1", "pod_ready_first;ready_before_endpoints", "6.000000", "7.000000"},
+	}, raceRows(report.Races))
+}
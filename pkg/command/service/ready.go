@@ -0,0 +1,184 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/report"
+)
+
+// GenerateReadyOutputFilename is the name of the files of the ready durations recorded with generate --wait
+const GenerateReadyOutputFilename = "ksvc_generate_ready_time"
+
+// WaitServiceReady watches the service until it is ready. The watch is restarted when it ends before the service is
+// ready or the timeout is over.
+func WaitServiceReady(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, name string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		svc, err := ksvcClient.Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil && svc.IsReady() {
+			return nil
+		}
+		options := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+		if err == nil {
+			options.ResourceVersion = svc.ResourceVersion
+		}
+		watcher, err := ksvcClient.Services(namespace).Watch(ctx, options)
+		if err == nil && watchServiceReady(ctx, watcher, name) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Knative Service %s in namespace %s is not ready after %s", name, namespace, timeout)
+		case <-time.After(updatePollInterval):
+		}
+	}
+}
+
+// watchServiceReady returns true as soon as the watch reports the service ready, and false when the watch ends or
+// the context is done before
+func watchServiceReady(ctx context.Context, watcher watch.Interface, name string) bool {
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false
+			}
+			if svc, ok := event.Object.(*servingv1.Service); ok && svc.Name == name && svc.IsReady() {
+				return true
+			}
+		}
+	}
+}
+
+// readyRecord is the duration from the Create call of a generated service until it was seen ready
+type readyRecord struct {
+	namespace string
+	name      string
+	ready     time.Duration
+}
+
+// readyRecorder records the durations from the Create calls of the generated services until they were seen ready.
+// Both timestamps are taken by the same clock, so that the durations don't depend on the clocks of the cluster.
+type readyRecorder struct {
+	mu      sync.Mutex
+	created map[string]time.Time
+	records []readyRecord
+}
+
+func newReadyRecorder() *readyRecorder {
+	return &readyRecorder{created: map[string]time.Time{}}
+}
+
+// create records the start of the Create call of the service
+func (r *readyRecorder) create(namespace, name string, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.created[namespace+"/"+name] = start
+}
+
+// ready records that the service was seen ready and returns the duration since its Create call
+func (r *readyRecorder) ready(namespace, name string, now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := now.Sub(r.created[namespace+"/"+name])
+	r.records = append(r.records, readyRecord{namespace: namespace, name: name, ready: d})
+	return d
+}
+
+// sorted returns the records sorted by namespace and name
+func (r *readyRecorder) sorted() []readyRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := append([]readyRecord(nil), r.records...)
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].namespace != records[j].namespace {
+			return records[i].namespace < records[j].namespace
+		}
+		return records[i].name < records[j].name
+	})
+	return records
+}
+
+// result returns the durations sorted by namespace and name with their average, percentiles and maximum
+func (r *readyRecorder) result() pkg.GenerateReadyResult {
+	result := pkg.GenerateReadyResult{Measurement: []pkg.GenerateReadyMeasurement{}}
+	durations := []float64{}
+	for _, record := range r.sorted() {
+		durations = append(durations, record.ready.Seconds())
+		result.Measurement = append(result.Measurement, pkg.GenerateReadyMeasurement{
+			ServiceName:      record.name,
+			ServiceNamespace: record.namespace,
+			Ready:            record.ready.Seconds(),
+		})
+	}
+	result.Ready = len(durations)
+	if len(durations) > 0 {
+		result.Average, _ = stats.Mean(durations)
+		result.P50, _ = stats.Percentile(durations, 50)
+		result.P95, _ = stats.Percentile(durations, 95)
+		result.Max, _ = stats.Max(durations)
+	}
+	return result
+}
+
+// write writes the number of ready services with the average, the percentiles and the maximum of their durations
+func (r *readyRecorder) write(out io.Writer) {
+	result := r.result()
+	fmt.Fprintf(out, "\nReady Measurement:\n")
+	if result.Ready == 0 {
+		fmt.Fprintf(out, "Ready: 0\n")
+		return
+	}
+	fmt.Fprintf(out, "Ready: %d | Average: %fs Percentile50: %fs Percentile95: %fs Max: %fs\n", result.Ready, result.Average, result.P50, result.P95, result.Max)
+}
+
+// save writes the ready durations in the formats to the report files in the output location of the run
+func (r *readyRecorder) save(out io.Writer, outputOptions pkg.OutputOptions, output, runID string, formats []string, current time.Time) error {
+	outputLocation, err := utils.RunOutputLocation(output, runID)
+	if err != nil {
+		return err
+	}
+	result := r.result()
+	rows := [][]string{{"svc_name", "svc_namespace", "ready"}}
+	for _, m := range result.Measurement {
+		rows = append(rows, []string{m.ServiceName, m.ServiceNamespace, fmt.Sprintf("%f", m.Ready)})
+	}
+	return report.Write(out, outputLocation, current.Format(utils.DateFormatString), outputOptions, report.Report{
+		Name:    GenerateReadyOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: formats,
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+)
+
+func markServiceReady(svc *servingv1.Service) {
+	svc.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+}
+
+func TestWaitServiceReady(t *testing.T) {
+	t.Run("service becomes ready while watched", func(t *testing.T) {
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+		client := k8sfake.NewSimpleClientset()
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		_, err := fakeServing.Services("ns-1").Create(context.Background(), svc, metav1.CreateOptions{})
+		assert.NilError(t, err)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			ready := svc.DeepCopy()
+			markServiceReady(ready)
+			_, _ = fakeServing.Services("ns-1").UpdateStatus(context.Background(), ready, metav1.UpdateOptions{})
+		}()
+		assert.NilError(t, WaitServiceReady(context.Background(), fakeServing, "ns-1", "ksvc-1", 5*time.Second))
+	})
+
+	t.Run("service not ready within the timeout", func(t *testing.T) {
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+		client := k8sfake.NewSimpleClientset()
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		_, err := fakeServing.Services("ns-1").Create(context.Background(), svc, metav1.CreateOptions{})
+		assert.NilError(t, err)
+
+		err = WaitServiceReady(context.Background(), fakeServing, "ns-1", "ksvc-1", 50*time.Millisecond)
+		assert.ErrorContains(t, err, "Knative Service ksvc-1 in namespace ns-1 is not ready after 50ms")
+	})
+}
+
+func TestReadyRecorder(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder := newReadyRecorder()
+	recorder.create("ns-2", "ksvc-1", start)
+	recorder.create("ns-1", "ksvc-2", start.Add(time.Second))
+	assert.Equal(t, 4*time.Second, recorder.ready("ns-2", "ksvc-1", start.Add(4*time.Second)))
+	assert.Equal(t, 2*time.Second, recorder.ready("ns-1", "ksvc-2", start.Add(3*time.Second)))
+
+	out := &bytes.Buffer{}
+	recorder.write(out)
+	assert.Assert(t, strings.Contains(out.String(), "Ready: 2 | Average: 3.000000s"), out.String())
+	assert.Assert(t, strings.Contains(out.String(), "Max: 4.000000s"), out.String())
+
+	dir := t.TempDir()
+	assert.NilError(t, recorder.save(out, pkg.OutputOptions{}, dir, "demo", nil, start))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "demo", start.Format(utils.DateFormatString)+"_"+GenerateReadyOutputFilename+".csv"))
+	assert.NilError(t, err)
+	assert.Equal(t, "svc_name,svc_namespace,ready\nksvc-2,ns-1,2.000000\nksvc-1,ns-2,4.000000\n", string(data))
+
+	empty := &bytes.Buffer{}
+	newReadyRecorder().write(empty)
+	assert.Equal(t, "\nReady Measurement:\nReady: 0\n", empty.String())
+}
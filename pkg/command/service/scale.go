@@ -24,7 +24,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -37,13 +37,18 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"knative.dev/kperf/pkg"
 	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
 
+	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/serving"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
@@ -51,6 +56,11 @@ import (
 
 const (
 	OutputFilename = "ksvc_scaling_time"
+
+	// ScaleMethodRequest scales a Knative Service by sending requests to it
+	ScaleMethodRequest = "request"
+	// ScaleMethodMinScale scales a Knative Service by setting its min-scale annotation
+	ScaleMethodMinScale = "min-scale"
 )
 
 type ServicesToScale struct {
@@ -75,12 +85,25 @@ func NewServiceScaleCommand(p *pkg.PerfParams) *cobra.Command {
 For example:
 # To measure a Knative Service scaling from zero
 kperf service scale --svc-perfix svc --range 1,200 --namespace ns --concurrency 20
+
+# To measure the time until 5 pods of a Knative Service are ready after its min-scale annotation was set to 5
+kperf service scale --svc-perfix svc --namespace ns --replicas 5 --scale-method min-scale
 `,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if cmd.Flags().NFlag() == 0 {
 				return fmt.Errorf("'service scale' requires flag(s)")
 			}
-			return nil
+			if scaleArgs.Replicas < 1 {
+				return fmt.Errorf("replicas must be at least 1")
+			}
+			if scaleArgs.ScaleMethod != ScaleMethodRequest && scaleArgs.ScaleMethod != ScaleMethodMinScale {
+				return fmt.Errorf("unsupported scale method %q, expected one of %s,%s", scaleArgs.ScaleMethod, ScaleMethodRequest, ScaleMethodMinScale)
+			}
+			if err := validateAutoscalerScrapeArgs(scaleArgs.Autoscaler); err != nil {
+				return err
+			}
+			return pkg.ValidateRunID(scaleArgs.RunID)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return ScaleServicesUpFromZero(p, scaleArgs)
@@ -95,10 +118,16 @@ kperf service scale --svc-perfix svc --range 1,200 --namespace ns --concurrency
 	serviceScaleCommand.Flags().StringVarP(&scaleArgs.NamespacePrefix, "namespace-prefix", "", "", "Service namespace prefix")
 	serviceScaleCommand.Flags().IntVarP(&scaleArgs.Concurrency, "concurrency", "c", 10, "Number of workers to do measurement job")
 	serviceScaleCommand.Flags().StringVarP(&scaleArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(serviceScaleCommand.Flags(), &scaleArgs.OutputFormats)
+	serviceScaleCommand.Flags().StringVarP(&scaleArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
 	serviceScaleCommand.Flags().BoolVarP(&scaleArgs.ResolvableDomain, "resolvable", "", false, "If Service endpoint resolvable url")
 	serviceScaleCommand.Flags().IntVarP(&scaleArgs.MaxRetries, "MaxRetries", "", 10, "Maximum number of trying to poll the service")
 	serviceScaleCommand.Flags().DurationVarP(&scaleArgs.RequestInterval, "wait", "", 2*time.Second, "Time to wait before retring to call the Knatice Service")
 	serviceScaleCommand.Flags().DurationVarP(&scaleArgs.RequestTimeout, "timeout", "", 2*time.Second, "Duration to wait for Knative Service to be ready")
+	serviceScaleCommand.Flags().IntVarP(&scaleArgs.Replicas, "replicas", "", 1, "Number of ready pods to scale the Knative Service to, more than 1 measures the time until each pod is ready")
+	serviceScaleCommand.Flags().StringVarP(&scaleArgs.ScaleMethod, "scale-method", "", ScaleMethodRequest, "How to scale the Knative Service, request sends requests to it, min-scale sets its min-scale annotation to --replicas and restores it afterwards")
+	serviceScaleCommand.Flags().DurationVarP(&scaleArgs.ReadyTimeout, "ready-timeout", "", 5*time.Minute, "Duration to wait for --replicas pods to be ready when scaling to more than 1 pod or with the min-scale method")
+	addAutoscalerScrapeFlags(serviceScaleCommand.Flags(), &scaleArgs.Autoscaler)
 	return serviceScaleCommand
 }
 
@@ -114,52 +143,42 @@ func ScaleServicesUpFromZero(params *pkg.PerfParams, inputs pkg.ScaleArgs) error
 		return err
 	}
 
-	knativeVersion := GetKnativeVersion(params)
-	ingressInfo := GetIngressController(params)
-	scaleFromZeroResult.KnativeInfo.ServingVersion = knativeVersion["serving"]
-	scaleFromZeroResult.KnativeInfo.EventingVersion = knativeVersion["eventing"]
-	scaleFromZeroResult.KnativeInfo.IngressController = ingressInfo["ingressController"]
-	scaleFromZeroResult.KnativeInfo.IngressVersion = ingressInfo["version"]
+	scaleFromZeroResult.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
 
 	rows := make([][]string, 0)
-	rows = append([][]string{{"svc_name", "svc_namespace", "svc_latency", "deployment_latency"}}, rows...)
+	if scaleToN(inputs) {
+		header := []string{"svc_name", "svc_namespace", "deployment_latency", "ready_latency"}
+		for i := 1; i <= inputs.Replicas; i++ {
+			header = append(header, fmt.Sprintf("replica_%d_ready", i))
+		}
+		rows = append(rows, header)
+		for _, m := range scaleFromZeroResult.Measurment {
+			row := []string{m.ServiceName, m.ServiceNamespace, fmt.Sprintf("%f", m.DeploymentLatency), fmt.Sprintf("%f", m.ReadyLatency)}
+			for _, ready := range m.ReplicaReady {
+				row = append(row, fmt.Sprintf("%f", ready))
+			}
+			rows = append(rows, row)
+		}
+	} else {
+		rows = append([][]string{{"svc_name", "svc_namespace", "svc_latency", "deployment_latency"}}, rows...)
 
-	for _, m := range scaleFromZeroResult.Measurment {
-		rows = append(rows, []string{m.ServiceName, m.ServiceNamespace, fmt.Sprintf("%f", m.ServiceLatency), fmt.Sprintf("%f", m.DeploymentLatency)})
+		for _, m := range scaleFromZeroResult.Measurment {
+			rows = append(rows, []string{m.ServiceName, m.ServiceNamespace, fmt.Sprintf("%f", m.ServiceLatency), fmt.Sprintf("%f", m.DeploymentLatency)})
+		}
 	}
 
 	current := time.Now()
-	outputLocation, err := utils.CheckOutputLocation(inputs.Output)
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
 	if err != nil {
 		fmt.Printf("failed to check measure output location: %s\n", err)
 	}
 
-	csvPath := filepath.Join(outputLocation, fmt.Sprintf("%s_%s.csv", current.Format(DateFormatString), OutputFilename))
-	err = utils.GenerateCSVFile(csvPath, rows)
-	if err != nil {
-		fmt.Printf("failed to generate CSV file and skip %s\n", err)
-	}
-	fmt.Printf("Measurement saved in CSV file %s\n", csvPath)
-
-	jsonPath := filepath.Join(outputLocation, fmt.Sprintf("%s_%s.json", current.Format(DateFormatString), OutputFilename))
-	jsonData, err := json.Marshal(scaleFromZeroResult)
-	if err != nil {
-		fmt.Printf("failed to generate json data and skip %s\n", err)
-	}
-	err = utils.GenerateJSONFile(jsonData, jsonPath)
-	if err != nil {
-		fmt.Printf("failed to generate json file and skip %s\n", err)
-	}
-	fmt.Printf("Measurement saved in JSON file %s\n", jsonPath)
-
-	htmlPath := filepath.Join(outputLocation, fmt.Sprintf("%s_%s.html", current.Format(DateFormatString), OutputFilename))
-	err = utils.GenerateHTMLFile(csvPath, htmlPath)
-	if err != nil {
-		fmt.Printf("failed to generate HTML file and skip %s\n", err)
-	}
-	fmt.Printf("Visualized measurement saved in HTML file %s\n", htmlPath)
-
-	return nil
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    OutputFilename,
+		Rows:    rows,
+		Result:  scaleFromZeroResult,
+		Formats: inputs.OutputFormats,
+	})
 }
 
 func scaleAndMeasure(ctx context.Context, params *pkg.PerfParams, inputs pkg.ScaleArgs, nsNameList []string, servicesListFunc func(context.Context, servingv1client.ServingV1Interface, []string, string) []ServicesToScale) (pkg.ScaleResult, error) {
@@ -171,31 +190,53 @@ func scaleAndMeasure(ctx context.Context, params *pkg.PerfParams, inputs pkg.Sca
 	objs := servicesListFunc(ctx, ksvcClient, nsNameList, inputs.SvcPrefix)
 	count := len(objs)
 
-	var wg sync.WaitGroup
+	var stopScrape func() *pkg.AutoscalerMetrics
+	if inputs.Autoscaler.Enabled {
+		scaled := map[string]bool{}
+		for _, obj := range objs {
+			scaled[obj.Namespace+"/"+obj.Service.Name] = true
+		}
+		stopScrape = measure.StartAutoscalerScraper(ctx, params, inputs.Autoscaler, func(namespace, service string) bool {
+			return scaled[namespace+"/"+service]
+		}, measure.DefaultLogger)
+	}
+
+	// all the services are scaled at the same time
 	var m sync.Mutex
-	wg.Add(count)
-	for i := 0; i < count; i++ {
-		go func(ndx int, m *sync.Mutex) {
-			defer wg.Done()
-			sdur, ddur, err := runScaleFromZero(ctx, params, inputs, objs[ndx].Namespace, objs[ndx].Service)
-			if err == nil {
-				//measure
-				fmt.Printf("result of scale for service %s is %f, %f \n", objs[ndx].Service.Name, sdur.Seconds(), ddur.Seconds())
-				m.Lock()
-				result.Measurment = append(result.Measurment, pkg.ScaleFromZeroResult{
-					ServiceName:       objs[ndx].Service.Name,
-					ServiceNamespace:  objs[ndx].Service.Namespace,
-					ServiceLatency:    sdur.Seconds(),
-					DeploymentLatency: ddur.Seconds(),
-				})
-				m.Unlock()
-			} else {
-				fmt.Printf("result of scale is error: %s", err)
+	pool.ForEach(ctx, count, count, func(ctx context.Context, ndx int) {
+		if scaleToN(inputs) {
+			measurement, err := runScaleToN(ctx, params, inputs, objs[ndx].Namespace, objs[ndx].Service)
+			if err != nil {
+				fmt.Printf("result of scale is error: %s\n", err)
+				return
 			}
-		}(i, &m)
-	}
-	wg.Wait()
+			fmt.Printf("result of scale for service %s to %d replicas is %f, %f \n", objs[ndx].Service.Name, inputs.Replicas, measurement.DeploymentLatency, measurement.ReadyLatency)
+			m.Lock()
+			result.Measurment = append(result.Measurment, measurement)
+			m.Unlock()
+			return
+		}
+		sdur, ddur, err := runScaleFromZero(ctx, params, inputs, objs[ndx].Namespace, objs[ndx].Service)
+		if err == nil {
+			//measure
+			fmt.Printf("result of scale for service %s is %f, %f \n", objs[ndx].Service.Name, sdur.Seconds(), ddur.Seconds())
+			m.Lock()
+			result.Measurment = append(result.Measurment, pkg.ScaleFromZeroResult{
+				ServiceName:       objs[ndx].Service.Name,
+				ServiceNamespace:  objs[ndx].Service.Namespace,
+				ServiceLatency:    sdur.Seconds(),
+				DeploymentLatency: ddur.Seconds(),
+			})
+			m.Unlock()
+		} else {
+			fmt.Printf("result of scale is error: %s\n", err)
+		}
+	})
 
+	if stopScrape != nil {
+		result.Autoscaler = stopScrape()
+		writeAutoscalerMetrics(os.Stdout, result.Autoscaler)
+	}
 	return result, nil
 }
 
@@ -234,7 +275,7 @@ func runScaleFromZero(ctx context.Context, params *pkg.PerfParams, inputs pkg.Sc
 	sdch := make(chan struct{})
 	errch := make(chan error)
 
-	endpoint, err := resolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
+	endpoint, err := ResolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get the cluster endpoint: %w", err)
 	}
@@ -274,6 +315,163 @@ func runScaleFromZero(ctx context.Context, params *pkg.PerfParams, inputs pkg.Sc
 	}
 }
 
+// scaleToN returns true if the services are scaled to more than one replica or by the min-scale annotation,
+// instead of measuring the first request to a service scaled to zero
+func scaleToN(inputs pkg.ScaleArgs) bool {
+	return inputs.Replicas > 1 || inputs.ScaleMethod == ScaleMethodMinScale
+}
+
+// runScaleToN scales the service to inputs.Replicas pods and measures the time until the deployment is scaled
+// and until each of the pods is ready
+func runScaleToN(ctx context.Context, params *pkg.PerfParams, inputs pkg.ScaleArgs, namespace string, svc *servingv1.Service) (pkg.ScaleFromZeroResult, error) {
+	measurement := pkg.ScaleFromZeroResult{ServiceName: svc.Name, ServiceNamespace: namespace}
+	selector := labels.SelectorFromSet(labels.Set{
+		serving.ServiceLabelKey: svc.Name,
+	}).String()
+	deployments, err := params.ClientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	// pods which are ready already are measured as ready at once. Setting min-scale creates a new revision,
+	// so only the deployment created for it is measured.
+	ready := 0
+	existing := make(map[string]bool)
+	for _, d := range deployments.Items {
+		existing[d.Name] = true
+		if inputs.ScaleMethod != ScaleMethodMinScale && int(d.Status.ReadyReplicas) > ready {
+			ready = int(d.Status.ReadyReplicas)
+		}
+	}
+	if ready > inputs.Replicas {
+		ready = inputs.Replicas
+	}
+	for i := 0; i < ready; i++ {
+		measurement.ReplicaReady = append(measurement.ReplicaReady, 0)
+	}
+	watcher, err := params.ClientSet.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:   selector,
+		ResourceVersion: deployments.ResourceVersion,
+	})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to watch deployments: %w", err)
+	}
+	defer watcher.Stop()
+
+	scaleCtx, cancel := context.WithTimeout(ctx, inputs.ReadyTimeout)
+	defer cancel()
+	start := time.Now()
+	if inputs.ScaleMethod == ScaleMethodMinScale {
+		restore, err := setMinScale(scaleCtx, params, namespace, svc, inputs.Replicas)
+		if err != nil {
+			return measurement, err
+		}
+		defer restore()
+	} else {
+		if err := sendScaleRequests(scaleCtx, params, inputs, svc); err != nil {
+			return measurement, err
+		}
+	}
+
+	for ready < inputs.Replicas {
+		select {
+		case <-scaleCtx.Done():
+			return measurement, fmt.Errorf("%d of %d replicas of service %s ready after %s", ready, inputs.Replicas, svc.Name, inputs.ReadyTimeout)
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return measurement, fmt.Errorf("watch of the deployments of service %s closed", svc.Name)
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			deployment, ok := event.Object.(*v1.Deployment)
+			if !ok || (inputs.ScaleMethod == ScaleMethodMinScale && existing[deployment.Name]) {
+				continue
+			}
+			offset := time.Since(start).Seconds()
+			if measurement.DeploymentLatency == 0 && deployment.Spec.Replicas != nil && int(*deployment.Spec.Replicas) >= inputs.Replicas {
+				measurement.DeploymentLatency = offset
+			}
+			for ; ready < int(deployment.Status.ReadyReplicas) && ready < inputs.Replicas; ready++ {
+				measurement.ReplicaReady = append(measurement.ReplicaReady, offset)
+			}
+		}
+	}
+	measurement.ReadyLatency = time.Since(start).Seconds()
+	if len(measurement.ReplicaReady) > 0 {
+		measurement.ReadyLatency = measurement.ReplicaReady[len(measurement.ReplicaReady)-1]
+	}
+	return measurement, nil
+}
+
+// setMinScale sets the min-scale annotation of the revision template of the service to replicas, the returned
+// function restores the previous annotation
+func setMinScale(ctx context.Context, params *pkg.PerfParams, namespace string, svc *servingv1.Service, replicas int) (func(), error) {
+	servingClient, err := params.NewServingClient()
+	if err != nil {
+		return nil, err
+	}
+	value := strconv.Itoa(replicas)
+	patch, err := minScalePatch(&value)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := servingClient.Services(namespace).Patch(ctx, svc.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to set min-scale of service %s: %w", svc.Name, err)
+	}
+
+	var previous *string
+	if v, ok := svc.Spec.Template.Annotations[autoscaling.MinScaleAnnotationKey]; ok {
+		previous = &v
+	}
+	return func() {
+		patch, err := minScalePatch(previous)
+		if err == nil {
+			_, err = servingClient.Services(namespace).Patch(context.Background(), svc.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		}
+		if err != nil {
+			fmt.Printf("failed to restore min-scale of service %s: %s\n", svc.Name, err)
+		}
+	}, nil
+}
+
+// minScalePatch returns the merge patch setting the min-scale annotation of the revision template, nil removes it
+func minScalePatch(value *string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{
+						autoscaling.MinScaleAnnotationKey: value,
+					},
+				},
+			},
+		},
+	})
+}
+
+// sendScaleRequests keeps inputs.Replicas concurrent requests in flight until ctx is done. This scales the
+// service to inputs.Replicas pods if each pod serves a single request, e.g. with containerConcurrency 1.
+func sendScaleRequests(ctx context.Context, params *pkg.PerfParams, inputs pkg.ScaleArgs, svc *servingv1.Service) error {
+	endpoint, err := ResolveEndpoint(ctx, params, inputs.ResolvableDomain, svc)
+	if err != nil {
+		return fmt.Errorf("failed to get the cluster endpoint: %w", err)
+	}
+	client := http.Client{}
+	for i := 0; i < inputs.Replicas; i++ {
+		go func() {
+			for ctx.Err() == nil {
+				if _, err := sendLoadRequest(ctx, client, http.MethodGet, endpoint, svc, ""); err != nil && ctx.Err() == nil {
+					if inputs.Verbose {
+						fmt.Printf("[Verbose] Service %s: request failed: %s\n", svc.Name, err)
+					}
+					time.Sleep(inputs.RequestInterval)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
 func Poll(httpClient http.Client, request *http.Request, maxRetries int, requestInterval time.Duration, requestTimeout time.Duration, url string) (*Response, error) {
 	var resp *Response
 	retries := 0
@@ -313,8 +511,8 @@ func Poll(httpClient http.Client, request *http.Request, maxRetries int, request
 	return resp, nil
 }
 
-// resolveEndpoint resolves the endpoint address considering whether the domain is resolvable
-func resolveEndpoint(ctx context.Context, params *pkg.PerfParams, resolvable bool, svc *servingv1.Service) (string, error) {
+// ResolveEndpoint resolves the endpoint address considering whether the domain is resolvable
+func ResolveEndpoint(ctx context.Context, params *pkg.PerfParams, resolvable bool, svc *servingv1.Service) (string, error) {
 	// If the domain is resolvable, it can be used directly
 	if resolvable {
 		url := svc.Status.RouteStatusFields.URL.URL()
@@ -15,15 +15,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
 	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
 	fakenetworkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
 	autoscalingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
@@ -95,3 +100,97 @@ func TestScaleServces(t *testing.T) {
 	_, err := scaleAndMeasure(context.TODO(), p, scaleArgs, []string{"ns-1"}, getFakeServices)
 	assert.NilError(t, err)
 }
+
+func TestNewServiceScaleCommand(t *testing.T) {
+	p := &pkg.PerfParams{ClientSet: k8sfake.NewSimpleClientset()}
+
+	_, err := testutil.ExecuteCommand(NewServiceScaleCommand(p))
+	assert.ErrorContains(t, err, "'service scale' requires flag(s)")
+
+	_, err = testutil.ExecuteCommand(NewServiceScaleCommand(p), "--svc-prefix", "ksvc", "--replicas", "0")
+	assert.ErrorContains(t, err, "replicas must be at least 1")
+
+	_, err = testutil.ExecuteCommand(NewServiceScaleCommand(p), "--svc-prefix", "ksvc", "--scale-method", "hpa")
+	assert.ErrorContains(t, err, "unsupported scale method \"hpa\", expected one of request,min-scale")
+
+	_, err = testutil.ExecuteCommand(NewServiceScaleCommand(p), "--svc-prefix", "ksvc", "--scrape-autoscaler", "--autoscaler-interval", "0s")
+	assert.ErrorContains(t, err, "--autoscaler-interval must be positive, given 0s")
+
+	_, err = testutil.ExecuteCommand(NewServiceScaleCommand(p), "--svc-prefix", "ksvc", "--scrape-autoscaler", "--autoscaler-metrics-port", "0")
+	assert.ErrorContains(t, err, "--autoscaler-metrics-port must be between 1 and 65535, given 0")
+}
+
+func TestWriteAutoscalerMetrics(t *testing.T) {
+	scaleUp := 2.5
+	metrics := &pkg.AutoscalerMetrics{Namespace: "knative-serving", Revisions: []pkg.AutoscalerRevision{
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-1", Revision: "ksvc-1-00001", FirstScaleUp: &scaleUp, Samples: []pkg.AutoscalerDecision{
+			{Offset: 0, DesiredPods: 0, ActualPods: 0},
+			{Offset: 2.5, DesiredPods: 3, ActualPods: 1, PanicMode: true},
+			{Offset: 5, DesiredPods: 3, ActualPods: 3},
+		}},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-2", Revision: "ksvc-2-00001", Samples: []pkg.AutoscalerDecision{
+			{Offset: 0, DesiredPods: 1, ActualPods: 1},
+		}},
+	}}
+	var out bytes.Buffer
+	writeAutoscalerMetrics(&out, metrics)
+	assert.Equal(t, `
+Autoscaler Decisions:
+- ns-1/ksvc-1 (ksvc-1-00001): first scale-up 2.500000s | peak desired pods 3 actual pods 3 | panic mode true
+- ns-1/ksvc-2 (ksvc-2-00001): first scale-up never | peak desired pods 1 actual pods 1 | panic mode false
+`, out.String())
+
+	out.Reset()
+	writeAutoscalerMetrics(&out, &pkg.AutoscalerMetrics{Namespace: "knative-serving"})
+	assert.Check(t, strings.Contains(out.String(), "no metrics of the scaled services reported by the autoscaler in namespace knative-serving"))
+}
+
+func TestRunScaleToN(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(newLoadTestDeployment(1))
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &clienttesting.Fake{}}
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+	}
+
+	var patches []string
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	fakeServing.PrependReactor("patch", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patches = append(patches, string(action.(clienttesting.PatchAction).GetPatch()))
+		if len(patches) == 1 {
+			// the new revision is scaled to 2 pods which get ready one after the other
+			go func() {
+				deployment := newLoadTestDeployment(0)
+				deployment.Name = "ksvc-1-00002-deployment"
+				replicas := int32(2)
+				deployment.Spec.Replicas = &replicas
+				client.AppsV1().Deployments("ns-1").Create(context.TODO(), deployment, metav1.CreateOptions{})
+				for ready := int32(1); ready <= 2; ready++ {
+					time.Sleep(10 * time.Millisecond)
+					deployment.Status.ReadyReplicas = ready
+					client.AppsV1().Deployments("ns-1").UpdateStatus(context.TODO(), deployment, metav1.UpdateOptions{})
+				}
+			}()
+		}
+		return true, svc, nil
+	})
+
+	inputs := pkg.ScaleArgs{Replicas: 2, ScaleMethod: ScaleMethodMinScale, ReadyTimeout: 5 * time.Second}
+	measurement, err := runScaleToN(context.TODO(), p, inputs, "ns-1", svc)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(measurement.ReplicaReady))
+	assert.Assert(t, measurement.ReplicaReady[0] > 0 && measurement.ReplicaReady[0] < measurement.ReplicaReady[1])
+	assert.Equal(t, measurement.ReplicaReady[1], measurement.ReadyLatency)
+	assert.Assert(t, measurement.DeploymentLatency > 0)
+	assert.DeepEqual(t, []string{
+		`{"spec":{"template":{"metadata":{"annotations":{"autoscaling.knative.dev/min-scale":"2"}}}}}`,
+		`{"spec":{"template":{"metadata":{"annotations":{"autoscaling.knative.dev/min-scale":null}}}}}`,
+	}, patches)
+
+	// the pods don't get ready in time
+	inputs.ReadyTimeout = 50 * time.Millisecond
+	_, err = runScaleToN(context.TODO(), p, inputs, "ns-1", svc)
+	assert.ErrorContains(t, err, "of 2 replicas of service ksvc-1 ready after 50ms")
+}
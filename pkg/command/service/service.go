@@ -32,6 +32,12 @@ kperf service measure --range 1,10, --name perf - to measure 10 Knative service
 	serviceCmd.AddCommand(NewServiceGenerateCommand(p))
 	serviceCmd.AddCommand(NewServiceCleanCommand(p))
 	serviceCmd.AddCommand(NewServiceScaleCommand(p))
+	serviceCmd.AddCommand(NewServiceColdStartCommand(p))
+	serviceCmd.AddCommand(NewServiceLoadCommand(p))
+	serviceCmd.AddCommand(NewServiceUpdateMeasureCommand(p))
+	serviceCmd.AddCommand(NewServiceDeleteMeasureCommand(p))
+	serviceCmd.AddCommand(NewServiceTrafficMeasureCommand(p))
+	serviceCmd.AddCommand(NewServiceActivatorOverheadCommand(p))
 
 	serviceCmd.InitDefaultHelpCmd()
 	return serviceCmd
@@ -32,4 +32,22 @@ func TestNewServiceCmd(t *testing.T) {
 
 	_, _, err = cmd.Find([]string{"clean"})
 	assert.NilError(t, err, "service command should have clean subcommand")
+
+	_, _, err = cmd.Find([]string{"coldstart"})
+	assert.NilError(t, err, "service command should have coldstart subcommand")
+
+	_, _, err = cmd.Find([]string{"load"})
+	assert.NilError(t, err, "service command should have load subcommand")
+
+	_, _, err = cmd.Find([]string{"update-measure"})
+	assert.NilError(t, err, "service command should have update-measure subcommand")
+
+	_, _, err = cmd.Find([]string{"delete-measure"})
+	assert.NilError(t, err, "service command should have delete-measure subcommand")
+
+	_, _, err = cmd.Find([]string{"traffic-measure"})
+	assert.NilError(t, err, "service command should have traffic-measure subcommand")
+
+	_, _, err = cmd.Find([]string{"activator-overhead"})
+	assert.NilError(t, err, "service command should have activator-overhead subcommand")
 }
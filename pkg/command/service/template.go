@@ -0,0 +1,76 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// serviceTemplateData holds the variables of a service template
+type serviceTemplateData struct {
+	// Index is the index of the service, the services are named like <Prefix>-<Index>
+	Index int
+	// Name is the default name of the service
+	Name string
+	// Namespace is the namespace the service is created in
+	Namespace string
+	// Prefix is the service name prefix
+	Prefix string
+	// MinScale and MaxScale are the values of --min-scale and --max-scale
+	MinScale int
+	MaxScale int
+}
+
+// serviceTemplate renders Knative Services from a go-template of a Knative Service in YAML or JSON
+type serviceTemplate struct {
+	tmpl *template.Template
+}
+
+// loadServiceTemplate reads and parses the service template file
+func loadServiceTemplate(path string) (*serviceTemplate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service template: %s", err)
+	}
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service template: %s", err)
+	}
+	return &serviceTemplate{tmpl: tmpl}, nil
+}
+
+// render returns the service rendered with the data. The service is named by the data unless the template sets a
+// name, and it is always created in the namespace of the data.
+func (t *serviceTemplate) render(data serviceTemplateData) (*servingv1.Service, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render service template: %s", err)
+	}
+	service := &servingv1.Service{}
+	if err := yaml.NewYAMLOrJSONDecoder(&buf, buf.Len()+1).Decode(service); err != nil {
+		return nil, fmt.Errorf("failed to decode rendered service template: %s", err)
+	}
+	if service.Name == "" {
+		service.Name = data.Name
+	}
+	service.Namespace = data.Namespace
+	return service, nil
+}
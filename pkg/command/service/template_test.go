@@ -0,0 +1,86 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func writeServiceTemplate(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "ksvc.yaml")
+	assert.NilError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestServiceTemplate(t *testing.T) {
+	data := serviceTemplateData{Index: 3, Name: "ksvc-3", Namespace: "ns-1", Prefix: "ksvc", MinScale: 1, MaxScale: 5}
+
+	t.Run("render YAML", func(t *testing.T) {
+		tmpl, err := loadServiceTemplate(writeServiceTemplate(t, `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  namespace: other
+spec:
+  template:
+    metadata:
+      annotations:
+        autoscaling.knative.dev/minScale: "{{.MinScale}}"
+        autoscaling.knative.dev/maxScale: "{{.MaxScale}}"
+    spec:
+      containers:
+      - image: example.com/{{.Prefix}}:{{.Index}}
+`))
+		assert.NilError(t, err)
+		svc, err := tmpl.render(data)
+		assert.NilError(t, err)
+		assert.Equal(t, "ksvc-3", svc.Name)
+		assert.Equal(t, "ns-1", svc.Namespace)
+		assert.Equal(t, "example.com/ksvc:3", svc.Spec.Template.Spec.Containers[0].Image)
+		assert.DeepEqual(t, map[string]string{"autoscaling.knative.dev/minScale": "1", "autoscaling.knative.dev/maxScale": "5"},
+			svc.Spec.Template.Annotations)
+	})
+
+	t.Run("render JSON with a custom name", func(t *testing.T) {
+		tmpl, err := loadServiceTemplate(writeServiceTemplate(t, `{"metadata": {"name": "api-{{.Index}}"}}`))
+		assert.NilError(t, err)
+		svc, err := tmpl.render(data)
+		assert.NilError(t, err)
+		assert.Equal(t, "api-3", svc.Name)
+	})
+
+	t.Run("unknown variable", func(t *testing.T) {
+		tmpl, err := loadServiceTemplate(writeServiceTemplate(t, `metadata:
+  name: {{.Replicas}}`))
+		assert.NilError(t, err)
+		_, err = tmpl.render(data)
+		assert.ErrorContains(t, err, "failed to render service template")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := loadServiceTemplate(writeServiceTemplate(t, `metadata: {{.Index`))
+		assert.ErrorContains(t, err, "failed to parse service template")
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		tmpl, err := loadServiceTemplate(writeServiceTemplate(t, `spec: [`))
+		assert.NilError(t, err)
+		_, err = tmpl.render(data)
+		assert.ErrorContains(t, err, "failed to decode rendered service template")
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"knative.dev/kperf/pkg"
+)
+
+// phaseAverages returns the average duration of every phase named like the columns of the measurement CSV file
+func phaseAverages(result pkg.Result) map[string]float64 {
+	return map[string]float64{
+		"configuration_ready":               result.AverageSvcConfigurationReadySum,
+		"revision_ready":                    result.AverageRevisionReadySum,
+		"deployment_created":                result.AverageDeploymentCreatedSum,
+		"pod_scheduled":                     result.AveragePodScheduledSum,
+		"containers_ready":                  result.AverageContainersReadySum,
+		"queue-proxy_started":               result.AverageQueueProxyStartedSum,
+		"user-container_started":            result.AverageUserContrainerStartedSum,
+		"route_ready":                       result.AverageSvcRoutesReadySum,
+		"kpa_active":                        result.AverageKpaActiveSum,
+		"sks_ready":                         result.AverageSksReadySum,
+		"sks_activator_endpoints_populated": result.AverageSksActivatorEndpointsPopulatedSum,
+		"sks_endpoints_populated":           result.AverageSksEndpointsPopulatedSum,
+		"ingress_ready":                     result.AverageIngressReadySum,
+		"ingress_config_ready":              result.AverageIngressNetworkConfiguredSum,
+		"ingress_lb_ready":                  result.AverageIngressLoadBalancerReadySum,
+		"certificate_ready":                 result.AverageCertificateReadySum,
+		"overall_ready":                     result.OverallAverage,
+	}
+}
+
+// parsePhaseThresholds parses the per phase thresholds given as phase=duration, e.g. revision_ready=10s
+func parsePhaseThresholds(thresholds map[string]string) (map[string]time.Duration, error) {
+	phases := phaseAverages(pkg.Result{})
+	parsed := make(map[string]time.Duration, len(thresholds))
+	for phase, value := range thresholds {
+		if _, ok := phases[phase]; !ok {
+			names := make([]string, 0, len(phases))
+			for name := range phases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown phase %q in --max-phase-avg, expected one of %s", phase, strings.Join(names, ","))
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q for phase %s in --max-phase-avg: %s", value, phase, err)
+		}
+		parsed[phase] = d
+	}
+	return parsed, nil
+}
+
+// thresholdCheck is a threshold given by a flag and the measured value it is checked against
+type thresholdCheck struct {
+	name  string
+	value float64
+	max   time.Duration
+}
+
+func (c thresholdCheck) exceeded() bool {
+	return c.value > c.max.Seconds()
+}
+
+func (c thresholdCheck) String() string {
+	return fmt.Sprintf("%s %fs > %s", c.name, c.value, c.max)
+}
+
+// thresholdChecks returns a check for every threshold which is given, ordered like the flags and by phase
+func thresholdChecks(inputs pkg.MeasureArgs, result pkg.MeasureResult) ([]thresholdCheck, error) {
+	phaseThresholds, err := parsePhaseThresholds(inputs.MaxPhaseAvg)
+	if err != nil {
+		return nil, err
+	}
+	var checks []thresholdCheck
+	add := func(name string, value float64, max time.Duration) {
+		if max > 0 {
+			checks = append(checks, thresholdCheck{name: name, value: value, max: max})
+		}
+	}
+	add("average ready", result.Result.OverallAverage, inputs.MaxAvgReady)
+	add("p95 ready", result.Result.P95, inputs.MaxP95Ready)
+
+	phases := make([]string, 0, len(phaseThresholds))
+	for phase := range phaseThresholds {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	averages := phaseAverages(result.Result)
+	for _, phase := range phases {
+		add("average "+phase, averages[phase], phaseThresholds[phase])
+	}
+	return checks, nil
+}
+
+// checkThresholds returns an error listing every threshold exceeded by the measure result. If thresholds
+// are given but no service is ready, the check fails as well.
+func checkThresholds(out io.Writer, inputs pkg.MeasureArgs, result pkg.MeasureResult) error {
+	checks, err := thresholdChecks(inputs, result)
+	if err != nil {
+		return err
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+	if result.Service.ReadyCount == 0 {
+		return fmt.Errorf("thresholds can't be checked, no service is ready")
+	}
+
+	var exceeded []string
+	for _, c := range checks {
+		if c.exceeded() {
+			exceeded = append(exceeded, c.String())
+		}
+	}
+	if len(exceeded) > 0 {
+		return fmt.Errorf("thresholds exceeded: %s", strings.Join(exceeded, "; "))
+	}
+	fmt.Fprintf(out, "All thresholds passed\n")
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestParsePhaseThresholds(t *testing.T) {
+	thresholds, err := parsePhaseThresholds(map[string]string{"revision_ready": "10s", "ingress_ready": "1m"})
+	assert.NilError(t, err)
+	assert.Equal(t, 10*time.Second, thresholds["revision_ready"])
+	assert.Equal(t, time.Minute, thresholds["ingress_ready"])
+
+	_, err = parsePhaseThresholds(map[string]string{"build_ready": "10s"})
+	assert.ErrorContains(t, err, "unknown phase \"build_ready\"")
+
+	_, err = parsePhaseThresholds(map[string]string{"revision_ready": "ten"})
+	assert.ErrorContains(t, err, "invalid duration \"ten\" for phase revision_ready")
+}
+
+func TestCheckThresholds(t *testing.T) {
+	result := pkg.MeasureResult{
+		Service: pkg.ServiceCount{ReadyCount: 10},
+		Result:  pkg.Result{OverallAverage: 20, P95: 40, AverageRevisionReadySum: 8, AverageIngressReadySum: 6},
+	}
+
+	t.Run("no thresholds", func(t *testing.T) {
+		assert.NilError(t, checkThresholds(ioutil.Discard, pkg.MeasureArgs{}, pkg.MeasureResult{}))
+	})
+
+	t.Run("thresholds passed", func(t *testing.T) {
+		inputs := pkg.MeasureArgs{MaxAvgReady: 30 * time.Second, MaxP95Ready: 45 * time.Second,
+			MaxPhaseAvg: map[string]string{"revision_ready": "10s"}}
+		assert.NilError(t, checkThresholds(ioutil.Discard, inputs, result))
+	})
+
+	t.Run("thresholds exceeded", func(t *testing.T) {
+		inputs := pkg.MeasureArgs{MaxAvgReady: 30 * time.Second, MaxP95Ready: 30 * time.Second,
+			MaxPhaseAvg: map[string]string{"revision_ready": "10s", "ingress_ready": "5s"}}
+		err := checkThresholds(ioutil.Discard, inputs, result)
+		assert.Error(t, err, "thresholds exceeded: p95 ready 40.000000s > 30s; average ingress_ready 6.000000s > 5s")
+	})
+
+	t.Run("no service ready", func(t *testing.T) {
+		err := checkThresholds(ioutil.Discard, pkg.MeasureArgs{MaxAvgReady: time.Second}, pkg.MeasureResult{})
+		assert.ErrorContains(t, err, "no service is ready")
+	})
+}
@@ -0,0 +1,360 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/ptr"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+)
+
+const (
+	TrafficOutputFilename = "ksvc_traffic_time"
+
+	// revisionEnv is set to the index of the revision in the revision template of the generated revisions
+	revisionEnv = "KPERF_REVISION"
+)
+
+func NewServiceTrafficMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	trafficArgs := pkg.TrafficMeasureArgs{}
+	serviceTrafficMeasureCommand := &cobra.Command{
+		Use:   "traffic-measure",
+		Short: "Change the traffic split of Knative services and measure the Route reconciliation",
+		Long: `Change the traffic split of Knative services all at the same time and measure how long the Route
+reconciliation takes to converge
+
+The percentages are set on the traffic targets of every service in order, the services need as many targets, e.g.
+generated with 'service generate --revisions'. The convergence is broken down into the time until the service
+observed the change, and the time until its Route is ready and routes the traffic as specified.
+
+For example:
+# To generate 3 revisions for each Knative Service with the traffic split evenly across them
+kperf service generate -n 10 -b 10 -i 1 --namespace ns --svc-prefix svc --revisions 3
+
+# To shift the traffic of all the services to their latest revision in steps
+kperf service traffic-measure --svc-prefix svc --namespace ns --percentages 10,20,70
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'service traffic-measure' requires flag(s)")
+			}
+			if err := validatePercentages(trafficArgs.Percentages); err != nil {
+				return err
+			}
+			return pkg.ValidateRunID(trafficArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureTraffic(p, trafficArgs)
+		},
+	}
+
+	serviceTrafficMeasureCommand.Flags().StringVarP(&trafficArgs.Namespace, "namespace", "", "", "Service namespace")
+	serviceTrafficMeasureCommand.Flags().StringVarP(&trafficArgs.SvcPrefix, "svc-prefix", "", "", "Service name prefix")
+	serviceTrafficMeasureCommand.Flags().BoolVarP(&trafficArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	serviceTrafficMeasureCommand.Flags().StringVarP(&trafficArgs.NamespaceRange, "namespace-range", "", "", "Service namespace range")
+	serviceTrafficMeasureCommand.Flags().StringVarP(&trafficArgs.NamespacePrefix, "namespace-prefix", "", "", "Service namespace prefix")
+	serviceTrafficMeasureCommand.Flags().StringVarP(&trafficArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(serviceTrafficMeasureCommand.Flags(), &trafficArgs.OutputFormats)
+	serviceTrafficMeasureCommand.Flags().StringVarP(&trafficArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	serviceTrafficMeasureCommand.Flags().IntSliceVarP(&trafficArgs.Percentages, "percentages", "", nil, "Comma separated traffic percentages of the traffic targets of the services in order, adding up to 100, e.g. 10,20,70")
+	serviceTrafficMeasureCommand.Flags().DurationVarP(&trafficArgs.Timeout, "timeout", "", 5*time.Minute, "Duration to wait for the Route of a Knative Service to converge")
+	return serviceTrafficMeasureCommand
+}
+
+// validatePercentages checks that the percentages split the traffic across at least two targets
+func validatePercentages(percentages []int) error {
+	if len(percentages) < 2 {
+		return fmt.Errorf("--percentages requires at least two traffic targets, given %v", percentages)
+	}
+	sum := 0
+	for _, p := range percentages {
+		if p < 0 {
+			return fmt.Errorf("--percentages must not be negative, given %v", percentages)
+		}
+		sum += p
+	}
+	if sum != 100 {
+		return fmt.Errorf("--percentages must add up to 100, given %v", percentages)
+	}
+	return nil
+}
+
+// MeasureTraffic used to change the traffic split of Knative Services and measure the convergence of their Routes
+func MeasureTraffic(params *pkg.PerfParams, inputs pkg.TrafficMeasureArgs) error {
+	ctx := context.Background()
+	nsNameList, err := GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	objs := getServices(ctx, ksvcClient, nsNameList, inputs.SvcPrefix)
+	if len(objs) == 0 {
+		return fmt.Errorf("no service found to change the traffic of")
+	}
+
+	fmt.Printf("Changing the traffic split of %d service(s) to %v\n", len(objs), inputs.Percentages)
+	result := pkg.TrafficResult{}
+	// the traffic of all the services is changed at the same time
+	var m sync.Mutex
+	pool.ForEach(ctx, len(objs), len(objs), func(ctx context.Context, i int) {
+		obj := objs[i]
+		measurement, err := runTrafficChange(ctx, ksvcClient, inputs, obj.Namespace, obj.Service)
+		if err != nil {
+			fmt.Printf("failed to measure traffic change of service %s/%s and skip: %s\n", obj.Namespace, obj.Service.Name, err)
+			return
+		}
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Service %s: Route Converged Duration is %fs\n", measurement.ServiceName, measurement.Converged)
+			fmt.Printf("[Verbose] Service %s: - Traffic Change Observed Duration is %fs\n", measurement.ServiceName, measurement.Observed)
+		}
+		m.Lock()
+		result.Measurement = append(result.Measurement, measurement)
+		m.Unlock()
+	})
+
+	sort.Slice(result.Measurement, func(i, j int) bool {
+		if result.Measurement[i].ServiceNamespace != result.Measurement[j].ServiceNamespace {
+			return result.Measurement[i].ServiceNamespace < result.Measurement[j].ServiceNamespace
+		}
+		return result.Measurement[i].ServiceName < result.Measurement[j].ServiceName
+	})
+
+	result.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	rows := [][]string{{"svc_name", "svc_namespace", "targets", "observed", "converged"}}
+	converged := make([]float64, 0, len(result.Measurement))
+	for _, r := range result.Measurement {
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace,
+			strconv.Itoa(r.Targets),
+			fmt.Sprintf("%f", r.Observed),
+			fmt.Sprintf("%f", r.Converged),
+		})
+		converged = append(converged, r.Converged)
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Traffic Split Measurement:\n")
+	fmt.Printf("Total: %d | Measured: %d Failed: %d\n", len(objs), len(result.Measurement), len(objs)-len(result.Measurement))
+	if len(converged) > 0 {
+		p50, _ := stats.Percentile(converged, 50)
+		p95, _ := stats.Percentile(converged, 95)
+		max, _ := stats.Max(converged)
+		fmt.Printf("Route Converged Duration: P50 %fs P95 %fs Max %fs\n", p50, p95, max)
+	}
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    TrafficOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// runTrafficChange sets the percentages on the traffic targets of the service and polls it until the change is
+// observed and the Route routes the traffic accordingly
+func runTrafficChange(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, inputs pkg.TrafficMeasureArgs, namespace string, svc *servingv1.Service) (pkg.TrafficMeasurement, error) {
+	measurement := pkg.TrafficMeasurement{
+		ServiceName:      svc.Name,
+		ServiceNamespace: namespace,
+		Targets:          len(svc.Spec.Traffic),
+	}
+	if len(svc.Spec.Traffic) != len(inputs.Percentages) {
+		return measurement, fmt.Errorf("service has %d traffic targets, but %d percentages are given", len(svc.Spec.Traffic), len(inputs.Percentages))
+	}
+	traffic := make([]servingv1.TrafficTarget, len(svc.Spec.Traffic))
+	changed := false
+	for i, t := range svc.Spec.Traffic {
+		traffic[i] = *t.DeepCopy()
+		traffic[i].Percent = ptr.Int64(int64(inputs.Percentages[i]))
+		changed = changed || t.Percent == nil || *t.Percent != int64(inputs.Percentages[i])
+	}
+	if !changed {
+		return measurement, fmt.Errorf("traffic is already split %v", inputs.Percentages)
+	}
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"traffic": traffic}})
+	if err != nil {
+		return measurement, err
+	}
+
+	start := time.Now()
+	updated, err := ksvcClient.Services(namespace).Patch(ctx, svc.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to change the traffic: %w", err)
+	}
+
+	err = wait.PollImmediate(updatePollInterval, inputs.Timeout, func() (bool, error) {
+		current, err := ksvcClient.Services(namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if measurement.Observed == 0 {
+			if current.Status.ObservedGeneration < updated.Generation {
+				return false, nil
+			}
+			measurement.Observed = time.Since(start).Seconds()
+		}
+		if !current.Status.GetCondition(servingv1.ServiceConditionRoutesReady).IsTrue() ||
+			!trafficConverged(traffic, current) {
+			return false, nil
+		}
+		measurement.Converged = time.Since(start).Seconds()
+		return true, nil
+	})
+	if err != nil {
+		if measurement.Observed == 0 {
+			return measurement, fmt.Errorf("traffic change is not observed: %w", err)
+		}
+		return measurement, fmt.Errorf("route doesn't converge to the traffic split: %w", err)
+	}
+	return measurement, nil
+}
+
+// trafficConverged returns whether the traffic of the service is routed to the revisions as specified, targets of
+// the latest revision are routed to the latest ready revision
+func trafficConverged(spec []servingv1.TrafficTarget, svc *servingv1.Service) bool {
+	expected := map[string]int64{}
+	for _, t := range spec {
+		revision := t.RevisionName
+		if t.LatestRevision != nil && *t.LatestRevision {
+			revision = svc.Status.LatestReadyRevisionName
+		}
+		if t.Percent != nil {
+			expected[revision] += *t.Percent
+		}
+	}
+	for revision, percent := range expected {
+		if trafficPercent(svc.Status.Traffic, revision) != percent {
+			return false
+		}
+	}
+	return true
+}
+
+// RevisionName returns the name of the i-th generated revision of a service, starting at 1
+func RevisionName(service string, i int) string {
+	return fmt.Sprintf("%s-rev-%d", service, i)
+}
+
+// addRevisions creates the revisions 2 to n of a generated service, and splits the traffic evenly across all of them
+// with the remainder going to the latest revision
+func addRevisions(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, name string, n int, timeout time.Duration) error {
+	if err := CreateRevisions(ctx, ksvcClient, namespace, name, n, timeout); err != nil {
+		return err
+	}
+
+	traffic := make([]servingv1.TrafficTarget, n)
+	for i := range traffic {
+		percent := 100 / n
+		if i == n-1 {
+			percent = 100 - (n-1)*(100/n)
+		}
+		traffic[i] = servingv1.TrafficTarget{RevisionName: RevisionName(name, i+1), Percent: ptr.Int64(int64(percent))}
+	}
+	patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"traffic": traffic}})
+	if err != nil {
+		return err
+	}
+	if _, err := ksvcClient.Services(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to split the traffic: %w", err)
+	}
+	return nil
+}
+
+// CreateRevisions creates the revisions 2 to n of a generated service, whose revision template is named
+// RevisionName(name, 1), by changing its revision template one after the other. Knative only creates a revision for
+// the latest generation it observed, so every revision is awaited before the next change.
+func CreateRevisions(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, name string, n int, timeout time.Duration) error {
+	for i := 2; i <= n; i++ {
+		svc, err := waitRevisionCreated(ctx, ksvcClient, namespace, name, RevisionName(name, i-1), timeout)
+		if err != nil {
+			return err
+		}
+		patch, err := revisionPatch(svc, i)
+		if err != nil {
+			return err
+		}
+		if _, err := ksvcClient.Services(namespace).Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to create revision %s: %w", RevisionName(name, i), err)
+		}
+	}
+	_, err := waitRevisionCreated(ctx, ksvcClient, namespace, name, RevisionName(name, n), timeout)
+	return err
+}
+
+// revisionPatch returns the JSON patch which renames the revision template to the i-th revision and sets its index
+// as environment variable, so that the template changes
+func revisionPatch(svc *servingv1.Service, i int) ([]byte, error) {
+	patch, err := updatePatch(svc, "", []string{fmt.Sprintf("%s=%d", revisionEnv, i)})
+	if err != nil {
+		return nil, err
+	}
+	ops := []map[string]interface{}{}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, err
+	}
+	ops = append(ops, map[string]interface{}{
+		"op":    "add",
+		"path":  "/spec/template/metadata/name",
+		"value": RevisionName(svc.Name, i),
+	})
+	return json.Marshal(ops)
+}
+
+// waitRevisionCreated polls the service until the revision is created and returns the service
+func waitRevisionCreated(ctx context.Context, ksvcClient servingv1client.ServingV1Interface, namespace, name, revision string, timeout time.Duration) (*servingv1.Service, error) {
+	var svc *servingv1.Service
+	err := wait.PollImmediate(updatePollInterval, timeout, func() (bool, error) {
+		var err error
+		svc, err = ksvcClient.Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return svc.Status.LatestCreatedRevisionName == revision, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("revision %s is not created: %w", revision, err)
+	}
+	return svc, nil
+}
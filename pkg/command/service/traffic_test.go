@@ -0,0 +1,182 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+// newTrafficTestService returns a service with its traffic split across the revisions ksvc-1-rev-1 and ksvc-1-rev-2
+// as given, in spec and status
+func newTrafficTestService(generation int64, percents ...int64) *servingv1.Service {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1", Generation: generation}}
+	for i, percent := range percents {
+		target := servingv1.TrafficTarget{RevisionName: RevisionName("ksvc-1", i+1), Percent: ptr.Int64(percent)}
+		svc.Spec.Traffic = append(svc.Spec.Traffic, target)
+		svc.Status.Traffic = append(svc.Status.Traffic, target)
+	}
+	svc.Status.ObservedGeneration = generation
+	svc.Status.Conditions = duckv1.Conditions{{Type: servingv1.ServiceConditionRoutesReady, Status: corev1.ConditionTrue}}
+	return svc
+}
+
+func TestNewServiceTrafficMeasureCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for service traffic-measure", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceTrafficMeasureCommand(p))
+		assert.ErrorContains(t, err, "'service traffic-measure' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewServiceTrafficMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "--percentages requires at least two traffic targets, given []")
+
+		_, err = testutil.ExecuteCommand(NewServiceTrafficMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--percentages", "120,-20")
+		assert.ErrorContains(t, err, "--percentages must not be negative, given [120 -20]")
+
+		_, err = testutil.ExecuteCommand(NewServiceTrafficMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--percentages", "50,40")
+		assert.ErrorContains(t, err, "--percentages must add up to 100, given [50 40]")
+
+		_, err = testutil.ExecuteCommand(NewServiceTrafficMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--percentages", "50,50")
+		assert.ErrorContains(t, err, "no service found to change the traffic of")
+	})
+
+	t.Run("measure traffic change as expected", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		// the route converges as soon as the service is patched
+		svc := newTrafficTestService(1, 50, 50)
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{*svc}}, nil
+		})
+		var patch map[string]map[string][]servingv1.TrafficTarget
+		fakeServing.PrependReactor("patch", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), &patch); err != nil {
+				return true, nil, err
+			}
+			return true, newTrafficTestService(2, 50, 50), nil
+		})
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if patch != nil {
+				return true, newTrafficTestService(2, 20, 80), nil
+			}
+			return true, svc, nil
+		})
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceTrafficMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--percentages", "20,80", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, newTrafficTestService(2, 20, 80).Spec.Traffic, patch["spec"]["traffic"])
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+TrafficOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+	})
+}
+
+func TestRunTrafficChange(t *testing.T) {
+	newClient := func(patched, current *servingv1.Service) *servingv1fake.FakeServingV1 {
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &clienttesting.Fake{}}
+		fakeServing.PrependReactor("patch", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, patched, nil
+		})
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, current, nil
+		})
+		return fakeServing
+	}
+	inputs := pkg.TrafficMeasureArgs{Percentages: []int{20, 80}, Timeout: time.Millisecond}
+
+	t.Run("traffic targets don't match the percentages", func(t *testing.T) {
+		fakeServing := newClient(nil, nil)
+		_, err := runTrafficChange(context.TODO(), fakeServing, inputs, "ns-1", newTrafficTestService(1, 100))
+		assert.ErrorContains(t, err, "service has 1 traffic targets, but 2 percentages are given")
+	})
+
+	t.Run("traffic already split", func(t *testing.T) {
+		fakeServing := newClient(nil, nil)
+		_, err := runTrafficChange(context.TODO(), fakeServing, inputs, "ns-1", newTrafficTestService(1, 20, 80))
+		assert.ErrorContains(t, err, "traffic is already split [20 80]")
+	})
+
+	t.Run("traffic change not observed", func(t *testing.T) {
+		fakeServing := newClient(newTrafficTestService(2, 50, 50), newTrafficTestService(1, 50, 50))
+		_, err := runTrafficChange(context.TODO(), fakeServing, inputs, "ns-1", newTrafficTestService(1, 50, 50))
+		assert.ErrorContains(t, err, "traffic change is not observed")
+	})
+
+	t.Run("route not converged", func(t *testing.T) {
+		current := newTrafficTestService(2, 50, 50)
+		current.Spec.Traffic = newTrafficTestService(2, 20, 80).Spec.Traffic
+		fakeServing := newClient(newTrafficTestService(2, 50, 50), current)
+		measurement, err := runTrafficChange(context.TODO(), fakeServing, inputs, "ns-1", newTrafficTestService(1, 50, 50))
+		assert.ErrorContains(t, err, "route doesn't converge to the traffic split")
+		assert.Check(t, measurement.Observed > 0)
+		assert.Equal(t, 2, measurement.Targets)
+	})
+
+	t.Run("route not ready", func(t *testing.T) {
+		current := newTrafficTestService(2, 20, 80)
+		current.Status.Conditions = duckv1.Conditions{{Type: servingv1.ServiceConditionRoutesReady, Status: corev1.ConditionUnknown}}
+		fakeServing := newClient(newTrafficTestService(2, 50, 50), current)
+		_, err := runTrafficChange(context.TODO(), fakeServing, inputs, "ns-1", newTrafficTestService(1, 50, 50))
+		assert.ErrorContains(t, err, "route doesn't converge to the traffic split")
+	})
+}
+
+func TestTrafficConverged(t *testing.T) {
+	svc := newTrafficTestService(1, 20, 80)
+	svc.Status.LatestReadyRevisionName = RevisionName("ksvc-1", 2)
+	spec := []servingv1.TrafficTarget{
+		{RevisionName: RevisionName("ksvc-1", 1), Percent: ptr.Int64(20)},
+		{LatestRevision: ptr.Bool(true), Percent: ptr.Int64(80)},
+	}
+	assert.Check(t, trafficConverged(spec, svc))
+
+	svc.Status.LatestReadyRevisionName = RevisionName("ksvc-1", 1)
+	assert.Check(t, !trafficConverged(spec, svc))
+}
@@ -0,0 +1,352 @@
+// Copyright 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/report"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+)
+
+const (
+	UpdateOutputFilename = "ksvc_update_time"
+
+	// updatePollInterval is the interval to poll the rollout of an updated service
+	updatePollInterval = time.Second
+)
+
+func NewServiceUpdateMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	updateArgs := pkg.UpdateMeasureArgs{}
+	serviceUpdateMeasureCommand := &cobra.Command{
+		Use:   "update-measure",
+		Short: "Update Knative service and measure the rollout time",
+		Long: `Update the revision template of Knative services and measure the rollout of the new revision
+
+The rollout is broken down into the time until the new revision is ready, the time until it receives 100% of the
+traffic, and the time the old revision takes to scale down after the traffic shift. The traffic at risk is the
+window in which pods of the old revision are draining, from the first one seen terminating until all are gone.
+
+For example:
+# To measure the rollout of a new image to the Knative Services with prefix svc in namespace ns
+kperf service update-measure --svc-prefix svc --namespace ns --image gcr.io/knative-samples/helloworld-go:v2
+
+# To measure the rollout of an environment variable change
+kperf service update-measure --svc-prefix svc --namespace ns --env TARGET=v2
+`,
+		Annotations: map[string]string{pkg.MutatingAnnotation: "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().NFlag() == 0 {
+				return fmt.Errorf("'service update-measure' requires flag(s)")
+			}
+			if updateArgs.Image == "" && len(updateArgs.Env) == 0 {
+				return fmt.Errorf("either --image or --env is required to update the services")
+			}
+			for _, env := range updateArgs.Env {
+				if !strings.Contains(env, "=") {
+					return fmt.Errorf("expected environment variable like KEY=VALUE, given %s", env)
+				}
+			}
+			return pkg.ValidateRunID(updateArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureUpdate(p, updateArgs)
+		},
+	}
+
+	serviceUpdateMeasureCommand.Flags().StringVarP(&updateArgs.Namespace, "namespace", "", "", "Service namespace")
+	serviceUpdateMeasureCommand.Flags().StringVarP(&updateArgs.SvcPrefix, "svc-prefix", "", "", "Service name prefix")
+	serviceUpdateMeasureCommand.Flags().BoolVarP(&updateArgs.Verbose, "verbose", "v", false, "Service verbose result")
+	serviceUpdateMeasureCommand.Flags().StringVarP(&updateArgs.NamespaceRange, "namespace-range", "", "", "Service namespace range")
+	serviceUpdateMeasureCommand.Flags().StringVarP(&updateArgs.NamespacePrefix, "namespace-prefix", "", "", "Service namespace prefix")
+	serviceUpdateMeasureCommand.Flags().IntVarP(&updateArgs.Concurrency, "concurrency", "c", 10, "Number of workers to do measurement job")
+	serviceUpdateMeasureCommand.Flags().StringVarP(&updateArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(serviceUpdateMeasureCommand.Flags(), &updateArgs.OutputFormats)
+	serviceUpdateMeasureCommand.Flags().StringVarP(&updateArgs.RunID, "run-id", "", "", "ID of the run, e.g. as printed by generate, the results are written to the subdirectory of the output location named by it")
+	serviceUpdateMeasureCommand.Flags().StringVarP(&updateArgs.Image, "image", "", "", "Image to set in the revision template of the services")
+	serviceUpdateMeasureCommand.Flags().StringArrayVarP(&updateArgs.Env, "env", "", nil, "Environment variable KEY=VALUE to set in the revision template of the services, can be repeated")
+	serviceUpdateMeasureCommand.Flags().DurationVarP(&updateArgs.Timeout, "timeout", "", 5*time.Minute, "Duration to wait for the rollout of a Knative Service including the scale down of the old revision")
+	return serviceUpdateMeasureCommand
+}
+
+// MeasureUpdate used to update Knative Services and measure the rollout of their new revisions
+func MeasureUpdate(params *pkg.PerfParams, inputs pkg.UpdateMeasureArgs) error {
+	ctx := context.Background()
+	nsNameList, err := GetNamespaces(ctx, params, inputs.Namespace, inputs.NamespaceRange, inputs.NamespacePrefix)
+	if err != nil {
+		return err
+	}
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	objs := getServices(ctx, ksvcClient, nsNameList, inputs.SvcPrefix)
+	if len(objs) == 0 {
+		return fmt.Errorf("no service found to update")
+	}
+
+	result := pkg.UpdateResult{}
+	var m sync.Mutex
+	pool.ForEach(ctx, inputs.Concurrency, len(objs), func(ctx context.Context, i int) {
+		obj := objs[i]
+		measurement, err := runUpdate(ctx, params, ksvcClient, inputs, obj.Namespace, obj.Service)
+		if err != nil {
+			fmt.Printf("failed to measure update of service %s/%s and skip: %s\n", obj.Namespace, obj.Service.Name, err)
+			return
+		}
+		if inputs.Verbose {
+			fmt.Printf("[Verbose] Service %s: Rollout Duration is %fs\n", measurement.ServiceName, measurement.Total)
+			fmt.Printf("[Verbose] Service %s: - Revision %s Ready Duration is %fs\n", measurement.ServiceName, measurement.NewRevision, measurement.RevisionReady)
+			fmt.Printf("[Verbose] Service %s: - Traffic Shifted Duration is %fs\n", measurement.ServiceName, measurement.TrafficShifted)
+			fmt.Printf("[Verbose] Service %s: - Revision %s Scaled Down Duration is %fs\n", measurement.ServiceName, measurement.OldRevision, measurement.OldRevisionScaledDown)
+			fmt.Printf("[Verbose] Service %s: - Traffic At Risk Duration is %fs, %fs of it before the traffic shift\n", measurement.ServiceName, measurement.TrafficAtRisk, measurement.DrainBeforeTrafficShifted)
+		}
+		m.Lock()
+		result.Measurement = append(result.Measurement, measurement)
+		m.Unlock()
+	})
+
+	sort.Slice(result.Measurement, func(i, j int) bool {
+		if result.Measurement[i].ServiceNamespace != result.Measurement[j].ServiceNamespace {
+			return result.Measurement[i].ServiceNamespace < result.Measurement[j].ServiceNamespace
+		}
+		return result.Measurement[i].ServiceName < result.Measurement[j].ServiceName
+	})
+
+	result.KnativeInfo = measure.GetKnativeInfo(context.TODO(), params, measure.DefaultLogger)
+
+	rows := [][]string{{"svc_name", "svc_namespace", "old_revision", "new_revision", "revision_ready",
+		"traffic_shifted", "old_revision_scaled_down", "old_revision_drain_started", "traffic_at_risk",
+		"drain_before_traffic_shifted", "total"}}
+	for _, r := range result.Measurement {
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace, r.OldRevision, r.NewRevision,
+			fmt.Sprintf("%f", r.RevisionReady),
+			fmt.Sprintf("%f", r.TrafficShifted),
+			fmt.Sprintf("%f", r.OldRevisionScaledDown),
+			fmt.Sprintf("%f", r.OldRevisionDrainStarted),
+			fmt.Sprintf("%f", r.TrafficAtRisk),
+			fmt.Sprintf("%f", r.DrainBeforeTrafficShifted),
+			fmt.Sprintf("%f", r.Total),
+		})
+	}
+
+	fmt.Printf("-------- Measurement --------\n")
+	fmt.Printf("Update Rollout Measurement:\n")
+	fmt.Printf("Total: %d | Measured: %d Failed: %d\n", len(objs), len(result.Measurement), len(objs)-len(result.Measurement))
+	writeTrafficAtRisk(os.Stdout, result.Measurement)
+
+	current := time.Now()
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+
+	return report.Write(os.Stdout, outputLocation, current.Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    UpdateOutputFilename,
+		Rows:    rows,
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	})
+}
+
+// runUpdate applies the template change to the service and polls it until the new revision is ready and
+// receives all the traffic, and until the pods of the previously ready revision are gone
+func runUpdate(ctx context.Context, params *pkg.PerfParams, ksvcClient servingv1client.ServingV1Interface, inputs pkg.UpdateMeasureArgs, namespace string, svc *servingv1.Service) (pkg.UpdateMeasurement, error) {
+	measurement := pkg.UpdateMeasurement{
+		ServiceName:      svc.Name,
+		ServiceNamespace: namespace,
+		OldRevision:      svc.Status.LatestReadyRevisionName,
+	}
+	patch, err := updatePatch(svc, inputs.Image, inputs.Env)
+	if err != nil {
+		return measurement, err
+	}
+
+	start := time.Now()
+	updated, err := ksvcClient.Services(namespace).Patch(ctx, svc.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return measurement, fmt.Errorf("failed to update service: %w", err)
+	}
+	fmt.Printf("Updated service %s/%s, waiting for the rollout\n", namespace, svc.Name)
+
+	var trafficShifted, drainStarted time.Duration
+	err = wait.PollImmediate(updatePollInterval, inputs.Timeout, func() (bool, error) {
+		// the pods of the old revision are watched from the update on, so that a drain starting before the traffic
+		// shift is seen
+		oldPods := 0
+		if measurement.OldRevision != "" {
+			selector := labels.SelectorFromSet(labels.Set{
+				serving.RevisionLabelKey: measurement.OldRevision,
+			}).String()
+			podList, err := params.ClientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return false, err
+			}
+			oldPods = len(podList.Items)
+			if drainStarted == 0 && terminating(podList.Items) {
+				drainStarted = time.Since(start)
+				measurement.OldRevisionDrainStarted = drainStarted.Seconds()
+			}
+		}
+
+		if trafficShifted == 0 {
+			current, err := ksvcClient.Services(namespace).Get(ctx, svc.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			if measurement.NewRevision == "" {
+				if current.Status.ObservedGeneration < updated.Generation ||
+					current.Status.LatestReadyRevisionName == measurement.OldRevision ||
+					current.Status.LatestReadyRevisionName != current.Status.LatestCreatedRevisionName {
+					return false, nil
+				}
+				measurement.NewRevision = current.Status.LatestReadyRevisionName
+				measurement.RevisionReady = time.Since(start).Seconds()
+			}
+			if trafficPercent(current.Status.Traffic, measurement.NewRevision) < 100 {
+				return false, nil
+			}
+			trafficShifted = time.Since(start)
+			measurement.TrafficShifted = trafficShifted.Seconds()
+		}
+
+		if oldPods > 0 {
+			return false, nil
+		}
+		total := time.Since(start)
+		measurement.Total = total.Seconds()
+		measurement.OldRevisionScaledDown = measurement.Total - trafficShifted.Seconds()
+		if drainStarted > 0 {
+			measurement.TrafficAtRisk = (total - drainStarted).Seconds()
+			if drainStarted < trafficShifted {
+				measurement.DrainBeforeTrafficShifted = (trafficShifted - drainStarted).Seconds()
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		switch {
+		case measurement.NewRevision == "":
+			return measurement, fmt.Errorf("new revision is not ready: %w", err)
+		case trafficShifted == 0:
+			return measurement, fmt.Errorf("traffic is not shifted to revision %s: %w", measurement.NewRevision, err)
+		default:
+			return measurement, fmt.Errorf("revision %s is not scaled down: %w", measurement.OldRevision, err)
+		}
+	}
+	return measurement, nil
+}
+
+// writeTrafficAtRisk writes the sum and the longest of the windows in which requests were served by draining pods of
+// the old revisions
+func writeTrafficAtRisk(out io.Writer, measurements []pkg.UpdateMeasurement) {
+	var total, longest, beforeShift float64
+	var drained int
+	for _, m := range measurements {
+		if m.TrafficAtRisk == 0 {
+			continue
+		}
+		drained++
+		total += m.TrafficAtRisk
+		beforeShift += m.DrainBeforeTrafficShifted
+		if m.TrafficAtRisk > longest {
+			longest = m.TrafficAtRisk
+		}
+	}
+	if drained == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Traffic At Risk: %fs in %d rollout(s), longest %fs, %fs of it before the traffic shift\n", total, drained, longest, beforeShift)
+}
+
+// terminating returns true if one of the pods is terminating
+func terminating(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// trafficPercent returns the percentage of the traffic routed to the revision
+func trafficPercent(traffic []servingv1.TrafficTarget, revision string) int64 {
+	var percent int64
+	for _, t := range traffic {
+		if t.RevisionName == revision && t.Percent != nil {
+			percent += *t.Percent
+		}
+	}
+	return percent
+}
+
+// updatePatch returns the JSON patch setting the image and the environment variables of the first container
+// of the revision template, existing environment variables with the same name are replaced
+func updatePatch(svc *servingv1.Service, image string, env []string) ([]byte, error) {
+	if len(svc.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("service %s has no container to update", svc.Name)
+	}
+	ops := []map[string]interface{}{}
+	if image != "" {
+		ops = append(ops, map[string]interface{}{
+			"op":    "replace",
+			"path":  "/spec/template/spec/containers/0/image",
+			"value": image,
+		})
+	}
+	if len(env) > 0 {
+		vars := append([]corev1.EnvVar{}, svc.Spec.Template.Spec.Containers[0].Env...)
+		for _, e := range env {
+			kv := strings.SplitN(e, "=", 2)
+			replaced := false
+			for i := range vars {
+				if vars[i].Name == kv[0] {
+					vars[i] = corev1.EnvVar{Name: kv[0], Value: kv[1]}
+					replaced = true
+				}
+			}
+			if !replaced {
+				vars = append(vars, corev1.EnvVar{Name: kv[0], Value: kv[1]})
+			}
+		}
+		ops = append(ops, map[string]interface{}{
+			"op":    "add",
+			"path":  "/spec/template/spec/containers/0/env",
+			"value": vars,
+		})
+	}
+	return json.Marshal(ops)
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func newUpdateTestService(latestRevision string) *servingv1.Service {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}}
+	svc.Spec.Template.Spec.Containers = []corev1.Container{{
+		Image: "helloworld:v1",
+		Env:   []corev1.EnvVar{{Name: "TARGET", Value: "v1"}},
+	}}
+	svc.Status.LatestCreatedRevisionName = latestRevision
+	svc.Status.LatestReadyRevisionName = latestRevision
+	svc.Status.Traffic = []servingv1.TrafficTarget{{RevisionName: latestRevision, Percent: ptr.Int64(100)}}
+	return svc
+}
+
+func TestNewServiceUpdateMeasureCommand(t *testing.T) {
+	t.Run("incompleted or wrong args for service update-measure", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		_, err := testutil.ExecuteCommand(NewServiceUpdateMeasureCommand(p))
+		assert.ErrorContains(t, err, "'service update-measure' requires flag(s)")
+
+		_, err = testutil.ExecuteCommand(NewServiceUpdateMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1")
+		assert.ErrorContains(t, err, "either --image or --env is required")
+
+		_, err = testutil.ExecuteCommand(NewServiceUpdateMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--env", "TARGET")
+		assert.ErrorContains(t, err, "expected environment variable like KEY=VALUE, given TARGET")
+
+		_, err = testutil.ExecuteCommand(NewServiceUpdateMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--image", "helloworld:v2")
+		assert.ErrorContains(t, err, "no service found to update")
+	})
+
+	t.Run("measure update as expected", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}})
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		p := &pkg.PerfParams{
+			ClientSet: client,
+			NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+				return fakeServing, nil
+			},
+		}
+
+		// the service is rolled out to the new revision as soon as it is patched
+		svc := newUpdateTestService("ksvc-1-00001")
+		fakeServing.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.ServiceList{Items: []servingv1.Service{*svc}}, nil
+		})
+		patched := false
+		fakeServing.PrependReactor("patch", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			patched = true
+			return true, svc, nil
+		})
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if patched {
+				return true, newUpdateTestService("ksvc-1-00002"), nil
+			}
+			return true, svc, nil
+		})
+
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewServiceUpdateMeasureCommand(p), "--svc-prefix", "ksvc", "--namespace", "ns-1", "--env", "TARGET=v2", "--output", outputDir, "-v")
+		assert.NilError(t, err)
+		assert.Assert(t, patched)
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "*_"+UpdateOutputFilename+".csv"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+	})
+}
+
+func TestRunUpdate(t *testing.T) {
+	newParams := func(objects ...runtime.Object) (*pkg.PerfParams, *servingv1fake.FakeServingV1) {
+		client := k8sfake.NewSimpleClientset(objects...)
+		fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+		fakeServing.PrependReactor("patch", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newUpdateTestService("ksvc-1-00001"), nil
+		})
+		return &pkg.PerfParams{ClientSet: client}, fakeServing
+	}
+	inputs := pkg.UpdateMeasureArgs{Image: "helloworld:v2", Timeout: time.Millisecond}
+
+	t.Run("new revision not ready", func(t *testing.T) {
+		p, fakeServing := newParams()
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			svc := newUpdateTestService("ksvc-1-00001")
+			svc.Status.LatestCreatedRevisionName = "ksvc-1-00002"
+			return true, svc, nil
+		})
+
+		_, err := runUpdate(context.TODO(), p, fakeServing, inputs, "ns-1", newUpdateTestService("ksvc-1-00001"))
+		assert.ErrorContains(t, err, "new revision is not ready")
+	})
+
+	t.Run("traffic not shifted", func(t *testing.T) {
+		p, fakeServing := newParams()
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			svc := newUpdateTestService("ksvc-1-00002")
+			svc.Status.Traffic = []servingv1.TrafficTarget{
+				{RevisionName: "ksvc-1-00001", Percent: ptr.Int64(50)},
+				{RevisionName: "ksvc-1-00002", Percent: ptr.Int64(50)},
+			}
+			return true, svc, nil
+		})
+
+		measurement, err := runUpdate(context.TODO(), p, fakeServing, inputs, "ns-1", newUpdateTestService("ksvc-1-00001"))
+		assert.ErrorContains(t, err, "traffic is not shifted to revision ksvc-1-00002")
+		assert.Equal(t, "ksvc-1-00002", measurement.NewRevision)
+	})
+
+	t.Run("old revision not scaled down", func(t *testing.T) {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "ksvc-1-00001-pod",
+			Namespace: "ns-1",
+			Labels:    map[string]string{serving.RevisionLabelKey: "ksvc-1-00001"},
+		}}
+		p, fakeServing := newParams(pod)
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newUpdateTestService("ksvc-1-00002"), nil
+		})
+
+		_, err := runUpdate(context.TODO(), p, fakeServing, inputs, "ns-1", newUpdateTestService("ksvc-1-00001"))
+		assert.ErrorContains(t, err, "revision ksvc-1-00001 is not scaled down")
+	})
+
+	t.Run("old revision draining", func(t *testing.T) {
+		now := metav1.Now()
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-pod",
+			Namespace:         "ns-1",
+			Labels:            map[string]string{serving.RevisionLabelKey: "ksvc-1-00001"},
+			DeletionTimestamp: &now,
+		}}
+		p, fakeServing := newParams()
+		drained := false
+		fakeServing.PrependReactor("list", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			// the pod is gone once the traffic is shifted
+			if drained {
+				return true, &corev1.PodList{}, nil
+			}
+			return true, &corev1.PodList{Items: []corev1.Pod{*pod}}, nil
+		})
+		fakeServing.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			drained = true
+			return true, newUpdateTestService("ksvc-1-00002"), nil
+		})
+
+		measurement, err := runUpdate(context.TODO(), p, fakeServing, pkg.UpdateMeasureArgs{Image: "helloworld:v2", Timeout: time.Second}, "ns-1", newUpdateTestService("ksvc-1-00001"))
+		assert.NilError(t, err)
+		assert.Assert(t, measurement.OldRevisionDrainStarted > 0)
+		assert.Assert(t, measurement.TrafficAtRisk > 0)
+		assert.Assert(t, measurement.DrainBeforeTrafficShifted > 0)
+		assert.Assert(t, math.Abs(measurement.Total-measurement.OldRevisionDrainStarted-measurement.TrafficAtRisk) < 1e-6)
+	})
+}
+
+func TestWriteTrafficAtRisk(t *testing.T) {
+	out := &bytes.Buffer{}
+	writeTrafficAtRisk(out, []pkg.UpdateMeasurement{{TrafficAtRisk: 2, DrainBeforeTrafficShifted: 1}, {TrafficAtRisk: 3}, {}})
+	assert.Equal(t, "Traffic At Risk: 5.000000s in 2 rollout(s), longest 3.000000s, 1.000000s of it before the traffic shift\n", out.String())
+
+	out.Reset()
+	writeTrafficAtRisk(out, []pkg.UpdateMeasurement{{}})
+	assert.Equal(t, "", out.String())
+}
+
+func TestUpdatePatch(t *testing.T) {
+	svc := newUpdateTestService("ksvc-1-00001")
+
+	patch, err := updatePatch(svc, "helloworld:v2", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, `[{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"helloworld:v2"}]`, string(patch))
+
+	patch, err = updatePatch(svc, "", []string{"TARGET=v2", "MODE=a=b"})
+	assert.NilError(t, err)
+	assert.Equal(t, `[{"op":"add","path":"/spec/template/spec/containers/0/env","value":[{"name":"TARGET","value":"v2"},{"name":"MODE","value":"a=b"}]}]`, string(patch))
+	assert.Equal(t, "v1", svc.Spec.Template.Spec.Containers[0].Env[0].Value)
+
+	_, err = updatePatch(&servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1"}}, "helloworld:v2", nil)
+	assert.ErrorContains(t, err, "service ksvc-1 has no container to update")
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// waveStats holds the Create calls of a wave of the generation
+type waveStats struct {
+	created int
+	failed  int
+	total   time.Duration
+	max     time.Duration
+}
+
+// waveRecorder records the latency of the Create calls per wave, so that the degradation of the control plane with
+// the growing number of objects can be seen
+type waveRecorder struct {
+	batch int
+	mu    sync.Mutex
+	waves map[int]*waveStats
+}
+
+func newWaveRecorder(batch int) *waveRecorder {
+	if batch <= 0 {
+		batch = 1
+	}
+	return &waveRecorder{batch: batch, waves: map[int]*waveStats{}}
+}
+
+// record records the Create call of the service with the index which took latency
+func (w *waveRecorder) record(index int, latency time.Duration, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wave := index / w.batch
+	stats, ok := w.waves[wave]
+	if !ok {
+		stats = &waveStats{}
+		w.waves[wave] = stats
+	}
+	if err != nil {
+		stats.failed++
+		return
+	}
+	stats.created++
+	stats.total += latency
+	if latency > stats.max {
+		stats.max = latency
+	}
+}
+
+// write writes a line per wave with the number of services created so far and the latency of its Create calls
+func (w *waveRecorder) write(out io.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	waves := make([]int, 0, len(w.waves))
+	for wave := range w.waves {
+		waves = append(waves, wave)
+	}
+	sort.Ints(waves)
+	fmt.Fprintf(out, "\nWave Measurement:\n")
+	total := 0
+	for _, wave := range waves {
+		stats := w.waves[wave]
+		total += stats.created
+		var average time.Duration
+		if stats.created > 0 {
+			average = stats.total / time.Duration(stats.created)
+		}
+		fmt.Fprintf(out, "wave-%d: Created: %d Failed: %d Total: %d | Create Average: %fs Max: %fs\n",
+			wave+1, stats.created, stats.failed, total, average.Seconds(), stats.max.Seconds())
+	}
+}
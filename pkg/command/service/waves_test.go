@@ -0,0 +1,40 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWaveRecorder(t *testing.T) {
+	w := newWaveRecorder(2)
+	w.record(2, 300*time.Millisecond, nil)
+	w.record(0, 100*time.Millisecond, nil)
+	w.record(1, 200*time.Millisecond, nil)
+	w.record(3, 0, errors.New("throttled"))
+
+	out := &bytes.Buffer{}
+	w.write(out)
+	assert.Equal(t, `
+Wave Measurement:
+wave-1: Created: 2 Failed: 0 Total: 2 | Create Average: 0.150000s Max: 0.200000s
+wave-2: Created: 1 Failed: 1 Total: 3 | Create Average: 0.300000s Max: 0.300000s
+`, out.String())
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// namespaceWeight is the share of the generated services of a namespace given by --namespace-weights
+type namespaceWeight struct {
+	namespace string
+	weight    int
+}
+
+// parseNamespaceWeights parses the weights like ns-1:50, the namespaces are kept in the given order
+func parseNamespaceWeights(specs []string) ([]namespaceWeight, error) {
+	weights := make([]namespaceWeight, 0, len(specs))
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		kv := strings.SplitN(spec, ":", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("expected namespace weight like ns-1:50, given %s", spec)
+		}
+		weight, err := strconv.Atoi(kv[1])
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("expected positive weight of namespace %s, given %s", kv[0], kv[1])
+		}
+		if seen[kv[0]] {
+			return nil, fmt.Errorf("--namespace-weights contains the namespace %s twice", kv[0])
+		}
+		seen[kv[0]] = true
+		weights = append(weights, namespaceWeight{namespace: kv[0], weight: weight})
+	}
+	return weights, nil
+}
+
+// weightedNamespaces returns the sequence of namespaces the generated services are created in one after the other,
+// every namespace appears in it as often as its share of the weights, e.g. ns-1:50,ns-2:30,ns-3:20 gives a sequence
+// of 10 with ns-1 5 times. The namespaces are interleaved by the smooth weighted round-robin, so that every batch is
+// spread like the whole run instead of filling one namespace after the other.
+func weightedNamespaces(weights []namespaceWeight) []string {
+	divisor := 0
+	for _, w := range weights {
+		divisor = gcd(divisor, w.weight)
+	}
+	total := 0
+	for _, w := range weights {
+		total += w.weight / divisor
+	}
+	current := make([]int, len(weights))
+	namespaces := make([]string, 0, total)
+	for len(namespaces) < total {
+		next := 0
+		for i, w := range weights {
+			current[i] += w.weight / divisor
+			if current[i] > current[next] {
+				next = i
+			}
+		}
+		current[next] -= total
+		namespaces = append(namespaces, weights[next].namespace)
+	}
+	return namespaces
+}
+
+// gcd returns the greatest common divisor of a and b
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package service
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseNamespaceWeights(t *testing.T) {
+	weights, err := parseNamespaceWeights([]string{"ns-1:50", "ns-2:30", "ns-3:20"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []namespaceWeight{{"ns-1", 50}, {"ns-2", 30}, {"ns-3", 20}}, weights, cmp.AllowUnexported(namespaceWeight{}))
+
+	for spec, expected := range map[string]string{
+		"ns-1":     "expected namespace weight like ns-1:50, given ns-1",
+		":50":      "expected namespace weight like ns-1:50, given :50",
+		"ns-1:x":   "expected positive weight of namespace ns-1, given x",
+		"ns-1:0":   "expected positive weight of namespace ns-1, given 0",
+		"ns-1:-10": "expected positive weight of namespace ns-1, given -10",
+	} {
+		_, err := parseNamespaceWeights([]string{spec})
+		assert.ErrorContains(t, err, expected)
+	}
+
+	_, err = parseNamespaceWeights([]string{"ns-1:50", "ns-1:50"})
+	assert.ErrorContains(t, err, "--namespace-weights contains the namespace ns-1 twice")
+}
+
+func TestWeightedNamespaces(t *testing.T) {
+	namespaces := weightedNamespaces([]namespaceWeight{{"ns-1", 50}, {"ns-2", 30}, {"ns-3", 20}})
+	assert.DeepEqual(t, []string{"ns-1", "ns-2", "ns-3", "ns-1", "ns-1", "ns-2", "ns-1", "ns-3", "ns-2", "ns-1"}, namespaces)
+
+	counts := map[string]int{}
+	for _, ns := range weightedNamespaces([]namespaceWeight{{"ns-1", 7}, {"ns-2", 3}}) {
+		counts[ns]++
+	}
+	assert.DeepEqual(t, map[string]int{"ns-1": 7, "ns-2": 3}, counts)
+
+	assert.DeepEqual(t, []string{"ns-1"}, weightedNamespaces([]namespaceWeight{{"ns-1", 40}}))
+}
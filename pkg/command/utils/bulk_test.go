@@ -0,0 +1,170 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+var testRecords = []pkg.MeasureRecord{
+	{RunTimestamp: "2022-01-01T00:00:00Z", ServiceName: "ksvc-1", ServiceNamespace: "ns-1", OverallReady: 1.5},
+	{RunTimestamp: "2022-01-01T00:00:00Z", ServiceName: "ksvc-2", ServiceNamespace: "ns-1", OverallReady: 2.5},
+}
+
+func TestParseBulkFormats(t *testing.T) {
+	formats, err := ParseBulkFormats("")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(formats))
+
+	formats, err = ParseBulkFormats("ndjson, Parquet")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{BulkFormatNDJSON, BulkFormatParquet}, formats)
+
+	_, err = ParseBulkFormats("ndjson,xml")
+	assert.ErrorContains(t, err, "unsupported bulk format \"xml\"")
+}
+
+func TestGenerateNDJSONFile(t *testing.T) {
+	path := "/tmp/generate-ndjson-test.ndjson"
+	err := GenerateNDJSONFile(path, testRecords, true)
+	assert.NilError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Assert(t, strings.Contains(lines[0], "\"svc_name\":\"ksvc-1\""))
+	assert.Assert(t, strings.Contains(lines[1], "\"overall_ready\":2.5"))
+
+	err = GenerateNDJSONFile("/tmp", testRecords, true)
+	assert.ErrorContains(t, err, "failed to create ndjson file open /tmp: is a directory")
+}
+
+func TestGenerateParquetFile(t *testing.T) {
+	path := "/tmp/generate-parquet-test.parquet"
+	err := GenerateParquetFile(path, testRecords, true)
+	assert.NilError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+
+	err = GenerateParquetFile("/tmp", testRecords, true)
+	assert.ErrorContains(t, err, "failed to create parquet file open /tmp: is a directory")
+}
+
+func TestGenerateRawParquetFile(t *testing.T) {
+	created := int64(1641038400000)
+	records := []pkg.MeasureRawRecord{
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", ServiceCreated: &created, RouteReady: &created},
+		{ServiceName: "ksvc-2", ServiceNamespace: "ns-1", ServiceCreated: &created},
+	}
+	path := "/tmp/generate-raw-parquet-test.parquet"
+	err := GenerateRawParquetFile(path, records, true)
+	assert.NilError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+	assert.Assert(t, strings.Contains(string(data), "sks_activator_endpoints_populated"))
+
+	err = GenerateRawParquetFile("/tmp", records, true)
+	assert.ErrorContains(t, err, "failed to create parquet file open /tmp: is a directory")
+}
+
+func TestInsertClickHouse(t *testing.T) {
+	t.Run("insert rows successfully", func(t *testing.T) {
+		var query, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("query")
+			data, _ := ioutil.ReadAll(r.Body)
+			body = string(data)
+		}))
+		defer server.Close()
+
+		err := InsertClickHouse(context.Background(), server.URL, "kperf", testRecords)
+		assert.NilError(t, err)
+		assert.Equal(t, "INSERT INTO kperf FORMAT JSONEachRow", query)
+		assert.Equal(t, 2, strings.Count(body, "\n"))
+
+		err = InsertClickHouse(context.Background(), server.URL, "perf.kperf", testRecords)
+		assert.NilError(t, err)
+		assert.Equal(t, "INSERT INTO perf.kperf FORMAT JSONEachRow", query)
+	})
+
+	t.Run("reject invalid tables", func(t *testing.T) {
+		for _, table := range []string{"", "kperf; DROP TABLE kperf", "a.b.c", "1kperf", "kperf "} {
+			err := InsertClickHouse(context.Background(), "http://127.0.0.1:1", table, testRecords)
+			assert.ErrorContains(t, err, "expected ClickHouse table like table or database.table", table)
+		}
+	})
+
+	t.Run("stop when the context is cancelled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := InsertClickHouse(ctx, server.URL, "kperf", testRecords)
+		assert.ErrorContains(t, err, "context canceled")
+	})
+
+	t.Run("return error if ClickHouse rejects rows", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Table default.kperf doesn't exist", http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		err := InsertClickHouse(context.Background(), server.URL, "kperf", testRecords)
+		assert.ErrorContains(t, err, "Table default.kperf doesn't exist")
+	})
+}
+
+func TestInsertBigQuery(t *testing.T) {
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	oldEndpoint, oldClient := bigQueryEndpoint, newBigQueryClient
+	defer func() { bigQueryEndpoint, newBigQueryClient = oldEndpoint, oldClient }()
+	bigQueryEndpoint = server.URL
+	newBigQueryClient = func(ctx context.Context) (*http.Client, error) {
+		return server.Client(), nil
+	}
+
+	err := InsertBigQuery(context.Background(), "project.dataset.table", testRecords)
+	assert.NilError(t, err)
+	assert.Equal(t, "/projects/project/datasets/dataset/tables/table/insertAll", path)
+	assert.Assert(t, strings.Contains(body, "{\"rows\":[{\"json\":{\"run_timestamp\""))
+
+	err = InsertBigQuery(context.Background(), "dataset.table", testRecords)
+	assert.ErrorContains(t, err, "expected BigQuery table like project.dataset.table, given dataset.table")
+}
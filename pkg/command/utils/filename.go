@@ -0,0 +1,81 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"knative.dev/kperf/pkg"
+)
+
+const (
+	// DateFormatString is the layout of the timestamps the result files are named with
+	DateFormatString = "20060102150405"
+	// TimestampPlaceholder is replaced by the timestamp of the run in --output-name
+	TimestampPlaceholder = "{timestamp}"
+)
+
+// ValidateOutputName rejects names which are no valid file name prefix, like ones with a path separator
+func ValidateOutputName(name string) error {
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("--output-name must be a file name without directories, given %q", name)
+	}
+	return nil
+}
+
+// OutputFileName returns the name of the result file with the suffix, like ksvc_creation_time.csv, written at the
+// timestamp. It starts with the timestamp, or with the name of the output options given by --output-name with the
+// placeholder {timestamp} replaced by it, so that CI systems can collect the files of a run by stable names.
+func OutputFileName(output pkg.OutputOptions, timestamp, suffix string) string {
+	prefix := timestamp
+	if output.Name != "" {
+		prefix = strings.ReplaceAll(output.Name, TimestampPlaceholder, timestamp)
+	}
+	return prefix + "_" + suffix
+}
+
+// CreateFile creates the result file for writing. An existing file is truncated with overwrite, given by --overwrite,
+// and refused otherwise, so that the results of a run written with a stable --output-name don't replace earlier ones
+// by mistake.
+func CreateFile(path string, overwrite bool) (*os.File, error) {
+	if err := CheckOverwrite(path, overwrite); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+}
+
+// CheckOverwrite returns an error if the result file exists and overwrite is not set, for files which are not
+// created by CreateFile
+func CheckOverwrite(path string, overwrite bool) error {
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && !overwrite {
+		return fmt.Errorf("%s already exists, replace it with --overwrite", path)
+	}
+	return nil
+}
+
+// WriteFile writes the data to the result file, existing files are handled like by CreateFile
+func WriteFile(path string, data []byte, overwrite bool) error {
+	file, err := CreateFile(path, overwrite)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
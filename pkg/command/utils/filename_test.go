@@ -0,0 +1,60 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestOutputFileName(t *testing.T) {
+	assert.Equal(t, OutputFileName(pkg.OutputOptions{}, "20220101120000", "ksvc_creation_time.csv"), "20220101120000_ksvc_creation_time.csv")
+	assert.Equal(t, OutputFileName(pkg.OutputOptions{Name: "nightly"}, "20220101120000", "ksvc_creation_time.csv"), "nightly_ksvc_creation_time.csv")
+	assert.Equal(t, OutputFileName(pkg.OutputOptions{Name: "nightly-{timestamp}"}, "20220101120000", "ksvc_creation_time.csv"), "nightly-20220101120000_ksvc_creation_time.csv")
+}
+
+func TestValidateOutputName(t *testing.T) {
+	for _, name := range []string{"", "nightly", "nightly-{timestamp}", "run.1"} {
+		assert.NilError(t, ValidateOutputName(name))
+	}
+	for _, name := range []string{"a/b", `a\b`, ".", ".."} {
+		assert.ErrorContains(t, ValidateOutputName(name), "--output-name must be a file name without directories")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kperf-filename")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nightly_ksvc_creation_time.csv")
+
+	assert.NilError(t, WriteFile(path, []byte("first run\n"), false))
+	assert.ErrorContains(t, WriteFile(path, []byte("second\n"), false), "already exists, replace it with --overwrite")
+	assert.ErrorContains(t, CheckOverwrite(path, false), "already exists")
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "first run\n")
+
+	assert.NilError(t, CheckOverwrite(path, true))
+	assert.NilError(t, WriteFile(path, []byte("second\n"), true))
+	data, err = ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "second\n")
+}
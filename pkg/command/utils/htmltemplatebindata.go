@@ -1,5 +1,9 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
+// templates/grafana_dashboard.json (3.546kB)
+// templates/heatmap.html (4.089kB)
+// templates/references/README.md (973B)
+// templates/report.html (14.299kB)
 // templates/single_chart.html (18.363kB)
 
 package utils
@@ -69,7 +73,87 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _templatesSingle_chartHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x7c\x7b\x93\xdb\x36\x92\xf8\xff\xfe\x14\xbd\xca\xee\x4a\xf3\xb3\x00\x91\x20\x08\x92\xca\x68\xea\xe7\x8c\xbd\xb1\x6b\xc7\x49\x2a\x76\x7c\x77\x99\x73\xa5\x20\x12\x23\xd1\xa6\x48\x1d\x09\xcd\x23\x29\x7f\xf7\xab\x06\x48\x3d\x49\x59\x7e\xec\x56\xed\xdd\x31\x65\x11\x04\x1a\xfd\xee\x46\x03\xe4\xe4\xfc\x4f\x4f\x7f\xbc\x7c\xfd\x1f\x3f\x3d\x83\xb9\x5e\x64\x17\x8f\xce\xed\xed\xd1\xf9\x5c\xc9\xe4\xe2\x11\x00\xc0\xf9\x42\x69\x09\xf1\x5c\x96\x95\xd2\x93\xde\x4a\xdf\x90\xb0\x57\x0f\xe9\x54\x67\xea\xe2\x27\x55\xde\x40\x22\xab\xf9\xb4\x90\x65\x72\x3e\xb2\xbd\x16\xa2\x8a\xcb\x74\xa9\xa1\x2a\xe3\x49\x6f\xae\xf5\xb2\x1a\x8f\x46\x71\x92\xd3\x77\x55\xa2\xb2\xf4\xb6\xa4\xb9\xd2\xa3\x7c\xb9\x18\xbd\xfb\xaf\x95\x2a\x1f\xfe\xbf\x47\x7d\xea\x8e\x92\xb4\xd2\x75\x0f\x5d\xa4\x08\xdd\xbb\x38\x1f\x59\x5c\x9f\x8a\x58\x21\xeb\xba\xb2\x38\xeb\x07\xa2\xf2\xe3\x78\xed\x03\x5e\x71\x91\x57\x1a\x32\x35\x53\x79\xf2\x02\x1f\x60\x02\xd7\xeb\x51\xbc\xfa\x4b\xa9\xe7\xe3\xd1\xe8\xa5\xef\x32\xf0\x5d\xb6\x20\x9c\x87\xe0\x48\xfc\x35\x2d\x70\xc1\x81\x30\x12\xe0\xc0\x4e\x1f\x31\x7d\xbf\xf6\x87\xed\xe8\x38\x67\x34\x00\x97\xfb\x34\xbc\x12\x82\xba\x10\x44\x21\x65\x31\xf1\x1c\x1a\x82\xef\x51\x0f\x02\x1c\x67\x0e\x88\x88\x7a\xd8\x98\x07\xd8\x1d\x0b\x97\x22\x2d\xd7\x71\xa8\x4b\x84\xa0\x81\x01\x20\x2e\x73\xae\xfc\xd0\x45\x50\xc4\x69\x11\x11\x9c\x41\x5c\x27\x58\x37\xbd\x10\x67\xff\xde\xc5\x55\x20\x1c\x88\x84\xf3\x9c\x09\x1e\x13\xd7\x75\xa8\x0f\x0e\x61\x0e\x0a\x43\x7d\xd3\x60\x8e\xf3\x06\x47\x9d\x7a\xb8\x19\x80\x7a\x70\xce\x23\x11\xd7\x33\xb1\xcf\x00\x40\x0d\x70\x8b\x83\x0e\x98\x61\xd2\x0c\x34\xb3\x3b\x99\xe2\xc2\x87\x48\xd0\xe8\x2a\x12\xd4\x07\x4f\x70\x2a\x62\xc2\x42\x60\x0e\xe5\xc4\x8b\x50\x5f\x02\x99\x88\xa8\x8b\xd4\x78\xe6\x72\x87\x06\xc0\x3d\x8f\xba\xb1\x8b\x4d\xcf\x03\xee\x52\x0e\xbe\x8f\x7a\x45\x6d\x63\x6b\xce\x7d\x9f\xf2\xd8\xe3\x34\x00\x07\x84\x4f\x39\x61\xac\x06\x20\x08\x70\x15\x21\x62\xe0\x3e\x37\x68\x88\xe7\x11\x97\x88\x88\xba\x86\x16\x0a\xc0\xaf\x7c\x3f\x32\xcc\x21\x47\xc4\x70\x24\x84\xbd\x47\xfc\x88\xa2\x45\xe0\xd3\x08\xd0\x32\xec\xb9\xc7\x43\xea\xc6\x84\x33\x1a\xa1\xdb\x30\x54\x0b\xa3\x11\x71\x1d\x0e\xc2\xa1\xee\x55\xe8\x00\xf7\x19\xc2\x30\x94\xc2\x0b\x90\x03\x6c\x85\xcc\xb0\xee\xba\x11\x0d\x33\x57\x70\xea\x02\x0b\x39\x0d\xe3\x06\x8e\x81\x70\xa9\x6b\xb0\x40\x83\x6e\xee\xb1\x80\x86\xb1\x25\x87\x28\x7c\x14\x1b\x99\xe1\xc4\x90\x8b\x38\x07\x3f\x70\x69\x64\xd0\x10\x24\x07\xa6\x65\xc9\x11\x43\xee\x2a\x08\x22\x9c\x24\x02\xea\x59\xbe\x0c\x20\x41\x7a\x06\x0d\x69\xf0\x75\xea\xc0\x75\x1c\xe4\xd8\x77\xd9\x95\x88\x18\x78\x9e\x09\x30\x70\x11\x2f\x3e\xe0\x3f\xf3\x80\xbd\xf8\x20\x22\x96\xb9\xa1\x03\x9e\xcb\xa8\x6b\xe6\x88\x88\x75\xa2\xf7\xb9\x6b\xa4\x66\x94\x5f\x09\xc1\xc0\xe3\x01\xf5\x32\x16\x38\x94\x81\x17\x51\x2f\x66\x18\x3c\x1e\x12\x0b\xa8\x07\x9e\xa0\x02\xdc\x10\x3d\x83\x5f\x05\x3e\x3a\x85\xf0\x18\xf5\x33\x2e\x28\x03\x86\x66\x8f\x39\x15\xc0\x8c\xaf\x61\x80\x72\x41\x43\xc2\x51\x3b\x1e\xa7\xde\x15\x72\x19\x3a\x21\x0d\x33\xc2\xb8\x6b\x82\x37\x88\x11\x32\x04\x17\x95\xec\xbb\x34\x20\x01\x0d\xcc\x14\x82\x53\x0c\x6a\x62\x50\x5f\x05\x08\xc7\x39\x8b\x89\x6b\xc2\x39\xa4\x21\x09\xa9\xc0\x59\xc8\x16\x31\x6c\x79\x6b\x31\x5c\x86\xe1\xcd\x38\x1a\xdb\x65\x84\x71\xca\x90\x1f\x6e\x5b\x7e\x48\x3d\x72\x4c\xf5\x41\x24\x50\xab\x19\xda\xc8\x0d\x9d\xd8\x15\x34\x42\xa7\xf6\x08\xa7\x8c\x08\x9f\x46\xc4\x0b\xcd\xfd\xb9\xf0\xd1\xc9\x1d\x08\x45\x4c\x36\x60\x7e\x84\x9c\x63\xcb\x84\x93\x73\xe5\x05\x0e\x30\x4c\x64\xcf\x5d\x61\x5c\xd5\x43\x81\x1c\xe2\x73\x54\xb0\xa0\x82\x78\x21\xc6\x58\x84\x34\xc1\x0d\x9d\x2b\x97\x71\xb4\x9f\xc5\x0a\x88\x0b\x90\x77\x84\x81\x1a\x76\xce\x9c\xf0\x8a\x07\x1c\x22\x2f\x8c\x37\x60\x48\xdc\xb6\x2c\xf1\x46\x0c\xea\x3e\x0f\x05\x8b\x2d\x65\x40\xca\xc4\x58\xb5\x16\xe5\xaa\x96\xba\x53\x2b\xa1\x1b\x62\xde\x08\x02\xea\xcf\x89\x11\xc2\x45\x13\xa3\xae\x03\xf3\xcb\x09\x73\x3d\x1a\x10\x86\x29\xb6\x6e\x87\x91\x09\x5b\x57\x60\x66\xe5\x81\xe9\x67\x94\x99\xa0\xe4\x84\x85\x18\x09\x3e\x06\x02\x43\x5f\x10\x24\xe2\xd8\xed\x51\x71\xe9\xfa\x1e\x3a\xa0\x1f\x51\x01\x82\x03\xe7\x28\x91\xc0\x2c\x15\xd1\x08\xd3\xa4\x13\x40\xc8\xc1\x8d\x38\xf5\xc1\x0d\x23\x1a\x60\x9a\xcc\x7c\xe1\x83\x43\xbd\x38\x08\x51\x76\x70\xb9\x4b\x3d\x22\x3c\x93\xf9\xb1\x69\x7e\xc1\x21\x38\x6e\xfa\x4d\x0f\x27\x9b\x51\xde\xed\x17\x3e\xea\x0e\x3d\x23\xf0\x49\xe0\xc7\x02\xe5\xc7\x1f\x30\x2d\x37\xf0\x91\xa6\xf1\x6f\xdb\x4f\x36\xfd\xb6\x69\x86\xc0\xc9\x48\xe0\x83\xc1\x81\x68\x8e\x82\x6e\xe1\x37\xad\x9a\x08\x98\xe1\xac\x41\x03\x0d\x9a\x2e\x48\x58\x0f\xd6\xfd\xa6\xd9\xb0\xd3\xe0\x80\x5a\xa8\x2e\x40\x38\x26\x71\x66\xc5\xf9\xbd\xbf\x56\xde\xdb\xbd\x4a\xa2\x52\x99\x8a\xf5\x93\x2c\xc3\x62\x02\x26\xbb\xd5\x83\x70\x42\x19\x09\x30\xa5\x82\x6b\x4a\x04\x37\x62\xd0\xf4\xd8\x42\xc2\x8d\xd8\xc2\x21\xcc\x17\x31\x09\x43\xca\x18\x37\xae\xe5\x40\xe0\xd2\x20\x10\xa6\xe9\x0a\xa7\xb2\x8f\x50\x3f\x36\xff\xc8\xa6\x9b\x6c\x1e\x49\xf3\x88\x50\x2f\x6d\x96\x72\x62\xe3\xa5\x8e\x40\xfc\x5e\xc8\x91\xa2\xbd\x87\x61\xe5\x62\xee\xf7\x04\xb6\xa1\xee\xc3\x3b\x30\x5f\xd8\x7b\x88\x7a\x31\x30\xa6\x5d\xf7\x2d\x1c\x22\xb8\x73\xc9\x84\x4f\x19\x0f\x0d\x4f\xc6\xab\x3d\xea\x38\xa1\xf1\x6b\x97\xc5\x0e\x88\x90\x46\x11\x03\xe6\xb8\x06\xcc\xf3\x99\xa9\x99\x3c\x9f\x55\x9c\x87\x84\x85\x16\x1e\xdb\x9e\xcf\x62\x87\xd8\x09\xa4\x9e\x80\x9d\xa4\x1e\xec\xef\x69\x7f\x95\xbf\x3a\xa6\x7f\x23\x36\xa6\x28\x97\xa1\x56\x59\x48\x3d\x93\x39\xc3\x48\x10\x37\x64\x54\xf8\x02\xe3\xd4\x89\x44\x16\x71\x1a\x05\x26\x56\xa3\x40\x3c\x71\x7d\x41\x51\xf8\xe6\xee\x98\xff\x8c\x49\x03\x16\x37\x76\x6a\xb1\x80\x6c\x9d\x49\x58\x40\x9d\x80\x91\x30\xa0\x22\xcc\x5c\xc7\xa5\xbe\x27\x48\x7d\xbf\x0c\x71\xd9\x0e\xc1\x8b\x42\x2a\x18\x37\x95\x25\x8f\x3c\xea\x71\xdb\xb6\x4a\xf4\x58\xab\x05\x22\x63\x34\xb9\xe5\x52\xc4\xb4\x63\xe2\x72\x1a\x84\x68\x6c\x16\x52\xc1\x89\x47\x85\x2b\x08\x77\xa9\x13\x86\x24\xa2\x42\xf0\xcc\x65\x01\xe5\x8c\x93\xfa\x1e\x0b\xea\x70\xb3\x72\xa1\x75\x0c\x08\x2e\x7d\x9e\xc3\xeb\x07\x3b\xf9\xa5\xcb\x42\xcb\x13\xc1\xd5\xba\xc5\x73\x1a\x85\x83\x55\x38\x58\x85\x43\xad\x70\xb0\x0a\xbf\x62\x98\x74\x3d\x01\x01\x5a\xd9\x15\x97\x6e\x28\x68\xc0\x40\x30\x9f\x7a\xa8\x4f\xa4\xe2\x39\x66\x46\x4d\x71\x21\x04\x47\x07\x26\xcc\xe3\x34\x74\x45\x16\xb9\xd4\x63\x21\xa9\x6f\xdc\x78\x61\x73\x8b\x02\x2a\x3c\x06\xf6\x76\x29\x02\x8f\xf2\x00\x8b\x0b\x46\x03\x87\x83\x1f\xf9\x94\xfb\x36\x9c\x4c\x05\x22\x70\x2d\x3b\xee\xc6\x58\x41\x44\x66\xb5\x0a\x58\x08\x2e\x0f\x29\x77\x85\x29\x87\x8c\x51\x6a\x9e\x6a\x6e\xa0\xbe\x59\x6e\x9a\x9b\xe5\xa6\xe6\xed\xd2\xf3\x1d\xea\xb3\x10\x42\xcf\xa5\x0c\x8d\xce\x42\xea\xa3\xd1\x2d\x49\xbc\xc7\x8c\x0b\x1a\xf8\xcc\x6e\x35\xf6\x43\x05\x23\xd9\x30\x45\x2c\x53\xa4\x66\x8a\xd4\x4c\x35\x8a\xb2\x39\x00\x63\xeb\x58\x8a\x41\x83\xf9\x86\x69\x46\x43\x1e\xe2\x72\x1b\xa2\xb9\x68\x18\x02\xf7\x68\x18\xb0\xcc\x0f\xa9\x1f\x31\x62\x6f\x32\xf2\xd1\xa7\xa0\xbe\xd9\x7c\x16\x44\x34\x74\x42\x62\x6f\xbb\xf0\x4f\x5c\x3f\xa0\x68\x59\x7b\xdb\x84\x94\x89\xeb\xbd\xc0\xbe\x7a\xf6\xfd\xb3\x1f\x9e\xfe\x76\xf5\xe2\x87\x67\xbf\x3d\x7f\xf1\xfd\xf3\xd7\x30\x01\xc6\xdb\x81\x5e\xbc\x7e\xf6\xf2\xb7\xbf\xbd\xf8\xf7\x67\x4f\x61\x02\x61\x3b\xcc\xe5\xf3\x27\x3f\xaf\x61\x02\xea\x6f\xe8\xdd\xac\xf2\x58\xa7\x45\x0e\x32\x79\xb7\xaa\xb4\x4a\xbe\x2f\xd3\xe4\xbb\x42\xeb\x62\x31\xb0\xdb\xc4\xa7\x52\xcb\x33\xf8\x63\x67\xfd\xbc\x95\x25\xe8\x42\xcb\xec\x12\x37\xd2\x30\x81\x0d\x28\x2d\x55\xb2\x8a\xd5\x60\x60\xc6\x87\x90\x6a\xb5\x18\x82\x3c\x83\xc9\xc5\x1e\x12\xbc\x4a\xa5\x57\x65\x6e\x71\xc1\x63\x03\x4c\x33\x95\xcf\xf4\x7c\x07\xf4\xc3\x10\x9c\xb3\x03\x0e\x2c\xd1\x7f\x4b\x13\x3d\x87\x09\xfc\x79\xd0\xfb\xa6\x07\x8f\xcd\xde\x5e\x3f\x2d\x16\x2f\x92\x33\x7a\x87\x63\x83\x96\x99\x69\xae\x2e\x8b\x55\xae\x61\x02\x83\x2d\x41\xfe\x5f\x8b\xc2\x1e\x6f\x0b\x67\x79\xdb\xc0\x6d\x94\x7f\x06\xa3\x6d\x86\x1e\xb5\x48\xf9\x52\xea\x39\x8d\x55\x9a\x0d\xd6\xf4\xcf\x36\xa8\x36\xc6\x5e\xcf\xfd\xd0\x62\xa7\x99\xd2\xcf\x8c\x88\x3f\x2e\xf1\xf9\xb5\x5a\x2c\x33\xa9\xd5\x20\x4d\x86\x60\x8e\x2a\x86\x5b\xfc\x0e\x21\x93\x53\x95\x55\x43\xa8\x54\x99\xaa\x6a\x08\xf2\x3e\xad\xae\xb0\xef\xe7\x42\x4b\xad\xda\x0c\x6b\xa7\x7f\x5f\x16\xab\xe5\x4b\xb9\x84\x09\xfc\xf1\xa1\x03\xa6\x75\x78\x4b\x5b\x37\x45\xf9\x4c\xc6\xf3\xc1\x20\x97\x0b\xd5\xe1\x01\x88\x6d\x86\xb4\x7e\x90\x0b\x85\x2b\x58\xbf\x15\x64\x29\x4b\x8d\x8e\x86\x98\x68\xb5\xcc\x52\x3d\xe8\xff\xd6\x3f\x3b\x80\x4d\x6f\x60\x60\x60\x1b\x53\x5d\x80\xb3\x2f\x64\x73\x6d\x93\x35\x73\xae\x9d\xb7\x07\x80\x1f\x40\x65\x95\x3a\x01\x03\x72\x76\x38\xbb\x95\xc1\x3f\xed\xaa\x98\xce\x65\xf5\xe3\x5d\xfe\x53\x59\x2c\x55\xa9\x1f\x06\x6b\xac\x67\x5d\x9c\xef\xce\xbf\x5e\x4f\x78\x0b\x13\xf8\x71\xfa\x4e\xc5\x9a\xbe\x57\x0f\xd5\x60\x17\xee\xac\xd1\xc9\x5f\xb6\x4f\x80\xda\xe2\xad\x9d\xf5\xb5\xd1\xaf\xf3\x9a\xd6\x9a\xf0\x6e\xb4\x1e\x06\x5c\xae\xee\xae\xd6\x7e\xb1\x9b\x2f\x16\x72\x79\xd4\x41\xea\xe0\x69\x57\x04\xce\x1b\xb6\x8e\xa4\x71\x91\x8f\xb7\xe5\xbc\xde\x53\xda\x9e\x34\x6f\x5b\x4c\xff\x31\xa9\x16\x0f\xb6\xfe\x7a\x5d\x14\x19\x3a\xe7\x21\x8f\x0d\xc4\x93\x2c\x1b\x77\x88\x50\xcd\x8b\xbb\x31\xe8\x72\xd5\x21\x88\x09\xea\x31\xf4\x6d\xa9\x0d\x32\xcb\xfa\xc7\x24\xde\xa9\xc8\xdb\x01\x8b\x3c\xce\xd2\xf8\xfd\x78\x93\x54\x06\x5d\x8e\xb6\x91\x14\x13\x24\xa6\xcb\xfa\xcc\x91\xa6\x79\xaa\x07\x49\x11\xaf\x16\x2a\xd7\x14\xb3\x52\xa6\xb0\xf9\xdd\xc3\x8b\x64\x90\x26\x67\x87\xa1\xb9\x8d\xaf\x30\xc9\x0b\x26\x0d\x62\x44\x60\x13\xda\xe0\xf8\x44\x2b\x9e\x4a\x0e\xf3\xce\x8e\x84\x06\x15\xb5\x26\xbe\x76\xde\xd2\x64\x27\x1d\xe1\x32\xd3\xe1\x6d\xdb\x57\x43\xeb\xda\x2c\x4b\x8d\xcf\xa3\xa5\x3a\xa7\x7d\xe8\xe6\xfe\x80\xa7\x2d\x51\x9a\x66\xe7\xe4\x46\x4d\xd5\x5a\x4d\x16\x5d\x3b\xb9\x43\xc5\x7c\x38\xf4\x84\xc5\xc3\x2f\xf9\x57\x73\xce\x55\x7e\xa2\x7b\xee\x6d\x59\xfe\xcf\x41\xbf\xbe\x83\xde\xc8\xac\xfa\x17\xf4\xd0\x47\xdd\x4f\x37\x45\x09\x83\x75\xa1\x00\x69\xbe\xb7\x00\xb6\x39\xc7\x4e\x6e\xbe\xee\x37\x8f\x66\x4a\x1f\x1e\x5b\x54\x6f\x5b\x93\x36\x9c\xee\xf8\x06\xcb\x10\x46\xa3\x26\x3d\xaf\x51\x7f\xde\xa2\x64\xb9\x7a\x7b\x72\x58\x2c\x65\xb9\x90\x43\x50\x43\xb0\x2b\xe8\xf1\x20\xb1\xea\xab\x8b\xa8\x52\x2d\x33\x19\xab\xc1\x9e\x6a\x86\xd0\xef\x0f\xc1\x3d\xee\xe4\xff\x2b\xa3\x0d\x2b\xb7\x4d\xd1\xb0\x1d\x72\x75\x21\x74\x4c\xfd\xcd\xf5\x19\x6b\x0a\x7c\xac\x0a\xfd\x38\xfe\xe3\x29\x01\x5a\x03\x72\x3d\xf2\x2f\x98\x30\xd0\x35\xe2\x62\xb1\x28\xf2\x8f\x16\x68\x72\x96\xc6\xaf\x1f\x96\xaa\x6b\x05\xd4\x66\xec\xba\x8f\x5b\x36\x0c\x8e\xa9\x2c\xf1\x56\x69\x19\xbf\xc7\x86\x4e\x33\x95\xf4\x5b\x8a\xc7\xc3\x18\x46\xaf\xfb\xb5\x28\x16\x63\xf8\xa3\x65\xb4\x54\x95\x2e\x4a\xd5\x3e\x88\x53\xdf\xa4\xea\xae\x7d\xb4\x92\xb7\xea\x49\xf5\x62\x21\x67\x66\xfa\x11\xc5\xd4\x9b\x03\x59\x55\xe9\x2c\x1f\xec\xe4\xc8\xe1\xa1\xc6\xce\xbe\x6d\xdb\xc6\x1e\xea\xa9\x4e\x86\x1d\x0a\x54\xf7\x7a\x5c\x6f\x4d\x3b\x01\x5e\xe9\x87\x6e\x0c\x60\x96\x80\x5c\xbf\x4a\x7f\x57\x63\x70\xc5\xe7\xd7\x3c\xba\x28\x32\x9d\x2e\x3b\x59\x2d\xd3\xd9\x4c\x95\x63\xe8\xe3\x36\xb9\xa3\x8e\xc1\xa1\x9f\x8a\x34\xd7\x08\xd8\xcd\xb0\xf5\x9b\x7e\x5c\x16\x55\x17\x26\xbc\xcc\xfe\xfc\x18\x1e\xbc\xa6\x32\x7e\x8f\x19\x26\x4f\x2e\x8b\xac\x40\xfe\xbe\x11\x32\x88\x42\xff\x70\xaf\xdc\x5c\xed\xf1\xdc\xa2\x12\xf8\x0c\x0b\xb0\x4f\x41\xbe\x2c\xaa\x14\xe3\x7a\x67\xd9\x42\xfd\x1d\x4b\x97\xb5\xab\x19\xb8\x4f\x21\x76\x53\x94\x0b\xa9\x8d\x69\x76\x16\x49\xb9\xa8\x3e\xb6\x38\xc6\x45\xae\x95\x39\x11\xb2\xf0\x98\xcf\xd0\xd6\x6f\x64\xb6\x52\xf0\x18\xfa\xe7\xd3\x72\x74\xd1\xad\x71\x3b\x6b\xbd\xaa\x60\xfe\xfd\xf8\x9a\x82\x84\xd3\xfa\xe8\xfe\xbc\xba\x9d\xc1\x6d\xaa\xee\xbe\x2b\xee\x27\x3d\x73\xa0\xe8\x30\x6e\x7e\x7a\x70\xab\xca\x2a\x2d\xf2\x49\xcf\xa5\x6e\x0f\xee\x17\x59\x5e\xd9\x2f\x45\xc6\xa3\xd1\xdd\xdd\x1d\xbd\xf3\x68\x51\xce\x46\xcc\x71\x9c\x51\x75\x3b\xeb\x81\x39\xfa\x9a\xf4\x5c\xd6\x83\xb9\x4a\x67\x73\x6d\xda\x17\xe7\x4b\xa9\xe7\x50\xe9\xb2\x78\xaf\x26\xbd\x7e\x73\xe6\x16\xa3\x5f\xa1\x90\x3d\xb8\x49\xb3\xac\x7d\x24\xb1\xdd\x27\xed\xa9\xcd\x5c\x7b\xe8\xf4\x83\xdd\x5e\x6f\x0a\x8e\xfa\x1d\x85\x29\x35\xce\x0c\xea\xd1\xc5\xf9\x68\x76\x71\x8e\x9c\x1f\xd1\x30\xd8\x63\x4d\x63\xa5\xc7\x13\xab\xb7\xc7\xd0\x87\x35\xaf\x1b\x7a\xd8\x3f\xde\x0c\xdc\x9e\x68\xc3\x0f\x7b\x19\x6f\xfb\xaa\x5d\xb2\x66\xa0\x1d\xee\xa4\x24\x64\x95\xd4\x15\x6a\x53\x73\xf6\x3a\x06\xa7\xdd\xc1\x71\x21\x18\xef\x1e\xa7\xb4\x03\x36\xeb\xef\xcb\x22\xc1\x3c\xb4\x58\x61\xe2\xcb\xd4\xa1\xec\x1d\x69\x72\x5a\xdc\x77\xb1\x78\xa3\xa4\x5e\xe1\x4a\xb5\xb3\x7e\x9c\x82\x78\x56\xa6\x9d\x82\x67\xea\x46\x8f\xa1\xef\xfd\xa5\x23\x5b\x96\xe8\xc4\x63\xe8\xf3\x2e\x80\x46\x73\xc7\x4f\xb2\xdb\xe7\xa2\x55\x65\x9a\x5f\xd9\x6c\xdc\x5d\xf6\x4f\x8b\x32\x51\x65\x93\x85\x4b\x95\x9c\xa4\xcf\xfb\x27\xf7\x69\x35\xde\xfb\xd0\xaa\xb9\x3e\xbe\x84\x48\xad\x66\x45\xf9\x70\x64\x15\x99\xe2\xda\x20\xcb\x87\xef\xe5\x72\x6c\x8b\xbd\x6e\x58\xeb\x41\xf5\xb9\x70\x27\x94\x39\x62\xbd\x4a\xf3\xa3\x4b\x02\x9c\xb0\x51\x6a\x2e\xac\x9f\x3e\xba\xc6\x34\x57\x6c\x15\xdc\x2b\x67\x53\x39\x60\xdc\x1d\x02\xf3\xc2\xfa\xc7\x3d\xeb\x7d\x6e\x25\xdb\xcd\xe1\xfa\x58\xfc\x63\xdc\x95\xe6\xd8\x7c\xbc\x7f\x8e\xfe\xa9\xeb\xf0\x41\x6f\xcb\x96\xef\xe1\x8b\xbc\xc6\xa4\xbc\x23\x2e\xf3\x3f\xd2\xc0\x9f\xaf\x6c\xbb\x74\x74\x54\xce\x9b\xd6\xf6\x6b\x18\xb3\x7d\xf9\x5b\x59\x2c\x2e\x5f\xbd\xd9\x7e\xfd\x92\x74\xbc\x30\xb3\x21\x07\x13\xb8\x7e\x7b\x30\x66\xc9\xb7\x8f\x65\xdb\x07\xe7\x2d\xe3\x5a\x4e\x33\x05\x13\xe8\xf5\x0e\x86\x4a\x95\x3d\x29\x4b\x98\x18\x9e\xea\xd7\x26\xbd\xff\xcc\x7b\xbb\x7b\x2c\xf3\x4a\xe2\xcf\x34\xad\x9e\x2d\x96\xfa\xc1\x6e\x11\x06\x76\xea\x19\xfc\xf5\xaf\x35\x96\xcd\x0b\x15\xb7\xad\x94\x5a\x9f\xd1\xa4\x30\x01\xe7\x5b\x48\xe1\x7c\x77\xe2\xb7\x90\x3e\x7e\xdc\x55\x84\xe1\x44\xe3\xb1\xa8\x03\x3b\xed\x3a\x7d\xdb\xbe\xc8\x5a\x79\x1f\x4f\xa0\x77\xae\xcb\x8b\x76\x5f\x69\x15\xc9\x12\xa0\xba\x4c\x17\x83\xb3\xce\x77\x2b\x0d\x3b\xc5\xf4\x9d\xd5\xdd\xce\xb4\x46\x89\xc3\xde\x91\x52\x61\xad\x8b\x77\x56\x17\xef\xe0\xbc\x46\xb7\xd6\xc5\xbb\x6e\x5d\x6c\xcb\x90\xc2\x64\xd2\xfd\x02\x6b\x1f\xfa\xdd\xc9\xd0\xb0\xf3\xa2\x0e\x26\x35\x7b\x1f\x9d\x77\xf2\x39\x04\xac\x63\xea\xfa\x1d\x10\x70\xbb\x4f\xda\xda\xae\x5c\x2e\xd4\xb8\xe6\xe9\xfa\x5d\xc7\x71\x58\xdb\x65\x17\xb7\xeb\x4f\x98\x51\xa7\x4b\xbb\xbd\x3f\x79\x56\xf5\xb0\x98\x16\x99\xdd\x0e\xb5\xef\x86\xf6\xaf\xee\xac\x75\x1a\xc4\xc9\x9a\xff\x0c\x47\x30\x89\xe9\x3a\x6d\xec\xb4\xd6\xfb\x3f\xd0\x1d\xcc\x11\xdc\x67\x91\xfc\xcc\xb5\x01\x3e\x59\x35\x9f\x16\x7f\xb0\x97\x99\xe6\x17\x69\x9e\xa8\xfb\xf3\x91\x9e\x77\xe4\xa8\x1d\xbe\x3f\x45\x91\xbb\x64\x7a\xb8\xc9\x81\xc7\xd0\x3b\x95\xd4\x17\x2a\xf0\x74\x95\x1c\xf2\xb9\x36\xf3\x89\xfc\x9e\xac\x96\x6d\x52\x49\x2b\xa9\xe4\x63\xa4\xbe\xb8\xaa\xd8\xe3\x63\xd4\xba\x38\x1d\x3b\x9c\xdb\x7a\x29\x5e\xcd\xd3\x1b\xdd\xf2\xe1\x4a\x5c\xe4\x37\xe9\x0c\x26\x5f\xfa\x19\x08\xf7\xcf\x4e\x3b\xda\xb3\x04\x5b\xb6\x88\x28\xe8\xe9\xe5\xd2\xbb\xea\x67\x25\x93\xbf\xa5\x99\xaa\x06\x37\xf8\xbb\xef\x3f\xe8\x59\x66\xa0\x5e\x21\xdb\xfc\x0b\x15\x80\x30\x30\x31\xb7\xea\xda\x69\xa9\x10\x6c\xd9\x23\x13\x85\x4b\x77\xae\xee\x00\x89\xfe\x6c\x3a\x06\x2d\x4b\x36\xd2\x1d\x69\x75\xaf\x1f\x8f\xa8\x56\x95\x36\x4c\x50\x5c\x14\x3a\x0b\x04\x8b\x9d\x16\x79\x56\xc8\x04\x79\x39\xe9\x25\xe5\x96\xb1\xac\xf9\x76\xca\xc7\xcd\x47\x4b\x43\x23\x9b\x39\xb9\x1f\x82\x9e\xa7\x15\x2d\x55\xb5\xca\x74\xf7\x29\xbc\x99\x4b\xe3\x4c\xc9\xf2\xc8\xdb\x92\x78\xef\xb0\x7d\x8b\x1f\x6a\x8d\xdc\x3d\xf7\xcf\x83\xde\x37\x4b\x55\xde\x90\x44\x69\x99\x66\xc4\x18\xbf\x77\x46\xe7\x7a\x91\x0d\x7a\xe7\x7a\x5a\x24\x0f\x17\xeb\x6f\xaf\x6a\xa4\x75\x28\x98\x48\xb0\x00\xa7\x9e\xec\x6f\x29\x19\x6f\x4f\xaa\xd7\xea\xde\x1a\xa6\xc5\x80\x47\x53\x84\xcc\x54\xa9\x07\xfd\x5f\xf2\x6a\xb5\x5c\x16\xa5\x56\x89\xd1\x6e\xcb\x77\x43\x5d\x71\x69\x5b\xfb\x7f\x55\x85\xdb\x1c\x53\x39\x4c\x7a\xe8\x3b\xa3\xb8\xaa\x7a\x9b\x3f\xb2\x42\x69\xf7\x18\xba\x29\x72\x4d\x2a\x7b\x7a\xca\x97\xf7\xdf\x1e\x0e\xde\xc8\x45\x9a\x3d\x8c\xa1\xff\x4a\xcd\x0a\x05\xbf\xbc\xe8\x0f\xe1\xb5\x9c\x17\x0b\x39\x84\xef\x55\xae\x6e\xe5\x10\xde\xa8\x32\x91\xb9\x1c\x42\x25\xf3\x8a\x60\x2c\xdf\xec\x62\x5a\xca\x24\x49\xf3\xd9\x18\x3c\x67\x9b\xc8\xd6\x77\x64\xd6\x2a\xbb\xcc\x2d\x64\x39\x4b\x73\xa2\x8b\xe5\x18\x98\xb3\xcf\x9d\x3d\xf2\x20\x71\x91\x65\x72\x59\xa9\x31\x34\xad\x23\xf8\xf5\x7c\xb8\xdf\x93\xec\x11\xb5\x68\xc7\xe0\x2e\xef\xa1\x2a\xb2\x34\x81\x6f\x12\xa5\x98\x12\xbb\xd4\x51\xbd\x44\x66\xe9\x2c\x1f\x43\xac\x72\xad\xca\x0e\x89\x29\xf3\x4b\xb5\x68\xe5\x89\x1a\xdf\x35\x59\xf1\x88\x59\x44\xab\x59\xee\x94\x3d\x76\x9a\x16\x59\xb2\x3b\x9c\xa4\xd5\x32\x93\x0f\x63\x48\x73\x2c\x1e\xc9\x34\x2b\xe2\xf7\xad\xf4\x77\x62\x27\x96\xf9\xad\xac\xf6\xf8\x98\xd7\x54\x7c\xa7\xcb\x6e\x87\xf1\x77\x44\x14\xd6\x81\xa4\x56\x04\xce\x26\x89\xb2\x2e\x8d\xf9\x68\xdb\x5c\x9d\x30\x5d\x06\x74\xba\x0d\x96\xa9\x1b\xdd\x65\xae\x7d\x6b\x81\x0d\x33\x8c\xac\x8b\x47\xe7\x23\xfb\x17\x9b\x8f\xce\x4d\x2c\xc5\x99\xac\xaa\x49\x6f\x5b\x05\x4b\x39\x53\xcd\xdf\x6d\x26\xe9\xed\x0e\x08\x86\xf8\x56\x38\x1e\x8c\x1b\x4f\xe8\x5d\x98\x9d\xcf\xab\x62\x55\xc6\x6a\x7c\x3e\x4a\xd2\xdb\xad\x29\x69\xbe\x5c\xe9\x3a\xc2\x0d\x3a\x28\xf2\x78\x2e\xf3\x99\x9a\xf4\xb6\xd7\x32\x93\xa0\xed\x82\xd6\x83\x51\xcd\xcf\x06\xd7\x01\xe9\xfa\x9c\x51\x95\xfb\xfc\xa5\xc9\xae\x7c\xd6\x4d\x7a\x17\xfb\x8c\x59\xd3\xef\x43\xdb\x84\x7c\x71\x3e\x32\x8d\x43\x36\xa6\xe5\x9a\xb9\xf9\x56\xd3\xbb\xf8\x4e\xe5\xf1\x7c\x21\xcb\xf7\xf0\xd4\x60\xaa\xce\x47\x73\xaf\xf9\x73\x58\x43\x6a\x47\x71\xfb\x5e\xb1\x2d\x85\x4d\xf2\x3b\xd6\xc6\xfd\xf9\x41\xa6\xc5\x5a\xf0\xa7\xb2\xc0\x1d\x39\xfc\x20\x17\xca\x94\x81\x2d\x50\xc9\xc5\xdf\x73\xa9\xd3\x5b\x65\x8a\xb7\x5d\xbc\xa3\x7d\xc4\x9d\x94\x36\x02\x9e\x44\x0b\x0b\xa4\xdb\x34\x56\x10\x97\x4a\xa2\x84\x5f\x44\xfc\xe9\x46\x53\xdd\x94\x5f\x2a\x59\xad\x4a\x05\xef\x3b\x38\x00\xac\xeb\xf2\xf8\xe1\x24\x4e\xfe\x44\xc8\x29\xba\xf8\xbb\x7a\xe8\x66\xa8\x83\x10\x10\xb2\x65\xed\xd1\x96\xb9\x77\xdd\xae\xd3\xd7\x4c\xc1\x93\xe6\x33\x78\x9d\x2e\xff\x49\x7e\x66\x5f\x4f\xc0\x56\xa4\x77\x8b\x7d\x39\x2f\x8a\x4a\x81\xcc\x0b\x3d\x57\x25\x5c\xbe\x7a\x63\x8b\xcd\x9b\xb2\x58\x40\x56\xc4\x32\x03\x5d\xc0\x54\x41\xa9\xf2\x44\x95\x2a\x81\x14\x33\xa7\x02\x4c\x45\xf4\x8b\xfc\xc4\xbc\x62\xec\xe6\xec\x79\x71\xab\x4a\x28\x2c\x35\x53\x62\x81\x2c\x95\x44\x76\x2a\xa5\xc0\xe6\x00\x95\xc0\x42\xe9\x32\x8d\x9b\x73\xb4\x15\x72\x69\xa6\x54\x72\xa1\xe0\xde\x1c\x59\x7f\x19\x9f\xf6\x9d\xd3\x11\x15\x66\x69\xfc\xbe\x61\xd4\x6e\x3f\x0c\x93\xf3\xe2\x6e\x34\x4f\x13\x65\xfa\x6b\x2e\x71\xcd\x1c\x4d\x65\xd9\x68\xd1\xd6\xa7\x5f\xc4\xde\xeb\xe6\xb5\x55\x6d\xf6\x27\x59\x76\x02\xaf\xdb\x1f\xd6\xda\x77\x8a\xc5\x8d\xe1\xa8\x7e\x0b\xd6\x48\x80\x00\xcd\xd0\x96\x0c\x15\x0a\x51\xfd\x23\xa4\xf8\xa5\xf9\xa6\xf2\x44\x39\x76\xbe\xc1\xec\x92\xc4\x98\xe1\x9f\x2d\x49\x6d\x0f\xf3\x9a\xf8\x04\x49\x64\xfe\x00\xfd\x99\xfd\x50\xef\x98\x41\xda\xf9\xdf\xf8\xbd\x96\xe5\x4c\xe9\xcd\x47\x83\x7a\xde\xfe\x9e\xa6\x0e\xa9\x3c\xb1\xda\x31\xf1\xff\x85\x91\xb2\x11\xfd\x2e\xd5\xf1\x1c\xec\xe7\x72\xaf\x1f\x96\x47\xc2\xbc\x06\xb5\xdc\x60\xe9\x51\xc1\x54\xe9\x3b\xa5\x72\x23\x1e\x9c\x93\x0b\x98\xca\x72\x08\x95\x96\xef\x55\x82\x6d\xd3\xb7\xca\xcd\xc7\x48\xb6\x07\x77\xff\x98\x0b\xd6\x33\x56\xf9\xa6\xa3\x73\x83\xd7\x5c\xa8\x03\xa5\x63\x7a\xc8\xdf\x57\x51\x86\xc9\xc3\xbf\x16\xc5\xa2\x5b\x09\xcf\x72\xbb\x5d\x28\xa0\x71\x66\xa8\x96\x2a\x4e\x6f\xd2\x78\x9d\xf4\xa6\x0a\x7e\x2f\x8a\x85\xc9\xc0\xa3\x62\xf5\xb5\xbc\xf4\x67\xfb\x01\x56\x37\x6f\x35\x00\xb2\x90\xe6\xa9\x4e\x65\x56\x1b\xcb\xee\x9f\x57\xa5\x5d\xaf\xe5\x8d\x56\x25\x54\xc6\x9a\xb8\xdc\x6d\x0c\x6a\xdf\x0d\x19\xe6\x71\xa0\xd1\xf6\x57\x54\xee\x9b\x54\xdd\x75\x0b\xf0\xd4\x6e\x59\xa0\x94\x77\x96\x93\x34\x87\x78\x55\x96\x2a\xd7\x5b\x41\xb0\x5a\x26\x52\xaf\x17\x1a\x23\xcc\x54\x21\xbf\x2a\x49\xb5\x4a\xbe\x56\x60\xc8\x5b\x05\xb2\x02\xf3\xd5\xda\x91\xa0\x40\xb0\x5d\x1e\x6f\x53\x75\x87\x33\x65\x0e\xe9\xe2\x94\xb5\xf7\x48\xb1\xb2\xff\xff\x47\x31\x67\x6c\xd5\xed\xcf\xe6\xc8\x05\x26\xd0\xfb\xe3\x0f\x8a\x8a\xfd\xf0\xa1\xb7\x0b\xb3\x3e\xaf\x41\xa0\x96\xc2\xfd\x10\xfa\xa4\xb3\x9f\x5e\x6f\xb8\xa1\x7f\x76\x88\xe4\xd4\x2f\x6e\xb7\xfe\x08\x6e\x08\xbd\x0c\x77\x97\x5b\xc7\x2f\x9f\x72\x16\xf4\x55\xcf\x7e\x36\xe7\x28\xe7\x23\x6b\x12\xdc\xea\xe9\x45\x76\xf1\xdf\x01\x00\x00\xff\xff\x84\x2d\x5e\x81\xbb\x47\x00\x00")
+var _templatesGrafana_dashboardJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x56\xef\x6b\x23\x37\x10\xfd\xee\xbf\x62\x3a\x04\xce\x2e\x7b\xad\xed\x9c\x7d\xb1\xe1\x3e\x94\x96\x42\x69\xa0\xa5\x81\x7e\x49\xc2\xa2\xec\x8e\xbd\x22\x5a\x69\x4f\x9a\x75\xe2\x9a\xfd\xdf\x8b\xb4\x3f\x6c\x37\x4e\x0e\x3b\xa4\x81\x85\xb5\x34\xd2\xcc\xd3\x7b\x6f\xe4\xdd\xf4\x00\x90\x25\x2b\xc2\x39\xe0\xef\x5a\xb0\x5c\x11\x5c\x91\x5d\xc9\x84\xe0\x2f\x12\xe9\x1a\x2e\x05\x93\x4e\xd6\x18\xf9\xb5\xa5\x4c\xfd\xca\xfb\x82\xec\xe2\x23\x3d\x16\xc6\x32\xd9\x3a\x94\x92\x4b\xac\x2c\x58\x1a\xed\x97\xb4\x49\x6c\x48\xa2\xea\x24\x70\x75\xf9\x9b\x83\x66\x5f\x0a\x77\x6b\xf8\x10\x52\xb5\x53\x16\xa4\x76\x2c\x94\xfa\x50\xe7\x64\xb1\x74\x38\x87\x6b\xbc\xaf\xa1\x61\xd4\xd4\xc6\xdb\x3a\x2e\x73\xfa\xc7\xe8\x80\xfe\xce\x9a\x07\xd7\x82\x71\x49\x46\xb9\xf8\x9b\xac\xab\xe1\x8c\x3f\x87\xf5\xab\x6e\x62\x14\xc6\x96\x16\x96\x5c\xe6\xb7\x8f\xf2\xa6\xa4\xcc\x7d\xba\x0d\x2e\xac\xc9\x7d\x40\x9b\x87\x8f\xd3\xcc\x57\x66\xd3\x8c\xb1\xaa\x97\x52\x5e\x28\xc1\x52\x2f\xfd\x86\x1e\x00\x00\x2a\xe9\xd8\x23\x0e\x23\x68\x66\xfd\x83\x5a\x84\xc4\x98\x0a\x16\xce\x94\x36\x21\x8c\xb6\x51\x25\xee\x48\xf9\xf0\x2f\x82\x05\x3c\x8d\xf3\xba\x78\x61\xf7\xd7\x92\xec\xda\x87\x0b\x6b\x72\xe2\x8c\x4a\x87\x4d\xb4\x8a\x9e\x87\xc2\x99\x3f\xbd\x51\xe9\x41\x24\x57\x97\x7f\x40\xb7\x02\xfa\x8e\x12\xa3\x53\x37\x38\x84\x2a\x29\x1d\x9b\xfc\x20\xa2\x49\x34\x1a\x46\xe3\x61\x74\x3e\x8c\xa6\xc3\xdd\x15\x49\x69\x2d\x69\x4f\xd6\x06\x99\x1e\xfd\x0f\x3c\x1f\x7a\x9e\x57\x42\x95\xd4\x0c\xab\x66\x43\xfd\xbe\xed\x35\x07\xc2\x42\x68\x52\xae\x63\xba\x3d\x1c\x06\x7f\x8e\xda\x32\x5b\x6f\x37\x76\x74\x8d\x1f\x1f\x24\x67\x52\x03\x67\x04\x7b\xc7\xec\x00\x76\x47\x73\x2c\x78\x3b\xbb\x43\xff\x1c\xf0\xec\x80\x1a\xb8\xb4\x32\xfd\xd3\x78\x68\x1b\x7c\xc4\x39\x0c\x23\xc0\x75\xf3\x7e\xc0\x39\x4c\x23\x40\xef\xb8\x8b\x4e\x19\x5c\x48\x52\xe9\xcf\x46\x2f\x64\x70\x12\xa6\xb4\x10\xa5\xe2\x3a\x47\xa9\x65\xe0\xa6\x20\x9b\x90\xe6\x30\x8c\x7c\xbb\x25\x32\x17\x81\x82\x71\xb5\x4d\xc5\xc2\x2e\x89\xb7\xc4\xec\x2b\x0f\x80\xf4\x58\x58\x9f\xce\x95\x79\x5f\xea\xc4\x92\x70\xd4\x0f\x3d\x15\xdf\xbb\x55\x12\x17\x99\x70\x14\xa7\xa5\x15\xbe\x93\xe3\x46\xf5\xf8\xae\x4c\xee\x89\x37\x21\xfa\xe5\x06\xcd\x8a\xac\x50\x2a\x0e\x6c\xde\x60\xa4\xe8\xcb\x0d\x9e\x75\x34\xde\x60\x75\x7d\x16\xc7\x56\xe8\x25\xdd\x0e\x06\xf0\x23\x1c\x59\x2e\x31\xa5\x7e\xb6\xda\x5e\xee\x8e\x78\xff\xa0\xa2\x25\xe9\xf4\x57\x63\x73\x11\x48\xdb\x53\xfb\x6c\xd3\x01\xac\xba\xfe\x68\xad\x55\x9b\xab\xeb\x97\x7d\x43\x8d\x5f\x30\x54\x4e\xc2\x95\x96\x52\x28\xc8\x42\x2e\x75\xc9\xf4\xd4\x45\x2c\x73\x72\x64\x25\xb9\x57\x79\x69\xfa\x1f\x2f\x8d\x2e\x4e\x34\x93\xcb\x8c\x65\x3c\xd1\x37\xfe\xde\xee\xfb\x3b\xc4\x15\x22\xa1\x01\xf4\xad\xe0\x3d\x51\x5b\x4a\x62\x36\x2c\xd4\xf5\x24\xf7\x1e\xf8\x1e\xa6\xc3\x97\xc5\xda\x6c\xba\xa4\x55\x75\x94\x3e\xe7\xcf\xea\xb3\xff\xff\xf3\x66\xc2\xb4\x4d\x7e\xd1\x34\xf9\xf8\x53\x23\xcc\xec\x58\x61\x4e\x12\x25\x93\x8e\xcd\xd2\x8a\x3c\xfe\x5a\x0a\xcd\x52\x51\x7f\xf8\xc3\x24\x82\x56\x2c\x75\x48\xa5\x93\x3a\xbd\xaa\xd5\x1c\xbc\xac\x64\x31\x19\xee\xe8\x17\x9d\x00\x7e\xf6\x9e\xe8\x67\x93\xd7\xa2\x9f\xbd\x27\xfa\xd9\x0e\xfa\x6f\xf7\xce\xa7\xa7\xbd\x53\xcc\x26\x6d\xc7\x84\x5b\x2d\xb0\xf9\xe6\xbd\x33\xfa\xdc\xde\x6a\xe3\xf7\x6f\x9e\x7d\xff\x45\x35\x05\xa7\x09\xf9\xdd\xa9\x4a\x6e\xea\xfd\x47\xde\x85\x93\xa7\x7a\xfe\xe4\xab\x2f\xe9\x7f\xd4\x74\x34\x7e\x67\x51\x5b\xed\x8e\xd4\xcd\x95\xf9\xb7\x44\xab\x3f\x66\x4e\x49\xbe\xf3\x51\xf3\x7c\xfa\xd7\x5a\xa2\x07\x70\xdb\xab\x7a\xff\x0e\x00\xeb\x62\xf0\x9b\xda\x0d\x00\x00")
+
+func templatesGrafana_dashboardJsonBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesGrafana_dashboardJson,
+		"templates/grafana_dashboard.json",
+	)
+}
+
+func templatesGrafana_dashboardJson() (*asset, error) {
+	bytes, err := templatesGrafana_dashboardJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/grafana_dashboard.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb5, 0x23, 0xf0, 0xed, 0xcb, 0xa2, 0x36, 0xc, 0xe5, 0x4a, 0xdd, 0x6, 0x19, 0x65, 0x46, 0x7, 0x3f, 0x15, 0x61, 0x2b, 0x7b, 0x76, 0xf4, 0xcc, 0x4f, 0x12, 0xca, 0xa2, 0x7, 0x5a, 0xe7, 0xad}}
+	return a, nil
+}
+
+var _templatesHeatmapHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x57\x5f\x8f\xdb\xb8\x11\x7f\xf7\xa7\x98\x2a\x0f\x67\x63\x65\x49\xde\xa4\x69\xa0\x58\x3e\xb4\xb9\x6b\x1b\xa0\xc1\x05\x77\xd7\x03\x5a\xd7\x0f\x63\x69\x64\x71\x8f\x22\x05\x92\xd2\xda\x31\xfc\xdd\x0b\xea\x9f\x65\x59\xdb\x04\x28\x2c\xc0\x12\x39\xf3\x9b\x1f\x87\x33\xc3\xe1\xfa\x0f\x3f\xfc\xf4\xe1\xd7\x7f\x7d\xfe\x11\x32\x93\xf3\xcd\x6c\xdd\xfc\xcd\xd6\x19\x61\xb2\x99\x01\x00\xac\x73\x32\x08\x71\x86\x4a\x93\x89\x9c\xd2\xa4\xcb\x77\x4e\x3b\x65\x98\xe1\xb4\xf9\x4c\x2a\x85\x8c\xd0\xe4\x58\xac\xfd\x66\xac\x99\xd7\xb1\x62\x85\x01\xad\xe2\xc8\xc9\x8c\x29\x74\xe8\xfb\x71\x22\xbc\x27\x9d\x10\x67\x95\xf2\x04\x19\x5f\x14\xb9\x4f\x16\xdf\x68\x3f\x61\xda\x74\x1f\x4b\x12\x5e\xce\xac\xb0\xb3\x59\xfb\x0d\xd4\x0d\x6e\xf3\x61\x7f\xbe\x0f\x85\xe5\xf7\xf7\x86\x04\x28\xc2\x44\x83\xc9\x08\x72\x42\x5d\x2a\xca\x49\x18\xf8\xf0\xcb\x6f\x6e\x3d\x98\x32\xa5\x0d\x98\x67\x09\xb1\xe4\x65\x2e\x34\xa0\xa2\x7a\x46\x93\xaa\x58\x4c\x20\x30\x27\x40\x91\xd4\x2f\xba\xc0\xb8\xf9\x32\x19\x0d\x6d\x2a\xca\x91\x09\x26\x0e\x3d\x8e\xc5\x48\x4a\x85\x86\x49\xa1\x41\xa6\x35\x68\x91\xa1\x26\x0d\x4c\x80\xa6\x58\x8a\x44\xf7\x18\x69\x29\x62\x2b\x7a\xc3\x7e\x1e\xeb\x6a\x01\xe7\x5e\xc8\x3e\x15\x2a\xe0\x4c\x90\x86\x08\x62\x5d\x79\x46\xb1\x7c\xbe\xf0\x74\xc1\x99\x99\x3b\xff\x11\xce\xe2\x4e\xdc\xee\x20\x29\x88\x1a\xbd\x6d\xb0\xeb\xa4\xdd\x69\xe1\xda\x71\x11\x9c\x1b\xb6\xa1\x1d\x4a\x48\x79\x9a\xb3\x98\xe6\x8f\x0b\xb7\xf3\x8d\x0e\x61\xbb\x73\xa1\x42\x5e\x76\xef\x39\x1e\xed\xcb\xe5\x06\x36\x95\x0a\xe6\x96\xf7\x13\x44\x10\xbc\x87\x27\x58\x77\x66\xbc\xc6\x86\xc7\x49\x1c\x4c\xf6\x1e\x9e\x1e\x1e\xc6\x0b\xb6\xbf\x4e\x3a\xc7\xa3\x57\x94\x3a\x9b\x07\xb7\xc4\x5f\xb0\xc7\x20\x82\xd5\x7b\x60\xb0\x6e\x96\xde\x9b\x61\xd3\x66\x2c\x47\x25\x9f\x7b\x4f\xb1\x17\x3d\x35\x24\xd5\x79\xa3\x61\xa6\xe4\xf3\x76\xb5\x83\x07\x70\x7c\x07\x1e\xc0\x7e\x06\xbb\x7b\xe5\x1b\x9f\x3c\x36\x3e\x51\xf2\xf9\x6b\x8e\xe8\x58\xd6\x3e\x87\xa8\x89\x96\xbf\x72\x89\x66\x6e\x2d\x3d\x4d\x58\x1a\x52\xad\xd5\x5a\xa2\xdb\x27\x58\xc2\xa3\x0b\x0c\x96\xb0\x6a\x77\xf1\x2b\xea\x39\x1e\x1b\xad\x1d\x44\xf0\x09\x4d\xe6\xe5\x78\x9c\xdf\xcf\xb6\x68\xf7\x60\x97\xd9\xcb\x5f\x8a\x4c\xa9\x44\x67\xab\x9f\xba\xcc\xfa\x57\xdf\x87\x03\x99\x36\x37\x7e\x2a\xea\x74\x89\x25\x97\xaa\x49\xf0\x98\x38\xd7\xb0\x3f\xf5\x69\xe7\x82\x90\x2a\x47\xce\xbe\x50\x62\xc7\xad\x50\x8e\x47\x96\x97\x79\x2f\x63\x33\x93\x30\xce\xa0\x0e\x44\x60\xe9\xd0\x5a\x41\xea\x73\x33\xac\x41\x93\x71\x41\x4b\x30\x19\x1a\xd0\x99\x54\xa6\xcb\x66\x5b\x31\x84\x34\xf0\x8c\x3a\xa3\x04\x64\x69\x3a\x63\xb2\x22\x85\x9c\xd7\x45\xe8\x4a\xeb\x3e\xe7\xc7\xab\xea\x5c\xea\xf6\x0c\xc6\xb1\x60\x63\x20\x41\x83\x10\x8d\xf7\xd6\x96\x8d\x1e\x78\x7e\x57\x3e\x3a\xe5\x1c\x8f\x03\x5d\xbb\x75\xd5\x36\xd8\xed\xee\x64\xdb\x5d\xa9\x67\x5d\xa8\xb6\xab\xdd\x95\x14\x7c\x0f\x73\x8b\xb3\x81\x00\xbe\x87\x6a\xfb\xb8\x03\x1f\xec\x40\x08\xc1\x02\xc2\x7a\xc4\xea\x3c\x8e\x70\x2f\xb7\x81\x61\xe9\xb4\x9e\xfa\x84\xc7\x41\x64\x79\x58\x14\xfc\x34\x17\x25\xe7\xee\x90\xaa\x17\x4b\x11\xa3\x99\x6f\x83\xdd\x62\x31\x9b\x60\x7b\xbf\x64\x23\x25\x37\xac\x08\x27\xbc\xd1\x66\x63\x8e\xc6\x90\x0a\xaf\x9b\x32\x2f\x50\x61\xae\xa7\x1c\x38\x32\x37\x2e\x04\xdb\x46\xb3\xd9\x91\xed\x6a\x57\x57\x83\xf5\x5e\xf9\x1b\x5b\x11\x3a\xe9\x26\x7a\x6e\x65\x83\x46\x36\x04\x07\x1e\x5e\xb4\x6a\x9f\x1b\xad\xd7\xb5\x92\x76\x26\x35\x6e\x93\xcc\xfe\x2e\xee\xdd\xd0\x41\xb1\x24\x84\x33\xa7\xd4\x84\xb0\x7a\x17\xb8\xa0\xd8\x21\x33\x21\xbc\x09\x5c\x30\xb2\x08\xe1\x31\x70\x61\x2f\x8d\x91\x79\x08\xab\x37\xc1\x04\xc6\xf1\xcf\x47\xa6\x43\x38\x9b\x53\x41\x21\x38\x31\x1a\x3a\x48\x75\x72\xdc\x3a\x50\xc3\xd1\xba\x5d\xc0\x23\xd3\xff\xc0\x3d\xf1\x10\xce\x4a\x1a\x34\x14\xc2\x9b\x3f\x5e\x26\x90\x4f\xdf\x88\xdc\xf9\xdf\x05\x26\x2a\x52\x9a\x42\x30\xaa\xa4\x09\x44\xcb\xe8\xdf\xd2\xae\x65\xdb\xa1\x6a\xce\x12\x52\x8e\xdb\x58\xfb\x28\x12\x3a\x86\x70\x75\x44\x70\x71\x7b\x02\x4c\x68\x96\xd0\x58\xf4\xb2\xbb\xb7\x53\x31\x5d\x22\xff\x84\x2f\x06\x5e\xce\x84\xb5\x32\x3d\x67\xcf\xd2\x41\xae\xad\x20\x1c\xe4\xc9\xb4\x4e\xc2\x72\x12\x9a\x49\x11\xc2\xe3\xb4\x44\x8c\x3c\x2e\x39\xee\x79\xeb\x9d\x69\x29\xa9\x18\x09\x13\x82\x93\x49\xc5\xbe\x48\x61\x90\x3b\xd3\x92\x4d\xcc\x38\x31\x09\x43\xea\x05\x99\x2e\x70\x5e\x58\xa8\xa1\xa3\xb9\x59\xe9\xd6\xd1\x5c\x3e\x93\x36\xb6\x3a\xd7\xf1\xe2\xb8\xe0\x04\xda\xd9\x41\x08\xdb\x41\xad\xa8\xc3\xbe\x9d\x9a\xc6\x66\xe2\x67\x14\x07\x0a\xe1\x5c\x9f\x14\x21\x6c\x9d\x57\xe9\x9f\xd2\x7d\x9a\x5a\xc8\x57\x6f\xf7\x48\xc9\xdb\xfa\x35\x78\xf7\x3a\x78\xbb\x77\x76\xdf\x94\x30\x9a\x14\x23\x3d\x08\x9f\x36\x06\xfb\x98\xb4\x11\xe6\x42\xa1\xe4\x41\x91\xd6\xac\xa2\x10\x56\x41\x10\x04\x97\x51\x29\xec\xbf\x1a\xb3\xe3\xee\xd6\x9c\x38\x81\x35\x11\x39\xd6\x4b\x7e\xac\x75\xdb\x71\xdb\x67\x2f\x93\xd3\x28\xb0\x52\x29\xcc\x52\xb3\x2f\xd6\xde\x9b\xe2\xf8\xfe\x7e\x32\xc5\x9c\xf1\x53\x08\xdf\xfd\x42\x07\x49\xf0\xcf\x8f\xdf\xb9\xf0\x2b\x66\x32\x47\x17\xfe\x46\x82\x2a\x74\xe1\x37\x52\x09\x0a\x74\x41\xa3\xd0\x4b\xbb\xd8\xf4\x16\xa9\xc0\x24\x61\xe2\x10\xc2\xeb\x60\x68\x64\x70\x58\x7b\x05\xa9\x74\x59\x5f\x01\xfe\x07\xc5\xb7\x93\x14\x9f\xa9\x49\xba\xbd\xe4\xc9\xed\x74\xc2\x74\xc1\xf1\x14\x02\x13\xb6\xa3\x5b\xee\xb9\x8c\x7f\x9f\xb4\xff\xaa\xb6\xdf\x6e\xcb\x32\x46\x51\xa1\x1e\x11\xc9\x5a\x33\xef\x82\xd1\x22\xda\x9d\xb0\xce\xdf\xcc\xd6\xbe\xed\x7f\xed\x4d\xa8\x76\x77\xcc\x51\xeb\xc8\xb9\x41\x2f\xf0\x40\xdd\x45\x28\x61\xd5\x8d\x4c\xed\x01\x67\x53\x9f\xe2\x7d\x0b\x50\xb7\x29\x6d\xb9\x5a\xfb\x09\xab\x5a\x65\x6e\xeb\xe1\x66\xcd\x44\x51\x1a\x60\xc9\xd8\x0c\xa9\x65\x93\x0d\x6d\x48\xc4\x19\xc5\xbf\xef\xe5\xd1\x81\xfa\x8d\x12\xf0\x37\xf0\xc1\xc6\x39\xe8\x18\x39\xd9\x9c\x6a\xba\x94\xb5\xdf\x40\x5f\x39\xde\xa1\x37\x2e\x72\x36\x43\x3e\xe3\xdb\x95\x3d\xaa\x63\x5d\xfd\x4c\xba\xe4\x06\x22\x70\xce\x67\xef\x07\x34\x78\xb9\x38\xb3\xe9\x4b\xc4\xf8\x3a\xd3\xa8\x5e\x4f\x6d\x2b\x5d\x5f\xf2\x20\x82\xf6\xb6\xe7\x31\xc1\xcc\x3c\x91\x71\x69\xef\x6a\xde\x81\xcc\x8f\xbc\xbe\xb6\xfd\xe5\xf4\x31\x99\x4f\x92\x5e\xb8\xe0\x70\xbb\x99\x83\x36\xdd\x22\xf7\x35\x25\x82\x6f\xc3\xbb\xba\xf8\x0a\x54\xb3\xf2\x34\x99\xb6\x39\xfb\x7a\xb7\xe6\xb5\xdb\x31\xe8\x4e\xfa\x29\x29\xe2\xcc\x56\x24\x88\x06\xad\xc6\xb8\xc9\xf8\x3f\x4c\xba\x75\x55\x5f\xdc\x47\x73\xbb\x95\x6b\xdf\x86\xf1\x66\x36\x5b\xfb\x99\xc9\xf9\x66\xf6\xdf\x01\x00\xa0\xc6\x59\xb0\xf9\x0f\x00\x00")
+
+func templatesHeatmapHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesHeatmapHtml,
+		"templates/heatmap.html",
+	)
+}
+
+func templatesHeatmapHtml() (*asset, error) {
+	bytes, err := templatesHeatmapHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/heatmap.html", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe3, 0xc2, 0x87, 0x1d, 0xf5, 0x6e, 0x70, 0x49, 0x34, 0xf9, 0x3a, 0xd0, 0xdc, 0xde, 0x16, 0x36, 0xc0, 0xe6, 0x53, 0x3a, 0x62, 0xe1, 0xb7, 0x31, 0x15, 0x8a, 0xc5, 0xa0, 0x4c, 0xf2, 0x53, 0x5a}}
+	return a, nil
+}
+
+var _templatesReferencesReadmeMd = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x92\x41\x6b\xdc\x48\x10\x85\xef\xfa\x15\x0f\xf6\x6a\x69\x67\x86\x59\x0c\xc6\x18\x96\x5d\xe3\x83\x21\x07\xdb\xe4\x6a\xb5\x5a\xa5\x51\x21\xa9\x7a\xd2\x5d\xad\xc9\xe4\xd7\x87\x6e\x8d\x6c\x93\x38\x57\xd5\xeb\xd2\xfb\xde\xab\xbf\xf0\x44\x1d\x79\x12\x4b\x68\x4c\xa0\x91\x85\x42\x51\xdc\xcf\xe4\xcf\xf8\xef\xf9\x2b\x3a\x1e\x09\x2c\xd0\x9e\x03\x5a\xf6\x64\xd5\xf9\x33\x38\x20\xf4\x7c\x3c\x52\x8b\x13\x6b\x8f\xe1\x48\xbe\x83\x09\x30\xf0\xbf\x2d\x44\xe7\x3c\xea\x45\x62\xdd\x74\x34\x9e\x50\x96\x6f\xba\xba\xc2\xbf\xc5\x27\xaf\x38\x40\x7b\xc2\x44\x26\x44\x4f\x13\x89\xbe\x3b\x3a\x79\x56\x25\x41\x73\x5e\x17\x07\xf2\x33\xdb\x37\x79\x7d\x05\xaa\x0e\x55\x51\xef\x36\xbb\xed\x66\xbb\xbd\xde\x6e\xf6\xd7\xfb\xeb\xd7\x21\xcc\xf6\xd5\x7a\x32\xca\x4e\x5e\x95\x27\xaa\x6c\x98\xeb\x2b\xb8\x0e\x06\x41\x8d\xb4\xc6\xb7\xf0\x51\x12\xb5\xc1\x20\xee\x24\x20\x99\xd9\x3b\x49\x1e\xaa\xa2\x78\x71\xb0\x4e\xd4\x73\x13\x95\x3e\x25\xbe\x29\x8a\x12\x0f\x24\xe4\x4d\x52\x48\xbb\xda\x82\x51\x8c\x64\x82\xe2\x9f\xcd\x6a\x39\x2c\x11\xfe\xc2\x71\xb8\xbc\xae\xf3\xf3\x3f\x40\xc2\x25\x8f\x76\x8c\x41\xc9\x17\xc0\xa9\x67\xdb\x27\xf3\x01\xe2\xb4\x67\x39\xa0\x89\x8a\x47\x31\xca\x33\x55\x45\x89\x2f\x66\xa2\x1c\x6b\x2e\xb6\x1e\x96\x49\x79\x3b\x93\x0f\xec\xe4\xae\xbc\xfd\xc0\x7a\x77\xc9\x26\x25\xf9\xae\xdd\x56\xdb\x7d\x79\x18\xa8\x94\x5d\x9e\xe7\x7a\x2f\xbf\x40\x1a\x26\x57\x0f\x8f\xf7\x19\xab\x00\x64\x57\xae\xb9\x96\x7b\x88\x6b\x29\x54\x78\xe9\x09\x92\xbc\x24\x91\x8b\x9a\x3d\xd5\xcb\x3e\xfa\xae\x24\xc9\xcd\x7a\x02\x59\xe8\xba\xac\x79\x0b\x3b\xd1\xfc\x4f\xc1\x7a\x6e\x16\xa2\x0f\xc6\x53\x77\xe9\xd3\x31\x8e\x23\x3c\x7d\x8b\x14\xf4\x26\x8b\x1e\x63\x43\x5e\x48\x29\xe0\x82\x9c\xf3\x6d\x39\x2c\x7d\xb2\x93\xab\x2c\x94\x38\x35\xe4\xf3\x30\xf0\x8f\xf5\xf7\x09\x27\x01\x5c\x34\xa4\x27\xe7\x87\x14\xf3\x68\xce\x17\x75\x1a\xac\x69\x3c\xa7\xbe\xe4\x90\xce\xa5\xe3\x43\xf4\xf9\xea\xc0\x1d\x58\x13\x9b\xb8\x85\xbb\xa5\xce\xc4\x51\xe1\x24\x77\xf4\x14\x05\x75\x6f\xec\xf0\x77\x13\x79\x6c\xcb\x2e\x8a\x0d\x55\xe8\x6b\xa8\x03\x4d\x0d\xb5\xef\x0d\xb2\xa8\xc3\x70\x24\xdf\x55\xc5\xcf\x01\x00\x80\xee\x05\x30\xcd\x03\x00\x00")
+
+func templatesReferencesReadmeMdBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesReferencesReadmeMd,
+		"templates/references/README.md",
+	)
+}
+
+func templatesReferencesReadmeMd() (*asset, error) {
+	bytes, err := templatesReferencesReadmeMdBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/references/README.md", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xcc, 0x2, 0xaf, 0xe2, 0xf8, 0x40, 0x83, 0x28, 0xc5, 0xc8, 0xcf, 0x13, 0xbf, 0x1f, 0xc5, 0x8c, 0x5b, 0xd2, 0xd7, 0xda, 0xdc, 0x84, 0xbf, 0x73, 0x56, 0xc5, 0xcf, 0x69, 0xc, 0x96, 0x2e, 0xdc}}
+	return a, nil
+}
+
+var _templatesReportHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x3a\x6b\x8f\xe3\x36\x92\xdf\xfd\x2b\x6a\x05\x04\x6b\x6d\xcb\xb2\xdd\x99\xdd\x2c\x9c\x76\x07\xbb\x33\x49\x6e\x80\xbb\x24\xd8\x99\x0d\x70\xeb\x33\x1a\xb4\x44\xdb\xec\x96\x49\x1d\x49\xdb\xed\xeb\xf8\xbf\x1f\x8a\xa4\xde\x94\xfb\x31\xc1\x7d\x3a\xb4\x31\x23\x89\x55\xc5\x62\x55\xb1\x5e\xe4\xcd\x1f\x3e\xfc\xfc\xfe\xf3\x7f\xfe\xf2\x3d\x6c\xf5\x2e\xbb\x1d\xdc\xd8\xff\x06\x37\x5b\x4a\xd2\xdb\x01\x00\xc0\xcd\x8e\x6a\x02\xc9\x96\x48\x45\xf5\x3c\xd8\xeb\xf5\xe8\xaf\x81\x1b\xd2\x4c\x67\xf4\xf6\x17\x2a\xd7\x20\x69\x2e\xa4\xbe\x19\xdb\x4f\x76\x58\x25\x92\xe5\x1a\x94\x4c\xe6\xc1\x56\xeb\x5c\xcd\xc6\xe3\x24\xe5\xf1\xbd\x4a\x69\xc6\x0e\x32\xe6\x54\x8f\x79\xbe\x1b\x53\x24\xaf\xd5\x38\x65\x4a\x17\x2f\x23\xca\xe3\x1d\x43\xe0\xe0\xf6\x66\x6c\x49\x35\xe8\xda\x17\xfc\x1b\x8f\x41\xb3\x1d\xcd\x18\xa7\xbf\x6c\x89\xa2\x0a\x88\xa4\xa0\xb7\x14\x72\xfb\x2a\xd6\xe6\xad\x00\x82\x03\xa3\xc7\x08\x28\x49\xb6\xb0\x96\x62\x07\x04\x94\x26\x52\x83\x16\x40\x38\x50\x9e\x42\x22\xb2\xfd\x8e\x17\x88\x92\x1c\xdb\x93\x29\x4d\x76\xb9\x2a\xbf\x26\x82\x2b\xdd\xe6\x62\x0e\x8b\x12\x00\x7f\x8b\x20\x11\x7c\xcd\x36\x7b\x49\x34\x13\xfc\x4e\x52\x92\x9e\x82\x08\x02\x75\x48\xee\x12\x49\x89\xa6\x29\xbe\xfa\xa0\x96\x51\x8b\x94\xa4\x07\xa6\x1a\x54\xca\x2f\x35\x52\x2d\xa8\x0e\x95\x94\xe6\x99\x38\xed\x28\xd7\x5e\xac\xda\x37\x0f\x64\x87\x5a\x2e\xd2\x3b\x95\x6c\x69\xba\xcf\x2c\x21\xfc\x50\xa3\xd1\x1c\xef\xa0\x27\x82\x6b\xc2\x38\x95\xaa\x5a\x54\x8b\x42\x07\xa4\x43\xe4\xbf\xf7\x74\x4f\x47\xb9\x14\x8f\xa7\x3b\xa3\x55\x2f\x27\x3e\xa8\x0e\xa9\xbd\xa2\x72\x54\xce\x78\x81\x5a\x0f\x60\x87\xe0\x43\x4e\xee\x48\xa2\xd9\x81\x22\x11\x7c\xab\x11\xa9\x0d\x76\x10\xd5\x83\xb2\x88\x44\x0b\x79\x47\x79\x9a\x0b\xc6\xb5\xba\xcb\x45\xbe\xcf\x0a\x02\x08\x54\xa3\xf7\x3c\x8e\x77\x9a\x97\x13\x7f\x11\x49\xc6\x37\x92\x2a\x75\x67\x6d\xba\xd2\x6b\xf9\xbd\xa2\xe9\x05\xed\x25\x98\xad\x3c\xc4\x7a\x26\xc9\x56\x7d\xd4\x12\x2a\x35\x5b\xb3\x84\x68\x5a\xa1\xd5\x3f\xd6\xd6\xdc\x85\xed\x90\x93\x62\xaf\x69\xef\xbe\xae\x8f\x76\x50\xc5\x81\x4a\x92\x65\x2f\x43\x2e\x71\x97\x83\xf2\x71\x3c\x86\x1c\x9d\xf4\xfb\x4f\xbf\x02\x12\x51\x40\x00\x9f\x8f\x4c\x6f\x81\x00\x7a\x74\x2a\x81\x71\x2d\x8c\x33\x74\x1e\x8e\x93\x1d\xfa\x4b\x9e\x9a\x8f\x52\x1c\x55\x04\x0f\xf4\x44\x53\x58\x9d\xec\x60\x4e\x12\x3a\xc6\x27\xe7\x0d\xeb\x13\x2a\x2a\x0f\x2c\xa1\xc0\xb8\x41\x5f\x33\x89\x5e\xf0\x28\x9c\xff\xac\xfc\xe3\x7a\xcf\x13\xf4\x79\x25\x8b\xc3\x44\x1d\x42\x78\x2a\x01\xf0\x77\x20\x12\x34\x59\x65\x14\xe6\xf0\xe4\x28\xcc\x60\xb1\x8c\x0c\x5f\xf8\x74\x6e\xc0\xb3\x35\x20\x99\x58\x4b\xb6\x1b\x86\x30\x9f\xcf\x21\x08\xda\x44\xf1\x4f\x52\xbd\x97\xdc\xd2\x6e\x0c\x36\xe9\xe1\xfc\x18\x1f\xd0\x73\x57\x74\x63\x95\x67\x4c\x0f\x83\xff\xe2\x41\xd8\x00\x37\xe4\x62\xc7\x27\xcc\x2d\xea\x62\xb2\x2c\x10\xa2\x16\xfc\x5a\x48\x18\xe2\x1c\x0c\xe6\x30\xfd\x16\x18\xdc\x58\x9c\x38\xa3\x7c\xa3\xb7\xdf\x02\xbb\xba\xf2\xb1\x8f\x38\x07\x92\xed\x69\x35\x0b\xeb\x9d\xa5\x40\x90\xe2\x88\x62\x74\x1a\x9a\x39\x02\x8b\xe9\x12\xae\x20\x18\x07\x70\x55\x7c\x99\xb4\xa4\xda\x60\xf5\x1e\xe6\x70\xfd\x2d\xdc\xc3\x8d\x03\x2f\x79\xbd\xf7\xf3\x8a\x7f\x52\x1c\x17\x0d\xe1\x2c\xee\x97\x4b\x98\x17\x13\xde\x2f\x07\x2d\x04\xe8\x72\x60\xf1\x51\xef\x71\xbe\x57\xdb\xa1\x14\xc7\x70\xd0\x8f\xe2\xd5\xf0\xb9\xbb\x39\x3e\x17\xa1\xdb\xbe\xe2\x1e\x29\xa3\x39\x64\xec\x81\xc2\xf5\xe4\x7a\x3a\x9a\x4c\x47\xd3\x6f\x60\x3a\x99\xbd\xfb\x66\xf6\xee\x9b\x78\x7a\xfd\x35\x5c\x4d\x26\x93\x09\xfc\xf3\xf3\xfb\xa8\x16\xff\xe1\xb8\x65\xc9\xd6\xa4\x1b\x5c\x68\x50\x54\xd7\x67\xc4\xcf\x96\x31\x9a\x02\x51\xc0\xf7\x59\xd6\xb3\x23\x4a\xbe\x86\x46\x48\x6d\xc9\xa2\xa5\xff\xc1\x8c\xc0\x6f\xbf\x59\x39\xc6\x8c\xa7\xf4\xf1\xe7\xf5\x30\x98\x4c\x26\x86\xe3\xc9\x34\xb0\x9b\x60\x72\x61\x0f\x34\x78\xe8\xca\x11\x2d\x27\xc7\xf4\xab\x50\x57\x61\x66\x10\x84\x3e\x81\x7f\x20\x9a\xc6\x66\x05\x43\x83\xb6\x98\x18\xfb\xfa\x8c\xf6\x65\x3f\x58\x83\xfb\x57\x10\xf6\xa9\x05\x73\xa5\x5f\x71\x2a\xe5\x88\x2a\xe3\x4b\x94\xc0\x18\x0a\xa9\x4b\x83\x4c\x12\x47\x2c\x74\x54\xf8\x9d\x42\xfe\x29\x4b\x8d\xfc\xa5\x49\xe9\xca\xc4\xcf\xe8\x45\x3d\xb0\x3c\xa7\xa9\x47\xee\xd5\xc4\x43\x63\x6d\x91\xa5\xde\x16\x5e\x63\xf7\x2d\x96\x83\x1e\x2b\x5d\x0b\xf9\x3d\x49\xb6\xc3\x92\xbe\x31\xd9\x4b\xbb\x19\xe6\xd6\x08\x7f\xc8\x04\xd1\x08\xbd\x30\xf3\x2f\x9b\x82\x2e\xd5\xcf\xd4\x4f\xe4\x27\x67\x1e\x3e\x05\xe3\x9f\xdb\xa3\x66\xc7\x98\xe7\x2e\xad\xa6\xbe\xcf\x5e\xad\x3a\x32\xa8\x82\xda\x7a\x48\x04\xab\x10\x9e\x0a\x20\x02\x23\x58\xc1\xd9\xab\xd7\x12\x69\x43\xf5\xbf\x31\xa5\xc5\x46\x92\xdd\xcf\x39\x7e\x6a\xc8\x3a\x82\x15\xe3\xea\xa2\xc4\x7b\xd5\xd4\x41\xd9\x31\x0e\xf3\xa6\x9b\x82\x5b\x98\xc0\x77\xee\x1b\xda\xe6\x0c\x26\x5d\x3c\xf2\x78\x19\xaf\x39\x34\x82\xa9\x9f\xce\x91\xa5\x7a\x0b\x73\x43\xef\xd6\x70\xf3\x1d\x0c\xf1\x65\x84\x2f\x21\x8c\xcd\x62\x61\x06\xd3\x0e\x6a\x46\x56\x34\xf3\xd8\x17\x92\x4d\xc4\x9e\x6b\xcf\x58\x23\x9c\x4c\x6c\x38\xc1\x09\x7a\xc3\x88\x9d\xc4\xba\xd3\x21\xb2\x77\x05\x0c\xfe\x64\xd9\x0e\x63\x2d\x7e\x60\x8f\x34\x1d\x5e\x87\xb8\x5f\x55\x6b\xbb\xe3\xcf\x32\x62\xf1\x27\xe1\xa0\xdf\xa8\x9c\xbc\xba\x3b\xc2\xeb\xdb\x2a\xd2\x8b\xff\x20\x7a\x8b\x45\xdf\xd0\x3c\xac\x33\x21\xe4\xd0\x5a\x7c\x25\x43\xcb\xae\x35\x1c\x18\xc1\x34\x5c\x5e\x5d\xbd\xc0\xa4\xbb\x73\x9a\x4a\x75\x06\x4f\x9a\x3e\xea\x19\x04\xa5\x99\xa2\xa3\x31\xee\x0b\xad\x2c\x02\x1c\xfe\xa4\x4f\x19\x9d\xc1\xd3\x5a\x70\xfd\x89\xfd\x0f\x9d\xc1\xf4\x2f\xe7\x73\x33\x83\xc3\x3f\x2d\x44\xa6\x59\x8e\x44\x25\xdb\x6c\xa8\x9c\x41\x40\x1e\x99\x0a\x7a\x60\x57\xe2\x11\xa9\x52\xa2\xf7\x12\xe9\xa7\x44\x93\x7f\x09\xb1\x9b\xc1\xd3\x39\x02\x49\x95\x16\xe6\xfb\x39\x02\x1c\xfa\x95\xd1\xa3\x7d\x53\xe4\x40\xff\xa6\x3e\xee\xc8\xc6\x0c\xfb\x78\xd9\x48\x96\xce\xe0\x29\xa3\x6b\x5c\xdd\xd7\x5f\x05\x11\x48\xb6\xd9\xe2\xcb\x3b\x7c\x59\x09\xad\x71\xa6\xeb\x49\x04\xae\x74\xf9\x77\x34\x90\x19\x68\xb9\xa7\x1e\x82\x8f\x7f\x7b\x64\x0a\x97\x76\xca\xe9\x0c\x02\x4c\x99\x37\x42\x9e\x02\xcb\xdb\xcc\x99\x57\x64\x92\xc6\x19\x04\x85\xe3\xf6\xad\xfd\xd4\x24\x65\x34\x1c\x94\x98\x85\x73\xf7\x61\x2a\x2a\x19\x55\x33\x58\x3c\x59\x60\xe7\x45\x1c\xa1\x15\x91\x25\x3b\xd6\xa4\x22\x58\x11\xf9\xde\xb1\xfa\x23\xc9\x67\x10\xfc\xf9\xab\xe0\xbc\xec\xb1\xde\x1e\x17\xf6\xfe\xc3\x0f\x1e\xe7\xe5\xf5\x5b\x96\xc1\xc2\x6f\xa9\x78\x47\xf2\xda\x06\xf0\x06\x98\x37\xba\xbc\x8b\xc6\x8d\xbf\xba\x84\xbc\x00\x4e\x6a\x98\x51\x06\x7e\x08\xa5\x29\x8a\x8c\xf2\xb4\x0f\x60\x2b\x8e\x9f\x4e\xbb\x95\xc8\x66\xb0\x26\x59\xdf\x4c\xd6\x42\x9c\x5f\x68\x8a\xc4\x7c\x8c\x80\xf9\xa4\xd2\x5a\xe6\xc2\xc1\x0e\x19\x5c\xc1\x14\x9d\x81\xa3\x68\x9d\xf6\x72\xe0\x41\x6d\xbb\x83\xae\xb7\x6a\x01\xa0\x26\x14\xcd\x68\x82\xd9\xc7\x1c\x9e\x9a\xc0\x4e\xab\x5d\xd7\xd6\xab\xd9\x82\x96\x0b\xee\x85\x7a\x4d\xa2\xd6\x2a\xfa\x2e\xb1\xf5\x52\x37\xf6\xfe\xc3\x0f\x90\x53\x69\x27\x09\xde\xea\xbe\x3a\x43\xf8\x6b\xf9\x34\xbf\xa6\xd7\x42\xee\x88\xd6\xe8\xfa\x6a\xc2\x21\x92\xec\x3a\xdb\xc5\xb3\x3c\x0b\xd8\xda\x34\x4c\xd3\xdd\x25\xdc\x1a\x3e\x82\xc6\x76\x0b\xfe\x84\x15\xeb\x15\x04\x33\xe3\xcc\x87\x66\xc4\x98\x0b\x96\x40\x7f\x82\xe9\x64\x52\xc5\xbc\xa9\x89\x79\x5f\x99\x52\x99\x71\x83\x50\x83\xb7\x29\xad\x0a\x06\x7d\xb3\x9f\xc3\xf8\x5e\x30\x3e\x0c\x6e\x56\x72\x7c\xeb\x89\x9c\x5d\xa3\xc3\x3f\x8f\x06\x32\xba\xa1\x1c\xdd\x76\xe1\x9d\x27\x85\x3b\x33\xfa\x54\x51\x69\x9a\xb3\xf2\xe9\xcb\x63\xcb\xef\x18\x4d\xfe\xfa\xc6\x68\xd2\x09\x01\x89\xe0\xa9\x7a\x45\xec\xd8\x31\x6e\xa4\xb5\x23\x8f\x33\x98\xbe\x2a\x96\x58\x7b\x19\xf8\x95\x55\x8b\x08\xe3\x31\x6c\xa8\xc6\x4a\x0d\x3d\xa6\x0d\x08\x90\x4a\x72\x54\xad\x5e\x33\x29\xfb\x22\x44\x61\xfc\x51\xb6\xcb\xac\xb7\x94\xc9\xaa\xd3\x6c\x5f\x29\x4f\x23\x6c\x9f\xb8\x15\x83\x62\x3c\xa9\x2a\x58\xec\x6c\x6f\x69\x49\xee\x48\x14\xb8\x96\x50\x09\x52\xee\x93\x0e\x6f\xbe\x02\x04\xbd\x9b\xa3\x00\xf3\x76\xe9\x89\x25\x48\xa3\xed\xb4\x0c\x5f\x95\xa9\x8a\xf5\x5a\x51\x5f\xaa\x8a\xb3\x56\x85\x5c\x67\xb8\xd9\x36\x7f\x8d\x73\x45\xc2\x56\xa0\xde\xc5\x18\xbc\xc5\x74\xe9\x29\xa9\x10\x93\xf2\x1e\x21\x58\xbc\x6b\x1f\x1e\x96\x62\xa5\x00\xe7\x73\x53\xd3\x63\x4d\xee\xb8\xa8\x7d\xa1\xbc\x82\xf0\xf1\x5e\xb9\xad\xc1\xf3\xbe\xa2\x9e\xbb\x5b\xee\x26\x1e\xe6\x9c\x02\x5c\x8a\x6f\x59\x1a\x15\xfa\xc6\x78\x39\x9d\x4c\x5a\x99\x3b\xfe\x4a\xd5\x38\x44\xe4\x7c\x64\x0d\xd5\x8f\xf4\xc6\xd8\x54\x98\x67\x91\x61\x4b\x71\x8c\x9d\x69\xff\xdf\x07\x2a\x8c\x61\xbf\x60\xfb\x9a\xca\xca\x9b\xa8\x2d\x49\xc5\x31\x38\xff\xae\xb1\xad\xa8\xd1\x6c\x70\xc3\x06\x21\xba\xf4\x8f\xd8\xbe\x19\x3c\x13\xcf\xac\xda\x17\x6c\x59\x45\x32\xa7\x63\xf7\x4d\xa1\x27\x41\x59\x0e\x1b\xdf\x4b\x8d\x2e\xd8\xd2\xd5\x73\xde\xa9\xba\x86\xf6\x05\xbe\xff\xfa\x77\xf5\xfd\xd6\x13\x16\xc6\xfb\x82\x48\xd0\x5b\x90\x30\x7e\xa0\x52\xd1\x5e\x76\xca\x9a\xa2\x33\x82\xbf\x82\xbc\xad\x2d\x94\x26\xc9\xc3\x0c\x82\xc2\x65\x95\x93\x39\xf1\x47\x26\x71\x28\xec\x38\x11\x99\xc0\x7c\x49\x4b\xc2\x55\x4e\x24\xe5\x3a\xf0\xd9\xf3\x2b\xe6\x29\x35\xdb\x55\xdd\x72\xe0\x57\x6d\x33\x84\x19\x77\xf7\xa3\x14\xfb\x5c\xc1\x8e\xe4\x36\x76\x75\x1b\xfd\xf4\x40\xe5\xa9\x0c\x3c\x5a\x00\xd3\x0a\x36\x88\x56\x12\x2b\xf7\x40\x8d\x64\x5f\x5b\xdf\x60\x2a\x4f\x3e\x5d\x3f\x0e\x78\x65\x23\xcd\xd2\x5c\xd4\xbc\x08\x36\x99\x4d\x10\x33\x43\xc1\xf2\x05\x4e\xcb\x40\xaa\x1e\x59\xad\x59\xa6\xa9\xfc\x8c\x25\x98\x83\x57\xe5\x31\x49\x71\x36\xec\xa6\x56\xc5\x19\x88\x21\x18\x81\x90\x40\xb2\xcc\x1c\x5c\x00\x5b\x03\x17\x56\x78\xc0\x14\x6c\xd8\x81\xf2\xae\x14\x6b\x93\x15\x55\x9f\x41\x51\xee\xff\xb6\x08\x30\x12\x99\x81\x2f\x3f\xf8\x70\x60\x5d\x7c\xd7\xbf\x9f\x41\xa3\x9d\xdf\x35\x60\x5c\x66\x01\x84\xcf\xb1\x5d\xcc\xf3\x3a\xac\xcd\xee\x55\xe7\x7c\xde\x32\xba\x1e\x6d\x9e\x7b\x34\xb8\xa1\xda\xd8\x7a\x59\xc1\x43\x22\x76\xb8\x13\x55\xa5\x2c\x85\x47\x5d\xf8\x86\xb5\x93\xd5\x2a\x93\xe0\x4a\x33\x73\x94\x76\xaa\x42\x64\x57\x6f\x9d\x29\xda\xda\xc3\x2d\xd5\x09\x10\xb8\x29\x84\x01\x87\x79\xb3\xc5\xf0\xd4\x23\xf4\xea\x10\x2c\x82\x45\x2b\xf8\x5b\x4a\xb1\x89\xb7\x31\x46\x52\x98\xdb\x52\x50\xac\x8b\x85\xd8\xa3\x43\x53\x1b\x1a\xc6\x02\x1f\x01\x5b\x7f\xe0\x36\xed\x14\x20\x66\x15\x67\x1f\x52\xd9\xf2\x30\x20\xad\xe2\x0d\xbf\xbd\xaa\xe1\x71\x61\x1b\x18\x5a\x51\xbb\x6a\x0e\x07\x3d\x06\x75\xa9\x2b\x82\xff\xfa\xfd\xf0\xff\x37\x45\x3c\x4d\x11\x37\x97\x55\xb8\x6f\xab\x95\xac\xaf\x59\x96\x7d\x32\x95\xe9\xd0\x16\xa8\x91\x33\x93\xce\x0e\x70\x9f\x3d\xfe\xde\x8e\xf8\xd6\x8e\x56\x43\x33\x8a\xb7\x50\x60\x0e\xa9\x48\xf6\xf8\x18\xdb\x24\xe1\x7b\x3b\x30\x0c\x2c\xbe\xa7\x0e\x77\xa8\x71\x71\xfc\x62\x01\x17\x93\x65\x2f\xa4\xdb\x4b\x0e\x70\xda\x05\xb4\x8b\x8c\x49\x9e\x53\x9e\xbe\xdf\xb2\x2c\x1d\x3a\xdc\xb0\x4f\xa0\x56\xd2\xed\x3b\x4d\x98\x32\x18\xe3\x9b\x07\x38\xe9\x38\x51\xca\x5d\xb3\xc2\xdf\x4a\xa4\xa7\x96\x3c\x30\x49\x1e\x29\xdb\x8d\x7e\x97\x3f\x7e\xdb\x1d\x5c\x93\x1d\xcb\x4e\x33\xf8\xe3\x27\xba\x11\x14\xfe\xf9\xf1\x8f\x11\x7c\x26\x5b\xb1\x23\x11\xfc\x48\x39\x3d\x90\x08\x7e\xa5\x32\x25\x9c\x60\x13\x80\xab\x11\x6e\xe5\x75\x93\x52\x4e\xd2\x94\xf1\xcd\x0c\xbe\x9e\xd4\x27\xa9\xe9\x3e\xce\xa9\x5c\x8f\x8c\xef\xb9\xc0\xe2\x5f\xbc\x2c\x1e\xa9\xed\x49\xaf\x44\x96\x36\x87\x53\xa6\xf2\x8c\x9c\x66\xc0\x38\x26\x40\xa3\x55\x26\x92\x87\x0b\xf3\x9b\x5b\x63\xad\xf9\xb7\x8e\xfa\xbb\x3f\x37\x78\xc7\xdf\x8e\xc8\x0d\xe3\xa3\x2a\x77\xad\x03\x94\x1a\x42\xa5\xdc\x0e\x6e\xc6\x78\x79\x02\xaf\xc5\x19\x35\x24\x19\x51\x6a\x1e\x98\x59\xed\xb5\xb7\x51\x4e\x36\xb4\xb8\x14\x97\xb2\x03\xb0\xb4\x39\x6e\x3c\xee\x48\x51\x63\xe0\x35\xb5\x1a\xe0\x3a\x3d\x23\xc5\xe0\xd6\xc4\xac\xd9\xcd\x38\x65\x87\x1a\xb0\xb5\x35\x3f\x71\x73\x49\xce\x8c\x3b\x36\x2a\xdc\x9b\x26\x95\x9e\x29\x4d\xf1\xfd\xd2\x29\x4d\x09\xda\x9e\xf2\x12\xf5\xbf\x33\xae\x3a\xc4\x19\xcf\xf7\x5d\xda\x78\xb0\x13\xb8\x9d\xc0\xf7\xbb\x15\x95\x01\x9e\xfd\xcc\x83\x69\x60\x0f\xe4\xe6\xc1\xd7\x93\x00\xc6\xde\x75\x76\xa8\x6d\x8b\x63\x9d\xa0\xc1\x96\xb1\x96\xe0\xf6\x39\xec\x24\x5d\xbf\x09\xcf\xe8\x5b\xbd\x09\xb5\xc8\xf8\x5f\x61\x2d\x9f\xdc\x1d\x8f\x17\x2a\xcf\x65\x57\xbd\xea\xeb\xe3\xe8\xd9\xe5\xd4\x1e\xdb\x57\x34\xd1\x71\x27\xea\xf0\x0f\xaa\xf6\x19\x36\x6b\x82\xa7\xa7\xf8\x03\xd1\xe4\x7c\x0e\x3a\x30\xe4\xe8\x00\xfe\x41\x8e\x9e\x71\x57\xbb\x58\x10\xfb\xd2\x86\x2a\x2e\x11\xd5\xab\x0b\x3b\x75\xe5\x85\x11\x4e\x92\x63\x1b\x8a\xd4\xae\x99\x34\x0a\x97\x56\x91\x63\x27\x6e\xc2\xba\x0e\xe0\xbc\x99\xb8\xc5\x2a\x63\x09\x1d\x5e\x87\x83\x76\x5b\xaf\xb4\xcd\xf2\xfe\x15\xa6\x6c\xc9\x5e\x1e\xa8\x32\x49\x44\xb3\xc0\x28\x0b\x0e\x77\x38\xd1\x4c\x8c\x4b\x66\x6d\xf4\xad\x87\xc7\x0d\xd5\x2e\x36\xfe\xfd\xf4\x31\x1d\x36\x94\x6b\x50\x82\xb0\x4b\x06\xfb\xe7\x0a\xe6\xf0\xf3\xea\x9e\x26\x3a\x7e\xa0\x27\x35\x34\xc0\x2a\x6c\x25\x2b\x8e\xc1\xda\xd5\x00\x97\xca\xbb\x81\x25\x9c\xc3\x6e\x41\xe0\xea\x24\x16\x61\x9d\xd4\x8e\xf5\x8e\x0e\xc9\xb2\xf2\x9a\x8b\xab\x81\xb0\xd6\x61\x25\xec\x39\xb4\x97\x14\x2a\xfe\x6b\xf9\x47\x4d\x1a\x11\x2c\x16\x01\x5e\xaa\x23\x59\x16\x2c\x97\x71\x22\x78\x42\x1c\xc4\x4f\x9e\xac\xb5\x28\xb8\x0a\x46\x16\x8e\x5b\xf3\x1f\xae\x27\x6c\x4a\xac\xd0\xc9\x67\x67\x78\x15\xa5\x9e\x95\x3d\x57\xec\xd9\x25\xc4\xad\x3b\x1c\xe7\x41\xd7\xde\x5e\xa7\x6e\x63\xa2\x2d\x75\xa3\xc3\x7d\x29\x3e\xc2\x06\x5e\x69\xd7\x98\x29\xce\x47\x5b\x42\x2d\xba\xb8\xa5\x50\xdd\xe1\xad\x3b\x18\x3b\xd7\x64\x5a\x23\x56\x26\x6b\x7d\x87\x65\x28\xff\x6a\x27\xcd\xc1\xdd\x1f\x8f\x19\x67\x7a\xf8\xa2\x45\x95\xd8\x41\x18\x41\x90\x61\xce\xd0\x12\x11\x76\xf9\xab\x0b\x02\x97\xf4\x5b\xd2\x8a\x15\xd5\xae\xa2\xf3\x5c\x82\x69\x18\xcc\x30\x8c\xba\x2b\x8e\xc0\x5c\x82\xd8\x91\x47\x6c\x31\x2a\xfa\x91\xeb\x21\x4a\xdf\xd9\x04\xf6\x9c\xa7\x11\x4c\xc3\x30\x32\x7d\xad\x70\xd0\x4d\xe4\xeb\x34\x05\x4f\xb6\x84\x6f\xd0\x38\x1b\x8b\x29\x81\x0d\xed\x67\xa1\x1a\x5f\x87\x35\x97\x86\x5a\x48\xd2\xf5\x9b\xe4\x8f\x51\xf6\x92\xe4\xd1\x2b\x5e\x92\x79\x92\xae\x9b\xd2\xae\x8a\x69\xbf\x9c\xd5\x05\xa1\xb9\x09\xeb\x6b\x2b\x3b\x2d\xd6\x55\xb8\x7b\x40\xde\x9b\x76\x2f\x5a\x6f\x33\x1b\x0c\x63\x93\x66\xc6\x2e\xe1\x45\x4b\xe7\x82\xd3\xe0\x8d\x74\xd5\xf3\x04\xcf\x40\x33\x45\x5b\x9c\xd7\xdd\x4e\xcd\x0c\xfa\xa5\xee\x33\x87\xe2\x7b\x57\x98\xc5\x97\xa6\xb0\xf1\xef\xf5\xf6\x52\xac\xb3\x32\x99\xa6\xfa\x2f\xb7\x63\x2a\x4d\x86\x5e\xcf\x8a\xda\x96\xe4\x68\x9b\x91\x5f\xac\xeb\x4e\x3a\xf7\x36\xed\x14\x07\x81\x99\x38\x52\xa5\xab\x9c\x00\x6f\x38\x66\x4c\xe1\x69\x94\xb9\x7c\xdd\x40\xc2\xfd\x63\xbb\x3e\x9d\xbe\xeb\x5b\x2e\x2e\x1a\x52\xed\x76\x6b\xeb\x06\x63\xcb\x49\x2f\x7b\x8b\xe0\x82\xbf\x72\x25\x73\x28\x85\xde\x0c\x1b\x52\x1c\x6d\x27\xa4\x88\x1b\xac\x0c\xfb\x9d\xbb\x89\x0d\xf2\xb5\x70\x3b\x74\xbc\xbb\x19\x16\xab\x65\xb5\x86\xdf\x7e\x43\xd5\x8e\x3a\x30\xa4\x0d\xf3\xc2\xa5\xbc\x2e\x20\x3b\xa4\x9a\xd7\x6b\xc5\xd5\x06\xd5\xea\xe6\x6b\x2b\xb6\x7a\x3b\x45\x35\xa6\x6a\xe2\x5d\xb0\x72\x61\x7d\xe2\x5a\xb0\x72\x22\x3c\x5a\x2a\x1e\x19\x77\xe6\xf4\x1d\x04\x30\xc4\x53\x27\xf3\xba\x70\xe3\xf6\x40\x2a\x0c\x60\x86\xdd\xe8\x66\xab\xe4\x1c\x76\xe5\x55\x6c\x8d\x37\xc5\x8c\x02\xd9\x1b\x38\x8a\x19\xd0\xfd\x94\x87\x8e\xcf\xf9\xb2\x82\x62\xd3\x97\x14\xe8\xee\x4b\x29\x44\xb7\x66\xe7\x30\x4d\x4c\x5e\x76\xa2\x4a\xd7\xd9\x35\xd1\x5a\xe1\xb6\x98\xab\x81\x51\x1f\x18\x86\xdd\x16\x85\x2b\xb4\x6e\xc6\xd8\x9b\xb8\x1d\x0c\x6e\xc6\x5b\xbd\xcb\x6e\x07\xff\x3b\x00\x86\x90\x44\x61\xdb\x37\x00\x00")
+
+func templatesReportHtmlBytes() ([]byte, error) {
+	return bindataRead(
+		_templatesReportHtml,
+		"templates/report.html",
+	)
+}
+
+func templatesReportHtml() (*asset, error) {
+	bytes, err := templatesReportHtmlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/report.html", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7a, 0x69, 0xc8, 0xf1, 0x39, 0x2d, 0x53, 0x42, 0xc4, 0xda, 0xc5, 0x40, 0x1b, 0x14, 0x20, 0x49, 0x7d, 0x42, 0x45, 0x2d, 0xb0, 0x27, 0x80, 0x33, 0x4b, 0xdf, 0xab, 0xd2, 0x54, 0xe4, 0xd4, 0xa1}}
+	return a, nil
+}
+
+var _templatesSingle_chartHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x3c\x69\x93\xdb\x36\x96\xdf\xf5\x2b\x5e\x34\x87\xd4\x63\x01\x22\x40\x10\x24\x95\x56\xd7\x3a\x6d\x4f\xec\x1a\x39\x49\xc5\x8e\x77\x77\x7a\x5d\x29\x8a\x44\x4b\x6c\x53\xa4\x96\xa4\xfa\x48\xaa\xff\xfb\xd6\x03\x48\x9d\xa4\x5a\x3e\x66\xaa\xa6\x76\x44\x59\x04\x81\x87\x77\xbf\x87\x83\x68\x9f\x7f\xf3\xe2\xc7\xcb\x77\xff\xfd\xd3\x4b\x98\x97\x8b\xe4\xa2\x73\x6e\x6e\x9d\xf3\xb9\x0a\xa2\x8b\x0e\x00\xc0\xf9\x42\x95\x01\x84\xf3\x20\x2f\x54\x39\xee\xae\xca\x6b\xe2\x75\xab\xa6\x32\x2e\x13\x75\xf1\x93\xca\xaf\x21\x0a\x8a\xf9\x34\x0b\xf2\xe8\x7c\x68\x6a\x0d\x44\x11\xe6\xf1\xb2\x84\x22\x0f\xc7\xdd\x79\x59\x2e\x8b\xd1\x70\x18\x46\x29\xbd\x29\x22\x95\xc4\xb7\x39\x4d\x55\x39\x4c\x97\x8b\xe1\xcd\xff\xae\x54\xfe\xf0\x1f\x36\x75\x28\x1b\x46\x71\x51\x56\x35\x74\x11\x23\x74\xf7\xe2\x7c\x68\x70\x7d\x2a\x62\x85\xac\x97\x85\xc1\x59\x3d\x10\x95\x1e\xc7\x6b\x88\xe0\x15\x66\x69\x51\x42\xa2\x66\x2a\x8d\x5e\xe3\x03\x8c\xe1\x6a\xdd\x8a\xdf\xde\x32\x28\xe7\xa3\xe1\xf0\x8d\xc3\x38\x38\x8c\x2f\x88\x10\x1e\x58\x01\xfe\xe2\x3f\x0b\x18\x58\xe0\xf9\x12\x2c\xd8\xa9\x23\xba\xee\xef\xbd\x41\x33\x3a\x21\x38\x75\x81\x09\x87\x7a\x13\x29\x29\x03\xd7\xf7\x28\x0f\x89\x6d\x51\x0f\x1c\x9b\xda\xe0\x62\x3b\xb7\x40\xfa\xd4\xc6\xc2\xdc\xc5\xea\x50\x32\x8a\xb4\x98\x65\x51\x46\xa4\xa4\xae\x06\x20\x8c\x5b\x13\xc7\x63\x08\x8a\x38\x0d\x22\x82\x3d\x08\xb3\xdc\x75\xd1\xf6\xb0\xf7\x6f\x6d\x5c\xb9\xd2\x02\x5f\x5a\xaf\xb8\x14\x21\x61\xcc\xa2\x0e\x58\x84\x5b\x16\xf1\x7c\xea\xe8\x02\xb7\xac\xf7\xd8\x6a\x55\xcd\x75\x03\x54\x8d\x73\xe1\xcb\xb0\xea\x89\x75\x1a\x00\x2a\x80\x5b\x6c\xb4\x40\x37\x93\xba\xa1\xee\xdd\xca\x94\x90\x0e\xf8\x92\xfa\x13\x5f\x52\x07\x6c\x29\xa8\x0c\x09\xf7\x80\x5b\x54\x10\xdb\x47\x7d\x49\x64\xc2\xa7\x0c\xa9\x89\x84\x09\x8b\xba\x20\x6c\x9b\xb2\x90\x61\xd1\xb6\x41\x30\x2a\xc0\x71\x50\xaf\xa8\x6d\x2c\xcd\x85\xe3\x50\x11\xda\x82\xba\x60\x81\x74\xa8\x20\x9c\x57\x00\x04\x01\x26\xbe\x83\x14\x85\x23\x34\x1a\x62\xdb\x84\x11\xe9\x53\xa6\x69\xa1\x00\x62\xe2\x38\xbe\x66\x0e\x39\x22\x9a\x23\x29\xcd\xdd\x17\x47\x14\x2d\x5d\x87\xfa\x80\x96\xe1\xaf\x6c\xe1\x51\x16\x12\xc1\xa9\x0f\x16\xf1\x38\xea\x8b\x53\x9f\x30\x4b\x80\xb4\x28\x9b\x78\x16\x08\x87\x23\x0c\x47\x29\x6c\x17\x39\xc0\x92\x87\x3e\x84\xfa\xf4\xa9\x97\x30\x29\x28\x03\xee\x09\xea\x85\x35\x1c\x07\xc9\x28\xd3\x58\xa0\x46\x37\xb7\xb9\x4b\xbd\xd0\x90\x03\x24\x87\x62\x23\x33\x82\x60\xfb\xc4\x17\x02\x1c\x97\x51\x5f\xa3\x21\x48\x0e\x74\xc9\x90\x23\x9a\xdc\xc4\x75\x7d\x94\x40\xba\xd4\x36\x7c\x69\x40\x82\xf4\x34\x1a\x52\xe3\x6b\xd5\x01\xb3\x2c\xe4\xd8\x61\x7c\x22\x7d\x0e\xb6\xad\x03\x0c\x18\xe2\xc5\x07\xfc\xa7\x1f\xb0\x16\x1f\xa4\xcf\x13\xe6\x59\x60\x33\x4e\x99\xee\x23\x7d\xde\x8a\xde\x11\x28\x3a\xb3\x38\x15\x13\x29\x39\xd8\xc2\xa5\x76\xc2\x5d\x8b\x72\xb0\x7d\x6a\x87\x1c\x83\xc7\x46\x62\x2e\xb5\xc1\x96\x54\x02\xf3\xd0\x47\xc4\xc4\x75\xd0\x29\xa4\xcd\xa9\x93\x08\x49\x39\x70\x34\x7b\x28\xa8\x04\xae\x7d\x0d\x03\x54\x48\xea\x11\x81\xda\xb1\x05\xb5\x27\xc8\xa5\x67\x79\xd4\x4b\x08\x17\x4c\x07\xaf\x1b\x12\x6e\x53\x0f\x18\x2a\xd9\x61\xd4\x25\x2e\x75\x75\x17\x82\x5d\x34\x6a\xa2\x51\x4f\x5c\x84\x13\x82\x87\x84\xe9\x70\xf6\xa8\x47\x3c\x2a\x89\x83\xe1\xce\x3c\xf4\x47\x31\xb1\xd7\x62\x30\x6e\x51\x8f\x70\x81\xc6\x66\x9c\x70\x41\x39\x08\x89\x1e\x8c\x25\xc7\xa3\x36\x39\xa6\x7a\xd7\x97\xa8\xeb\x04\x6d\xc4\x3c\x2b\x64\x92\xfa\xe8\xd4\x36\x11\x94\x13\xe9\x50\x9f\xd8\x9e\xbe\xbf\x92\x0e\x3a\xb9\x05\x9e\x0c\xc9\x06\xcc\xf1\x29\x37\x25\x1d\x4e\xd6\xc4\x76\x2d\xe0\x98\xc8\x5e\x31\xa9\x5d\xd5\x46\x81\x2c\xe2\x08\x54\xb0\xa4\x92\xd8\x1e\xc6\x98\x8f\x34\x81\x79\xd6\x84\x71\x01\xd2\x47\x81\x25\xf5\x01\x71\x01\xf2\x8e\x30\x50\xc1\xce\xb9\xe5\x4d\x84\x2b\xc0\xb7\xbd\x70\x03\x86\xc4\x4d\x07\x43\xbc\x16\x83\xb2\x57\x9e\xe4\xa1\xa1\x0c\x48\x99\x68\xab\x56\xa2\x4c\x2a\xa9\x5b\xb5\xe2\x31\x0f\xf3\x86\xeb\x52\x67\x4e\xb4\x10\x0c\x4d\x8c\xba\x76\x31\xc1\xa2\x7a\x99\x4d\x5d\xc2\x31\xc5\x56\x65\x0f\x3d\xd4\x22\x4c\x62\x66\x15\xae\xae\xe7\x94\xeb\xa0\x14\x84\x7b\x94\x11\xe6\x60\x20\x70\xf4\x05\x49\x7c\x81\x68\x6c\x2a\x2f\x99\x63\xa3\x03\x3a\x3e\x95\x20\x05\x08\x81\x12\x49\xcc\x52\x3e\xf5\x43\x4c\xf1\x2e\x78\x02\x98\x2f\xa8\x03\xcc\xf3\xa9\x8b\x69\x32\x71\xa4\x03\x16\xb5\x43\xd7\x43\xd9\x81\x09\x46\x6d\x22\xd1\x21\x4d\x51\xff\x82\x45\xb0\x5d\xd7\xeb\x1a\x41\x36\xad\xa2\xdd\x2f\x1c\xd4\x1d\x7a\x86\xeb\x10\xd7\x09\x25\xca\x8f\x3f\x80\x3f\x84\xb9\x0e\xd2\xd4\xfe\x6d\xea\xc9\xa6\xde\x14\x75\x13\x58\x09\x71\x1d\xd0\x38\x10\xcd\x51\xd0\x2d\xfc\xf8\x03\x15\x11\xd0\xcd\x49\x8d\x06\x6a\x34\x6d\x90\xb0\x6e\xac\xea\x75\xb1\x66\xa7\xc6\x01\x95\x50\x6d\x80\x5b\xd8\x0f\x25\x46\xa1\x88\xeb\xfc\xd6\x5b\x2b\xef\xc3\xba\x84\x93\x87\x12\x0a\x95\xa8\xb0\x7c\x9e\x24\x38\x99\x80\xf1\x46\xb1\x98\x1c\xa4\xe5\x05\xbe\x04\x9c\x16\x00\xc3\x69\x03\x61\x3e\x87\xba\x46\x4f\x1a\x80\xf9\x7c\x61\x11\xee\xc8\x90\x78\x1e\xe5\x5c\x20\x94\xb4\xc0\x65\xd4\x75\xa5\x2e\x32\x69\x15\xe6\x11\xaa\xc7\xfa\x1f\xd9\x54\x93\xcd\x23\xa9\x1f\x11\x4a\x4f\x63\x3c\xcb\xc2\x94\xcd\xa9\x25\x11\xbf\xed\x09\xa4\x68\xee\x9e\x57\x30\xcc\xfd\xb6\x24\xdc\xf3\xa0\xaa\xc3\x3b\x70\x47\x9a\xbb\x87\x16\xd7\x30\xba\x5c\xd5\x2d\x2c\x22\x85\x75\xc9\xa5\x43\xb9\xf0\x90\x09\xe3\xd5\x36\xb5\x2c\x0f\x8b\x0e\xe3\xa1\x05\xd2\xa3\xbe\xcf\x81\x5b\x4c\x83\xd9\x0e\xd7\xf3\x26\xdb\xe1\x85\x10\x1e\xe1\x9e\x81\xc7\xb2\xed\xf0\xd0\x22\xa6\x03\xa9\x3a\x10\xdb\xe1\xa4\x6a\xdc\xd8\xc1\x68\x7f\x95\xbe\x3d\xa6\x7f\x2d\x36\xa6\x28\xc6\x51\xab\xdc\xa3\xb6\xce\x9c\x9e\x2f\x09\xf3\x38\x95\x8e\xc4\x38\xb5\x7c\x99\xf8\x82\xfa\xae\x8e\x55\xdf\x95\xcf\x99\x23\x29\x0a\x5f\xdf\x2d\x6d\x2e\x44\x29\x5d\x1e\xd6\x76\x6a\xb0\x40\xd0\xd8\x93\x70\x97\x5a\x2e\x27\x9e\x4b\xa5\x97\x30\x8b\x51\xc7\x96\xa4\xba\x5f\x7a\xb6\x4b\xb9\x07\xb6\xef\x51\xc9\x85\x9e\x59\x0a\xdf\xa6\xb6\x30\x65\xa3\x44\x9b\x37\x5a\xc0\xd7\x46\x5b\x3b\x99\x05\x8c\xe8\x72\x48\x98\xa0\xae\x87\xc6\xe6\x1e\x95\x82\xd8\x54\x32\x49\x04\xa3\x96\xe7\x11\x9f\x4a\x29\x12\xc6\x5d\x2a\xb8\x20\xd5\x3d\x94\xd4\x42\x2b\x72\x8a\xd6\xd1\x20\x38\xf4\xd9\x96\xa8\x1e\x4c\xe7\x37\x8c\xeb\x7c\x11\x5a\x04\x47\xeb\x06\xcf\xa9\x15\x0e\x46\xe1\x60\x14\x0e\x95\xc2\xc1\x28\x7c\xc2\x31\xe9\xda\x12\x5c\xb4\x32\x93\x97\xcc\x93\xd4\xe5\x20\xb9\x43\x6d\xf4\x68\xa4\x62\x5b\x68\x22\xa8\x28\x2e\xa4\x14\xe8\xc0\x84\xdb\x82\x7a\x4c\x26\x3e\xa3\x36\xf7\x48\x75\x13\xda\x0b\xeb\x9b\xef\x52\x69\x73\x30\xb7\x4b\xe9\xda\x54\xb8\x38\xb9\xe0\xd4\xb5\x04\x38\xbe\x43\x05\x62\x96\x16\x62\xd6\xf7\xa7\xdc\x18\x67\x10\x38\x01\x71\xa8\xcb\x3d\x60\xc2\xa3\x82\x21\x06\x17\x15\x00\x35\x4f\x15\x37\x50\xdd\x0c\x37\xf5\xcd\x70\x43\x2a\xa6\x6c\xc7\xa2\x0e\xf7\xc0\xb3\x19\xe5\x68\x74\xee\x51\x07\x8d\x6e\x22\x07\xef\x21\x17\x92\xba\x0e\x37\x4b\x8d\xfd\x50\xc1\x48\xd6\x4c\x11\xc3\x14\xa9\x98\x22\x15\x53\xb5\xa2\x4c\x0e\xc0\xd8\x3a\x96\x62\xd0\x60\x8e\x66\x9a\x53\x4f\x78\x38\xdc\x7a\x8e\x04\x49\x3d\x0f\x84\x4d\x3d\x97\x27\x8e\x47\x1d\x9f\x13\x73\x0b\x7c\x07\x7d\x0a\xaa\x1b\x3a\x3a\xae\x69\xa8\x67\x79\xc4\xdc\x76\xe1\x9f\x33\xc7\xa5\x68\x59\x73\xdb\x84\x94\x8e\xeb\xbd\xc0\x9e\xbc\xfc\xfe\xe5\x0f\x2f\x7e\x9d\xbc\xfe\xe1\xe5\xaf\xaf\x5e\x7f\xff\xea\x1d\x8c\x81\x8b\x66\xa0\xd7\xef\x5e\xbe\xf9\xf5\xaf\xaf\xff\xeb\xe5\x0b\x18\x83\xd7\x0c\x73\xf9\xea\xf9\xcf\x6b\x18\x97\x3a\x1b\x7a\xd7\xab\x34\x2c\xe3\x2c\x85\x20\xba\x59\x15\xa5\x8a\xbe\xcf\xe3\xe8\xbb\xac\x2c\xb3\x45\xdf\x2c\x13\x5f\x04\x65\x70\x06\xbf\xaf\x7b\xe0\xf7\x36\xc8\xa1\xcc\xca\x20\xb9\xc4\x85\x34\x8c\xab\x15\x25\x82\xd2\x5c\x45\xab\x50\xf5\xfb\xba\x7d\x00\x71\xa9\x16\x03\x08\xce\x60\x7c\xb1\x87\x04\xbf\xb9\x2a\x57\x79\x6a\x70\xc1\x33\x0d\x4c\x13\x95\xce\xca\xf9\x0e\xe8\xe3\x00\xac\xb3\x9d\x1a\xe4\xc0\x10\xfd\xcf\x38\x2a\xe7\x30\x86\x3f\xf6\xbb\x7f\xe8\xc2\x33\xbd\xb6\x2f\x5f\x64\x8b\xd7\xd1\x19\xbd\xc3\xb6\x7e\x43\xcf\x38\x55\x97\xd9\x2a\x2d\x61\x0c\xfd\x2d\x41\xfe\xd2\xa0\xb0\x67\xdb\xc2\x19\xde\xe0\x2f\x87\xca\x3f\x83\xe1\x36\x43\x9d\x06\x29\xdf\x04\xe5\x9c\x86\x2a\x4e\xfa\x6b\xfa\x67\xf0\x97\x43\x63\xaf\xfb\x3e\x36\xd8\x69\xa6\xca\x97\x5a\xc4\x1f\x97\xf8\xfc\x4e\x2d\x96\x49\x50\xaa\x7e\x1c\x0d\x40\x6f\x55\x0c\xb6\xf8\x1d\x40\x12\x4c\x55\x52\x0c\xa0\x50\x79\xac\x8a\x01\x04\xf7\x71\x31\xc1\xba\x9f\xb3\x32\x28\x55\x93\x61\x4d\xf7\xef\xf3\x6c\xb5\x7c\x13\x2c\x61\x0c\xbf\x3f\xb6\xc0\x34\x36\x6f\x69\xeb\x3a\xcb\x5f\x06\xe1\xbc\xdf\x4f\x83\x85\x6a\xf1\x00\xa4\x38\x43\x5a\x3f\x04\x0b\x85\x23\x58\xaf\xb3\x07\xa1\x09\x2e\x71\xd7\x03\xc6\x80\x98\x68\xb1\x4c\xe2\xb2\xdf\xfb\xb5\xb7\x6b\x59\xbc\xe2\x6b\xe8\x6b\xd8\xda\x54\x17\x60\xed\x0b\x59\x7f\xb6\xc9\xea\x3e\x57\xd6\x87\xce\x1e\x0c\x3c\x82\x4a\x0a\x75\x02\x06\xe4\xec\xb0\xf7\x41\x0d\x32\xf8\xcd\xae\x8a\xe9\x3c\x28\x7e\xbc\x4b\x7f\xca\xb3\xa5\xca\xcb\x87\xfe\x1a\xeb\x59\x1b\xe7\xbb\xfd\xaf\xd6\x1d\x3e\xc0\x18\x7e\x9c\xde\xa8\xb0\xa4\x1f\xd5\x43\xd1\xdf\x85\x3b\xab\x75\xf2\xa7\x0a\x01\x4e\x1a\x8a\xaa\xf2\x04\xd6\xd7\x46\xbf\x4a\x2b\x5a\x6b\xc2\x3b\xb0\x8f\x87\x01\x97\xaa\xbb\xc9\xda\x2f\x76\xf3\xc5\x22\x58\x1e\x75\x90\x2a\x78\x9a\x15\x81\xfd\x06\x9d\x86\x06\x88\xc3\x2c\x1d\x6d\xcb\x79\xb5\xa7\xb4\x3d\x69\x3e\x34\x98\xfe\x29\xa9\x16\x0f\x66\xfe\xf5\x2e\xcb\x92\x02\x03\x65\x07\x02\xbf\x35\xc4\xf3\x24\x19\x35\x34\xe3\xb7\x98\x67\x77\x23\x28\xf3\x55\x8b\x20\x3a\xa8\x47\xd0\x33\x53\x6d\x08\x92\xa4\xd7\x0c\x68\x24\xde\x99\x91\x37\x03\x66\x69\x98\xc4\xe1\xc7\xd1\x26\xa9\xf4\xdb\x1c\x6d\x23\x29\x26\x48\x4c\x97\xd5\x9e\x23\x8d\xd3\xb8\xec\x47\x59\xb8\x5a\xa8\xb4\xa4\x98\x95\x12\x85\xc5\xef\x1e\x5e\x47\xfd\x38\x3a\x3b\xeb\x34\xa0\x5a\xe3\xcb\x74\xf2\x82\x71\x8d\x18\x11\x98\x84\xb6\x97\xad\xb7\x2f\x64\xc4\x88\xa7\xa2\xc3\xbc\xb3\xfd\x31\xe8\xa9\x31\xf1\x95\xf5\x81\x46\x3b\xe9\x08\x87\x99\x16\x6f\xdb\xbe\x6a\x5a\x57\x08\x4f\x6b\x9f\x47\x4b\xb5\x76\xdb\x73\x92\xa3\x3c\x6d\x89\x52\x17\x5b\x3b\xd7\x6a\x2a\xd6\x6a\x32\xe8\x9a\xc9\x1d\x2a\xe6\xf1\xd0\x13\x16\x0f\xbf\xa4\x5f\xcd\x39\x57\xe9\x89\xee\xb9\xb7\x64\xf9\xb7\x83\x7e\x7d\x07\xbd\x0e\x92\xe2\x5f\xd0\x43\x3b\xed\xed\xd7\x59\x0e\xfd\xf5\x44\x01\xe2\x14\x76\x73\xf9\xd9\x91\xcc\xab\x73\xf3\x55\xaf\x7e\xd4\xe9\xbf\x07\xcf\xcc\xd0\xfd\x01\xc6\x5f\xe6\xf8\x1a\xcb\x00\x86\xc3\x3a\x3d\xaf\x51\x7f\xde\xa0\xa4\xbb\x7e\xf8\x70\x72\x58\x2c\x83\x7c\x11\x0c\x40\x0d\xf4\xc4\xe8\xa9\x20\xd1\xd8\xeb\x49\x54\xae\x96\x49\x10\xaa\xfe\x9e\x6a\x06\xd0\xeb\x0d\x80\x9d\xfd\x3b\xda\xf6\xa3\x0d\x67\x6e\x9b\x49\xc3\x76\xc8\x55\x13\xa1\x63\xea\xaf\x3f\xcd\x21\x7b\x74\x4c\x79\x72\x16\xfa\x34\xfe\xe3\x29\xe1\x30\xe0\xfe\xd5\x13\x06\xfa\x54\x98\x2d\x16\x59\xfa\xe4\x04\x2d\x98\xc5\xe1\xbb\x87\xa5\x6a\x1b\x01\x4b\xdd\x76\xd5\xc3\x25\x5b\x6f\x00\xbd\x69\x90\xe3\xad\x28\x83\xf0\x23\x16\xca\x38\x51\x51\xaf\x61\xf2\x78\x18\xc3\xe8\x75\x7f\xcf\xb2\xc5\x08\x7e\x6f\x18\x8f\x73\x55\x94\x59\xae\x9a\x1b\xb1\xeb\xfb\x58\xdd\x35\xb7\x16\xc1\xad\x7a\x5e\xbc\x5e\x04\x33\xdd\xfd\x88\x62\xaa\xc5\x41\x50\x14\xf1\x2c\xed\xd7\x91\xaf\x73\xe4\xe0\x50\x63\x67\xdf\x76\x4e\x9a\x89\x57\xc9\xb0\x45\x81\xea\xbe\x1c\x55\x4b\xd3\x56\x80\xb7\xe5\x43\x3b\x06\xbc\xae\xb3\xb4\x7c\x1b\xff\xa6\x46\xc0\x64\x23\xd0\xe3\x29\x16\x28\xb3\x2c\x29\xe3\x65\x2b\xab\x79\x3c\x9b\xa9\x7c\x04\x3d\x5c\x26\xb7\xcc\x63\xb0\xe9\xa7\x2c\x4e\x4b\x95\xb7\xe1\xd9\xf8\x4d\x2f\xcc\xb3\xa2\x0d\x13\x5e\x7a\x7d\x7e\x0c\x0f\x5e\xd3\x20\xfc\x88\x19\x26\x8d\x2e\xb3\x24\x43\xfe\xfe\x20\x03\xd7\xf7\x9c\x5e\xa7\xa5\xc7\x9e\xd9\x8f\xa8\xe4\x73\x2c\xc0\x3f\x05\xf9\x32\x2b\x62\x8c\xeb\x9d\x61\x0b\xf5\x77\x2c\x5d\x56\xae\xa6\xe1\x3e\x85\xd8\x75\x96\x2f\x82\x52\x9b\x66\x67\x90\x0c\x16\xc5\x31\x72\x26\x5f\xa4\xa5\xd2\x3b\x42\x06\x1e\xf3\x19\xda\xfa\x7d\x90\xac\x14\x3c\x83\xde\xf9\x34\x1f\x5e\xb4\x6b\xdc\xf4\x5a\x8f\x2a\x38\x3e\x3c\x3d\xa6\x20\xe1\xb8\xda\xba\x3f\x2f\x6e\x67\x70\x1b\xab\xbb\xef\xb2\xfb\x71\x17\xb7\x07\x99\xc5\x85\xfe\xe9\xc2\xad\xca\x8b\x38\x4b\xc7\x5d\x46\x59\x17\xee\x17\x49\x5a\x98\x23\x28\xa3\xe1\xf0\xee\xee\x8e\xde\xd9\x34\xcb\x67\x43\x6e\x59\xd6\xb0\xb8\x9d\x75\x41\x6f\x7d\x8d\xbb\x8c\x77\x61\xae\xe2\xd9\xbc\xd4\xe5\x8b\x73\x7c\x41\x00\x45\x99\x67\x1f\xd5\xb8\xdb\xab\xf7\xdc\x42\xf4\x2b\x14\xb2\x0b\xd7\x71\x92\x34\xb7\x44\xa6\xfa\xa4\x35\xb5\xee\x6b\x36\x9d\x70\x7f\xe4\xc3\x87\x0f\x9b\x09\x47\xf5\x8e\x02\xb3\x67\xef\x4c\xa3\x1e\x5e\x9c\x0f\x67\x78\x76\xe5\x76\x76\x44\xc3\xd5\xb6\xa6\xb6\xd2\xb3\xb1\x9e\x4c\x61\x6f\x58\xf3\xba\xa1\x87\xf5\xa3\x4d\xc3\xed\x89\x36\x7c\xdc\xcb\x78\x0d\x2e\x59\x31\xf0\x6d\xe7\xb4\xa8\x6b\xf0\x53\xa3\xb3\xb6\x50\x9b\xea\xbd\xd7\x11\x58\xcd\x0e\x8e\x03\xc1\x68\x77\x3b\xa5\x19\xb0\x1e\x7f\xdf\x64\x11\x2e\xd0\x16\x2b\x4c\x7c\x89\xea\x9d\xc2\x21\xa6\xc9\x69\x76\xdf\xc6\xe2\xb5\x0a\xca\x15\x8e\x54\x3b\xe3\xc7\x29\x88\x67\x79\xdc\x2a\x78\xa2\xae\xcb\x11\xf4\xec\x3f\xb5\x64\xcb\x1c\x9d\x78\x04\x3d\xd1\x06\x50\x6b\xee\xf8\x4e\x76\x73\x5f\xb4\x6a\x10\xa7\x7a\x67\xf4\xd8\xb4\x7f\x9a\xe5\x91\xca\xeb\x2c\x9c\xab\xe8\x24\x7d\xde\x3f\xbf\x8f\x8b\xd1\xde\x41\xab\xfa\x7a\x7a\x08\x09\x4a\x35\xcb\xf2\x87\x16\xb1\xf1\x3b\xc5\xb1\x21\xc8\x1f\xbe\x0f\x96\x23\x33\xd9\x6b\x87\x35\x1e\x54\xed\x0b\xb7\x42\xe9\x2d\xd6\x49\x9c\x1e\x1d\x12\x4e\x59\x28\xd5\x1f\x9c\x3f\x3d\x39\xc6\xd4\x97\x4e\x47\x23\xe8\xe6\xb3\x69\xd0\xe7\x82\x0d\x80\xdb\x5e\xf5\xc3\xce\xba\x47\xfb\x37\x8f\x7c\x2d\x96\xa9\xaf\xf5\xb6\xf8\x53\xdc\xe5\x7a\xdb\x7c\xb4\xbf\x8f\xde\xf9\x34\x6e\x0e\x6b\x1b\x96\x7c\x0f\x5f\xe4\x35\x3a\xe5\xf5\xfe\x9f\x19\xb8\x73\x5a\x6d\x83\xb2\xcd\xd0\xd1\x69\xee\xb7\x29\xad\x27\x15\x33\x55\xea\xe5\xcb\x5f\xf3\x6c\x71\xf9\xf6\xfd\xf6\xeb\x97\xa8\xe5\x85\x99\x09\x39\x3c\x70\xb9\xbb\x5a\xc0\x36\x43\xbe\xb9\x6d\x93\xbc\x9a\xdb\xcb\x60\x9a\xe0\x2b\x8c\xee\xae\xda\xb0\x29\x57\xc9\xf3\x3c\x87\xb1\xe6\xa9\x7a\x6d\xd2\xfd\x9f\xb4\xbb\xbb\xc6\xc2\x85\xed\x37\x7f\xa4\x71\xf1\x72\xb1\x2c\x1f\xcc\x12\xa1\x6f\xba\x9e\xc1\x9f\xff\x5c\x61\xa9\xde\x13\xc0\x05\xb0\xa6\xa9\xd4\x7a\x8f\x26\x86\x31\x58\xdf\x42\x0c\xe7\xbb\x1d\xbf\x85\xf8\xd9\xb3\xa6\x9e\x35\xb3\xda\x63\x71\x1b\xdd\x74\xbb\x8a\x3f\x34\x0f\xb2\x46\xde\x67\x63\xe8\x9e\x97\xf9\x45\xb3\xaf\x34\x8a\x64\x08\xd0\x32\x8f\x17\xfd\xb3\xd6\x77\x2b\x35\x3b\xd9\xf4\xc6\xe8\x6e\xa7\x5b\xad\xc4\x41\xf7\xc8\x54\x61\xad\x8b\x1b\xa3\x8b\x1b\x38\xaf\xd0\xad\x75\x71\xd3\xae\x8b\xfa\x83\x32\xc4\xb8\xb9\x60\x3d\x05\x59\x43\xdf\x9c\x0c\xbd\x99\x87\x54\x7e\x65\xd8\x7b\xb2\xdf\xc9\xfb\x10\x9b\x98\xba\xba\x01\x02\xac\x7d\xa7\xad\xe9\x83\xfb\x16\xa3\x4a\x65\x57\x37\x0d\xe1\xda\x76\xa1\x9f\x8f\xe0\xea\x13\x7a\x54\xe9\x12\xb3\xd7\x91\x6c\xb9\x7f\x15\x0f\x8b\x69\x96\x98\x05\x29\xef\x3c\x09\xbe\x93\x43\x3e\x0f\xe2\x64\xcd\x7f\x86\x23\xe8\xc4\x74\x15\xd7\x76\x5a\xeb\xfd\x1f\xe8\x0e\x14\x2d\xf5\x59\x24\x3b\x9f\xaf\xc3\x4f\x53\xcd\xa7\xc5\xdf\x7e\x66\x9a\x5f\xc4\x69\xa4\xee\xcf\x87\xe5\xbc\x25\x47\x7d\xb6\x22\x77\xc9\xe0\x99\x87\x18\x9e\x41\xf7\x54\x52\x9d\xcf\x6f\xfd\x34\x95\x1c\xf2\xb9\x36\xf3\x89\xfc\x9e\xac\x96\x6d\x52\x51\x23\xa9\xe8\x29\x52\x9d\x4f\x6b\x79\xec\x3c\xc1\xc7\xb0\x71\x70\x7a\xec\xb4\x3f\x6d\x12\x32\x2d\xe6\xf1\x75\xb9\xb7\xf7\x5d\x6d\x52\x5c\xc7\x33\x18\x7f\xe9\x31\x10\xe1\x9c\x75\x1a\x16\xb7\x87\x8a\x36\x04\x0f\x13\xa3\x16\xb4\x45\x96\x86\xe9\xd2\x4d\xf1\xb3\x0a\xa2\xbf\xc6\x89\x2a\xfa\xd7\xf8\xbb\xef\x3f\xe8\x59\xba\xa1\x1a\x21\x9b\xfc\x0b\x15\x80\x30\x30\xc6\x4d\x0a\x55\x5c\x59\x0d\x33\x04\x04\xca\x55\x10\x29\x1c\xba\x53\x75\x07\x48\x14\x89\xab\xbc\xdf\x30\x64\x23\xdd\x21\xee\x7f\x3d\x1b\xd2\x52\x15\xa5\xe6\x8e\xe2\xa0\xd0\x3a\x41\x30\xd8\x69\x96\x26\x59\x80\x2f\x1e\x4e\x7b\x49\xb9\x65\x2c\x18\x1f\x4c\x1f\x37\x87\x96\x06\x5a\x36\xfd\x66\x61\x00\xe5\x3c\x2e\x68\xae\x8a\x55\x52\x9e\x75\x1a\x90\x6e\x30\xd3\x30\x51\x41\x7e\xe4\x6d\x49\xb8\xb7\xd9\xbe\xc5\x0f\x35\x46\x6e\xef\x8b\x47\xab\x96\x2a\xbf\x26\x91\x2a\x83\x38\x21\xda\xf8\xdd\x33\x8a\x7f\x5e\xd5\xef\x9e\x97\xd3\x2c\x7a\xb8\x58\x9f\xbd\xaa\x90\x56\xa1\xa0\x23\xc1\x00\x34\x13\x78\xec\xec\x55\x6c\x2b\x19\x75\xfd\xbc\x78\xa7\xee\x8d\x61\x1a\x0c\x78\x34\x45\x04\x89\xca\xcb\x7e\xef\x97\xb4\x58\x2d\x97\x59\x8e\x2f\x8a\x10\x4d\xc3\xb9\xa1\xc7\x4e\xf3\x93\x29\xed\xff\x55\x15\xae\x63\x01\x9d\x64\xdc\x45\xdf\x19\x86\x45\x51\xfd\x11\x19\x7e\x51\xda\x3d\x86\x70\xfb\x9a\x14\x7a\xba\xc0\xc4\xf2\x7e\x57\x0c\xdd\x78\x1d\x2c\xe2\xe4\x61\x04\xbd\xb7\x6a\x96\x29\xf8\xe5\x75\x6f\x00\xef\x82\x79\x86\xaf\xf7\xbe\x57\xa9\xba\x0d\x06\xf0\x5e\xe5\x51\x90\x06\x03\x28\x82\xb4\x20\x38\x9e\x5e\xef\x62\x5a\x06\x51\x14\xa7\xb3\x11\xd8\xd6\x36\x91\xad\x73\x64\xc6\x2a\xbb\xcc\x2d\x82\x7c\x16\xa7\xa4\xcc\x96\x23\xe0\x3b\x1d\x37\x5b\x1e\x24\xcc\x92\x24\x58\x16\x6a\x04\x75\xe9\x08\xfe\x72\x3e\xd8\xaf\x89\xf6\x88\x1a\xb4\x23\x60\xcb\x7b\x28\xb2\x24\x8e\xe0\x0f\x91\x52\x5c\xc9\x5d\xea\xa8\x5e\x12\x24\xf1\x2c\x1d\x41\xa8\x70\xd3\xbd\x45\x62\xca\x9d\x5c\x2d\x1a\x79\xa2\xda\x77\x75\x56\x3c\x62\x16\xb9\x2f\xb8\x36\xcb\x9d\xde\x3b\x1d\xc1\x34\x4b\xa2\xdd\xe6\x28\x2e\x96\x49\xf0\x30\x82\x38\xc5\xc9\x23\x99\x26\x59\xf8\xb1\x91\xfe\x4e\xec\x84\x41\x7a\x1b\x14\x7b\x7c\xcc\x2b\x2a\x8e\xd5\x66\xb7\xc3\xf8\x3b\x22\x0a\x5f\xde\x1f\x53\x04\xf6\x26\x91\x32\x2e\x8d\xf9\x68\xdb\x5c\xad\x30\x6d\x06\xb4\xda\x0d\x86\x5b\x7a\x6d\xe6\xda\xb7\x56\x15\x66\x18\x59\x17\x9d\xf3\xa1\xf9\x8b\xcd\xce\xb9\x8e\xa5\x30\x09\x8a\x62\xdc\xdd\x56\xc1\x32\x98\xa9\xfa\xef\x36\xa3\xf8\x76\x07\x04\x43\x7c\x2b\x1c\x0f\xda\xb5\x27\x74\x2f\x70\xa0\x85\xb7\xd9\x2a\x0f\xd5\xe8\x7c\x18\xc5\xb7\x5b\x5d\xe2\x74\xb9\x2a\xab\x08\xd7\xe8\x20\x4b\xc3\x79\x90\xce\xd4\xb8\xbb\x3d\x96\xe9\x04\x8d\x00\xc5\x59\x17\x86\x15\x3f\x1b\x5c\x07\xa4\xab\x7d\x46\x95\xef\xf3\x17\x47\xbb\xf2\x19\x37\xe9\x5e\xec\x33\xa6\x0d\x73\x00\xad\x6b\x11\x58\x17\x0e\xd9\x98\xe6\x6b\xe6\xe6\x5b\x45\xfb\xe2\x3b\x95\x86\xf3\x45\x90\x7f\x84\x17\x9a\x6e\x71\x3e\x9c\xdb\x55\xb3\xc6\xb5\xab\xb8\x7d\xaf\xd8\x96\xc2\x8c\x02\xeb\x67\xfc\xe2\xfa\x7c\xa7\x02\xbf\x38\x17\xfc\x29\xcf\x70\x45\x0e\xb8\x55\xaf\xa7\x81\x0d\x50\xd1\xc5\xdf\xd2\xa0\x8c\x6f\x95\x9e\xbc\xed\x00\x9c\x0f\xcb\xfc\x44\x4a\x1b\x01\x4f\xa2\x85\x13\xa4\xdb\x38\x54\x10\xe6\x2a\x40\x09\xbf\x88\xf8\x8b\x8d\xa6\xda\x29\xbf\x51\x41\xb1\xca\x15\x7c\x6c\xe1\x00\xf0\x78\x6f\x1a\x3e\x9c\xc4\xc9\x37\x84\x9c\xa2\x8b\xbf\xa9\x87\x76\x86\x5a\x08\x01\x21\x9b\xda\x7a\x4c\xaf\x3c\x6d\xdb\xed\x5a\x7d\xed\x12\x27\x07\x71\x3a\x83\x77\xf1\xf2\x9f\xe4\x67\xe6\xf5\x04\x6c\x45\x7a\xbb\xd8\x97\xf3\x2c\x2b\x14\x04\x69\x56\xce\x55\x0e\x97\x6f\xdf\xeb\x99\x18\x5c\xe7\xd9\x02\x92\x2c\x0c\x12\x28\x33\x98\x2a\xc8\x55\x1a\xa9\x5c\x45\x78\x0e\xa9\x9c\x2b\xc0\x54\x44\x4f\xb2\x4e\x1b\x9b\xfa\x15\x63\x3b\x67\xaf\xb2\x5b\x95\x83\xce\xd3\xca\x4c\xb1\x20\xc8\x55\x80\xec\x14\x4a\x81\xc9\x01\x2a\x82\x85\x2a\xf3\x38\xac\xf6\xab\x60\x85\x5c\xea\x2e\x05\x1e\x71\xbe\xd7\x5b\xd6\x5f\xc6\xa7\x79\xe7\x74\x44\x85\x78\xac\xb4\x66\xd4\xac\x66\x34\x93\xf3\xec\x6e\x38\x8f\x23\xa5\xb9\xa9\xb8\xc4\x31\x73\x38\xc5\xfd\xc2\x2d\xb9\xbe\x8c\xbd\x77\xf5\x6b\xab\xca\xec\xcf\x93\xe4\x04\x5e\xb7\x0f\xd6\x9a\x77\x8a\xd9\xb5\xe6\xa8\x7a\x0b\x56\x4b\x80\x47\x1b\xeb\xa6\x2d\x19\x0a\x14\xa2\xf8\x47\x48\xf1\x4b\x5a\x7c\x92\x1c\xab\xf4\x04\x49\xb4\x19\xfe\xd9\x92\x54\xf6\xd0\xaf\x89\x4f\x90\x24\x48\x1f\xa0\x87\xe7\x1d\x96\xc7\x0d\xd2\xcc\xff\xc6\xef\xcb\x20\x9f\xa9\x72\x73\x68\xb0\x9c\x37\xbf\xa7\xa9\x42\x2a\x8d\x8c\x76\x74\xfc\x17\x5f\x4b\xf4\xbb\xb8\x0c\xe7\xa0\x73\x1f\xe0\x39\xa3\x76\xf9\x2b\x50\xc3\x0d\x4e\x3d\x0a\x98\xaa\xf2\x4e\xa9\x54\x8b\x07\xe7\xe4\x02\xa6\x41\x3e\x80\xa2\x0c\x3e\xaa\x08\xcb\xba\x6e\x95\xea\xc3\x48\xa6\x06\x57\xff\x89\x8a\x36\x3d\x56\xe9\xa6\xe2\x80\xec\xfe\x15\xa4\x11\xa8\x32\xa4\x87\xfc\x7d\x15\x65\xe8\x3c\x8c\x27\xa0\xda\x95\xf0\x32\xc5\xa1\x44\xc7\x5c\xe5\xcc\x50\x2c\x55\x18\x5f\xc7\xe1\x3a\xe9\x4d\x15\xfc\x96\x65\x0b\x9d\x81\x87\xd9\xea\x6b\x79\xe9\xcf\xe6\x00\x56\x3b\x6f\x15\x00\x32\x87\xc7\x1d\xe3\x20\xa9\x5c\xc7\xac\x9f\x57\xb9\x19\xaf\x83\xeb\x52\xe5\x50\x68\x6b\xe2\x70\xb7\x31\xa8\x79\x37\xa4\x99\xc7\x86\x5a\xdb\x5f\x51\xb9\xef\x63\x75\xd7\x2e\xc0\x0b\xb3\x64\x81\x3c\xb8\x33\x9c\xc4\x29\x84\xab\x3c\xc7\x03\x15\x9b\x20\x58\x2d\xa3\xa0\xac\x12\x40\x25\xcc\x54\x21\xbf\x2a\x8a\x4b\x15\x7d\x25\x76\xdf\x06\xb7\x0a\x82\x02\xf4\xa9\xb5\x76\x96\x35\xd8\x2e\x8f\x78\x44\x06\x7b\x06\x29\xc4\x8b\x53\xc6\xde\x23\x93\x95\xfd\xff\x1f\x05\x77\x8f\xc2\xe2\xf6\x67\xbd\xe5\x82\xef\xd4\x7e\xff\x9d\xa2\x62\x1f\x1f\x37\x5b\x79\x1a\x66\xbd\x5f\x83\x40\x0d\x13\xf7\x43\xe8\x93\xf6\x7e\xba\xdd\xc1\x86\xfe\xd9\x21\x92\x53\x4f\xdc\x6e\x70\x9e\x0d\xa0\x9b\xe0\xea\x72\x6b\xfb\xe5\x53\xf6\x82\xbe\xea\xde\xcf\x66\x1f\xe5\x7c\xa8\x3b\x76\x3a\xe7\xc3\x79\xb9\x48\x2e\xfe\x6f\x00\x84\x2d\x5e\x81\xbb\x47\x00\x00")
 
 func templatesSingle_chartHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -180,7 +264,11 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"templates/single_chart.html": templatesSingle_chartHtml,
+	"templates/grafana_dashboard.json": templatesGrafana_dashboardJson,
+	"templates/heatmap.html":           templatesHeatmapHtml,
+	"templates/references/README.md":   templatesReferencesReadmeMd,
+	"templates/report.html":            templatesReportHtml,
+	"templates/single_chart.html":      templatesSingle_chartHtml,
 }
 
 // AssetDebug is true if the assets were built with the debug flag enabled.
@@ -228,6 +316,12 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"templates": {nil, map[string]*bintree{
+		"grafana_dashboard.json": {templatesGrafana_dashboardJson, map[string]*bintree{}},
+		"heatmap.html":           {templatesHeatmapHtml, map[string]*bintree{}},
+		"references": {nil, map[string]*bintree{
+			"README.md": {templatesReferencesReadmeMd, map[string]*bintree{}},
+		}},
+		"report.html":       {templatesReportHtml, map[string]*bintree{}},
 		"single_chart.html": {templatesSingle_chartHtml, map[string]*bintree{}},
 	}},
 }}
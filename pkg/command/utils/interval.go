@@ -0,0 +1,52 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// IntervalValue is a flag value holding a duration like 10s or 500ms, a plain number is read as seconds so that
+// flags which used to take the number of seconds keep accepting it
+type IntervalValue time.Duration
+
+// NewIntervalValue sets p to the default value and returns the flag value writing to p
+func NewIntervalValue(value time.Duration, p *time.Duration) *IntervalValue {
+	*p = value
+	return (*IntervalValue)(p)
+}
+
+func (i *IntervalValue) Set(s string) error {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		*i = IntervalValue(time.Duration(seconds * float64(time.Second)))
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("expected a duration like 10s or a number of seconds, given %s", s)
+	}
+	*i = IntervalValue(d)
+	return nil
+}
+
+func (i *IntervalValue) Type() string {
+	return "duration"
+}
+
+func (i *IntervalValue) String() string {
+	return time.Duration(*i).String()
+}
@@ -0,0 +1,38 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIntervalValue(t *testing.T) {
+	var d time.Duration
+	value := NewIntervalValue(time.Second, &d)
+	assert.Equal(t, time.Second, d)
+
+	assert.NilError(t, value.Set("20"))
+	assert.Equal(t, 20*time.Second, d)
+	assert.NilError(t, value.Set("0.5"))
+	assert.Equal(t, 500*time.Millisecond, d)
+	assert.NilError(t, value.Set("1m30s"))
+	assert.Equal(t, 90*time.Second, d)
+	assert.Equal(t, "1m30s", value.String())
+
+	assert.ErrorContains(t, value.Set("soon"), "expected a duration like 10s or a number of seconds, given soon")
+}
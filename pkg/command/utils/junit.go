@@ -0,0 +1,75 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML file as read by Jenkins and Prow
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is a suite of test cases, the number of tests and failures and the time are set when the file
+// is generated
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a test case, which failed if Failure is set
+type JUnitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure is the failure of a test case
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// GenerateJUnitFile writes the test suites as JUnit XML file
+func GenerateJUnitFile(path string, suites JUnitTestSuites, overwrite bool) error {
+	for i := range suites.Suites {
+		suite := &suites.Suites[i]
+		suite.Tests = len(suite.TestCases)
+		suite.Failures = 0
+		total := 0.0
+		for _, tc := range suite.TestCases {
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+			total += tc.Time
+		}
+		suite.Time = fmt.Sprintf("%f", total)
+	}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal junit data %s", err)
+	}
+	if err := WriteFile(path, append([]byte(xml.Header), data...), overwrite); err != nil {
+		return fmt.Errorf("failed to write junit file %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGenerateJUnitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	err := GenerateJUnitFile(path, JUnitTestSuites{Suites: []JUnitTestSuite{{
+		Name: "services",
+		TestCases: []JUnitTestCase{
+			{ClassName: "ns-1", Name: "ksvc-1", Time: 1.5},
+			{ClassName: "ns-1", Name: "ksvc-2", Failure: &JUnitFailure{Message: "not_ready", Text: "service ns-1/ksvc-2 is not_ready"}},
+		},
+	}}}, true)
+	assert.NilError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="services" tests="2" failures="1" time="1.500000">
+    <testcase classname="ns-1" name="ksvc-1" time="1.5"></testcase>
+    <testcase classname="ns-1" name="ksvc-2" time="0">
+      <failure message="not_ready">service ns-1/ksvc-2 is not_ready</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`, string(data))
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+const (
+	// MetadataKey is the key of the run metadata in the JSON files
+	MetadataKey = "metadata"
+	// csvComment starts the lines of the run metadata above the header of the CSV files
+	csvComment = '#'
+)
+
+// runMetadata are the key/values of --meta recorded in every file of the run
+var runMetadata map[string]string
+
+// SetRunMetadata sets the key/values recorded in every file written from now on, nil records none
+func SetRunMetadata(metadata map[string]string) {
+	runMetadata = metadata
+}
+
+// RunMetadata returns the key/values recorded in every file of the run
+func RunMetadata() map[string]string {
+	return runMetadata
+}
+
+// ParseMetadata parses key=value pairs like team=serving, keys must not be empty or given twice
+func ParseMetadata(pairs []string) (map[string]string, error) {
+	metadata := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("expected metadata like team=serving, given %q", pair)
+		}
+		if _, ok := metadata[key]; ok {
+			return nil, fmt.Errorf("metadata key %s is given twice", key)
+		}
+		metadata[key] = parts[1]
+	}
+	return metadata, nil
+}
+
+// metadataKeys returns the keys of the run metadata sorted, so that the files are written deterministically
+func metadataKeys() []string {
+	keys := make([]string, 0, len(runMetadata))
+	for key := range runMetadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// csvMetadata returns the comment lines of the run metadata written above the header of the CSV files, like
+// "# team=serving"
+func csvMetadata() string {
+	var b strings.Builder
+	for _, key := range metadataKeys() {
+		fmt.Fprintf(&b, "%c %s=%s\n", csvComment, key, runMetadata[key])
+	}
+	return b.String()
+}
+
+// stripCSVMetadata removes the comment lines of the run metadata from the CSV data, e.g. for the charts
+func stripCSVMetadata(data []byte) []byte {
+	for len(data) > 0 && data[0] == csvComment {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return nil
+		}
+		data = data[i+1:]
+	}
+	return data
+}
+
+// jsonMetadata adds the run metadata to the JSON object, other JSON values are returned unchanged
+func jsonMetadata(data []byte) []byte {
+	if len(runMetadata) == 0 {
+		return data
+	}
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return data
+	}
+	metadata, err := json.Marshal(runMetadata)
+	if err != nil {
+		return data
+	}
+	object[MetadataKey] = metadata
+	withMetadata, err := json.Marshal(object)
+	if err != nil {
+		return data
+	}
+	return withMetadata
+}
+
+// htmlMetadata adds the run metadata as meta tags named like kperf:team to the head of the HTML page
+func htmlMetadata(page []byte) []byte {
+	if len(runMetadata) == 0 {
+		return page
+	}
+	head := bytes.Index(page, []byte("<head>"))
+	if head < 0 {
+		return page
+	}
+	head += len("<head>")
+	var tags strings.Builder
+	for _, key := range metadataKeys() {
+		fmt.Fprintf(&tags, "\n    <meta name=\"kperf:%s\" content=\"%s\">", html.EscapeString(key), html.EscapeString(runMetadata[key]))
+	}
+	return append(append(append([]byte{}, page[:head]...), tags.String()...), page[head:]...)
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseMetadata(t *testing.T) {
+	metadata, err := ParseMetadata([]string{"team=serving", "build=1234", "note=a=b"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"team": "serving", "build": "1234", "note": "a=b"}, metadata)
+
+	_, err = ParseMetadata([]string{"team"})
+	assert.ErrorContains(t, err, "expected metadata like team=serving, given \"team\"")
+	_, err = ParseMetadata([]string{"=serving"})
+	assert.ErrorContains(t, err, "expected metadata like team=serving")
+	_, err = ParseMetadata([]string{"team=serving", "team=eventing"})
+	assert.ErrorContains(t, err, "metadata key team is given twice")
+}
+
+func TestRunMetadata(t *testing.T) {
+	SetRunMetadata(map[string]string{"team": "serving", "build": "1234"})
+	defer SetRunMetadata(nil)
+	dir := t.TempDir()
+
+	t.Run("CSV header", func(t *testing.T) {
+		csvPath := filepath.Join(dir, "metadata.csv")
+		assert.NilError(t, GenerateCSVFile(csvPath, [][]string{{"svc_name", "overall_ready"}, {"ksvc-1", "10.000000"}}, true))
+		data, err := ioutil.ReadFile(csvPath)
+		assert.NilError(t, err)
+		assert.Equal(t, "# build=1234\n# team=serving\nsvc_name,overall_ready\nksvc-1,10.000000\n", string(data))
+
+		// the metadata is skipped when the file is read back and drawn
+		rows, err := ReadCSVFile(csvPath)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, [][]string{{"svc_name", "overall_ready"}, {"ksvc-1", "10.000000"}}, rows)
+		htmlPath := filepath.Join(dir, "metadata.html")
+		assert.NilError(t, GenerateHTMLFile(csvPath, htmlPath, true))
+		page, err := ioutil.ReadFile(htmlPath)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(page), `var csvResult = "svc_name,overall_ready\nksvc-1,10.000000\n"`))
+		assert.Assert(t, strings.Contains(string(page), `<meta name="kperf:build" content="1234">`))
+		assert.Assert(t, strings.Contains(string(page), `<meta name="kperf:team" content="serving">`))
+	})
+
+	t.Run("JSON object", func(t *testing.T) {
+		jsonPath := filepath.Join(dir, "metadata.json")
+		assert.NilError(t, GenerateJSONFile([]byte(`{"Service":{"ReadyCount":1}}`), jsonPath, true))
+		data, err := ioutil.ReadFile(jsonPath)
+		assert.NilError(t, err)
+		result := struct {
+			Metadata map[string]string `json:"metadata"`
+		}{}
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.DeepEqual(t, map[string]string{"team": "serving", "build": "1234"}, result.Metadata)
+
+		// other JSON values are written unchanged
+		arrayPath := filepath.Join(dir, "array.json")
+		assert.NilError(t, GenerateJSONFile([]byte(`[1,2]`), arrayPath, true))
+		data, err = ioutil.ReadFile(arrayPath)
+		assert.NilError(t, err)
+		assert.Equal(t, "[1,2]", string(data))
+	})
+}
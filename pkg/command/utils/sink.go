@@ -0,0 +1,194 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"knative.dev/kperf/pkg"
+)
+
+// InfluxMeasurement is the measurement the per service points are written to
+const InfluxMeasurement = "kperf_ksvc_creation"
+
+// Sink stores the per service records of a measurement outside of the output location, e.g. in a database the
+// results of nightly runs are collected in
+type Sink interface {
+	// Name describes where the records are stored, e.g. "ClickHouse table kperf_ksvc_creation"
+	Name() string
+	Write(ctx context.Context, records []pkg.MeasureRecord) error
+}
+
+// ClickHouseSink inserts the records into a ClickHouse table, see InsertClickHouse
+type ClickHouseSink struct {
+	URL   string
+	Table string
+}
+
+func (s ClickHouseSink) Name() string {
+	return "ClickHouse table " + s.Table
+}
+
+func (s ClickHouseSink) Write(ctx context.Context, records []pkg.MeasureRecord) error {
+	return InsertClickHouse(ctx, s.URL, s.Table, records)
+}
+
+// BigQuerySink streams the records into a BigQuery table, see InsertBigQuery
+type BigQuerySink struct {
+	Table string
+}
+
+func (s BigQuerySink) Name() string {
+	return "BigQuery table " + s.Table
+}
+
+func (s BigQuerySink) Write(ctx context.Context, records []pkg.MeasureRecord) error {
+	return InsertBigQuery(ctx, s.Table, records)
+}
+
+// InfluxSink writes the records as points in line protocol through the InfluxDB v2 write API, which is served by
+// InfluxDB 1.8 as well. Every record is a point of InfluxMeasurement at the time of the run, tagged with the service
+// and the Knative versions, with a field per duration.
+type InfluxSink struct {
+	// URL of the InfluxDB, e.g. http://influxdb:8086
+	URL    string
+	Bucket string
+	// Org is optional for InfluxDB 1.8 and tokens scoped to a single organization
+	Org string
+	// Token is sent as API token if given
+	Token string
+}
+
+func (s InfluxSink) Name() string {
+	return "InfluxDB bucket " + s.Bucket
+}
+
+func (s InfluxSink) Write(ctx context.Context, records []pkg.MeasureRecord) error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse InfluxDB url %s", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/v2/write"
+	q := u.Query()
+	q.Set("bucket", s.Bucket)
+	if s.Org != "" {
+		q.Set("org", s.Org)
+	}
+	q.Set("precision", "s")
+	u.RawQuery = q.Encode()
+
+	body := &bytes.Buffer{}
+	if err := WriteInfluxLines(body, records); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Token "+s.Token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to write to InfluxDB %s", err)
+	}
+	defer resp.Body.Close()
+	// the write API answers 204 No Content on success
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to write to InfluxDB: %s %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// WriteInfluxLines writes one line protocol point per record with second precision
+func WriteInfluxLines(w io.Writer, records []pkg.MeasureRecord) error {
+	for _, r := range records {
+		t, err := time.Parse(time.RFC3339, r.RunTimestamp)
+		if err != nil {
+			return fmt.Errorf("expected run timestamp like 2022-01-01T00:00:00Z, given %q", r.RunTimestamp)
+		}
+		line := &strings.Builder{}
+		line.WriteString(InfluxMeasurement)
+		for _, tag := range [][2]string{
+			{"svc_namespace", r.ServiceNamespace},
+			{"svc_name", r.ServiceName},
+			{"serving_version", r.ServingVersion},
+			{"eventing_version", r.EventingVersion},
+			{"ingress_controller", r.IngressController},
+			{"ingress_version", r.IngressVersion},
+		} {
+			// empty tag values are not allowed
+			if tag[1] != "" {
+				fmt.Fprintf(line, ",%s=%s", tag[0], escapeInfluxTag(tag[1]))
+			}
+		}
+		for i, field := range influxFields(r) {
+			sep := ","
+			if i == 0 {
+				sep = " "
+			}
+			fmt.Fprintf(line, "%s%s=%s", sep, field.key, strconv.FormatFloat(field.value, 'f', -1, 64))
+		}
+		fmt.Fprintf(line, " %d\n", t.Unix())
+		if _, err := io.WriteString(w, line.String()); err != nil {
+			return fmt.Errorf("failed to write line protocol %s", err)
+		}
+	}
+	return nil
+}
+
+type influxField struct {
+	key   string
+	value float64
+}
+
+func influxFields(r pkg.MeasureRecord) []influxField {
+	return []influxField{
+		{"configuration_ready", r.ConfigurationReady},
+		{"revision_ready", r.RevisionReady},
+		{"deployment_created", r.DeploymentCreated},
+		{"pod_scheduled", r.PodScheduled},
+		{"containers_ready", r.ContainersReady},
+		{"queue_proxy_started", r.QueueProxyStarted},
+		{"user_container_started", r.UserContainerStarted},
+		{"route_ready", r.RouteReady},
+		{"kpa_active", r.KpaActive},
+		{"sks_ready", r.SksReady},
+		{"sks_activator_endpoints_populated", r.SksActivatorEndpointsPopulated},
+		{"sks_endpoints_populated", r.SksEndpointsPopulated},
+		{"ingress_ready", r.IngressReady},
+		{"ingress_config_ready", r.IngressConfigReady},
+		{"ingress_lb_ready", r.IngressLoadBalancerReady},
+		{"certificate_ready", r.CertificateReady},
+		{"overall_ready", r.OverallReady},
+	}
+}
+
+var influxTagEscaper = strings.NewReplacer(",", "\\,", "=", "\\=", " ", "\\ ")
+
+func escapeInfluxTag(value string) string {
+	return influxTagEscaper.Replace(value)
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestWriteInfluxLines(t *testing.T) {
+	records := []pkg.MeasureRecord{
+		{RunTimestamp: "2022-01-01T00:00:00Z", ServiceName: "ksvc-1", ServiceNamespace: "ns-1", IngressController: "Kourier Ingress",
+			ServingVersion: "v1.3,0", RevisionReady: 1, OverallReady: 1.5},
+	}
+	buf := &bytes.Buffer{}
+	err := WriteInfluxLines(buf, records)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(buf.String(),
+		"kperf_ksvc_creation,svc_namespace=ns-1,svc_name=ksvc-1,serving_version=v1.3\\,0,ingress_controller=Kourier\\ Ingress configuration_ready=0,revision_ready=1,"))
+	assert.Assert(t, strings.HasSuffix(buf.String(), ",overall_ready=1.5 1640995200\n"))
+
+	err = WriteInfluxLines(buf, []pkg.MeasureRecord{{ServiceName: "ksvc-1"}})
+	assert.ErrorContains(t, err, "expected run timestamp like 2022-01-01T00:00:00Z, given \"\"")
+}
+
+func TestInfluxSink(t *testing.T) {
+	t.Run("write points successfully", func(t *testing.T) {
+		var path, bucket, org, precision, auth, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			bucket, org, precision = r.URL.Query().Get("bucket"), r.URL.Query().Get("org"), r.URL.Query().Get("precision")
+			auth = r.Header.Get("Authorization")
+			data, _ := ioutil.ReadAll(r.Body)
+			body = string(data)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		sink := InfluxSink{URL: server.URL, Bucket: "kperf", Org: "knative", Token: "secret"}
+		assert.Equal(t, "InfluxDB bucket kperf", sink.Name())
+		err := sink.Write(context.Background(), testRecords)
+		assert.NilError(t, err)
+		assert.Equal(t, "/api/v2/write", path)
+		assert.Equal(t, "kperf", bucket)
+		assert.Equal(t, "knative", org)
+		assert.Equal(t, "s", precision)
+		assert.Equal(t, "Token secret", auth)
+		assert.Equal(t, 2, strings.Count(body, "\n"))
+	})
+
+	t.Run("return error if InfluxDB rejects points", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"code":"not found","message":"bucket \"kperf\" not found"}`, http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		err := InfluxSink{URL: server.URL, Bucket: "kperf"}.Write(context.Background(), testRecords)
+		assert.ErrorContains(t, err, "failed to write to InfluxDB: 404 Not Found")
+	})
+}
+
+func TestClickHouseSink(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+	}))
+	defer server.Close()
+
+	sink := ClickHouseSink{URL: server.URL, Table: "kperf"}
+	assert.Equal(t, "ClickHouse table kperf", sink.Name())
+	assert.NilError(t, sink.Write(context.Background(), testRecords))
+	assert.Equal(t, "INSERT INTO kperf FORMAT JSONEachRow", query)
+}
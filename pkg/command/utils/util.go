@@ -15,19 +15,29 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io/ioutil"
+	"math"
 	"os"
+	"path/filepath"
+	"strconv"
+
+	"sigs.k8s.io/yaml"
 )
 
-func GenerateCSVFile(path string, rows [][]string) error {
-	file, err := os.Create(path)
+func GenerateCSVFile(path string, rows [][]string, overwrite bool) error {
+	file, err := CreateFile(path, overwrite)
 	if err != nil {
 		return fmt.Errorf("failed to create csv file %s\n", err)
 	}
 	defer file.Close()
+	if _, err := file.WriteString(csvMetadata()); err != nil {
+		return fmt.Errorf("failed to write csv file %s\n", err)
+	}
 
 	csvWriter := csv.NewWriter(file)
 	csvWriter.WriteAll(rows)
@@ -35,12 +45,97 @@ func GenerateCSVFile(path string, rows [][]string) error {
 	return nil
 }
 
-func GenerateHTMLFile(sourceCSV string, targetHTML string) error {
+func ReadCSVFile(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open csv file %s", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	// the run metadata above the header is skipped
+	reader.Comment = csvComment
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv file %s", err)
+	}
+	return rows, nil
+}
+
+func GenerateHTMLFile(sourceCSV string, targetHTML string, overwrite bool) error {
+	return generateHTMLFile("templates/single_chart.html", sourceCSV, targetHTML, overwrite)
+}
+
+// GenerateRowsHTMLFile renders the rows as chart like GenerateHTMLFile, without reading them from a CSV file
+func GenerateRowsHTMLFile(rows [][]string, targetHTML string, overwrite bool) error {
+	return generateRowsHTMLFile("templates/single_chart.html", rows, targetHTML, overwrite)
+}
+
+// GenerateHeatmapHTMLFile renders the measurement CSV as a heatmap with the services on one axis and the phases
+// on the other, colored by duration
+func GenerateHeatmapHTMLFile(sourceCSV string, targetHTML string, overwrite bool) error {
+	return generateHTMLFile("templates/heatmap.html", sourceCSV, targetHTML, overwrite)
+}
+
+// GenerateRowsHeatmapHTMLFile renders the rows as heatmap like GenerateHeatmapHTMLFile, without reading them from a
+// CSV file
+func GenerateRowsHeatmapHTMLFile(rows [][]string, targetHTML string, overwrite bool) error {
+	return generateRowsHTMLFile("templates/heatmap.html", rows, targetHTML, overwrite)
+}
+
+// GenerateReportHTMLFile renders the measurement rows as an interactive report with a histogram and CDF curves per
+// phase, and with a timeline per service drawn from the raw timestamp rows if there are any. If the group rows map
+// the services to groups, the charts can be filtered by group and the groups are compared.
+func GenerateReportHTMLFile(rows [][]string, rawRows [][]string, groupRows [][]string, targetHTML string, overwrite bool) error {
+	data, err := csvString(rows)
+	if err != nil {
+		return err
+	}
+	raw, err := csvString(rawRows)
+	if err != nil {
+		return err
+	}
+	groups, err := csvString(groupRows)
+	if err != nil {
+		return err
+	}
+	return renderHTMLFile("templates/report.html", map[string]interface{}{
+		"Data":   data,
+		"Raw":    raw,
+		"Groups": groups,
+	}, targetHTML, overwrite)
+}
+
+func csvString(rows [][]string) (string, error) {
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(rows); err != nil {
+		return "", fmt.Errorf("failed to write csv data %s", err)
+	}
+	return buf.String(), nil
+}
+
+func generateHTMLFile(asset string, sourceCSV string, targetHTML string, overwrite bool) error {
 	data, err := ioutil.ReadFile(sourceCSV)
 	if err != nil {
 		return fmt.Errorf("failed to read csv file %s", err)
 	}
-	htmlTemplate, err := Asset("templates/single_chart.html")
+	return renderHTMLFile(asset, map[string]interface{}{
+		"Data": string(stripCSVMetadata(data)),
+	}, targetHTML, overwrite)
+}
+
+func generateRowsHTMLFile(asset string, rows [][]string, targetHTML string, overwrite bool) error {
+	data, err := csvString(rows)
+	if err != nil {
+		return err
+	}
+	return renderHTMLFile(asset, map[string]interface{}{
+		"Data": data,
+	}, targetHTML, overwrite)
+}
+
+func renderHTMLFile(asset string, data map[string]interface{}, targetHTML string, overwrite bool) error {
+	htmlTemplate, err := Asset(asset)
 	if err != nil {
 		return fmt.Errorf("failed to load asset: %s", err)
 	}
@@ -48,24 +143,27 @@ func GenerateHTMLFile(sourceCSV string, targetHTML string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse html template %s", err)
 	}
-	htmlFile, err := os.OpenFile(targetHTML, os.O_WRONLY|os.O_CREATE, 0644)
+	var page bytes.Buffer
+	if err := viewTemplate.Execute(&page, data); err != nil {
+		return err
+	}
+	htmlFile, err := CreateFile(targetHTML, overwrite)
 	if err != nil {
 		return fmt.Errorf("failed to open html file %s", err)
 	}
 	defer htmlFile.Close()
-	return viewTemplate.Execute(htmlFile, map[string]interface{}{
-		"Data": string(data),
-	})
+	_, err = htmlFile.Write(htmlMetadata(page.Bytes()))
+	return err
 }
 
-func GenerateJSONFile(jsonData []byte, targetJSON string) error {
-	jsonFile, err := os.OpenFile(targetJSON, os.O_WRONLY|os.O_CREATE, 0644)
+func GenerateJSONFile(jsonData []byte, targetJSON string, overwrite bool) error {
+	jsonFile, err := CreateFile(targetJSON, overwrite)
 	if err != nil {
 		return fmt.Errorf("failed to create json file %s", err)
 	}
 	defer jsonFile.Close()
 
-	_, err = jsonFile.Write(jsonData)
+	_, err = jsonFile.Write(jsonMetadata(jsonData))
 	if err != nil {
 		fmt.Println("failed to write json data", err)
 		return err
@@ -73,6 +171,49 @@ func GenerateJSONFile(jsonData []byte, targetJSON string) error {
 	return nil
 }
 
+// GenerateYAMLFile writes the JSON data with the run metadata as YAML file
+func GenerateYAMLFile(jsonData []byte, targetYAML string, overwrite bool) error {
+	data, err := yaml.JSONToYAML(jsonMetadata(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to convert json data to yaml %s", err)
+	}
+	if err := WriteFile(targetYAML, data, overwrite); err != nil {
+		return fmt.Errorf("failed to write yaml file %s", err)
+	}
+	return nil
+}
+
+// GenerateRowsNDJSONFile writes a JSON object per row keyed by the columns of the header row, values which are
+// numbers are written as numbers
+func GenerateRowsNDJSONFile(path string, rows [][]string, overwrite bool) error {
+	file, err := CreateFile(path, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to create ndjson file %s", err)
+	}
+	defer file.Close()
+	if len(rows) == 0 {
+		return nil
+	}
+	encoder := json.NewEncoder(file)
+	for _, row := range rows[1:] {
+		object := make(map[string]interface{}, len(row))
+		for i, value := range row {
+			if i >= len(rows[0]) {
+				break
+			}
+			if number, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(number, 0) && !math.IsNaN(number) {
+				object[rows[0][i]] = number
+			} else {
+				object[rows[0][i]] = value
+			}
+		}
+		if err := encoder.Encode(object); err != nil {
+			return fmt.Errorf("failed to write ndjson file %s", err)
+		}
+	}
+	return nil
+}
+
 func CheckOutputLocation(outputLocation string) (string, error) {
 	dirInfo, err := os.Stat(outputLocation)
 	if err != nil {
@@ -91,3 +232,20 @@ func CheckOutputLocation(outputLocation string) (string, error) {
 	}
 	return outputLocation, nil
 }
+
+// RunOutputLocation returns the subdirectory of the output location named by the run ID, which is created if it
+// doesn't exist, so that the results of concurrent runs don't overwrite each other. Without a run ID the output
+// location itself is returned.
+func RunOutputLocation(outputLocation, runID string) (string, error) {
+	if runID == "" {
+		return CheckOutputLocation(outputLocation)
+	}
+	if _, err := CheckOutputLocation(outputLocation); err != nil {
+		return outputLocation, err
+	}
+	runLocation := filepath.Join(outputLocation, runID)
+	if err := os.MkdirAll(runLocation, 0755); err != nil {
+		return outputLocation, fmt.Errorf("failed to create output location of run %s: %s\n", runID, err)
+	}
+	return CheckOutputLocation(runLocation)
+}
@@ -21,6 +21,7 @@ import (
 	"html/template"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -34,7 +35,7 @@ func TestGenerateCSVFile(t *testing.T) {
 	t.Run("generate CSV file successfully", func(t *testing.T) {
 		path := "/tmp/generate-csv-test.csv"
 		rows := [][]string{{"1", "2", "3"}, {"1", "2", "3"}, {"1", "2", "3"}}
-		err := GenerateCSVFile(path, rows)
+		err := GenerateCSVFile(path, rows, true)
 		assert.NilError(t, err)
 
 		file, err := os.Open(path)
@@ -51,16 +52,43 @@ func TestGenerateCSVFile(t *testing.T) {
 	t.Run("return error if path is not available", func(t *testing.T) {
 		path := "/tmp"
 		rows := [][]string{{"1", "2", "3"}, {"1", "2", "3"}, {"1", "2", "3"}}
-		err := GenerateCSVFile(path, rows)
+		err := GenerateCSVFile(path, rows, true)
 		assert.ErrorContains(t, err, "failed to create csv file open /tmp: is a directory")
 	})
 }
 
+func TestGenerateHeatmapHTMLFile(t *testing.T) {
+	targetHTML := filepath.Join(t.TempDir(), "heatmap.html")
+	err := GenerateHeatmapHTMLFile("../../../test/asset/test.csv", targetHTML, true)
+	assert.NilError(t, err)
+
+	data, err := ioutil.ReadFile(targetHTML)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(data), "var csvResult = \"1, 2, 3\\n4, 5, 6\\n7, 8, 9\\n\""))
+	assert.Assert(t, strings.Contains(string(data), "type: \"heatmap\""))
+}
+
+func TestGenerateReportHTMLFile(t *testing.T) {
+	targetHTML := filepath.Join(t.TempDir(), "report.html")
+	rows := [][]string{{"svc_name", "svc_namespace", "overall_ready"}, {"ksvc-1", "ns", "10.000000"}}
+	rawRows := [][]string{{"svc_name", "svc_namespace", "svc_created", "route_ready"},
+		{"ksvc-1", "ns", "2021-01-17 10:47:37 +0000 UTC", "2021-01-17 10:47:47 +0000 UTC"}}
+	groupRows := [][]string{{"svc_name", "svc_namespace", "group"}, {"ksvc-1", "ns", "ksvc"}}
+	err := GenerateReportHTMLFile(rows, rawRows, groupRows, targetHTML, true)
+	assert.NilError(t, err)
+
+	data, err := ioutil.ReadFile(targetHTML)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(data), "var csvResult = \"svc_name,svc_namespace,overall_ready\\nksvc-1,ns,10.000000\\n\""))
+	assert.Assert(t, strings.Contains(string(data), "var csvRaw = \"svc_name,svc_namespace,svc_created,route_ready\\nksvc-1,ns,2021-01-17 10:47:37 \\u002b0000 UTC,2021-01-17 10:47:47 \\u002b0000 UTC\\n\""))
+	assert.Assert(t, strings.Contains(string(data), "var csvGroups = \"svc_name,svc_namespace,group\\nksvc-1,ns,ksvc\\n\""))
+}
+
 func TestGenerateHTMLFile(t *testing.T) {
 	t.Run("generate HTML file successfully", func(t *testing.T) {
 		sourceCSV := "../../../test/asset/test.csv"
 		targetHTML := "/tmp/test.html"
-		err := GenerateHTMLFile(sourceCSV, targetHTML)
+		err := GenerateHTMLFile(sourceCSV, targetHTML, true)
 		assert.NilError(t, err)
 
 		file, err := os.Open(targetHTML)
@@ -83,7 +111,7 @@ func TestGenerateHTMLFile(t *testing.T) {
 	t.Run("failed to read csv file", func(t *testing.T) {
 		sourceCSV := "../../../test/asset/test1.csv"
 		targetHTML := "/tmp/test.html"
-		err := GenerateHTMLFile(sourceCSV, targetHTML)
+		err := GenerateHTMLFile(sourceCSV, targetHTML, true)
 		assert.ErrorContains(t, err, "failed to read csv file open ../../../test/asset/test1.csv")
 	})
 
@@ -94,7 +122,7 @@ func TestGenerateHTMLFile(t *testing.T) {
 		})
 		sourceCSV := "../../../test/asset/test.csv"
 		targetHTML := "/tmp/test.html"
-		err := GenerateHTMLFile(sourceCSV, targetHTML)
+		err := GenerateHTMLFile(sourceCSV, targetHTML, true)
 		assert.ErrorContains(t, err, "failed to parse html template")
 	})
 
@@ -104,7 +132,7 @@ func TestGenerateHTMLFile(t *testing.T) {
 		})
 		sourceCSV := "../../../test/asset/test.csv"
 		targetHTML := "/tmp/test.html"
-		err := GenerateHTMLFile(sourceCSV, targetHTML)
+		err := GenerateHTMLFile(sourceCSV, targetHTML, true)
 		assert.ErrorContains(t, err, "failed to load asset")
 	})
 }
@@ -121,7 +149,7 @@ func TestGenerateJSONFile(t *testing.T) {
 		}
 		data, _ := json.Marshal(cat)
 		path := "/tmp/test.json"
-		err := GenerateJSONFile(data, path)
+		err := GenerateJSONFile(data, path, true)
 		assert.NilError(t, err)
 		fileData, err := ioutil.ReadFile(path)
 		assert.NilError(t, err)
@@ -139,7 +167,7 @@ func TestGenerateJSONFile(t *testing.T) {
 		}
 		data, _ := json.Marshal(cat)
 		path := "/tmp"
-		err := GenerateJSONFile(data, path)
+		err := GenerateJSONFile(data, path, true)
 		assert.ErrorContains(t, err, "failed to create json file open /tmp: is a directory")
 	})
 }
@@ -163,3 +191,20 @@ func TestCheckOutputLocation(t *testing.T) {
 		assert.ErrorContains(t, err, "is not directory")
 	})
 }
+
+func TestRunOutputLocation(t *testing.T) {
+	dir := t.TempDir()
+	location, err := RunOutputLocation(dir, "")
+	assert.NilError(t, err)
+	assert.Equal(t, dir, location)
+
+	location, err = RunOutputLocation(dir, "demo")
+	assert.NilError(t, err)
+	assert.Equal(t, filepath.Join(dir, "demo"), location)
+	info, err := os.Stat(location)
+	assert.NilError(t, err)
+	assert.Check(t, info.IsDir())
+
+	_, err = RunOutputLocation("/tmpdbcd", "demo")
+	assert.ErrorContains(t, err, "is not existed")
+}
@@ -15,23 +15,224 @@
 package version
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/discovery"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/measure"
 )
 
 var Version string
 var BuildDate string
 var GitRevision string
 
+// servingModule is the module of the vendored Knative Serving client APIs, its version tells the Knative release
+// kperf is built against
+const servingModule = "knative.dev/serving"
+
+var (
+	// clientModules are the modules of the vendored client APIs kperf talks to the cluster with
+	clientModules = []string{servingModule, "knative.dev/networking", "knative.dev/pkg", "k8s.io/client-go"}
+
+	// apiGroupVersions are the API group versions the kperf commands use, serving.knative.dev/v1 is required by all
+	// service commands, the others only by the commands of their resources
+	apiGroupVersions = []string{
+		"serving.knative.dev/v1",
+		"networking.internal.knative.dev/v1alpha1",
+		"eventing.knative.dev/v1",
+		"messaging.knative.dev/v1",
+		"sources.knative.dev/v1beta1",
+		"messaging.knative.dev/v1beta1",
+	}
+
+	// readBuildInfo returns the modules kperf is built with
+	readBuildInfo = debug.ReadBuildInfo
+)
+
 // NewVersionCommand implements 'kperf version' command
-func NewVersionCommand() *cobra.Command {
-	return &cobra.Command{
+func NewVersionCommand(p *pkg.PerfParams) *cobra.Command {
+	client := false
+	versionCommand := &cobra.Command{
 		Use:   "version",
 		Short: "Prints the kperf version",
+		Long: `Prints the kperf version with the vendored client APIs, and the versions detected on the cluster
+
+The Knative release of the vendored client APIs is compared with the Knative Serving release running on the cluster,
+and the API group versions kperf uses are checked to be served. Please add the output to bug reports about
+measurements which differ from the expectation.
+
+For example:
+# To print the versions of kperf and the cluster of the current kubeconfig context
+kperf version
+
+# To print the versions of kperf only
+kperf version --client
+`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmd.Printf("Version:      %s\n", Version)
-			cmd.Printf("Build Date:   %s\n", BuildDate)
-			cmd.Printf("Git Revision: %s\n", GitRevision)
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Version:      %s\n", Version)
+			fmt.Fprintf(out, "Build Date:   %s\n", BuildDate)
+			fmt.Fprintf(out, "Git Revision: %s\n", GitRevision)
+			if client {
+				return nil
+			}
+			fmt.Fprintf(out, "Go Version:   %s\n", runtime.Version())
+			fmt.Fprintf(out, "Platform:     %s/%s\n", runtime.GOOS, runtime.GOARCH)
+			modules := clientModuleVersions()
+			writeClientAPIs(out, modules)
+			writeServer(cmd.Context(), out, p, knativeRelease(modules[servingModule]))
 			return nil
 		},
 	}
+	versionCommand.Flags().BoolVarP(&client, "client", "", false, "Print the kperf version only, without the client APIs and the cluster")
+	return versionCommand
+}
+
+// clientModuleVersions returns the versions of the client modules kperf is built with, taking replacements into
+// account
+func clientModuleVersions() map[string]string {
+	versions := map[string]string{}
+	info, ok := readBuildInfo()
+	if !ok {
+		return versions
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		versions[dep.Path] = dep.Version
+	}
+	return versions
+}
+
+func writeClientAPIs(out io.Writer, modules map[string]string) {
+	fmt.Fprintf(out, "\nClient APIs:\n")
+	for _, module := range clientModules {
+		v := modules[module]
+		if v == "" {
+			v = "Unknown"
+		}
+		fmt.Fprintf(out, "  %-24s %s\n", module, v)
+	}
+	if release := knativeRelease(modules[servingModule]); release != "" {
+		fmt.Fprintf(out, "  %-24s %s\n", "Knative release", release)
+	}
+}
+
+// writeServer writes the versions detected on the cluster, the API group versions kperf uses and the compatibility
+// verdict. A cluster which can't be reached is reported, but no error, so that the client versions can be printed
+// without a cluster.
+func writeServer(ctx context.Context, out io.Writer, p *pkg.PerfParams, clientRelease string) {
+	fmt.Fprintf(out, "\nServer:\n")
+	if p == nil || p.ClientSet == nil {
+		fmt.Fprintf(out, "  Unavailable: no kubeconfig found\n")
+		return
+	}
+	serverVersion, err := p.ClientSet.Discovery().ServerVersion()
+	if err != nil {
+		fmt.Fprintf(out, "  Unavailable: %s\n", err)
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := log.New(ioutil.Discard, "", 0)
+	knative := measure.GetKnativeInfo(ctx, p, logger)
+	fmt.Fprintf(out, "  %-24s %s\n", "Kubernetes", serverVersion.GitVersion)
+	fmt.Fprintf(out, "  %-24s %s\n", "Knative Serving", knative.ServingVersion)
+	fmt.Fprintf(out, "  %-24s %s\n", "Knative Eventing", knative.EventingVersion)
+	fmt.Fprintf(out, "  %-24s %s %s\n", "Ingress", knative.IngressController, knative.IngressVersion)
+
+	served := servedGroupVersions(p.ClientSet.Discovery())
+	fmt.Fprintf(out, "\nAPI Group Versions:\n")
+	for _, gv := range apiGroupVersions {
+		state := "not served"
+		if served[gv] {
+			state = "served"
+		}
+		fmt.Fprintf(out, "  %-40s %s\n", gv, state)
+	}
+	fmt.Fprintf(out, "\nCompatibility: %s\n", compatibility(clientRelease, knative.ServingVersion, served))
+}
+
+// servedGroupVersions returns the API group versions served by the cluster
+func servedGroupVersions(client discovery.DiscoveryInterface) map[string]bool {
+	served := map[string]bool{}
+	groups, err := client.ServerGroups()
+	if err != nil {
+		return served
+	}
+	for _, group := range groups.Groups {
+		for _, v := range group.Versions {
+			served[v.GroupVersion] = true
+		}
+	}
+	return served
+}
+
+// knativeRelease returns the Knative release of a version of the knative.dev/serving module, e.g. 1.3 for
+// v0.30.1-0.20220315121703-b5996a729dc5. The modules are versioned v0.(27+minor) since Knative 1.0. An empty string is
+// returned for versions which can't be mapped.
+func knativeRelease(moduleVersion string) string {
+	major, minor, ok := majorMinor(moduleVersion)
+	if !ok || major != 0 || minor < 27 {
+		return ""
+	}
+	return fmt.Sprintf("1.%d", minor-27)
+}
+
+// majorMinor returns the major and the minor version of a version like v1.3.0 or 1.3
+func majorMinor(version string) (int, int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// compatibility returns the verdict whether the vendored client APIs of the Knative release fit the Knative Serving
+// release of the cluster. Releases up to 2 minor versions apart are compatible, like the supported version skew of
+// Kubernetes.
+func compatibility(clientRelease, serverRelease string, served map[string]bool) string {
+	if !served[apiGroupVersions[0]] {
+		return fmt.Sprintf("Incompatible, the cluster doesn't serve %s", apiGroupVersions[0])
+	}
+	serverMajor, serverMinor, ok := majorMinor(serverRelease)
+	if !ok {
+		return "Unknown, the Knative Serving release of the cluster can't be detected"
+	}
+	clientMajor, clientMinor, ok := majorMinor(clientRelease)
+	if !ok {
+		return "Unknown, the Knative release of the client APIs can't be detected"
+	}
+	skew := serverMinor - clientMinor
+	if skew < 0 {
+		skew = -skew
+	}
+	switch {
+	case serverMajor == clientMajor && skew == 0:
+		return fmt.Sprintf("Compatible, the client APIs match Knative Serving %s", serverRelease)
+	case serverMajor == clientMajor && skew <= 2:
+		return fmt.Sprintf("Compatible, Knative Serving %s is within 2 minor releases of the client APIs of Knative %s", serverRelease, clientRelease)
+	default:
+		return fmt.Sprintf("Untested, Knative Serving %s is more than 2 minor releases apart from the client APIs of Knative %s, measurements may be off", serverRelease, clientRelease)
+	}
 }
@@ -16,9 +16,18 @@ package version
 
 import (
 	"fmt"
+	"runtime/debug"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kperf/pkg"
 	"knative.dev/kperf/pkg/testutil"
 )
 
@@ -37,10 +46,61 @@ func TestVersionOutput(t *testing.T) {
 	Version = fakeVersion
 	BuildDate = fakeBuildDate
 	GitRevision = fakeGitRevision
-	expectedOutput := fmt.Sprintf(versionOutputTemplate, fakeVersion, fakeBuildDate, fakeGitRevision)
+	restore := readBuildInfo
+	defer func() { readBuildInfo = restore }()
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Deps: []*debug.Module{
+			{Path: "knative.dev/serving", Version: "v0.30.1-0.20220315121703-b5996a729dc5"},
+			{Path: "knative.dev/pkg", Version: "v0.0.0-20220315095603-616f1ab878c5", Replace: &debug.Module{Path: "knative.dev/pkg", Version: "v0.0.0-20220401000000-000000000000"}},
+		}}, true
+	}
+
+	t.Run("client only", func(t *testing.T) {
+		versionCmd := NewVersionCommand(nil)
+		out, err := testutil.ExecuteCommand(versionCmd, "--client")
+		assert.NilError(t, err)
+		assert.Equal(t, fmt.Sprintf(versionOutputTemplate, fakeVersion, fakeBuildDate, fakeGitRevision), out)
+	})
+
+	t.Run("without cluster", func(t *testing.T) {
+		out, err := testutil.ExecuteCommand(NewVersionCommand(nil))
+		assert.NilError(t, err)
+		assert.Assert(t, strings.HasPrefix(out, fmt.Sprintf(versionOutputTemplate, fakeVersion, fakeBuildDate, fakeGitRevision)), out)
+		assert.Assert(t, strings.Contains(out, "knative.dev/serving      v0.30.1-0.20220315121703-b5996a729dc5\n"), out)
+		assert.Assert(t, strings.Contains(out, "knative.dev/pkg          v0.0.0-20220401000000-000000000000\n"), out)
+		assert.Assert(t, strings.Contains(out, "k8s.io/client-go         Unknown\n"), out)
+		assert.Assert(t, strings.Contains(out, "Knative release          1.3\n"), out)
+		assert.Assert(t, strings.Contains(out, "Server:\n  Unavailable: no kubeconfig found\n"), out)
+	})
+
+	t.Run("with cluster", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "knative-serving", Labels: map[string]string{"serving.knative.dev/release": "v1.4.0"}}})
+		fake := client.Discovery().(*fakediscovery.FakeDiscovery)
+		fake.FakedServerVersion = &k8sversion.Info{GitVersion: "v1.23.4"}
+		fake.Resources = []*metav1.APIResourceList{{GroupVersion: "serving.knative.dev/v1"}}
+		out, err := testutil.ExecuteCommand(NewVersionCommand(&pkg.PerfParams{ClientSet: client}))
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(out, "Kubernetes               v1.23.4\n"), out)
+		assert.Assert(t, strings.Contains(out, "Knative Serving          1.4.0\n"), out)
+		assert.Assert(t, strings.Contains(out, "serving.knative.dev/v1                   served\n"), out)
+		assert.Assert(t, strings.Contains(out, "eventing.knative.dev/v1                  not served\n"), out)
+		assert.Assert(t, strings.Contains(out, "Compatibility: Compatible, Knative Serving 1.4.0 is within 2 minor releases of the client APIs of Knative 1.3\n"), out)
+	})
+}
+
+func TestKnativeRelease(t *testing.T) {
+	assert.Equal(t, "1.3", knativeRelease("v0.30.1-0.20220315121703-b5996a729dc5"))
+	assert.Equal(t, "1.0", knativeRelease("v0.27.0"))
+	assert.Equal(t, "", knativeRelease("v0.26.0"))
+	assert.Equal(t, "", knativeRelease("(devel)"))
+}
 
-	versionCmd := NewVersionCommand()
-	out, err := testutil.ExecuteCommand(versionCmd)
-	assert.NilError(t, err)
-	assert.Equal(t, expectedOutput, out)
+func TestCompatibility(t *testing.T) {
+	served := map[string]bool{"serving.knative.dev/v1": true}
+	assert.Equal(t, "Compatible, the client APIs match Knative Serving 1.3.2", compatibility("1.3", "1.3.2", served))
+	assert.Assert(t, strings.HasPrefix(compatibility("1.3", "1.6.0", served), "Untested"))
+	assert.Assert(t, strings.HasPrefix(compatibility("1.3", "Unknown", served), "Unknown"))
+	assert.Assert(t, strings.HasPrefix(compatibility("", "1.3.0", served), "Unknown"))
+	assert.Assert(t, strings.HasPrefix(compatibility("1.3", "1.3.0", map[string]bool{}), "Incompatible"))
 }
@@ -0,0 +1,433 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/service"
+	"knative.dev/kperf/pkg/command/utils"
+	"knative.dev/kperf/pkg/measure"
+	"knative.dev/kperf/pkg/pool"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/report"
+)
+
+const (
+	WebhookOutputFilename = "webhook_latency"
+
+	// OperationCreate creates a Knative Service with dry run, admitted by the defaulting and validating webhooks
+	OperationCreate = "create"
+	// OperationUpdate changes the revision template of an existing Knative Service with dry run
+	OperationUpdate = "update"
+	// OperationBaseline creates a ConfigMap with dry run, which no Knative webhook admits, as baseline of the
+	// latency of the API server
+	OperationBaseline = "baseline"
+
+	// probeAnnotation is set in the revision template by the update requests, so that every request changes it
+	probeAnnotation = "kperf.knative.dev/webhook-probe"
+)
+
+// Operations are the operations of the requests in the order they are reported
+var Operations = []string{OperationCreate, OperationUpdate, OperationBaseline}
+
+// dryRun are the options of the requests, which are admitted but not persisted
+var dryRun = []string{metav1.DryRunAll}
+
+func NewWebhookMeasureCommand(p *pkg.PerfParams) *cobra.Command {
+	measureArgs := pkg.WebhookMeasureArgs{}
+	measureCommand := &cobra.Command{
+		Use:   "measure",
+		Short: "Measure the latency of the admission webhooks",
+		Long: `Send a stream of dry run Knative Service requests and measure their round-trip latency over time
+
+The requests are sent at --qps for --duration, each one with the next operation of --operations in turn:
+  create    creates a Knative Service named <svc-prefix>-<index> with dry run
+  update    changes the revision template of the existing Knative Service --service with dry run
+  baseline  creates a ConfigMap with dry run, which no Knative webhook admits
+Dry run requests pass the defaulting and validating webhooks like real ones but are never persisted, so the cluster
+isn't changed and the command is allowed in read-only mode. The latency of the baseline requests is the one of the
+API server alone, the difference to the Knative Service requests is the time spent in the webhooks. The percentiles
+of every operation are reported for the whole run and for every --interval of it. Requests are skipped if all
+--concurrency workers are busy, which shows that the webhooks can't keep up with the rate.
+
+For example:
+# To measure the creation of Knative Services in namespace ktest at 20 requests per second for 5 minutes
+kperf webhook measure --namespace ktest --qps 20 --duration 5m
+
+# To compare the creations and updates of Knative Services with the baseline of the API server
+kperf webhook measure --namespace ktest --service ksvc-0 --operations create,update,baseline
+`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if measureArgs.Namespace == "" {
+				return fmt.Errorf("'webhook measure' requires --namespace")
+			}
+			if len(measureArgs.Operations) == 0 {
+				return fmt.Errorf("at least 1 operation is required")
+			}
+			for _, operation := range measureArgs.Operations {
+				if !isOperation(operation) {
+					return fmt.Errorf("unknown operation %s, expected one of %v", operation, Operations)
+				}
+				if operation == OperationUpdate && measureArgs.Service == "" {
+					return fmt.Errorf("operation update requires --service")
+				}
+			}
+			if measureArgs.QPS < 1 || measureArgs.Concurrency < 1 {
+				return fmt.Errorf("--qps and --concurrency must be at least 1")
+			}
+			if measureArgs.Duration <= 0 || measureArgs.Interval <= 0 {
+				return fmt.Errorf("--duration and --interval must be positive")
+			}
+			return pkg.ValidateRunID(measureArgs.RunID)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MeasureWebhooks(p, measureArgs)
+		},
+	}
+
+	measureCommand.Flags().StringVarP(&measureArgs.Namespace, "namespace", "", "", "Namespace the dry run requests are sent to")
+	measureCommand.Flags().StringVarP(&measureArgs.Service, "service", "", "", "Existing Knative Service changed by the update requests")
+	measureCommand.Flags().StringVarP(&measureArgs.SvcPrefix, "svc-prefix", "", "kperf-webhook", "Name prefix of the Knative Services and ConfigMaps created with dry run")
+	measureCommand.Flags().StringSliceVarP(&measureArgs.Operations, "operations", "", []string{OperationCreate}, "Comma separated operations of the requests sent in turn, create, update or baseline")
+	measureCommand.Flags().IntVarP(&measureArgs.QPS, "qps", "", 10, "Requests sent per second")
+	measureCommand.Flags().DurationVarP(&measureArgs.Duration, "duration", "", time.Minute, "Duration to send requests")
+	measureCommand.Flags().DurationVarP(&measureArgs.Interval, "interval", "", 10*time.Second, "Interval the latencies over time are reported for")
+	measureCommand.Flags().IntVarP(&measureArgs.Concurrency, "concurrency", "c", 10, "Number of requests in flight at a time")
+	measureCommand.Flags().DurationVarP(&measureArgs.Timeout, "timeout", "", 30*time.Second, "Timeout of a single request")
+	measureCommand.Flags().BoolVarP(&measureArgs.Verbose, "verbose", "v", false, "Print the failed requests")
+	measureCommand.Flags().StringVarP(&measureArgs.Output, "output", "o", ".", "Measure result location")
+	report.AddFlag(measureCommand.Flags(), &measureArgs.OutputFormats)
+	measureCommand.Flags().StringVarP(&measureArgs.RunID, "run-id", "", "", "ID of the run, the results are written to the subdirectory of the output location named by it")
+	return measureCommand
+}
+
+func isOperation(operation string) bool {
+	for _, o := range Operations {
+		if o == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// sample is a request of the stream, offset is when it was scheduled since the start of the run
+type sample struct {
+	operation string
+	offset    time.Duration
+	latency   time.Duration
+	err       error
+	skipped   bool
+}
+
+// token schedules the request with the index
+type token struct {
+	index  int
+	offset time.Duration
+}
+
+// MeasureWebhooks sends the stream of dry run requests and writes the latencies of every operation over time
+func MeasureWebhooks(params *pkg.PerfParams, inputs pkg.WebhookMeasureArgs) error {
+	ctx, cancel := utils.InterruptContext(context.Background())
+	defer cancel()
+	ksvcClient, err := params.NewServingClient()
+	if err != nil {
+		return err
+	}
+	if inputs.Service != "" {
+		if _, err := ksvcClient.Services(inputs.Namespace).Get(ctx, inputs.Service, metav1.GetOptions{}); err != nil {
+			return fmt.Errorf("failed to get Knative Service %s in namespace %s: %w", inputs.Service, inputs.Namespace, err)
+		}
+	}
+
+	fmt.Printf("Sending %d dry run request(s) per second for %s to namespace %s\n", inputs.QPS, inputs.Duration, inputs.Namespace)
+	var m sync.Mutex
+	samples := []sample{}
+	record := func(s sample) {
+		m.Lock()
+		defer m.Unlock()
+		samples = append(samples, s)
+	}
+	operation := func(index int) string {
+		return inputs.Operations[index%len(inputs.Operations)]
+	}
+
+	start := time.Now()
+	loadCtx, stop := context.WithTimeout(ctx, inputs.Duration)
+	defer stop()
+	tokens := make(chan token, inputs.Concurrency)
+	go func() {
+		defer close(tokens)
+		ticker := time.NewTicker(time.Second / time.Duration(inputs.QPS))
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-loadCtx.Done():
+				return
+			case now := <-ticker.C:
+				t := token{index: i, offset: now.Sub(start)}
+				select {
+				case tokens <- t:
+				default:
+					record(sample{operation: operation(i), offset: t.offset, skipped: true})
+				}
+			}
+		}
+	}()
+	pool.ForEach(ctx, inputs.Concurrency, inputs.Concurrency, func(ctx context.Context, _ int) {
+		for t := range tokens {
+			s := sample{operation: operation(t.index), offset: t.offset}
+			requestStart := time.Now()
+			s.err = sendRequest(ctx, params, ksvcClient, inputs, s.operation, t.index)
+			s.latency = time.Since(requestStart)
+			if ctx.Err() != nil {
+				// requests interrupted by Ctrl-C are not counted
+				return
+			}
+			if s.err != nil && inputs.Verbose {
+				fmt.Printf("[Verbose] %s request %d failed after %s: %s\n", s.operation, t.index, s.latency, s.err)
+			}
+			record(s)
+		}
+	})
+
+	result := pkg.WebhookMeasureResult{
+		QPS:         inputs.QPS,
+		Duration:    time.Since(start).Seconds(),
+		KnativeInfo: measure.GetKnativeInfo(context.Background(), params, measure.DefaultLogger),
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].offset < samples[j].offset
+	})
+	result.Operations, result.Windows = latencies(samples, inputs.Operations, inputs.Interval)
+
+	fmt.Printf("-------- Measurement --------\n")
+	writeLatencies(os.Stdout, result, inputs.Interval, render.NewOptions(os.Stdout, false, false))
+	outputLocation, err := utils.RunOutputLocation(inputs.Output, inputs.RunID)
+	if err != nil {
+		fmt.Printf("failed to check measure output location: %s\n", err)
+	}
+	if err := report.Write(os.Stdout, outputLocation, time.Now().Format(utils.DateFormatString), params.Output, report.Report{
+		Name:    WebhookOutputFilename,
+		Rows:    windowRows(result),
+		RawRows: rawRows(samples),
+		Result:  result,
+		Formats: inputs.OutputFormats,
+	}); err != nil {
+		return err
+	}
+	return failedRequests(samples)
+}
+
+// sendRequest sends the dry run request of the operation with the index
+func sendRequest(ctx context.Context, params *pkg.PerfParams, ksvcClient servingv1client.ServingV1Interface, inputs pkg.WebhookMeasureArgs, operation string, index int) error {
+	ctx, cancel := context.WithTimeout(ctx, inputs.Timeout)
+	defer cancel()
+	name := fmt.Sprintf("%s-%d", inputs.SvcPrefix, index)
+	switch operation {
+	case OperationCreate:
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: inputs.Namespace}}
+		svc.Spec.Template.Spec.Containers = []corev1.Container{{Image: service.ServiceImage}}
+		_, err := ksvcClient.Services(inputs.Namespace).Create(ctx, svc, metav1.CreateOptions{DryRun: dryRun})
+		return err
+	case OperationUpdate:
+		patch, err := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": map[string]string{probeAnnotation: fmt.Sprintf("%d", index)}},
+		}}})
+		if err != nil {
+			return err
+		}
+		_, err = ksvcClient.Services(inputs.Namespace).Patch(ctx, inputs.Service, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+		return err
+	case OperationBaseline:
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: inputs.Namespace}}
+		_, err := params.ClientSet.CoreV1().ConfigMaps(inputs.Namespace).Create(ctx, cm, metav1.CreateOptions{DryRun: dryRun})
+		return err
+	}
+	return fmt.Errorf("unknown operation %s", operation)
+}
+
+// latencies returns the statistics of every operation over the whole run and for every interval of it, the samples
+// are sorted by their offset
+func latencies(samples []sample, operations []string, interval time.Duration) ([]pkg.WebhookLatency, []pkg.WebhookLatency) {
+	overall := make([]pkg.WebhookLatency, 0, len(operations))
+	windows := []pkg.WebhookLatency{}
+	seen := map[string]bool{}
+	for _, operation := range operations {
+		if seen[operation] {
+			continue
+		}
+		seen[operation] = true
+		var all []sample
+		byWindow := map[int][]sample{}
+		last := 0
+		for _, s := range samples {
+			if s.operation != operation {
+				continue
+			}
+			all = append(all, s)
+			window := int(s.offset / interval)
+			byWindow[window] = append(byWindow[window], s)
+			last = window
+		}
+		overall = append(overall, statistics(operation, 0, all))
+		for window := 0; window <= last && len(all) > 0; window++ {
+			windows = append(windows, statistics(operation, (time.Duration(window)*interval).Seconds(), byWindow[window]))
+		}
+	}
+	return overall, windows
+}
+
+// statistics returns the statistics of the samples of the operation, the latencies of the failed requests are not
+// part of the percentiles
+func statistics(operation string, start float64, samples []sample) pkg.WebhookLatency {
+	latency := pkg.WebhookLatency{Operation: operation, Start: start}
+	durations := []float64{}
+	for _, s := range samples {
+		switch {
+		case s.skipped:
+			latency.Skipped++
+		case s.err != nil:
+			latency.Requests++
+			latency.Errors++
+		default:
+			latency.Requests++
+			durations = append(durations, s.latency.Seconds())
+		}
+	}
+	if len(durations) == 0 {
+		return latency
+	}
+	latency.P50, _ = stats.Percentile(durations, 50)
+	latency.P95, _ = stats.Percentile(durations, 95)
+	latency.P99, _ = stats.Percentile(durations, 99)
+	latency.Max, _ = stats.Max(durations)
+	return latency
+}
+
+// failedRequests returns an error with the first error if no request succeeded, e.g. because the requests are
+// forbidden
+func failedRequests(samples []sample) error {
+	var first error
+	for _, s := range samples {
+		if s.skipped {
+			continue
+		}
+		if s.err == nil {
+			return nil
+		}
+		if first == nil {
+			first = s.err
+		}
+	}
+	if first == nil {
+		return fmt.Errorf("no request was sent")
+	}
+	return fmt.Errorf("all requests failed, e.g. %s", first)
+}
+
+// windowRows returns the rows of the CSV file with a row for every interval of every operation
+func windowRows(result pkg.WebhookMeasureResult) [][]string {
+	rows := [][]string{{"operation", "start", "requests", "errors", "skipped", "p50", "p95", "p99", "max"}}
+	for _, w := range result.Windows {
+		rows = append(rows, []string{w.Operation,
+			fmt.Sprintf("%f", w.Start),
+			fmt.Sprintf("%d", w.Requests),
+			fmt.Sprintf("%d", w.Errors),
+			fmt.Sprintf("%d", w.Skipped),
+			fmt.Sprintf("%f", w.P50),
+			fmt.Sprintf("%f", w.P95),
+			fmt.Sprintf("%f", w.P99),
+			fmt.Sprintf("%f", w.Max),
+		})
+	}
+	return rows
+}
+
+// rawRows returns the rows of the CSV file with a row for every request
+func rawRows(samples []sample) [][]string {
+	rows := [][]string{{"operation", "offset", "latency", "skipped", "error"}}
+	for _, s := range samples {
+		errText := ""
+		if s.err != nil {
+			errText = s.err.Error()
+		}
+		rows = append(rows, []string{s.operation,
+			fmt.Sprintf("%f", s.offset.Seconds()),
+			fmt.Sprintf("%f", s.latency.Seconds()),
+			fmt.Sprintf("%t", s.skipped),
+			errText,
+		})
+	}
+	return rows
+}
+
+// writeLatencies writes the statistics of every operation with the ones of its intervals below it, the bars compare
+// the 99th percentiles and rows with failed or skipped requests are highlighted
+func writeLatencies(w io.Writer, result pkg.WebhookMeasureResult, interval time.Duration, options render.Options) {
+	var longest float64
+	for _, l := range append(append([]pkg.WebhookLatency{}, result.Operations...), result.Windows...) {
+		if l.P99 > longest {
+			longest = l.P99
+		}
+	}
+	row := func(name string, l pkg.WebhookLatency) *render.Row {
+		r := &render.Row{Name: name, Values: []string{
+			fmt.Sprintf("%d", l.Requests),
+			fmt.Sprintf("%d", l.Errors),
+			fmt.Sprintf("%d", l.Skipped),
+			fmt.Sprintf("%.4fs", l.P50),
+			fmt.Sprintf("%.4fs", l.P95),
+			fmt.Sprintf("%.4fs", l.P99),
+			fmt.Sprintf("%.4fs", l.Max),
+		}}
+		if longest > 0 {
+			r.Bar = l.P99 / longest
+		}
+		if l.Errors > 0 || l.Skipped > 0 {
+			r.Style = render.Red
+		}
+		return r
+	}
+	rows := make([]*render.Row, 0, len(result.Operations))
+	for _, o := range result.Operations {
+		parent := row(o.Operation, o)
+		for _, window := range result.Windows {
+			if window.Operation != o.Operation {
+				continue
+			}
+			start := time.Duration(window.Start * float64(time.Second))
+			parent.Children = append(parent.Children, row(fmt.Sprintf("%s-%s", start, start+interval), window))
+		}
+		rows = append(rows, parent)
+	}
+	render.Table(w, []string{"OPERATION", "REQUESTS", "ERRORS", "SKIPPED", "P50", "P95", "P99", "MAX"}, rows, options)
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func TestLatencies(t *testing.T) {
+	samples := []sample{
+		{operation: OperationCreate, offset: 0, latency: time.Second},
+		{operation: OperationBaseline, offset: time.Second, latency: 100 * time.Millisecond},
+		{operation: OperationCreate, offset: 2 * time.Second, latency: 3 * time.Second},
+		{operation: OperationCreate, offset: 3 * time.Second, err: fmt.Errorf("denied")},
+		{operation: OperationCreate, offset: 25 * time.Second, skipped: true},
+	}
+	overall, windows := latencies(samples, []string{OperationCreate, OperationBaseline, OperationCreate}, 10*time.Second)
+	assert.Equal(t, 2, len(overall))
+	assert.DeepEqual(t, pkg.WebhookLatency{Operation: OperationCreate, Requests: 3, Errors: 1, Skipped: 1, P50: 1, P95: 2, P99: 2, Max: 3}, overall[0])
+	assert.Equal(t, 1, overall[1].Requests)
+	assert.Equal(t, 0.1, overall[1].Max)
+
+	// the windows without requests are reported as well
+	assert.Equal(t, 4, len(windows))
+	assert.DeepEqual(t, pkg.WebhookLatency{Operation: OperationCreate, Start: 10}, windows[1])
+	assert.DeepEqual(t, pkg.WebhookLatency{Operation: OperationCreate, Start: 20, Skipped: 1}, windows[2])
+	assert.Equal(t, OperationBaseline, windows[3].Operation)
+}
+
+func TestWriteLatencies(t *testing.T) {
+	result := pkg.WebhookMeasureResult{
+		Operations: []pkg.WebhookLatency{{Operation: OperationCreate, Requests: 20, Errors: 1, P50: 0.05, P95: 0.2, P99: 0.25, Max: 0.3}},
+		Windows: []pkg.WebhookLatency{
+			{Operation: OperationCreate, Requests: 10, P99: 0.1},
+			{Operation: OperationCreate, Start: 10, Requests: 10, Errors: 1, P99: 0.25},
+		},
+	}
+	out := &bytes.Buffer{}
+	writeLatencies(out, result, 10*time.Second, render.Options{})
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("OPERATION")), out.String())
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("10s-20s")), out.String())
+	assert.Check(t, bytes.Contains(out.Bytes(), []byte("0.2500s")), out.String())
+
+	rows := windowRows(result)
+	assert.Equal(t, 3, len(rows))
+	assert.DeepEqual(t, []string{"create", "10.000000", "10", "1", "0", "0.000000", "0.000000", "0.250000", "0.000000"}, rows[2])
+}
+
+func TestFailedRequests(t *testing.T) {
+	assert.ErrorContains(t, failedRequests(nil), "no request was sent")
+	assert.ErrorContains(t, failedRequests([]sample{{skipped: true}, {err: fmt.Errorf("forbidden")}}), "all requests failed, e.g. forbidden")
+	assert.NilError(t, failedRequests([]sample{{err: fmt.Errorf("forbidden")}, {}}))
+}
+
+func TestMeasureWebhooks(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	fakeServing := &servingv1fake.FakeServingV1{Fake: &client.Fake}
+	existing := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-0", Namespace: "ns-1"}}
+	assert.NilError(t, client.Tracker().Create(servingv1.SchemeGroupVersion.WithResource("services"), existing, "ns-1"))
+	// the fake doesn't know dry run, the requests are answered without persisting anything and are recorded
+	var m sync.Mutex
+	dryRuns := map[string]int{}
+	answer := func(action clienttesting.Action) (bool, runtime.Object, error) {
+		m.Lock()
+		defer m.Unlock()
+		dryRuns[action.GetVerb()+" "+action.GetResource().Resource]++
+		if action.GetResource().Resource == "configmaps" && dryRuns["create configmaps"] == 1 {
+			return true, nil, fmt.Errorf("too many requests")
+		}
+		if create, ok := action.(clienttesting.CreateAction); ok {
+			return true, create.GetObject(), nil
+		}
+		return true, existing, nil
+	}
+	fakeServing.PrependReactor("create", "services", answer)
+	fakeServing.PrependReactor("patch", "services", answer)
+	client.PrependReactor("create", "configmaps", answer)
+	p := &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return fakeServing, nil
+		},
+	}
+
+	t.Run("operations", func(t *testing.T) {
+		outputDir := t.TempDir()
+		_, err := testutil.ExecuteCommand(NewWebhookMeasureCommand(p), "--namespace", "ns-1", "--service", "ksvc-0",
+			"--operations", "create,update,baseline", "--qps", "100", "--duration", "300ms", "--interval", "100ms",
+			"--run-id", "webhook-1", "--output", outputDir)
+		assert.NilError(t, err)
+		assert.Check(t, dryRuns["create services"] > 0)
+		assert.Check(t, dryRuns["patch services"] > 0)
+		assert.Check(t, dryRuns["create configmaps"] > 0)
+		// nothing was created
+		cms, err := client.CoreV1().ConfigMaps("ns-1").List(context.Background(), metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 0, len(cms.Items))
+
+		matches, err := filepath.Glob(filepath.Join(outputDir, "webhook-1", "*_"+WebhookOutputFilename+".json"))
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(matches))
+		data, err := ioutil.ReadFile(matches[0])
+		assert.NilError(t, err)
+		result := pkg.WebhookMeasureResult{}
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, 100, result.QPS)
+		assert.Equal(t, 3, len(result.Operations))
+		assert.Equal(t, OperationCreate, result.Operations[0].Operation)
+		assert.Equal(t, OperationBaseline, result.Operations[2].Operation)
+		assert.Equal(t, 1, result.Operations[2].Errors)
+		assert.Check(t, len(result.Windows) >= 3)
+	})
+
+	t.Run("missing service", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewWebhookMeasureCommand(p), "--namespace", "ns-1", "--service", "ksvc-1",
+			"--operations", "update", "--output", t.TempDir())
+		assert.ErrorContains(t, err, "failed to get Knative Service ksvc-1 in namespace ns-1")
+	})
+
+	t.Run("invalid flags", func(t *testing.T) {
+		_, err := testutil.ExecuteCommand(NewWebhookMeasureCommand(p))
+		assert.ErrorContains(t, err, "requires --namespace")
+		_, err = testutil.ExecuteCommand(NewWebhookMeasureCommand(p), "--namespace", "ns-1", "--operations", "delete")
+		assert.ErrorContains(t, err, "unknown operation delete")
+		_, err = testutil.ExecuteCommand(NewWebhookMeasureCommand(p), "--namespace", "ns-1", "--operations", "update")
+		assert.ErrorContains(t, err, "operation update requires --service")
+		_, err = testutil.ExecuteCommand(NewWebhookMeasureCommand(p), "--namespace", "ns-1", "--qps", "0")
+		assert.ErrorContains(t, err, "--qps and --concurrency must be at least 1")
+		_, err = testutil.ExecuteCommand(NewWebhookMeasureCommand(p), "--namespace", "ns-1", "--interval", "0s")
+		assert.ErrorContains(t, err, "--duration and --interval must be positive")
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"github.com/spf13/cobra"
+	"knative.dev/kperf/pkg"
+)
+
+func NewWebhookCmd(p *pkg.PerfParams) *cobra.Command {
+	var webhookCmd = &cobra.Command{
+		Use:   "webhook",
+		Short: "Knative webhook latency probe",
+		Long: `Knative Serving admission webhook latency probe. For example:
+
+kperf webhook measure --namespace ns --qps 20 --duration 5m - to measure the latency of dry run Knative Service creations over time`,
+	}
+	webhookCmd.AddCommand(NewWebhookMeasureCommand(p))
+
+	webhookCmd.InitDefaultHelpCmd()
+	return webhookCmd
+}
@@ -0,0 +1,299 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wizard
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+)
+
+const (
+	// defaultNodes is the cluster size offered when the worker nodes can't be counted
+	defaultNodes = 3
+	// podsPerNodeBatch is the number of pods started per worker node in a batch, which the scheduler and the
+	// kubelets handle without queueing
+	podsPerNodeBatch = 5
+	// podStartBudget is the interval added to the batch interval per pod started on a node in a batch
+	podStartBudget = 2 * time.Second
+	// maxPodsPerNode is the pod limit of the kubelet, the services beyond it can't be ready at the same time
+	maxPodsPerNode = 110
+	// controlPlaneLabel marks the control plane nodes, which run no Knative Services
+	controlPlaneLabel = "node-role.kubernetes.io/control-plane"
+)
+
+// answers are the answers to the questions of the wizard
+type answers struct {
+	nodes           int
+	namespacePrefix string
+	namespaces      int
+	services        int
+	svcPrefix       string
+	output          string
+}
+
+// plan are the settings derived from the answers
+type plan struct {
+	answers
+	batch               int
+	interval            time.Duration
+	generateConcurrency int
+	measureConcurrency  int
+	qps                 int
+	burst               int
+	timeout             time.Duration
+	serviceTimeout      time.Duration
+}
+
+// NewInitCommand implements 'kperf init' command
+func NewInitCommand(p *pkg.PerfParams) *cobra.Command {
+	initArgs := pkg.InitArgs{}
+	initCommand := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a config file for a first benchmark",
+		Long: `Ask about the cluster size, the namespaces and the desired scale and generate a config file
+
+The config file holds the flags of 'service generate', 'service measure' and 'service clean' with a concurrency, a
+client side QPS and timeouts derived from the answers, so that the first benchmark doesn't need to guess them. The
+number of worker nodes is counted in the cluster if it can be reached. Empty answers keep the default in brackets.
+
+For example:
+# To answer the questions and write the config file kperf.yaml
+kperf init
+
+# To run the benchmark with the config file
+kperf --config kperf.yaml service generate
+kperf --config kperf.yaml service measure
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Init(p, initArgs, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	initCommand.Flags().StringVarP(&initArgs.Output, "output", "o", "kperf.yaml", "Path of the generated config file")
+	initCommand.Flags().BoolVarP(&initArgs.Force, "force", "", false, "Overwrite the config file if it exists, like --overwrite")
+	initCommand.Flags().BoolVarP(&initArgs.Defaults, "defaults", "", false, "Don't ask, generate the config file with the defaults")
+	return initCommand
+}
+
+// Init asks the questions of the wizard on out, reads the answers from in and writes the config file
+func Init(params *pkg.PerfParams, inputs pkg.InitArgs, in io.Reader, out io.Writer) error {
+	path := inputs.Output
+	overwrite := inputs.Force || params.Output.Overwrite
+	// an existing config file is refused before asking the questions, not after
+	if err := utils.CheckOverwrite(path, overwrite); err != nil {
+		return err
+	}
+
+	a := answers{
+		nodes:           countNodes(params),
+		namespacePrefix: "ktest",
+		namespaces:      1,
+		services:        100,
+		svcPrefix:       "ktest",
+		output:          ".",
+	}
+	if !inputs.Defaults {
+		q := &questions{in: bufio.NewReader(in), out: out}
+		fmt.Fprintf(out, "This wizard generates a kperf config file for a first benchmark, empty answers keep the default in brackets.\n\n")
+		for _, ask := range []func() error{
+			func() error { return q.number("How many worker nodes does the cluster have?", &a.nodes) },
+			func() error { return q.text("Which prefix should the namespaces have?", &a.namespacePrefix) },
+			func() error {
+				return q.number("How many namespaces should the Knative Services be spread over?", &a.namespaces)
+			},
+			func() error { return q.number("How many Knative Services should be created?", &a.services) },
+			func() error { return q.text("Which prefix should the Knative Services have?", &a.svcPrefix) },
+			func() error { return q.text("Which directory should the results be written to?", &a.output) },
+		} {
+			if err := ask(); err != nil {
+				return err
+			}
+		}
+	}
+
+	pl := newPlan(a)
+	if err := utils.WriteFile(path, []byte(pl.config()), overwrite); err != nil {
+		return fmt.Errorf("failed to write config file: %s", err)
+	}
+	fmt.Fprintf(out, "\nConfig file saved in %s\n", path)
+	if warning := pl.capacityWarning(); warning != "" {
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+	}
+	fmt.Fprintf(out, "Run the benchmark with:\n  kperf --config %s service generate\n  kperf --config %s service measure\n  kperf --config %s service clean\n",
+		path, path, path)
+	return nil
+}
+
+// countNodes returns the number of worker nodes of the cluster, or defaultNodes if they can't be listed
+func countNodes(params *pkg.PerfParams) int {
+	if params == nil || params.ClientSet == nil {
+		return defaultNodes
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	nodes, err := params.ClientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return defaultNodes
+	}
+	workers := 0
+	for _, node := range nodes.Items {
+		if _, ok := node.Labels[controlPlaneLabel]; !ok && !node.Spec.Unschedulable {
+			workers++
+		}
+	}
+	if workers == 0 {
+		return defaultNodes
+	}
+	return workers
+}
+
+// questions asks the questions and reads the answers line by line
+type questions struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// text asks the question until the answer is not blank, the default is kept for an empty answer
+func (q *questions) text(question string, value *string) error {
+	return q.ask(question, *value, func(answer string) error {
+		if strings.ContainsAny(answer, " \t") {
+			return fmt.Errorf("expected a value without blanks, given %q", answer)
+		}
+		*value = answer
+		return nil
+	})
+}
+
+// number asks the question until the answer is a positive number, the default is kept for an empty answer
+func (q *questions) number(question string, value *int) error {
+	return q.ask(question, strconv.Itoa(*value), func(answer string) error {
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 1 {
+			return fmt.Errorf("expected a positive number, given %q", answer)
+		}
+		*value = n
+		return nil
+	})
+}
+
+func (q *questions) ask(question, defaultValue string, set func(answer string) error) error {
+	for {
+		fmt.Fprintf(q.out, "%s [%s]: ", question, defaultValue)
+		line, err := q.in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			if err == io.EOF && line == "" {
+				// the answers ended, the defaults are kept for the questions left
+				fmt.Fprintln(q.out)
+			}
+			return nil
+		}
+		setErr := set(answer)
+		if setErr == nil {
+			return nil
+		}
+		fmt.Fprintf(q.out, "%s\n", setErr)
+		if err == io.EOF {
+			return setErr
+		}
+	}
+}
+
+// newPlan derives the settings from the answers. A batch starts podsPerNodeBatch pods per node, and the batches are
+// podStartBudget per pod apart, so that the pods of a batch are started before the next one. The workers create and
+// measure the services in parallel, the client side QPS allows each of them about two API server requests per
+// second. A service waits for the pods of its batch to be started, the other pods of the batch may be scheduled before
+// its own one.
+func newPlan(a answers) plan {
+	p := plan{answers: a}
+	p.batch = clamp(a.nodes*podsPerNodeBatch, 1, a.services)
+	podsPerNode := (p.batch + a.nodes - 1) / a.nodes
+	p.interval = time.Duration(podsPerNode) * podStartBudget
+	if p.interval < 5*time.Second {
+		p.interval = 5 * time.Second
+	}
+	p.generateConcurrency = clamp(p.batch, 1, 20)
+	p.measureConcurrency = clamp(a.services/10, 5, 50)
+	p.qps = clamp(2*max(p.generateConcurrency, p.measureConcurrency), 10, 100)
+	p.burst = 2 * p.qps
+	p.timeout = 5*time.Minute + p.interval
+	p.serviceTimeout = time.Minute
+	return p
+}
+
+// capacityWarning warns when the services can't all have a pod at the same time
+func (p plan) capacityWarning() string {
+	if p.services <= p.nodes*maxPodsPerNode {
+		return ""
+	}
+	return fmt.Sprintf("%d Knative Services exceed the %d pods %d worker node(s) can run at the same time, the services "+
+		"scale to zero when they are ready, but their pods may wait for the ones of other services", p.services, p.nodes*maxPodsPerNode, p.nodes)
+}
+
+// config returns the config file of the plan
+func (p plan) config() string {
+	namespaces := fmt.Sprintf(`    namespace-prefix: %s
+    namespace-range: 1,%d
+    svc-prefix: %s
+`, p.namespacePrefix, p.namespaces, p.svcPrefix)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# kperf config file generated by 'kperf init' for %d Knative Services in %d namespace(s) on %d worker node(s)\n",
+		p.services, p.namespaces, p.nodes)
+	fmt.Fprintf(&b, "# client side limit of the API server requests\n")
+	fmt.Fprintf(&b, "qps: %d\nburst: %d\n", p.qps, p.burst)
+	fmt.Fprintf(&b, "service:\n")
+	fmt.Fprintf(&b, "  generate:\n")
+	fmt.Fprintf(&b, "    # %d pods per worker node every %s\n", (p.batch+p.nodes-1)/p.nodes, p.interval)
+	fmt.Fprintf(&b, "    number: %d\n    batch: %d\n    interval: %s\n    concurrency: %d\n", p.services, p.batch, p.interval, p.generateConcurrency)
+	b.WriteString(namespaces)
+	fmt.Fprintf(&b, "    create-namespaces: true\n    wait: true\n    timeout: %s\n", p.timeout)
+	fmt.Fprintf(&b, "  measure:\n")
+	b.WriteString(namespaces)
+	fmt.Fprintf(&b, "    concurrency: %d\n    per-service-timeout: %s\n    output: %s\n", p.measureConcurrency, p.serviceTimeout, p.output)
+	fmt.Fprintf(&b, "  clean:\n")
+	b.WriteString(namespaces)
+	return b.String()
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
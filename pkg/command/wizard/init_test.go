@@ -0,0 +1,175 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wizard
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/testutil"
+)
+
+func node(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func readConfig(t *testing.T, path string) *viper.Viper {
+	v := viper.New()
+	v.SetConfigFile(path)
+	assert.NilError(t, v.ReadInConfig())
+	return v
+}
+
+func TestNewInitCommand(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(
+		node("master", map[string]string{controlPlaneLabel: ""}),
+		node("worker-1", nil),
+		node("worker-2", nil),
+	)
+	p := &pkg.PerfParams{ClientSet: client}
+
+	t.Run("answer the questions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "kperf.yaml")
+		cmd := NewInitCommand(p)
+		cmd.SetIn(strings.NewReader("\nperf\n2\nmany\n300\n\n/tmp/results\n"))
+		out, err := testutil.ExecuteCommand(cmd, "--output", path)
+		assert.NilError(t, err)
+		assert.Check(t, strings.Contains(out, "How many worker nodes does the cluster have? [2]: "), out)
+		assert.Check(t, strings.Contains(out, `expected a positive number, given "many"`), out)
+		assert.Check(t, strings.Contains(out, "Config file saved in "+path), out)
+		assert.Check(t, strings.Contains(out, "kperf --config "+path+" service generate"), out)
+
+		v := readConfig(t, path)
+		assert.Equal(t, 300, v.GetInt("service.generate.number"))
+		assert.Equal(t, 10, v.GetInt("service.generate.batch"))
+		assert.Equal(t, "10s", v.GetString("service.generate.interval"))
+		assert.Equal(t, 10, v.GetInt("service.generate.concurrency"))
+		assert.Equal(t, "perf", v.GetString("service.generate.namespace-prefix"))
+		assert.Equal(t, "1,2", v.GetString("service.generate.namespace-range"))
+		assert.Equal(t, "ktest", v.GetString("service.generate.svc-prefix"))
+		assert.Equal(t, true, v.GetBool("service.generate.create-namespaces"))
+		assert.Equal(t, "1,2", v.GetString("service.measure.namespace-range"))
+		assert.Equal(t, 30, v.GetInt("service.measure.concurrency"))
+		assert.Equal(t, "/tmp/results", v.GetString("service.measure.output"))
+		assert.Equal(t, "perf", v.GetString("service.clean.namespace-prefix"))
+		assert.Equal(t, 60, v.GetInt("qps"))
+		assert.Equal(t, 120, v.GetInt("burst"))
+	})
+
+	t.Run("existing config file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "kperf.yaml")
+		assert.NilError(t, ioutil.WriteFile(path, []byte("qps: 1\n"), 0644))
+		cmd := NewInitCommand(p)
+		_, err := testutil.ExecuteCommand(cmd, "--output", path, "--defaults")
+		assert.ErrorContains(t, err, path+" already exists, replace it with --overwrite")
+
+		cmd = NewInitCommand(&pkg.PerfParams{ClientSet: client, Output: pkg.OutputOptions{Overwrite: true}})
+		_, err = testutil.ExecuteCommand(cmd, "--output", path, "--defaults")
+		assert.NilError(t, err)
+		v := readConfig(t, path)
+		assert.Equal(t, 100, v.GetInt("service.generate.number"))
+		assert.Equal(t, "1,1", v.GetString("service.generate.namespace-range"))
+
+		assert.NilError(t, ioutil.WriteFile(path, []byte("qps: 1\n"), 0644))
+		cmd = NewInitCommand(p)
+		_, err = testutil.ExecuteCommand(cmd, "--output", path, "--defaults", "--force")
+		assert.NilError(t, err)
+		v = readConfig(t, path)
+		assert.Equal(t, 100, v.GetInt("service.generate.number"))
+	})
+
+	t.Run("output name", func(t *testing.T) {
+		// the config file is written to the given path, --output-name only names the result files
+		dir := t.TempDir()
+		cmd := NewInitCommand(&pkg.PerfParams{ClientSet: client, Output: pkg.OutputOptions{Name: "nightly"}})
+		path := filepath.Join(dir, "kperf.yaml")
+		out, err := testutil.ExecuteCommand(cmd, "--output", path, "--defaults")
+		assert.NilError(t, err)
+		assert.Check(t, strings.Contains(out, "Config file saved in "+path), out)
+		v := readConfig(t, path)
+		assert.Equal(t, 100, v.GetInt("service.generate.number"))
+	})
+
+	t.Run("answers ended", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "kperf.yaml")
+		cmd := NewInitCommand(p)
+		cmd.SetIn(strings.NewReader("1"))
+		_, err := testutil.ExecuteCommand(cmd, "--output", path)
+		assert.NilError(t, err)
+		v := readConfig(t, path)
+		assert.Equal(t, 5, v.GetInt("service.generate.batch"))
+		assert.Equal(t, 100, v.GetInt("service.generate.number"))
+	})
+}
+
+func TestCountNodes(t *testing.T) {
+	assert.Equal(t, defaultNodes, countNodes(&pkg.PerfParams{}))
+	assert.Equal(t, defaultNodes, countNodes(&pkg.PerfParams{ClientSet: k8sfake.NewSimpleClientset()}))
+
+	cordoned := node("worker-2", nil)
+	cordoned.Spec.Unschedulable = true
+	client := k8sfake.NewSimpleClientset(node("master", map[string]string{controlPlaneLabel: ""}), node("worker-1", nil), cordoned)
+	assert.Equal(t, 1, countNodes(&pkg.PerfParams{ClientSet: client}))
+}
+
+func TestNewPlan(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		answers  answers
+		batch    int
+		interval time.Duration
+		gen      int
+		measure  int
+		qps      int
+	}{
+		{"small", answers{nodes: 3, services: 10}, 10, 8 * time.Second, 10, 5, 20},
+		{"default", answers{nodes: 3, services: 100}, 15, 10 * time.Second, 15, 10, 30},
+		{"single node", answers{nodes: 1, services: 50}, 5, 10 * time.Second, 5, 5, 10},
+		{"large", answers{nodes: 50, services: 5000}, 250, 10 * time.Second, 20, 50, 100},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newPlan(tc.answers)
+			assert.Equal(t, tc.batch, p.batch)
+			assert.Equal(t, tc.interval, p.interval)
+			assert.Equal(t, tc.gen, p.generateConcurrency)
+			assert.Equal(t, tc.measure, p.measureConcurrency)
+			assert.Equal(t, tc.qps, p.qps)
+			assert.Equal(t, 2*tc.qps, p.burst)
+			assert.Equal(t, 5*time.Minute+tc.interval, p.timeout)
+		})
+	}
+}
+
+func TestCapacityWarning(t *testing.T) {
+	assert.Equal(t, "", newPlan(answers{nodes: 3, services: 330}).capacityWarning())
+	warning := newPlan(answers{nodes: 1, services: 200}).capacityWarning()
+	assert.Check(t, strings.Contains(warning, "200 Knative Services exceed the 110 pods 1 worker node(s) can run"), warning)
+
+	out := &bytes.Buffer{}
+	path := filepath.Join(t.TempDir(), "kperf.yaml")
+	assert.NilError(t, Init(&pkg.PerfParams{}, pkg.InitArgs{Output: path, Defaults: true}, strings.NewReader(""), out))
+	assert.Check(t, !strings.Contains(out.String(), "Warning:"), out.String())
+}
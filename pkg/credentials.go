@@ -0,0 +1,144 @@
+// Copyright 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// credentialRetries is the number of times a request rejected as unauthorized is sent again with refreshed
+	// credentials. Exec credential plugins only refresh their credentials after a rejected request, so the
+	// first retry can still carry the expired credentials.
+	credentialRetries = 2
+	credentialBackoff = time.Second
+)
+
+// credentialRefresher keeps long runs going when their credentials expire or are rotated. Requests rejected
+// as unauthorized are sent again through a transport built from a freshly loaded kubeconfig, so rotated
+// bearer tokens, token files and exec credential plugins are picked up without restarting the run. The refreshed
+// transport replaces the one with the stale credentials for all the following requests.
+type credentialRefresher struct {
+	params *PerfParams
+
+	mu   sync.Mutex
+	next http.RoundTripper
+	// generation counts the refreshes, so that the requests rejected concurrently refresh the transport only once
+	generation int
+}
+
+// current returns the transport with the credentials loaded last and its generation
+func (r *credentialRefresher) current() (http.RoundTripper, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.next, r.generation
+}
+
+// refresh replaces the transport of the generation which was rejected with one holding the credentials of the
+// kubeconfig loaded again. A transport refreshed since by a concurrent request is returned as it is.
+func (r *credentialRefresher) refresh(rejected int) (http.RoundTripper, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation != rejected {
+		return r.next, r.generation, nil
+	}
+	rt, err := r.refreshedTransport()
+	if err != nil {
+		return nil, 0, err
+	}
+	closeIdleConnections(r.next)
+	r.next = rt
+	r.generation++
+	return r.next, r.generation, nil
+}
+
+func (r *credentialRefresher) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt, generation := r.current()
+	sent := req
+	if generation > 0 {
+		// the Authorization header set from the stale credentials of the client config is replaced by the one of
+		// the refreshed transport
+		sent = req.Clone(req.Context())
+		sent.Header.Del("Authorization")
+	}
+	resp, err := rt.RoundTrip(sent)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// the request can only be sent again if its body can be replayed
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	for i := 0; i < credentialRetries; i++ {
+		rt, generation, err = r.refresh(generation)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to refresh credentials: %s\n", err)
+			return resp, nil
+		}
+		retry := req.Clone(req.Context())
+		// the Authorization header is set again by the refreshed transport
+		retry.Header.Del("Authorization")
+		if req.GetBody != nil {
+			retry.Body, err = req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+		}
+		if i > 0 {
+			// give the exec credential plugin time to refresh the credentials rejected again
+			timer := time.NewTimer(credentialBackoff)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				return resp, nil
+			case <-timer.C:
+			}
+		}
+		resp.Body.Close()
+		resp, err = rt.RoundTrip(retry)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// refreshedTransport loads the kubeconfig again and returns a transport with its current credentials
+func (r *credentialRefresher) refreshedTransport() (http.RoundTripper, error) {
+	config, err := r.params.clientConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	return rest.TransportFor(config)
+}
+
+// closeIdleConnections closes the idle connections of the transport replaced by a refreshed one, which is
+// unwrapped like the transports of client-go
+func closeIdleConnections(rt http.RoundTripper) {
+	switch t := rt.(type) {
+	case interface{ CloseIdleConnections() }:
+		t.CloseIdleConnections()
+	case utilnet.RoundTripperWrapper:
+		closeIdleConnections(t.WrappedRoundTripper())
+	}
+}
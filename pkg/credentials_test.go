@@ -0,0 +1,102 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+    insecure-skip-tls-verify: true
+users:
+- name: test
+  user:
+    token: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`
+
+func TestCredentialRefresher(t *testing.T) {
+	var requests, unauthorized, revoked int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("Authorization") != "Bearer rotated" || atomic.LoadInt32(&revoked) == 1 {
+			atomic.AddInt32(&unauthorized, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"ns-1"}}`))
+	}))
+	defer server.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	writeKubeconfig := func(token string) {
+		assert.NilError(t, ioutil.WriteFile(kubeconfig, []byte(fmt.Sprintf(testKubeconfig, server.URL, token)), 0600))
+	}
+
+	writeKubeconfig("expired")
+	p := &PerfParams{KubeCfgPath: kubeconfig}
+	assert.NilError(t, p.Initialize())
+
+	_, err := p.ClientSet.CoreV1().Namespaces().Get(context.TODO(), "ns-1", metav1.GetOptions{})
+	assert.Check(t, apierrors.IsUnauthorized(err), "expected unauthorized error, got %v", err)
+
+	// the token is rotated in the kubeconfig during the run
+	writeKubeconfig("rotated")
+	ns, err := p.ClientSet.CoreV1().Namespaces().Get(context.TODO(), "ns-1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "ns-1", ns.Name)
+
+	// the refreshed transport is kept, the following requests aren't rejected first
+	atomic.StoreInt32(&requests, 0)
+	atomic.StoreInt32(&unauthorized, 0)
+	for i := 0; i < 3; i++ {
+		_, err = p.ClientSet.CoreV1().Namespaces().Get(context.TODO(), "ns-1", metav1.GetOptions{})
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&unauthorized))
+
+	// the backoff before retrying credentials which are rejected again ends with the request
+	atomic.StoreInt32(&revoked, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = p.ClientSet.CoreV1().Namespaces().Get(ctx, "ns-1", metav1.GetOptions{})
+	assert.Assert(t, err != nil)
+	assert.Assert(t, time.Since(start) < credentialBackoff, "the request took %s", time.Since(start))
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"strconv"
+	"time"
+)
+
+// ExpiresAtLabel holds the time in Unix seconds after which a resource generated by kperf has expired and is
+// reaped by `kperf clean expired`, so resources of forgotten runs don't pile up in shared clusters
+const ExpiresAtLabel = "kperf.dev/expires-at"
+
+// ExpiryLabels returns the labels to stamp a resource created at now which expires after ttl, no labels if ttl is 0
+func ExpiryLabels(ttl time.Duration, now time.Time) map[string]string {
+	if ttl <= 0 {
+		return nil
+	}
+	return map[string]string{ExpiresAtLabel: strconv.FormatInt(now.Add(ttl).Unix(), 10)}
+}
+
+// Expired returns true if the labels of a resource stamp it as expired at now. Resources without or with an
+// invalid expiry label never expire.
+func Expired(labels map[string]string, now time.Time) bool {
+	value, ok := labels[ExpiresAtLabel]
+	if !ok {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	return !now.Before(time.Unix(expiresAt, 0))
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestExpiryLabels(t *testing.T) {
+	now := time.Unix(1640995200, 0)
+	assert.Check(t, ExpiryLabels(0, now) == nil)
+	assert.DeepEqual(t, map[string]string{ExpiresAtLabel: "1640998800"}, ExpiryLabels(time.Hour, now))
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Unix(1640995200, 0)
+	assert.Check(t, Expired(ExpiryLabels(time.Hour, now), now.Add(time.Hour)))
+	assert.Check(t, !Expired(ExpiryLabels(time.Hour, now), now.Add(time.Minute)))
+	assert.Check(t, !Expired(nil, now), "resources without expiry label should never expire")
+	assert.Check(t, !Expired(map[string]string{ExpiresAtLabel: "tomorrow"}, now), "resources with invalid expiry label should never expire")
+}
@@ -14,6 +14,12 @@
 
 package generator
 
+import (
+	"context"
+
+	"knative.dev/kperf/pkg/pool"
+)
+
 // func Clean do the clean action for resource with name in ns
 type Clean func(ns, name string)
 
@@ -22,11 +28,6 @@ type BatchCleaner struct {
 	namespaceNameList [][2]string
 	concurrency       int
 	cleanFunc         Clean
-
-	doneChan          chan bool
-	namespaceNameChan chan [2]string
-	finishedChan      chan int
-	finishedCount     int
 }
 
 func NewBatchCleaner(namespaceNameList [][2]string, concurrency int, cleanFunc Clean) *BatchCleaner {
@@ -36,47 +37,12 @@ func NewBatchCleaner(namespaceNameList [][2]string, concurrency int, cleanFunc C
 	return &BatchCleaner{
 		namespaceNameList: namespaceNameList,
 		concurrency:       concurrency,
-		namespaceNameChan: make(chan [2]string, len(namespaceNameList)),
 		cleanFunc:         cleanFunc,
-
-		doneChan:      make(chan bool),
-		finishedChan:  make(chan int, concurrency*5),
-		finishedCount: 0,
 	}
 }
 
 func (bc *BatchCleaner) Clean() {
-	// avoid the blocked channel
-	if len(bc.namespaceNameList) == 0 {
-		return
-	}
-	go bc.checkFinished()
-	for i := 0; i < bc.concurrency; i++ {
-		go bc.doClean()
-	}
-	for _, nsname := range bc.namespaceNameList {
-		bc.namespaceNameChan <- nsname
-	}
-	<-bc.doneChan
-}
-
-func (bc *BatchCleaner) doClean() {
-	for {
-		select {
-		case <-bc.doneChan:
-			return
-		case nsname := <-bc.namespaceNameChan:
-			bc.cleanFunc(nsname[0], nsname[1])
-			bc.finishedChan <- 1
-		}
-	}
-}
-
-func (bc *BatchCleaner) checkFinished() {
-	for range bc.finishedChan {
-		bc.finishedCount++
-		if bc.finishedCount >= len(bc.namespaceNameList) {
-			close(bc.doneChan)
-		}
-	}
+	pool.ForEach(context.Background(), bc.concurrency, len(bc.namespaceNameList), func(ctx context.Context, i int) {
+		bc.cleanFunc(bc.namespaceNameList[i][0], bc.namespaceNameList[i][1])
+	})
 }
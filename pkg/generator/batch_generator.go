@@ -15,8 +15,13 @@
 package generator
 
 import (
+	"context"
 	"os"
 	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"knative.dev/kperf/pkg/pool"
 )
 
 // func Generator do the generate action in namespace ns with the index as the suffix of the resource name
@@ -38,11 +43,9 @@ type BatchGenerator struct {
 	namespaceList     []string
 	generateFunc      Generator
 	postGeneratorFunc PostGenerator
-
-	indexChan     chan int
-	finishedChan  chan int
-	finishedCount int
-	doneChan      chan bool
+	abortFunc         func()
+	clock             clock.Clock
+	ctx               context.Context
 }
 
 func NewBatchGenerator(interval time.Duration, count, batch int, concurrency int, namespaceList []string, generator Generator, postGenerator PostGenerator) *BatchGenerator {
@@ -55,62 +58,75 @@ func NewBatchGenerator(interval time.Duration, count, batch int, concurrency int
 		namespaceList:     namespaceList,
 		generateFunc:      generator,
 		postGeneratorFunc: postGenerator,
+		clock:             clock.RealClock{},
+		ctx:               context.Background(),
+	}
+}
 
-		indexChan:     make(chan int, batch*5),
-		finishedChan:  make(chan int, batch*5),
-		finishedCount: 0,
-		doneChan:      make(chan bool),
+// WithAbort sets abortFunc to be called before the generate process exits because PostGenerator failed, or
+// panics in Generator or PostGenerator
+func (bg *BatchGenerator) WithAbort(abortFunc func()) *BatchGenerator {
+	bg.abortFunc = abortFunc
+	return bg
+}
+
+// WithClock sets the clock pacing the batches, a nil clock keeps the real clock
+func (bg *BatchGenerator) WithClock(c clock.Clock) *BatchGenerator {
+	if c != nil {
+		bg.clock = c
 	}
+	return bg
+}
+
+// WithContext sets the context of the generate process, once it is cancelled no further batch is started and the
+// queued resources are not generated
+func (bg *BatchGenerator) WithContext(ctx context.Context) *BatchGenerator {
+	bg.ctx = ctx
+	return bg
 }
 
 func (bg *BatchGenerator) Generate() {
-	// avoid the blocked channel
 	if bg.count == 0 {
 		return
 	}
-	ticker := time.NewTicker(bg.interval)
+	ticker := bg.clock.NewTicker(bg.interval)
 	defer ticker.Stop()
-	go bg.checkFinished()
-	for i := 0; i < bg.concurrency; i++ {
-		go bg.doGenerate()
-	}
-	for {
+	workers := pool.New(bg.ctx, bg.concurrency, bg.batch*5).WithClock(bg.clock)
+	defer workers.Wait()
+	for bg.counter < bg.count {
 		select {
-		case <-bg.doneChan:
+		case <-ticker.C():
+		case <-bg.ctx.Done():
 			return
-		case <-ticker.C:
-			i := 0
-			for bg.counter < bg.count && i < bg.batch {
-				bg.indexChan <- bg.counter
-				bg.counter++
-				i++
+		}
+		for i := 0; bg.counter < bg.count && i < bg.batch; i++ {
+			index := bg.counter
+			if err := workers.Submit(func(ctx context.Context) {
+				bg.doGenerate(index)
+			}); err != nil {
+				// the context is cancelled
+				return
 			}
+			bg.counter++
 		}
 	}
-
 }
 
-func (bg *BatchGenerator) doGenerate() {
-	for {
-		select {
-		case <-bg.doneChan:
-			return
-		case index := <-bg.indexChan:
-			ns := bg.namespaceList[index%len(bg.namespaceList)]
-			ns, name := bg.generateFunc(ns, index)
-			if bg.postGeneratorFunc(ns, name) != nil {
-				os.Exit(1)
+func (bg *BatchGenerator) doGenerate(index int) {
+	if bg.abortFunc != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				bg.abortFunc()
+				panic(r)
 			}
-			bg.finishedChan <- 1
-		}
+		}()
 	}
-}
-
-func (bg *BatchGenerator) checkFinished() {
-	for range bg.finishedChan {
-		bg.finishedCount++
-		if bg.finishedCount >= bg.count {
-			close(bg.doneChan)
+	ns := bg.namespaceList[index%len(bg.namespaceList)]
+	ns, name := bg.generateFunc(ns, index)
+	if bg.postGeneratorFunc(ns, name) != nil {
+		if bg.abortFunc != nil {
+			bg.abortFunc()
 		}
+		os.Exit(1)
 	}
 }
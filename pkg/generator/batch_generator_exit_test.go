@@ -56,3 +56,24 @@ func TestBatchGeneratorExit(t *testing.T) {
 	// should complete ahead of scheduled 4 (count/batch) seconds
 	assert.Assert(t, duration < 4)
 }
+
+func TestBatchGeneratorAbort(t *testing.T) {
+	generateFunc := func(ns string, index int) (string, string) {
+		return ns, fmt.Sprintf("%s-%d", ns, index)
+	}
+	postGeneratorFunc := func(ns, name string) error {
+		return errors.New("fake error")
+	}
+
+	if os.Getenv("RUN_IN_COMMAND") == "true" {
+		generator.NewBatchGenerator(time.Duration(1)*time.Second, 2, 1, 1, []string{"ns1"}, generateFunc, postGeneratorFunc).
+			WithAbort(func() { fmt.Println("aborted") }).Generate()
+		return
+	}
+	// Run the test in a subprocess
+	cmd := exec.Command(os.Args[0], "-test.run=TestBatchGeneratorAbort")
+	cmd.Env = append(os.Environ(), "RUN_IN_COMMAND=true")
+	output, err := cmd.Output()
+	assert.ErrorContains(t, err, "exit status 1")
+	assert.Assert(t, strings.Contains(string(output), "aborted"), "abort function not called before exit: %q", output)
+}
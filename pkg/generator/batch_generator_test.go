@@ -15,12 +15,14 @@
 package generator_test
 
 import (
+	"context"
 	"fmt"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"knative.dev/kperf/pkg/generator"
 )
 
@@ -85,3 +87,61 @@ func TestBatchGenerator(t *testing.T) {
 		assert.Assert(t, postGeneratorFuncCalled == 8)
 	})
 }
+
+func TestBatchGeneratorWithClock(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	generated := make(chan int)
+	generateFunc := func(ns string, index int) (string, string) {
+		generated <- index
+		return ns, fmt.Sprintf("%s-%d", ns, index)
+	}
+	postGeneratorFunc := func(ns, name string) error { return nil }
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		generator.NewBatchGenerator(time.Minute, 5, 2, 1, []string{"ns1"}, generateFunc, postGeneratorFunc).
+			WithClock(fakeClock).Generate()
+	}()
+
+	// every tick of the clock generates the next batch, the last one is smaller
+	for _, batch := range [][]int{{0, 1}, {2, 3}, {4}} {
+		for !fakeClock.HasWaiters() {
+			time.Sleep(time.Millisecond)
+		}
+		fakeClock.Step(time.Minute)
+		for _, index := range batch {
+			assert.Equal(t, index, <-generated)
+		}
+	}
+	<-done
+}
+
+func TestBatchGeneratorWithContext(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+	generated := make(chan int, 5)
+	generateFunc := func(ns string, index int) (string, string) {
+		generated <- index
+		return ns, fmt.Sprintf("%s-%d", ns, index)
+	}
+	postGeneratorFunc := func(ns, name string) error { return nil }
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		generator.NewBatchGenerator(time.Minute, 5, 2, 1, []string{"ns1"}, generateFunc, postGeneratorFunc).
+			WithClock(fakeClock).WithContext(ctx).Generate()
+	}()
+
+	// the first batch is generated, no further batch is started once the context is cancelled
+	for !fakeClock.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	fakeClock.Step(time.Minute)
+	assert.Equal(t, 0, <-generated)
+	assert.Equal(t, 1, <-generated)
+	cancel()
+	<-done
+	assert.Equal(t, 0, len(generated))
+}
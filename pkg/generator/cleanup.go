@@ -0,0 +1,99 @@
+// Copyright 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// func Delete deletes a resource created by a run
+type Delete func() error
+
+// Cleanup deletes the resources created by a run if the run aborts, so no test resources are left behind.
+// Resources are added once they are created and deleted in the reverse order of their creation.
+type Cleanup struct {
+	lock      sync.Mutex
+	once      sync.Once
+	resources []createdResource
+}
+
+type createdResource struct {
+	kind       string
+	namespace  string
+	name       string
+	deleteFunc Delete
+}
+
+// String returns the kind and the name of the resource, and its namespace unless it is cluster scoped
+func (r createdResource) String() string {
+	if r.namespace == "" {
+		return fmt.Sprintf("%s %s", r.kind, r.name)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", r.kind, r.name, r.namespace)
+}
+
+func NewCleanup() *Cleanup {
+	return &Cleanup{}
+}
+
+// Add adds a created resource which is deleted by deleteFunc, ns is empty for cluster scoped resources like namespaces
+func (c *Cleanup) Add(kind, ns, name string, deleteFunc Delete) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.resources = append(c.resources, createdResource{kind: kind, namespace: ns, name: name, deleteFunc: deleteFunc})
+}
+
+// Run deletes the created resources, it only deletes them the first time it is called
+func (c *Cleanup) Run() {
+	c.once.Do(func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if len(c.resources) == 0 {
+			return
+		}
+		fmt.Printf("Run failed, deleting the %d created resources\n", len(c.resources))
+		for i := len(c.resources) - 1; i >= 0; i-- {
+			r := c.resources[i]
+			fmt.Printf("Deleting %s\n", r)
+			if err := r.deleteFunc(); err != nil {
+				fmt.Printf("failed to delete %s : %s\n", r, err)
+			}
+		}
+	})
+}
+
+// HandleSignals runs the cleanup and exits when the run is interrupted or terminated, until the returned
+// function is called
+func (c *Cleanup) HandleSignals() func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			c.Run()
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
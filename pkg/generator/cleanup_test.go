@@ -0,0 +1,43 @@
+// Copyright 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator_test
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"knative.dev/kperf/pkg/generator"
+)
+
+func TestCleanup(t *testing.T) {
+	deleted := []string{}
+	deleteFunc := func(name string, err error) generator.Delete {
+		return func() error {
+			deleted = append(deleted, name)
+			return err
+		}
+	}
+
+	cleanup := generator.NewCleanup()
+	cleanup.Add("Knative Service", "ns1", "ksvc-0", deleteFunc("ksvc-0", nil))
+	cleanup.Add("Knative Service", "ns1", "ksvc-1", deleteFunc("ksvc-1", errors.New("fake error")))
+	cleanup.Add("Knative Service", "ns2", "ksvc-2", deleteFunc("ksvc-2", nil))
+
+	cleanup.Run()
+	// the resources are deleted once in the reverse order of their creation, even if a deletion fails
+	cleanup.Run()
+	assert.DeepEqual(t, []string{"ksvc-2", "ksvc-1", "ksvc-0"}, deleted)
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package measure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"knative.dev/kperf/pkg"
+)
+
+const (
+	// autoscalerSelector selects the pods of the Knative Serving autoscaler by their app label
+	autoscalerSelector = "app=autoscaler"
+
+	// the metrics the autoscaler reports for every revision with the labels namespace_name, service_name and
+	// revision_name
+	autoscalerDesiredPods         = "autoscaler_desired_pods"
+	autoscalerActualPods          = "autoscaler_actual_pods"
+	autoscalerExcessBurstCapacity = "autoscaler_excess_burst_capacity"
+	autoscalerPanicMode           = "autoscaler_panic_mode"
+)
+
+// autoscalerMetricNames are the metrics scraped from the autoscaler pods
+var autoscalerMetricNames = []string{autoscalerDesiredPods, autoscalerActualPods, autoscalerExcessBurstCapacity, autoscalerPanicMode}
+
+// AutoscalerScraper samples the decisions the Knative Serving autoscaler reports in its Prometheus metrics for the
+// revisions of the services accepted by the filter
+type AutoscalerScraper struct {
+	params *pkg.PerfParams
+	args   pkg.AutoscalerScrapeArgs
+	filter func(namespace, service string) bool
+	logger Logger
+
+	start     time.Time
+	revisions map[string]*pkg.AutoscalerRevision
+	// warned holds the failures which were logged already, so that an unreachable metrics endpoint is reported once
+	// and not with every sample
+	warned map[string]bool
+}
+
+// NewAutoscalerScraper returns a scraper of the autoscaler pods in the namespace of the args, the offsets of the
+// samples are measured from now
+func NewAutoscalerScraper(params *pkg.PerfParams, args pkg.AutoscalerScrapeArgs, filter func(namespace, service string) bool, logger Logger) *AutoscalerScraper {
+	return &AutoscalerScraper{
+		params:    params,
+		args:      args,
+		filter:    filter,
+		logger:    logger,
+		start:     time.Now(),
+		revisions: map[string]*pkg.AutoscalerRevision{},
+		warned:    map[string]bool{},
+	}
+}
+
+// StartAutoscalerScraper samples the autoscaler metrics every interval of the args until the returned function is
+// called, which stops the sampling and returns the metrics
+func StartAutoscalerScraper(ctx context.Context, params *pkg.PerfParams, args pkg.AutoscalerScrapeArgs, filter func(namespace, service string) bool, logger Logger) func() *pkg.AutoscalerMetrics {
+	scraper := NewAutoscalerScraper(params, args, filter, logger)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			if err := scraper.Sample(ctx); err != nil && ctx.Err() == nil {
+				scraper.warn("pods", "failed to scrape the autoscaler: %s\n", err)
+			}
+		}, args.Interval)
+	}()
+	return func() *pkg.AutoscalerMetrics {
+		cancel()
+		<-done
+		return scraper.Metrics()
+	}
+}
+
+// Sample adds a sample of every revision the autoscaler pods report metrics for. With several autoscaler replicas
+// only the leader of the bucket of a revision reports it.
+func (a *AutoscalerScraper) Sample(ctx context.Context) error {
+	now := time.Now()
+	pods, err := a.params.ClientSet.CoreV1().Pods(a.args.Namespace).List(ctx, metav1.ListOptions{LabelSelector: autoscalerSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list the autoscaler pods in namespace %s: %w", a.args.Namespace, err)
+	}
+	samples := map[string]*pkg.AutoscalerDecision{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		data, err := a.params.ClientSet.CoreV1().Pods(a.args.Namespace).ProxyGet("http", pod.Name, strconv.Itoa(a.args.MetricsPort), "metrics", nil).DoRaw(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.warn("metrics/"+pod.Name, "failed to scrape the metrics of autoscaler pod %s: %s\n", pod.Name, err)
+			}
+			continue
+		}
+		metrics, err := parsePrometheusSamples(bytes.NewReader(data), autoscalerMetricNames)
+		if err != nil {
+			a.warn("parse/"+pod.Name, "failed to read the metrics of autoscaler pod %s: %s\n", pod.Name, err)
+			continue
+		}
+		for _, m := range metrics {
+			namespace, service, revision := m.labels["namespace_name"], m.labels["service_name"], m.labels["revision_name"]
+			if !a.filter(namespace, service) {
+				continue
+			}
+			key := namespace + "/" + revision
+			if _, ok := a.revisions[key]; !ok {
+				a.revisions[key] = &pkg.AutoscalerRevision{ServiceNamespace: namespace, ServiceName: service, Revision: revision}
+			}
+			sample, ok := samples[key]
+			if !ok {
+				sample = &pkg.AutoscalerDecision{Time: now, Offset: now.Sub(a.start).Seconds()}
+				samples[key] = sample
+			}
+			switch m.name {
+			case autoscalerDesiredPods:
+				sample.DesiredPods = m.value
+			case autoscalerActualPods:
+				sample.ActualPods = m.value
+			case autoscalerExcessBurstCapacity:
+				sample.ExcessBurstCapacity = m.value
+			case autoscalerPanicMode:
+				sample.PanicMode = m.value > 0
+			}
+		}
+	}
+	for key, sample := range samples {
+		r := a.revisions[key]
+		if r.FirstScaleUp == nil && sample.DesiredPods > sample.ActualPods {
+			offset := sample.Offset
+			r.FirstScaleUp = &offset
+		}
+		r.Samples = append(r.Samples, *sample)
+	}
+	return nil
+}
+
+// Metrics returns the samples taken so far, the revisions are sorted by namespace, service and name
+func (a *AutoscalerScraper) Metrics() *pkg.AutoscalerMetrics {
+	metrics := &pkg.AutoscalerMetrics{
+		Namespace: a.args.Namespace,
+		Interval:  a.args.Interval.Seconds(),
+		Start:     a.start,
+		Revisions: []pkg.AutoscalerRevision{},
+	}
+	for _, r := range a.revisions {
+		metrics.Revisions = append(metrics.Revisions, *r)
+	}
+	sort.Slice(metrics.Revisions, func(i, j int) bool {
+		a, b := metrics.Revisions[i], metrics.Revisions[j]
+		if a.ServiceNamespace != b.ServiceNamespace {
+			return a.ServiceNamespace < b.ServiceNamespace
+		}
+		if a.ServiceName != b.ServiceName {
+			return a.ServiceName < b.ServiceName
+		}
+		return a.Revision < b.Revision
+	})
+	return metrics
+}
+
+func (a *AutoscalerScraper) warn(key, format string, v ...interface{}) {
+	if a.warned[key] {
+		return
+	}
+	a.warned[key] = true
+	a.logger.Printf(format, v...)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package measure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	restclient "k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/kperf/pkg"
+)
+
+// autoscalerMetricsText returns the metrics the autoscaler reports for revision ksvc-1-00001 of service ns-1/ksvc-1
+// and for a revision of a service which isn't scaled
+func autoscalerMetricsText(desired, actual int, panic bool) string {
+	panicMode := "0"
+	if panic {
+		panicMode = "1"
+	}
+	labels := `{configuration_name="ksvc-1",namespace_name="ns-1",revision_name="ksvc-1-00001",service_name="ksvc-1"}`
+	other := `{configuration_name="other",namespace_name="ns-1",revision_name="other-00001",service_name="other"}`
+	return `# HELP autoscaler_desired_pods Number of pods autoscaler wants to allocate
+# TYPE autoscaler_desired_pods gauge
+autoscaler_desired_pods` + labels + ` ` + strconv.Itoa(desired) + `
+autoscaler_actual_pods` + labels + ` ` + strconv.Itoa(actual) + `
+autoscaler_excess_burst_capacity` + labels + ` -100
+autoscaler_panic_mode` + labels + ` ` + panicMode + `
+autoscaler_desired_pods` + other + ` 5
+go_goroutines 42
+`
+}
+
+func TestAutoscalerScraper(t *testing.T) {
+	args := pkg.AutoscalerScrapeArgs{
+		Enabled:     true,
+		Namespace:   DefaultControlPlaneNamespace,
+		Interval:    time.Second,
+		MetricsPort: DefaultControlPlaneMetricsPort,
+	}
+	scaled := func(namespace, service string) bool {
+		return namespace == "ns-1" && service == "ksvc-1"
+	}
+
+	t.Run("sample the decisions for the scaled services", func(t *testing.T) {
+		p, client := newControlPlaneParams(t, nil,
+			newControlPlanePod("autoscaler-1", "autoscaler", corev1.PodRunning),
+			newControlPlanePod("autoscaler-2", "autoscaler", corev1.PodPending),
+			newControlPlanePod("controller-1", "controller", corev1.PodRunning),
+		)
+		responses := []string{autoscalerMetricsText(0, 0, false), autoscalerMetricsText(3, 1, true), autoscalerMetricsText(3, 3, false)}
+		var scraped []string
+		client.PrependProxyReactor("pods", func(action clienttesting.Action) (bool, restclient.ResponseWrapper, error) {
+			proxy := action.(clienttesting.ProxyGetAction)
+			scraped = append(scraped, proxy.GetName()+":"+proxy.GetPort())
+			response := responses[0]
+			responses = responses[1:]
+			return true, fakeProxyResponse{data: response}, nil
+		})
+
+		scraper := NewAutoscalerScraper(p, args, scaled, DefaultLogger)
+		for i := 0; i < 3; i++ {
+			assert.NilError(t, scraper.Sample(context.TODO()))
+		}
+		metrics := scraper.Metrics()
+
+		assert.DeepEqual(t, []string{"autoscaler-1:9090", "autoscaler-1:9090", "autoscaler-1:9090"}, scraped)
+		assert.Equal(t, DefaultControlPlaneNamespace, metrics.Namespace)
+		assert.Equal(t, 1.0, metrics.Interval)
+		assert.Equal(t, 1, len(metrics.Revisions))
+		r := metrics.Revisions[0]
+		assert.Equal(t, "ns-1", r.ServiceNamespace)
+		assert.Equal(t, "ksvc-1", r.ServiceName)
+		assert.Equal(t, "ksvc-1-00001", r.Revision)
+		assert.Equal(t, 3, len(r.Samples))
+		assert.Equal(t, 0.0, r.Samples[0].DesiredPods)
+		assert.Equal(t, -100.0, r.Samples[0].ExcessBurstCapacity)
+		assert.Equal(t, 3.0, r.Samples[1].DesiredPods)
+		assert.Equal(t, 1.0, r.Samples[1].ActualPods)
+		assert.Check(t, r.Samples[1].PanicMode)
+		assert.Check(t, !r.Samples[2].PanicMode)
+		assert.Check(t, r.FirstScaleUp != nil)
+		assert.Equal(t, r.Samples[1].Offset, *r.FirstScaleUp)
+	})
+
+	t.Run("warn once about unreachable autoscaler pods", func(t *testing.T) {
+		p, client := newControlPlaneParams(t, nil, newControlPlanePod("autoscaler-1", "autoscaler", corev1.PodRunning))
+		client.PrependProxyReactor("pods", func(action clienttesting.Action) (bool, restclient.ResponseWrapper, error) {
+			return true, fakeProxyResponse{err: errors.New("connection refused")}, nil
+		})
+		var logs bytes.Buffer
+		scraper := NewAutoscalerScraper(p, args, scaled, log.New(&logs, "", 0))
+		assert.NilError(t, scraper.Sample(context.TODO()))
+		assert.NilError(t, scraper.Sample(context.TODO()))
+		assert.Equal(t, 0, len(scraper.Metrics().Revisions))
+		assert.Equal(t, 1, strings.Count(logs.String(), "failed to scrape the metrics of autoscaler pod autoscaler-1: connection refused"))
+	})
+
+	t.Run("sample until stopped", func(t *testing.T) {
+		p, client := newControlPlaneParams(t, nil, newControlPlanePod("autoscaler-1", "autoscaler", corev1.PodRunning))
+		client.PrependProxyReactor("pods", func(action clienttesting.Action) (bool, restclient.ResponseWrapper, error) {
+			return true, fakeProxyResponse{data: autoscalerMetricsText(1, 0, false)}, nil
+		})
+		intervalArgs := args
+		intervalArgs.Interval = 10 * time.Millisecond
+
+		stop := StartAutoscalerScraper(context.TODO(), p, intervalArgs, scaled, DefaultLogger)
+		time.Sleep(50 * time.Millisecond)
+		metrics := stop()
+		assert.Equal(t, 1, len(metrics.Revisions))
+		assert.Check(t, len(metrics.Revisions[0].Samples) >= 2, "expected several samples, got %d", len(metrics.Revisions[0].Samples))
+		assert.Check(t, metrics.Revisions[0].FirstScaleUp != nil)
+	})
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/kperf/pkg"
+)
+
+// Checkpoint is the progress of a measurement. It is written to a state file periodically, so that a crashed
+// or killed measurement can be resumed without measuring the processed services again.
+type Checkpoint struct {
+	// Processed maps the namespace/name of every processed service to its state
+	Processed map[string]string `json:"processed"`
+	// Records holds the durations of the processed ready services
+	Records []pkg.MeasureRecord `json:"records"`
+	// RawRecords holds the timestamps of the processed ready services
+	RawRecords []pkg.MeasureRawRecord `json:"raw_records"`
+}
+
+// The states of a measured service
+const (
+	StateReady    = "ready"
+	StateNotFound = "not_found"
+	StateNotReady = "not_ready"
+	StateFailed   = "failed"
+	// StateProgressDeadlineExceeded is a service whose revision failed as its Deployment exceeded the progress deadline
+	StateProgressDeadlineExceeded = "progress_deadline_exceeded"
+	// StateTimedOut is a service whose measurement didn't finish within the per service timeout
+	StateTimedOut = "timed_out"
+)
+
+var statusNames = map[serviceStatus]string{
+	statusReady:    StateReady,
+	statusNotFound: StateNotFound,
+	statusNotReady: StateNotReady,
+	statusFailed:   StateFailed,
+
+	statusProgressDeadlineExceeded: StateProgressDeadlineExceeded,
+	statusTimedOut:                 StateTimedOut,
+}
+
+// ReadCheckpoint reads the checkpoint from the state file
+func ReadCheckpoint(file string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %s", file, err)
+	}
+	return checkpoint, nil
+}
+
+// WriteCheckpoint writes the checkpoint to the state file. The checkpoint is written to a temporary file which
+// replaces the state file, so that a crash while writing doesn't leave a truncated state file.
+func WriteCheckpoint(file string, checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %s", err)
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %s", tmp, err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %s", file, err)
+	}
+	return nil
+}
+
+// restore adds the processed services of the checkpoint to the result and returns the services which are not
+// processed yet
+func (c *Checkpoint) restore(result *Result, services []types.NamespacedName) []types.NamespacedName {
+	for svc, name := range c.Processed {
+		namespace := strings.SplitN(svc, "/", 2)[0]
+		for status, statusName := range statusNames {
+			if statusName == name {
+				result.count(namespace, status)
+			}
+		}
+	}
+	for _, record := range c.Records {
+		result.Records = append(result.Records, record)
+		addSums(&result.Summary, record)
+	}
+	result.RawRecords = append(result.RawRecords, c.RawRecords...)
+
+	remaining := make([]types.NamespacedName, 0, len(services))
+	for _, svc := range services {
+		if _, ok := c.Processed[svc.String()]; !ok {
+			remaining = append(remaining, svc)
+		}
+	}
+	return remaining
+}
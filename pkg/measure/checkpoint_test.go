@@ -0,0 +1,52 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestReadWriteCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "checkpoint.json")
+
+	_, err := ReadCheckpoint(file)
+	assert.ErrorContains(t, err, "no such file or directory")
+
+	checkpoint := &Checkpoint{
+		Processed: map[string]string{"ns-1/ksvc-1": "ready"},
+		Records:   []pkg.MeasureRecord{{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", OverallReady: 5}},
+	}
+	assert.NilError(t, WriteCheckpoint(file, checkpoint))
+	read, err := ReadCheckpoint(file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, checkpoint.Processed, read.Processed)
+	assert.DeepEqual(t, checkpoint.Records, read.Records)
+
+	// the temporary file is renamed to the state file
+	files, err := ioutil.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(files))
+
+	assert.NilError(t, ioutil.WriteFile(file, []byte("{"), 0644))
+	_, err = ReadCheckpoint(file)
+	assert.ErrorContains(t, err, "failed to parse checkpoint")
+}
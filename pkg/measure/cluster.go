@@ -0,0 +1,52 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"knative.dev/kperf/pkg"
+)
+
+// MergeClusters returns the measurement of every cluster, named by the kubeconfig context of the same index, and the
+// statistics of the ready services of all clusters merged. Clusters without a result, e.g. since their clients
+// couldn't be created, are skipped.
+func MergeClusters(contexts []string, results []*Result) pkg.MultiClusterMeasureResult {
+	merged := &pkg.MeasureResult{SvcReadyTime: make([]float64, 0)}
+	multi := pkg.MultiClusterMeasureResult{Clusters: make([]pkg.ClusterMeasureResult, 0, len(results))}
+	for i, r := range results {
+		if r == nil {
+			continue
+		}
+		s := r.Summary
+		multi.Clusters = append(multi.Clusters, pkg.ClusterMeasureResult{
+			Context:     contexts[i],
+			KnativeInfo: s.KnativeInfo,
+			Service:     s.Service,
+			Result:      s.Result,
+		})
+		merged.Service.ReadyCount += s.Service.ReadyCount
+		merged.Service.NotReadyCount += s.Service.NotReadyCount
+		merged.Service.NotFoundCount += s.Service.NotFoundCount
+		merged.Service.FailCount += s.Service.FailCount
+		merged.Service.ProgressDeadlineExceededCount += s.Service.ProgressDeadlineExceededCount
+		merged.Service.TimedOutCount += s.Service.TimedOutCount
+		for _, record := range r.Records {
+			addSums(merged, record)
+		}
+	}
+	summarize(merged)
+	multi.Service = merged.Service
+	multi.Result = merged.Result
+	return multi
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestMergeClusters(t *testing.T) {
+	kourier := &Result{
+		Summary: pkg.MeasureResult{
+			Service:     pkg.ServiceCount{ReadyCount: 2, NotReadyCount: 1},
+			KnativeInfo: pkg.KnativeInfo{IngressController: "kourier"},
+			Result:      pkg.Result{OverallAverage: 3},
+		},
+		Records: []pkg.MeasureRecord{{OverallReady: 2, RevisionReady: 1}, {OverallReady: 4, RevisionReady: 3}},
+	}
+	istio := &Result{
+		Summary: pkg.MeasureResult{
+			Service:     pkg.ServiceCount{ReadyCount: 1, FailCount: 1},
+			KnativeInfo: pkg.KnativeInfo{IngressController: "istio"},
+			Result:      pkg.Result{OverallAverage: 9},
+		},
+		Records: []pkg.MeasureRecord{{OverallReady: 9, RevisionReady: 5}},
+	}
+
+	multi := MergeClusters([]string{"kourier", "broken", "istio"}, []*Result{kourier, nil, istio})
+	assert.Equal(t, 2, len(multi.Clusters))
+	assert.Equal(t, "kourier", multi.Clusters[0].Context)
+	assert.Equal(t, "kourier", multi.Clusters[0].KnativeInfo.IngressController)
+	assert.Equal(t, 3.0, multi.Clusters[0].Result.OverallAverage)
+	assert.Equal(t, "istio", multi.Clusters[1].Context)
+	assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 3, NotReadyCount: 1, FailCount: 1}, multi.Service)
+	assert.Equal(t, 5.0, multi.Result.OverallAverage)
+	assert.Equal(t, 3.0, multi.Result.AverageRevisionReadySum)
+	assert.Equal(t, 9.0, multi.Result.OverallMax)
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+)
+
+// sortContainers sorts the container starts by namespace and name of the service and the name of the container
+func sortContainers(containers []pkg.ContainerStarted) {
+	sort.Slice(containers, func(i, j int) bool {
+		a, b := containers[i], containers[j]
+		if a.ServiceNamespace != b.ServiceNamespace {
+			return a.ServiceNamespace < b.ServiceNamespace
+		}
+		if a.ServiceName != b.ServiceName {
+			return a.ServiceName < b.ServiceName
+		}
+		return a.Container < b.Container
+	})
+}
+
+// ContainerRows returns the rows of the CSV file of the container starts with a header
+func ContainerRows(containers []pkg.ContainerStarted) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "container", "serving", "started"}}
+	for _, c := range containers {
+		rows = append(rows, []string{c.ServiceName, c.ServiceNamespace, c.Container, strconv.FormatBool(c.Serving), fmt.Sprintf("%f", c.Started)})
+	}
+	return rows
+}
+
+// containerStatistic is the number of services a container was started for, with the average and the maximum start
+type containerStatistic struct {
+	container string
+	services  int
+	average   float64
+	max       float64
+}
+
+// containerStatistics returns the statistics of the starts by container name, sorted by name
+func containerStatistics(containers []pkg.ContainerStarted) []containerStatistic {
+	byName := map[string]*containerStatistic{}
+	names := []string{}
+	for _, c := range containers {
+		s, ok := byName[c.Container]
+		if !ok {
+			s = &containerStatistic{container: c.Container}
+			byName[c.Container] = s
+			names = append(names, c.Container)
+		}
+		s.services++
+		s.average += c.Started
+		if c.Started > s.max {
+			s.max = c.Started
+		}
+	}
+	sort.Strings(names)
+	statistics := make([]containerStatistic, 0, len(names))
+	for _, name := range names {
+		s := byName[name]
+		s.average /= float64(s.services)
+		statistics = append(statistics, *s)
+	}
+	return statistics
+}
+
+// writeContainers writes the average and the maximum start of every container of the multi-container revisions
+func writeContainers(w io.Writer, containers []pkg.ContainerStarted, options render.Options) {
+	rows := []*render.Row{}
+	for _, s := range containerStatistics(containers) {
+		rows = append(rows, &render.Row{Name: s.container, Values: []string{strconv.Itoa(s.services), seconds(s.average), seconds(s.max)}, Bar: -1})
+	}
+	render.Table(w, []string{"CONTAINER STARTED", "SERVICES", "AVERAGE", "MAX"}, rows, options)
+	fmt.Fprintf(w, "\n")
+}
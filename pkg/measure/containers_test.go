@@ -0,0 +1,44 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestContainerStatistics(t *testing.T) {
+	containers := []pkg.ContainerStarted{
+		{ServiceName: "ksvc-2", ServiceNamespace: "ns-1", Container: "sidecar", Started: 3},
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "sidecar", Started: 1},
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "app", Serving: true, Started: 2},
+	}
+	assert.DeepEqual(t, []containerStatistic{
+		{container: "app", services: 1, average: 2, max: 2},
+		{container: "sidecar", services: 2, average: 2, max: 3},
+	}, containerStatistics(containers), cmp.AllowUnexported(containerStatistic{}))
+
+	sortContainers(containers)
+	assert.DeepEqual(t, [][]string{
+		{"svc_name", "svc_namespace", "container", "serving", "started"},
+		{"ksvc-1", "ns-1", "app", "true", "2.000000"},
+		{"ksvc-1", "ns-1", "sidecar", "false", "1.000000"},
+		{"ksvc-2", "ns-1", "sidecar", "false", "3.000000"},
+	}, ContainerRows(containers))
+}
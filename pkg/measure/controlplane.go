@@ -0,0 +1,333 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"knative.dev/kperf/pkg"
+)
+
+const (
+	// DefaultControlPlaneNamespace is the namespace of the Knative Serving control plane
+	DefaultControlPlaneNamespace = "knative-serving"
+	// DefaultControlPlaneMetricsPort is the port the Knative Serving components serve their Prometheus metrics on
+	DefaultControlPlaneMetricsPort = 9090
+
+	// controlPlaneSelector selects the pods of the profiled Knative Serving components by their app label
+	controlPlaneSelector = "app in (controller,autoscaler,activator,webhook)"
+)
+
+// podMetricsResource is the PodMetrics resource of metrics-server, read with the dynamic client since its typed client
+// is not a dependency of kperf
+var podMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// ControlPlaneProfiler samples the resource usage and the Prometheus metrics of the Knative Serving control plane pods
+type ControlPlaneProfiler struct {
+	params *pkg.PerfParams
+	args   pkg.ControlPlaneProfileArgs
+	logger Logger
+
+	pods map[string]*pkg.ControlPlanePod
+	// warned holds the failures which were logged already, so that a missing metrics-server or metrics endpoint is
+	// reported once and not with every sample
+	warned map[string]bool
+}
+
+// NewControlPlaneProfiler returns a profiler of the control plane pods in the namespace of the args
+func NewControlPlaneProfiler(params *pkg.PerfParams, args pkg.ControlPlaneProfileArgs, logger Logger) *ControlPlaneProfiler {
+	return &ControlPlaneProfiler{
+		params: params,
+		args:   args,
+		logger: logger,
+		pods:   map[string]*pkg.ControlPlanePod{},
+		warned: map[string]bool{},
+	}
+}
+
+// StartControlPlaneProfiler samples the control plane pods every interval of the args until the returned function is
+// called, which stops the sampling and returns the profile
+func StartControlPlaneProfiler(ctx context.Context, params *pkg.PerfParams, args pkg.ControlPlaneProfileArgs, logger Logger) func() *pkg.ControlPlaneProfile {
+	profiler := NewControlPlaneProfiler(params, args, logger)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			if err := profiler.Sample(ctx); err != nil && ctx.Err() == nil {
+				profiler.warn("pods", "failed to profile the control plane: %s\n", err)
+			}
+		}, args.Interval)
+	}()
+	return func() *pkg.ControlPlaneProfile {
+		cancel()
+		<-done
+		return profiler.Profile()
+	}
+}
+
+// Sample adds a sample of every control plane pod, the resource usage and the metrics are left out of the samples
+// if they can't be read
+func (c *ControlPlaneProfiler) Sample(ctx context.Context) error {
+	now := time.Now()
+	pods, err := c.params.ClientSet.CoreV1().Pods(c.args.Namespace).List(ctx, metav1.ListOptions{LabelSelector: controlPlaneSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list the pods in namespace %s: %w", c.args.Namespace, err)
+	}
+	usage, err := c.podUsage(ctx)
+	if err != nil && ctx.Err() == nil {
+		c.warn("usage", "failed to get the resource usage of the control plane pods, is metrics-server installed? %s\n", err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		sample := pkg.ControlPlaneSample{Time: now}
+		if u, ok := usage[pod.Name]; ok {
+			sample.CPUMillicores = &u.cpuMillicores
+			sample.MemoryBytes = &u.memoryBytes
+		}
+		if len(c.args.Metrics) > 0 {
+			sample.Metrics, err = c.scrape(ctx, pod.Name)
+			if err != nil && ctx.Err() == nil {
+				c.warn("metrics/"+pod.Name, "failed to scrape the metrics of pod %s: %s\n", pod.Name, err)
+			}
+		}
+		p, ok := c.pods[pod.Name]
+		if !ok {
+			p = &pkg.ControlPlanePod{Name: pod.Name, Component: pod.Labels["app"]}
+			c.pods[pod.Name] = p
+		}
+		p.Samples = append(p.Samples, sample)
+	}
+	return nil
+}
+
+// Profile returns the samples taken so far, the pods are sorted by component and name
+func (c *ControlPlaneProfiler) Profile() *pkg.ControlPlaneProfile {
+	profile := &pkg.ControlPlaneProfile{
+		Namespace: c.args.Namespace,
+		Interval:  c.args.Interval.Seconds(),
+		Pods:      []pkg.ControlPlanePod{},
+	}
+	for _, p := range c.pods {
+		profile.Pods = append(profile.Pods, *p)
+	}
+	sort.Slice(profile.Pods, func(i, j int) bool {
+		if profile.Pods[i].Component != profile.Pods[j].Component {
+			return profile.Pods[i].Component < profile.Pods[j].Component
+		}
+		return profile.Pods[i].Name < profile.Pods[j].Name
+	})
+	return profile
+}
+
+// podUsage is the resource usage of the containers of a pod
+type podUsage struct {
+	cpuMillicores int64
+	memoryBytes   int64
+}
+
+// podUsage returns the resource usage of the control plane pods reported by metrics-server, by pod name
+func (c *ControlPlaneProfiler) podUsage(ctx context.Context) (map[string]podUsage, error) {
+	dynamicClient, err := c.params.NewDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	list, err := dynamicClient.Resource(podMetricsResource).Namespace(c.args.Namespace).List(ctx, metav1.ListOptions{LabelSelector: controlPlaneSelector})
+	if err != nil {
+		return nil, err
+	}
+	usages := map[string]podUsage{}
+	for _, item := range list.Items {
+		containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the usage of pod %s: %w", item.GetName(), err)
+		}
+		usage := podUsage{}
+		for _, container := range containers {
+			values, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			cpu, err := usageQuantity(values, "cpu")
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the usage of pod %s: %w", item.GetName(), err)
+			}
+			memory, err := usageQuantity(values, "memory")
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the usage of pod %s: %w", item.GetName(), err)
+			}
+			usage.cpuMillicores += cpu.MilliValue()
+			usage.memoryBytes += memory.Value()
+		}
+		usages[item.GetName()] = usage
+	}
+	return usages, nil
+}
+
+// usageQuantity returns the usage of the resource by a container of PodMetrics, zero if it isn't reported
+func usageQuantity(container map[string]interface{}, name string) (resource.Quantity, error) {
+	value, _, _ := unstructured.NestedString(container, "usage", name)
+	if value == "" {
+		return resource.Quantity{}, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid %s usage %q: %w", name, value, err)
+	}
+	return quantity, nil
+}
+
+// scrape reads the Prometheus metrics of the pod through the API server proxy
+func (c *ControlPlaneProfiler) scrape(ctx context.Context, pod string) (map[string]float64, error) {
+	data, err := c.params.ClientSet.CoreV1().Pods(c.args.Namespace).ProxyGet("http", pod, strconv.Itoa(c.args.MetricsPort), "metrics", nil).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrometheusMetrics(bytes.NewReader(data), c.args.Metrics)
+}
+
+func (c *ControlPlaneProfiler) warn(key, format string, v ...interface{}) {
+	if c.warned[key] {
+		return
+	}
+	c.warned[key] = true
+	c.logger.Printf(format, v...)
+}
+
+// parsePrometheusMetrics reads the metrics with the names from the Prometheus text format, the samples of a metric
+// with different labels are summed up, e.g. the depth of all work queues
+func parsePrometheusMetrics(r io.Reader, names []string) (map[string]float64, error) {
+	samples, err := parsePrometheusSamples(r, names)
+	if err != nil {
+		return nil, err
+	}
+	metrics := map[string]float64{}
+	for _, sample := range samples {
+		metrics[sample.name] += sample.value
+	}
+	return metrics, nil
+}
+
+// prometheusSample is a sample of a metric with its labels
+type prometheusSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+}
+
+// parsePrometheusSamples reads the samples of the metrics with the names from the Prometheus text format
+func parsePrometheusSamples(r io.Reader, names []string) ([]prometheusSample, error) {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+	samples := []prometheusSample{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, rest := line, ""
+		if i := strings.IndexAny(line, "{ "); i >= 0 {
+			name, rest = line[:i], line[i:]
+		}
+		if !wanted[name] {
+			continue
+		}
+		sample := prometheusSample{name: name, labels: map[string]string{}}
+		if strings.HasPrefix(rest, "{") {
+			// label values are quoted and may contain spaces and escaped quotes, the value follows the closing brace
+			var err error
+			sample.labels, rest, err = parsePrometheusLabels(rest[1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid sample %q: %w", line, err)
+			}
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid sample %q", line)
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sample %q: %w", line, err)
+		}
+		sample.value = value
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
+
+// parsePrometheusLabels reads the labels like name="value",other="a \"quoted\" value"} following the opening brace
+// of a sample, it returns them with the rest of the line after the closing brace
+func parsePrometheusLabels(s string) (map[string]string, string, error) {
+	labels := map[string]string{}
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+		i := strings.Index(s, "=")
+		if i <= 0 || len(s) < i+2 || s[i+1] != '"' {
+			return nil, "", fmt.Errorf("expected label like name=\"value\"")
+		}
+		name := strings.TrimSpace(s[:i])
+		s = s[i+2:]
+		var value strings.Builder
+		closed := false
+		for j := 0; j < len(s); j++ {
+			c := s[j]
+			if c == '\\' && j+1 < len(s) {
+				j++
+				switch s[j] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[j])
+				}
+				continue
+			}
+			if c == '"' {
+				s = s[j+1:]
+				closed = true
+				break
+			}
+			value.WriteByte(c)
+		}
+		if !closed {
+			return nil, "", fmt.Errorf("unterminated value of label %s", name)
+		}
+		labels[name] = value.String()
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/kperf/pkg"
+)
+
+// fakeProxyResponse is the response of a pod proxy request in the fake clientset
+type fakeProxyResponse struct {
+	data string
+	err  error
+}
+
+func (r fakeProxyResponse) DoRaw(context.Context) ([]byte, error) {
+	return []byte(r.data), r.err
+}
+
+func (r fakeProxyResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(r.data)), r.err
+}
+
+func newControlPlanePod(name, app string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: DefaultControlPlaneNamespace, Labels: map[string]string{"app": app}},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newPodMetrics(name, app string, usage ...map[string]interface{}) *unstructured.Unstructured {
+	containers := []interface{}{}
+	for _, u := range usage {
+		containers = append(containers, map[string]interface{}{"name": "container", "usage": u})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": DefaultControlPlaneNamespace,
+			"labels":    map[string]interface{}{"app": app},
+		},
+		"containers": containers,
+	}}
+}
+
+func newControlPlaneParams(t *testing.T, podMetrics []*unstructured.Unstructured, pods ...runtime.Object) (*pkg.PerfParams, *k8sfake.Clientset) {
+	client := k8sfake.NewSimpleClientset(pods...)
+	fakeDynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podMetricsResource: "PodMetricsList",
+	})
+	// the resource of PodMetrics is pods, which can't be guessed from the kind
+	for _, m := range podMetrics {
+		assert.NilError(t, fakeDynamic.Tracker().Create(podMetricsResource, m, m.GetNamespace()))
+	}
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewDynamicClient: func() (dynamic.Interface, error) {
+			return fakeDynamic, nil
+		},
+	}, client
+}
+
+func TestControlPlaneProfiler(t *testing.T) {
+	args := pkg.ControlPlaneProfileArgs{
+		Enabled:     true,
+		Namespace:   DefaultControlPlaneNamespace,
+		Interval:    time.Second,
+		MetricsPort: DefaultControlPlaneMetricsPort,
+	}
+
+	t.Run("sample the resource usage of the control plane pods", func(t *testing.T) {
+		p, _ := newControlPlaneParams(t, []*unstructured.Unstructured{
+			newPodMetrics("controller-1", "controller", map[string]interface{}{"cpu": "250m", "memory": "64Mi"}),
+			newPodMetrics("activator-1", "activator",
+				map[string]interface{}{"cpu": "100m", "memory": "32Mi"},
+				map[string]interface{}{"cpu": "1", "memory": "16Mi"}),
+		},
+			newControlPlanePod("controller-1", "controller", corev1.PodRunning),
+			newControlPlanePod("activator-1", "activator", corev1.PodRunning),
+			newControlPlanePod("webhook-1", "webhook", corev1.PodRunning),
+			newControlPlanePod("activator-2", "activator", corev1.PodPending),
+			newControlPlanePod("domain-mapping-1", "domain-mapping", corev1.PodRunning),
+		)
+
+		profiler := NewControlPlaneProfiler(p, args, DefaultLogger)
+		assert.NilError(t, profiler.Sample(context.TODO()))
+		assert.NilError(t, profiler.Sample(context.TODO()))
+		profile := profiler.Profile()
+
+		assert.Equal(t, DefaultControlPlaneNamespace, profile.Namespace)
+		assert.Equal(t, 1.0, profile.Interval)
+		assert.Equal(t, 3, len(profile.Pods))
+		activator := profile.Pods[0]
+		assert.Equal(t, "activator-1", activator.Name)
+		assert.Equal(t, "activator", activator.Component)
+		assert.Equal(t, 2, len(activator.Samples))
+		assert.Equal(t, int64(1100), *activator.Samples[0].CPUMillicores)
+		assert.Equal(t, int64(48*1024*1024), *activator.Samples[0].MemoryBytes)
+		assert.Equal(t, "controller-1", profile.Pods[1].Name)
+		assert.Equal(t, int64(250), *profile.Pods[1].Samples[1].CPUMillicores)
+		// metrics-server doesn't report pods which were just started
+		assert.Equal(t, "webhook-1", profile.Pods[2].Name)
+		assert.Check(t, profile.Pods[2].Samples[0].CPUMillicores == nil)
+		assert.Check(t, profile.Pods[2].Samples[0].MemoryBytes == nil)
+	})
+
+	t.Run("scrape the prometheus metrics of the control plane pods", func(t *testing.T) {
+		p, client := newControlPlaneParams(t, nil,
+			newControlPlanePod("controller-1", "controller", corev1.PodRunning),
+			newControlPlanePod("autoscaler-1", "autoscaler", corev1.PodRunning),
+		)
+		// without metrics-server only the metrics are sampled
+		p.NewDynamicClient = func() (dynamic.Interface, error) {
+			return nil, errors.New("the server could not find the requested resource")
+		}
+		var ports []string
+		client.PrependProxyReactor("pods", func(action clienttesting.Action) (bool, restclient.ResponseWrapper, error) {
+			proxy := action.(clienttesting.ProxyGetAction)
+			ports = append(ports, proxy.GetPort())
+			if proxy.GetName() == "autoscaler-1" {
+				return true, fakeProxyResponse{err: errors.New("connection refused")}, nil
+			}
+			return true, fakeProxyResponse{data: "workqueue_depth{name=\"route\"} 3\nworkqueue_depth{name=\"revision\"} 4\n"}, nil
+		})
+
+		metricsArgs := args
+		metricsArgs.Metrics = []string{"workqueue_depth", "go_goroutines"}
+		var logs bytes.Buffer
+		profiler := NewControlPlaneProfiler(p, metricsArgs, log.New(&logs, "", 0))
+		assert.NilError(t, profiler.Sample(context.TODO()))
+		assert.NilError(t, profiler.Sample(context.TODO()))
+		profile := profiler.Profile()
+
+		assert.DeepEqual(t, []string{"9090", "9090", "9090", "9090"}, ports)
+		assert.Equal(t, "autoscaler-1", profile.Pods[0].Name)
+		assert.Check(t, profile.Pods[0].Samples[0].Metrics == nil)
+		assert.Check(t, profile.Pods[1].Samples[0].CPUMillicores == nil)
+		assert.DeepEqual(t, map[string]float64{"workqueue_depth": 7}, profile.Pods[1].Samples[0].Metrics)
+		// the failures are logged once
+		assert.Equal(t, 1, strings.Count(logs.String(), "failed to scrape the metrics of pod autoscaler-1: connection refused"))
+		assert.Equal(t, 1, strings.Count(logs.String(), "is metrics-server installed?"))
+	})
+
+	t.Run("sample until stopped", func(t *testing.T) {
+		p, _ := newControlPlaneParams(t, nil, newControlPlanePod("controller-1", "controller", corev1.PodRunning))
+		intervalArgs := args
+		intervalArgs.Interval = 10 * time.Millisecond
+
+		stop := StartControlPlaneProfiler(context.TODO(), p, intervalArgs, DefaultLogger)
+		time.Sleep(50 * time.Millisecond)
+		profile := stop()
+		assert.Equal(t, 1, len(profile.Pods))
+		assert.Check(t, len(profile.Pods[0].Samples) >= 2, "expected several samples, got %d", len(profile.Pods[0].Samples))
+	})
+}
+
+func TestParsePrometheusMetrics(t *testing.T) {
+	text := `# HELP workqueue_depth Current depth of workqueue
+# TYPE workqueue_depth gauge
+workqueue_depth{name="route"} 3
+workqueue_depth{name="revision with space"} 4 1395066363000
+workqueue_depth_total 100
+go_goroutines 42
+
+process_cpu_seconds_total 1.5e+01
+`
+	metrics, err := parsePrometheusMetrics(strings.NewReader(text), []string{"workqueue_depth", "go_goroutines", "process_cpu_seconds_total", "missing"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]float64{"workqueue_depth": 7, "go_goroutines": 42, "process_cpu_seconds_total": 15}, metrics)
+
+	_, err = parsePrometheusMetrics(strings.NewReader("go_goroutines many\n"), []string{"go_goroutines"})
+	assert.ErrorContains(t, err, `invalid sample "go_goroutines many"`)
+}
+
+func TestParsePrometheusSamples(t *testing.T) {
+	text := `autoscaler_desired_pods{namespace_name="ns-1",revision_name="ksvc-1-00001", service_name="ksvc-1"} 3
+autoscaler_desired_pods{} 1
+workqueue_depth{name="a \"quoted\", value}"} 2
+`
+	samples, err := parsePrometheusSamples(strings.NewReader(text), []string{"autoscaler_desired_pods", "workqueue_depth"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []prometheusSample{
+		{name: "autoscaler_desired_pods", labels: map[string]string{"namespace_name": "ns-1", "revision_name": "ksvc-1-00001", "service_name": "ksvc-1"}, value: 3},
+		{name: "autoscaler_desired_pods", labels: map[string]string{}, value: 1},
+		{name: "workqueue_depth", labels: map[string]string{"name": `a "quoted", value}`}, value: 2},
+	}, samples, cmp.AllowUnexported(prometheusSample{}))
+
+	_, err = parsePrometheusSamples(strings.NewReader(`workqueue_depth{name="route} 3`+"\n"), []string{"workqueue_depth"})
+	assert.ErrorContains(t, err, "unterminated value of label name")
+	_, err = parsePrometheusSamples(strings.NewReader(`workqueue_depth{name} 3`+"\n"), []string{"workqueue_depth"})
+	assert.ErrorContains(t, err, "expected label like name=")
+}
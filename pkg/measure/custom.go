@@ -0,0 +1,74 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ExtraMetrics returns custom metrics of the measurement from start to end, e.g. the latency of a database during the
+// run, as JSON object which is merged into the custom metrics of the summary
+type ExtraMetrics func(ctx context.Context, start, end time.Time) (map[string]interface{}, error)
+
+// CommandMetrics returns ExtraMetrics which run the shell command with sh and parse the JSON object it writes to
+// stdout. The start and end of the measurement are passed in the environment variables KPERF_RUN_START and
+// KPERF_RUN_END in RFC3339, additionally to env.
+func CommandMetrics(command string, env ...string) ExtraMetrics {
+	return func(ctx context.Context, start, end time.Time) (map[string]interface{}, error) {
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = append(append(os.Environ(), env...),
+			"KPERF_RUN_START="+start.UTC().Format(time.RFC3339),
+			"KPERF_RUN_END="+end.UTC().Format(time.RFC3339),
+		)
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("extra metrics command %q failed: %s %s", command, err, strings.TrimSpace(stderr.String()))
+		}
+		metrics := map[string]interface{}{}
+		if err := json.Unmarshal(stdout.Bytes(), &metrics); err != nil {
+			return nil, fmt.Errorf("extra metrics command %q didn't write a JSON object: %s", command, err)
+		}
+		return metrics, nil
+	}
+}
+
+// collectExtraMetrics merges the metrics of all ExtraMetrics of the Measurer, the ones of later ExtraMetrics replace
+// the ones with the same key. Failing ExtraMetrics are logged and skipped, so that they don't lose the measurement.
+func (m *Measurer) collectExtraMetrics(ctx context.Context, start, end time.Time) map[string]interface{} {
+	var custom map[string]interface{}
+	for _, extra := range m.ExtraMetrics {
+		metrics, err := extra(ctx, start, end)
+		if err != nil {
+			m.logger.Printf("failed to collect extra metrics and skip them %s\n", err)
+			continue
+		}
+		for key, value := range metrics {
+			if custom == nil {
+				custom = map[string]interface{}{}
+			}
+			custom[key] = value
+		}
+	}
+	return custom
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCommandMetrics(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+
+	t.Run("parse the JSON object of the command", func(t *testing.T) {
+		metrics, err := CommandMetrics(`echo "{\"db\": {\"p95\": 0.25}, \"window\": \"$KPERF_RUN_START/$KPERF_RUN_END\", \"run\": \"$KPERF_RUN_ID\"}"`,
+			"KPERF_RUN_ID=demo")(context.Background(), start, end)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]interface{}{
+			"db":     map[string]interface{}{"p95": 0.25},
+			"window": "2022-01-01T00:00:00Z/2022-01-01T00:01:00Z",
+			"run":    "demo",
+		}, metrics)
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		_, err := CommandMetrics("echo broken >&2; exit 3")(context.Background(), start, end)
+		assert.ErrorContains(t, err, `extra metrics command "echo broken >&2; exit 3" failed: exit status 3 broken`)
+	})
+
+	t.Run("no JSON object", func(t *testing.T) {
+		_, err := CommandMetrics("echo '[1, 2]'")(context.Background(), start, end)
+		assert.ErrorContains(t, err, `extra metrics command "echo '[1, 2]'" didn't write a JSON object`)
+	})
+}
+
+func TestCollectExtraMetrics(t *testing.T) {
+	p, _ := newMeasureTestParams()
+	logs := &bytes.Buffer{}
+	measurer := NewMeasurer(p, nil, log.New(logs, "", 0))
+	assert.Assert(t, measurer.collectExtraMetrics(context.Background(), time.Time{}, time.Time{}) == nil)
+
+	measurer.ExtraMetrics = []ExtraMetrics{
+		func(ctx context.Context, start, end time.Time) (map[string]interface{}, error) {
+			return map[string]interface{}{"db": 1.0, "cache": 2.0}, nil
+		},
+		func(ctx context.Context, start, end time.Time) (map[string]interface{}, error) {
+			return nil, errors.New("connection refused")
+		},
+		func(ctx context.Context, start, end time.Time) (map[string]interface{}, error) {
+			return map[string]interface{}{"db": 3.0}, nil
+		},
+	}
+	custom := measurer.collectExtraMetrics(context.Background(), time.Time{}, time.Time{})
+	assert.DeepEqual(t, map[string]interface{}{"db": 3.0, "cache": 2.0}, custom)
+	assert.Assert(t, strings.Contains(logs.String(), "failed to collect extra metrics and skip them connection refused"))
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"math"
+	"sort"
+
+	"knative.dev/kperf/pkg"
+)
+
+// readyDistribution counts how often every overall ready duration was measured. The durations are truncated to the
+// precision of the Measurer, so the statistics of any number of services are computed exactly from a few hundred, or
+// with milliseconds a few hundred thousand, distinct values instead of keeping the duration of every service.
+type readyDistribution struct {
+	counts map[float64]int
+	total  int
+	// sorted are the distinct durations in ascending order, they are sorted again after a duration was added
+	sorted []float64
+}
+
+func newReadyDistribution() *readyDistribution {
+	return &readyDistribution{counts: map[float64]int{}}
+}
+
+// add counts a measured duration
+func (d *readyDistribution) add(duration float64) {
+	if d.counts[duration] == 0 {
+		d.sorted = nil
+	}
+	d.counts[duration]++
+	d.total++
+}
+
+// nth returns the i-th smallest duration counting from 0, like the sorted durations of every service would
+func (d *readyDistribution) nth(i int) float64 {
+	if d.sorted == nil {
+		for duration := range d.counts {
+			d.sorted = append(d.sorted, duration)
+		}
+		sort.Float64s(d.sorted)
+	}
+	for _, duration := range d.sorted {
+		if i < d.counts[duration] {
+			return duration
+		}
+		i -= d.counts[duration]
+	}
+	return math.NaN()
+}
+
+// median returns the median like stats.Median
+func (d *readyDistribution) median() float64 {
+	if d.total%2 == 0 {
+		return (d.nth(d.total/2-1) + d.nth(d.total/2)) / 2
+	}
+	return d.nth(d.total / 2)
+}
+
+// percentile returns the percentile like stats.Percentile
+func (d *readyDistribution) percentile(percent float64) float64 {
+	if d.total == 1 {
+		return d.nth(0)
+	}
+	index := percent / 100 * float64(d.total)
+	if index == float64(int64(index)) {
+		return d.nth(int(index) - 1)
+	}
+	if index > 1 {
+		return (d.nth(int(index)-1) + d.nth(int(index))) / 2
+	}
+	return math.NaN()
+}
+
+// summarize sets the statistics of the overall ready duration of the result
+func (d *readyDistribution) summarize(result *pkg.Result) {
+	if d.total == 0 {
+		return
+	}
+	result.OverallMedian = d.median()
+	result.OverallMin = d.nth(0)
+	result.OverallMax = d.nth(d.total - 1)
+	result.P50 = d.percentile(50)
+	result.P90 = d.percentile(90)
+	result.P95 = d.percentile(95)
+	result.P98 = d.percentile(98)
+	result.P99 = d.percentile(99)
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/montanaflynn/stats"
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestReadyDistribution(t *testing.T) {
+	// the statistics are the same as the ones of every duration, for odd and even numbers of services
+	random := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 10, 101, 1000} {
+		d := newReadyDistribution()
+		durations := make([]float64, 0, n)
+		for i := 0; i < n; i++ {
+			duration := float64(random.Intn(60))
+			d.add(duration)
+			durations = append(durations, duration)
+		}
+		expected := pkg.MeasureResult{SvcReadyTime: durations}
+		expected.Service.ReadyCount = n
+		summarize(&expected)
+		var result pkg.Result
+		d.summarize(&result)
+		assert.Equal(t, expected.Result.OverallMedian, result.OverallMedian, "n=%d", n)
+		assert.Equal(t, expected.Result.OverallMin, result.OverallMin, "n=%d", n)
+		assert.Equal(t, expected.Result.OverallMax, result.OverallMax, "n=%d", n)
+		for _, p := range []struct{ percent, expected, actual float64 }{
+			{50, expected.Result.P50, result.P50},
+			{90, expected.Result.P90, result.P90},
+			{95, expected.Result.P95, result.P95},
+			{98, expected.Result.P98, result.P98},
+			{99, expected.Result.P99, result.P99},
+		} {
+			assert.Equal(t, p.expected, p.actual, "n=%d p%v", n, p.percent)
+		}
+	}
+
+	// durations added after the statistics were computed are counted
+	d := newReadyDistribution()
+	d.add(2)
+	assert.Equal(t, 2.0, d.nth(0))
+	d.add(1)
+	median, _ := stats.Median([]float64{2, 1})
+	assert.Equal(t, median, d.median())
+	assert.Equal(t, 1.0, d.nth(0))
+
+	// nothing is set without durations
+	var result pkg.Result
+	newReadyDistribution().summarize(&result)
+	assert.DeepEqual(t, pkg.Result{}, result)
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"knative.dev/kperf/pkg"
+)
+
+// collectEvents collects the Events of a resource created for the service in trace if the Measurer collects them.
+// Events which can't be listed are logged and don't affect the measurement of the service.
+func (m *Measurer) collectEvents(ctx context.Context, trace *serviceTrace, kind, name string) {
+	if !m.CollectEvents {
+		return
+	}
+	selector := fields.Set{"involvedObject.kind": kind, "involvedObject.name": name}.AsSelector().String()
+	var eventList *corev1.EventList
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		eventList, err = m.params.ClientSet.CoreV1().Events(trace.service.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.logger.Printf("failed to list Events of %s %s and skip them %s\n", kind, name, err)
+		return
+	}
+	for i := range eventList.Items {
+		e := &eventList.Items[i]
+		// the events are matched again in case the field selector is ignored by the client
+		if e.InvolvedObject.Kind != kind || e.InvolvedObject.Name != name {
+			continue
+		}
+		trace.events = append(trace.events, serviceEvent(trace, e))
+	}
+}
+
+// serviceEvent converts an Event of a resource of the traced service. Events recorded with the events.k8s.io API
+// only set the event time and their series instead of the first and last timestamp and the count.
+func serviceEvent(trace *serviceTrace, e *corev1.Event) pkg.ServiceEvent {
+	first := firstTime(e)
+	last := e.LastTimestamp.Time
+	count := e.Count
+	if e.Series != nil {
+		if last.IsZero() {
+			last = e.Series.LastObservedTime.Time
+		}
+		if count == 0 {
+			count = e.Series.Count
+		}
+	}
+	if last.IsZero() {
+		last = first
+	}
+	if count == 0 {
+		count = 1
+	}
+	return pkg.ServiceEvent{
+		ServiceName:      trace.service.Name,
+		ServiceNamespace: trace.service.Namespace,
+		Kind:             e.InvolvedObject.Kind,
+		Name:             e.InvolvedObject.Name,
+		Type:             e.Type,
+		Reason:           e.Reason,
+		Message:          e.Message,
+		Count:            count,
+		FirstTime:        first.UTC(),
+		LastTime:         last.UTC(),
+	}
+}
+
+// firstTime returns when the Event was first observed, the event time for Events recorded with the events.k8s.io API
+func firstTime(e *corev1.Event) time.Time {
+	if e.FirstTimestamp.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.FirstTimestamp.Time
+}
+
+// sortServiceEvents sorts the events by service and the events of a service by the time they were first observed
+func sortServiceEvents(events []pkg.ServiceEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].ServiceNamespace != events[j].ServiceNamespace {
+			return events[i].ServiceNamespace < events[j].ServiceNamespace
+		}
+		if events[i].ServiceName != events[j].ServiceName {
+			return events[i].ServiceName < events[j].ServiceName
+		}
+		return events[i].FirstTime.Before(events[j].FirstTime)
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/kperf/pkg"
+)
+
+func newEvent(name, kind, object, reason string, first time.Time, count int32) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "ns-1"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object, Namespace: "ns-1"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        reason + " of " + object,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(first.Add(time.Duration(count-1) * time.Second)),
+	}
+}
+
+func TestCollectEvents(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:              "ksvc-1-00001-deployment",
+		Namespace:         "ns-1",
+		CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+	}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "ksvc-1-00001-deployment-abc",
+		Namespace: "ns-1",
+		Labels:    map[string]string{"serving.knative.dev/revision": "ksvc-1-00001"},
+	}}
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+	}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(created)}}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "queue-proxy", State: running},
+		{Name: "user-container", State: running},
+	}
+	p, fake := newMeasureTestParams(deployment, pod,
+		newEvent("pull", "Pod", "ksvc-1-00001-deployment-abc", "BackOff", created.Add(2*time.Second), 3),
+		newEvent("scheduling", "Pod", "ksvc-1-00001-deployment-abc", "FailedScheduling", created.Add(time.Second), 1),
+		newEvent("revision", "Revision", "ksvc-1-00001", "ProgressDeadlineExceeded", created.Add(3*time.Second), 1),
+		// the events of other resources are not collected
+		newEvent("other", "Pod", "ksvc-2-00001-deployment-abc", "BackOff", created, 1))
+	fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, newReadyService("ksvc-1", "ns-1", created), nil
+	})
+	prependReadyReactors(fake, created)
+
+	measurer := NewMeasurer(p, nil, nil)
+	measurer.CollectEvents = true
+	result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+	reasons := []string{}
+	for _, e := range result.Events {
+		assert.Equal(t, "ksvc-1", e.ServiceName)
+		assert.Equal(t, "ns-1", e.ServiceNamespace)
+		reasons = append(reasons, e.Kind+"/"+e.Reason)
+	}
+	// ordered by the time they were first observed
+	assert.DeepEqual(t, []string{"Pod/FailedScheduling", "Pod/BackOff", "Revision/ProgressDeadlineExceeded"}, reasons)
+	assert.Equal(t, int32(3), result.Events[1].Count)
+	assert.Equal(t, created.Add(4*time.Second), result.Events[1].LastTime)
+
+	// the events are listed by the kind and name of the resource
+	selectors := []string{}
+	for _, action := range p.ClientSet.(*k8sfake.Clientset).Actions() {
+		if action.Matches("list", "events") {
+			selectors = append(selectors, action.(clienttesting.ListAction).GetListRestrictions().Fields.String())
+		}
+	}
+	assert.DeepEqual(t, []string{
+		"involvedObject.kind=Revision,involvedObject.name=ksvc-1-00001",
+		"involvedObject.kind=Pod,involvedObject.name=ksvc-1-00001-deployment-abc",
+		"involvedObject.kind=Deployment,involvedObject.name=ksvc-1-00001-deployment",
+	}, selectors)
+
+	t.Run("nothing is collected by default", func(t *testing.T) {
+		result, err := NewMeasurer(p, nil, nil).Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 0, len(result.Events))
+	})
+
+	t.Run("events which can't be listed don't affect the measurement", func(t *testing.T) {
+		client := p.ClientSet.(*k8sfake.Clientset)
+		client.PrependReactor("list", "events", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+		logs := &bytes.Buffer{}
+		measurer := NewMeasurer(p, nil, log.New(logs, "", 0))
+		measurer.CollectEvents = true
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+		assert.Equal(t, 0, len(result.Events))
+		assert.Assert(t, strings.Contains(logs.String(), "failed to list Events of Revision ksvc-1-00001 and skip them forbidden"))
+	})
+}
+
+func TestServiceEvent(t *testing.T) {
+	trace := &serviceTrace{service: types.NamespacedName{Namespace: "ns-1", Name: "ksvc-1"}}
+	observed := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	// events of the events.k8s.io API only have an event time and a series
+	e := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "pod-1"},
+		Type:           corev1.EventTypeNormal,
+		Reason:         "Pulling",
+		EventTime:      metav1.NewMicroTime(observed),
+		Series:         &corev1.EventSeries{Count: 4, LastObservedTime: metav1.NewMicroTime(observed.Add(time.Minute))},
+	}
+	assert.DeepEqual(t, pkg.ServiceEvent{
+		ServiceName:      "ksvc-1",
+		ServiceNamespace: "ns-1",
+		Kind:             "Pod",
+		Name:             "pod-1",
+		Type:             corev1.EventTypeNormal,
+		Reason:           "Pulling",
+		Count:            4,
+		FirstTime:        observed,
+		LastTime:         observed.Add(time.Minute),
+	}, serviceEvent(trace, e))
+
+	// a single event was observed once at its event time
+	e.Series = nil
+	converted := serviceEvent(trace, e)
+	assert.Equal(t, int32(1), converted.Count)
+	assert.Equal(t, observed, converted.LastTime)
+}
+
+func TestSortServiceEvents(t *testing.T) {
+	at := func(s int) time.Time {
+		return time.Date(2022, 1, 1, 0, 0, s, 0, time.UTC)
+	}
+	events := []pkg.ServiceEvent{
+		{ServiceNamespace: "ns-2", ServiceName: "ksvc-1", Reason: "a", FirstTime: at(0)},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-1", Reason: "b", FirstTime: at(2)},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-1", Reason: "c", FirstTime: at(1)},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-0", Reason: "d", FirstTime: at(3)},
+	}
+	sortServiceEvents(events)
+	reasons := ""
+	for _, e := range events {
+		reasons += e.Reason
+	}
+	assert.Equal(t, "dcba", reasons)
+}
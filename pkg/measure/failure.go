@@ -0,0 +1,79 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+)
+
+// sortFailures sorts the failures by namespace and name of the service
+func sortFailures(failures []pkg.ServiceFailure) {
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].ServiceNamespace != failures[j].ServiceNamespace {
+			return failures[i].ServiceNamespace < failures[j].ServiceNamespace
+		}
+		return failures[i].ServiceName < failures[j].ServiceName
+	})
+}
+
+// FailureReasonCount is the number of services which couldn't be measured for a reason
+type FailureReasonCount struct {
+	Reason string
+	Count  int
+}
+
+// FailureReasons returns the number of services by failure reason, the most frequent reason first
+func FailureReasons(failures []pkg.ServiceFailure) []FailureReasonCount {
+	counts := map[string]int{}
+	for _, f := range failures {
+		counts[f.Reason]++
+	}
+	reasons := make([]FailureReasonCount, 0, len(counts))
+	for reason, count := range counts {
+		reasons = append(reasons, FailureReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if reasons[i].Count != reasons[j].Count {
+			return reasons[i].Count > reasons[j].Count
+		}
+		return reasons[i].Reason < reasons[j].Reason
+	})
+	return reasons
+}
+
+// FailureRows returns the rows of the CSV file of the failures with a header
+func FailureRows(failures []pkg.ServiceFailure) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "state", "reason", "message"}}
+	for _, f := range failures {
+		rows = append(rows, []string{f.ServiceName, f.ServiceNamespace, f.State, f.Reason, f.Message})
+	}
+	return rows
+}
+
+// writeFailureReasons writes the number of services by failure reason
+func writeFailureReasons(w io.Writer, failures []pkg.ServiceFailure, options render.Options) {
+	rows := []*render.Row{}
+	for _, r := range FailureReasons(failures) {
+		rows = append(rows, &render.Row{Name: r.Reason, Values: []string{strconv.Itoa(r.Count)}, Bar: -1, Style: render.Red})
+	}
+	render.Table(w, []string{"FAILURE REASON", "COUNT"}, rows, options)
+	fmt.Fprintf(w, "\n")
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"knative.dev/pkg/apis"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestFailureReasons(t *testing.T) {
+	failures := []pkg.ServiceFailure{
+		{ServiceName: "ksvc-2", ServiceNamespace: "ns-1", State: StateNotReady, Reason: pkg.FailureAPIError, Message: "failed to get Revision"},
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-2", State: StateNotReady, Reason: pkg.FailureNotReady},
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", State: StateNotReady, Reason: pkg.FailureNotReady},
+		{ServiceName: "ksvc-3", ServiceNamespace: "ns-1", State: StateTimedOut, Reason: pkg.FailureTimeout},
+	}
+	// the most frequent reason comes first, reasons with the same count are sorted by name
+	assert.DeepEqual(t, []FailureReasonCount{
+		{Reason: pkg.FailureNotReady, Count: 2},
+		{Reason: pkg.FailureAPIError, Count: 1},
+		{Reason: pkg.FailureTimeout, Count: 1},
+	}, FailureReasons(failures))
+
+	sortFailures(failures)
+	assert.DeepEqual(t, [][]string{
+		{"svc_name", "svc_namespace", "state", "reason", "message"},
+		{"ksvc-1", "ns-1", StateNotReady, pkg.FailureNotReady, ""},
+		{"ksvc-2", "ns-1", StateNotReady, pkg.FailureAPIError, "failed to get Revision"},
+		{"ksvc-3", "ns-1", StateTimedOut, pkg.FailureTimeout, ""},
+		{"ksvc-1", "ns-2", StateNotReady, pkg.FailureNotReady, ""},
+	}, FailureRows(failures))
+}
+
+func TestConditionCause(t *testing.T) {
+	assert.Equal(t, " (no Ready condition)", conditionCause(nil))
+	assert.Equal(t, "", conditionCause(&apis.Condition{}))
+	assert.Equal(t, " (RevisionMissing)", conditionCause(&apis.Condition{Reason: "RevisionMissing"}))
+	assert.Equal(t, " (RevisionFailed: image not found)", conditionCause(&apis.Condition{Reason: "RevisionFailed", Message: "image not found"}))
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	servingv1api "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/pool"
+)
+
+// Follow watches the services matching the selector in the namespace, or in all namespaces if it is empty, and
+// measures every service whose name matches as soon as it is ready, until the context is done. Only the services
+// created after CreatedAfter are measured, which defaults to the start of Follow, so that the services which
+// existed before are skipped. The record of every measured service is streamed like by Measure and passed to
+// measured, which is not called concurrently.
+func (m *Measurer) Follow(ctx context.Context, namespace, selector string, match func(name string) bool, measured func(pkg.MeasureRecord)) error {
+	c, err := m.newClients()
+	if err != nil {
+		return err
+	}
+	createdAfter := m.CreatedAfter
+	if createdAfter.IsZero() {
+		createdAfter = m.Clock.Now()
+	}
+	concurrency := m.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// the workers pass the records to a single goroutine, which writes and hands them to measured one after the other
+	records := make(chan pkg.MeasureRecord, concurrency)
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for record := range records {
+			if m.Verbose {
+				writeVerbose(m.out, record)
+			}
+			if m.Stream != nil {
+				if err := json.NewEncoder(m.Stream).Encode(record); err != nil {
+					m.logger.Printf("failed to stream record of service %s/%s: %s\n", record.ServiceNamespace, record.ServiceName, err)
+				}
+			}
+			if measured != nil {
+				measured(record)
+			}
+		}
+	}()
+	// a service is only submitted once a worker is free, so that the watch isn't read ahead of the measurement
+	workers := pool.New(ctx, concurrency, 0)
+	defer func() {
+		workers.Wait()
+		close(records)
+		<-collected
+	}()
+	measure := func(name types.NamespacedName) pool.Task {
+		return func(ctx context.Context) {
+			trace := &serviceTrace{service: name, api: stopwatch{clock: m.Clock}}
+			record, _, status := m.measureService(ctx, c, name, trace)
+			if status != statusReady {
+				m.logger.Printf("failed to measure service %s and skip\n", name)
+				return
+			}
+			records <- record
+		}
+	}
+
+	// a service is measured once, the services are remembered until they are deleted as the watch is restarted
+	// whenever it ends and then sends all services again
+	seen := map[types.NamespacedName]bool{}
+	for ctx.Err() == nil {
+		watcher, err := c.serving.Services(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			m.logger.Printf("failed to watch services, retrying: %s\n", err)
+			select {
+			case <-ctx.Done():
+			case <-m.Clock.After(m.RetryBackoff):
+			}
+			continue
+		}
+		m.followEvents(ctx, watcher, func(eventType watch.EventType, svc *servingv1api.Service) {
+			name := types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}
+			if eventType == watch.Deleted {
+				delete(seen, name)
+				return
+			}
+			if seen[name] || svc.CreationTimestamp.Time.Before(createdAfter) || (match != nil && !match(svc.Name)) || !svc.IsReady() {
+				return
+			}
+			seen[name] = true
+			// Submit only fails when the context is done, which ends the watch
+			_ = workers.Submit(measure(name))
+		})
+	}
+	return nil
+}
+
+// followEvents passes the services of the watch events to handle until the watch ends or the context is done
+func (m *Measurer) followEvents(ctx context.Context, watcher watch.Interface, handle func(watch.EventType, *servingv1api.Service)) {
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			if svc, ok := event.Object.(*servingv1api.Service); ok {
+				handle(event.Type, svc)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/kperf/pkg"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestFollow(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:              "ksvc-1-00001-deployment",
+		Namespace:         "ns-1",
+		CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+	}}
+	p, fake := newMeasureTestParams(deployment)
+	prependReadyReactors(fake, created)
+	fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, newReadyService(action.(clienttesting.GetAction).GetName(), "ns-1", created), nil
+	})
+	watcher := watch.NewFake()
+	var selector string
+	fake.PrependWatchReactor("services", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		selector = action.(clienttesting.WatchAction).GetWatchRestrictions().Labels.String()
+		return true, watcher, nil
+	})
+
+	measurer := NewMeasurer(p, nil, nil)
+	measurer.CreatedAfter = created.Add(-time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	records := make(chan pkg.MeasureRecord, 10)
+	done := make(chan error)
+	go func() {
+		done <- measurer.Follow(ctx, "ns-1", "app=demo", func(name string) bool { return name != "other-1" }, func(record pkg.MeasureRecord) {
+			records <- record
+		})
+	}()
+
+	// services created before, not ready yet or not matching are skipped
+	watcher.Add(newReadyService("ksvc-0", "ns-1", created.Add(-time.Hour)))
+	watcher.Add(&servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1", CreationTimestamp: metav1.NewTime(created)}})
+	watcher.Add(newReadyService("other-1", "ns-1", created))
+	watcher.Modify(newReadyService("ksvc-1", "ns-1", created))
+	record := <-records
+	assert.Equal(t, "ksvc-1", record.ServiceName)
+	assert.Equal(t, 5.0, record.OverallReady)
+	assert.Equal(t, "app=demo", selector)
+
+	// a service is only measured once
+	watcher.Modify(newReadyService("ksvc-1", "ns-1", created))
+	cancel()
+	assert.NilError(t, <-done)
+	assert.Equal(t, 0, len(records))
+}
@@ -0,0 +1,254 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package measure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/serving/pkg/apis/serving"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+)
+
+// httpRouteResource and gatewayResource are the resources of the Gateway API, read with the dynamic client since its
+// typed client is not a dependency of kperf
+var (
+	httpRouteResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "httproutes"}
+	gatewayResource   = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "gateways"}
+)
+
+// gatewayProgrammedConditions are the conditions of a Gateway which is ready to serve its routes, Programmed of the
+// current releases of the Gateway API and Ready of the earlier ones
+var gatewayProgrammedConditions = []string{"Programmed", "Ready"}
+
+// gatewayCondition is a condition of the status of a Gateway API resource
+type gatewayCondition struct {
+	conditionType      string
+	status             string
+	lastTransitionTime metav1.Time
+}
+
+// collectGatewayRoutes measures the HTTPRoutes the Gateway API implementation created for the Ingress of the service
+// and the Gateways they are attached to in trace. Routes and Gateways which can't be read are logged and don't
+// affect the measurement of the service.
+func (m *Measurer) collectGatewayRoutes(ctx context.Context, client dynamic.Interface, trace *serviceTrace, ingressName string, ingressCreated metav1.Time) {
+	var routeList *unstructured.UnstructuredList
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		routeList, err = client.Resource(httpRouteResource).Namespace(trace.service.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", serving.RouteLabelKey, ingressName),
+		})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.logger.Printf("failed to list HTTPRoutes of Ingress %s and skip them %s\n", ingressName, err)
+		return
+	}
+	if len(routeList.Items) == 0 {
+		return
+	}
+
+	var created, accepted, resolvedRefs, programmed metav1.Time
+	gateways := map[string]bool{}
+	for i := range routeList.Items {
+		route := &routeList.Items[i]
+		trace.add("HTTPRoute", route.GetName(), "created", route.GetCreationTimestamp())
+		later(&created, m.truncate(route.GetCreationTimestamp()))
+		parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+		for _, parent := range parents {
+			values, ok := parent.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(values, "parentRef", "name")
+			for _, c := range gatewayConditions(values) {
+				trace.add("HTTPRoute", route.GetName(), name+"/"+c.conditionType, c.lastTransitionTime)
+				if c.status != string(metav1.ConditionTrue) {
+					continue
+				}
+				switch c.conditionType {
+				case "Accepted":
+					later(&accepted, m.truncate(c.lastTransitionTime))
+				case "ResolvedRefs":
+					later(&resolvedRefs, m.truncate(c.lastTransitionTime))
+				}
+			}
+		}
+		refs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		for _, ref := range refs {
+			values, ok := ref.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// parent references default to Gateways in the namespace of the route
+			if kind, found, _ := unstructured.NestedString(values, "kind"); found && kind != "Gateway" {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(values, "name")
+			namespace, _, _ := unstructured.NestedString(values, "namespace")
+			if namespace == "" {
+				namespace = route.GetNamespace()
+			}
+			if name != "" {
+				gateways[namespace+"/"+name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(gateways))
+	for gateway := range gateways {
+		names = append(names, gateway)
+	}
+	sort.Strings(names)
+	for _, gateway := range names {
+		i := strings.Index(gateway, "/")
+		var gatewayIns *unstructured.Unstructured
+		trace.api.start()
+		err := m.retry(ctx, func() (err error) {
+			gatewayIns, err = client.Resource(gatewayResource).Namespace(gateway[:i]).Get(ctx, gateway[i+1:], metav1.GetOptions{})
+			return err
+		})
+		trace.api.stop()
+		if err != nil {
+			m.logger.Printf("failed to get Gateway %s and skip it %s\n", gateway, err)
+			continue
+		}
+		status, _, _ := unstructured.NestedMap(gatewayIns.Object, "status")
+		if c, ok := gatewayProgrammed(gatewayConditions(status)); ok {
+			trace.add("Gateway", gateway, c.conditionType, c.lastTransitionTime)
+			later(&programmed, m.truncate(c.lastTransitionTime))
+		}
+	}
+
+	since := func(t metav1.Time) float64 {
+		if t.IsZero() || t.Before(&ingressCreated) {
+			return 0
+		}
+		return t.Sub(ingressCreated.Time).Seconds()
+	}
+	trace.gatewayRoute = &pkg.GatewayRoute{
+		ServiceName:       trace.service.Name,
+		ServiceNamespace:  trace.service.Namespace,
+		HTTPRoutes:        len(routeList.Items),
+		Gateways:          names,
+		Created:           since(created),
+		Accepted:          since(accepted),
+		ResolvedRefs:      since(resolvedRefs),
+		GatewayProgrammed: since(programmed),
+	}
+}
+
+// later sets t to ts if ts is later
+func later(t *metav1.Time, ts metav1.Time) {
+	if t.Before(&ts) {
+		*t = ts
+	}
+}
+
+// gatewayConditions returns the conditions of the status of a Gateway API resource, or of a parent in the status of
+// an HTTPRoute. Conditions with an invalid transition time have a zero one.
+func gatewayConditions(status map[string]interface{}) []gatewayCondition {
+	items, _, _ := unstructured.NestedSlice(status, "conditions")
+	conditions := make([]gatewayCondition, 0, len(items))
+	for _, item := range items {
+		values, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := gatewayCondition{}
+		c.conditionType, _, _ = unstructured.NestedString(values, "type")
+		c.status, _, _ = unstructured.NestedString(values, "status")
+		transition, _, _ := unstructured.NestedString(values, "lastTransitionTime")
+		if t, err := time.Parse(time.RFC3339, transition); err == nil {
+			c.lastTransitionTime = metav1.NewTime(t)
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions
+}
+
+// gatewayProgrammed returns the true condition of a Gateway telling it is programmed, false if it isn't
+func gatewayProgrammed(conditions []gatewayCondition) (gatewayCondition, bool) {
+	for _, conditionType := range gatewayProgrammedConditions {
+		for _, c := range conditions {
+			if c.conditionType == conditionType && c.status == string(metav1.ConditionTrue) {
+				return c, true
+			}
+		}
+	}
+	return gatewayCondition{}, false
+}
+
+// sortGatewayRoutes sorts the routes by namespace and name of the service
+func sortGatewayRoutes(routes []pkg.GatewayRoute) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].ServiceNamespace != routes[j].ServiceNamespace {
+			return routes[i].ServiceNamespace < routes[j].ServiceNamespace
+		}
+		return routes[i].ServiceName < routes[j].ServiceName
+	})
+}
+
+// GatewayRouteRows returns the rows of the CSV file of the Gateway API routes with a header
+func GatewayRouteRows(routes []pkg.GatewayRoute) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "httproutes", "gateways", "httproute_created", "httproute_accepted",
+		"httproute_resolved_refs", "gateway_programmed"}}
+	for _, r := range routes {
+		rows = append(rows, []string{r.ServiceName, r.ServiceNamespace, strconv.Itoa(r.HTTPRoutes), strings.Join(r.Gateways, " "),
+			fmt.Sprintf("%f", r.Created), fmt.Sprintf("%f", r.Accepted), fmt.Sprintf("%f", r.ResolvedRefs),
+			fmt.Sprintf("%f", r.GatewayProgrammed)})
+	}
+	return rows
+}
+
+// writeGatewayRoutes writes the average and the maximum duration from the creation of the Ingress until the
+// HTTPRoutes were created, accepted and resolved, and until their Gateways were programmed
+func writeGatewayRoutes(w io.Writer, routes []pkg.GatewayRoute, options render.Options) {
+	phases := []struct {
+		name     string
+		duration func(r pkg.GatewayRoute) float64
+	}{
+		{"HTTPRoute Created", func(r pkg.GatewayRoute) float64 { return r.Created }},
+		{"HTTPRoute Accepted", func(r pkg.GatewayRoute) float64 { return r.Accepted }},
+		{"HTTPRoute ResolvedRefs", func(r pkg.GatewayRoute) float64 { return r.ResolvedRefs }},
+		{"Gateway Programmed", func(r pkg.GatewayRoute) float64 { return r.GatewayProgrammed }},
+	}
+	rows := []*render.Row{}
+	for _, phase := range phases {
+		average, max := 0.0, 0.0
+		for _, r := range routes {
+			d := phase.duration(r)
+			average += d
+			if d > max {
+				max = d
+			}
+		}
+		average /= float64(len(routes))
+		rows = append(rows, &render.Row{Name: phase.name, Values: []string{seconds(average), seconds(max)}, Bar: -1})
+	}
+	render.Table(w, []string{"GATEWAY API", "AVERAGE", "MAX"}, rows, options)
+	fmt.Fprintf(w, "\n")
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package measure
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"knative.dev/kperf/pkg"
+)
+
+func newGatewayCondition(conditionType string, transition time.Time) interface{} {
+	return map[string]interface{}{
+		"type":               conditionType,
+		"status":             "True",
+		"lastTransitionTime": transition.Format(time.RFC3339),
+	}
+}
+
+func newHTTPRoute(name, route string, created time.Time, gateway string, conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "HTTPRoute",
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         "ns-1",
+			"labels":            map[string]interface{}{"serving.knative.dev/route": route},
+			"creationTimestamp": created.Format(time.RFC3339),
+		},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": gateway, "namespace": "gateway-system"}},
+		},
+		"status": map[string]interface{}{
+			"parents": []interface{}{map[string]interface{}{
+				"parentRef":  map[string]interface{}{"name": gateway, "namespace": "gateway-system"},
+				"conditions": conditions,
+			}},
+		},
+	}}
+}
+
+func newGateway(name string, conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "Gateway",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "gateway-system"},
+		"status":     map[string]interface{}{"conditions": conditions},
+	}}
+}
+
+func newGatewayClient(t *testing.T, objects ...*unstructured.Unstructured) dynamic.Interface {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		httpRouteResource: "HTTPRouteList",
+		gatewayResource:   "GatewayList",
+	})
+	// the resource of Gateway is gateways, which is guessed wrong from the kind
+	for _, o := range objects {
+		resource := httpRouteResource
+		if o.GetKind() == "Gateway" {
+			resource = gatewayResource
+		}
+		assert.NilError(t, client.Tracker().Create(resource, o, o.GetNamespace()))
+	}
+	return client
+}
+
+func TestCollectGatewayRoutes(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	ingressCreated := metav1.NewTime(created)
+	client := newGatewayClient(t,
+		newHTTPRoute("ksvc-1.ns-1.example.com", "ksvc-1", created.Add(time.Second), "knative-gateway",
+			newGatewayCondition("Accepted", created.Add(2*time.Second)), newGatewayCondition("ResolvedRefs", created.Add(3*time.Second))),
+		newHTTPRoute("ksvc-1.ns-1.svc.cluster.local", "ksvc-1", created.Add(time.Second), "knative-local-gateway",
+			newGatewayCondition("Accepted", created.Add(4*time.Second)), newGatewayCondition("ResolvedRefs", created.Add(3*time.Second))),
+		newHTTPRoute("ksvc-2.ns-1.example.com", "ksvc-2", created.Add(time.Minute), "knative-gateway",
+			newGatewayCondition("Accepted", created.Add(time.Minute))),
+		// a Gateway programmed before the Ingress was created serves the route right away
+		newGateway("knative-gateway", newGatewayCondition("Programmed", created.Add(-time.Hour))),
+		// earlier releases of the Gateway API tell that a Gateway is programmed with Ready
+		newGateway("knative-local-gateway", newGatewayCondition("Ready", created.Add(5*time.Second))),
+	)
+
+	m := NewMeasurer(&pkg.PerfParams{}, nil, nil)
+
+	t.Run("measure the HTTPRoutes of the Ingress and their Gateways", func(t *testing.T) {
+		trace := &serviceTrace{service: types.NamespacedName{Namespace: "ns-1", Name: "ksvc-1"}, api: stopwatch{clock: m.Clock}, debug: true}
+		m.collectGatewayRoutes(context.Background(), client, trace, "ksvc-1", ingressCreated)
+		assert.DeepEqual(t, &pkg.GatewayRoute{
+			ServiceName:       "ksvc-1",
+			ServiceNamespace:  "ns-1",
+			HTTPRoutes:        2,
+			Gateways:          []string{"gateway-system/knative-gateway", "gateway-system/knative-local-gateway"},
+			Created:           1,
+			Accepted:          4,
+			ResolvedRefs:      3,
+			GatewayProgrammed: 5,
+		}, trace.gatewayRoute)
+		fields := map[string]bool{}
+		for _, ts := range trace.timestamps {
+			fields[ts.Kind+"/"+ts.Field] = true
+		}
+		assert.Assert(t, fields["HTTPRoute/knative-local-gateway/Accepted"])
+		assert.Assert(t, fields["Gateway/Ready"])
+	})
+
+	t.Run("skip services without HTTPRoutes", func(t *testing.T) {
+		trace := &serviceTrace{service: types.NamespacedName{Namespace: "ns-1", Name: "ksvc-3"}, api: stopwatch{clock: m.Clock}}
+		m.collectGatewayRoutes(context.Background(), client, trace, "ksvc-3", ingressCreated)
+		assert.Assert(t, trace.gatewayRoute == nil)
+	})
+
+	t.Run("write the routes", func(t *testing.T) {
+		routes := []pkg.GatewayRoute{
+			{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", HTTPRoutes: 2, Gateways: []string{"gateway-system/knative-gateway"},
+				Created: 1, Accepted: 2, ResolvedRefs: 2, GatewayProgrammed: 0},
+			{ServiceName: "ksvc-2", ServiceNamespace: "ns-1", HTTPRoutes: 2, Gateways: []string{"gateway-system/knative-gateway"},
+				Created: 1, Accepted: 4, ResolvedRefs: 3, GatewayProgrammed: 1},
+		}
+		rows := GatewayRouteRows(routes)
+		assert.Equal(t, 3, len(rows))
+		assert.DeepEqual(t, []string{"ksvc-2", "ns-1", "2", "gateway-system/knative-gateway", "1.000000", "4.000000", "3.000000", "1.000000"}, rows[2])
+
+		out := &bytes.Buffer{}
+		writeGatewayRoutes(out, routes, SummaryOptions{}.Options)
+		assert.Assert(t, strings.Contains(out.String(), "HTTPRoute Accepted      3.000000s  4.000000s\n"), out.String())
+		assert.Assert(t, strings.Contains(out.String(), "Gateway Programmed      0.500000s  1.000000s\n"), out.String())
+	})
+}
+
+func TestMeasureGatewayAPI(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:              "ksvc-1-00001-deployment",
+		Namespace:         "ns-1",
+		CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+	}}
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-network", Namespace: "knative-serving"},
+		Data:       map[string]string{"ingress-class": "gateway-api.ingress.networking.knative.dev"},
+	}
+	p, fake := newMeasureTestParams(deployment, config)
+	fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, newReadyService("ksvc-1", "ns-1", created), nil
+	})
+	prependReadyReactors(fake, created)
+	// the Ingress is created 3s after the service
+	client := newGatewayClient(t,
+		newHTTPRoute("ksvc-1.ns-1.example.com", "ksvc-1", created.Add(3*time.Second), "knative-gateway",
+			newGatewayCondition("Accepted", created.Add(4*time.Second))),
+		newGateway("knative-gateway", newGatewayCondition("Programmed", created.Add(-time.Hour))),
+	)
+	p.NewDynamicClient = func() (dynamic.Interface, error) {
+		return client, nil
+	}
+
+	result, err := NewMeasurer(p, nil, nil).Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+	assert.DeepEqual(t, []pkg.GatewayRoute{{
+		ServiceName:      "ksvc-1",
+		ServiceNamespace: "ns-1",
+		HTTPRoutes:       1,
+		Gateways:         []string{"gateway-system/knative-gateway"},
+		Accepted:         1,
+	}}, result.Summary.GatewayRoutes)
+	assert.Equal(t, "Gateway API", result.Summary.KnativeInfo.IngressController)
+
+	summary := &bytes.Buffer{}
+	result.WriteSummary(summary, SummaryOptions{})
+	assert.Assert(t, strings.Contains(summary.String(), "GATEWAY API"), summary.String())
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/montanaflynn/stats"
+
+	"knative.dev/kperf/pkg"
+)
+
+// GroupByNamespace returns the number of services by state and the statistics of the overall ready duration of
+// the ready services for every namespace, sorted by namespace
+func (r *Result) GroupByNamespace() []pkg.NamespaceMeasureResult {
+	readyTimes := map[string][]float64{}
+	for _, record := range r.Records {
+		readyTimes[record.ServiceNamespace] = append(readyTimes[record.ServiceNamespace], record.OverallReady)
+	}
+	namespaces := make([]pkg.NamespaceMeasureResult, 0, len(r.NamespaceCounts))
+	for ns, count := range r.NamespaceCounts {
+		result := pkg.NamespaceMeasureResult{Namespace: ns, Service: count}
+		if times := readyTimes[ns]; len(times) > 0 {
+			result.Average, _ = stats.Mean(times)
+			result.P50, _ = stats.Percentile(times, 50)
+			result.P95, _ = stats.Percentile(times, 95)
+			result.P99, _ = stats.Percentile(times, 99)
+		}
+		namespaces = append(namespaces, result)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Namespace < namespaces[j].Namespace
+	})
+	return namespaces
+}
+
+// NoGroup is the group of the services without a value of the grouping dimension, e.g. without the label
+const NoGroup = "<none>"
+
+// GroupKey returns the group of the service in the namespace
+type GroupKey func(namespace, name string) string
+
+// NamespaceKey groups the services by namespace
+func NamespaceKey(namespace, name string) string {
+	return namespace
+}
+
+// PrefixKey groups the services by the longest of the prefixes their name starts with. Without prefixes the
+// prefix of a name is the name without its trailing -<index>, e.g. api for api-12.
+func PrefixKey(prefixes []string) GroupKey {
+	return func(namespace, name string) string {
+		if len(prefixes) == 0 {
+			if i := strings.LastIndex(name, "-"); i > 0 && isDigits(name[i+1:]) {
+				return name[:i]
+			}
+			return name
+		}
+		longest, found := "", false
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) && (!found || len(prefix) > len(longest)) {
+				longest, found = prefix, true
+			}
+		}
+		if !found {
+			return NoGroup
+		}
+		return longest
+	}
+}
+
+// LabelKey groups the services by the value of the label, which the Measurer has to collect with Labels
+func (r *Result) LabelKey(label string) GroupKey {
+	return func(namespace, name string) string {
+		if value, ok := r.Labels[namespace+"/"+name][label]; ok {
+			return value
+		}
+		return NoGroup
+	}
+}
+
+// NodeKey groups the services by the node their pod landed on. The services which are not ready have no record with
+// a node and are in NoGroup.
+func (r *Result) NodeKey() GroupKey {
+	nodes := map[string]string{}
+	for _, record := range r.Records {
+		if record.NodeName != "" {
+			nodes[record.ServiceNamespace+"/"+record.ServiceName] = record.NodeName
+		}
+	}
+	return func(namespace, name string) string {
+		if node, ok := nodes[namespace+"/"+name]; ok {
+			return node
+		}
+		return NoGroup
+	}
+}
+
+// GroupBy partitions the measurement by the key of the services and returns the number of services by state, the
+// averages of every phase and the percentiles of the overall ready duration of every group, sorted by group
+func (r *Result) GroupBy(key GroupKey) []pkg.GroupMeasureResult {
+	summaries := map[string]*pkg.MeasureResult{}
+	summary := func(group string) *pkg.MeasureResult {
+		s, ok := summaries[group]
+		if !ok {
+			s = &pkg.MeasureResult{SvcReadyTime: make([]float64, 0)}
+			summaries[group] = s
+		}
+		return s
+	}
+	for service, state := range r.States {
+		i := strings.Index(service, "/")
+		s := summary(key(service[:i], service[i+1:]))
+		switch state {
+		case StateNotFound:
+			s.Service.NotFoundCount++
+		case StateNotReady:
+			s.Service.NotReadyCount++
+		case StateFailed:
+			s.Service.FailCount++
+		case StateProgressDeadlineExceeded:
+			s.Service.ProgressDeadlineExceededCount++
+		case StateTimedOut:
+			s.Service.TimedOutCount++
+		default:
+			s.Service.ReadyCount++
+		}
+	}
+	for _, record := range r.Records {
+		addSums(summary(key(record.ServiceNamespace, record.ServiceName)), record)
+	}
+	groups := make([]pkg.GroupMeasureResult, 0, len(summaries))
+	for group, s := range summaries {
+		summarize(s)
+		groups = append(groups, pkg.GroupMeasureResult{Group: group, Service: s.Service, Result: s.Result})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Group < groups[j].Group
+	})
+	return groups
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestGroupByNamespace(t *testing.T) {
+	result := &Result{
+		Records: []pkg.MeasureRecord{
+			{ServiceName: "ksvc-1", ServiceNamespace: "ns-2", OverallReady: 4},
+			{ServiceName: "ksvc-2", ServiceNamespace: "ns-2", OverallReady: 8},
+			{ServiceName: "ksvc-3", ServiceNamespace: "ns-1", OverallReady: 2},
+		},
+		NamespaceCounts: map[string]pkg.ServiceCount{
+			"ns-1": {ReadyCount: 1},
+			"ns-2": {ReadyCount: 2, FailCount: 1},
+			"ns-3": {NotReadyCount: 1},
+		},
+	}
+
+	namespaces := result.GroupByNamespace()
+	assert.DeepEqual(t, []pkg.NamespaceMeasureResult{
+		{Namespace: "ns-1", Service: pkg.ServiceCount{ReadyCount: 1}, Average: 2, P50: 2, P95: 2, P99: 2},
+		{Namespace: "ns-2", Service: pkg.ServiceCount{ReadyCount: 2, FailCount: 1}, Average: 6, P50: 4, P95: 6, P99: 6},
+		{Namespace: "ns-3", Service: pkg.ServiceCount{NotReadyCount: 1}},
+	}, namespaces)
+}
+
+func TestGroupBy(t *testing.T) {
+	result := &Result{
+		Records: []pkg.MeasureRecord{
+			{ServiceName: "web-1", ServiceNamespace: "ns-1", NodeName: "node-a", ConfigurationReady: 2, OverallReady: 4},
+			{ServiceName: "web-2", ServiceNamespace: "ns-2", NodeName: "node-b", ConfigurationReady: 4, OverallReady: 8},
+			{ServiceName: "api-v2-1", ServiceNamespace: "ns-1", NodeName: "node-a", ConfigurationReady: 1, OverallReady: 2},
+		},
+		States: map[string]string{
+			"ns-1/web-1":    StateReady,
+			"ns-2/web-2":    StateReady,
+			"ns-2/web-3":    StateFailed,
+			"ns-1/api-v2-1": StateReady,
+			"ns-1/api-1":    StateNotReady,
+			"ns-1/other":    StateNotFound,
+		},
+		Labels: map[string]map[string]string{
+			"ns-1/web-1":    {"team": "a"},
+			"ns-2/web-2":    {"team": "b"},
+			"ns-2/web-3":    {"team": "b"},
+			"ns-1/api-v2-1": {"team": "a"},
+			"ns-1/api-1":    {},
+		},
+	}
+
+	t.Run("by namespace", func(t *testing.T) {
+		groups := result.GroupBy(NamespaceKey)
+		assert.Equal(t, 2, len(groups))
+		assert.Equal(t, "ns-1", groups[0].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 2, NotReadyCount: 1, NotFoundCount: 1}, groups[0].Service)
+		assert.Equal(t, 1.5, groups[0].Result.AverageSvcConfigurationReadySum)
+		assert.Equal(t, 3.0, groups[0].Result.OverallAverage)
+		assert.Equal(t, "ns-2", groups[1].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 1, FailCount: 1}, groups[1].Service)
+		assert.Equal(t, 8.0, groups[1].Result.P99)
+	})
+
+	t.Run("by the given prefixes", func(t *testing.T) {
+		groups := result.GroupBy(PrefixKey([]string{"web", "api", "api-v2"}))
+		var names []string
+		for _, g := range groups {
+			names = append(names, g.Group)
+		}
+		assert.DeepEqual(t, []string{NoGroup, "api", "api-v2", "web"}, names)
+		assert.DeepEqual(t, pkg.ServiceCount{NotReadyCount: 1}, groups[1].Service)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 1}, groups[2].Service)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 2, FailCount: 1}, groups[3].Service)
+		assert.Equal(t, 6.0, groups[3].Result.OverallAverage)
+	})
+
+	t.Run("by the prefixes of the names", func(t *testing.T) {
+		key := PrefixKey(nil)
+		assert.Equal(t, "api-v2", key("ns-1", "api-v2-1"))
+		assert.Equal(t, "web", key("ns-1", "web-12"))
+		assert.Equal(t, "other", key("ns-1", "other"))
+		assert.Equal(t, "api-v2", key("ns-1", "api-v2"))
+	})
+
+	t.Run("by label", func(t *testing.T) {
+		groups := result.GroupBy(result.LabelKey("team"))
+		assert.Equal(t, 3, len(groups))
+		assert.Equal(t, NoGroup, groups[0].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{NotReadyCount: 1, NotFoundCount: 1}, groups[0].Service)
+		assert.Equal(t, "a", groups[1].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 2}, groups[1].Service)
+		assert.Equal(t, "b", groups[2].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 1, FailCount: 1}, groups[2].Service)
+	})
+
+	t.Run("by node", func(t *testing.T) {
+		groups := result.GroupBy(result.NodeKey())
+		assert.Equal(t, 3, len(groups))
+		assert.Equal(t, NoGroup, groups[0].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{NotReadyCount: 1, NotFoundCount: 1, FailCount: 1}, groups[0].Service)
+		assert.Equal(t, "node-a", groups[1].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 2}, groups[1].Service)
+		assert.Equal(t, 3.0, groups[1].Result.OverallAverage)
+		assert.Equal(t, "node-b", groups[2].Group)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 1}, groups[2].Service)
+		assert.Equal(t, 8.0, groups[2].Result.P99)
+	})
+}
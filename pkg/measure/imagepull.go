@@ -0,0 +1,215 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package measure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+)
+
+var (
+	// containerFieldPath matches the field path of the Events of a container, e.g. spec.containers{user-container}
+	containerFieldPath = regexp.MustCompile(`^spec\.containers\{(.+)\}$`)
+	// pulledIn matches the pull time the kubelet reports in the message of the Pulled Event, it is more precise than
+	// the timestamps of the Events, e.g. Successfully pulled image "ko.local/helloworld" in 1.234567s
+	pulledIn = regexp.MustCompile(`^Successfully pulled image ".*" in ([0-9][0-9.a-zµ]*)`)
+)
+
+// imagePullEvents are the first Pulling and Pulled Events of a container
+type imagePullEvents struct {
+	pulling, pulled *corev1.Event
+}
+
+// collectImagePulls breaks down the starts of the containers of the Pod into their image pulls in trace. Events which
+// can't be listed are logged and don't affect the measurement of the service.
+func (m *Measurer) collectImagePulls(ctx context.Context, trace *serviceTrace, pod *corev1.Pod, podCreated metav1.Time) {
+	selector := fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": pod.Name}.AsSelector().String()
+	var eventList *corev1.EventList
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		eventList, err = m.params.ClientSet.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.logger.Printf("failed to list Events of Pod %s and skip its image pulls %s\n", pod.Name, err)
+		return
+	}
+	trace.imagePulls = append(trace.imagePulls, m.imagePulls(trace, pod, podCreated, eventList.Items)...)
+}
+
+// imagePulls returns the image pulls of the containers of the Pod with a Pulled Event, in the order of the containers
+func (m *Measurer) imagePulls(trace *serviceTrace, pod *corev1.Pod, podCreated metav1.Time, events []corev1.Event) []pkg.ImagePull {
+	byContainer := map[string]*imagePullEvents{}
+	for i := range events {
+		e := &events[i]
+		// the events are matched again in case the field selector is ignored by the client, and the ones of an
+		// earlier Pod with the same name are skipped
+		if e.InvolvedObject.Kind != "Pod" || e.InvolvedObject.Name != pod.Name ||
+			(e.InvolvedObject.UID != "" && pod.UID != "" && e.InvolvedObject.UID != pod.UID) {
+			continue
+		}
+		match := containerFieldPath.FindStringSubmatch(e.InvolvedObject.FieldPath)
+		if match == nil || (e.Reason != "Pulling" && e.Reason != "Pulled") {
+			continue
+		}
+		c, ok := byContainer[match[1]]
+		if !ok {
+			c = &imagePullEvents{}
+			byContainer[match[1]] = c
+		}
+		// a restarted container pulls its image again, only the first pull is part of the start of the Pod
+		if e.Reason == "Pulling" && (c.pulling == nil || firstTime(e).Before(firstTime(c.pulling))) {
+			c.pulling = e
+		}
+		if e.Reason == "Pulled" && (c.pulled == nil || firstTime(e).Before(firstTime(c.pulled))) {
+			c.pulled = e
+		}
+	}
+
+	pulls := []pkg.ImagePull{}
+	for _, container := range pod.Spec.Containers {
+		c, ok := byContainer[container.Name]
+		if !ok || c.pulled == nil {
+			continue
+		}
+		pulled := m.truncate(metav1.NewTime(firstTime(c.pulled)))
+		pull := pkg.ImagePull{
+			ServiceName:      trace.service.Name,
+			ServiceNamespace: trace.service.Namespace,
+			Container:        container.Name,
+			Image:            container.Image,
+			Cached:           strings.Contains(c.pulled.Message, "already present on machine"),
+			Pulling:          pulled.Sub(podCreated.Time).Seconds(),
+			Pulled:           pulled.Sub(podCreated.Time).Seconds(),
+		}
+		if c.pulling != nil {
+			pulling := m.truncate(metav1.NewTime(firstTime(c.pulling)))
+			pull.Pulling = pulling.Sub(podCreated.Time).Seconds()
+			pull.Pull = pull.Pulled - pull.Pulling
+		}
+		if pull.Cached {
+			pull.Pull = 0
+		} else if d, ok := pulledDuration(c.pulled.Message); ok {
+			pull.Pull = d.Seconds()
+		}
+		if started, found := containerStarted(pod.Status.ContainerStatuses, container.Name); found {
+			pull.Started = m.truncate(started).Sub(pulled.Time).Seconds()
+		}
+		pulls = append(pulls, pull)
+	}
+	return pulls
+}
+
+// pulledDuration returns the pull time reported in the message of a Pulled Event, false if it doesn't report one
+func pulledDuration(message string) (time.Duration, bool) {
+	match := pulledIn.FindStringSubmatch(message)
+	if match == nil {
+		return 0, false
+	}
+	d, err := time.ParseDuration(match[1])
+	return d, err == nil
+}
+
+// sortImagePulls sorts the image pulls by namespace and name of the service and the name of the container
+func sortImagePulls(pulls []pkg.ImagePull) {
+	sort.Slice(pulls, func(i, j int) bool {
+		a, b := pulls[i], pulls[j]
+		if a.ServiceNamespace != b.ServiceNamespace {
+			return a.ServiceNamespace < b.ServiceNamespace
+		}
+		if a.ServiceName != b.ServiceName {
+			return a.ServiceName < b.ServiceName
+		}
+		return a.Container < b.Container
+	})
+}
+
+// ImagePullRows returns the rows of the CSV file of the image pulls with a header
+func ImagePullRows(pulls []pkg.ImagePull) [][]string {
+	rows := [][]string{{"svc_name", "svc_namespace", "container", "image", "cached", "pulling", "pulled", "pull", "started"}}
+	for _, p := range pulls {
+		rows = append(rows, []string{p.ServiceName, p.ServiceNamespace, p.Container, p.Image, strconv.FormatBool(p.Cached),
+			fmt.Sprintf("%f", p.Pulling), fmt.Sprintf("%f", p.Pulled), fmt.Sprintf("%f", p.Pull), fmt.Sprintf("%f", p.Started)})
+	}
+	return rows
+}
+
+// imagePullStatistic is the number of services a container was started for and how often its image was cached, with
+// the average and the maximum pull and the average start after the pull
+type imagePullStatistic struct {
+	container string
+	services  int
+	cached    int
+	average   float64
+	max       float64
+	started   float64
+}
+
+// imagePullStatistics returns the statistics of the image pulls by container name, sorted by name
+func imagePullStatistics(pulls []pkg.ImagePull) []imagePullStatistic {
+	byName := map[string]*imagePullStatistic{}
+	names := []string{}
+	for _, p := range pulls {
+		s, ok := byName[p.Container]
+		if !ok {
+			s = &imagePullStatistic{container: p.Container}
+			byName[p.Container] = s
+			names = append(names, p.Container)
+		}
+		s.services++
+		if p.Cached {
+			s.cached++
+		}
+		s.average += p.Pull
+		if p.Pull > s.max {
+			s.max = p.Pull
+		}
+		s.started += p.Started
+	}
+	sort.Strings(names)
+	statistics := make([]imagePullStatistic, 0, len(names))
+	for _, name := range names {
+		s := byName[name]
+		s.average /= float64(s.services)
+		s.started /= float64(s.services)
+		statistics = append(statistics, *s)
+	}
+	return statistics
+}
+
+// writeImagePulls writes the average and the maximum image pull of every container, and the average time from the
+// pulled image until the container was running
+func writeImagePulls(w io.Writer, pulls []pkg.ImagePull, options render.Options) {
+	rows := []*render.Row{}
+	for _, s := range imagePullStatistics(pulls) {
+		rows = append(rows, &render.Row{Name: s.container, Values: []string{strconv.Itoa(s.services), strconv.Itoa(s.cached),
+			seconds(s.average), seconds(s.max), seconds(s.started)}, Bar: -1})
+	}
+	render.Table(w, []string{"IMAGE PULL", "SERVICES", "CACHED", "AVERAGE", "MAX", "STARTED AFTER"}, rows, options)
+	fmt.Fprintf(w, "\n")
+}
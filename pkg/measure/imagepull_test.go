@@ -0,0 +1,67 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package measure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestPulledDuration(t *testing.T) {
+	for message, expected := range map[string]time.Duration{
+		`Successfully pulled image "ko.local/helloworld" in 1.5s`:                                1500 * time.Millisecond,
+		`Successfully pulled image "ko.local/helloworld" in 812.3ms (812.3ms including waiting)`: 812300 * time.Microsecond,
+		`Successfully pulled image "ko.local/helloworld" in 1m2.5s (1m2.5s including waiting)`:   62500 * time.Millisecond,
+	} {
+		d, ok := pulledDuration(message)
+		assert.Check(t, ok, message)
+		assert.Equal(t, expected, d, message)
+	}
+	for _, message := range []string{
+		`Container image "ko.local/helloworld" already present on machine`,
+		`Successfully pulled image "ko.local/helloworld"`,
+	} {
+		_, ok := pulledDuration(message)
+		assert.Check(t, !ok, message)
+	}
+}
+
+func TestImagePullRows(t *testing.T) {
+	rows := ImagePullRows([]pkg.ImagePull{
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "user-container", Image: "ko.local/helloworld", Pulling: 1, Pulled: 3, Pull: 1.5, Started: 1},
+		{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "queue-proxy", Image: "queue", Cached: true, Pulling: 1, Pulled: 1},
+	})
+	assert.DeepEqual(t, [][]string{
+		{"svc_name", "svc_namespace", "container", "image", "cached", "pulling", "pulled", "pull", "started"},
+		{"ksvc-1", "ns-1", "user-container", "ko.local/helloworld", "false", "1.000000", "3.000000", "1.500000", "1.000000"},
+		{"ksvc-1", "ns-1", "queue-proxy", "queue", "true", "1.000000", "1.000000", "0.000000", "0.000000"},
+	}, rows)
+}
+
+func TestImagePullStatistics(t *testing.T) {
+	statistics := imagePullStatistics([]pkg.ImagePull{
+		{Container: "user-container", Pull: 1, Started: 1},
+		{Container: "user-container", Pull: 3, Started: 2},
+		{Container: "queue-proxy", Cached: true, Started: 1},
+	})
+	assert.DeepEqual(t, []imagePullStatistic{
+		{container: "queue-proxy", services: 1, cached: 1, started: 1},
+		{container: "user-container", services: 2, average: 2, max: 3, started: 1.5},
+	}, statistics, cmp.AllowUnexported(imagePullStatistic{}))
+}
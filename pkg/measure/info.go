@@ -0,0 +1,146 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kperf/pkg"
+)
+
+// GetKnativeInfo returns the Knative versions and the ingress controller of the cluster
+func GetKnativeInfo(ctx context.Context, p *pkg.PerfParams, logger Logger) pkg.KnativeInfo {
+	knativeVersion := GetKnativeVersion(ctx, p, logger)
+	ingressInfo := GetIngressController(ctx, p, logger)
+	return pkg.KnativeInfo{
+		ServingVersion:    knativeVersion["serving"],
+		EventingVersion:   knativeVersion["eventing"],
+		IngressController: ingressInfo["ingressController"],
+		IngressVersion:    ingressInfo["version"],
+	}
+}
+
+// Get Knative Serving and Eventing version
+// Returns a map like {"eventing":"0.20.0", "serving":"0.20.0"}
+func GetKnativeVersion(ctx context.Context, p *pkg.PerfParams, logger Logger) map[string]string {
+	knativeVersion := make(map[string]string)
+	knativeServingNs, err := p.ClientSet.CoreV1().Namespaces().Get(ctx, "knative-serving", metav1.GetOptions{})
+	if err != nil {
+		logger.Printf("failed to get Knative Serving version: %s\n", err)
+		knativeVersion["serving"] = "Unknown"
+	} else {
+		servingVersion := knativeServingNs.Labels["serving.knative.dev/release"]
+		servingVersion = strings.Trim(servingVersion, "v")
+		knativeVersion["serving"] = servingVersion
+	}
+
+	knativeEventingNs, err := p.ClientSet.CoreV1().Namespaces().Get(ctx, "knative-eventing", metav1.GetOptions{})
+	if err != nil {
+		logger.Printf("failed to get Knative Eventing version: %s\n", err)
+		knativeVersion["eventing"] = "Unknown"
+	} else {
+		eventingVersion := knativeEventingNs.Labels["eventing.knative.dev/release"]
+		eventingVersion = strings.Trim(eventingVersion, "v")
+		knativeVersion["eventing"] = eventingVersion
+	}
+	return knativeVersion
+}
+
+// gatewayAPIIngressClass is the part of the ingress class of the Gateway API ingress implementation
+const gatewayAPIIngressClass = "gateway-api"
+
+// ingressProviders are the ingress implementations of Knative by a part of their ingress class, with the name of
+// the Deployment of their Knative controller in the knative-serving namespace which is labeled with their version
+var ingressProviders = []struct {
+	class      string
+	name       string
+	controller string
+}{
+	{"kourier", "Kourier", "net-kourier-controller"},
+	{"contour", "Contour", "net-contour-controller"},
+	{gatewayAPIIngressClass, "Gateway API", "net-gateway-api-controller"},
+}
+
+// getIngressClass returns the ingress class of the config-network ConfigMap of Knative Serving
+func getIngressClass(ctx context.Context, p *pkg.PerfParams) (string, error) {
+	knativeServingConfig, err := p.ClientSet.CoreV1().ConfigMaps("knative-serving").Get(ctx, "config-network", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	// newer releases of Knative name the key ingress-class
+	ingressClass := knativeServingConfig.Data["ingress-class"]
+	if ingressClass == "" {
+		ingressClass = knativeServingConfig.Data["ingress.class"]
+	}
+	return ingressClass, nil
+}
+
+// UsesGatewayAPI returns true if the cluster uses the Gateway API ingress implementation of Knative
+func UsesGatewayAPI(ctx context.Context, p *pkg.PerfParams) bool {
+	ingressClass, err := getIngressClass(ctx, p)
+	return err == nil && strings.Contains(ingressClass, gatewayAPIIngressClass)
+}
+
+// Get Knative ingress controller solution and version
+// Returns a map like {"ingressController":"Istio", "version":"1.7.3"}
+// 1) If it is using Istio, get version from istio deployment labels in istio-system.
+// 2) If it is using Kourier, Contour or the Gateway API, get version from the labels of its Knative controller.
+// 3) If it is using other options, put version as "Unknown".
+func GetIngressController(ctx context.Context, p *pkg.PerfParams, logger Logger) map[string]string {
+	ingressController := make(map[string]string)
+	ingressClass, err := getIngressClass(ctx, p)
+	if err != nil {
+		logger.Printf("failed to get Knative ingress controller info: %s\n", err)
+		ingressController["ingressController"] = "Unknown"
+		ingressController["version"] = "Unknown"
+		return ingressController
+	}
+	if strings.Contains(ingressClass, "istio") {
+		ingressController["ingressController"] = "Istio"
+		istioVersion, err := p.ClientSet.CoreV1().ConfigMaps("istio-system").Get(ctx, "istio", metav1.GetOptions{})
+		if err != nil {
+			logger.Printf("failed to get Istio version: %s\n", err)
+			ingressController["version"] = "Unknown"
+			return ingressController
+		}
+		ingressController["version"] = istioVersion.Labels["operator.istio.io/version"]
+		return ingressController
+	}
+	for _, provider := range ingressProviders {
+		if !strings.Contains(ingressClass, provider.class) {
+			continue
+		}
+		ingressController["ingressController"] = provider.name
+		ingressController["version"] = "Unknown"
+		controller, err := p.ClientSet.AppsV1().Deployments("knative-serving").Get(ctx, provider.controller, metav1.GetOptions{})
+		if err != nil {
+			logger.Printf("failed to get %s version: %s\n", provider.name, err)
+			return ingressController
+		}
+		for _, label := range []string{"app.kubernetes.io/version", "networking.knative.dev/release", "serving.knative.dev/release"} {
+			if version := strings.TrimPrefix(controller.Labels[label], "v"); version != "" {
+				ingressController["version"] = version
+				break
+			}
+		}
+		return ingressController
+	}
+	ingressController["ingressController"] = "Unknown"
+	ingressController["version"] = "Unknown"
+	return ingressController
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestGetKnativeVersion(t *testing.T) {
+	t.Run("get knative serving and eventing version", func(t *testing.T) {
+		servingNs := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "knative-serving",
+				Labels: map[string]string{"serving.knative.dev/release": "v0.20.0"},
+			},
+		}
+		eventingNs := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "knative-eventing",
+				Labels: map[string]string{"eventing.knative.dev/release": "v0.20.0"},
+			},
+		}
+		client := k8sfake.NewSimpleClientset(servingNs, eventingNs)
+
+		p := &pkg.PerfParams{
+			ClientSet: client,
+		}
+		version := GetKnativeVersion(context.TODO(), p, DefaultLogger)
+		assert.Equal(t, "0.20.0", version["serving"])
+		assert.Equal(t, "0.20.0", version["eventing"])
+	})
+
+	t.Run("failed to get knative serving and eventing version", func(t *testing.T) {
+		client := k8sfake.NewSimpleClientset()
+		p := &pkg.PerfParams{
+			ClientSet: client,
+		}
+		version := GetKnativeVersion(context.TODO(), p, DefaultLogger)
+		assert.Equal(t, "Unknown", version["serving"])
+		assert.Equal(t, "Unknown", version["eventing"])
+	})
+}
+
+func TestGetIngressController(t *testing.T) {
+	t.Run("get knative ingress controller with version", func(t *testing.T) {
+		servingNs := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "knative-serving",
+			},
+		}
+
+		istioNs := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "istio-system",
+			},
+		}
+
+		client := k8sfake.NewSimpleClientset(servingNs, istioNs)
+		p := &pkg.PerfParams{
+			ClientSet: client,
+		}
+
+		configMapKnative := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "config-network",
+			},
+			Data: map[string]string{"ingress.class": "istio.ingress.networking.knative.dev"},
+		}
+		p.ClientSet.CoreV1().ConfigMaps("knative-serving").Create(context.TODO(), configMapKnative, metav1.CreateOptions{})
+
+		configMapIstio := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "istio",
+				Labels: map[string]string{"operator.istio.io/version": "1.7.3"},
+			},
+			Data: map[string]string{"ingress.class": "istio.ingress.networking.knative.dev"},
+		}
+		p.ClientSet.CoreV1().ConfigMaps("istio-system").Create(context.TODO(), configMapIstio, metav1.CreateOptions{})
+
+		ingressController := GetIngressController(context.TODO(), p, DefaultLogger)
+		assert.Equal(t, "Istio", ingressController["ingressController"])
+		assert.Equal(t, "1.7.3", ingressController["version"])
+	})
+
+	t.Run("get knative ingress controller without version", func(t *testing.T) {
+		servingNs := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "knative-serving",
+			},
+		}
+
+		client := k8sfake.NewSimpleClientset(servingNs)
+		p := &pkg.PerfParams{
+			ClientSet: client,
+		}
+		configMapKnative := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "config-network",
+			},
+			Data: map[string]string{"ingress.class": "istio.ingress.networking.knative.dev"},
+		}
+		p.ClientSet.CoreV1().ConfigMaps("knative-serving").Create(context.TODO(), configMapKnative, metav1.CreateOptions{})
+
+		ingressController := GetIngressController(context.TODO(), p, DefaultLogger)
+		assert.Equal(t, "Istio", ingressController["ingressController"])
+		assert.Equal(t, "Unknown", ingressController["version"])
+	})
+
+	t.Run("get kourier, contour and gateway api ingress controllers", func(t *testing.T) {
+		for _, c := range []struct {
+			class      string
+			controller string
+			labels     map[string]string
+			name       string
+			version    string
+		}{
+			{"kourier.ingress.networking.knative.dev", "net-kourier-controller", map[string]string{"app.kubernetes.io/version": "1.8.1"}, "Kourier", "1.8.1"},
+			{"contour.ingress.networking.knative.dev", "net-contour-controller", map[string]string{"networking.knative.dev/release": "v1.8.0"}, "Contour", "1.8.0"},
+			{"gateway-api.ingress.networking.knative.dev", "net-gateway-api-controller", nil, "Gateway API", "Unknown"},
+		} {
+			controller := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: c.controller, Namespace: "knative-serving", Labels: c.labels}}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config-network", Namespace: "knative-serving"},
+				Data:       map[string]string{"ingress-class": c.class},
+			}
+			p := &pkg.PerfParams{ClientSet: k8sfake.NewSimpleClientset(controller, configMap)}
+			ingressController := GetIngressController(context.TODO(), p, DefaultLogger)
+			assert.Equal(t, c.name, ingressController["ingressController"])
+			assert.Equal(t, c.version, ingressController["version"])
+		}
+	})
+
+	t.Run("get unknown knative ingress controller", func(t *testing.T) {
+		servingNs := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "knative-serving",
+			},
+		}
+
+		client := k8sfake.NewSimpleClientset(servingNs)
+		p := &pkg.PerfParams{
+			ClientSet: client,
+		}
+		configMapKnative := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "config-network",
+			},
+		}
+		p.ClientSet.CoreV1().ConfigMaps("knative-serving").Create(context.TODO(), configMapKnative, metav1.CreateOptions{})
+
+		ingressController := GetIngressController(context.TODO(), p, DefaultLogger)
+		assert.Equal(t, "Unknown", ingressController["ingressController"])
+		assert.Equal(t, "Unknown", ingressController["version"])
+	})
+}
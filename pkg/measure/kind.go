@@ -0,0 +1,292 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1api "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+)
+
+const (
+	// KindService measures Knative Services
+	KindService = "service"
+	// KindConfiguration measures Configurations created without a Service, there are no route and ingress
+	// phases and the overall ready duration is the one of the Configuration
+	KindConfiguration = "configuration"
+	// KindRoute measures Routes created without a Service, starting from the Route and the revision it routes
+	// traffic to
+	KindRoute = "route"
+)
+
+// Kinds are the supported kinds of the resources the measurement starts from
+var Kinds = []string{KindService, KindConfiguration, KindRoute}
+
+// ValidateKind returns an error if the kind is not supported
+func ValidateKind(kind string) error {
+	for _, k := range Kinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported kind %q, expected one of %s", kind, strings.Join(Kinds, ","))
+}
+
+// topLevel holds the timestamps of the resource a measurement starts from and the names of the resources
+// created for it
+type topLevel struct {
+	created            metav1.Time
+	configurationReady metav1.Time
+	routeReady         metav1.Time
+	ready              metav1.Time
+
+	// configuration is the Configuration if it was already read, otherwise it's read by configurationName
+	configuration     *servingv1api.Configuration
+	configurationName string
+	// revisionName is the revision to measure, empty for the latest ready revision of the configuration
+	revisionName string
+	// ingressName is the Ingress to measure, empty if there is no Ingress
+	ingressName string
+}
+
+// list returns the names of the resources of the kind in the namespace
+func (m *Measurer) list(ctx context.Context, client servingv1client.ServingV1Interface, ns string, opts metav1.ListOptions) ([]types.NamespacedName, error) {
+	var names []types.NamespacedName
+	add := func(meta metav1.ObjectMeta) {
+		if meta.CreationTimestamp.Time.Before(m.CreatedAfter) {
+			return
+		}
+		names = append(names, types.NamespacedName{Namespace: meta.Namespace, Name: meta.Name})
+	}
+	err := m.retry(ctx, func() error {
+		names = nil
+		switch m.kind() {
+		case KindConfiguration:
+			list, err := client.Configurations(ns).List(ctx, opts)
+			if err != nil {
+				return err
+			}
+			for _, item := range list.Items {
+				add(item.ObjectMeta)
+			}
+		case KindRoute:
+			list, err := client.Routes(ns).List(ctx, opts)
+			if err != nil {
+				return err
+			}
+			for _, item := range list.Items {
+				add(item.ObjectMeta)
+			}
+		default:
+			list, err := client.Services(ns).List(ctx, opts)
+			if err != nil {
+				return err
+			}
+			for _, item := range list.Items {
+				add(item.ObjectMeta)
+			}
+		}
+		return nil
+	})
+	return names, err
+}
+
+func (m *Measurer) kind() string {
+	if m.Kind == "" {
+		return KindService
+	}
+	return m.Kind
+}
+
+// getTopLevel reads the resource the measurement starts from, the returned status is statusReady if it is ready
+func (m *Measurer) getTopLevel(ctx context.Context, c clients, name types.NamespacedName, trace *serviceTrace) (topLevel, serviceStatus) {
+	switch m.kind() {
+	case KindConfiguration:
+		return m.getConfigurationTopLevel(ctx, c, name, trace)
+	case KindRoute:
+		return m.getRouteTopLevel(ctx, c, name, trace)
+	default:
+		return m.getServiceTopLevel(ctx, c, name, trace)
+	}
+}
+
+// apiFailureReason returns FailureNotFound for not found errors and FailureAPIError for others
+func apiFailureReason(err error) string {
+	if notFoundStatus(err) == statusNotFound {
+		return pkg.FailureNotFound
+	}
+	return pkg.FailureAPIError
+}
+
+// conditionCause returns the reason and the message of a condition which isn't true to append to a message, or an
+// empty string if the condition is missing or has neither
+func conditionCause(cond *apis.Condition) string {
+	switch {
+	case cond == nil:
+		return " (no Ready condition)"
+	case cond.Reason == "" && cond.Message == "":
+		return ""
+	case cond.Message == "":
+		return fmt.Sprintf(" (%s)", cond.Reason)
+	}
+	return fmt.Sprintf(" (%s: %s)", cond.Reason, cond.Message)
+}
+
+// notFoundStatus returns statusNotFound for not found errors and statusFailed for others
+func notFoundStatus(err error) serviceStatus {
+	if strings.Contains(err.Error(), "not found") {
+		return statusNotFound
+	}
+	return statusFailed
+}
+
+func (m *Measurer) getServiceTopLevel(ctx context.Context, c clients, name types.NamespacedName, trace *serviceTrace) (topLevel, serviceStatus) {
+	var svcIns *servingv1api.Service
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		svcIns, err = c.serving.Services(name.Namespace).Get(ctx, name.Name, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, apiFailureReason(err), "failed to get Knative Service %s", err)
+		return topLevel{}, notFoundStatus(err)
+	}
+	trace.knative("Service", svcIns, svcIns.Status.Conditions)
+	trace.labels = svcIns.Labels
+	if !svcIns.IsReady() {
+		if status := m.rolloutStatus(ctx, c, name, svcIns.Status.LatestCreatedRevisionName, trace); status != statusNotReady {
+			return topLevel{}, status
+		}
+		m.fail(trace, pkg.FailureNotReady, "service %s/%s not ready%s and skip measuring", name.Namespace, name.Name,
+			conditionCause(svcIns.Status.GetCondition(servingv1api.ServiceConditionReady)))
+		return topLevel{}, statusNotReady
+	}
+	routesReady := m.truncate(svcIns.Status.GetCondition(servingv1api.ServiceConditionRoutesReady).LastTransitionTime.Inner)
+	return topLevel{
+		created:            m.truncate(svcIns.GetCreationTimestamp()),
+		configurationReady: m.truncate(svcIns.Status.GetCondition(servingv1api.ServiceConditionConfigurationsReady).LastTransitionTime.Inner),
+		routeReady:         routesReady,
+		ready:              routesReady,
+		configurationName:  name.Name,
+		ingressName:        name.Name,
+	}, statusReady
+}
+
+func (m *Measurer) getConfigurationTopLevel(ctx context.Context, c clients, name types.NamespacedName, trace *serviceTrace) (topLevel, serviceStatus) {
+	var cfgIns *servingv1api.Configuration
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		cfgIns, err = c.serving.Configurations(name.Namespace).Get(ctx, name.Name, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, apiFailureReason(err), "failed to get Configuration %s", err)
+		return topLevel{}, notFoundStatus(err)
+	}
+	trace.knative("Configuration", cfgIns, cfgIns.Status.Conditions)
+	trace.labels = cfgIns.Labels
+	if !cfgIns.IsReady() {
+		if status := m.rolloutStatus(ctx, c, name, cfgIns.Status.LatestCreatedRevisionName, trace); status != statusNotReady {
+			return topLevel{}, status
+		}
+		m.fail(trace, pkg.FailureNotReady, "configuration %s/%s not ready%s and skip measuring", name.Namespace, name.Name,
+			conditionCause(cfgIns.Status.GetCondition(servingv1api.ConfigurationConditionReady)))
+		return topLevel{}, statusNotReady
+	}
+	ready := m.truncate(cfgIns.Status.GetCondition(servingv1api.ConfigurationConditionReady).LastTransitionTime.Inner)
+	return topLevel{
+		created:            m.truncate(cfgIns.GetCreationTimestamp()),
+		configurationReady: ready,
+		ready:              ready,
+		configuration:      cfgIns,
+	}, statusReady
+}
+
+func (m *Measurer) getRouteTopLevel(ctx context.Context, c clients, name types.NamespacedName, trace *serviceTrace) (topLevel, serviceStatus) {
+	var routeIns *servingv1api.Route
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		routeIns, err = c.serving.Routes(name.Namespace).Get(ctx, name.Name, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, apiFailureReason(err), "failed to get Route %s", err)
+		return topLevel{}, notFoundStatus(err)
+	}
+	trace.knative("Route", routeIns, routeIns.Status.Conditions)
+	trace.labels = routeIns.Labels
+	if !routeIns.IsReady() {
+		m.fail(trace, pkg.FailureNotReady, "route %s/%s not ready%s and skip measuring", name.Namespace, name.Name,
+			conditionCause(routeIns.Status.GetCondition(servingv1api.RouteConditionReady)))
+		return topLevel{}, statusNotReady
+	}
+
+	// the measured revision is the first one which receives traffic
+	var revisionName string
+	for _, target := range routeIns.Status.Traffic {
+		if target.Percent != nil && *target.Percent > 0 {
+			revisionName = target.RevisionName
+			break
+		}
+	}
+	if revisionName == "" {
+		m.fail(trace, pkg.FailureNoTraffic, "route %s/%s routes no traffic and skip measuring", name.Name, name.Namespace)
+		return topLevel{}, statusNotReady
+	}
+	var configurationName string
+	for _, target := range routeIns.Spec.Traffic {
+		if target.ConfigurationName != "" {
+			configurationName = target.ConfigurationName
+			break
+		}
+	}
+	if configurationName == "" {
+		// the route pins revisions, the configuration is the one the revision was created for
+		var revisionIns *servingv1api.Revision
+		trace.api.start()
+		err := m.retry(ctx, func() (err error) {
+			revisionIns, err = c.serving.Revisions(name.Namespace).Get(ctx, revisionName, metav1.GetOptions{})
+			return err
+		})
+		trace.api.stop()
+		if err != nil {
+			m.fail(trace, pkg.FailureAPIError, "failed to get Revision and skip measuring %s", err)
+			return topLevel{}, statusNotReady
+		}
+		configurationName = revisionIns.Labels[serving.ConfigurationLabelKey]
+	}
+
+	ready := m.truncate(routeIns.Status.GetCondition(servingv1api.RouteConditionReady).LastTransitionTime.Inner)
+	return topLevel{
+		created:           m.truncate(routeIns.GetCreationTimestamp()),
+		routeReady:        ready,
+		ready:             ready,
+		configurationName: configurationName,
+		revisionName:      revisionName,
+		ingressName:       name.Name,
+	}, statusReady
+}
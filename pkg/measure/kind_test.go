@@ -0,0 +1,187 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/ptr"
+
+	networkingv1alpha1api "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestValidateKind(t *testing.T) {
+	for _, kind := range Kinds {
+		assert.NilError(t, ValidateKind(kind))
+	}
+	assert.ErrorContains(t, ValidateKind("revision"), "unsupported kind \"revision\", expected one of service,configuration,route")
+}
+
+// prependRevisionReactors returns the revision rev-00001 of the configuration cfg, and its PodAutoscaler and
+// ServerlessService, which are all ready 3s after created
+func prependRevisionReactors(fake *clienttesting.Fake, created time.Time) {
+	fake.PrependReactor("get", "revisions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		rev := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{
+			Name:              action.(clienttesting.GetAction).GetName(),
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{serving.ConfigurationLabelKey: "cfg"},
+		}}
+		rev.Status.Conditions = readyConditions(created, 3*time.Second, servingv1.RevisionConditionReady)
+		return true, rev, nil
+	})
+	fake.PrependReactor("get", "podautoscalers", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		kpa := &autoscalingv1alpha1.PodAutoscaler{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+		kpa.Status.Conditions = readyConditions(created, 3*time.Second, autoscalingv1alpha1.PodAutoscalerConditionActive)
+		return true, kpa, nil
+	})
+	fake.PrependReactor("get", "serverlessservices", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sks := &networkingv1alpha1api.ServerlessService{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+		sks.Status.Conditions = readyConditions(created, 3*time.Second, networkingv1alpha1api.ActivatorEndpointsPopulated,
+			networkingv1alpha1api.ServerlessServiceConditionEndspointsPopulated, networkingv1alpha1api.ServerlessServiceConditionReady)
+		return true, sks, nil
+	})
+}
+
+func newReadyConfiguration(name string, created time.Time) *servingv1.Configuration {
+	cfg := &servingv1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns-1", CreationTimestamp: metav1.NewTime(created)}}
+	cfg.Status.Conditions = readyConditions(created, 4*time.Second, servingv1.ConfigurationConditionReady)
+	cfg.Status.LatestReadyRevisionName = "rev-00001"
+	return cfg
+}
+
+func TestMeasureKinds(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:              "rev-00001-deployment",
+		Namespace:         "ns-1",
+		CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+	}}
+
+	t.Run("configuration", func(t *testing.T) {
+		p, fake := newMeasureTestParams(deployment)
+		prependRevisionReactors(fake, created)
+		fake.PrependReactor("get", "configurations", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newReadyConfiguration(action.(clienttesting.GetAction).GetName(), created), nil
+		})
+		ingresses := 0
+		fake.PrependReactor("get", "ingresses", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			ingresses++
+			return true, nil, nil
+		})
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Kind = KindConfiguration
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "cfg"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+		assert.Equal(t, 0, ingresses)
+		record := result.Records[0]
+		assert.Equal(t, "cfg", record.ServiceName)
+		assert.Equal(t, 4.0, record.ConfigurationReady)
+		assert.Equal(t, 3.0, record.RevisionReady)
+		assert.Equal(t, 0.0, record.RouteReady)
+		assert.Equal(t, 0.0, record.IngressReady)
+		assert.Equal(t, 4.0, record.OverallReady)
+		assert.Assert(t, result.RawRecords[0].RouteReady == nil)
+	})
+
+	t.Run("route pinning a revision", func(t *testing.T) {
+		p, fake := newMeasureTestParams(deployment)
+		prependRevisionReactors(fake, created)
+		var configurations []string
+		fake.PrependReactor("get", "configurations", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			name := action.(clienttesting.GetAction).GetName()
+			configurations = append(configurations, name)
+			return true, newReadyConfiguration(name, created), nil
+		})
+		fake.PrependReactor("get", "routes", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			route := &servingv1.Route{ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "ns-1", CreationTimestamp: metav1.NewTime(created.Add(time.Second))}}
+			route.Spec.Traffic = []servingv1.TrafficTarget{{RevisionName: "rev-00001", Percent: ptr.Int64(100)}}
+			route.Status.Traffic = []servingv1.TrafficTarget{{RevisionName: "rev-00001", Percent: ptr.Int64(100)}}
+			route.Status.Conditions = readyConditions(created, 6*time.Second, servingv1.RouteConditionReady)
+			return true, route, nil
+		})
+		fake.PrependReactor("get", "ingresses", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			assert.Equal(t, "route", action.(clienttesting.GetAction).GetName())
+			ingress := &networkingv1alpha1api.Ingress{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created.Add(3 * time.Second))}}
+			ingress.Status.Conditions = readyConditions(created, 5*time.Second, networkingv1alpha1api.IngressConditionNetworkConfigured,
+				networkingv1alpha1api.IngressConditionLoadBalancerReady)
+			return true, ingress, nil
+		})
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Kind = KindRoute
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "route"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+		// the configuration is found by the label of the revision the route pins
+		assert.DeepEqual(t, []string{"cfg"}, configurations)
+		record := result.Records[0]
+		assert.Equal(t, 4.0, record.ConfigurationReady)
+		assert.Equal(t, 5.0, record.RouteReady)
+		assert.Equal(t, 2.0, record.IngressReady)
+		assert.Equal(t, 5.0, record.OverallReady)
+	})
+
+	t.Run("route without traffic", func(t *testing.T) {
+		p, fake := newMeasureTestParams()
+		fake.PrependReactor("get", "routes", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			route := &servingv1.Route{}
+			route.Status.Conditions = readyConditions(created, time.Second, servingv1.RouteConditionReady)
+			return true, route, nil
+		})
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Kind = KindRoute
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "route"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.NotReadyCount)
+	})
+}
+
+func TestListKinds(t *testing.T) {
+	p, fake := newMeasureTestParams()
+	fake.PrependReactor("list", "configurations", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &servingv1.ConfigurationList{Items: []servingv1.Configuration{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cfg-1", Namespace: "ns-1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns-1"}},
+		}}, nil
+	})
+	fake.PrependReactor("list", "routes", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &servingv1.RouteList{Items: []servingv1.Route{{ObjectMeta: metav1.ObjectMeta{Name: "route-1", Namespace: "ns-1"}}}}, nil
+	})
+
+	measurer := NewMeasurer(p, nil, nil)
+	measurer.Kind = KindConfiguration
+	found, err := measurer.ListServices(context.Background(), []string{"ns-1"}, "cfg")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.NamespacedName{{Namespace: "ns-1", Name: "cfg-1"}}, found)
+
+	measurer.Kind = KindRoute
+	found, err = measurer.ListServices(context.Background(), []string{"ns-1"}, "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.NamespacedName{{Namespace: "ns-1", Name: "route-1"}}, found)
+}
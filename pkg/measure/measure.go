@@ -0,0 +1,1076 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package measure measures the creation time of Knative Services and the phases it consists of. It is used by
+// 'kperf service measure' and can be embedded by other tools to measure Knative Services programmatically.
+package measure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/montanaflynn/stats"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/dynamic"
+	networkingv1api "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	autoscalingv1api "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1api "knative.dev/serving/pkg/apis/serving/v1"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/pool"
+)
+
+// Logger receives the messages about services which can't be measured and the cluster information which
+// can't be read. *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// DefaultLogger writes the messages to stdout like the kperf commands do
+var DefaultLogger Logger = log.New(os.Stdout, "", 0)
+
+// Measurer measures the creation time of Knative Services
+type Measurer struct {
+	params *pkg.PerfParams
+	out    io.Writer
+	logger Logger
+
+	// Concurrency is the number of services measured in parallel
+	Concurrency int
+	// Verbose writes the durations of every measured service to the output
+	Verbose bool
+	// CheckpointFile is the state file the progress is written to, empty disables checkpointing
+	CheckpointFile string
+	// CheckpointInterval is how often the progress is written to CheckpointFile
+	CheckpointInterval time.Duration
+	// Resume reads the progress from CheckpointFile and only measures the services which are not processed yet
+	Resume bool
+	// Clock paces the checkpoints and times the workers, it defaults to the clock of the params
+	Clock clock.Clock
+	// DebugTimestamps collects every timestamp of the resources read for the services in the result
+	DebugTimestamps bool
+	// SummaryOnly only computes the summary, the records of the services are neither kept in the result nor streamed,
+	// and the percentiles are computed from the counts of the distinct durations instead of every duration
+	SummaryOnly bool
+	// CollectEvents collects the Events of the Revision, Deployment and Pods of every service in the result
+	CollectEvents bool
+	// Retries is how often a Get or List call failing with a transient error like throttling is retried
+	Retries int
+	// RetryBackoff is the backoff before the first retry, it doubles with every retry
+	RetryBackoff time.Duration
+	// ServiceTimeout is the deadline of the measurement of a single service, including its retries. A service whose
+	// measurement doesn't finish in time is counted as timed out, so that it can't stall its worker. 0 disables it.
+	ServiceTimeout time.Duration
+	// Kind is the kind of the resources the measurement starts from, one of Kinds, it defaults to KindService
+	Kind string
+	// Stream receives the record of every ready service as JSON line as soon as the service is measured
+	Stream io.Writer
+	// Labels are the label keys whose values are collected for every service in Result.Labels, e.g. to group the
+	// results by them
+	Labels []string
+	// CreatedAfter skips the resources created before this time when they are listed, the zero time lists all
+	CreatedAfter time.Time
+	// Precision is the unit the timestamps are truncated to before the durations are computed, it defaults to a
+	// second
+	Precision time.Duration
+	// UserContainerName is the name of the container serving the requests whose start is measured, it is detected
+	// from the containers of the revision if it is empty
+	UserContainerName string
+	// ImagePulls breaks down the start of the containers of every ready service into the image pull and the container
+	// start, read from the Events of its Pod
+	ImagePulls bool
+	// ExtraMetrics are called when the services are measured, the custom metrics they return are merged into
+	// Summary.Custom of the result, e.g. to attach measurements of other systems during the run to the same report
+	ExtraMetrics []ExtraMetrics
+}
+
+// Result is the measurement of a set of Knative Services
+type Result struct {
+	// Summary holds the number of services by state, and the averages and percentiles of the ready services
+	Summary pkg.MeasureResult
+	// Records holds the durations of every ready service in seconds with the precision of the Measurer, it is empty
+	// if the Measurer only computes the summary
+	Records []pkg.MeasureRecord
+	// RawRecords holds the timestamps of every ready service, it is empty if the Measurer only computes the summary
+	RawRecords []pkg.MeasureRawRecord
+	// NamespaceCounts holds the number of services by state in every namespace
+	NamespaceCounts map[string]pkg.ServiceCount
+	// States maps the namespace/name of every measured service to its state, one of the State constants, it is empty
+	// if the Measurer only computes the summary
+	States map[string]string
+	// Labels maps the namespace/name of every service which was found to the values of the labels of the Measurer
+	Labels map[string]map[string]string
+	// Workers holds the services measured by every worker and where the worker spent its time
+	Workers []pkg.WorkerMeasureResult
+	// DebugTimestamps holds every timestamp of the resources read for the services if the Measurer collects them
+	DebugTimestamps []pkg.DebugTimestamp
+	// Events holds the Events of the resources of the services if the Measurer collects them
+	Events []pkg.ServiceEvent
+}
+
+// NewMeasurer returns a Measurer which writes the verbose output to out and the messages to logger.
+// A nil out or logger discards the output or the messages.
+func NewMeasurer(params *pkg.PerfParams, out io.Writer, logger Logger) *Measurer {
+	if out == nil {
+		out = ioutil.Discard
+	}
+	if logger == nil {
+		logger = log.New(ioutil.Discard, "", 0)
+	}
+	var c clock.Clock = clock.RealClock{}
+	if params.Clock != nil {
+		c = params.Clock
+	}
+	return &Measurer{params: params, out: out, logger: logger, Concurrency: 10, CheckpointInterval: 10 * time.Second, Clock: c,
+		Retries: 3, RetryBackoff: time.Second}
+}
+
+// ListServices returns the services with the name prefix in the namespaces
+func (m *Measurer) ListServices(ctx context.Context, namespaces []string, prefix string) ([]types.NamespacedName, error) {
+	servingClient, err := m.params.NewServingClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create serving client%s\n", err)
+	}
+	var services []types.NamespacedName
+	for _, ns := range namespaces {
+		found, err := m.list(ctx, servingClient, ns, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s under namespace %s error:%v", m.kind(), ns, err)
+		}
+		if len(found) == 0 {
+			m.logger.Printf("no %s found under namespace %s and skip\n", m.kind(), ns)
+			continue
+		}
+		for _, svc := range found {
+			if strings.HasPrefix(svc.Name, prefix) {
+				services = append(services, svc)
+			}
+		}
+	}
+	return services, nil
+}
+
+// SelectServices returns the services matching the label selector in the namespaces, or in all namespaces if
+// no namespace is given
+func (m *Measurer) SelectServices(ctx context.Context, namespaces []string, selector string) ([]types.NamespacedName, error) {
+	servingClient, err := m.params.NewServingClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create serving client%s\n", err)
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	var services []types.NamespacedName
+	for _, ns := range namespaces {
+		found, err := m.list(ctx, servingClient, ns, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s with selector %s under namespace %s error:%v", m.kind(), selector, ns, err)
+		}
+		services = append(services, found...)
+	}
+	return services, nil
+}
+
+// Measure measures the services. Services which are not found, not ready or which can't be measured are
+// counted in the summary and logged, the returned error is only about the measurement as a whole.
+func (m *Measurer) Measure(ctx context.Context, services []types.NamespacedName) (*Result, error) {
+	c, err := m.newClients()
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, errors.New("no service found to measure")
+	}
+	if UsesGatewayAPI(ctx, m.params) {
+		if c.gateway, err = m.params.NewDynamicClient(); err != nil {
+			m.logger.Printf("failed to create dynamic client and skip the HTTPRoutes %s\n", err)
+			c.gateway = nil
+		}
+	}
+
+	result := &Result{
+		Summary:    pkg.MeasureResult{SvcReadyTime: make([]float64, 0)},
+		Records:    make([]pkg.MeasureRecord, 0),
+		RawRecords: make([]pkg.MeasureRawRecord, 0),
+
+		NamespaceCounts: make(map[string]pkg.ServiceCount),
+		Labels:          make(map[string]map[string]string),
+	}
+	checkpoint := &Checkpoint{Processed: make(map[string]string)}
+	if m.Resume && m.CheckpointFile != "" {
+		previous, err := ReadCheckpoint(m.CheckpointFile)
+		switch {
+		case os.IsNotExist(err):
+			m.logger.Printf("checkpoint %s not found, measuring all services\n", m.CheckpointFile)
+		case err != nil:
+			return nil, err
+		default:
+			services = previous.restore(result, services)
+			for svc, status := range previous.Processed {
+				checkpoint.Processed[svc] = status
+			}
+			m.logger.Printf("resuming from checkpoint %s, %d services already processed\n", m.CheckpointFile, len(previous.Processed))
+		}
+	}
+
+	// the workers pass the outcome of every service to the collector, which is the only one changing the result, so
+	// that the workers share no state. The checkpoints are copies of the progress written by their own goroutine.
+	a := &aggregation{
+		measurer:   m,
+		result:     result,
+		checkpoint: checkpoint,
+		ready:      newReadyDistribution(),
+		phases:     phaseDistributions{},
+		busyTimes:  make(map[int]time.Duration),
+		apiTimes:   make(map[int]time.Duration),
+	}
+	snapshots := make(chan *Checkpoint, 1)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for snapshot := range snapshots {
+			if err := WriteCheckpoint(m.CheckpointFile, snapshot); err != nil {
+				m.logger.Printf("%s\n", err)
+			}
+		}
+	}()
+	var tick <-chan time.Time
+	if m.CheckpointFile != "" && m.CheckpointInterval > 0 {
+		ticker := m.Clock.NewTicker(m.CheckpointInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	start := m.Clock.Now()
+	workerStats := pool.Collect(ctx, m.Concurrency, len(services), func(ctx context.Context, i int) interface{} {
+		return m.measureOutcome(ctx, c, services[i])
+	}, func(r pool.Result) {
+		a.add(r.Worker, r.Value.(serviceOutcome))
+		select {
+		case <-tick:
+			// a checkpoint is skipped while the previous one is still written, the progress is only checkpointed
+			// when a service was measured
+			select {
+			case snapshots <- a.snapshot():
+			default:
+			}
+		default:
+		}
+	})
+	for worker := range workerStats {
+		workerStats[worker].Busy = a.busyTimes[worker]
+	}
+	result.Workers = workerResults(workerStats, a.apiTimes, m.Clock.Since(start))
+	if m.CheckpointFile != "" {
+		snapshots <- a.snapshot()
+	}
+	close(snapshots)
+	<-written
+	result.States = checkpoint.Processed
+
+	sortRecords(result.Records)
+	sortRawRecords(result.RawRecords)
+	sortDebugTimestamps(result.DebugTimestamps)
+	sortServiceEvents(result.Events)
+	sortFailedRollouts(result.Summary.FailedRollouts)
+	sortFailures(result.Summary.Failures)
+	sortContainers(result.Summary.Containers)
+	sortImagePulls(result.Summary.ImagePulls)
+	sortGatewayRoutes(result.Summary.GatewayRoutes)
+	if m.SummaryOnly {
+		summarizeAverages(&result.Summary)
+		a.ready.summarize(&result.Summary.Result)
+		a.phases.summarize(&result.Summary.Result)
+	} else {
+		summarize(&result.Summary)
+	}
+	if ctx.Err() != nil {
+		// the partial result is completed with a fresh context, the cancelled one fails every API call
+		result.Summary.Interrupted = len(services) - a.measured
+		m.logger.Printf("measurement interrupted, %d of %d services measured\n", a.measured, len(services))
+		ctx = context.Background()
+	}
+	result.Summary.Custom = m.collectExtraMetrics(ctx, start, m.Clock.Now())
+	result.Summary.KnativeInfo = GetKnativeInfo(ctx, m.params, m.logger)
+	return result, nil
+}
+
+// serviceOutcome is the measurement of a single service a worker passes to the collector
+type serviceOutcome struct {
+	service   types.NamespacedName
+	record    pkg.MeasureRecord
+	rawRecord pkg.MeasureRawRecord
+	status    serviceStatus
+	trace     *serviceTrace
+	// busy is the time the worker spent measuring the service
+	busy time.Duration
+	// cancelled is set when the measurement was cancelled before the service was found ready
+	cancelled bool
+}
+
+// measureOutcome measures the service within the ServiceTimeout and times the worker
+func (m *Measurer) measureOutcome(ctx context.Context, c clients, svc types.NamespacedName) serviceOutcome {
+	busy := &stopwatch{clock: m.Clock}
+	trace := &serviceTrace{service: svc, api: stopwatch{clock: m.Clock}, debug: m.DebugTimestamps}
+	busy.start()
+	record, rawRecord, status := m.measureServiceWithin(ctx, c, svc, trace)
+	busy.stop()
+	return serviceOutcome{
+		service:   svc,
+		record:    record,
+		rawRecord: rawRecord,
+		status:    status,
+		trace:     trace,
+		busy:      busy.total,
+		cancelled: status != statusReady && ctx.Err() != nil,
+	}
+}
+
+// aggregation adds up the outcomes of the services in the result. It is only used by the goroutine collecting the
+// outcomes, so it needs no lock.
+type aggregation struct {
+	measurer   *Measurer
+	result     *Result
+	checkpoint *Checkpoint
+	ready      *readyDistribution
+	phases     phaseDistributions
+	// the busy and API times of the workers are measured with the clock of the measurer
+	busyTimes map[int]time.Duration
+	apiTimes  map[int]time.Duration
+	measured  int
+}
+
+// add adds the outcome of a service measured by the worker
+func (a *aggregation) add(worker int, o serviceOutcome) {
+	m, result := a.measurer, a.result
+	a.busyTimes[worker] += o.busy
+	a.apiTimes[worker] += o.trace.api.total
+	if o.cancelled {
+		// the service is not counted and not checkpointed when the measurement was cancelled, so that it is
+		// measured again on resume
+		return
+	}
+	a.measured++
+	svc, record, status, trace := o.service, o.record, o.status, o.trace
+	result.DebugTimestamps = append(result.DebugTimestamps, trace.timestamps...)
+	result.Events = append(result.Events, trace.events...)
+	if !m.SummaryOnly {
+		a.checkpoint.Processed[svc.String()] = statusNames[status]
+	}
+	if len(m.Labels) > 0 && trace.labels != nil {
+		values := map[string]string{}
+		for _, key := range m.Labels {
+			if value, ok := trace.labels[key]; ok {
+				values[key] = value
+			}
+		}
+		result.Labels[svc.String()] = values
+	}
+	result.count(svc.Namespace, status)
+	if status != statusReady {
+		f := failure{reason: pkg.FailureUnknown}
+		if trace.failure != nil {
+			f = *trace.failure
+		}
+		result.Summary.Failures = append(result.Summary.Failures, pkg.ServiceFailure{
+			ServiceName:      svc.Name,
+			ServiceNamespace: svc.Namespace,
+			State:            statusNames[status],
+			Reason:           f.reason,
+			Message:          f.message,
+		})
+	}
+	if trace.rollout != nil {
+		result.Summary.FailedRollouts = append(result.Summary.FailedRollouts, *trace.rollout)
+	}
+	if status == statusReady && m.SummaryOnly {
+		addPhaseSums(&result.Summary, record)
+		a.ready.add(record.OverallReady)
+		a.phases.add(record)
+		if m.Verbose {
+			writeVerbose(m.out, record)
+		}
+	} else if status == statusReady {
+		result.Records = append(result.Records, record)
+		result.RawRecords = append(result.RawRecords, o.rawRecord)
+		result.Summary.Containers = append(result.Summary.Containers, trace.containers...)
+		result.Summary.ImagePulls = append(result.Summary.ImagePulls, trace.imagePulls...)
+		if trace.gatewayRoute != nil {
+			result.Summary.GatewayRoutes = append(result.Summary.GatewayRoutes, *trace.gatewayRoute)
+		}
+		addSums(&result.Summary, record)
+		if m.Verbose {
+			writeVerbose(m.out, record)
+		}
+		if m.Stream != nil {
+			if err := json.NewEncoder(m.Stream).Encode(record); err != nil {
+				m.logger.Printf("failed to stream record of service %s: %s\n", svc, err)
+			}
+		}
+	}
+}
+
+// snapshot returns a copy of the progress, which can be written while the outcomes are added
+func (a *aggregation) snapshot() *Checkpoint {
+	snapshot := &Checkpoint{
+		Processed:  make(map[string]string, len(a.checkpoint.Processed)),
+		Records:    append([]pkg.MeasureRecord(nil), a.result.Records...),
+		RawRecords: append([]pkg.MeasureRawRecord(nil), a.result.RawRecords...),
+	}
+	for svc, status := range a.checkpoint.Processed {
+		snapshot.Processed[svc] = status
+	}
+	return snapshot
+}
+
+// count counts a measured service in the summary and in its namespace
+func (r *Result) count(namespace string, status serviceStatus) {
+	nsCount := r.NamespaceCounts[namespace]
+	for _, c := range []*pkg.ServiceCount{&r.Summary.Service, &nsCount} {
+		switch status {
+		case statusNotFound:
+			c.NotFoundCount++
+		case statusNotReady:
+			c.NotReadyCount++
+		case statusFailed:
+			c.FailCount++
+		case statusProgressDeadlineExceeded:
+			c.ProgressDeadlineExceededCount++
+		case statusTimedOut:
+			c.TimedOutCount++
+		default:
+			c.ReadyCount++
+		}
+	}
+	r.NamespaceCounts[namespace] = nsCount
+}
+
+type clients struct {
+	serving     servingv1client.ServingV1Interface
+	autoscaling autoscalingv1alpha1.AutoscalingV1alpha1Interface
+	networking  networkingv1alpha1.NetworkingV1alpha1Interface
+	// gateway reads the HTTPRoutes and Gateways of the Gateway API ingress implementation, nil if the cluster uses
+	// another one
+	gateway dynamic.Interface
+}
+
+func (m *Measurer) newClients() (clients, error) {
+	autoscalingClient, err := m.params.NewAutoscalingClient()
+	if err != nil {
+		return clients{}, fmt.Errorf("failed to create autoscaling client%s\n", err)
+	}
+	servingClient, err := m.params.NewServingClient()
+	if err != nil {
+		return clients{}, fmt.Errorf("failed to create serving client%s\n", err)
+	}
+	nwclient, err := m.params.NewNetworkingClient()
+	if err != nil {
+		return clients{}, fmt.Errorf("failed to create networking client%s\n", err)
+	}
+	return clients{serving: servingClient, autoscaling: autoscalingClient, networking: nwclient}, nil
+}
+
+type serviceStatus int
+
+const (
+	statusReady serviceStatus = iota
+	statusNotFound
+	statusNotReady
+	statusFailed
+	// statusProgressDeadlineExceeded is a service which is not ready since the Deployment of its revision didn't
+	// make progress within the progress deadline, e.g. as its image can't be pulled
+	statusProgressDeadlineExceeded
+	// statusTimedOut is a service whose measurement didn't finish within the ServiceTimeout, e.g. as an API call hangs
+	statusTimedOut
+)
+
+// measureServiceWithin measures the service within the ServiceTimeout. The measurement runs with its own trace, so
+// that a measurement which is given up can't change the trace after the timeout. Its API calls are cancelled, but
+// calls which don't return on cancellation are left behind and their results are discarded.
+func (m *Measurer) measureServiceWithin(ctx context.Context, c clients, name types.NamespacedName, trace *serviceTrace) (pkg.MeasureRecord, pkg.MeasureRawRecord, serviceStatus) {
+	if m.ServiceTimeout <= 0 {
+		return m.measureService(ctx, c, name, trace)
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.ServiceTimeout)
+	defer cancel()
+
+	type measured struct {
+		record    pkg.MeasureRecord
+		rawRecord pkg.MeasureRawRecord
+		status    serviceStatus
+		trace     serviceTrace
+	}
+	done := make(chan measured, 1)
+	own := *trace
+	go func() {
+		record, rawRecord, status := m.measureService(ctx, c, name, &own)
+		done <- measured{record: record, rawRecord: rawRecord, status: status, trace: own}
+	}()
+	select {
+	case r := <-done:
+		*trace = r.trace
+		return r.record, r.rawRecord, r.status
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// the measurement is cancelled as a whole, its API calls return with the cancellation
+			r := <-done
+			*trace = r.trace
+			return r.record, r.rawRecord, r.status
+		}
+		m.fail(trace, pkg.FailureTimeout, "measuring service %s timed out after %s and skip measuring", name, m.ServiceTimeout)
+		return pkg.MeasureRecord{}, pkg.MeasureRawRecord{}, statusTimedOut
+	}
+}
+
+// fail logs the message why the service can't be measured and keeps it with the reason in trace
+func (m *Measurer) fail(trace *serviceTrace, reason, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	m.logger.Printf("%s\n", message)
+	trace.failure = &failure{reason: reason, message: message}
+}
+
+// measureService reads the timestamps of the service and the resources created for it,
+// and collects the time spent in API calls and the debug timestamps in trace
+func (m *Measurer) measureService(ctx context.Context, c clients, name types.NamespacedName, trace *serviceTrace) (pkg.MeasureRecord, pkg.MeasureRawRecord, serviceStatus) {
+	var (
+		record    pkg.MeasureRecord
+		rawRecord pkg.MeasureRawRecord
+
+		podScheduledDuration, containersReadyDuration, queueProxyStartedDuration, userContrainerStartedDuration time.Duration
+	)
+	svc := name.Name
+	svcNs := name.Namespace
+	top, status := m.getTopLevel(ctx, c, name, trace)
+	if status != statusReady {
+		return record, rawRecord, status
+	}
+
+	svcCreatedTime := top.created
+	svcRoutesReady := top.routeReady
+
+	svcReadyDuration := top.ready.Sub(svcCreatedTime.Time)
+	var svcRoutesReadyDuration time.Duration
+	if !svcRoutesReady.IsZero() {
+		svcRoutesReadyDuration = svcRoutesReady.Sub(svcCreatedTime.Time)
+	}
+
+	cfgIns := top.configuration
+	if cfgIns == nil {
+		trace.api.start()
+		err := m.retry(ctx, func() (err error) {
+			cfgIns, err = c.serving.Configurations(svcNs).Get(ctx, top.configurationName, metav1.GetOptions{})
+			return err
+		})
+		trace.api.stop()
+		if err != nil {
+			m.fail(trace, pkg.FailureAPIError, "failed to get Configuration and skip measuring %s", err)
+			return record, rawRecord, statusNotReady
+		}
+		trace.knative("Configuration", cfgIns, cfgIns.Status.Conditions)
+	}
+	// a Service reports when its configuration is ready, otherwise the Configuration is measured on its own
+	svcConfigurationsReady := top.configurationReady
+	configurationStart := svcCreatedTime
+	if svcConfigurationsReady.IsZero() {
+		svcConfigurationsReady = m.truncate(cfgIns.Status.GetCondition(servingv1api.ConfigurationConditionReady).LastTransitionTime.Inner)
+		configurationStart = m.truncate(cfgIns.GetCreationTimestamp())
+	}
+	svcConfigurationsReadyDuration := svcConfigurationsReady.Sub(configurationStart.Time)
+	revisionName := top.revisionName
+	if revisionName == "" {
+		revisionName = cfgIns.Status.LatestReadyRevisionName
+	}
+
+	var revisionIns *servingv1api.Revision
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		revisionIns, err = c.serving.Revisions(svcNs).Get(ctx, revisionName, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, pkg.FailureAPIError, "failed to get Revision and skip measuring %s", err)
+		return record, rawRecord, statusNotReady
+	}
+	trace.knative("Revision", revisionIns, revisionIns.Status.Conditions)
+	m.collectEvents(ctx, trace, "Revision", revisionName)
+
+	revisionCreatedTime := m.truncate(revisionIns.GetCreationTimestamp())
+	revisionReadyTime := m.truncate(revisionIns.Status.GetCondition(servingv1api.RevisionConditionReady).LastTransitionTime.Inner)
+	revisionReadyDuration := revisionReadyTime.Sub(revisionCreatedTime.Time)
+
+	label := fmt.Sprintf("serving.knative.dev/revision=%s", revisionName)
+	var podList *corev1.PodList
+	trace.api.start()
+	err = m.retry(ctx, func() (err error) {
+		podList, err = m.params.ClientSet.CoreV1().Pods(svcNs).List(ctx, metav1.ListOptions{LabelSelector: label})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, pkg.FailureAPIError, "list Pods of revision[%s] error :%v", revisionName, err)
+		return record, rawRecord, statusNotReady
+	}
+	for i := range podList.Items {
+		trace.pod(&podList.Items[i])
+		m.collectEvents(ctx, trace, "Pod", podList.Items[i].Name)
+	}
+
+	deploymentName := revisionName + "-deployment"
+	var deploymentIns *appsv1.Deployment
+	trace.api.start()
+	err = m.retry(ctx, func() (err error) {
+		deploymentIns, err = m.params.ClientSet.AppsV1().Deployments(svcNs).Get(ctx, deploymentName, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, pkg.FailureAPIError, "failed to find deployment of revision[%s] error:%v", revisionName, err)
+		return record, rawRecord, statusNotReady
+	}
+	trace.deployment(deploymentIns)
+	m.collectEvents(ctx, trace, "Deployment", deploymentName)
+
+	deploymentCreatedTime := m.truncate(deploymentIns.GetCreationTimestamp())
+	deploymentCreatedDuration := deploymentCreatedTime.Sub(revisionCreatedTime.Time)
+
+	var podCreatedTime, podScheduledTime, containersReadyTime, queueProxyStartedTime,
+		userContrainerStartedTime metav1.Time
+	// the node of the pod, so that slow nodes can be told apart, e.g. ones with a cold image cache
+	var nodeName string
+	if len(podList.Items) > 0 {
+		pod := podList.Items[0]
+		nodeName = pod.Spec.NodeName
+		podCreatedTime = m.truncate(pod.GetCreationTimestamp())
+		present, PodScheduledCdt := GetPodCondition(&pod.Status, corev1.PodScheduled)
+		if present == -1 {
+			m.fail(trace, pkg.FailureMissingCondition, "failed to find Pod Condition PodScheduled and skip measuring")
+			return record, rawRecord, statusNotReady
+		}
+		podScheduledTime = m.truncate(PodScheduledCdt.LastTransitionTime)
+		present, containersReadyCdt := GetPodCondition(&pod.Status, corev1.ContainersReady)
+		if present == -1 {
+			m.fail(trace, pkg.FailureMissingCondition, "failed to find Pod Condition ContainersReady and skip measuring")
+			return record, rawRecord, statusNotReady
+		}
+		containersReadyTime = m.truncate(containersReadyCdt.LastTransitionTime)
+		podScheduledDuration = podScheduledTime.Sub(podCreatedTime.Time)
+		containersReadyDuration = containersReadyTime.Sub(podCreatedTime.Time)
+
+		started, found := containerStarted(pod.Status.ContainerStatuses, QueueProxyContainerName)
+		if !found {
+			m.fail(trace, pkg.FailureMissingContainerStatus, "failed to get %s container status and skip", QueueProxyContainerName)
+			return record, rawRecord, statusNotReady
+		}
+		queueProxyStartedTime = m.truncate(started)
+
+		// the user container is the one serving the requests, the other containers of a multi-container revision
+		// are broken down on their own
+		userContainer := m.UserContainerName
+		if userContainer == "" {
+			userContainer = UserContainerName(revisionIns.Spec.Containers)
+		}
+		started, found = containerStarted(pod.Status.ContainerStatuses, userContainer)
+		if !found {
+			m.fail(trace, pkg.FailureMissingContainerStatus, "failed to get %s container status and skip", userContainer)
+			return record, rawRecord, statusNotReady
+		}
+		userContrainerStartedTime = m.truncate(started)
+		if len(revisionIns.Spec.Containers) > 1 {
+			for _, container := range revisionIns.Spec.Containers {
+				started, found := containerStarted(pod.Status.ContainerStatuses, container.Name)
+				if !found {
+					continue
+				}
+				trace.containers = append(trace.containers, pkg.ContainerStarted{
+					ServiceName:      svc,
+					ServiceNamespace: svcNs,
+					Container:        container.Name,
+					Serving:          container.Name == userContainer,
+					Started:          m.truncate(started).Sub(podCreatedTime.Time).Seconds(),
+				})
+			}
+		}
+
+		if m.ImagePulls {
+			m.collectImagePulls(ctx, trace, &pod, podCreatedTime)
+		}
+
+		queueProxyStartedDuration = queueProxyStartedTime.Sub(podCreatedTime.Time)
+		userContrainerStartedDuration = userContrainerStartedTime.Sub(podCreatedTime.Time)
+	}
+	// TODO: Need to figure out a better way to measure PA time as its status keeps changing even after service creation.
+
+	var kpaIns *autoscalingv1api.PodAutoscaler
+	trace.api.start()
+	err = m.retry(ctx, func() (err error) {
+		kpaIns, err = c.autoscaling.PodAutoscalers(svcNs).Get(ctx, revisionName, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, pkg.FailureAPIError, "failed to get PodAutoscaler %s", err)
+		return record, rawRecord, statusNotReady
+	}
+	trace.knative("PodAutoscaler", kpaIns, kpaIns.Status.Conditions)
+	kpaCreatedTime := m.truncate(kpaIns.GetCreationTimestamp())
+	kpaActiveTime := m.truncate(kpaIns.Status.GetCondition(autoscalingv1api.PodAutoscalerConditionActive).LastTransitionTime.Inner)
+	kpaActiveDuration := kpaActiveTime.Sub(kpaCreatedTime.Time)
+
+	var sksIns *networkingv1api.ServerlessService
+	trace.api.start()
+	err = m.retry(ctx, func() (err error) {
+		sksIns, err = c.networking.ServerlessServices(svcNs).Get(ctx, revisionName, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		m.fail(trace, pkg.FailureAPIError, "failed to get ServerlessService %s", err)
+		return record, rawRecord, statusNotReady
+	}
+	trace.knative("ServerlessService", sksIns, sksIns.Status.Conditions)
+	sksCreatedTime := m.truncate(sksIns.GetCreationTimestamp())
+	sksActivatorEndpointsPopulatedTime := m.truncate(sksIns.Status.GetCondition(networkingv1api.ActivatorEndpointsPopulated).LastTransitionTime.Inner)
+	sksEndpointsPopulatedTime := m.truncate(sksIns.Status.GetCondition(networkingv1api.ServerlessServiceConditionEndspointsPopulated).LastTransitionTime.Inner)
+	sksReadyTime := m.truncate(sksIns.Status.GetCondition(networkingv1api.ServerlessServiceConditionReady).LastTransitionTime.Inner)
+	sksActivatorEndpointsPopulatedDuration := sksActivatorEndpointsPopulatedTime.Sub(sksCreatedTime.Time)
+	sksEndpointsPopulatedDuration := sksEndpointsPopulatedTime.Sub(sksCreatedTime.Time)
+	sksReadyDuration := sksReadyTime.Sub(sksCreatedTime.Time)
+
+	var ingressCreatedTime, ingressNetworkConfiguredTime, ingressLoadBalancerReadyTime metav1.Time
+	var ingressNetworkConfiguredDuration, ingressLoadBalancerReadyDuration, ingressReadyDuration time.Duration
+	if top.ingressName != "" {
+		var ingressIns *networkingv1api.Ingress
+		trace.api.start()
+		err = m.retry(ctx, func() (err error) {
+			ingressIns, err = c.networking.Ingresses(svcNs).Get(ctx, top.ingressName, metav1.GetOptions{})
+			return err
+		})
+		trace.api.stop()
+		if err != nil {
+			m.fail(trace, pkg.FailureAPIError, "failed to get Ingress %s", err)
+			return record, rawRecord, statusNotReady
+		}
+		trace.knative("Ingress", ingressIns, ingressIns.Status.Conditions)
+		ingressCreatedTime = m.truncate(ingressIns.GetCreationTimestamp())
+		ingressNetworkConfiguredTime = m.truncate(ingressIns.Status.GetCondition(networkingv1api.IngressConditionNetworkConfigured).LastTransitionTime.Inner)
+		ingressLoadBalancerReadyTime = m.truncate(ingressIns.Status.GetCondition(networkingv1api.IngressConditionLoadBalancerReady).LastTransitionTime.Inner)
+		ingressNetworkConfiguredDuration = ingressNetworkConfiguredTime.Sub(ingressCreatedTime.Time)
+		ingressLoadBalancerReadyDuration = ingressLoadBalancerReadyTime.Sub(ingressNetworkConfiguredTime.Time)
+		ingressReadyDuration = ingressLoadBalancerReadyTime.Sub(ingressCreatedTime.Time)
+		if c.gateway != nil {
+			m.collectGatewayRoutes(ctx, c.gateway, trace, top.ingressName, ingressCreatedTime)
+		}
+	}
+
+	// the Certificates of the route are only created when auto-TLS is enabled, the route is ready once the last of
+	// them is ready
+	var certificateCreatedTime, certificateReadyTime metav1.Time
+	var certificateReadyDuration time.Duration
+	if top.ingressName != "" {
+		var certificateList *networkingv1api.CertificateList
+		trace.api.start()
+		err = m.retry(ctx, func() (err error) {
+			certificateList, err = c.networking.Certificates(svcNs).List(ctx, metav1.ListOptions{
+				LabelSelector: fmt.Sprintf("%s=%s", serving.RouteLabelKey, top.ingressName),
+			})
+			return err
+		})
+		trace.api.stop()
+		if err != nil {
+			m.fail(trace, pkg.FailureAPIError, "failed to list Certificates %s", err)
+			return record, rawRecord, statusNotReady
+		}
+		for i := range certificateList.Items {
+			certificate := &certificateList.Items[i]
+			trace.knative("Certificate", certificate, certificate.Status.Conditions)
+			created := m.truncate(certificate.GetCreationTimestamp())
+			if certificateCreatedTime.IsZero() || created.Before(&certificateCreatedTime) {
+				certificateCreatedTime = created
+			}
+			ready := m.truncate(certificate.Status.GetCondition(networkingv1api.CertificateConditionReady).LastTransitionTime.Inner)
+			if certificateReadyTime.Before(&ready) {
+				certificateReadyTime = ready
+			}
+		}
+		if !certificateReadyTime.IsZero() {
+			certificateReadyDuration = certificateReadyTime.Sub(certificateCreatedTime.Time)
+		}
+	}
+
+	rawRecord = pkg.MeasureRawRecord{
+		ServiceName:                    svc,
+		ServiceNamespace:               svcNs,
+		ServiceCreated:                 timestampMillis(svcCreatedTime),
+		ConfigurationReady:             timestampMillis(svcConfigurationsReady),
+		RevisionCreated:                timestampMillis(revisionCreatedTime),
+		RevisionReady:                  timestampMillis(revisionReadyTime),
+		DeploymentCreated:              timestampMillis(deploymentCreatedTime),
+		PodCreated:                     timestampMillis(podCreatedTime),
+		PodScheduled:                   timestampMillis(podScheduledTime),
+		ContainersReady:                timestampMillis(containersReadyTime),
+		QueueProxyStarted:              timestampMillis(queueProxyStartedTime),
+		UserContainerStarted:           timestampMillis(userContrainerStartedTime),
+		RouteReady:                     timestampMillis(svcRoutesReady),
+		KpaCreated:                     timestampMillis(kpaCreatedTime),
+		KpaActive:                      timestampMillis(kpaActiveTime),
+		SksCreated:                     timestampMillis(sksCreatedTime),
+		SksActivatorEndpointsPopulated: timestampMillis(sksActivatorEndpointsPopulatedTime),
+		SksEndpointsPopulated:          timestampMillis(sksEndpointsPopulatedTime),
+		IngressCreated:                 timestampMillis(ingressCreatedTime),
+		IngressConfigReady:             timestampMillis(ingressNetworkConfiguredTime),
+		IngressLoadBalancerReady:       timestampMillis(ingressLoadBalancerReadyTime),
+		CertificateCreated:             timestampMillis(certificateCreatedTime),
+		CertificateReady:               timestampMillis(certificateReadyTime),
+	}
+
+	record = pkg.MeasureRecord{
+		ServiceName:                    svc,
+		ServiceNamespace:               svcNs,
+		NodeName:                       nodeName,
+		ConfigurationReady:             svcConfigurationsReadyDuration.Seconds(),
+		RevisionReady:                  revisionReadyDuration.Seconds(),
+		DeploymentCreated:              deploymentCreatedDuration.Seconds(),
+		PodScheduled:                   podScheduledDuration.Seconds(),
+		ContainersReady:                containersReadyDuration.Seconds(),
+		QueueProxyStarted:              queueProxyStartedDuration.Seconds(),
+		UserContainerStarted:           userContrainerStartedDuration.Seconds(),
+		RouteReady:                     svcRoutesReadyDuration.Seconds(),
+		KpaActive:                      kpaActiveDuration.Seconds(),
+		SksReady:                       sksReadyDuration.Seconds(),
+		SksActivatorEndpointsPopulated: sksActivatorEndpointsPopulatedDuration.Seconds(),
+		SksEndpointsPopulated:          sksEndpointsPopulatedDuration.Seconds(),
+		IngressReady:                   ingressReadyDuration.Seconds(),
+		IngressConfigReady:             ingressNetworkConfiguredDuration.Seconds(),
+		IngressLoadBalancerReady:       ingressLoadBalancerReadyDuration.Seconds(),
+		CertificateReady:               certificateReadyDuration.Seconds(),
+		OverallReady:                   svcReadyDuration.Seconds(),
+	}
+	return record, rawRecord, statusReady
+}
+
+// writeVerbose writes the durations of a single service
+func writeVerbose(w io.Writer, r pkg.MeasureRecord) {
+	svc := r.ServiceName
+	line := func(format string, seconds float64) {
+		d := time.Duration(seconds * float64(time.Second))
+		fmt.Fprintf(w, format, svc, d, seconds)
+	}
+	line("[Verbose] Service %s: Service Configuration Ready Duration is %s/%fs\n", r.ConfigurationReady)
+	line("[Verbose] Service %s: - Service Revision Ready Duration is %s/%fs\n", r.RevisionReady)
+	line("[Verbose] Service %s:   - Service Deployment Created Duration is %s/%fs\n", r.DeploymentCreated)
+	line("[Verbose] Service %s:     - Service Pod Scheduled Duration is %s/%fs\n", r.PodScheduled)
+	line("[Verbose] Service %s:     - Service Pod Containers Ready Duration is %s/%fs\n", r.ContainersReady)
+	line("[Verbose] Service %s:       - Service Pod queue-proxy Started Duration is %s/%fs\n", r.QueueProxyStarted)
+	line("[Verbose] Service %s:       - Service Pod user-container Started Duration is %s/%fs\n", r.UserContainerStarted)
+	line("[Verbose] Service %s:   - Service PodAutoscaler Active Duration is %s/%fs\n", r.KpaActive)
+	line("[Verbose] Service %s:     - Service ServerlessService Ready Duration is %s/%fs\n", r.SksReady)
+	line("[Verbose] Service %s:       - Service ServerlessService ActivatorEndpointsPopulated Duration is %s/%fs\n", r.SksActivatorEndpointsPopulated)
+	line("[Verbose] Service %s:       - Service ServerlessService EndpointsPopulated Duration is %s/%fs\n", r.SksEndpointsPopulated)
+	line("[Verbose] Service %s: Service Route Ready Duration is %s/%fs\n", r.RouteReady)
+	line("[Verbose] Service %s: - Service Ingress Ready Duration is %s/%fs\n", r.IngressReady)
+	line("[Verbose] Service %s:   - Service Ingress Network Configured Duration is %s/%fs\n", r.IngressConfigReady)
+	line("[Verbose] Service %s:   - Service Ingress LoadBalancer Ready Duration is %s/%fs\n", r.IngressLoadBalancerReady)
+	line("[Verbose] Service %s: - Service Certificate Ready Duration is %s/%fs\n", r.CertificateReady)
+	line("[Verbose] Service %s: Overall Service Ready Duration is %s/%fs\n", r.OverallReady)
+}
+
+// addSums adds the durations of a ready service to the sums and the phase times, and its overall ready duration to
+// the ready times of the result
+func addSums(result *pkg.MeasureResult, r pkg.MeasureRecord) {
+	addPhaseSums(result, r)
+	addPhaseTimes(result, r)
+	result.SvcReadyTime = append(result.SvcReadyTime, r.OverallReady)
+}
+
+// addPhaseSums adds the durations of a ready service to the sums of the result
+func addPhaseSums(result *pkg.MeasureResult, r pkg.MeasureRecord) {
+	result.Sums.SvcConfigurationsReadySum += r.ConfigurationReady
+	result.Sums.RevisionReadySum += r.RevisionReady
+	result.Sums.DeploymentCreatedSum += r.DeploymentCreated
+	result.Sums.PodScheduledSum += r.PodScheduled
+	result.Sums.ContainersReadySum += r.ContainersReady
+	result.Sums.QueueProxyStartedSum += r.QueueProxyStarted
+	result.Sums.UserContrainerStartedSum += r.UserContainerStarted
+	result.Sums.SvcRoutesReadySum += r.RouteReady
+	result.Sums.KpaActiveSum += r.KpaActive
+	result.Sums.SksReadySum += r.SksReady
+	result.Sums.SksActivatorEndpointsPopulatedSum += r.SksActivatorEndpointsPopulated
+	result.Sums.SksEndpointsPopulatedSum += r.SksEndpointsPopulated
+	result.Sums.IngressReadySum += r.IngressReady
+	result.Sums.IngressNetworkConfiguredSum += r.IngressConfigReady
+	result.Sums.IngressLoadBalancerReadySum += r.IngressLoadBalancerReady
+	result.Sums.CertificateReadySum += r.CertificateReady
+	result.Sums.SvcReadySum += r.OverallReady
+}
+
+// summarize computes the averages and the percentiles of every phase and of the overall ready duration
+func summarize(result *pkg.MeasureResult) {
+	if result.Service.ReadyCount == 0 {
+		return
+	}
+	summarizeAverages(result)
+	result.Result.OverallMedian, _ = stats.Median(result.SvcReadyTime)
+	result.Result.OverallMin, _ = stats.Min(result.SvcReadyTime)
+	result.Result.OverallMax, _ = stats.Max(result.SvcReadyTime)
+	result.Result.P50, _ = stats.Percentile(result.SvcReadyTime, 50)
+	result.Result.P90, _ = stats.Percentile(result.SvcReadyTime, 90)
+	result.Result.P95, _ = stats.Percentile(result.SvcReadyTime, 95)
+	result.Result.P98, _ = stats.Percentile(result.SvcReadyTime, 98)
+	result.Result.P99, _ = stats.Percentile(result.SvcReadyTime, 99)
+	summarizePhases(result)
+}
+
+// summarizeAverages computes the averages of every phase and the total and average overall ready duration
+func summarizeAverages(result *pkg.MeasureResult) {
+	if result.Service.ReadyCount == 0 {
+		return
+	}
+	ready := float64(result.Service.ReadyCount)
+	result.Result.AverageSvcConfigurationReadySum = result.Sums.SvcConfigurationsReadySum / ready
+	result.Result.AverageRevisionReadySum = result.Sums.RevisionReadySum / ready
+	result.Result.AverageDeploymentCreatedSum = result.Sums.DeploymentCreatedSum / ready
+	result.Result.AveragePodScheduledSum = result.Sums.PodScheduledSum / ready
+	result.Result.AverageContainersReadySum = result.Sums.ContainersReadySum / ready
+	result.Result.AverageQueueProxyStartedSum = result.Sums.QueueProxyStartedSum / ready
+	result.Result.AverageUserContrainerStartedSum = result.Sums.UserContrainerStartedSum / ready
+	result.Result.AverageKpaActiveSum = result.Sums.KpaActiveSum / ready
+	result.Result.AverageSksReadySum = result.Sums.SksReadySum / ready
+	result.Result.AverageSksActivatorEndpointsPopulatedSum = result.Sums.SksActivatorEndpointsPopulatedSum / ready
+	result.Result.AverageSksEndpointsPopulatedSum = result.Sums.SksEndpointsPopulatedSum / ready
+	result.Result.AverageSvcRoutesReadySum = result.Sums.SvcRoutesReadySum / ready
+	result.Result.AverageIngressReadySum = result.Sums.IngressReadySum / ready
+	result.Result.AverageIngressNetworkConfiguredSum = result.Sums.IngressNetworkConfiguredSum / ready
+	result.Result.AverageIngressLoadBalancerReadySum = result.Sums.IngressLoadBalancerReadySum / ready
+	result.Result.AverageCertificateReadySum = result.Sums.CertificateReadySum / ready
+
+	result.Result.OverallTotal = result.Sums.SvcReadySum
+	result.Result.OverallAverage = result.Sums.SvcReadySum / ready
+}
+
+func sortRecords(records []pkg.MeasureRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		return ServiceLess(records[i].ServiceNamespace, records[i].ServiceName, records[j].ServiceNamespace, records[j].ServiceName)
+	})
+}
+
+func sortRawRecords(records []pkg.MeasureRawRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		return ServiceLess(records[i].ServiceNamespace, records[i].ServiceName, records[j].ServiceNamespace, records[j].ServiceName)
+	})
+}
+
+// ServiceLess orders the services by the number their name ends with, like ksvc-2 before ksvc-10, and the services
+// with the same number or a name without one, like the ones selected by labels, by name and namespace. The numbered
+// names come first.
+func ServiceLess(namespaceA, nameA, namespaceB, nameB string) bool {
+	indexA, errA := strconv.ParseInt(nameA[strings.LastIndex(nameA, "-")+1:], 10, 64)
+	indexB, errB := strconv.ParseInt(nameB[strings.LastIndex(nameB, "-")+1:], 10, 64)
+	if (errA == nil) != (errB == nil) {
+		return errA == nil
+	}
+	if errA == nil && indexA != indexB {
+		return indexA < indexB
+	}
+	if nameA != nameB {
+		return nameA < nameB
+	}
+	return namespaceA < namespaceB
+}
+
+// timestampMillis converts t to milliseconds since epoch, it returns nil if t is not set
+func timestampMillis(t metav1.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	millis := t.UnixNano() / int64(time.Millisecond)
+	return &millis
+}
+
+// GetPodCondition extracts the provided condition from the given status and returns that.
+// Returns nil and -1 if the condition is not present, and the index of the located condition.
+func GetPodCondition(status *corev1.PodStatus, conditionType corev1.PodConditionType) (int, *corev1.PodCondition) {
+	if status == nil {
+		return -1, nil
+	}
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return i, &status.Conditions[i]
+		}
+	}
+	return -1, nil
+}
+
+const (
+	// QueueProxyContainerName is the name of the sidecar Knative Serving adds to the Pods of every revision
+	QueueProxyContainerName = "queue-proxy"
+	// DefaultUserContainerName is the name Knative Serving gives the container of a revision without a name by default
+	DefaultUserContainerName = "user-container"
+)
+
+// UserContainerName returns the name of the container of a revision which serves the requests: the container with a
+// port in a multi-container revision, or the first one otherwise. The names are set by the defaulting of Knative
+// Serving, DefaultUserContainerName is returned for a container without a name.
+func UserContainerName(containers []corev1.Container) string {
+	serving := 0
+	for i, c := range containers {
+		if len(c.Ports) > 0 {
+			serving = i
+			break
+		}
+	}
+	if len(containers) == 0 || containers[serving].Name == "" {
+		return DefaultUserContainerName
+	}
+	return containers[serving].Name
+}
+
+// containerStarted returns when the container of a Pod started running, false if it isn't running
+func containerStarted(statuses []corev1.ContainerStatus, name string) (metav1.Time, bool) {
+	status, found := GetContainerStatus(statuses, name)
+	if !found || status.State.Running == nil {
+		return metav1.Time{}, false
+	}
+	return status.State.Running.StartedAt, true
+}
+
+// GetContainerStatus extracts the status of the container with the given name.
+// Returns nil and false if the container is not present.
+func GetContainerStatus(status []corev1.ContainerStatus, name string) (*corev1.ContainerStatus, bool) {
+	for i := range status {
+		s := &status[i]
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,765 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/kperf/pkg"
+	networkingv1alpha1api "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
+	fakenetworkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1/fake"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	autoscalingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1"
+	autoscalingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/autoscaling/v1alpha1/fake"
+	servingv1client "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1"
+	servingv1fake "knative.dev/serving/pkg/client/clientset/versioned/typed/serving/v1/fake"
+)
+
+// newMeasureTestParams returns params whose serving, autoscaling and networking clients share the returned fake
+func newMeasureTestParams(objects ...runtime.Object) (*pkg.PerfParams, *clienttesting.Fake) {
+	client := k8sfake.NewSimpleClientset(objects...)
+	fake := &clienttesting.Fake{}
+	return &pkg.PerfParams{
+		ClientSet: client,
+		NewServingClient: func() (servingv1client.ServingV1Interface, error) {
+			return &servingv1fake.FakeServingV1{Fake: fake}, nil
+		},
+		NewAutoscalingClient: func() (autoscalingv1client.AutoscalingV1alpha1Interface, error) {
+			return &autoscalingv1fake.FakeAutoscalingV1alpha1{Fake: fake}, nil
+		},
+		NewNetworkingClient: func() (networkingv1alpha1.NetworkingV1alpha1Interface, error) {
+			return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: fake}, nil
+		},
+	}, fake
+}
+
+// hangingServingClient is a serving client whose Get call of the service with the name hangs until hang is closed.
+// The reactors of the fake clients can't hang, as the fake holds its lock while they react.
+type hangingServingClient struct {
+	servingv1client.ServingV1Interface
+	name string
+	hang chan struct{}
+}
+
+func (c *hangingServingClient) Services(namespace string) servingv1client.ServiceInterface {
+	return &hangingServices{ServiceInterface: c.ServingV1Interface.Services(namespace), client: c}
+}
+
+type hangingServices struct {
+	servingv1client.ServiceInterface
+	client *hangingServingClient
+}
+
+func (s *hangingServices) Get(ctx context.Context, name string, options metav1.GetOptions) (*servingv1.Service, error) {
+	if name == s.client.name {
+		<-s.client.hang
+	}
+	return s.ServiceInterface.Get(ctx, name, options)
+}
+
+// readyConditions returns true conditions of the types which transitioned d after created
+func readyConditions(created time.Time, d time.Duration, types ...apis.ConditionType) duckv1.Conditions {
+	conditions := duckv1.Conditions{}
+	for _, t := range types {
+		conditions = append(conditions, apis.Condition{
+			Type:               t,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(created.Add(d))},
+		})
+	}
+	return conditions
+}
+
+func newReadyService(name, namespace string, created time.Time) *servingv1.Service {
+	svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         namespace,
+		CreationTimestamp: metav1.NewTime(created),
+	}}
+	svc.Status.Conditions = append(readyConditions(created, 2*time.Second, servingv1.ServiceConditionConfigurationsReady),
+		readyConditions(created, 5*time.Second, servingv1.ServiceConditionRoutesReady, servingv1.ServiceConditionReady)...)
+	return svc
+}
+
+// prependReadyReactors makes the fake return the resources of the revision ksvc-1-00001, which got ready 5 seconds
+// after created
+func prependReadyReactors(fake *clienttesting.Fake, created time.Time) {
+	fake.PrependReactor("get", "configurations", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		cfg := &servingv1.Configuration{}
+		cfg.Status.LatestReadyRevisionName = "ksvc-1-00001"
+		return true, cfg, nil
+	})
+	fake.PrependReactor("get", "revisions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		rev := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+		rev.Status.Conditions = readyConditions(created, 3*time.Second, servingv1.RevisionConditionReady)
+		return true, rev, nil
+	})
+	fake.PrependReactor("get", "podautoscalers", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		kpa := &autoscalingv1alpha1.PodAutoscaler{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+		kpa.Status.Conditions = readyConditions(created, 3*time.Second, autoscalingv1alpha1.PodAutoscalerConditionActive)
+		return true, kpa, nil
+	})
+	fake.PrependReactor("get", "serverlessservices", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sks := &networkingv1alpha1api.ServerlessService{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+		sks.Status.Conditions = readyConditions(created, 3*time.Second, networkingv1alpha1api.ActivatorEndpointsPopulated,
+			networkingv1alpha1api.ServerlessServiceConditionEndspointsPopulated, networkingv1alpha1api.ServerlessServiceConditionReady)
+		return true, sks, nil
+	})
+	fake.PrependReactor("get", "ingresses", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		ingress := &networkingv1alpha1api.Ingress{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created.Add(3 * time.Second))}}
+		ingress.Status.Conditions = readyConditions(created, 4*time.Second, networkingv1alpha1api.IngressConditionNetworkConfigured,
+			networkingv1alpha1api.IngressConditionLoadBalancerReady)
+		return true, ingress, nil
+	})
+}
+
+func TestMeasure(t *testing.T) {
+	t.Run("no service to measure", func(t *testing.T) {
+		p, _ := newMeasureTestParams()
+		_, err := NewMeasurer(p, nil, nil).Measure(context.Background(), nil)
+		assert.ErrorContains(t, err, "no service found to measure")
+	})
+
+	t.Run("measure ready and not ready services", func(t *testing.T) {
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment",
+			Namespace:         "ns-1",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+		}}
+		p, fake := newMeasureTestParams(deployment)
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			name := action.(clienttesting.GetAction).GetName()
+			if name == "ksvc-1" {
+				return true, newReadyService(name, "ns-1", created), nil
+			}
+			return true, &servingv1.Service{}, nil
+		})
+		prependReadyReactors(fake, created)
+
+		out := &bytes.Buffer{}
+		measurer := NewMeasurer(p, out, nil)
+		measurer.Verbose = true
+		measurer.DebugTimestamps = true
+		stream := &bytes.Buffer{}
+		measurer.Stream = stream
+		measurer.ExtraMetrics = []ExtraMetrics{func(ctx context.Context, start, end time.Time) (map[string]interface{}, error) {
+			return map[string]interface{}{"db_latency": 0.25}, nil
+		}}
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{
+			{Namespace: "ns-1", Name: "ksvc-2"},
+			{Namespace: "ns-1", Name: "ksvc-1"},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+		assert.Equal(t, 1, result.Summary.Service.NotReadyCount)
+		assert.Equal(t, 1, len(result.Records))
+		assert.Equal(t, "ksvc-1", result.Records[0].ServiceName)
+		assert.Equal(t, 2.0, result.Records[0].ConfigurationReady)
+		assert.Equal(t, 1.0, result.Records[0].DeploymentCreated)
+		assert.Equal(t, 3.0, result.Records[0].RevisionReady)
+		assert.Equal(t, 1.0, result.Records[0].IngressReady)
+		// without auto-TLS there is no Certificate
+		assert.Equal(t, 0.0, result.Records[0].CertificateReady)
+		assert.Assert(t, result.RawRecords[0].CertificateReady == nil)
+		assert.Equal(t, 5.0, result.Records[0].OverallReady)
+		assert.Equal(t, created.UnixNano()/int64(time.Millisecond), *result.RawRecords[0].ServiceCreated)
+		assert.Equal(t, 5.0, result.Summary.Result.OverallAverage)
+		assert.Equal(t, 5.0, result.Summary.Result.P95)
+		assert.Equal(t, "Unknown", result.Summary.KnativeInfo.ServingVersion)
+		assert.DeepEqual(t, map[string]interface{}{"db_latency": 0.25}, result.Summary.Custom)
+		assert.Assert(t, strings.Contains(out.String(), "[Verbose] Service ksvc-1: Overall Service Ready Duration is 5s/5.000000s"))
+		// only the ready service is streamed
+		var streamed pkg.MeasureRecord
+		assert.NilError(t, json.Unmarshal(stream.Bytes(), &streamed))
+		assert.DeepEqual(t, result.Records[0], streamed)
+		services := 0
+		for _, w := range result.Workers {
+			services += w.Services
+		}
+		assert.Equal(t, 2, services)
+		// the timestamps of every resource read for ksvc-1 are collected, including the ones not used in a phase
+		fields := map[string]bool{}
+		for _, ts := range result.DebugTimestamps {
+			assert.Equal(t, "ksvc-1", ts.ServiceName)
+			fields[ts.Kind+"/"+ts.Field] = true
+		}
+		assert.Assert(t, fields["Service/Ready"])
+		assert.Assert(t, fields["Deployment/created"])
+		assert.Assert(t, fields["Ingress/LoadBalancerReady"])
+
+		summary := &bytes.Buffer{}
+		result.WriteSummary(summary, SummaryOptions{})
+		assert.Assert(t, strings.HasPrefix(summary.String(), "-------- Measurement --------\n"))
+		assert.Assert(t, strings.Contains(summary.String(), "Total: 2 | Ready: 1 NotReady: 1 NotFound: 0 Fail: 0\n"))
+		assert.Assert(t, strings.Contains(summary.String(), "Percentile95  5.000000s\n"))
+
+		// the service which is not ready is kept with the reason why it isn't measured
+		assert.DeepEqual(t, []pkg.ServiceFailure{{
+			ServiceName:      "ksvc-2",
+			ServiceNamespace: "ns-1",
+			State:            StateNotReady,
+			Reason:           pkg.FailureNotReady,
+			Message:          "service ns-1/ksvc-2 not ready (no Ready condition) and skip measuring",
+		}}, result.Summary.Failures)
+		assert.Assert(t, strings.Contains(summary.String(), "FAILURE REASON  COUNT\nNotReady            1\n"), summary.String())
+	})
+
+	t.Run("measure multi-container services", func(t *testing.T) {
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment",
+			Namespace:         "ns-1",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+		}}
+		running := func(name string, d time.Duration) corev1.ContainerStatus {
+			return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(created.Add(d))},
+			}}
+		}
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment-abc",
+			Namespace:         "ns-1",
+			Labels:            map[string]string{"serving.knative.dev/revision": "ksvc-1-00001"},
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+		pod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(time.Second))},
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(4 * time.Second))},
+		}
+		pod.Spec.NodeName = "node-1"
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{running("queue-proxy", 2*time.Second), running("app", 3*time.Second), running("sidecar", time.Second)}
+		p, fake := newMeasureTestParams(deployment, pod)
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newReadyService("ksvc-1", "ns-1", created), nil
+		})
+		prependReadyReactors(fake, created)
+		fake.PrependReactor("get", "revisions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			rev := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+			rev.Spec.Containers = []corev1.Container{
+				{Name: "sidecar"},
+				{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}},
+			}
+			rev.Status.Conditions = readyConditions(created, 3*time.Second, servingv1.RevisionConditionReady)
+			return true, rev, nil
+		})
+		services := []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}}
+
+		// the container with the port is the user container
+		result, err := NewMeasurer(p, nil, nil).Measure(context.Background(), services)
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+		assert.Equal(t, 2.0, result.Records[0].QueueProxyStarted)
+		assert.Equal(t, 3.0, result.Records[0].UserContainerStarted)
+		assert.Equal(t, "node-1", result.Records[0].NodeName)
+		assert.DeepEqual(t, []pkg.ContainerStarted{
+			{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "app", Serving: true, Started: 3},
+			{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "sidecar", Started: 1},
+		}, result.Summary.Containers)
+		summary := &bytes.Buffer{}
+		result.WriteSummary(summary, SummaryOptions{})
+		assert.Assert(t, strings.Contains(summary.String(), "app                       1  3.000000s  3.000000s\n"), summary.String())
+
+		// the name overrides the detection
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.UserContainerName = "sidecar"
+		result, err = measurer.Measure(context.Background(), services)
+		assert.NilError(t, err)
+		assert.Equal(t, 1.0, result.Records[0].UserContainerStarted)
+		measurer.UserContainerName = "missing"
+		result, err = measurer.Measure(context.Background(), services)
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.NotReadyCount)
+		assert.Equal(t, pkg.FailureMissingContainerStatus, result.Summary.Failures[0].Reason)
+		assert.Equal(t, "failed to get missing container status and skip", result.Summary.Failures[0].Message)
+	})
+
+	t.Run("break down the image pulls", func(t *testing.T) {
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment",
+			Namespace:         "ns-1",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+		}}
+		running := func(name string, d time.Duration) corev1.ContainerStatus {
+			return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(created.Add(d))},
+			}}
+		}
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment-abc",
+			Namespace:         "ns-1",
+			Labels:            map[string]string{"serving.knative.dev/revision": "ksvc-1-00001"},
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+		pod.Spec.Containers = []corev1.Container{{Name: "user-container", Image: "ko.local/helloworld"}, {Name: "queue-proxy", Image: "gcr.io/knative-releases/queue"}}
+		pod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(time.Second))},
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(5 * time.Second))},
+		}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{running("queue-proxy", 2*time.Second), running("user-container", 4*time.Second)}
+		event := func(name, pod, container, reason, message string, d time.Duration) *corev1.Event {
+			return &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns-1"},
+				InvolvedObject: corev1.ObjectReference{
+					Kind:      "Pod",
+					Name:      pod,
+					FieldPath: "spec.containers{" + container + "}",
+				},
+				Reason:         reason,
+				Message:        message,
+				FirstTimestamp: metav1.NewTime(created.Add(d)),
+			}
+		}
+		p, fake := newMeasureTestParams(deployment, pod,
+			event("pulling", pod.Name, "user-container", "Pulling", `Pulling image "ko.local/helloworld"`, time.Second),
+			event("pulled", pod.Name, "user-container", "Pulled", `Successfully pulled image "ko.local/helloworld" in 1.5s (1.5s including waiting)`, 3*time.Second),
+			event("present", pod.Name, "queue-proxy", "Pulled", `Container image "gcr.io/knative-releases/queue" already present on machine`, time.Second),
+			event("other", "other-pod", "user-container", "Pulling", `Pulling image "ko.local/helloworld"`, 0),
+		)
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newReadyService("ksvc-1", "ns-1", created), nil
+		})
+		prependReadyReactors(fake, created)
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.ImagePulls = true
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 5.0, result.Records[0].ContainersReady)
+		assert.DeepEqual(t, []pkg.ImagePull{
+			{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "queue-proxy", Image: "gcr.io/knative-releases/queue",
+				Cached: true, Pulling: 1, Pulled: 1, Started: 1},
+			{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Container: "user-container", Image: "ko.local/helloworld",
+				Pulling: 1, Pulled: 3, Pull: 1.5, Started: 1},
+		}, result.Summary.ImagePulls)
+		summary := &bytes.Buffer{}
+		result.WriteSummary(summary, SummaryOptions{})
+		assert.Assert(t, strings.Contains(summary.String(), "user-container         1       0  1.500000s  1.500000s      1.000000s\n"), summary.String())
+
+		// without the breakdown the Events are not listed
+		result, err = NewMeasurer(p, nil, nil).Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 0, len(result.Summary.ImagePulls))
+	})
+
+	t.Run("measure the certificates of auto-TLS services", func(t *testing.T) {
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment",
+			Namespace:         "ns-1",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+		}}
+		p, fake := newMeasureTestParams(deployment)
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newReadyService("ksvc-1", "ns-1", created), nil
+		})
+		prependReadyReactors(fake, created)
+		fake.PrependReactor("list", "certificates", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			assert.Equal(t, "serving.knative.dev/route=ksvc-1", action.(clienttesting.ListAction).GetListRestrictions().Labels.String())
+			certificate := func(name string, created time.Time, ready time.Duration) networkingv1alpha1api.Certificate {
+				c := networkingv1alpha1api.Certificate{ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "ns-1",
+					Labels:            map[string]string{"serving.knative.dev/route": "ksvc-1"},
+					CreationTimestamp: metav1.NewTime(created),
+				}}
+				c.Status.Conditions = readyConditions(created, ready, networkingv1alpha1api.CertificateConditionReady)
+				return c
+			}
+			return true, &networkingv1alpha1api.CertificateList{Items: []networkingv1alpha1api.Certificate{
+				certificate("route-1", created.Add(3*time.Second), time.Second),
+				certificate("route-1-local", created.Add(2*time.Second), time.Second),
+			}}, nil
+		})
+
+		out := &bytes.Buffer{}
+		measurer := NewMeasurer(p, out, nil)
+		measurer.Verbose = true
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+		// from the first Certificate created to the last one ready
+		assert.Equal(t, 2.0, result.Records[0].CertificateReady)
+		assert.Equal(t, created.Add(2*time.Second).UnixNano()/int64(time.Millisecond), *result.RawRecords[0].CertificateCreated)
+		assert.Equal(t, created.Add(4*time.Second).UnixNano()/int64(time.Millisecond), *result.RawRecords[0].CertificateReady)
+		assert.Equal(t, 2.0, result.Summary.Result.AverageCertificateReadySum)
+		assert.Assert(t, strings.Contains(out.String(), "[Verbose] Service ksvc-1: - Service Certificate Ready Duration is 2s/2.000000s"))
+	})
+
+	t.Run("only compute the summary", func(t *testing.T) {
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment",
+			Namespace:         "ns-1",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+		}}
+		p, fake := newMeasureTestParams(deployment)
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newReadyService(action.(clienttesting.GetAction).GetName(), "ns-1", created), nil
+		})
+		prependReadyReactors(fake, created)
+		services := []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}, {Namespace: "ns-1", Name: "ksvc-2"}}
+
+		full, err := NewMeasurer(p, nil, nil).Measure(context.Background(), services)
+		assert.NilError(t, err)
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.SummaryOnly = true
+		stream := &bytes.Buffer{}
+		measurer.Stream = stream
+		result, err := measurer.Measure(context.Background(), services)
+		assert.NilError(t, err)
+		assert.Equal(t, 2, result.Summary.Service.ReadyCount)
+		assert.DeepEqual(t, full.Summary.Sums, result.Summary.Sums)
+		assert.DeepEqual(t, full.Summary.Result, result.Summary.Result)
+		// neither the records nor the states of the services are kept
+		assert.Equal(t, 0, len(result.Records))
+		assert.Equal(t, 0, len(result.RawRecords))
+		assert.Equal(t, 0, len(result.Summary.SvcReadyTime))
+		assert.Equal(t, 0, len(result.States))
+		assert.Equal(t, 0, stream.Len())
+	})
+
+	t.Run("retry throttled calls", func(t *testing.T) {
+		p, fake := newMeasureTestParams()
+		calls := 0
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			calls++
+			if calls == 1 {
+				return true, nil, apierrors.NewTooManyRequests("slow down", 1)
+			}
+			return true, nil, apierrors.NewNotFound(servingv1.Resource("services"), "ksvc-1")
+		})
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.RetryBackoff = time.Millisecond
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		// the throttled call doesn't count as a failure
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 0, result.Summary.Service.FailCount)
+		assert.Equal(t, 1, result.Summary.Service.NotFoundCount)
+		assert.Equal(t, pkg.FailureNotFound, result.Summary.Failures[0].Reason)
+	})
+
+	t.Run("interrupted measurement", func(t *testing.T) {
+		p, fake := newMeasureTestParams()
+		ctx, interrupt := context.WithCancel(context.Background())
+		defer interrupt()
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			name := action.(clienttesting.GetAction).GetName()
+			if name == "ksvc-2" {
+				interrupt()
+				return true, nil, context.Canceled
+			}
+			return true, nil, apierrors.NewNotFound(servingv1.Resource("services"), name)
+		})
+
+		checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Concurrency = 1
+		measurer.CheckpointFile = checkpoint
+		result, err := measurer.Measure(ctx, []types.NamespacedName{
+			{Namespace: "ns-1", Name: "ksvc-1"},
+			{Namespace: "ns-1", Name: "ksvc-2"},
+			{Namespace: "ns-1", Name: "ksvc-3"},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.Summary.Service.NotFoundCount)
+		assert.Equal(t, 0, result.Summary.Service.FailCount)
+		assert.Equal(t, 2, result.Summary.Interrupted)
+		// the Knative versions are still read after the interruption
+		assert.Equal(t, "Unknown", result.Summary.KnativeInfo.ServingVersion)
+
+		// the interrupted services are measured on resume
+		previous, err := ReadCheckpoint(checkpoint)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]string{"ns-1/ksvc-1": statusNames[statusNotFound]}, previous.Processed)
+	})
+
+	t.Run("time out hanging services", func(t *testing.T) {
+		created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment",
+			Namespace:         "ns-1",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+		}}
+		p, fake := newMeasureTestParams(deployment)
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, newReadyService(action.(clienttesting.GetAction).GetName(), "ns-1", created), nil
+		})
+		prependReadyReactors(fake, created)
+		// the Get call of ksvc-2 hangs until the test is done, it doesn't return on the cancellation
+		hang := make(chan struct{})
+		defer close(hang)
+		p.NewServingClient = func() (servingv1client.ServingV1Interface, error) {
+			return &hangingServingClient{ServingV1Interface: &servingv1fake.FakeServingV1{Fake: fake}, name: "ksvc-2", hang: hang}, nil
+		}
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Concurrency = 1
+		measurer.ServiceTimeout = 50 * time.Millisecond
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{
+			{Namespace: "ns-1", Name: "ksvc-1"},
+			{Namespace: "ns-1", Name: "ksvc-2"},
+			{Namespace: "ns-1", Name: "ksvc-3"},
+		})
+		assert.NilError(t, err)
+		// the worker moves on to ksvc-3 after ksvc-2 timed out
+		assert.Equal(t, 2, result.Summary.Service.ReadyCount)
+		assert.Equal(t, 1, result.Summary.Service.TimedOutCount)
+		assert.Equal(t, 0, result.Summary.Service.NotReadyCount)
+		assert.Equal(t, StateTimedOut, result.States["ns-1/ksvc-2"])
+		assert.Equal(t, 1, len(result.Summary.Failures))
+		assert.Equal(t, pkg.FailureTimeout, result.Summary.Failures[0].Reason)
+		assert.Equal(t, StateTimedOut, result.Summary.Failures[0].State)
+		assert.Equal(t, 2, len(result.Records))
+	})
+
+	t.Run("time the workers with the clock", func(t *testing.T) {
+		p, fake := newMeasureTestParams()
+		fakeClock := clock.NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+		p.Clock = fakeClock
+		// every API call takes a second
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			fakeClock.Step(time.Second)
+			return true, &servingv1.Service{}, nil
+		})
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Concurrency = 1
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{
+			{Namespace: "ns-1", Name: "ksvc-1"},
+			{Namespace: "ns-1", Name: "ksvc-2"},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []pkg.WorkerMeasureResult{
+			{Worker: 0, Services: 2, Average: 1, APITime: 2, ComputeTime: 0, Utilization: 100},
+		}, result.Workers)
+	})
+
+	t.Run("collect labels", func(t *testing.T) {
+		p, fake := newMeasureTestParams()
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &servingv1.Service{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"team": "a", "app": action.(clienttesting.GetAction).GetName()},
+			}}, nil
+		})
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Labels = []string{"team", "tier"}
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]map[string]string{"ns-1/ksvc-1": {"team": "a"}}, result.Labels)
+	})
+
+	t.Run("resume from checkpoint", func(t *testing.T) {
+		p, fake := newMeasureTestParams()
+		var measured []string
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			measured = append(measured, action.(clienttesting.GetAction).GetName())
+			return true, &servingv1.Service{}, nil
+		})
+
+		file := filepath.Join(t.TempDir(), "checkpoint.json")
+		err := WriteCheckpoint(file, &Checkpoint{
+			Processed: map[string]string{"ns-1/ksvc-1": "ready", "ns-1/ksvc-2": "not_found"},
+			Records:   []pkg.MeasureRecord{{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", OverallReady: 5}},
+		})
+		assert.NilError(t, err)
+
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Concurrency = 1
+		measurer.CheckpointFile = file
+		measurer.Resume = true
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{
+			{Namespace: "ns-1", Name: "ksvc-1"},
+			{Namespace: "ns-1", Name: "ksvc-2"},
+			{Namespace: "ns-1", Name: "ksvc-3"},
+		})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"ksvc-3"}, measured)
+		assert.Equal(t, 1, result.Summary.Service.ReadyCount)
+		assert.Equal(t, 1, result.Summary.Service.NotFoundCount)
+		assert.Equal(t, 1, result.Summary.Service.NotReadyCount)
+		assert.Equal(t, 5.0, result.Summary.Result.OverallAverage)
+		assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 1, NotReadyCount: 1, NotFoundCount: 1}, result.NamespaceCounts["ns-1"])
+		assert.DeepEqual(t, map[string]string{"ns-1/ksvc-1": StateReady, "ns-1/ksvc-2": StateNotFound, "ns-1/ksvc-3": StateNotReady}, result.States)
+
+		checkpoint, err := ReadCheckpoint(file)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]string{"ns-1/ksvc-1": "ready", "ns-1/ksvc-2": "not_found", "ns-1/ksvc-3": "not_ready"}, checkpoint.Processed)
+		assert.Equal(t, 1, len(checkpoint.Records))
+	})
+}
+
+func TestListServices(t *testing.T) {
+	p, fake := newMeasureTestParams()
+	fake.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() != "ns-1" {
+			return true, &servingv1.ServiceList{}, nil
+		}
+		return true, &servingv1.ServiceList{Items: []servingv1.Service{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other-1", Namespace: "ns-1"}},
+		}}, nil
+	})
+
+	services, err := NewMeasurer(p, nil, nil).ListServices(context.Background(), []string{"ns-1", "ns-2"}, "ksvc")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}}, services)
+}
+
+func TestSelectServices(t *testing.T) {
+	p, fake := newMeasureTestParams()
+	var namespaces, selectors []string
+	fake.PrependReactor("list", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		namespaces = append(namespaces, action.GetNamespace())
+		selectors = append(selectors, action.(clienttesting.ListAction).GetListRestrictions().Labels.String())
+		return true, &servingv1.ServiceList{Items: []servingv1.Service{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1", Namespace: "ns-1", Labels: map[string]string{"app": "demo"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "ksvc-2", Namespace: "ns-2", Labels: map[string]string{"app": "other"}}},
+		}}, nil
+	})
+	m := NewMeasurer(p, nil, nil)
+
+	services, err := m.SelectServices(context.Background(), nil, "app=demo")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}}, services)
+	assert.DeepEqual(t, []string{metav1.NamespaceAll}, namespaces)
+	assert.DeepEqual(t, []string{"app=demo"}, selectors)
+
+	namespaces = nil
+	_, err = m.SelectServices(context.Background(), []string{"ns-1", "ns-2"}, "app=demo")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"ns-1", "ns-2"}, namespaces)
+}
+
+func TestWriteSummaryWithoutReadyService(t *testing.T) {
+	result := &Result{}
+	result.Summary.Service.NotFoundCount = 2
+	out := &bytes.Buffer{}
+	result.WriteSummary(out, SummaryOptions{})
+	assert.Assert(t, strings.HasSuffix(out.String(), "Service Ready Measurement:\nTotal: 2 | Ready: 0 NotReady: 0 NotFound: 2 Fail: 0\n"))
+}
+
+func TestSortRecords(t *testing.T) {
+	records := []pkg.MeasureRecord{
+		{ServiceName: "web", ServiceNamespace: "ns-1"},
+		{ServiceName: "ksvc-10", ServiceNamespace: "ns-1"},
+		{ServiceName: "hello", ServiceNamespace: "ns-2"},
+		{ServiceName: "ksvc-2", ServiceNamespace: "ns-1"},
+		{ServiceName: "hello", ServiceNamespace: "ns-1"},
+		{ServiceName: "api-orders-3", ServiceNamespace: "ns-1"},
+	}
+	sortRecords(records)
+	names := []string{}
+	for _, r := range records {
+		names = append(names, r.ServiceNamespace+"/"+r.ServiceName)
+	}
+	assert.DeepEqual(t, []string{"ns-1/ksvc-2", "ns-1/api-orders-3", "ns-1/ksvc-10", "ns-1/hello", "ns-2/hello", "ns-1/web"}, names)
+}
+
+func TestTimestampMillis(t *testing.T) {
+	assert.Check(t, timestampMillis(metav1.Time{}) == nil)
+
+	millis := timestampMillis(metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 1, 500000000, time.UTC)))
+	assert.Equal(t, int64(1640995201500), *millis)
+}
+func TestGetPodCondition(t *testing.T) {
+	t.Run("get pod condition when pod is scheduled", func(t *testing.T) {
+		podCondition := &corev1.PodCondition{
+			Type: corev1.PodScheduled,
+		}
+		podStatus := &corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				*podCondition,
+			},
+		}
+		i, condition := GetPodCondition(podStatus, corev1.PodScheduled)
+		assert.Equal(t, 0, i)
+		assert.Equal(t, corev1.PodScheduled, condition.Type)
+	})
+
+	t.Run("get pod condition when pod isn't scheduled", func(t *testing.T) {
+		podCondition := &corev1.PodCondition{
+			Type: corev1.PodInitialized,
+		}
+		podStatus := &corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				*podCondition,
+			},
+		}
+		i, condition := GetPodCondition(podStatus, corev1.PodScheduled)
+		assert.Equal(t, -1, i)
+		assert.Equal(t, (*corev1.PodCondition)(nil), condition)
+	})
+
+	t.Run("get pod condition when pod status is nil", func(t *testing.T) {
+		podCondition := (*corev1.PodCondition)(nil)
+		i, condition := GetPodCondition(nil, corev1.PodScheduled)
+		assert.Equal(t, -1, i)
+		assert.Equal(t, podCondition, condition)
+	})
+}
+
+func TestUserContainerName(t *testing.T) {
+	assert.Equal(t, DefaultUserContainerName, UserContainerName(nil))
+	assert.Equal(t, DefaultUserContainerName, UserContainerName([]corev1.Container{{}}))
+	assert.Equal(t, "app", UserContainerName([]corev1.Container{{Name: "app"}}))
+	assert.Equal(t, "app", UserContainerName([]corev1.Container{{Name: "app"}, {Name: "sidecar"}}))
+	assert.Equal(t, "server", UserContainerName([]corev1.Container{{Name: "sidecar"}, {Name: "server", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}}}))
+}
+
+func TestGetContainerStatus(t *testing.T) {
+	t.Run("get container status seccussfully", func(t *testing.T) {
+		var containerStatus []corev1.ContainerStatus
+		container := corev1.ContainerStatus{
+			Name: "user-container",
+		}
+		containerStatus = append(containerStatus, container)
+		s, status := GetContainerStatus(containerStatus, "user-container")
+		assert.Equal(t, container.Name, s.Name)
+		assert.Equal(t, true, status)
+	})
+
+	t.Run("get container status when the condition is not present", func(t *testing.T) {
+		var containerStatus []corev1.ContainerStatus
+		s, status := GetContainerStatus(containerStatus, "queue-proxy")
+		assert.Equal(t, (*corev1.ContainerStatus)(nil), s)
+		assert.Equal(t, false, status)
+	})
+}
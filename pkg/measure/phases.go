@@ -0,0 +1,114 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"github.com/montanaflynn/stats"
+
+	"knative.dev/kperf/pkg"
+)
+
+// PhaseOverallReady is the phase from the creation of a service until it is ready, its statistics are the overall
+// ones of the result instead of the ones in Phases
+const PhaseOverallReady = "overall_ready"
+
+// RecordPhases returns the durations of the phases of a measured service named like the columns of the measurement
+// CSV file
+func RecordPhases(r pkg.MeasureRecord) map[string]float64 {
+	return map[string]float64{
+		"configuration_ready":               r.ConfigurationReady,
+		"revision_ready":                    r.RevisionReady,
+		"deployment_created":                r.DeploymentCreated,
+		"pod_scheduled":                     r.PodScheduled,
+		"containers_ready":                  r.ContainersReady,
+		"queue-proxy_started":               r.QueueProxyStarted,
+		"user-container_started":            r.UserContainerStarted,
+		"route_ready":                       r.RouteReady,
+		"kpa_active":                        r.KpaActive,
+		"sks_ready":                         r.SksReady,
+		"sks_activator_endpoints_populated": r.SksActivatorEndpointsPopulated,
+		"sks_endpoints_populated":           r.SksEndpointsPopulated,
+		"ingress_ready":                     r.IngressReady,
+		"ingress_config_ready":              r.IngressConfigReady,
+		"ingress_lb_ready":                  r.IngressLoadBalancerReady,
+		"certificate_ready":                 r.CertificateReady,
+		PhaseOverallReady:                   r.OverallReady,
+	}
+}
+
+// addPhaseTimes adds the durations of the phases of a ready service to the phase times of the result
+func addPhaseTimes(result *pkg.MeasureResult, r pkg.MeasureRecord) {
+	if result.PhaseTimes == nil {
+		result.PhaseTimes = map[string][]float64{}
+	}
+	for phase, duration := range RecordPhases(r) {
+		if phase != PhaseOverallReady {
+			result.PhaseTimes[phase] = append(result.PhaseTimes[phase], duration)
+		}
+	}
+}
+
+// summarizePhases computes the statistics of every phase from the phase times of the result
+func summarizePhases(result *pkg.MeasureResult) {
+	if len(result.PhaseTimes) == 0 {
+		return
+	}
+	result.Result.Phases = make(map[string]pkg.PhaseStatistics, len(result.PhaseTimes))
+	for phase, durations := range result.PhaseTimes {
+		var s pkg.PhaseStatistics
+		s.Min, _ = stats.Min(durations)
+		s.Max, _ = stats.Max(durations)
+		s.Median, _ = stats.Median(durations)
+		s.P90, _ = stats.Percentile(durations, 90)
+		s.P95, _ = stats.Percentile(durations, 95)
+		s.P99, _ = stats.Percentile(durations, 99)
+		result.Result.Phases[phase] = s
+	}
+}
+
+// phaseDistributions counts the durations of every phase but the overall ready one, like readyDistribution counts
+// the overall ready durations
+type phaseDistributions map[string]*readyDistribution
+
+// add counts the durations of the phases of a ready service
+func (d phaseDistributions) add(r pkg.MeasureRecord) {
+	for phase, duration := range RecordPhases(r) {
+		if phase == PhaseOverallReady {
+			continue
+		}
+		if d[phase] == nil {
+			d[phase] = newReadyDistribution()
+		}
+		d[phase].add(duration)
+	}
+}
+
+// summarize sets the statistics of every phase of the result
+func (d phaseDistributions) summarize(result *pkg.Result) {
+	if len(d) == 0 {
+		return
+	}
+	result.Phases = make(map[string]pkg.PhaseStatistics, len(d))
+	for phase, distribution := range d {
+		result.Phases[phase] = pkg.PhaseStatistics{
+			Min:    distribution.nth(0),
+			Max:    distribution.nth(distribution.total - 1),
+			Median: distribution.median(),
+			P90:    distribution.percentile(90),
+			P95:    distribution.percentile(95),
+			P99:    distribution.percentile(99),
+		}
+	}
+}
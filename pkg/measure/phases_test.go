@@ -0,0 +1,74 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"math/rand"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestRecordPhases(t *testing.T) {
+	phases := RecordPhases(pkg.MeasureRecord{RevisionReady: 3, IngressLoadBalancerReady: 2, OverallReady: 5})
+	assert.Equal(t, 17, len(phases))
+	assert.Equal(t, 3.0, phases["revision_ready"])
+	assert.Equal(t, 2.0, phases["ingress_lb_ready"])
+	assert.Equal(t, 5.0, phases[PhaseOverallReady])
+}
+
+func TestSummarizePhases(t *testing.T) {
+	result := pkg.MeasureResult{}
+	for _, d := range []float64{4, 1, 3, 2, 10} {
+		result.Service.ReadyCount++
+		addSums(&result, pkg.MeasureRecord{PodScheduled: d, RevisionReady: 2 * d, OverallReady: 3 * d})
+	}
+	summarize(&result)
+	assert.DeepEqual(t, pkg.PhaseStatistics{Min: 1, Max: 10, Median: 3, P90: 7, P95: 7, P99: 7}, result.Result.Phases["pod_scheduled"])
+	assert.DeepEqual(t, pkg.PhaseStatistics{Min: 2, Max: 20, Median: 6, P90: 14, P95: 14, P99: 14}, result.Result.Phases["revision_ready"])
+	// the overall ready duration has its own statistics
+	_, ok := result.Result.Phases[PhaseOverallReady]
+	assert.Assert(t, !ok)
+	assert.Equal(t, 9.0, result.Result.OverallMedian)
+
+	// nothing is set without ready services
+	empty := pkg.MeasureResult{}
+	summarize(&empty)
+	assert.Assert(t, empty.Result.Phases == nil)
+}
+
+func TestPhaseDistributions(t *testing.T) {
+	// the statistics are the same as the ones of every duration
+	random := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 10, 101} {
+		d := phaseDistributions{}
+		expected := pkg.MeasureResult{}
+		for i := 0; i < n; i++ {
+			record := pkg.MeasureRecord{ConfigurationReady: float64(random.Intn(60)), IngressReady: float64(random.Intn(10))}
+			d.add(record)
+			addPhaseTimes(&expected, record)
+		}
+		summarizePhases(&expected)
+		var result pkg.Result
+		d.summarize(&result)
+		assert.DeepEqual(t, expected.Result.Phases, result.Phases)
+	}
+
+	var result pkg.Result
+	phaseDistributions{}.summarize(&result)
+	assert.Assert(t, result.Phases == nil)
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PrecisionSeconds truncates the timestamps to whole seconds like they are serialized by the API server
+	PrecisionSeconds = "s"
+	// PrecisionMillis keeps the milliseconds of the timestamps, e.g. of clusters or watch caches which report them
+	PrecisionMillis = "ms"
+)
+
+// Precisions are the supported precisions of the durations
+var Precisions = []string{PrecisionSeconds, PrecisionMillis}
+
+// ValidatePrecision returns an error if the precision of the durations is not supported
+func ValidatePrecision(precision string) error {
+	for _, p := range Precisions {
+		if p == precision {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported precision %q, expected one of %s", precision, strings.Join(Precisions, ","))
+}
+
+// PrecisionUnit returns the unit the timestamps are truncated to with the precision, one of Precisions
+func PrecisionUnit(precision string) time.Duration {
+	if precision == PrecisionMillis {
+		return time.Millisecond
+	}
+	return time.Second
+}
+
+// FormatSeconds formats a duration in seconds with the precision, whole seconds or seconds with three decimals
+func FormatSeconds(s float64, precision string) string {
+	if precision == PrecisionMillis {
+		return fmt.Sprintf("%.3f", s)
+	}
+	return fmt.Sprintf("%d", int(s))
+}
+
+// truncate returns the timestamp truncated to the precision of the Measurer
+func (m *Measurer) truncate(t metav1.Time) metav1.Time {
+	precision := m.Precision
+	if precision <= 0 {
+		precision = time.Second
+	}
+	return metav1.NewTime(t.Truncate(precision))
+}
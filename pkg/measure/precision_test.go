@@ -0,0 +1,81 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestValidatePrecision(t *testing.T) {
+	assert.NilError(t, ValidatePrecision(PrecisionSeconds))
+	assert.NilError(t, ValidatePrecision(PrecisionMillis))
+	assert.ErrorContains(t, ValidatePrecision("us"), `unsupported precision "us", expected one of s,ms`)
+}
+
+func TestFormatSeconds(t *testing.T) {
+	assert.Equal(t, "6", FormatSeconds(6.2, PrecisionSeconds))
+	assert.Equal(t, "6", FormatSeconds(6.2, ""))
+	assert.Equal(t, "6.200", FormatSeconds(6.2, PrecisionMillis))
+	assert.Equal(t, "0.045", FormatSeconds(0.045, PrecisionMillis))
+}
+
+func TestMeasurePrecision(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	measureWith := func(t *testing.T, precision time.Duration) *Result {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:              "ksvc-1-00001-deployment",
+			Namespace:         "ns-1",
+			CreationTimestamp: metav1.NewTime(created.Add(time.Second)),
+		}}
+		p, fake := newMeasureTestParams(deployment)
+		fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			svc := newReadyService("ksvc-1", "ns-1", created)
+			// the service was created 1.2s before its resources, it is ready 6.2s after it was created
+			svc.CreationTimestamp = metav1.NewTime(created.Add(-1200 * time.Millisecond))
+			return true, svc, nil
+		})
+		prependReadyReactors(fake, created)
+		measurer := NewMeasurer(p, nil, nil)
+		measurer.Precision = precision
+		result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(result.Records))
+		return result
+	}
+
+	t.Run("whole seconds by default", func(t *testing.T) {
+		result := measureWith(t, 0)
+		assert.Equal(t, 7.0, result.Records[0].OverallReady)
+		assert.Equal(t, 4.0, result.Records[0].ConfigurationReady)
+		assert.Equal(t, 7.0, result.Summary.Result.P95)
+	})
+
+	t.Run("milliseconds", func(t *testing.T) {
+		result := measureWith(t, time.Millisecond)
+		assert.Equal(t, 6.2, result.Records[0].OverallReady)
+		assert.Equal(t, 3.2, result.Records[0].ConfigurationReady)
+		assert.Equal(t, 6.2, result.Summary.Result.OverallAverage)
+		assert.Equal(t, 6.2, result.Summary.Result.P95)
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/montanaflynn/stats"
+
+	"knative.dev/kperf/pkg"
+)
+
+const (
+	// RacePodReadyFirst is a service whose pod was ready long before the endpoints of its ServerlessService were
+	// populated
+	RacePodReadyFirst = "pod_ready_first"
+	// RaceEndpointsFirst is a service whose ServerlessService reported populated endpoints long before its pod was ready
+	RaceEndpointsFirst = "endpoints_first"
+	// RaceReadyBeforeEndpoints is a service whose Revision was ready long before the endpoints of its ServerlessService
+	// were populated, i.e. the status was ready before the data path
+	RaceReadyBeforeEndpoints = "ready_before_endpoints"
+)
+
+// DetectRaces compares the time the pod containers of the services were ready, and the time their Revision was ready,
+// with the time the endpoints of their ServerlessService were populated. The services whose gaps exceed the race gap
+// are reported as races, sorted by namespace and name. The raw records without these timestamps are skipped.
+func DetectRaces(records []pkg.MeasureRawRecord, gap time.Duration) pkg.RaceReport {
+	report := pkg.RaceReport{Gap: gap.Seconds()}
+	gaps := make([]float64, 0, len(records))
+	for _, r := range records {
+		if r.ContainersReady == nil || r.SksEndpointsPopulated == nil {
+			continue
+		}
+		report.Services++
+		race := pkg.InitScaleRace{
+			ServiceName:      r.ServiceName,
+			ServiceNamespace: r.ServiceNamespace,
+			PodToEndpoints:   millisBetween(*r.ContainersReady, *r.SksEndpointsPopulated),
+		}
+		gaps = append(gaps, math.Abs(race.PodToEndpoints))
+		switch {
+		case race.PodToEndpoints > report.Gap:
+			race.Kinds = append(race.Kinds, RacePodReadyFirst)
+			report.PodReadyFirst++
+		case race.PodToEndpoints < -report.Gap:
+			race.Kinds = append(race.Kinds, RaceEndpointsFirst)
+			report.EndpointsFirst++
+		}
+		if r.RevisionReady != nil {
+			race.RevisionToEndpoints = millisBetween(*r.RevisionReady, *r.SksEndpointsPopulated)
+			if race.RevisionToEndpoints > report.Gap {
+				race.Kinds = append(race.Kinds, RaceReadyBeforeEndpoints)
+				report.ReadyBeforeEndpoints++
+			}
+		}
+		if len(race.Kinds) > 0 {
+			report.Races = append(report.Races, race)
+		}
+	}
+	if len(gaps) > 0 {
+		report.GapP50, _ = stats.Percentile(gaps, 50)
+		report.GapP95, _ = stats.Percentile(gaps, 95)
+		report.GapMax, _ = stats.Max(gaps)
+	}
+	sort.Slice(report.Races, func(i, j int) bool {
+		if report.Races[i].ServiceNamespace != report.Races[j].ServiceNamespace {
+			return report.Races[i].ServiceNamespace < report.Races[j].ServiceNamespace
+		}
+		return report.Races[i].ServiceName < report.Races[j].ServiceName
+	})
+	return report
+}
+
+// millisBetween returns the seconds from the first to the second timestamp in milliseconds since epoch
+func millisBetween(from, to int64) float64 {
+	return float64(to-from) / 1000
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestDetectRaces(t *testing.T) {
+	ms := func(v int64) *int64 { return &v }
+	record := func(name, ns string, containersReady, revisionReady, endpoints *int64) pkg.MeasureRawRecord {
+		return pkg.MeasureRawRecord{ServiceName: name, ServiceNamespace: ns, ContainersReady: containersReady,
+			RevisionReady: revisionReady, SksEndpointsPopulated: endpoints}
+	}
+	records := []pkg.MeasureRawRecord{
+		// within the gap
+		record("ksvc-1", "ns-1", ms(1000), ms(2000), ms(2000)),
+		// the pod was ready 6s before the endpoints, the Revision 7s before
+		record("ksvc-3", "ns-2", ms(1000), ms(0), ms(7000)),
+		// the endpoints were populated 8s before the pod was ready
+		record("ksvc-2", "ns-2", ms(9000), ms(9500), ms(1000)),
+		// the Revision was ready 6s before the endpoints, the pod 1s before
+		record("ksvc-4", "ns-1", ms(5000), ms(0), ms(6000)),
+		// skipped without the endpoints
+		record("ksvc-5", "ns-1", ms(1000), ms(1000), nil),
+	}
+
+	report := DetectRaces(records, 5*time.Second)
+	assert.Equal(t, 5.0, report.Gap)
+	assert.Equal(t, 4, report.Services)
+	assert.Equal(t, 1, report.PodReadyFirst)
+	assert.Equal(t, 1, report.EndpointsFirst)
+	assert.Equal(t, 2, report.ReadyBeforeEndpoints)
+	assert.Equal(t, 8.0, report.GapMax)
+	assert.Assert(t, report.GapP50 >= 1 && report.GapP50 < 8)
+
+	assert.Equal(t, 3, len(report.Races))
+	assert.Equal(t, "ksvc-4", report.Races[0].ServiceName)
+	assert.DeepEqual(t, []string{RaceReadyBeforeEndpoints}, report.Races[0].Kinds)
+	assert.Equal(t, "ksvc-2", report.Races[1].ServiceName)
+	assert.DeepEqual(t, []string{RaceEndpointsFirst}, report.Races[1].Kinds)
+	assert.Equal(t, -8.0, report.Races[1].PodToEndpoints)
+	assert.Equal(t, "ksvc-3", report.Races[2].ServiceName)
+	assert.DeepEqual(t, []string{RacePodReadyFirst, RaceReadyBeforeEndpoints}, report.Races[2].Kinds)
+	assert.Equal(t, 7.0, report.Races[2].RevisionToEndpoints)
+
+	empty := DetectRaces(nil, time.Second)
+	assert.Equal(t, 0, empty.Services)
+	assert.Equal(t, 0, len(empty.Races))
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// isTransient returns true for errors which are likely gone on the next attempt: throttling by the API server,
+// server side and client side timeouts and an unavailable API server
+func isTransient(err error) bool {
+	if apierrors.IsTooManyRequests(err) || apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retry calls f until it succeeds, fails with an error which is not transient or Retries retries are used up.
+// The backoff before the first retry is RetryBackoff and doubles with every retry. The last error is returned.
+func (m *Measurer) retry(ctx context.Context, f func() error) error {
+	backoff := m.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= m.Retries || !isTransient(err) {
+			return err
+		}
+		m.logger.Printf("retrying in %s after transient error: %s\n", backoff, err)
+		select {
+		case <-m.Clock.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsTransient(t *testing.T) {
+	resource := schema.GroupResource{Group: "serving.knative.dev", Resource: "services"}
+	assert.Assert(t, isTransient(apierrors.NewTooManyRequests("slow down", 1)))
+	assert.Assert(t, isTransient(apierrors.NewServerTimeout(resource, "get", 1)))
+	assert.Assert(t, isTransient(apierrors.NewTimeoutError("timeout", 1)))
+	assert.Assert(t, isTransient(apierrors.NewServiceUnavailable("unavailable")))
+	assert.Assert(t, !isTransient(apierrors.NewNotFound(resource, "ksvc-1")))
+	assert.Assert(t, !isTransient(errors.New("failed")))
+}
+
+func TestRetry(t *testing.T) {
+	p, _ := newMeasureTestParams()
+	m := NewMeasurer(p, nil, nil)
+	m.Retries = 2
+	m.RetryBackoff = time.Millisecond
+	throttled := apierrors.NewTooManyRequests("slow down", 1)
+
+	t.Run("succeed after transient errors", func(t *testing.T) {
+		calls := 0
+		err := m.retry(context.Background(), func() error {
+			calls++
+			if calls < 3 {
+				return throttled
+			}
+			return nil
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("give up after the retries", func(t *testing.T) {
+		calls := 0
+		err := m.retry(context.Background(), func() error {
+			calls++
+			return throttled
+		})
+		assert.Equal(t, throttled, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("don't retry other errors", func(t *testing.T) {
+		calls := 0
+		notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "services"}, "ksvc-1")
+		err := m.retry(context.Background(), func() error {
+			calls++
+			return notFound
+		})
+		assert.Equal(t, notFound, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stop on cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		m.RetryBackoff = time.Hour
+		calls := 0
+		err := m.retry(ctx, func() error {
+			calls++
+			return throttled
+		})
+		assert.Equal(t, throttled, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"context"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/kperf/pkg"
+	servingv1api "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// rolloutStatus returns statusProgressDeadlineExceeded if the latest created revision of a service which is not
+// ready failed since its Deployment didn't make progress within the progress deadline, and keeps the failed rollout
+// in trace. Otherwise it returns statusNotReady.
+func (m *Measurer) rolloutStatus(ctx context.Context, c clients, name types.NamespacedName, revisionName string, trace *serviceTrace) serviceStatus {
+	if revisionName == "" {
+		return statusNotReady
+	}
+	var revisionIns *servingv1api.Revision
+	trace.api.start()
+	err := m.retry(ctx, func() (err error) {
+		revisionIns, err = c.serving.Revisions(name.Namespace).Get(ctx, revisionName, metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err != nil {
+		return statusNotReady
+	}
+	cond := revisionIns.Status.GetCondition(servingv1api.RevisionConditionResourcesAvailable)
+	if cond == nil || cond.Reason != servingv1api.ReasonProgressDeadlineExceeded {
+		cond = revisionIns.Status.GetCondition(servingv1api.RevisionConditionReady)
+	}
+	if cond == nil || cond.Reason != servingv1api.ReasonProgressDeadlineExceeded {
+		return statusNotReady
+	}
+	trace.knative("Revision", revisionIns, revisionIns.Status.Conditions)
+
+	rollout := &pkg.FailedRollout{
+		ServiceName:      name.Name,
+		ServiceNamespace: name.Namespace,
+		Revision:         revisionName,
+		Reason:           cond.Reason,
+		Message:          cond.Message,
+	}
+	// the deadline is the one of the config-deployment ConfigMap when the Deployment was created
+	var deploymentIns *appsv1.Deployment
+	trace.api.start()
+	err = m.retry(ctx, func() (err error) {
+		deploymentIns, err = m.params.ClientSet.AppsV1().Deployments(name.Namespace).Get(ctx, revisionName+"-deployment", metav1.GetOptions{})
+		return err
+	})
+	trace.api.stop()
+	if err == nil && deploymentIns.Spec.ProgressDeadlineSeconds != nil {
+		rollout.ProgressDeadline = float64(*deploymentIns.Spec.ProgressDeadlineSeconds)
+	}
+	m.fail(trace, pkg.FailureProgressDeadlineExceeded, "revision %s of %s/%s exceeded its progress deadline of %.0fs and skip measuring", revisionName, name.Name, name.Namespace, rollout.ProgressDeadline)
+	trace.rollout = rollout
+	return statusProgressDeadlineExceeded
+}
+
+// sortFailedRollouts sorts the failed rollouts by namespace and name of the service
+func sortFailedRollouts(rollouts []pkg.FailedRollout) {
+	sort.Slice(rollouts, func(i, j int) bool {
+		if rollouts[i].ServiceNamespace != rollouts[j].ServiceNamespace {
+			return rollouts[i].ServiceNamespace < rollouts[j].ServiceNamespace
+		}
+		return rollouts[i].ServiceName < rollouts[j].ServiceName
+	})
+}
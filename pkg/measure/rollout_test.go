@@ -0,0 +1,76 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestMeasureProgressDeadlineExceeded(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ksvc-1-00001-deployment", Namespace: "ns-1"},
+		Spec:       appsv1.DeploymentSpec{ProgressDeadlineSeconds: ptr.Int32(120)},
+	}
+	p, fake := newMeasureTestParams(deployment)
+	fake.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		svc := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: action.(clienttesting.GetAction).GetName(), Namespace: "ns-1"}}
+		svc.Status.Conditions = []apis.Condition{{Type: servingv1.ServiceConditionReady, Status: corev1.ConditionFalse}}
+		svc.Status.LatestCreatedRevisionName = svc.Name + "-00001"
+		return true, svc, nil
+	})
+	fake.PrependReactor("get", "revisions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		rev := &servingv1.Revision{ObjectMeta: metav1.ObjectMeta{Name: action.(clienttesting.GetAction).GetName(), Namespace: "ns-1"}}
+		if rev.Name == "ksvc-1-00001" {
+			rev.Status.Conditions = []apis.Condition{{
+				Type:    servingv1.RevisionConditionReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  servingv1.ReasonProgressDeadlineExceeded,
+				Message: "Initial scale was never achieved",
+			}}
+		}
+		return true, rev, nil
+	})
+
+	measurer := NewMeasurer(p, nil, nil)
+	result, err := measurer.Measure(context.Background(), []types.NamespacedName{{Namespace: "ns-1", Name: "ksvc-1"}, {Namespace: "ns-1", Name: "ksvc-2"}})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, result.Summary.Service.ProgressDeadlineExceededCount)
+	assert.Equal(t, 1, result.Summary.Service.NotReadyCount)
+	assert.Equal(t, StateProgressDeadlineExceeded, result.States["ns-1/ksvc-1"])
+	assert.Equal(t, StateNotReady, result.States["ns-1/ksvc-2"])
+	assert.Equal(t, 1, len(result.Summary.FailedRollouts))
+	rollout := result.Summary.FailedRollouts[0]
+	assert.Equal(t, "ksvc-1-00001", rollout.Revision)
+	assert.Equal(t, 120.0, rollout.ProgressDeadline)
+	assert.Equal(t, "Initial scale was never achieved", rollout.Message)
+
+	out := &bytes.Buffer{}
+	result.WriteSummary(out, SummaryOptions{})
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Total: 2 | Ready: 0 NotReady: 1 NotFound: 0 Fail: 0 ProgressDeadlineExceeded: 1\n")))
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("Revision ksvc-1-00001 of ns-1/ksvc-1 exceeded the progress deadline of 120s: Initial scale was never achieved\n")))
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// SampleFirst samples the first services
+	SampleFirst = "first"
+	// SampleRandom samples services at random
+	SampleRandom = "random"
+	// SampleStratified splits the services into as many strata of consecutive services as services are sampled
+	// and samples one service at random from every stratum, so that the sample covers the whole range of services
+	SampleStratified = "stratified"
+	// SampleNamespace samples every namespace proportionally to its number of services and the services of a
+	// namespace at random, so that the sample has the same mix of namespaces as all services
+	SampleNamespace = "namespace"
+)
+
+// Sample returns n of the services chosen by the strategy, in the order of the services. All services are
+// returned if there are not more than n.
+func Sample(services []types.NamespacedName, n int, strategy string, rnd *rand.Rand) ([]types.NamespacedName, error) {
+	if err := ValidateSampleStrategy(strategy); err != nil {
+		return nil, err
+	}
+	if n >= len(services) {
+		return services, nil
+	}
+	if n < 0 {
+		n = 0
+	}
+	switch strategy {
+	case SampleFirst:
+		return services[:n], nil
+	case SampleRandom:
+		indexes := rnd.Perm(len(services))[:n]
+		sort.Ints(indexes)
+		return pick(services, indexes), nil
+	case SampleStratified:
+		indexes := make([]int, 0, n)
+		for i := 0; i < n; i++ {
+			low, high := i*len(services)/n, (i+1)*len(services)/n
+			indexes = append(indexes, low+rnd.Intn(high-low))
+		}
+		return pick(services, indexes), nil
+	default: // SampleNamespace
+		return pick(services, sampleNamespaces(services, n, rnd)), nil
+	}
+}
+
+// SampleStrategies are the supported sample strategies
+var SampleStrategies = []string{SampleRandom, SampleFirst, SampleStratified, SampleNamespace}
+
+// ValidateSampleStrategy returns an error if the sample strategy is not supported
+func ValidateSampleStrategy(strategy string) error {
+	for _, s := range SampleStrategies {
+		if s == strategy {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported sample strategy %q, expected one of %s", strategy, strings.Join(SampleStrategies, ","))
+}
+
+// sampleNamespaces returns the sorted indexes of n services sampled from every namespace proportionally to its
+// number of services. The quotas are rounded down and the remaining services are given to the namespaces with the
+// largest remainders.
+func sampleNamespaces(services []types.NamespacedName, n int, rnd *rand.Rand) []int {
+	namespaces := make([]string, 0)
+	members := make(map[string][]int)
+	for i, svc := range services {
+		if _, ok := members[svc.Namespace]; !ok {
+			namespaces = append(namespaces, svc.Namespace)
+		}
+		members[svc.Namespace] = append(members[svc.Namespace], i)
+	}
+
+	quotas := make(map[string]int, len(namespaces))
+	remainders := make(map[string]int, len(namespaces))
+	assigned := 0
+	for _, ns := range namespaces {
+		share := n * len(members[ns])
+		quotas[ns] = share / len(services)
+		remainders[ns] = share % len(services)
+		assigned += quotas[ns]
+	}
+	byRemainder := append([]string(nil), namespaces...)
+	sort.SliceStable(byRemainder, func(i, j int) bool {
+		return remainders[byRemainder[i]] > remainders[byRemainder[j]]
+	})
+	for i := 0; assigned < n; i++ {
+		quotas[byRemainder[i]]++
+		assigned++
+	}
+
+	indexes := make([]int, 0, n)
+	for _, ns := range namespaces {
+		for _, j := range rnd.Perm(len(members[ns]))[:quotas[ns]] {
+			indexes = append(indexes, members[ns][j])
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+func pick(services []types.NamespacedName, indexes []int) []types.NamespacedName {
+	picked := make([]types.NamespacedName, 0, len(indexes))
+	for _, i := range indexes {
+		picked = append(picked, services[i])
+	}
+	return picked
+}
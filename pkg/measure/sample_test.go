@@ -0,0 +1,90 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSample(t *testing.T) {
+	services := make([]types.NamespacedName, 0, 100)
+	for i := 0; i < 100; i++ {
+		services = append(services, types.NamespacedName{Namespace: "ns-1", Name: fmt.Sprintf("ksvc-%d", i)})
+	}
+	index := func(svc types.NamespacedName) int {
+		var i int
+		fmt.Sscanf(svc.Name, "ksvc-%d", &i)
+		return i
+	}
+
+	t.Run("all services if there are not more", func(t *testing.T) {
+		sampled, err := Sample(services, 100, SampleRandom, rand.New(rand.NewSource(1)))
+		assert.NilError(t, err)
+		assert.Equal(t, 100, len(sampled))
+	})
+
+	t.Run("first", func(t *testing.T) {
+		sampled, err := Sample(services, 3, SampleFirst, nil)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, services[:3], sampled)
+	})
+
+	t.Run("random keeps the order", func(t *testing.T) {
+		sampled, err := Sample(services, 10, SampleRandom, rand.New(rand.NewSource(1)))
+		assert.NilError(t, err)
+		assert.Equal(t, 10, len(sampled))
+		for i := 1; i < len(sampled); i++ {
+			assert.Assert(t, index(sampled[i-1]) < index(sampled[i]))
+		}
+	})
+
+	t.Run("stratified covers the range", func(t *testing.T) {
+		sampled, err := Sample(services, 10, SampleStratified, rand.New(rand.NewSource(1)))
+		assert.NilError(t, err)
+		assert.Equal(t, 10, len(sampled))
+		for i, svc := range sampled {
+			assert.Assert(t, index(svc) >= i*10 && index(svc) < (i+1)*10, "service %s not in stratum %d", svc, i)
+		}
+	})
+
+	t.Run("namespace samples proportionally", func(t *testing.T) {
+		// ns-1 has 100 services, ns-2 50 and ns-3 10
+		mixed := append([]types.NamespacedName(nil), services...)
+		for i := 0; i < 50; i++ {
+			mixed = append(mixed, types.NamespacedName{Namespace: "ns-2", Name: fmt.Sprintf("ksvc-%d", i)})
+		}
+		for i := 0; i < 10; i++ {
+			mixed = append(mixed, types.NamespacedName{Namespace: "ns-3", Name: fmt.Sprintf("ksvc-%d", i)})
+		}
+		sampled, err := Sample(mixed, 17, SampleNamespace, rand.New(rand.NewSource(1)))
+		assert.NilError(t, err)
+		counts := map[string]int{}
+		for _, svc := range sampled {
+			counts[svc.Namespace]++
+		}
+		// the quotas are 10.625, 5.3125 and 1.0625, the remaining service goes to ns-1 with the largest remainder
+		assert.DeepEqual(t, map[string]int{"ns-1": 11, "ns-2": 5, "ns-3": 1}, counts)
+	})
+
+	t.Run("unsupported strategy", func(t *testing.T) {
+		_, err := Sample(services, 10, "smart", nil)
+		assert.ErrorContains(t, err, "unsupported sample strategy \"smart\"")
+	})
+}
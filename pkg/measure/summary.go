@@ -0,0 +1,334 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+)
+
+const (
+	// SummaryFormatTable writes the summary as tables which fit the terminal
+	SummaryFormatTable = "table"
+	// SummaryFormatYAML writes the same breakdown as an indented YAML block, e.g. to paste it into an issue
+	SummaryFormatYAML = "yaml"
+)
+
+// SummaryFormats are the supported formats of the summary
+var SummaryFormats = []string{SummaryFormatTable, SummaryFormatYAML}
+
+// ValidateSummaryFormat returns an error if the format of the summary is not supported
+func ValidateSummaryFormat(format string) error {
+	for _, f := range SummaryFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported summary format %q, expected one of %s", format, strings.Join(SummaryFormats, ","))
+}
+
+// SummaryOptions controls how the summary is written
+type SummaryOptions struct {
+	// Format is one of SummaryFormats, the table format is the default
+	Format string
+	render.Options
+}
+
+// summaryPhase is a phase of the summary with the phases it breaks down into, the name is the one of the CSV column
+type summaryPhase struct {
+	name    string
+	title   string
+	total   float64
+	average float64
+	// statistics are the percentiles of the phase, they are not set if the result has none
+	statistics *pkg.PhaseStatistics
+	phases     []summaryPhase
+}
+
+// phaseTree returns the phases of the summary, the phases of a revision and the ones of a route
+func phaseTree(s pkg.MeasureResult) []summaryPhase {
+	phase := func(name, title string, total, average float64, phases ...summaryPhase) summaryPhase {
+		p := summaryPhase{name: name, title: title, total: total, average: average, phases: phases}
+		if statistics, ok := s.Result.Phases[name]; ok {
+			p.statistics = &statistics
+		}
+		return p
+	}
+	return []summaryPhase{
+		phase("configuration_ready", "Configuration Ready", s.Sums.SvcConfigurationsReadySum, s.Result.AverageSvcConfigurationReadySum,
+			phase("revision_ready", "Revision Ready", s.Sums.RevisionReadySum, s.Result.AverageRevisionReadySum,
+				phase("deployment_created", "Deployment Created", s.Sums.DeploymentCreatedSum, s.Result.AverageDeploymentCreatedSum,
+					phase("pod_scheduled", "Pod Scheduled", s.Sums.PodScheduledSum, s.Result.AveragePodScheduledSum),
+					phase("containers_ready", "Pod Containers Ready", s.Sums.ContainersReadySum, s.Result.AverageContainersReadySum,
+						phase("queue-proxy_started", "Pod queue-proxy Started", s.Sums.QueueProxyStartedSum, s.Result.AverageQueueProxyStartedSum),
+						phase("user-container_started", "Pod user-container Started", s.Sums.UserContrainerStartedSum, s.Result.AverageUserContrainerStartedSum))),
+				phase("kpa_active", "PodAutoscaler Active", s.Sums.KpaActiveSum, s.Result.AverageKpaActiveSum,
+					phase("sks_ready", "ServerlessService Ready", s.Sums.SksReadySum, s.Result.AverageSksReadySum,
+						phase("sks_activator_endpoints_populated", "ServerlessService ActivatorEndpointsPopulated", s.Sums.SksActivatorEndpointsPopulatedSum, s.Result.AverageSksActivatorEndpointsPopulatedSum),
+						phase("sks_endpoints_populated", "ServerlessService EndpointsPopulated", s.Sums.SksEndpointsPopulatedSum, s.Result.AverageSksEndpointsPopulatedSum))))),
+		phase("route_ready", "Route Ready", s.Sums.SvcRoutesReadySum, s.Result.AverageSvcRoutesReadySum,
+			phase("ingress_ready", "Ingress Ready", s.Sums.IngressReadySum, s.Result.AverageIngressReadySum,
+				phase("ingress_config_ready", "Ingress Network Configured", s.Sums.IngressNetworkConfiguredSum, s.Result.AverageIngressNetworkConfiguredSum),
+				phase("ingress_lb_ready", "Ingress LoadBalancer Ready", s.Sums.IngressLoadBalancerReadySum, s.Result.AverageIngressLoadBalancerReadySum)),
+			phase("certificate_ready", "Certificate Ready", s.Sums.CertificateReadySum, s.Result.AverageCertificateReadySum)),
+	}
+}
+
+// statistic is a statistic of the overall ready durations
+type statistic struct {
+	name  string
+	value float64
+}
+
+// overallStatistics returns the statistics of the overall ready durations in the order they are written
+func overallStatistics(r pkg.Result) []statistic {
+	return []statistic{
+		{"Total", r.OverallTotal},
+		{"Average", r.OverallAverage},
+		{"Median", r.OverallMedian},
+		{"Min", r.OverallMin},
+		{"Max", r.OverallMax},
+		{"Percentile50", r.P50},
+		{"Percentile90", r.P90},
+		{"Percentile95", r.P95},
+		{"Percentile98", r.P98},
+		{"Percentile99", r.P99},
+	}
+}
+
+// phaseStatistics returns the statistics of the durations of a phase in the order they are written
+func phaseStatistics(s pkg.PhaseStatistics) []statistic {
+	return []statistic{
+		{"Median", s.Median},
+		{"Min", s.Min},
+		{"Max", s.Max},
+		{"Percentile90", s.P90},
+		{"Percentile95", s.P95},
+		{"Percentile99", s.P99},
+	}
+}
+
+// WriteSummary writes the summary of the measurement in the format of 'kperf service measure'
+func (r *Result) WriteSummary(w io.Writer, options SummaryOptions) {
+	if options.Format == SummaryFormatYAML {
+		writeSummaryYAML(w, r.Summary)
+		return
+	}
+	s := r.Summary
+	total := serviceTotal(s.Service)
+	if s.Service.ReadyCount == 0 {
+		fmt.Fprintf(w, "-----------------------------\n")
+		writeBasicInformation(w, s.KnativeInfo, options.Options)
+		fmt.Fprintf(w, "Service Ready Measurement:\n")
+		fmt.Fprintf(w, "Total: %d | Ready: %d NotReady: %d NotFound: %d Fail: %d%s\n", total, s.Service.ReadyCount, s.Service.NotReadyCount, s.Service.NotFoundCount, s.Service.FailCount, optionalCounts(s.Service))
+		writeFailedRollouts(w, s.FailedRollouts)
+		if len(s.Failures) > 0 {
+			fmt.Fprintf(w, "\n")
+			writeFailureReasons(w, s.Failures, options.Options)
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "-------- Measurement --------\n")
+	writeBasicInformation(w, s.KnativeInfo, options.Options)
+	fmt.Fprintf(w, "Total: %d | Ready: %d NotReady: %d NotFound: %d Fail: %d%s\n\n", total, s.Service.ReadyCount, s.Service.NotReadyCount, s.Service.NotFoundCount, s.Service.FailCount, optionalCounts(s.Service))
+
+	// the bars compare the averages of the phases
+	phases := phaseTree(s)
+	var longest float64
+	var walk func(phases []summaryPhase)
+	walk = func(phases []summaryPhase) {
+		for _, p := range phases {
+			if p.average > longest {
+				longest = p.average
+			}
+			walk(p.phases)
+		}
+	}
+	walk(phases)
+	var rows func(phases []summaryPhase) []*render.Row
+	rows = func(phases []summaryPhase) []*render.Row {
+		result := make([]*render.Row, 0, len(phases))
+		for _, p := range phases {
+			values := []string{seconds(p.total), seconds(p.average)}
+			if len(s.Result.Phases) > 0 {
+				var statistics pkg.PhaseStatistics
+				if p.statistics != nil {
+					statistics = *p.statistics
+				}
+				values = append(values, seconds(statistics.Median), seconds(statistics.P95), seconds(statistics.P99))
+			}
+			row := &render.Row{Name: p.title, Values: values, Children: rows(p.phases)}
+			if longest > 0 {
+				row.Bar = p.average / longest
+			}
+			result = append(result, row)
+		}
+		return result
+	}
+	// the percentiles of the phases are only known if the result was measured with them
+	header := []string{"PHASE", "TOTAL", "AVERAGE"}
+	if len(s.Result.Phases) > 0 {
+		header = append(header, "MEDIAN", "P95", "P99")
+	}
+	render.Table(w, header, rows(phases), options.Options)
+
+	fmt.Fprintf(w, "\n-----------------------------\n")
+	fmt.Fprintf(w, "Overall Service Ready Measurement:\n")
+	state := func(name string, count int, style string) *render.Row {
+		row := &render.Row{Name: name, Values: []string{strconv.Itoa(count), fmt.Sprintf("%.2f%%", float64(count)/float64(total)*100)}, Bar: -1}
+		if count > 0 {
+			row.Style = style
+		}
+		return row
+	}
+	states := []*render.Row{
+		state("Ready", s.Service.ReadyCount, render.Green),
+		state("NotReady", s.Service.NotReadyCount, render.Red),
+		state("NotFound", s.Service.NotFoundCount, render.Red),
+		state("Fail", s.Service.FailCount, render.Red),
+	}
+	if s.Service.ProgressDeadlineExceededCount > 0 {
+		states = append(states, state("ProgressDeadlineExceeded", s.Service.ProgressDeadlineExceededCount, render.Red))
+	}
+	if s.Service.TimedOutCount > 0 {
+		states = append(states, state("TimedOut", s.Service.TimedOutCount, render.Red))
+	}
+	states = append(states, &render.Row{Name: "Total", Values: []string{strconv.Itoa(total), "100.00%"}, Bar: -1})
+	render.Table(w, []string{"SERVICES", "COUNT", "PERCENT"}, states, options.Options)
+	fmt.Fprintf(w, "\n")
+	if len(s.FailedRollouts) > 0 {
+		writeFailedRollouts(w, s.FailedRollouts)
+		fmt.Fprintf(w, "\n")
+	}
+	if len(s.Failures) > 0 {
+		writeFailureReasons(w, s.Failures, options.Options)
+	}
+	if len(s.Containers) > 0 {
+		writeContainers(w, s.Containers, options.Options)
+	}
+	if len(s.ImagePulls) > 0 {
+		writeImagePulls(w, s.ImagePulls, options.Options)
+	}
+	if len(s.GatewayRoutes) > 0 {
+		writeGatewayRoutes(w, s.GatewayRoutes, options.Options)
+	}
+	statistics := []*render.Row{}
+	for _, statistic := range overallStatistics(s.Result) {
+		statistics = append(statistics, &render.Row{Name: statistic.name, Values: []string{seconds(statistic.value)}, Bar: -1})
+	}
+	render.Table(w, []string{"STATISTIC", "DURATION"}, statistics, options.Options)
+}
+
+// serviceTotal returns the number of services in all states
+func serviceTotal(c pkg.ServiceCount) int {
+	return c.ReadyCount + c.NotReadyCount + c.NotFoundCount + c.FailCount + c.ProgressDeadlineExceededCount + c.TimedOutCount
+}
+
+// optionalCounts returns the counts of the services which exceeded the progress deadline or timed out to append to
+// the counts of the other states, they are left out if there are none
+func optionalCounts(c pkg.ServiceCount) string {
+	counts := ""
+	if c.ProgressDeadlineExceededCount > 0 {
+		counts += fmt.Sprintf(" ProgressDeadlineExceeded: %d", c.ProgressDeadlineExceededCount)
+	}
+	if c.TimedOutCount > 0 {
+		counts += fmt.Sprintf(" TimedOut: %d", c.TimedOutCount)
+	}
+	return counts
+}
+
+// writeFailedRollouts writes the revisions which exceeded the progress deadline with the deadline
+func writeFailedRollouts(w io.Writer, rollouts []pkg.FailedRollout) {
+	for _, r := range rollouts {
+		fmt.Fprintf(w, "Revision %s of %s/%s exceeded the progress deadline of %.0fs: %s\n", r.Revision, r.ServiceNamespace, r.ServiceName, r.ProgressDeadline, r.Message)
+	}
+}
+
+func writeBasicInformation(w io.Writer, info pkg.KnativeInfo, options render.Options) {
+	render.Table(w, []string{"COMPONENT", "VERSION"}, []*render.Row{
+		{Name: "Knative Serving", Values: []string{info.ServingVersion}, Bar: -1},
+		{Name: "Knative Eventing", Values: []string{info.EventingVersion}, Bar: -1},
+		{Name: fmt.Sprintf("Ingress (%s)", info.IngressController), Values: []string{info.IngressVersion}, Bar: -1},
+	}, options)
+}
+
+// writeSummaryYAML writes the summary as a YAML block with the phases nested like the table
+func writeSummaryYAML(w io.Writer, s pkg.MeasureResult) {
+	total := serviceTotal(s.Service)
+	fmt.Fprintf(w, "measurement:\n")
+	fmt.Fprintf(w, "  knative:\n")
+	fmt.Fprintf(w, "    serving: %s\n", strconv.Quote(s.KnativeInfo.ServingVersion))
+	fmt.Fprintf(w, "    eventing: %s\n", strconv.Quote(s.KnativeInfo.EventingVersion))
+	fmt.Fprintf(w, "    ingressController: %s\n", strconv.Quote(s.KnativeInfo.IngressController))
+	fmt.Fprintf(w, "    ingressVersion: %s\n", strconv.Quote(s.KnativeInfo.IngressVersion))
+	fmt.Fprintf(w, "  services:\n")
+	fmt.Fprintf(w, "    total: %d\n", total)
+	fmt.Fprintf(w, "    ready: %d\n", s.Service.ReadyCount)
+	fmt.Fprintf(w, "    notReady: %d\n", s.Service.NotReadyCount)
+	fmt.Fprintf(w, "    notFound: %d\n", s.Service.NotFoundCount)
+	fmt.Fprintf(w, "    fail: %d\n", s.Service.FailCount)
+	if s.Service.ProgressDeadlineExceededCount > 0 {
+		fmt.Fprintf(w, "    progressDeadlineExceeded: %d\n", s.Service.ProgressDeadlineExceededCount)
+	}
+	if s.Service.TimedOutCount > 0 {
+		fmt.Fprintf(w, "    timedOut: %d\n", s.Service.TimedOutCount)
+	}
+	if len(s.Failures) > 0 {
+		fmt.Fprintf(w, "  failureReasons:\n")
+		for _, r := range FailureReasons(s.Failures) {
+			fmt.Fprintf(w, "    %s: %d\n", r.Reason, r.Count)
+		}
+	}
+	if s.Service.ReadyCount == 0 {
+		return
+	}
+	var phases func(indent string, ps []summaryPhase)
+	phases = func(indent string, ps []summaryPhase) {
+		fmt.Fprintf(w, "%sphases:\n", indent)
+		for _, p := range ps {
+			fmt.Fprintf(w, "%s  %s:\n", indent, p.name)
+			fmt.Fprintf(w, "%s    total: %s\n", indent, yamlFloat(p.total))
+			fmt.Fprintf(w, "%s    average: %s\n", indent, yamlFloat(p.average))
+			if p.statistics != nil {
+				for _, statistic := range phaseStatistics(*p.statistics) {
+					fmt.Fprintf(w, "%s    %s: %s\n", indent, strings.ToLower(statistic.name[:1])+statistic.name[1:], yamlFloat(statistic.value))
+				}
+			}
+			if len(p.phases) > 0 {
+				phases(indent+"    ", p.phases)
+			}
+		}
+	}
+	phases("  ", phaseTree(s))
+	fmt.Fprintf(w, "  overall:\n")
+	for _, statistic := range overallStatistics(s.Result) {
+		fmt.Fprintf(w, "    %s: %s\n", strings.ToLower(statistic.name[:1])+statistic.name[1:], yamlFloat(statistic.value))
+	}
+}
+
+// seconds formats a duration in seconds like the other durations of the summary
+func seconds(s float64) string {
+	return fmt.Sprintf("%fs", s)
+}
+
+func yamlFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
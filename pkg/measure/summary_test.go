@@ -0,0 +1,133 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/render"
+)
+
+func newSummaryTestResult() *Result {
+	result := &Result{}
+	result.Summary.KnativeInfo = pkg.KnativeInfo{ServingVersion: "1.3.0", EventingVersion: "Unknown", IngressController: "Istio", IngressVersion: "1.12.0"}
+	result.Summary.Service = pkg.ServiceCount{ReadyCount: 2, FailCount: 2}
+	result.Summary.Sums.SvcConfigurationsReadySum = 20
+	result.Summary.Result.AverageSvcConfigurationReadySum = 10
+	result.Summary.Sums.SvcRoutesReadySum = 25
+	result.Summary.Result.AverageSvcRoutesReadySum = 12.5
+	result.Summary.Result.OverallTotal = 25
+	result.Summary.Result.OverallAverage = 12.5
+	result.Summary.Result.P99 = 15
+	result.Summary.Result.Phases = map[string]pkg.PhaseStatistics{
+		"route_ready": {Min: 10, Max: 15, Median: 12.5, P90: 14.5, P95: 14.75, P99: 14.95},
+	}
+	return result
+}
+
+func TestWriteSummary(t *testing.T) {
+	t.Run("write the summary as tables", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		newSummaryTestResult().WriteSummary(out, SummaryOptions{Options: render.Options{Width: 80, Color: true}})
+		summary := out.String()
+		assert.Assert(t, strings.Contains(summary, "Knative Serving     1.3.0\n"), summary)
+		assert.Assert(t, strings.Contains(summary, "Ingress (Istio)    1.12.0\n"), summary)
+		assert.Assert(t, strings.Contains(summary, "Route Ready  "), summary)
+		assert.Assert(t, regexp.MustCompile(`PHASE +TOTAL +AVERAGE +MEDIAN +P95 +P99`).MatchString(summary), summary)
+		assert.Assert(t, strings.Contains(summary, "12.500000s  12.500000s  14.750000s  14.950000s"), summary)
+		assert.Assert(t, strings.Contains(summary, "\x1b[32mReady         2   50.00%\x1b[0m\nNotReady      0    0.00%\n"), summary)
+		assert.Assert(t, strings.Contains(summary, "\x1b[31mFail          2   50.00%\x1b[0m\nTotal         4  100.00%\n"), summary)
+		assert.Assert(t, strings.Contains(summary, "Percentile99  15.000000s\n"), summary)
+		ansi := regexp.MustCompile("\x1b\\[[0-9]*m")
+		for _, line := range strings.Split(summary, "\n") {
+			assert.Assert(t, len([]rune(ansi.ReplaceAllString(line, ""))) <= 80, "line %q is wider than the terminal", line)
+		}
+	})
+
+	t.Run("write the summary as YAML", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		newSummaryTestResult().WriteSummary(out, SummaryOptions{Format: SummaryFormatYAML})
+		summary := out.String()
+		assert.Assert(t, strings.HasPrefix(summary, `measurement:
+  knative:
+    serving: "1.3.0"
+    eventing: "Unknown"
+    ingressController: "Istio"
+    ingressVersion: "1.12.0"
+  services:
+    total: 4
+    ready: 2
+    notReady: 0
+    notFound: 0
+    fail: 2
+  phases:
+    configuration_ready:
+      total: 20
+      average: 10
+      phases:
+        revision_ready:
+          total: 0
+          average: 0
+          phases:
+            deployment_created:
+`), summary)
+		assert.Assert(t, strings.Contains(summary, `
+    route_ready:
+      total: 25
+      average: 12.5
+      median: 12.5
+      min: 10
+      max: 15
+      percentile90: 14.5
+      percentile95: 14.75
+      percentile99: 14.95
+      phases:
+        ingress_ready:
+`), summary)
+		assert.Assert(t, strings.HasSuffix(summary, `
+  overall:
+    total: 25
+    average: 12.5
+    median: 0
+    min: 0
+    max: 0
+    percentile50: 0
+    percentile90: 0
+    percentile95: 0
+    percentile98: 0
+    percentile99: 15
+`), summary)
+	})
+
+	t.Run("write the YAML without phases if no service is ready", func(t *testing.T) {
+		result := &Result{}
+		result.Summary.Service.NotFoundCount = 1
+		out := &bytes.Buffer{}
+		result.WriteSummary(out, SummaryOptions{Format: SummaryFormatYAML})
+		assert.Assert(t, strings.HasSuffix(out.String(), "    notFound: 1\n    fail: 0\n"), out.String())
+	})
+}
+
+func TestValidateSummaryFormat(t *testing.T) {
+	assert.NilError(t, ValidateSummaryFormat(SummaryFormatTable))
+	assert.NilError(t, ValidateSummaryFormat(SummaryFormatYAML))
+	assert.ErrorContains(t, ValidateSummaryFormat("json"), `unsupported summary format "json", expected one of table,yaml`)
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"knative.dev/kperf/pkg"
+)
+
+// serviceTrace holds what is collected while measuring a single service besides its record: the time spent in
+// API calls, if debug is set every timestamp of the resources read for the service, and the events of its resources
+type serviceTrace struct {
+	service    types.NamespacedName
+	api        stopwatch
+	debug      bool
+	timestamps []pkg.DebugTimestamp
+	events     []pkg.ServiceEvent
+	// labels are the labels of the resource the measurement of the service starts from
+	labels map[string]string
+	// rollout is the failed rollout of a service whose revision exceeded its progress deadline
+	rollout *pkg.FailedRollout
+	// failure is why the service couldn't be measured, nil if it was measured
+	failure *failure
+	// containers are the starts of the containers of a multi-container revision
+	containers []pkg.ContainerStarted
+	// imagePulls are the image pulls of the containers of the Pod, if the Measurer breaks them down
+	imagePulls []pkg.ImagePull
+	// gatewayRoute is the programming of the HTTPRoutes of the Ingress, if the cluster uses the Gateway API
+	gatewayRoute *pkg.GatewayRoute
+}
+
+// failure is the reason, one of the pkg.Failure constants, and the message why a service couldn't be measured
+type failure struct {
+	reason  string
+	message string
+}
+
+// add collects the timestamp of the field of a resource, zero timestamps are skipped
+func (t *serviceTrace) add(kind, name, field string, ts metav1.Time) {
+	if !t.debug || ts.IsZero() {
+		return
+	}
+	t.timestamps = append(t.timestamps, pkg.DebugTimestamp{
+		ServiceName:      t.service.Name,
+		ServiceNamespace: t.service.Namespace,
+		Kind:             kind,
+		Name:             name,
+		Field:            field,
+		Time:             ts.UTC(),
+	})
+}
+
+// knative collects the creation time and the last transition time of every condition of a Knative resource
+func (t *serviceTrace) knative(kind string, obj metav1.Object, conditions duckv1.Conditions) {
+	t.add(kind, obj.GetName(), "created", obj.GetCreationTimestamp())
+	for _, c := range conditions {
+		t.add(kind, obj.GetName(), string(c.Type), c.LastTransitionTime.Inner)
+	}
+}
+
+// deployment collects the creation time and the last update and transition time of every condition of a deployment
+func (t *serviceTrace) deployment(d *appsv1.Deployment) {
+	t.add("Deployment", d.Name, "created", d.CreationTimestamp)
+	for _, c := range d.Status.Conditions {
+		t.add("Deployment", d.Name, string(c.Type)+"/updated", c.LastUpdateTime)
+		t.add("Deployment", d.Name, string(c.Type), c.LastTransitionTime)
+	}
+}
+
+// pod collects the creation time, the last transition time of every condition and the start and finish times of
+// every init and regular container of a pod
+func (t *serviceTrace) pod(p *corev1.Pod) {
+	t.add("Pod", p.Name, "created", p.CreationTimestamp)
+	for _, c := range p.Status.Conditions {
+		t.add("Pod", p.Name, string(c.Type), c.LastTransitionTime)
+	}
+	for _, statuses := range [][]corev1.ContainerStatus{p.Status.InitContainerStatuses, p.Status.ContainerStatuses} {
+		for _, s := range statuses {
+			for _, state := range []corev1.ContainerState{s.LastTerminationState, s.State} {
+				if state.Running != nil {
+					t.add("Pod", p.Name, s.Name+"/started", state.Running.StartedAt)
+				}
+				if state.Terminated != nil {
+					t.add("Pod", p.Name, s.Name+"/started", state.Terminated.StartedAt)
+					t.add("Pod", p.Name, s.Name+"/finished", state.Terminated.FinishedAt)
+				}
+			}
+		}
+	}
+}
+
+// sortDebugTimestamps sorts the timestamps by service and keeps the order of the timestamps of a service
+func sortDebugTimestamps(timestamps []pkg.DebugTimestamp) {
+	sort.SliceStable(timestamps, func(i, j int) bool {
+		if timestamps[i].ServiceNamespace != timestamps[j].ServiceNamespace {
+			return timestamps[i].ServiceNamespace < timestamps[j].ServiceNamespace
+		}
+		return timestamps[i].ServiceName < timestamps[j].ServiceName
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestServiceTrace(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(created.Add(d)) }
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1", CreationTimestamp: at(0)},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, LastTransitionTime: at(time.Second)}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "user-container",
+				State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: at(4 * time.Second)}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: at(2 * time.Second), FinishedAt: at(3 * time.Second)}},
+			}},
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "rev-1-deployment", CreationTimestamp: at(0)},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			// the zero update time is skipped
+			{Type: appsv1.DeploymentAvailable, LastTransitionTime: at(5 * time.Second)},
+		}},
+	}
+	entry := func(kind, name, field string, d time.Duration) pkg.DebugTimestamp {
+		return pkg.DebugTimestamp{ServiceName: "ksvc-1", ServiceNamespace: "ns-1", Kind: kind, Name: name, Field: field, Time: created.Add(d)}
+	}
+
+	trace := &serviceTrace{service: types.NamespacedName{Namespace: "ns-1", Name: "ksvc-1"}, debug: true}
+	trace.pod(pod)
+	trace.deployment(deployment)
+	assert.DeepEqual(t, []pkg.DebugTimestamp{
+		entry("Pod", "pod-1", "created", 0),
+		entry("Pod", "pod-1", "PodScheduled", time.Second),
+		entry("Pod", "pod-1", "user-container/started", 2*time.Second),
+		entry("Pod", "pod-1", "user-container/finished", 3*time.Second),
+		entry("Pod", "pod-1", "user-container/started", 4*time.Second),
+		entry("Deployment", "rev-1-deployment", "created", 0),
+		entry("Deployment", "rev-1-deployment", "Available", 5*time.Second),
+	}, trace.timestamps)
+
+	// nothing is collected without debug
+	trace = &serviceTrace{}
+	trace.pod(pod)
+	assert.Equal(t, 0, len(trace.timestamps))
+}
+
+func TestSortDebugTimestamps(t *testing.T) {
+	timestamps := []pkg.DebugTimestamp{
+		{ServiceNamespace: "ns-2", ServiceName: "ksvc-1", Field: "created"},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-2", Field: "created"},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-1", Field: "created"},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-1", Field: "Ready"},
+	}
+	sortDebugTimestamps(timestamps)
+	assert.DeepEqual(t, []pkg.DebugTimestamp{
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-1", Field: "created"},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-1", Field: "Ready"},
+		{ServiceNamespace: "ns-1", ServiceName: "ksvc-2", Field: "created"},
+		{ServiceNamespace: "ns-2", ServiceName: "ksvc-1", Field: "created"},
+	}, timestamps)
+}
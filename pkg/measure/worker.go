@@ -0,0 +1,62 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/pool"
+)
+
+// stopwatch sums up the time of the clock spent between start and stop, it is used by a single worker
+type stopwatch struct {
+	clock   clock.PassiveClock
+	total   time.Duration
+	started time.Time
+}
+
+func (s *stopwatch) start() {
+	s.started = s.clock.Now()
+}
+
+func (s *stopwatch) stop() {
+	s.total += s.clock.Since(s.started)
+}
+
+// workerResults returns the services measured by every worker, the average wall time per service and the split of
+// the busy time into the time spent in API calls and the remaining compute time. The utilization is the busy time
+// of the worker relative to the elapsed time of the measurement.
+func workerResults(stats []pool.WorkerStats, apiTimes map[int]time.Duration, elapsed time.Duration) []pkg.WorkerMeasureResult {
+	results := make([]pkg.WorkerMeasureResult, 0, len(stats))
+	for worker, s := range stats {
+		result := pkg.WorkerMeasureResult{
+			Worker:      worker,
+			Services:    s.Tasks,
+			APITime:     apiTimes[worker].Seconds(),
+			ComputeTime: (s.Busy - apiTimes[worker]).Seconds(),
+		}
+		if s.Tasks > 0 {
+			result.Average = s.Busy.Seconds() / float64(s.Tasks)
+		}
+		if elapsed > 0 {
+			result.Utilization = 100 * s.Busy.Seconds() / elapsed.Seconds()
+		}
+		results = append(results, result)
+	}
+	return results
+}
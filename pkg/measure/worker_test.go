@@ -0,0 +1,92 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measure
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/pool"
+)
+
+func TestWorkerResults(t *testing.T) {
+	stats := []pool.WorkerStats{
+		{Tasks: 4, Busy: 2 * time.Second},
+		{Tasks: 0},
+	}
+	results := workerResults(stats, map[int]time.Duration{0: 1500 * time.Millisecond}, 4*time.Second)
+	assert.DeepEqual(t, []pkg.WorkerMeasureResult{
+		{Worker: 0, Services: 4, Average: 0.5, APITime: 1.5, ComputeTime: 0.5, Utilization: 50},
+		{Worker: 1},
+	}, results)
+}
+
+func TestStopwatch(t *testing.T) {
+	c := clock.NewFakePassiveClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := &stopwatch{clock: c}
+	s.start()
+	c.SetTime(c.Now().Add(time.Second))
+	s.stop()
+	assert.Equal(t, time.Second, s.total)
+
+	s.start()
+	c.SetTime(c.Now().Add(500 * time.Millisecond))
+	s.stop()
+	assert.Equal(t, 1500*time.Millisecond, s.total)
+}
+
+func TestAggregation(t *testing.T) {
+	result := &Result{NamespaceCounts: map[string]pkg.ServiceCount{}, Labels: map[string]map[string]string{}}
+	a := &aggregation{
+		measurer:   &Measurer{},
+		result:     result,
+		checkpoint: &Checkpoint{Processed: map[string]string{}},
+		busyTimes:  map[int]time.Duration{},
+		apiTimes:   map[int]time.Duration{},
+	}
+	outcome := func(name string, status serviceStatus, cancelled bool) serviceOutcome {
+		svc := types.NamespacedName{Namespace: "ns", Name: name}
+		return serviceOutcome{
+			service:   svc,
+			record:    pkg.MeasureRecord{ServiceName: name, ServiceNamespace: "ns", OverallReady: 2},
+			status:    status,
+			trace:     &serviceTrace{service: svc, api: stopwatch{total: time.Second}},
+			busy:      2 * time.Second,
+			cancelled: cancelled,
+		}
+	}
+	a.add(0, outcome("ready", statusReady, false))
+	a.add(1, outcome("not-ready", statusNotReady, false))
+	a.add(1, outcome("cancelled", statusNotFound, true))
+
+	// the cancelled service is timed, but neither counted nor checkpointed
+	assert.Equal(t, 2, a.measured)
+	assert.DeepEqual(t, map[int]time.Duration{0: 2 * time.Second, 1: 4 * time.Second}, a.busyTimes)
+	assert.DeepEqual(t, map[int]time.Duration{0: time.Second, 1: 2 * time.Second}, a.apiTimes)
+	assert.DeepEqual(t, pkg.ServiceCount{ReadyCount: 1, NotReadyCount: 1}, result.NamespaceCounts["ns"])
+	assert.Equal(t, 1, len(result.Records))
+
+	snapshot := a.snapshot()
+	assert.DeepEqual(t, map[string]string{"ns/ready": StateReady, "ns/not-ready": StateNotReady}, snapshot.Processed)
+	// the snapshot is a copy, which isn't changed by the outcomes added later
+	a.add(0, outcome("later", statusReady, false))
+	assert.Equal(t, 2, len(snapshot.Processed))
+	assert.Equal(t, 1, len(snapshot.Records))
+}
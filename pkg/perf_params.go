@@ -15,9 +15,12 @@ package pkg
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -48,6 +51,12 @@ func (params *PerfParams) Initialize() error {
 	if params.NewNetworkingClient == nil {
 		params.NewNetworkingClient = params.newNetworkingClient
 	}
+	if params.NewDynamicClient == nil {
+		params.NewDynamicClient = params.newDynamicClient
+	}
+	if params.Clock == nil {
+		params.Clock = clock.RealClock{}
+	}
 	return nil
 }
 
@@ -89,36 +98,85 @@ func (params *PerfParams) newNetworkingClient() (networkingv1alpha1.NetworkingV1
 	return client, nil
 }
 
+func (params *PerfParams) newDynamicClient() (dynamic.Interface, error) {
+	restConfig, err := params.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 // RestConfig returns REST config, which can be to use to create specific clientset
 func (params *PerfParams) RestConfig() (*rest.Config, error) {
 	var err error
 
-	if params.ClientConfig == nil {
+	if params.ClientConfig == nil && params.AuthProvider != AuthInCluster {
 		params.ClientConfig, err = params.GetClientConfig()
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	config, err := params.ClientConfig.ClientConfig()
+	config, err := params.clientConfig(params.ClientConfig)
 	if err != nil {
 		return nil, err
 	}
+	if params.QPS > 0 {
+		config.QPS = params.QPS
+	}
+	if params.Burst > 0 {
+		config.Burst = params.Burst
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &credentialRefresher{params: params, next: rt}
+	})
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &auditRecorder{params: params, next: rt}
+	})
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &readOnlyGuard{params: params, next: rt}
+	})
 
 	return config, nil
 }
 
+// clientConfig returns the REST config of the client config, which is loaded from the kubeconfig if nil, with the
+// credentials of the auth provider. With in-cluster auth the kubeconfig isn't loaded at all, so that the pod doesn't
+// need one.
+func (params *PerfParams) clientConfig(clientConfig clientcmd.ClientConfig) (*rest.Config, error) {
+	if params.AuthProvider == AuthInCluster {
+		return params.authConfig(nil)
+	}
+	var err error
+	if clientConfig == nil {
+		clientConfig, err = params.GetClientConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return params.authConfig(config)
+}
+
 // GetClientConfig gets ClientConfig from KubeCfgPath
 func (params *PerfParams) GetClientConfig() (clientcmd.ClientConfig, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if len(params.KubeCfgPath) == 0 {
-		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}), nil
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, params.configOverrides()), nil
 	}
 
 	_, err := os.Stat(params.KubeCfgPath)
 	if err == nil {
 		loadingRules.ExplicitPath = params.KubeCfgPath
-		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}), nil
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, params.configOverrides()), nil
 	}
 
 	if !os.IsNotExist(err) {
@@ -132,3 +190,42 @@ func (params *PerfParams) GetClientConfig() (clientcmd.ClientConfig, error) {
 	}
 	return nil, fmt.Errorf("Config file '%s' can not be found", params.KubeCfgPath)
 }
+
+// Reinitialize creates the clients again, e.g. after the API server or the proxy were changed
+func (params *PerfParams) Reinitialize() error {
+	params.ClientConfig = nil
+	params.ClientSet = nil
+	return params.Initialize()
+}
+
+// ForContext returns a copy of the params with new clients for the kubeconfig context, e.g. to measure several
+// clusters in one run
+func (params *PerfParams) ForContext(context string) (*PerfParams, error) {
+	p := &PerfParams{
+		KubeCfgPath:  params.KubeCfgPath,
+		Context:      context,
+		APIServer:    params.APIServer,
+		ProxyURL:     params.ProxyURL,
+		QPS:          params.QPS,
+		Burst:        params.Burst,
+		ReadOnly:     params.ReadOnly,
+		AuthProvider: params.AuthProvider,
+		AuthCluster:  params.AuthCluster,
+		AuthRegion:   params.AuthRegion,
+		Audit:        params.Audit,
+		Clock:        params.Clock,
+		Output:       params.Output,
+	}
+	if err := p.Initialize(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// configOverrides overrides the current context of the kubeconfig, and its cluster with the API server and the proxy
+func (params *PerfParams) configOverrides() *clientcmd.ConfigOverrides {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: params.Context}
+	overrides.ClusterInfo.Server = params.APIServer
+	overrides.ClusterInfo.ProxyURL = params.ProxyURL
+	return overrides
+}
@@ -0,0 +1,126 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGetClientConfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NilError(t, ioutil.WriteFile(kubeconfig, []byte(fmt.Sprintf(testKubeconfig, "https://api.cluster:6443", "token")), 0600))
+
+	t.Run("kubeconfig without overrides", func(t *testing.T) {
+		p := &PerfParams{KubeCfgPath: kubeconfig}
+		config, err := p.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, "https://api.cluster:6443", config.Host)
+		assert.Check(t, config.Proxy == nil)
+	})
+
+	t.Run("api server and proxy overrides", func(t *testing.T) {
+		p := &PerfParams{KubeCfgPath: kubeconfig, APIServer: "https://bastion.cluster:6443", ProxyURL: "socks5://localhost:1080"}
+		config, err := p.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, "https://bastion.cluster:6443", config.Host)
+
+		req, err := http.NewRequest("GET", config.Host, nil)
+		assert.NilError(t, err)
+		proxy, err := config.Proxy(req)
+		assert.NilError(t, err)
+		assert.Equal(t, "socks5://localhost:1080", proxy.String())
+	})
+
+	t.Run("qps and burst", func(t *testing.T) {
+		p := &PerfParams{KubeCfgPath: kubeconfig}
+		config, err := p.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, float32(0), config.QPS)
+		assert.Equal(t, 0, config.Burst)
+
+		p = &PerfParams{KubeCfgPath: kubeconfig, QPS: 100, Burst: 200}
+		config, err = p.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, float32(100), config.QPS)
+		assert.Equal(t, 200, config.Burst)
+	})
+
+	t.Run("context override", func(t *testing.T) {
+		multi := filepath.Join(t.TempDir(), "config")
+		assert.NilError(t, ioutil.WriteFile(multi, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://api.cluster:6443
+- name: staging
+  cluster:
+    server: https://api.staging:6443
+users:
+- name: test
+  user:
+    token: token
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+- name: staging
+  context:
+    cluster: staging
+    user: test
+current-context: test
+`), 0600))
+		p := &PerfParams{KubeCfgPath: multi, Context: "staging"}
+		config, err := p.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, "https://api.staging:6443", config.Host)
+
+		p = &PerfParams{KubeCfgPath: multi, Context: "missing"}
+		_, err = p.RestConfig()
+		assert.ErrorContains(t, err, "context \"missing\" does not exist")
+
+		p = &PerfParams{KubeCfgPath: multi, QPS: 50, Burst: 100, Output: OutputOptions{Name: "nightly", Overwrite: true}}
+		assert.NilError(t, p.Initialize())
+		staging, err := p.ForContext("staging")
+		assert.NilError(t, err)
+		assert.Equal(t, "staging", staging.Context)
+		assert.Equal(t, float32(50), staging.QPS)
+		assert.Equal(t, p.Output, staging.Output)
+		config, err = staging.RestConfig()
+		assert.NilError(t, err)
+		assert.Equal(t, "https://api.staging:6443", config.Host)
+		_, err = p.ForContext("missing")
+		assert.ErrorContains(t, err, "context \"missing\" does not exist")
+	})
+
+	t.Run("invalid proxy", func(t *testing.T) {
+		p := &PerfParams{KubeCfgPath: kubeconfig, ProxyURL: "ftp://localhost"}
+		_, err := p.RestConfig()
+		assert.ErrorContains(t, err, "proxy")
+	})
+
+	t.Run("missing kubeconfig", func(t *testing.T) {
+		p := &PerfParams{KubeCfgPath: filepath.Join(t.TempDir(), "missing")}
+		_, err := p.GetClientConfig()
+		assert.ErrorContains(t, err, "can not be found")
+	})
+}
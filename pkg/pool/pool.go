@@ -0,0 +1,174 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pool runs tasks with a fixed number of workers. It is shared by the commands which generate, measure,
+// clean or load many resources concurrently.
+package pool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+// Task is run by a worker of the pool with the context of the pool
+type Task func(ctx context.Context)
+
+// WorkerStats holds the number of tasks a single worker ran and the time it spent running them
+type WorkerStats struct {
+	Tasks int
+	Busy  time.Duration
+}
+
+// Pool runs the submitted tasks with a fixed number of workers. The tasks are queued in a bounded queue, Submit
+// blocks while it is full. Once the context is cancelled no further task is started.
+type Pool struct {
+	ctx   context.Context
+	tasks chan Task
+	group sync.WaitGroup
+	once  sync.Once
+	clock clock.PassiveClock
+	// stats holds the stats of every worker, each one is only written by its worker
+	stats []WorkerStats
+}
+
+// New starts a pool of workers which queues up to queueSize tasks. Less than 1 worker is set to 1, a negative
+// queue size to 0.
+func New(ctx context.Context, workers, queueSize int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	p := &Pool{
+		ctx:   ctx,
+		tasks: make(chan Task, queueSize),
+		clock: clock.RealClock{},
+		stats: make([]WorkerStats, workers),
+	}
+	for i := 0; i < workers; i++ {
+		p.group.Add(1)
+		go p.work(i)
+	}
+	return p
+}
+
+// WithClock sets the clock timing the tasks for the busy time of the workers, a nil clock keeps the real clock. It
+// must be called before the first Submit.
+func (p *Pool) WithClock(c clock.PassiveClock) *Pool {
+	if c != nil {
+		p.clock = c
+	}
+	return p
+}
+
+type workerKey struct{}
+
+// Worker returns the index of the worker running the task with the context, or -1 if the context is not the
+// context of a task
+func Worker(ctx context.Context) int {
+	if worker, ok := ctx.Value(workerKey{}).(int); ok {
+		return worker
+	}
+	return -1
+}
+
+func (p *Pool) work(worker int) {
+	defer p.group.Done()
+	ctx := context.WithValue(p.ctx, workerKey{}, worker)
+	for task := range p.tasks {
+		if p.ctx.Err() != nil {
+			// the queued tasks are dropped after the cancellation
+			continue
+		}
+		start := p.clock.Now()
+		task(ctx)
+		p.stats[worker].Tasks++
+		p.stats[worker].Busy += p.clock.Since(start)
+	}
+}
+
+// Submit queues the task and blocks while the queue is full. It returns the error of the context if the context
+// is cancelled before the task is queued. Submit must not be called after Wait.
+func (p *Pool) Submit(task Task) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+}
+
+// Wait closes the queue, waits until the workers finished the queued tasks and returns the stats of the workers
+func (p *Pool) Wait() []WorkerStats {
+	p.once.Do(func() { close(p.tasks) })
+	p.group.Wait()
+	stats := make([]WorkerStats, len(p.stats))
+	copy(stats, p.stats)
+	return stats
+}
+
+// ForEach runs task for the indexes 0 to n-1 with up to workers workers and returns the stats of the workers.
+// Once the context is cancelled the remaining indexes are skipped.
+func ForEach(ctx context.Context, workers, n int, task func(ctx context.Context, i int)) []WorkerStats {
+	if workers > n {
+		workers = n
+	}
+	p := New(ctx, workers, workers)
+	for i := 0; i < n; i++ {
+		index := i
+		if err := p.Submit(func(ctx context.Context) { task(ctx, index) }); err != nil {
+			break
+		}
+	}
+	return p.Wait()
+}
+
+// Result is the value the task of Collect returned for an index, with the worker which ran it
+type Result struct {
+	Index  int
+	Worker int
+	Value  interface{}
+}
+
+// Collect runs task for the indexes 0 to n-1 with up to workers workers like ForEach, and passes the value every
+// task returned to collect. collect is called by the goroutine calling Collect, one result after the other, so that
+// the results can be aggregated without locking. The results are passed through a channel holding one result per
+// worker, a worker blocks while it is full. Collect returns once all results are collected.
+func Collect(ctx context.Context, workers, n int, task func(ctx context.Context, i int) interface{}, collect func(Result)) []WorkerStats {
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	results := make(chan Result, workers)
+	var stats []WorkerStats
+	go func() {
+		defer close(results)
+		stats = ForEach(ctx, workers, n, func(ctx context.Context, i int) {
+			results <- Result{Index: i, Worker: Worker(ctx), Value: task(ctx, i)}
+		})
+	}()
+	for r := range results {
+		collect(r)
+	}
+	return stats
+}
@@ -0,0 +1,184 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/clock"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("run all submitted tasks", func(t *testing.T) {
+		var ran int64
+		p := New(context.Background(), 3, 1)
+		for i := 0; i < 10; i++ {
+			assert.NilError(t, p.Submit(func(ctx context.Context) {
+				atomic.AddInt64(&ran, 1)
+			}))
+		}
+		stats := p.Wait()
+		assert.Equal(t, int64(10), ran)
+		assert.Equal(t, 3, len(stats))
+		tasks := 0
+		for _, s := range stats {
+			tasks += s.Tasks
+		}
+		assert.Equal(t, 10, tasks)
+
+		// waiting again returns the same stats
+		assert.DeepEqual(t, stats, p.Wait())
+	})
+
+	t.Run("at least one worker", func(t *testing.T) {
+		p := New(context.Background(), 0, -1)
+		ran := false
+		assert.NilError(t, p.Submit(func(ctx context.Context) { ran = true }))
+		assert.Equal(t, 1, len(p.Wait()))
+		assert.Assert(t, ran)
+	})
+
+	t.Run("stop on cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var ran int64
+		p := New(ctx, 1, 5)
+		assert.NilError(t, p.Submit(func(ctx context.Context) {
+			close(started)
+			<-release
+			atomic.AddInt64(&ran, 1)
+		}))
+		<-started
+		for i := 0; i < 5; i++ {
+			assert.NilError(t, p.Submit(func(ctx context.Context) { atomic.AddInt64(&ran, 1) }))
+		}
+		cancel()
+		close(release)
+
+		// the queue is full and the context is cancelled, so Submit doesn't block
+		assert.Equal(t, context.Canceled, p.Submit(func(ctx context.Context) {}))
+		p.Wait()
+		assert.Equal(t, int64(1), ran)
+	})
+
+	t.Run("measure busy time", func(t *testing.T) {
+		p := New(context.Background(), 1, 0)
+		assert.NilError(t, p.Submit(func(ctx context.Context) { time.Sleep(10 * time.Millisecond) }))
+		stats := p.Wait()
+		assert.Equal(t, 1, stats[0].Tasks)
+		assert.Assert(t, stats[0].Busy >= 10*time.Millisecond)
+	})
+
+	t.Run("measure busy time with the clock", func(t *testing.T) {
+		fakeClock := clock.NewFakeClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+		p := New(context.Background(), 1, 0).WithClock(fakeClock)
+		assert.NilError(t, p.Submit(func(ctx context.Context) { fakeClock.Step(time.Minute) }))
+		stats := p.Wait()
+		assert.Equal(t, time.Minute, stats[0].Busy)
+	})
+}
+
+func TestForEach(t *testing.T) {
+	var (
+		m    sync.Mutex
+		seen []int
+	)
+	stats := ForEach(context.Background(), 10, 4, func(ctx context.Context, i int) {
+		m.Lock()
+		defer m.Unlock()
+		seen = append(seen, i)
+	})
+	// no more workers than indexes are started
+	assert.Equal(t, 4, len(stats))
+	assert.Equal(t, 4, len(seen))
+
+	stats = ForEach(context.Background(), 2, 0, func(ctx context.Context, i int) {
+		t.Fatal("task should not run without indexes")
+	})
+	assert.Equal(t, 0, stats[0].Tasks)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ran := false
+	ForEach(ctx, 2, 3, func(ctx context.Context, i int) { ran = true })
+	assert.Assert(t, !ran)
+}
+
+func TestWorker(t *testing.T) {
+	assert.Equal(t, -1, Worker(context.Background()))
+
+	var (
+		m       sync.Mutex
+		workers = map[int]int{}
+	)
+	stats := ForEach(context.Background(), 3, 9, func(ctx context.Context, i int) {
+		m.Lock()
+		defer m.Unlock()
+		workers[Worker(ctx)]++
+	})
+	// the tasks counted by the index of their worker match the stats of the worker
+	for worker, s := range stats {
+		assert.Equal(t, s.Tasks, workers[worker])
+	}
+}
+
+func TestCollect(t *testing.T) {
+	t.Run("collect every result", func(t *testing.T) {
+		sum := 0
+		indexes := map[int]bool{}
+		stats := Collect(context.Background(), 200, 1000, func(ctx context.Context, i int) interface{} {
+			return i * 2
+		}, func(r Result) {
+			// collect is not called concurrently, so the results are added up without a lock
+			sum += r.Value.(int)
+			indexes[r.Index] = true
+			assert.Assert(t, r.Worker >= 0 && r.Worker < 200)
+		})
+		assert.Equal(t, 999*1000, sum)
+		assert.Equal(t, 1000, len(indexes))
+		assert.Equal(t, 200, len(stats))
+	})
+
+	t.Run("no indexes", func(t *testing.T) {
+		stats := Collect(context.Background(), 3, 0, func(ctx context.Context, i int) interface{} {
+			t.Fatal("task should not run without indexes")
+			return nil
+		}, func(r Result) {
+			t.Fatal("nothing should be collected without indexes")
+		})
+		assert.Equal(t, 1, len(stats))
+	})
+
+	t.Run("stop on cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		collected := 0
+		Collect(ctx, 1, 10, func(ctx context.Context, i int) interface{} {
+			if i == 2 {
+				cancel()
+			}
+			return i
+		}, func(r Result) {
+			collected++
+		})
+		// the results of the tasks started before the cancellation are still collected
+		assert.Assert(t, collected >= 3 && collected < 10, "collected %d results", collected)
+	})
+}
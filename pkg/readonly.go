@@ -0,0 +1,54 @@
+// Copyright 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MutatingAnnotation marks commands which change the cluster, either through the API server or by sending
+// traffic which scales Knative Services. These commands are refused in read-only mode.
+const MutatingAnnotation = "kperf.knative.dev/mutating"
+
+// readOnlyGuard rejects every API server request which could change the cluster in read-only mode, so
+// read-only mode holds even if a command not marked as mutating tries to change the cluster. Dry run requests
+// are passed, they are admitted but never persisted. ReadOnly is checked for every request as the clients are
+// created before the flags are parsed.
+type readOnlyGuard struct {
+	params *PerfParams
+	next   http.RoundTripper
+}
+
+func (g *readOnlyGuard) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !g.params.ReadOnly {
+		return g.next.RoundTrip(req)
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return g.next.RoundTrip(req)
+	}
+	if isDryRun(req) {
+		return g.next.RoundTrip(req)
+	}
+	return nil, fmt.Errorf("read-only mode: refusing %s %s", req.Method, req.URL.Path)
+}
+
+// isDryRun returns true for the requests which only run the admission of a change without persisting it
+func isDryRun(req *http.Request) bool {
+	return req.URL.Query().Get("dryRun") == metav1.DryRunAll
+}
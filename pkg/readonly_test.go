@@ -0,0 +1,62 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadOnlyGuard(t *testing.T) {
+	var methods []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"ns-1"}}`))
+	}))
+	defer server.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NilError(t, ioutil.WriteFile(kubeconfig, []byte(fmt.Sprintf(testKubeconfig, server.URL, "token")), 0600))
+	p := &PerfParams{KubeCfgPath: kubeconfig}
+	assert.NilError(t, p.Initialize())
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}}
+
+	// read-only mode is enabled after the clients are created like by the --read-only flag
+	p.ReadOnly = true
+	_, err := p.ClientSet.CoreV1().Namespaces().Get(context.TODO(), "ns-1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, err = p.ClientSet.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+	assert.ErrorContains(t, err, "read-only mode: refusing POST /api/v1/namespaces")
+	err = p.ClientSet.CoreV1().Namespaces().Delete(context.TODO(), "ns-1", metav1.DeleteOptions{})
+	assert.ErrorContains(t, err, "read-only mode: refusing DELETE /api/v1/namespaces/ns-1")
+	// dry run requests are admitted but not persisted
+	_, err = p.ClientSet.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{http.MethodGet, http.MethodPost}, methods)
+
+	p.ReadOnly = false
+	_, err = p.ClientSet.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
+	assert.NilError(t, err)
+}
@@ -0,0 +1,225 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render writes the summaries of the commands as tables which fit the terminal they are written to.
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ANSI styles of the text written with Style
+const (
+	Bold  = "\x1b[1m"
+	Red   = "\x1b[31m"
+	Green = "\x1b[32m"
+	Cyan  = "\x1b[36m"
+	reset = "\x1b[0m"
+)
+
+const (
+	// columnGap separates the columns of a table
+	columnGap = 2
+	// minNameWidth is the width the first column is truncated to at most, so that the names stay recognizable
+	minNameWidth = 12
+	// minBarWidth is the width below which the bars are left out instead of being squeezed
+	minBarWidth = 10
+	// maxBarWidth is the width of the bars if the width is not limited
+	maxBarWidth = 30
+)
+
+// Options controls how the output is rendered
+type Options struct {
+	// Width is the number of columns the output fits into, 0 if it isn't limited, e.g. when written to a file
+	Width int
+	// Color enables ANSI colors
+	Color bool
+}
+
+// NewOptions returns the options for the output written to the file. The width is the one of the terminal, or the
+// COLUMNS environment variable if it is set. Colors are enabled on terminals unless NO_COLOR is set or TERM is
+// dumb, color and noColor force them on or off.
+func NewOptions(f *os.File, color, noColor bool) Options {
+	options := Options{}
+	terminal := f != nil && term.IsTerminal(int(f.Fd()))
+	if terminal {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil {
+			options.Width = width
+		}
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		options.Width = columns
+	}
+	switch {
+	case noColor:
+		options.Color = false
+	case color:
+		options.Color = true
+	default:
+		_, disabled := os.LookupEnv("NO_COLOR")
+		options.Color = terminal && !disabled && os.Getenv("TERM") != "dumb"
+	}
+	return options
+}
+
+// Row is a row of a table, its children are rendered below it and indented with tree lines
+type Row struct {
+	Name   string
+	Values []string
+	// Bar is the length of the bar drawn after the values relative to the longest bar, from 0 to 1. The bar is left
+	// out if it is negative.
+	Bar float64
+	// Style is the ANSI style of the name and the values, e.g. Red to highlight failures
+	Style    string
+	Children []*Row
+}
+
+// line is a row of the table with the tree lines in front of its name
+type line struct {
+	name string
+	row  *Row
+}
+
+// Table writes the rows below the header, the first column shows the hierarchy of the rows and the values are
+// aligned to the right. To fit the width the bars are left out first, then the names are truncated.
+func Table(w io.Writer, header []string, rows []*Row, options Options) {
+	lines := flatten(rows, "", "")
+
+	nameWidth := width(header[0])
+	valueWidths := make([]int, len(header)-1)
+	for i := range valueWidths {
+		valueWidths[i] = width(header[i+1])
+	}
+	bars := false
+	for _, l := range lines {
+		if n := width(l.name); n > nameWidth {
+			nameWidth = n
+		}
+		for i, value := range l.row.Values {
+			if i < len(valueWidths) && width(value) > valueWidths[i] {
+				valueWidths[i] = width(value)
+			}
+		}
+		bars = bars || l.row.Bar >= 0
+	}
+	valuesWidth := 0
+	for _, n := range valueWidths {
+		valuesWidth += columnGap + n
+	}
+
+	barWidth := 0
+	if bars {
+		barWidth = maxBarWidth
+		if options.Width > 0 {
+			barWidth = options.Width - nameWidth - valuesWidth - columnGap
+			if barWidth > maxBarWidth {
+				barWidth = maxBarWidth
+			}
+			if barWidth < minBarWidth {
+				barWidth = 0
+			}
+		}
+	}
+	if options.Width > 0 && nameWidth+valuesWidth > options.Width {
+		nameWidth = options.Width - valuesWidth
+		if nameWidth < minNameWidth {
+			nameWidth = minNameWidth
+		}
+	}
+
+	cells := make([]string, 0, len(header))
+	cells = append(cells, pad(truncate(header[0], nameWidth), nameWidth, false))
+	for i, h := range header[1:] {
+		cells = append(cells, pad(h, valueWidths[i], true))
+	}
+	fmt.Fprintln(w, Style(strings.TrimRight(strings.Join(cells, strings.Repeat(" ", columnGap)), " "), Bold, options))
+	for _, l := range lines {
+		cells = cells[:0]
+		cells = append(cells, pad(truncate(l.name, nameWidth), nameWidth, false))
+		for i := range valueWidths {
+			value := ""
+			if i < len(l.row.Values) {
+				value = l.row.Values[i]
+			}
+			cells = append(cells, pad(value, valueWidths[i], true))
+		}
+		text := strings.Join(cells, strings.Repeat(" ", columnGap))
+		if l.row.Style != "" {
+			text = Style(text, l.row.Style, options)
+		}
+		if barWidth > 0 && l.row.Bar > 0 {
+			bar := l.row.Bar
+			if bar > 1 {
+				bar = 1
+			}
+			if n := int(bar*float64(barWidth) + 0.5); n > 0 {
+				text += strings.Repeat(" ", columnGap) + Style(strings.Repeat("█", n), Cyan, options)
+			}
+		}
+		fmt.Fprintln(w, text)
+	}
+}
+
+// flatten returns the rows in the order they are written with the tree lines in front of their names
+func flatten(rows []*Row, prefix, childPrefix string) []line {
+	lines := []line{}
+	for _, row := range rows {
+		lines = append(lines, line{name: prefix + row.Name, row: row})
+		for i, child := range row.Children {
+			if i == len(row.Children)-1 {
+				lines = append(lines, flatten([]*Row{child}, childPrefix+"└─ ", childPrefix+"   ")...)
+			} else {
+				lines = append(lines, flatten([]*Row{child}, childPrefix+"├─ ", childPrefix+"│  ")...)
+			}
+		}
+	}
+	return lines
+}
+
+// Style returns the text with the ANSI style if colors are enabled
+func Style(text, ansi string, options Options) string {
+	if !options.Color || text == "" {
+		return text
+	}
+	return ansi + text + reset
+}
+
+// width returns the number of columns of the text, the tree lines are a single column each
+func width(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// truncate shortens the text to the width, the end is replaced by an ellipsis
+func truncate(text string, n int) string {
+	if width(text) <= n {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:n-1]) + "…"
+}
+
+func pad(text string, n int, right bool) string {
+	padding := strings.Repeat(" ", n-width(text))
+	if right {
+		return padding + text
+	}
+	return text + padding
+}
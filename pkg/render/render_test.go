@@ -0,0 +1,134 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func newTestRows() []*Row {
+	return []*Row{
+		{Name: "Configuration Ready", Values: []string{"10.0s", "5.0s"}, Bar: 0.5, Children: []*Row{
+			{Name: "Revision Ready", Values: []string{"8.0s", "4.0s"}, Bar: 0.4, Children: []*Row{
+				{Name: "Deployment Created", Values: []string{"2.0s", "1.0s"}, Bar: 0.1},
+			}},
+			{Name: "PodAutoscaler Active", Values: []string{"4.0s", "2.0s"}, Bar: 0.2},
+		}},
+		{Name: "Route Ready", Values: []string{"20.0s", "10.0s"}, Bar: 1},
+	}
+}
+
+func TestTable(t *testing.T) {
+	t.Run("render the tree with bars if the width is not limited", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		Table(out, []string{"PHASE", "TOTAL", "AVERAGE"}, newTestRows(), Options{})
+		assert.Equal(t, `PHASE                     TOTAL  AVERAGE
+Configuration Ready       10.0s     5.0s  ███████████████
+├─ Revision Ready          8.0s     4.0s  ████████████
+│  └─ Deployment Created   2.0s     1.0s  ███
+└─ PodAutoscaler Active    4.0s     2.0s  ██████
+Route Ready               20.0s    10.0s  ██████████████████████████████
+`, out.String())
+	})
+
+	t.Run("leave out the bars which don't fit", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		Table(out, []string{"PHASE", "TOTAL", "AVERAGE"}, newTestRows(), Options{Width: 50})
+		assert.Equal(t, `PHASE                     TOTAL  AVERAGE
+Configuration Ready       10.0s     5.0s
+├─ Revision Ready          8.0s     4.0s
+│  └─ Deployment Created   2.0s     1.0s
+└─ PodAutoscaler Active    4.0s     2.0s
+Route Ready               20.0s    10.0s
+`, out.String())
+	})
+
+	t.Run("truncate the names which don't fit", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		Table(out, []string{"PHASE", "TOTAL", "AVERAGE"}, newTestRows(), Options{Width: 32})
+		assert.Equal(t, `PHASE             TOTAL  AVERAGE
+Configuration R…  10.0s     5.0s
+├─ Revision Rea…   8.0s     4.0s
+│  └─ Deploymen…   2.0s     1.0s
+└─ PodAutoscale…   4.0s     2.0s
+Route Ready       20.0s    10.0s
+`, out.String())
+	})
+
+	t.Run("color the header and the bars", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		Table(out, []string{"PHASE", "TOTAL"}, []*Row{{Name: "Route Ready", Values: []string{"1.0s"}, Bar: 0.1}}, Options{Color: true})
+		assert.Equal(t, "\x1b[1mPHASE        TOTAL\x1b[0m\nRoute Ready   1.0s  \x1b[36m███\x1b[0m\n", out.String())
+	})
+
+	t.Run("color the rows with a style", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		Table(out, []string{"SERVICES", "COUNT"}, []*Row{{Name: "Fail", Values: []string{"2"}, Bar: -1, Style: Red}}, Options{Color: true})
+		assert.Equal(t, "\x1b[1mSERVICES  COUNT\x1b[0m\n\x1b[31mFail          2\x1b[0m\n", out.String())
+
+		out.Reset()
+		Table(out, []string{"SERVICES", "COUNT"}, []*Row{{Name: "Fail", Values: []string{"2"}, Bar: -1, Style: Red}}, Options{})
+		assert.Equal(t, "SERVICES  COUNT\nFail          2\n", out.String())
+	})
+}
+
+func TestStyle(t *testing.T) {
+	assert.Equal(t, "ready", Style("ready", Green, Options{}))
+	assert.Equal(t, "\x1b[32mready\x1b[0m", Style("ready", Green, Options{Color: true}))
+	assert.Equal(t, "", Style("", Green, Options{Color: true}))
+}
+
+// setenv sets the environment variable for the test
+func setenv(t *testing.T, key, value string) {
+	previous, ok := os.LookupEnv(key)
+	assert.NilError(t, os.Setenv(key, value))
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestNewOptions(t *testing.T) {
+	// the output of the tests is not a terminal
+	file, err := ioutil.TempFile(t.TempDir(), "out")
+	assert.NilError(t, err)
+	defer file.Close()
+
+	setenv(t, "COLUMNS", "")
+	setenv(t, "TERM", "xterm")
+	assert.DeepEqual(t, Options{}, NewOptions(file, false, false))
+	assert.DeepEqual(t, Options{Color: true}, NewOptions(file, true, false))
+	assert.DeepEqual(t, Options{}, NewOptions(file, true, true))
+
+	setenv(t, "COLUMNS", "120")
+	assert.DeepEqual(t, Options{Width: 120}, NewOptions(file, false, false))
+	assert.DeepEqual(t, Options{Width: 120}, NewOptions(nil, false, false))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Route Ready", truncate("Route Ready", 11))
+	assert.Equal(t, "Route Re…", truncate("Route Ready", 9))
+	assert.Equal(t, "└─ Ro…", truncate("└─ Route Ready", 6))
+	assert.Equal(t, 6, width(strings.Repeat("█", 6)))
+}
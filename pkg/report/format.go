@@ -0,0 +1,126 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// FormatCSV writes the rows and the raw timestamps of a report as CSV files
+	FormatCSV = "csv"
+	// FormatJSON writes the result of a report as JSON file labeled with its schema
+	FormatJSON = "json"
+	// FormatHTML writes the rows of a report as chart, and as heatmap and report if they are rows of phases
+	FormatHTML = "html"
+	// FormatYAML writes the result of a report as YAML file labeled with its schema
+	FormatYAML = "yaml"
+	// FormatJUnit writes a JUnit XML file with a test case per row of a report
+	FormatJUnit = "junit"
+	// FormatNDJSON writes a JSON object per row of a report, which can be loaded by
+	// `bq load --source_format=NEWLINE_DELIMITED_JSON` or ClickHouse `FORMAT JSONEachRow`
+	FormatNDJSON = "ndjson"
+	// FormatParquet writes the raw timestamps as Parquet file instead of CSV, only 'service measure' supports it
+	FormatParquet = "parquet"
+
+	// FlagName is the name of the flag every command writing files takes the formats from
+	FlagName = "output-format"
+)
+
+var (
+	// Formats are the formats every report can be written in
+	Formats = []string{FormatCSV, FormatJSON, FormatHTML, FormatYAML, FormatJUnit, FormatNDJSON}
+	// DefaultFormats are written if no format is given
+	DefaultFormats = []string{FormatCSV, FormatJSON, FormatHTML}
+)
+
+// ParseFormats parses formats like "csv,json" given as separate values, comma separated or both. The formats must
+// be one of Formats or of the extra formats supported by the command, duplicates are dropped.
+func ParseFormats(values []string, extra ...string) ([]string, error) {
+	allowed := append(append([]string{}, Formats...), extra...)
+	formats := []string{}
+	for _, value := range values {
+		for _, f := range strings.Split(value, ",") {
+			f = strings.TrimSpace(strings.ToLower(f))
+			if f == "" || contains(formats, f) {
+				continue
+			}
+			if !contains(allowed, f) {
+				return nil, fmt.Errorf("unsupported output format %q, expected any of %s", f, strings.Join(allowed, ","))
+			}
+			formats = append(formats, f)
+		}
+	}
+	return formats, nil
+}
+
+// Enabled returns true if the format is one of the formats, no formats are the DefaultFormats
+func Enabled(formats []string, format string) bool {
+	if len(formats) == 0 {
+		formats = DefaultFormats
+	}
+	return contains(formats, format)
+}
+
+// AddFlag adds the --output-format flag with the DefaultFormats to the flags of a command, the formats are
+// validated when the flag is set. Commands supporting formats other than Formats, like parquet, pass them as extra
+// formats.
+func AddFlag(flags *pflag.FlagSet, formats *[]string, extra ...string) {
+	*formats = append([]string{}, DefaultFormats...)
+	value := &formatsValue{formats: formats, extra: extra}
+	flags.Var(value, FlagName, fmt.Sprintf("Comma separated formats the results are written in, any of %s",
+		strings.Join(append(append([]string{}, Formats...), extra...), ",")))
+}
+
+// formatsValue is the value of the --output-format flag. Like string slice flags the first value replaces the
+// defaults and further ones are appended.
+type formatsValue struct {
+	formats *[]string
+	extra   []string
+	changed bool
+}
+
+func (v *formatsValue) Set(value string) error {
+	formats, err := ParseFormats([]string{value}, v.extra...)
+	if err != nil {
+		return err
+	}
+	if v.changed {
+		formats, _ = ParseFormats(append(*v.formats, formats...), v.extra...)
+	}
+	*v.formats = formats
+	v.changed = true
+	return nil
+}
+
+func (v *formatsValue) Type() string {
+	return "strings"
+}
+
+func (v *formatsValue) String() string {
+	return "[" + strings.Join(*v.formats, ",") + "]"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats([]string{"CSV, json", "csv", "", "junit"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{FormatCSV, FormatJSON, FormatJUnit}, formats)
+
+	_, err = ParseFormats([]string{"parquet"})
+	assert.ErrorContains(t, err, `unsupported output format "parquet", expected any of csv,json,html,yaml,junit,ndjson`)
+	formats, err = ParseFormats([]string{"parquet"}, FormatParquet)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{FormatParquet}, formats)
+}
+
+func TestEnabled(t *testing.T) {
+	assert.Check(t, Enabled(nil, FormatHTML))
+	assert.Check(t, !Enabled(nil, FormatYAML))
+	assert.Check(t, Enabled([]string{FormatYAML}, FormatYAML))
+	assert.Check(t, !Enabled([]string{FormatYAML}, FormatCSV))
+}
+
+func TestAddFlag(t *testing.T) {
+	var formats []string
+	flags := pflag.NewFlagSet("measure", pflag.ContinueOnError)
+	AddFlag(flags, &formats, FormatParquet)
+	assert.DeepEqual(t, DefaultFormats, formats)
+	assert.Equal(t, "[csv,json,html]", flags.Lookup(FlagName).DefValue)
+
+	// the first value replaces the defaults, further ones are appended
+	assert.NilError(t, flags.Parse([]string{"--output-format", "yaml,parquet", "--output-format", "yaml,junit"}))
+	assert.DeepEqual(t, []string{FormatYAML, FormatParquet, FormatJUnit}, formats)
+
+	err := flags.Parse([]string{"--output-format", "xml"})
+	assert.ErrorContains(t, err, `unsupported output format "xml"`)
+}
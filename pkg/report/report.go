@@ -0,0 +1,179 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report writes the results of the kperf commands to files in the formats selected with --output-format,
+// so that every command produces the same artifacts.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"knative.dev/kperf/pkg"
+	"knative.dev/kperf/pkg/command/utils"
+)
+
+// SchemaKey is the key of the schema of the result in the JSON and YAML files of the reports
+const SchemaKey = "schema"
+
+// Schema returns the schema the JSON file of the report with the name is labeled with, so that the results of the
+// kperf commands can be told apart by dashboards and scripts
+func Schema(name string) string {
+	return fmt.Sprintf("kperf.knative.dev/%s/v1", name)
+}
+
+// Report is the result of a benchmark written to the files every kperf measurement is written to
+type Report struct {
+	// Name is the name of the files without the timestamp and the extension, e.g. broker_creation_time
+	Name string
+	// Rows are the rows of the CSV file with a header, the charts are drawn from them
+	Rows [][]string
+	// RawRows are the rows of the CSV file of the raw timestamps with a header, no file is written if it is empty
+	RawRows [][]string
+	// Result is written to the JSON and YAML files labeled with the schema of the report
+	Result interface{}
+	// Phases marks rows keyed by the name and namespace in the first two columns with the durations of the phases
+	// in the other columns, they are rendered as heatmap and as report with histograms and CDF curves as well
+	Phases bool
+	// Formats are the formats the report is written in, no formats are the DefaultFormats
+	Formats []string
+	// JUnit are the test suites of the JUnit file, by default a test case per row is written
+	JUnit *utils.JUnitTestSuites
+}
+
+// Marshal returns the JSON object of the result with the schema of the report named by name added
+func Marshal(name string, result interface{}) ([]byte, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("failed to add the schema to the result, it is no JSON object: %s", err)
+	}
+	schema, err := json.Marshal(Schema(name))
+	if err != nil {
+		return nil, err
+	}
+	object[SchemaKey] = schema
+	return json.Marshal(object)
+}
+
+// Write writes the report in its formats to the files named by the timestamp, or the name of the output options, and
+// the name of the report in the output location, and the paths of the files written to out. A file which can't be
+// written, e.g. since it exists and the output options don't overwrite it, is skipped and its error returned with the
+// ones of the other skipped files.
+func Write(out io.Writer, outputLocation, timestamp string, output pkg.OutputOptions, report Report) error {
+	path := func(suffix string) string {
+		return filepath.Join(outputLocation, utils.OutputFileName(output, timestamp, suffix))
+	}
+	enabled := func(format string) bool {
+		return Enabled(report.Formats, format)
+	}
+	var errs []error
+	// saved writes the message with the path of the file if it was written, and records the error otherwise
+	saved := func(err error, message, path string) {
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		fmt.Fprintf(out, "%s %s\n", message, path)
+	}
+
+	if enabled(FormatCSV) {
+		if len(report.RawRows) > 0 {
+			rawPath := path("raw_" + report.Name + ".csv")
+			saved(utils.GenerateCSVFile(rawPath, report.RawRows, output.Overwrite), "Raw Timestamp saved in CSV file", rawPath)
+		}
+
+		csvPath := path(report.Name + ".csv")
+		saved(utils.GenerateCSVFile(csvPath, report.Rows, output.Overwrite), "Measurement saved in CSV file", csvPath)
+	}
+
+	if enabled(FormatJSON) || enabled(FormatYAML) {
+		jsonData, err := Marshal(report.Name, report.Result)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to generate json data: %s", err))
+		} else {
+			if enabled(FormatJSON) {
+				jsonPath := path(report.Name + ".json")
+				saved(utils.GenerateJSONFile(jsonData, jsonPath, output.Overwrite), "Measurement saved in JSON file", jsonPath)
+			}
+			if enabled(FormatYAML) {
+				yamlPath := path(report.Name + ".yaml")
+				saved(utils.GenerateYAMLFile(jsonData, yamlPath, output.Overwrite), "Measurement saved in YAML file", yamlPath)
+			}
+		}
+	}
+
+	if enabled(FormatHTML) {
+		htmlPath := path(report.Name + ".html")
+		saved(utils.GenerateRowsHTMLFile(report.Rows, htmlPath, output.Overwrite), "Visualized measurement saved in HTML file", htmlPath)
+		if report.Phases {
+			heatmapPath := path(report.Name + "_heatmap.html")
+			saved(utils.GenerateRowsHeatmapHTMLFile(report.Rows, heatmapPath, output.Overwrite), "Heatmap of the measurement saved in HTML file", heatmapPath)
+
+			reportPath := path(report.Name + "_report.html")
+			saved(utils.GenerateReportHTMLFile(report.Rows, report.RawRows, nil, reportPath, output.Overwrite), "Report of the measurement saved in HTML file", reportPath)
+		}
+	}
+
+	if enabled(FormatNDJSON) {
+		ndjsonPath := path(report.Name + ".ndjson")
+		saved(utils.GenerateRowsNDJSONFile(ndjsonPath, report.Rows, output.Overwrite), "Measurement saved in NDJSON file", ndjsonPath)
+	}
+
+	if enabled(FormatJUnit) {
+		suites := report.JUnit
+		if suites == nil {
+			rowSuites := junitRows(report.Name, report.Rows, report.Phases)
+			suites = &rowSuites
+		}
+		junitPath := path(report.Name + "_junit.xml")
+		saved(utils.GenerateJUnitFile(junitPath, *suites, output.Overwrite), "Measurement saved in JUnit file", junitPath)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// junitRows returns a test suite with a test case named by the first column of every row. The rows of phases are
+// classed by the namespace in their second column, and a row with a value in a column named error fails.
+func junitRows(name string, rows [][]string, phases bool) utils.JUnitTestSuites {
+	suite := utils.JUnitTestSuite{Name: name}
+	if len(rows) > 0 {
+		errorColumn := -1
+		for i, column := range rows[0] {
+			if column == "error" {
+				errorColumn = i
+			}
+		}
+		for _, row := range rows[1:] {
+			if len(row) == 0 {
+				continue
+			}
+			tc := utils.JUnitTestCase{ClassName: name, Name: row[0]}
+			if phases && len(row) > 1 {
+				tc.ClassName = row[1]
+			}
+			if errorColumn >= 0 && errorColumn < len(row) && row[errorColumn] != "" {
+				tc.Failure = &utils.JUnitFailure{Message: "error", Text: row[errorColumn]}
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+	}
+	return utils.JUnitTestSuites{Suites: []utils.JUnitTestSuite{suite}}
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"knative.dev/kperf/pkg"
+)
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal("eventing_latency", struct {
+		Sent int `json:"sent"`
+	}{Sent: 3})
+	assert.NilError(t, err)
+	assert.Equal(t, `{"schema":"kperf.knative.dev/eventing_latency/v1","sent":3}`, string(data))
+
+	_, err = Marshal("eventing_latency", []int{1})
+	assert.ErrorContains(t, err, "it is no JSON object")
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("rows without phases", func(t *testing.T) {
+		dir := t.TempDir()
+		out := &bytes.Buffer{}
+		assert.NilError(t, Write(out, dir, "20220110120000", pkg.OutputOptions{}, Report{
+			Name:   "eventing_latency",
+			Rows:   [][]string{{"event_id", "latency"}, {"1", "0.100000"}},
+			Result: map[string]int{"sent": 1},
+		}))
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{
+			filepath.Join(dir, "20220110120000_eventing_latency.csv"),
+			filepath.Join(dir, "20220110120000_eventing_latency.html"),
+			filepath.Join(dir, "20220110120000_eventing_latency.json"),
+		}, files)
+		assert.Assert(t, !strings.Contains(out.String(), "failed"), out.String())
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "20220110120000_eventing_latency.json"))
+		assert.NilError(t, err)
+		result := map[string]interface{}{}
+		assert.NilError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, "kperf.knative.dev/eventing_latency/v1", result[SchemaKey])
+	})
+
+	t.Run("rows of phases with raw timestamps", func(t *testing.T) {
+		dir := t.TempDir()
+		out := &bytes.Buffer{}
+		assert.NilError(t, Write(out, dir, "20220110120000", pkg.OutputOptions{}, Report{
+			Name:    "broker_creation_time",
+			Rows:    [][]string{{"trigger_name", "trigger_namespace", "trigger_ready"}, {"t-1", "ns-1", "2"}},
+			RawRows: [][]string{{"trigger_name", "trigger_namespace", "trigger_ready"}, {"t-1", "ns-1", "2022-01-10 12:00:02 +0000 UTC"}},
+			Result:  map[string]int{"ready": 1},
+			Phases:  true,
+		}))
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		assert.NilError(t, err)
+		assert.Equal(t, 6, len(files))
+		assert.Assert(t, strings.Contains(out.String(), "Raw Timestamp saved in CSV file "+filepath.Join(dir, "20220110120000_raw_broker_creation_time.csv")))
+		assert.Assert(t, strings.Contains(out.String(), "Report of the measurement saved in HTML file "+filepath.Join(dir, "20220110120000_broker_creation_time_report.html")))
+		assert.Assert(t, !strings.Contains(out.String(), "failed"), out.String())
+	})
+
+	t.Run("all formats", func(t *testing.T) {
+		dir := t.TempDir()
+		out := &bytes.Buffer{}
+		assert.NilError(t, Write(out, dir, "20220110120000", pkg.OutputOptions{}, Report{
+			Name:    "webhook_latency",
+			Rows:    [][]string{{"operation", "p99", "error"}, {"create", "0.250000", ""}, {"update", "0.100000", "denied"}},
+			Result:  map[string]int{"qps": 10},
+			Formats: []string{FormatYAML, FormatNDJSON, FormatJUnit},
+		}))
+		files, err := filepath.Glob(filepath.Join(dir, "*"))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{
+			filepath.Join(dir, "20220110120000_webhook_latency.ndjson"),
+			filepath.Join(dir, "20220110120000_webhook_latency.yaml"),
+			filepath.Join(dir, "20220110120000_webhook_latency_junit.xml"),
+		}, files)
+		assert.Assert(t, !strings.Contains(out.String(), "failed"), out.String())
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "20220110120000_webhook_latency.yaml"))
+		assert.NilError(t, err)
+		assert.Equal(t, "qps: 10\nschema: kperf.knative.dev/webhook_latency/v1\n", string(data))
+		data, err = ioutil.ReadFile(filepath.Join(dir, "20220110120000_webhook_latency.ndjson"))
+		assert.NilError(t, err)
+		assert.Equal(t, `{"error":"","operation":"create","p99":0.25}
+{"error":"denied","operation":"update","p99":0.1}
+`, string(data))
+		data, err = ioutil.ReadFile(filepath.Join(dir, "20220110120000_webhook_latency_junit.xml"))
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(data), `<testsuite name="webhook_latency" tests="2" failures="1"`), string(data))
+		assert.Assert(t, strings.Contains(string(data), `<failure message="error">denied</failure>`), string(data))
+	})
+
+	t.Run("existing files", func(t *testing.T) {
+		dir := t.TempDir()
+		report := Report{Name: "ksvc_scaling_time", Rows: [][]string{{"svc_name", "latency"}, {"ksvc-1", "1.000000"}}, Formats: []string{FormatCSV}}
+		output := pkg.OutputOptions{Name: "nightly"}
+		assert.NilError(t, Write(&bytes.Buffer{}, dir, "20220110120000", output, report))
+
+		out := &bytes.Buffer{}
+		err := Write(out, dir, "20220110120000", output, report)
+		assert.ErrorContains(t, err, filepath.Join(dir, "nightly_ksvc_scaling_time.csv")+" already exists, replace it with --overwrite")
+		assert.Assert(t, !strings.Contains(out.String(), "saved"), out.String())
+
+		output.Overwrite = true
+		out.Reset()
+		assert.NilError(t, Write(out, dir, "20220110120000", output, report))
+		assert.Equal(t, "Measurement saved in CSV file "+filepath.Join(dir, "nightly_ksvc_scaling_time.csv")+"\n", out.String())
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RunIDLabel holds the ID of the kperf run which generated a resource, so that `kperf service clean --run-id`
+// removes exactly the resources of a run
+const RunIDLabel = "kperf.knative.dev/run-id"
+
+// NewRunID returns a new ID of a run started at now, the start time with a random suffix like 20210117104747-3f9a
+func NewRunID(now time.Time) string {
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	return now.UTC().Format("20060102150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// ValidateRunID checks that the run ID can be used as label value and as name of the output subdirectory of the run
+func ValidateRunID(runID string) error {
+	if errs := validation.IsValidLabelValue(runID); len(errs) > 0 {
+		return fmt.Errorf("invalid run ID %q: %s", runID, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// RunSelector adds the requirement of the run ID label to the label selector, the selector is returned unchanged
+// without a run ID
+func RunSelector(selector, runID string) string {
+	if runID == "" {
+		return selector
+	}
+	if selector != "" {
+		selector += ","
+	}
+	return selector + RunIDLabel + "=" + runID
+}
+
+// GeneratedLabels returns the labels to stamp a resource generated at now by the run which expires after ttl, the
+// run ID label is only set with a run ID
+func GeneratedLabels(runID string, ttl time.Duration, now time.Time) map[string]string {
+	labels := ExpiryLabels(ttl, now)
+	if runID == "" {
+		return labels
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[RunIDLabel] = runID
+	return labels
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNewRunID(t *testing.T) {
+	now := time.Unix(1640995200, 0)
+	id := NewRunID(now)
+	assert.Check(t, regexp.MustCompile(`^20220101000000-[0-9a-f]{4}$`).MatchString(id), "unexpected run ID %s", id)
+}
+
+func TestGeneratedLabels(t *testing.T) {
+	now := time.Unix(1640995200, 0)
+	assert.Check(t, GeneratedLabels("", 0, now) == nil)
+	assert.DeepEqual(t, map[string]string{RunIDLabel: "run-1"}, GeneratedLabels("run-1", 0, now))
+	assert.DeepEqual(t, map[string]string{RunIDLabel: "run-1", ExpiresAtLabel: "1640998800"}, GeneratedLabels("run-1", time.Hour, now))
+}
+
+func TestValidateRunID(t *testing.T) {
+	assert.NilError(t, ValidateRunID(""))
+	assert.NilError(t, ValidateRunID("20210117104747-3f9a"))
+	assert.ErrorContains(t, ValidateRunID("../demo"), "invalid run ID \"../demo\"")
+	assert.ErrorContains(t, ValidateRunID("a/b"), "invalid run ID")
+}
+
+func TestRunSelector(t *testing.T) {
+	assert.Equal(t, "app=demo", RunSelector("app=demo", ""))
+	assert.Equal(t, RunIDLabel+"=demo", RunSelector("", "demo"))
+	assert.Equal(t, "app=demo,"+RunIDLabel+"=demo", RunSelector("app=demo", "demo"))
+}
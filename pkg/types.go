@@ -16,6 +16,8 @@ package pkg
 import (
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	networkingv1alpha1 "knative.dev/networking/pkg/client/clientset/versioned/typed/networking/v1alpha1"
@@ -24,29 +26,90 @@ import (
 )
 
 type PerfParams struct {
-	KubeCfgPath          string
+	KubeCfgPath string
+	// Context is the kubeconfig context to use, empty uses the current context
+	Context   string
+	APIServer string
+	ProxyURL  string
+	// QPS and Burst limit the API server requests on the client side, 0 keeps the client-go defaults
+	QPS      float32
+	Burst    int
+	ReadOnly bool
+	// AuthProvider replaces the credentials of the kubeconfig by the token helper of a managed cloud or by the
+	// service account of the pod, AuthCluster and AuthRegion name the EKS cluster
+	AuthProvider         string
+	AuthCluster          string
+	AuthRegion           string
+	Audit                *AuditLog
 	ClientConfig         clientcmd.ClientConfig
 	ClientSet            kubernetes.Interface
 	NewAutoscalingClient func() (autoscalingv1alpha1.AutoscalingV1alpha1Interface, error)
 	NewServingClient     func() (servingv1client.ServingV1Interface, error)
 	NewNetworkingClient  func() (networkingv1alpha1.NetworkingV1alpha1Interface, error)
+	NewDynamicClient     func() (dynamic.Interface, error)
+	// Clock is the time source of the measurement and generation, nil uses the real clock
+	Clock clock.Clock
+	// Output names the result files and tells whether existing ones are replaced
+	Output OutputOptions
+}
+
+// OutputOptions are the options of the result files of a run given by --output-name and --overwrite
+type OutputOptions struct {
+	// Name replaces the timestamp the names of the result files start with, empty keeps the timestamp
+	Name string
+	// Overwrite allows to replace existing result files
+	Overwrite bool
 }
 
 type GenerateArgs struct {
 	Number      int
-	Interval    int
+	Interval    time.Duration
 	Batch       int
 	Concurrency int
 	MinScale    int
 	MaxScale    int
 
+	Target            int
+	TargetUtilization int
+	ScaleDownDelay    time.Duration
+	Window            time.Duration
+	Metric            string
+	Class             string
+
 	NamespacePrefix string
 	NamespaceRange  string
 	Namespace       string
 	SvcPrefix       string
+	// NamespaceWeights are the namespaces like ns-1:50 the services are spread across by weight
+	NamespaceWeights []string
+
+	CheckReady    bool
+	MeasureInline bool
+	Timeout       time.Duration
+
+	CleanupOnFailure bool
+	TTL              time.Duration
+	RunID            string
+	CreateNamespaces bool
 
-	CheckReady bool
-	Timeout    time.Duration
+	Template  string
+	Revisions int
+
+	Output        string
+	OutputFormats []string
+	ControlPlane  ControlPlaneProfileArgs
+
+	// Contexts are the kubeconfig contexts of the clusters the services are generated in one after the other
+	Contexts []string
+}
+
+// ControlPlaneProfileArgs configures the sampling of the Knative Serving control plane pods during a run
+type ControlPlaneProfileArgs struct {
+	Enabled     bool
+	Namespace   string
+	Interval    time.Duration
+	Metrics     []string
+	MetricsPort int
 }
 
 type CleanArgs struct {
@@ -54,7 +117,29 @@ type CleanArgs struct {
 	NamespaceRange  string
 	Namespace       string
 	SvcPrefix       string
+	Selector        string
+	SvcRegex        string
+	RunID           string
+	OlderThan       time.Duration
 	Concurrency     int
+	// Report writes the deleted services and namespaces with the durations of their Delete calls to report files
+	Report        bool
+	Output        string
+	OutputFormats []string
+}
+
+type CleanExpiredArgs struct {
+	Concurrency int
+	DryRun      bool
+}
+
+type ExporterInstallArgs struct {
+	Namespace   string
+	Image       string
+	MetricsPort int
+	Selector    string
+	Concurrency int
+	DryRun      bool
 }
 
 type MeasureArgs struct {
@@ -63,9 +148,73 @@ type MeasureArgs struct {
 	SvcPrefix       string
 	NamespaceRange  string
 	NamespacePrefix string
+	Selector        string
+	SvcRegex        string
 	Concurrency     int
 	Verbose         bool
 	Output          string
+	RunID           string
+
+	OutputFormats   []string
+	BulkFormat      string
+	ClickHouseURL   string
+	ClickHouseTable string
+	BigQueryTable   string
+	InfluxURL       string
+	InfluxBucket    string
+	InfluxOrg       string
+
+	MaxAvgReady time.Duration
+	MaxP95Ready time.Duration
+	MaxPhaseAvg map[string]string
+
+	Checkpoint         string
+	CheckpointInterval time.Duration
+	Resume             bool
+
+	GroupBy         string
+	DebugTimestamps bool
+	CollectEvents   bool
+	Retries         int
+	RetryBackoff    time.Duration
+	// ServiceTimeout is the deadline of the measurement of a single service, 0 disables it
+	ServiceTimeout time.Duration
+	// UserContainerName is the container whose start is measured, it is detected from the revision if empty
+	UserContainerName string
+	// ImagePulls breaks down the start of the containers into the image pull and the container start
+	ImagePulls bool
+
+	Kind   string
+	Stream bool
+
+	Sample         string
+	Limit          int
+	SampleStrategy string
+	SampleSeed     int64
+
+	CreatedAfter string
+	Since        time.Duration
+
+	Follow      bool
+	MetricsAddr string
+
+	ControlPlane ControlPlaneProfileArgs
+
+	// Contexts are the kubeconfig contexts of the clusters the services are measured in one after the other
+	Contexts []string
+
+	// DetectRaces reports the services whose data-path and status readiness diverged by more than RaceGap
+	DetectRaces bool
+	RaceGap     time.Duration
+
+	SummaryOnly   bool
+	SummaryFormat string
+	Color         bool
+	NoColor       bool
+
+	Precision string
+
+	ExtraMetricsCmd string
 }
 
 type ScaleArgs struct {
@@ -81,6 +230,251 @@ type ScaleArgs struct {
 	ResolvableDomain bool
 	Verbose          bool
 	Output           string
+	OutputFormats    []string
+	RunID            string
+	Replicas         int
+	ScaleMethod      string
+	ReadyTimeout     time.Duration
+	Autoscaler       AutoscalerScrapeArgs
+}
+
+// AutoscalerScrapeArgs configures the scraping of the decisions of the Knative Serving autoscaler during a run
+type AutoscalerScrapeArgs struct {
+	Enabled     bool
+	Namespace   string
+	Interval    time.Duration
+	MetricsPort int
+}
+
+type ColdStartArgs struct {
+	SvcRange           string
+	Namespace          string
+	SvcPrefix          string
+	NamespaceRange     string
+	NamespacePrefix    string
+	Concurrency        int
+	ResolvableDomain   bool
+	ScaleToZeroTimeout time.Duration
+	RequestTimeout     time.Duration
+	Verbose            bool
+	Output             string
+	OutputFormats      []string
+	RunID              string
+}
+
+type CompareArgs struct {
+	Base          string
+	Reference     string
+	Current       string
+	Alpha         float64
+	Test          string
+	NoiseProfile  string
+	Output        string
+	OutputFormats []string
+}
+
+type AttestArgs struct {
+	Run        string
+	Materials  []string
+	Parameters []string
+	BuilderID  string
+	Verify     string
+	Output     string
+}
+
+type CalibrateArgs struct {
+	Runs          int
+	Number        int
+	Concurrency   int
+	Namespace     string
+	SvcPrefix     string
+	Timeout       time.Duration
+	Output        string
+	OutputFormats []string
+}
+
+type InitArgs struct {
+	Output   string
+	Force    bool
+	Defaults bool
+}
+
+type FeatureMatrixArgs struct {
+	Features         []string
+	Number           int
+	Concurrency      int
+	Namespace        string
+	SvcPrefix        string
+	Timeout          time.Duration
+	Settle           time.Duration
+	ServingNamespace string
+	Output           string
+	OutputFormats    []string
+}
+
+type AutoscalerBenchmarkArgs struct {
+	Namespace        string
+	Service          string
+	Shape            string
+	MinQPS           int
+	MaxQPS           int
+	Period           time.Duration
+	Duration         time.Duration
+	Interval         time.Duration
+	Connections      int
+	RequestTimeout   time.Duration
+	ResolvableDomain bool
+	Verbose          bool
+	Output           string
+	OutputFormats    []string
+	RunID            string
+}
+
+type IngressBenchmarkArgs struct {
+	Number           int
+	Concurrency      int
+	Namespace        string
+	SvcPrefix        string
+	Requests         int
+	Connections      int
+	Timeout          time.Duration
+	RequestTimeout   time.Duration
+	ResolvableDomain bool
+	Keep             bool
+	Verbose          bool
+	Output           string
+	OutputFormats    []string
+	RunID            string
+	// Contexts are the kubeconfig contexts of the clusters the same services are benchmarked in one after the other
+	Contexts []string
+}
+
+type RevisionGCArgs struct {
+	Namespace   string
+	SvcPrefix   string
+	Number      int
+	Concurrency int
+	Revisions   int
+	// MaxRevisions are the max-non-active-revisions settings of the config-gc ConfigMap measured one after the other
+	MaxRevisions     []int
+	Timeout          time.Duration
+	Interval         time.Duration
+	ServingNamespace string
+	Keep             bool
+	Output           string
+	OutputFormats    []string
+	RunID            string
+}
+
+type WebhookMeasureArgs struct {
+	Namespace string
+	// Service is the existing Knative Service the update requests change
+	Service       string
+	SvcPrefix     string
+	Operations    []string
+	QPS           int
+	Duration      time.Duration
+	Interval      time.Duration
+	Concurrency   int
+	Timeout       time.Duration
+	Verbose       bool
+	Output        string
+	OutputFormats []string
+	RunID         string
+}
+
+type LoadArgs struct {
+	Namespace        string
+	SvcPrefix        string
+	NamespaceRange   string
+	NamespacePrefix  string
+	QPS              int
+	Duration         time.Duration
+	Connections      int
+	Payload          string
+	RequestTimeout   time.Duration
+	ResolvableDomain bool
+	Verbose          bool
+	Output           string
+	OutputFormats    []string
+	RunID            string
+	DisruptionWindow time.Duration
+	// ValidateMaxScale checks that the replicas of the services under load stay within their max-scale
+	ValidateMaxScale bool
+}
+
+type ActivatorOverheadArgs struct {
+	Namespace        string
+	Svc              string
+	Concurrency      []int
+	Duration         time.Duration
+	Settle           time.Duration
+	RequestTimeout   time.Duration
+	ResolvableDomain bool
+	Verbose          bool
+	Output           string
+	OutputFormats    []string
+	RunID            string
+}
+
+type UpdateMeasureArgs struct {
+	Namespace       string
+	SvcPrefix       string
+	NamespaceRange  string
+	NamespacePrefix string
+	Concurrency     int
+	Image           string
+	Env             []string
+	Timeout         time.Duration
+	Verbose         bool
+	Output          string
+	OutputFormats   []string
+	RunID           string
+}
+
+type DeleteMeasureArgs struct {
+	Namespace       string
+	SvcPrefix       string
+	NamespaceRange  string
+	NamespacePrefix string
+	Count           int
+	Concurrency     int
+	Timeout         time.Duration
+	PollInterval    time.Duration
+	Verbose         bool
+	Output          string
+	OutputFormats   []string
+	RunID           string
+}
+
+type TrafficMeasureArgs struct {
+	Namespace       string
+	SvcPrefix       string
+	NamespaceRange  string
+	NamespacePrefix string
+	Percentages     []int
+	Timeout         time.Duration
+	Verbose         bool
+	Output          string
+	OutputFormats   []string
+	RunID           string
+}
+
+type FunctionDeployArgs struct {
+	Count         int
+	Concurrency   int
+	Namespace     string
+	NamePrefix    string
+	Runtime       string
+	Template      string
+	Registry      string
+	Image         string
+	FuncPath      string
+	Timeout       time.Duration
+	Verbose       bool
+	Output        string
+	OutputFormats []string
+	RunID         string
 }
 
 type MeasureResult struct {
@@ -89,11 +483,828 @@ type MeasureResult struct {
 	Service      ServiceCount
 	KnativeInfo  KnativeInfo
 	SvcReadyTime []float64 `json:"-"`
+	// PhaseTimes holds the durations of every phase but the overall ready one of the ready services
+	PhaseTimes   map[string][]float64     `json:"-"`
+	Namespaces   []NamespaceMeasureResult `json:",omitempty"`
+	GroupBy      string                   `json:",omitempty"`
+	Groups       []GroupMeasureResult     `json:",omitempty"`
+	RunID        string                   `json:",omitempty"`
+	ControlPlane *ControlPlaneProfile     `json:",omitempty"`
+	// Custom holds the metrics of the extra metrics hooks, e.g. measurements of other systems during the run
+	Custom map[string]interface{} `json:"custom,omitempty"`
+	// FailedRollouts holds the services whose revision exceeded the progress deadline
+	FailedRollouts []FailedRollout `json:",omitempty"`
+	// Races holds the init-scale race report, nil if races weren't detected
+	Races *RaceReport `json:",omitempty"`
+	// Interrupted is the number of services left unmeasured when the measurement was interrupted, e.g. by Ctrl-C
+	Interrupted int `json:",omitempty"`
+	// Failures holds why every service which isn't ready couldn't be measured
+	Failures []ServiceFailure `json:",omitempty"`
+	// Containers holds the starts of every container of the ready services with multi-container revisions
+	Containers []ContainerStarted `json:",omitempty"`
+	// ImagePulls holds the image pulls of every container of the ready services, if they were broken down
+	ImagePulls []ImagePull `json:",omitempty"`
+	// GatewayRoutes holds the programming of the HTTPRoutes of every ready service, if the cluster uses the Gateway
+	// API ingress implementation
+	GatewayRoutes []GatewayRoute `json:",omitempty"`
+}
+
+// RaceReport quantifies the races between the data-path and the status readiness of the ready services. The gap of a
+// service is the time from the ready pod containers until the endpoints of its ServerlessService were populated,
+// negative if they were populated first. Services is the number of services with both timestamps, the counts are the
+// services whose gap exceeded Gap in either direction, and whose Revision was ready more than Gap before the endpoints
+// were populated. The percentiles and the maximum are the ones of the absolute gaps. Durations are in seconds.
+type RaceReport struct {
+	Gap                  float64
+	Services             int
+	PodReadyFirst        int
+	EndpointsFirst       int
+	ReadyBeforeEndpoints int
+	GapP50               float64 `json:"GapPercentile50"`
+	GapP95               float64 `json:"GapPercentile95"`
+	GapMax               float64
+	Races                []InitScaleRace `json:",omitempty"`
+}
+
+// InitScaleRace is a service whose data-path and status readiness diverged by more than the gap of the report.
+// PodToEndpoints and RevisionToEndpoints are the times from the ready pod containers and the ready Revision until the
+// endpoints of the ServerlessService were populated, in seconds.
+type InitScaleRace struct {
+	ServiceName         string   `json:"svcName"`
+	ServiceNamespace    string   `json:"svcNamespace"`
+	Kinds               []string `json:"kinds"`
+	PodToEndpoints      float64  `json:"podToEndpoints"`
+	RevisionToEndpoints float64  `json:"revisionToEndpoints"`
+}
+
+// NamespaceMeasureResult holds the number of services by state in a namespace and the statistics of the
+// overall ready duration of its ready services in seconds
+type NamespaceMeasureResult struct {
+	Namespace string
+	Service   ServiceCount
+	Average   float64
+	P50       float64 `json:"Percentile50"`
+	P95       float64 `json:"Percentile95"`
+	P99       float64 `json:"Percentile99"`
+}
+
+// GroupMeasureResult holds the number of services by state of a group of the services and the statistics of its
+// ready services in seconds
+type GroupMeasureResult struct {
+	Group   string
+	Service ServiceCount
+	Result  Result
+}
+
+// ClusterMeasureResult holds the number of services by state and the statistics of the ready services of a cluster
+// of a multi-cluster measurement, named by its kubeconfig context
+type ClusterMeasureResult struct {
+	Context     string
+	KnativeInfo KnativeInfo
+	Service     ServiceCount
+	Result      Result
+}
+
+// MultiClusterMeasureResult holds the measurement of every cluster, and the number of services by state and the
+// statistics of the ready services of all clusters merged
+type MultiClusterMeasureResult struct {
+	Service  ServiceCount
+	Result   Result
+	Clusters []ClusterMeasureResult
+}
+
+// WorkerMeasureResult holds the services measured by a worker, the average wall time per service and how much of
+// it was spent waiting for the API server, in seconds
+type WorkerMeasureResult struct {
+	Worker      int
+	Services    int
+	Average     float64
+	APITime     float64
+	ComputeTime float64
+	// Utilization is the percentage of the measurement the worker was busy
+	Utilization float64
+}
+
+type EventingGenerateArgs struct {
+	Number            int
+	Interval          int
+	Batch             int
+	Concurrency       int
+	TriggersPerBroker int
+
+	NamespacePrefix string
+	NamespaceRange  string
+	Namespace       string
+	BrokerPrefix    string
+	BrokerClass     string
+	Subscriber      string
+	SubscriberImage string
+
+	CleanupOnFailure bool
+	TTL              time.Duration
+	RunID            string
+}
+
+type EventingCleanArgs struct {
+	NamespacePrefix string
+	NamespaceRange  string
+	Namespace       string
+	BrokerPrefix    string
+	Concurrency     int
+}
+
+type EventingMeasureArgs struct {
+	NamespacePrefix string
+	NamespaceRange  string
+	Namespace       string
+	BrokerPrefix    string
+	Verbose         bool
+	Output          string
+	OutputFormats   []string
+	RunID           string
+}
+
+type EventingLatencyArgs struct {
+	Namespace     string
+	Image         string
+	BrokerClass   string
+	Channel       bool
+	Rate          int
+	Duration      time.Duration
+	Drain         time.Duration
+	Timeout       time.Duration
+	Keep          bool
+	Verbose       bool
+	Output        string
+	OutputFormats []string
+	RunID         string
+}
+
+// EventingLatencyResult holds the end-to-end delivery latencies of the events sent through a Broker or Channel.
+// Latencies are in seconds.
+type EventingLatencyResult struct {
+	Sent        int         `json:"sent"`
+	SendErrors  int         `json:"sendErrors"`
+	Received    int         `json:"received"`
+	Duplicates  int         `json:"duplicates"`
+	Dropped     int         `json:"dropped"`
+	Min         float64     `json:"min"`
+	Mean        float64     `json:"mean"`
+	P50         float64     `json:"percentile50"`
+	P90         float64     `json:"percentile90"`
+	P95         float64     `json:"percentile95"`
+	P99         float64     `json:"percentile99"`
+	Max         float64     `json:"max"`
+	KnativeInfo KnativeInfo `json:"knativeInfo"`
+}
+
+type EventingKafkaArgs struct {
+	Count            int
+	Namespace        string
+	Channel          bool
+	BootstrapServers []string
+	Rate             int
+	Duration         time.Duration
+	MessageSize      int
+	ProducerImage    string
+	Image            string
+	Drain            time.Duration
+	Timeout          time.Duration
+	Keep             bool
+	Verbose          bool
+	Output           string
+	OutputFormats    []string
+	RunID            string
+}
+
+// EventingKafkaResult holds the readiness of the KafkaSources or KafkaChannels and the delivery of the messages
+// produced to them. Throughput is the number of delivered messages per second from the first until the last delivery,
+// ConsumerLag the number of produced messages which weren't delivered at the end of the drain. The latencies are the
+// ones of the first delivery of every message since it was produced.
+type EventingKafkaResult struct {
+	KnativeInfo KnativeInfo              `json:"knativeInfo"`
+	Sources     []KafkaSourceMeasurement `json:"sources"`
+	Produced    int                      `json:"produced"`
+	Delivered   int                      `json:"delivered"`
+	ConsumerLag int                      `json:"consumerLag"`
+	Throughput  float64                  `json:"throughput"`
+	Latency     EventingLatencyResult    `json:"latency"`
+}
+
+// KafkaSourceMeasurement is a single KafkaSource or KafkaChannel. Ready is the time since its creation until it was
+// ready in seconds.
+type KafkaSourceMeasurement struct {
+	Name        string  `json:"name"`
+	Namespace   string  `json:"namespace"`
+	Ready       float64 `json:"ready"`
+	Produced    int     `json:"produced"`
+	Delivered   int     `json:"delivered"`
+	ConsumerLag int     `json:"consumerLag"`
+}
+
+type EventingMeasureResult struct {
+	Sums             EventingSums `json:"-"`
+	Result           EventingResult
+	Broker           ServiceCount
+	Trigger          ServiceCount
+	KnativeInfo      KnativeInfo
+	TriggerReadyTime []float64 `json:"-"`
+}
+
+type EventingSums struct {
+	BrokerReadySum               float64
+	TriggerReadySum              float64
+	TriggerSubscriberResolvedSum float64
+	TriggerSubscriptionReadySum  float64
+}
+
+type EventingResult struct {
+	AverageBrokerReady               float64 `json:"AverageBrokerReadyDuration"`
+	AverageTriggerReady              float64 `json:"AverageTriggerReadyDuration"`
+	AverageTriggerSubscriberResolved float64 `json:"AverageTriggerSubscriberResolvedDuration"`
+	AverageTriggerSubscriptionReady  float64 `json:"AverageTriggerSubscriptionReadyDuration"`
+	OverallTotal                     float64 `json:"Total"`
+	OverallAverage                   float64 `json:"Average"`
+	OverallMedian                    float64 `json:"Median"`
+	OverallMin                       float64 `json:"Min"`
+	OverallMax                       float64 `json:"Max"`
+	P50                              float64 `json:"Percentile50"`
+	P90                              float64 `json:"Percentile90"`
+	P95                              float64 `json:"Percentile95"`
+	P98                              float64 `json:"Percentile98"`
+	P99                              float64 `json:"Percentile99"`
+}
+
+type NamespaceGenerateArgs struct {
+	Number      int
+	Interval    int
+	Batch       int
+	Concurrency int
+
+	NamespacePrefix string
+	// Labels are the key=value labels of the namespaces the downstream controllers select them by
+	Labels       []string
+	ProbeWebhook bool
+	Timeout      time.Duration
+	RunID        string
+}
+
+type NamespaceCleanArgs struct {
+	NamespacePrefix string
+	RunID           string
+	Concurrency     int
+}
+
+type NamespaceMeasureArgs struct {
+	NamespacePrefix string
+	Probes          []string
+	Verbose         bool
+	Output          string
+	OutputFormats   []string
+	RunID           string
+}
+
+type DomainMappingGenerateArgs struct {
+	Number      int
+	Interval    int
+	Batch       int
+	Concurrency int
+
+	NamespacePrefix string
+	NamespaceRange  string
+	Namespace       string
+	DomainPrefix    string
+	Domain          string
+	SvcPrefix       string
+	DomainClaims    bool
+	TTL             time.Duration
+	RunID           string
+}
+
+type DomainMappingCleanArgs struct {
+	NamespacePrefix string
+	NamespaceRange  string
+	Namespace       string
+	DomainPrefix    string
+	Concurrency     int
+}
+
+type DomainMappingMeasureArgs struct {
+	NamespacePrefix string
+	NamespaceRange  string
+	Namespace       string
+	DomainPrefix    string
+	Verbose         bool
+	Output          string
+	OutputFormats   []string
+	RunID           string
+}
+
+type DomainMappingMeasureResult struct {
+	Sums          DomainMappingSums `json:"-"`
+	Result        DomainMappingResult
+	DomainMapping ServiceCount
+	KnativeInfo   KnativeInfo
+	ReadyTime     []float64 `json:"-"`
+}
+
+type DomainMappingSums struct {
+	DomainClaimedSum          float64
+	ReferenceResolvedSum      float64
+	CertificateProvisionedSum float64
+	IngressReadySum           float64
+	ReadySum                  float64
+}
+
+type DomainMappingResult struct {
+	AverageDomainClaimed          float64 `json:"AverageDomainClaimedDuration"`
+	AverageReferenceResolved      float64 `json:"AverageReferenceResolvedDuration"`
+	AverageCertificateProvisioned float64 `json:"AverageCertificateProvisionedDuration"`
+	AverageIngressReady           float64 `json:"AverageIngressReadyDuration"`
+	OverallTotal                  float64 `json:"Total"`
+	OverallAverage                float64 `json:"Average"`
+	OverallMedian                 float64 `json:"Median"`
+	OverallMin                    float64 `json:"Min"`
+	OverallMax                    float64 `json:"Max"`
+	P50                           float64 `json:"Percentile50"`
+	P90                           float64 `json:"Percentile90"`
+	P95                           float64 `json:"Percentile95"`
+	P98                           float64 `json:"Percentile98"`
+	P99                           float64 `json:"Percentile99"`
+}
+
+// MeasureRecord is the measurement of a single Knative Service flattened into one table row,
+// so that results of many runs can be bulk loaded into BigQuery, ClickHouse and the like.
+// Durations are in seconds.
+type MeasureRecord struct {
+	RunTimestamp      string `json:"run_timestamp" parquet:"name=run_timestamp, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ServiceName       string `json:"svc_name" parquet:"name=svc_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ServiceNamespace  string `json:"svc_namespace" parquet:"name=svc_namespace, type=BYTE_ARRAY, convertedtype=UTF8"`
+	NodeName          string `json:"node_name" parquet:"name=node_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ServingVersion    string `json:"serving_version" parquet:"name=serving_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	EventingVersion   string `json:"eventing_version" parquet:"name=eventing_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IngressController string `json:"ingress_controller" parquet:"name=ingress_controller, type=BYTE_ARRAY, convertedtype=UTF8"`
+	IngressVersion    string `json:"ingress_version" parquet:"name=ingress_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	ConfigurationReady             float64 `json:"configuration_ready" parquet:"name=configuration_ready, type=DOUBLE"`
+	RevisionReady                  float64 `json:"revision_ready" parquet:"name=revision_ready, type=DOUBLE"`
+	DeploymentCreated              float64 `json:"deployment_created" parquet:"name=deployment_created, type=DOUBLE"`
+	PodScheduled                   float64 `json:"pod_scheduled" parquet:"name=pod_scheduled, type=DOUBLE"`
+	ContainersReady                float64 `json:"containers_ready" parquet:"name=containers_ready, type=DOUBLE"`
+	QueueProxyStarted              float64 `json:"queue_proxy_started" parquet:"name=queue_proxy_started, type=DOUBLE"`
+	UserContainerStarted           float64 `json:"user_container_started" parquet:"name=user_container_started, type=DOUBLE"`
+	RouteReady                     float64 `json:"route_ready" parquet:"name=route_ready, type=DOUBLE"`
+	KpaActive                      float64 `json:"kpa_active" parquet:"name=kpa_active, type=DOUBLE"`
+	SksReady                       float64 `json:"sks_ready" parquet:"name=sks_ready, type=DOUBLE"`
+	SksActivatorEndpointsPopulated float64 `json:"sks_activator_endpoints_populated" parquet:"name=sks_activator_endpoints_populated, type=DOUBLE"`
+	SksEndpointsPopulated          float64 `json:"sks_endpoints_populated" parquet:"name=sks_endpoints_populated, type=DOUBLE"`
+	IngressReady                   float64 `json:"ingress_ready" parquet:"name=ingress_ready, type=DOUBLE"`
+	IngressConfigReady             float64 `json:"ingress_config_ready" parquet:"name=ingress_config_ready, type=DOUBLE"`
+	IngressLoadBalancerReady       float64 `json:"ingress_lb_ready" parquet:"name=ingress_lb_ready, type=DOUBLE"`
+	CertificateReady               float64 `json:"certificate_ready" parquet:"name=certificate_ready, type=DOUBLE"`
+	OverallReady                   float64 `json:"overall_ready" parquet:"name=overall_ready, type=DOUBLE"`
+}
+
+// MeasureRawRecord holds the raw timestamps of a single Knative Service measurement as
+// milliseconds since epoch, timestamps which are not available are nil
+// DebugTimestamp is a timestamp of a resource read while measuring a service, e.g. its creation time or the last
+// transition time of one of its conditions
+type DebugTimestamp struct {
+	ServiceName      string
+	ServiceNamespace string
+	Kind             string
+	Name             string
+	Field            string
+	Time             time.Time
+}
+
+// ServiceEvent is a Kubernetes Event of a resource created for a measured service, e.g. a failed image pull of one of
+// its pods. FirstTime and LastTime are when the event was first and last observed.
+type ServiceEvent struct {
+	ServiceName      string
+	ServiceNamespace string
+	Kind             string
+	Name             string
+	Type             string
+	Reason           string
+	Message          string
+	Count            int32
+	FirstTime        time.Time
+	LastTime         time.Time
+}
+
+type MeasureRawRecord struct {
+	ServiceName      string `parquet:"name=svc_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ServiceNamespace string `parquet:"name=svc_namespace, type=BYTE_ARRAY, convertedtype=UTF8"`
+
+	ServiceCreated                 *int64 `parquet:"name=svc_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ConfigurationReady             *int64 `parquet:"name=configuration_ready, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	RevisionCreated                *int64 `parquet:"name=revision_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	RevisionReady                  *int64 `parquet:"name=revision_ready, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	DeploymentCreated              *int64 `parquet:"name=deployment_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	PodCreated                     *int64 `parquet:"name=pod_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	PodScheduled                   *int64 `parquet:"name=pod_scheduled, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ContainersReady                *int64 `parquet:"name=containers_ready, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	QueueProxyStarted              *int64 `parquet:"name=queue_proxy_started, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	UserContainerStarted           *int64 `parquet:"name=user_container_started, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	RouteReady                     *int64 `parquet:"name=route_ready, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	KpaCreated                     *int64 `parquet:"name=kpa_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	KpaActive                      *int64 `parquet:"name=kpa_active, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	SksCreated                     *int64 `parquet:"name=sks_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	SksActivatorEndpointsPopulated *int64 `parquet:"name=sks_activator_endpoints_populated, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	SksEndpointsPopulated          *int64 `parquet:"name=sks_endpoints_populated, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	IngressCreated                 *int64 `parquet:"name=ingress_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	IngressConfigReady             *int64 `parquet:"name=ingress_config_ready, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	IngressLoadBalancerReady       *int64 `parquet:"name=ingress_lb_ready, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	CertificateCreated             *int64 `parquet:"name=certificate_created, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	CertificateReady               *int64 `parquet:"name=certificate_ready, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
 }
 
 type ScaleResult struct {
 	KnativeInfo KnativeInfo
 	Measurment  []ScaleFromZeroResult
+	// Autoscaler holds the decisions of the autoscaler for the scaled services, nil if they weren't scraped
+	Autoscaler *AutoscalerMetrics `json:",omitempty"`
+}
+
+type ColdStartResult struct {
+	KnativeInfo KnativeInfo
+	Measurement []ColdStartMeasurement `json:"measurement"`
+}
+
+// ColdStartMeasurement is the time to first byte of the first request to a Knative Service scaled to zero,
+// broken down into the phases of the Pod started for the request. Durations are in seconds.
+type ColdStartMeasurement struct {
+	ServiceName          string
+	ServiceNamespace     string
+	TimeToFirstByte      float64 `json:"timeToFirstByte"`
+	PodCreated           float64 `json:"podCreated"`
+	PodScheduled         float64 `json:"podScheduled"`
+	QueueProxyStarted    float64 `json:"queueProxyStarted"`
+	UserContainerStarted float64 `json:"userContainerStarted"`
+	ActivatorForwarding  float64 `json:"activatorForwarding"`
+}
+
+type UpdateResult struct {
+	KnativeInfo KnativeInfo
+	Measurement []UpdateMeasurement `json:"measurement"`
+}
+
+// UpdateMeasurement is the rollout of a template change of a single Knative Service. RevisionReady and
+// TrafficShifted are the time since the update until the new Revision is ready and receives 100% of the
+// traffic, OldRevisionScaledDown is the time since the traffic shift until all Pods of the old Revision are
+// gone. OldRevisionDrainStarted is the time since the update until the first Pod of the old Revision was seen
+// terminating, i.e. its queue-proxy draining the requests in flight. TrafficAtRisk is the window from then until all
+// Pods of the old Revision are gone, in which requests are served by draining Pods, DrainBeforeTrafficShifted is the
+// part of it in which the Route still sent new requests to the old Revision. The drain durations are 0 if no Pod of
+// the old Revision was seen terminating. Durations are in seconds.
+type UpdateMeasurement struct {
+	ServiceName               string
+	ServiceNamespace          string
+	OldRevision               string  `json:"oldRevision"`
+	NewRevision               string  `json:"newRevision"`
+	RevisionReady             float64 `json:"revisionReady"`
+	TrafficShifted            float64 `json:"trafficShifted"`
+	OldRevisionScaledDown     float64 `json:"oldRevisionScaledDown"`
+	OldRevisionDrainStarted   float64 `json:"oldRevisionDrainStarted"`
+	TrafficAtRisk             float64 `json:"trafficAtRisk"`
+	DrainBeforeTrafficShifted float64 `json:"drainBeforeTrafficShifted"`
+	Total                     float64 `json:"total"`
+}
+
+type DeleteResult struct {
+	KnativeInfo KnativeInfo
+	// PollInterval is the interval in seconds the resources were polled in, the resolution of the durations
+	PollInterval float64               `json:"pollInterval"`
+	Resources    []DeleteResourceStats `json:"resources"`
+	Measurement  []DeleteMeasurement   `json:"measurement"`
+}
+
+// DeleteMeasurement is the deletion of a single Knative Service. The durations are the time since the Delete call of
+// the Service until the Service and the resources of its Revisions are gone, i.e. their finalizers are cleared, the
+// ones of the Revisions until the last of the kind is gone. Existing holds the number of resources of every kind which
+// existed before the deletion, the durations of the kinds without any are 0. Total is the time until all of them are
+// gone. Durations are in seconds.
+type DeleteMeasurement struct {
+	ServiceName       string
+	ServiceNamespace  string
+	Revisions         []string       `json:"revisions"`
+	Existing          map[string]int `json:"existing"`
+	ServiceDeleted    float64        `json:"serviceDeleted"`
+	RevisionDeleted   float64        `json:"revisionDeleted"`
+	DeploymentDeleted float64        `json:"deploymentDeleted"`
+	SKSDeleted        float64        `json:"sksDeleted"`
+	KPADeleted        float64        `json:"kpaDeleted"`
+	IngressDeleted    float64        `json:"ingressDeleted"`
+	Total             float64        `json:"total"`
+}
+
+// DeleteResourceStats holds the average, the percentiles and the maximum of the deletion durations of a kind of
+// resource, Deleted is the number of resources of the kind which existed before the deletion
+type DeleteResourceStats struct {
+	Resource string  `json:"resource"`
+	Deleted  int     `json:"deleted"`
+	Average  float64 `json:"average"`
+	P50      float64 `json:"percentile50"`
+	P95      float64 `json:"percentile95"`
+	P99      float64 `json:"percentile99"`
+	Max      float64 `json:"max"`
+}
+
+type TrafficResult struct {
+	KnativeInfo KnativeInfo
+	Measurement []TrafficMeasurement `json:"measurement"`
+}
+
+// TrafficMeasurement is the Route reconciliation of a traffic split change of a single Knative Service. Observed is
+// the time since the change until the Service observed it, Converged until the Route is ready and routes the traffic
+// as specified. Durations are in seconds.
+type TrafficMeasurement struct {
+	ServiceName      string
+	ServiceNamespace string
+	Targets          int     `json:"targets"`
+	Observed         float64 `json:"observed"`
+	Converged        float64 `json:"converged"`
+}
+
+type FunctionResult struct {
+	KnativeInfo KnativeInfo
+	Measurement []FunctionMeasurement `json:"measurement"`
+}
+
+// FunctionMeasurement is the deployment of a single Knative Function. Build is the time the func CLI took to build
+// the image of the function from its template, 0 with a prebuilt image, Deploy the time until the Knative Service of
+// the function was created and Ready the time since then until the Service is ready. Total is the sum of the phases.
+// Durations are in seconds.
+type FunctionMeasurement struct {
+	ServiceName      string
+	ServiceNamespace string
+	Build            float64 `json:"build"`
+	Deploy           float64 `json:"deploy"`
+	Ready            float64 `json:"ready"`
+	Total            float64 `json:"total"`
+}
+
+// AutoscalerBenchmarkResult holds the replicas of a Knative Service sampled under a load shape. OverProvisioned and
+// UnderProvisioned are the areas between the ready and the ideal replicas in replica seconds. TimeToScale is the
+// average and TimeToScaleMax the longest time the ready replicas took to catch up with the ideal replicas after the
+// service became under-provisioned, ScaleUps the number of times it did. PodChurn is the number of pods started and
+// terminated during the load. Durations are in seconds.
+type AutoscalerBenchmarkResult struct {
+	KnativeInfo      KnativeInfo        `json:"knativeInfo"`
+	ServiceName      string             `json:"serviceName"`
+	ServiceNamespace string             `json:"serviceNamespace"`
+	Shape            string             `json:"shape"`
+	Target           float64            `json:"target"`
+	Requests         int                `json:"requests"`
+	Failed           int                `json:"failed"`
+	OverProvisioned  float64            `json:"overProvisioned"`
+	UnderProvisioned float64            `json:"underProvisioned"`
+	ScaleUps         int                `json:"scaleUps"`
+	TimeToScale      float64            `json:"timeToScale"`
+	TimeToScaleMax   float64            `json:"timeToScaleMax"`
+	PodsStarted      int                `json:"podsStarted"`
+	PodsTerminated   int                `json:"podsTerminated"`
+	PodChurn         int                `json:"podChurn"`
+	Samples          []AutoscalerSample `json:"samples"`
+}
+
+// AutoscalerSample is the state of the service at an offset since the start of the load in seconds. QPS is the rate
+// of the load shape, Concurrency the average number of requests in flight since the previous sample and Ideal the
+// replicas needed to serve it at the target concurrency per replica. Desired are the replicas the autoscaler asked
+// for and Actual the ready ones.
+type AutoscalerSample struct {
+	Offset      float64 `json:"offset"`
+	QPS         float64 `json:"qps"`
+	Concurrency float64 `json:"concurrency"`
+	Ideal       int     `json:"ideal"`
+	Desired     int     `json:"desired"`
+	Actual      int     `json:"actual"`
+}
+
+// InlineReadyResult holds the readiness of the services generated with --measure-inline measured in two ways. Watched
+// is the wall-clock duration from the Create call until a watch started before the creation reported the service
+// ready, Status the duration from the creation timestamp until the last transition of the Ready condition.
+// Difference is Status minus Watched, it exposes the inaccuracy of the status timestamps. Durations are in seconds.
+type InlineReadyResult struct {
+	Ready       int                      `json:"ready"`
+	NotReady    int                      `json:"notReady"`
+	Watched     InlineReadyStats         `json:"watched"`
+	Status      InlineReadyStats         `json:"status"`
+	Difference  InlineReadyStats         `json:"difference"`
+	Measurement []InlineReadyMeasurement `json:"measurement"`
+}
+
+// InlineReadyStats are the statistics of the durations of one view of the readiness
+type InlineReadyStats struct {
+	Average float64 `json:"average"`
+	P50     float64 `json:"percentile50"`
+	P95     float64 `json:"percentile95"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+// InlineReadyMeasurement is the readiness of a single generated service measured in both ways
+type InlineReadyMeasurement struct {
+	ServiceName      string  `json:"serviceName"`
+	ServiceNamespace string  `json:"serviceNamespace"`
+	Watched          float64 `json:"watched"`
+	Status           float64 `json:"status"`
+	Difference       float64 `json:"difference"`
+}
+
+// GenerateReadyResult holds the durations in seconds from the Create calls of the services generated with --wait
+// until they were seen ready
+type GenerateReadyResult struct {
+	Ready       int                        `json:"ready"`
+	Average     float64                    `json:"average"`
+	P50         float64                    `json:"percentile50"`
+	P95         float64                    `json:"percentile95"`
+	Max         float64                    `json:"max"`
+	Measurement []GenerateReadyMeasurement `json:"measurement"`
+}
+
+// GenerateReadyMeasurement is the duration of a single generated service until it was seen ready
+type GenerateReadyMeasurement struct {
+	ServiceName      string  `json:"serviceName"`
+	ServiceNamespace string  `json:"serviceNamespace"`
+	Ready            float64 `json:"ready"`
+}
+
+// CleanResult is the report of 'service clean'. Deleted and Failed are the numbers of services deleted and failed to
+// delete, the average, percentiles and maximum are the ones of the durations of the Delete calls of the deleted
+// services and Duration is the time of the whole clean, all in seconds.
+type CleanResult struct {
+	Deleted    int               `json:"deleted"`
+	Failed     int               `json:"failed"`
+	Duration   float64           `json:"duration"`
+	Average    float64           `json:"average"`
+	P50        float64           `json:"percentile50"`
+	P95        float64           `json:"percentile95"`
+	P99        float64           `json:"percentile99"`
+	Max        float64           `json:"max"`
+	Services   []CleanedResource `json:"services"`
+	Namespaces []CleanedResource `json:"namespaces,omitempty"`
+}
+
+// CleanedResource is a service or namespace deleted by 'service clean' with the duration of its Delete call in
+// seconds, Error tells why it couldn't be deleted
+type CleanedResource struct {
+	Name      string  `json:"name"`
+	Namespace string  `json:"namespace,omitempty"`
+	Duration  float64 `json:"duration"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// IngressBenchmarkResult holds the benchmark of the ingress of every cluster with the same set of Knative Services
+type IngressBenchmarkResult struct {
+	Clusters []IngressClusterResult `json:"clusters"`
+}
+
+// IngressClusterResult is the benchmark of the ingress of a cluster, Context is empty for the current context.
+// Programming is the time from the creation of the KIngress of a service until the ingress reported its load balancer
+// ready, FirstRequest the time from the creation of the service until the first request through the ingress
+// succeeded. DataPath holds the latencies of the requests sent through the ingress once all services were reachable.
+// Durations are in seconds.
+type IngressClusterResult struct {
+	Context      string               `json:"context,omitempty"`
+	KnativeInfo  KnativeInfo          `json:"knativeInfo"`
+	Services     int                  `json:"services"`
+	Failed       int                  `json:"failed"`
+	Programming  IngressLatency       `json:"programming"`
+	FirstRequest IngressLatency       `json:"firstRequest"`
+	DataPath     IngressDataPath      `json:"dataPath"`
+	Measurement  []IngressMeasurement `json:"measurement"`
+}
+
+// IngressLatency are the statistics of the durations of the services in seconds
+type IngressLatency struct {
+	P50 float64 `json:"percentile50"`
+	P95 float64 `json:"percentile95"`
+	P99 float64 `json:"percentile99"`
+	Max float64 `json:"max"`
+}
+
+// IngressDataPath holds the requests sent through the ingress and the latencies of the successful ones in seconds
+type IngressDataPath struct {
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	P50      float64 `json:"percentile50"`
+	P95      float64 `json:"percentile95"`
+	P99      float64 `json:"percentile99"`
+}
+
+// IngressMeasurement is the programming of the ingress for a single service in seconds
+type IngressMeasurement struct {
+	ServiceName      string  `json:"serviceName"`
+	ServiceNamespace string  `json:"serviceNamespace"`
+	Programming      float64 `json:"programming"`
+	FirstRequest     float64 `json:"firstRequest"`
+}
+
+// ActivatorOverheadResult holds the latencies of the requests to a Knative Service through the activator and directly
+// to its pods at each concurrency level. The activator path is forced with a target burst capacity of -1, the direct
+// path with 0, while a min-scale of 1 keeps the pods warm on both paths.
+type ActivatorOverheadResult struct {
+	KnativeInfo      KnativeInfo      `json:"knativeInfo"`
+	ServiceName      string           `json:"serviceName"`
+	ServiceNamespace string           `json:"serviceNamespace"`
+	Levels           []ActivatorLevel `json:"levels"`
+}
+
+// ActivatorLevel compares both paths at a concurrency level. The overheads are the latencies of the activator path
+// minus the ones of the direct path in seconds, ThroughputLoss is the share of the direct throughput the activator
+// path didn't reach.
+type ActivatorLevel struct {
+	Concurrency    int           `json:"concurrency"`
+	Activator      ActivatorPath `json:"activator"`
+	Direct         ActivatorPath `json:"direct"`
+	MeanOverhead   float64       `json:"meanOverhead"`
+	P50Overhead    float64       `json:"percentile50Overhead"`
+	P95Overhead    float64       `json:"percentile95Overhead"`
+	P99Overhead    float64       `json:"percentile99Overhead"`
+	ThroughputLoss float64       `json:"throughputLoss"`
+}
+
+// ActivatorPath holds the requests sent on one path at a concurrency level. Throughput is in requests per second,
+// the latencies of the successful requests are in seconds.
+type ActivatorPath struct {
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	Throughput float64 `json:"throughput"`
+	Mean       float64 `json:"mean"`
+	P50        float64 `json:"percentile50"`
+	P95        float64 `json:"percentile95"`
+	P99        float64 `json:"percentile99"`
+}
+
+type LoadResult struct {
+	KnativeInfo KnativeInfo
+	Measurement []ServiceLoadResult `json:"measurement"`
+}
+
+// ServiceLoadResult holds the request latencies of a single Knative Service under load together with the
+// scaling of its deployment and the disruptions of its pods during the load. Latencies are in seconds,
+// DisruptionErrors are the failed requests attributed to the disruptions.
+type ServiceLoadResult struct {
+	ServiceName         string
+	ServiceNamespace    string
+	Requests            int              `json:"requests"`
+	Errors              int              `json:"errors"`
+	Min                 float64          `json:"min"`
+	Mean                float64          `json:"mean"`
+	P50                 float64          `json:"percentile50"`
+	P90                 float64          `json:"percentile90"`
+	P95                 float64          `json:"percentile95"`
+	P99                 float64          `json:"percentile99"`
+	Max                 float64          `json:"max"`
+	ScaleEvents         []ScaleEvent     `json:"scaleEvents"`
+	ReplicaLatencies    []ReplicaLatency `json:"replicaLatencies"`
+	Disruptions         []Disruption     `json:"disruptions"`
+	DisruptionErrors    int              `json:"disruptionErrors"`
+	ErrorsPerDisruption float64          `json:"errorsPerDisruption"`
+	// MaxScale is the validation of the max-scale of the service, nil if it wasn't validated
+	MaxScale *MaxScaleValidation `json:"maxScale,omitempty"`
+}
+
+// MaxScaleValidation holds whether the replicas of a service under load stayed within its max-scale.
+// MaxReadyReplicas and MaxDesiredReplicas are the most ready and desired replicas of its deployment during the load,
+// CapReached is the time since the start of the load until the ready replicas reached the max-scale, -1 if they
+// didn't. The latencies are the P95 of the requests served below and at the cap, Penalty is their difference.
+// Durations are in seconds.
+type MaxScaleValidation struct {
+	MaxScale           int     `json:"maxScale"`
+	MaxReadyReplicas   int     `json:"maxReadyReplicas"`
+	MaxDesiredReplicas int     `json:"maxDesiredReplicas"`
+	Enforced           bool    `json:"enforced"`
+	CapReached         float64 `json:"capReached"`
+	BelowCapP95        float64 `json:"belowCapPercentile95"`
+	AtCapP95           float64 `json:"atCapPercentile95"`
+	AtCapErrors        int     `json:"atCapErrors"`
+	Penalty            float64 `json:"penalty"`
+}
+
+// ScaleEvent is a change of the ready replicas, Offset is the time since the start of the load in seconds
+type ScaleEvent struct {
+	Offset        float64 `json:"offset"`
+	ReadyReplicas int     `json:"readyReplicas"`
+}
+
+// Disruption is a pod of the service terminated during the load, Offset is the time since the start of the load in
+// seconds and Cause one of eviction, preemption or deletion. Errors are the failed requests attributed to it.
+type Disruption struct {
+	Offset float64 `json:"offset"`
+	Pod    string  `json:"pod"`
+	Cause  string  `json:"cause"`
+	Errors int     `json:"errors"`
+}
+
+// ReplicaLatency holds the latencies of the requests sent while the deployment had ReadyReplicas replicas
+type ReplicaLatency struct {
+	ReadyReplicas int     `json:"readyReplicas"`
+	Requests      int     `json:"requests"`
+	P50           float64 `json:"percentile50"`
+	P95           float64 `json:"percentile95"`
 }
 
 type ScaleFromZeroResult struct {
@@ -101,6 +1312,10 @@ type ScaleFromZeroResult struct {
 	ServiceNamespace  string
 	ServiceLatency    float64 `json:"serviceLatency"`
 	DeploymentLatency float64 `json:"deploymentLatency"`
+	// ReadyLatency is the time until the requested number of replicas is ready when scaling to N replicas
+	ReadyLatency float64 `json:"readyLatency,omitempty"`
+	// ReplicaReady holds the time until the first, second etc. replica was ready when scaling to N replicas
+	ReplicaReady []float64 `json:"replicaReady,omitempty"`
 }
 
 type Sums struct {
@@ -115,6 +1330,7 @@ type Sums struct {
 	IngressReadySum                   float64
 	IngressNetworkConfiguredSum       float64
 	IngressLoadBalancerReadySum       float64
+	CertificateReadySum               float64
 	PodScheduledSum                   float64
 	ContainersReadySum                float64
 	QueueProxyStartedSum              float64
@@ -122,11 +1338,331 @@ type Sums struct {
 	DeploymentCreatedSum              float64
 }
 
+type CompareResult struct {
+	Base    string
+	Current string
+	Test    string
+	Alpha   float64
+	Metrics []MetricComparison
+}
+
+// MetricComparison compares the samples of a single metric, e.g. revision_ready, between two runs.
+// A difference is only reported as a regression or an improvement if it is statistically significant.
+type MetricComparison struct {
+	Metric        string
+	BaseCount     int
+	CurrentCount  int
+	BaseMedian    float64
+	CurrentMedian float64
+	DeltaPercent  float64
+	PValue        float64
+	Significant   bool
+	// ThresholdPercent is the minimum relative change of the median taken from the noise profile
+	ThresholdPercent float64 `json:",omitempty"`
+	Verdict          string
+}
+
+type DiffArgs struct {
+	Old           string
+	New           string
+	Threshold     float64
+	Output        string
+	OutputFormats []string
+}
+
+type SLAArgs struct {
+	Input         string
+	Targets       map[string]string
+	Title         string
+	Output        string
+	OutputFormats []string
+}
+
+// SLAResult holds the objectives of an SLA document rendered from a measure result
+type SLAResult struct {
+	Title       string
+	Input       string
+	RunID       string `json:",omitempty"`
+	KnativeInfo KnativeInfo
+	Service     ServiceCount
+	Met         int
+	Missed      int
+	Objectives  []SLAObjective
+}
+
+// SLAObjective is a target of a percentile of the durations of a phase. Margin is the target minus the observed
+// duration in seconds, MarginPercent the margin relative to the target.
+type SLAObjective struct {
+	Phase         string
+	Percentile    string
+	Target        float64
+	Observed      float64
+	Margin        float64
+	MarginPercent float64
+	Met           bool
+}
+
+type DiffResult struct {
+	Old            string
+	New            string
+	Threshold      float64
+	OldKnativeInfo KnativeInfo
+	NewKnativeInfo KnativeInfo
+	Metrics        []MetricDiff
+}
+
+// MetricDiff is the change of a single value of the measure result, e.g. AverageRevisionDuration, between two runs
+type MetricDiff struct {
+	Metric       string
+	Old          float64
+	New          float64
+	Delta        float64
+	DeltaPercent float64
+	Verdict      string
+}
+
+// NoiseProfile is the run-to-run variance of every phase measured on a cluster by running
+// the same workload several times
+type NoiseProfile struct {
+	CreatedAt      string
+	Runs           int
+	ServicesPerRun int
+	KnativeInfo    KnativeInfo
+	Phases         map[string]PhaseNoise
+}
+
+// PhaseNoise describes the spread of the per run medians of a single phase, durations are in seconds
+type PhaseNoise struct {
+	Mean                   float64
+	StdDev                 float64
+	CoefficientOfVariation float64
+	// ThresholdPercent is the relative change of the median below which a difference is considered noise
+	ThresholdPercent float64
+}
+
+// FeatureMatrix is the performance of the same workload with feature flags of Knative Serving toggled one at a
+// time, compared to the baseline run with the configured flags
+type FeatureMatrix struct {
+	CreatedAt      string
+	ServicesPerRun int
+	KnativeInfo    KnativeInfo
+	// Baseline holds the feature flags of the cluster before the matrix was run
+	Baseline map[string]string
+	Runs     []FeatureRun
+}
+
+// FeatureRun is a run of the feature matrix, durations are in seconds
+type FeatureRun struct {
+	// Name is the toggled flag as name=value, or baseline
+	Name    string
+	Feature string `json:",omitempty"`
+	Value   string `json:",omitempty"`
+	Medians map[string]float64
+	P95     map[string]float64 `json:"Percentile95"`
+	// Impact is the relative change of the median of every phase to the baseline in percent
+	Impact map[string]float64 `json:",omitempty"`
+}
+
+// Attestation is an in-toto statement about the results of a benchmark run, its predicate is a SLSA provenance of
+// the run
+type Attestation struct {
+	Type          string               `json:"_type"`
+	Subject       []AttestationSubject `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     BenchmarkProvenance  `json:"predicate"`
+}
+
+// AttestationSubject is a file attested by its digests, keyed by the digest algorithm
+type AttestationSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// BenchmarkProvenance describes how the results of a benchmark run were produced: the inputs of the run, the
+// environment it ran in and the files it used
+type BenchmarkProvenance struct {
+	Builder    ProvenanceBuilder    `json:"builder"`
+	BuildType  string               `json:"buildType"`
+	Invocation ProvenanceInvocation `json:"invocation"`
+	Metadata   ProvenanceMetadata   `json:"metadata"`
+	Materials  []AttestationSubject `json:"materials,omitempty"`
+}
+
+type ProvenanceBuilder struct {
+	ID string `json:"id"`
+}
+
+type ProvenanceInvocation struct {
+	Parameters  map[string]string    `json:"parameters,omitempty"`
+	Environment BenchmarkEnvironment `json:"environment"`
+}
+
+// BenchmarkEnvironment is the snapshot of the environment of a benchmark run, the Knative versions are the ones
+// recorded in the results of the run
+type BenchmarkEnvironment struct {
+	KperfVersion      string      `json:"kperfVersion"`
+	KperfGitRevision  string      `json:"kperfGitRevision,omitempty"`
+	GoVersion         string      `json:"goVersion"`
+	Platform          string      `json:"platform"`
+	KubernetesVersion string      `json:"kubernetesVersion,omitempty"`
+	Knative           KnativeInfo `json:"knative"`
+}
+
+type ProvenanceMetadata struct {
+	BuildInvocationID string `json:"buildInvocationId,omitempty"`
+	BuildFinishedOn   string `json:"buildFinishedOn,omitempty"`
+	Reproducible      bool   `json:"reproducible"`
+}
+
 type ServiceCount struct {
 	ReadyCount    int `json:"Ready"`
 	NotReadyCount int `json:"NotReady"`
 	NotFoundCount int `json:"NotFound"`
 	FailCount     int `json:"Fail"`
+	// ProgressDeadlineExceededCount is the number of services whose revision failed since its Deployment didn't
+	// make progress within the progress deadline, they are not counted as NotReady
+	ProgressDeadlineExceededCount int `json:"ProgressDeadlineExceeded,omitempty"`
+	// TimedOutCount is the number of services whose measurement didn't finish within the per service timeout, they
+	// are not counted as NotReady
+	TimedOutCount int `json:"TimedOut,omitempty"`
+}
+
+const (
+	// FailureAPIError is a service whose resources couldn't be read from the API server
+	FailureAPIError = "APIError"
+	// FailureNotFound is a service which doesn't exist
+	FailureNotFound = "NotFound"
+	// FailureNotReady is a service whose Ready condition isn't true
+	FailureNotReady = "NotReady"
+	// FailureNoTraffic is a Route which routes no traffic to a revision
+	FailureNoTraffic = "NoTraffic"
+	// FailureMissingCondition is a service whose Pod lacks a condition the durations are computed from
+	FailureMissingCondition = "MissingCondition"
+	// FailureMissingContainerStatus is a service whose Pod lacks the status of the queue-proxy or user container
+	FailureMissingContainerStatus = "MissingContainerStatus"
+	// FailureProgressDeadlineExceeded is a service whose Deployment didn't make progress within the deadline
+	FailureProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	// FailureTimeout is a service whose measurement didn't finish within the per service timeout
+	FailureTimeout = "Timeout"
+	// FailureUnknown is a service which couldn't be measured for no known reason
+	FailureUnknown = "Unknown"
+)
+
+// ServiceFailure is why a service couldn't be measured. State is the state it is counted in, Reason one of the
+// Failure constants and Message the message which was logged.
+type ServiceFailure struct {
+	ServiceName      string `json:"svcName"`
+	ServiceNamespace string `json:"svcNamespace"`
+	State            string `json:"state"`
+	Reason           string `json:"reason"`
+	Message          string `json:"message,omitempty"`
+}
+
+// ContainerStarted is the start of a container of a multi-container revision. Started is the duration from the
+// creation of the Pod until the container was running in seconds, Serving marks the container serving the requests.
+type ContainerStarted struct {
+	ServiceName      string  `json:"svcName"`
+	ServiceNamespace string  `json:"svcNamespace"`
+	Container        string  `json:"container"`
+	Serving          bool    `json:"serving"`
+	Started          float64 `json:"started"`
+}
+
+// ImagePull is the image pull of a container of the Pod of a ready service, read from the Pulling and Pulled Events of
+// the kubelet. Pulling and Pulled are the durations from the creation of the Pod until the kubelet started and
+// finished pulling the image, Pull is the time spent pulling and Started the time from the pulled image until the
+// container was running, all in seconds. Cached images were present on the node already and not pulled.
+type ImagePull struct {
+	ServiceName      string  `json:"svcName"`
+	ServiceNamespace string  `json:"svcNamespace"`
+	Container        string  `json:"container"`
+	Image            string  `json:"image"`
+	Cached           bool    `json:"cached"`
+	Pulling          float64 `json:"pulling"`
+	Pulled           float64 `json:"pulled"`
+	Pull             float64 `json:"pull"`
+	Started          float64 `json:"started"`
+}
+
+// GatewayRoute is the programming of the HTTPRoutes the Gateway API ingress implementation created for the Knative
+// Ingress of a ready service, read from their conditions and the ones of the Gateways they are attached to. Created,
+// Accepted and ResolvedRefs are the durations from the creation of the Ingress until the last of the HTTPRoutes was
+// created, accepted by its Gateways and its backends were resolved, GatewayProgrammed until the last of the Gateways
+// was programmed, all in seconds. Gateways programmed before the Ingress was created count as 0.
+type GatewayRoute struct {
+	ServiceName       string   `json:"svcName"`
+	ServiceNamespace  string   `json:"svcNamespace"`
+	HTTPRoutes        int      `json:"httpRoutes"`
+	Gateways          []string `json:"gateways"`
+	Created           float64  `json:"created"`
+	Accepted          float64  `json:"accepted"`
+	ResolvedRefs      float64  `json:"resolvedRefs"`
+	GatewayProgrammed float64  `json:"gatewayProgrammed"`
+}
+
+// FailedRollout is a service which is not ready since the Deployment of its latest revision exceeded the progress
+// deadline. ProgressDeadline is the deadline of the Deployment in seconds, 0 if the Deployment wasn't found.
+type FailedRollout struct {
+	ServiceName      string  `json:"svcName"`
+	ServiceNamespace string  `json:"svcNamespace"`
+	Revision         string  `json:"revision"`
+	Reason           string  `json:"reason"`
+	Message          string  `json:"message,omitempty"`
+	ProgressDeadline float64 `json:"progressDeadline"`
+}
+
+// ControlPlaneProfile holds the time series of the Knative Serving control plane pods sampled during a run
+type ControlPlaneProfile struct {
+	Namespace string            `json:"namespace"`
+	Interval  float64           `json:"interval"`
+	Pods      []ControlPlanePod `json:"pods"`
+}
+
+// ControlPlanePod holds the samples of a control plane pod, the component is the app label of the pod, e.g.
+// controller, autoscaler, activator or webhook
+type ControlPlanePod struct {
+	Name      string               `json:"name"`
+	Component string               `json:"component"`
+	Samples   []ControlPlaneSample `json:"samples"`
+}
+
+// ControlPlaneSample holds the resource usage reported by metrics-server and the scraped Prometheus metrics of a
+// control plane pod at a time, metrics which can't be read are left out
+type ControlPlaneSample struct {
+	Time          time.Time          `json:"time"`
+	CPUMillicores *int64             `json:"cpuMillicores,omitempty"`
+	MemoryBytes   *int64             `json:"memoryBytes,omitempty"`
+	Metrics       map[string]float64 `json:"metrics,omitempty"`
+}
+
+// AutoscalerMetrics holds the time series of the decisions of the Knative Serving autoscaler for the revisions of the
+// services scaled during a run, to tell the time the autoscaler took to decide from the time the pods took to start
+type AutoscalerMetrics struct {
+	Namespace string               `json:"namespace"`
+	Interval  float64              `json:"interval"`
+	Start     time.Time            `json:"start"`
+	Revisions []AutoscalerRevision `json:"revisions"`
+}
+
+// AutoscalerRevision holds the samples of the autoscaler metrics of a revision. FirstScaleUp is the offset in seconds
+// from the start of the scraping until the autoscaler first wanted more pods than it had, nil if it never did.
+type AutoscalerRevision struct {
+	ServiceNamespace string               `json:"serviceNamespace"`
+	ServiceName      string               `json:"serviceName"`
+	Revision         string               `json:"revision"`
+	FirstScaleUp     *float64             `json:"firstScaleUp,omitempty"`
+	Samples          []AutoscalerDecision `json:"samples"`
+}
+
+// AutoscalerDecision holds the autoscaler metrics of a revision at a time, the offset is in seconds from the start of
+// the scraping
+type AutoscalerDecision struct {
+	Time                time.Time `json:"time"`
+	Offset              float64   `json:"offset"`
+	DesiredPods         float64   `json:"desiredPods"`
+	ActualPods          float64   `json:"actualPods"`
+	ExcessBurstCapacity float64   `json:"excessBurstCapacity"`
+	PanicMode           bool      `json:"panicMode"`
 }
 
 type KnativeInfo struct {
@@ -152,6 +1688,7 @@ type Result struct {
 	AverageIngressReadySum                   float64 `json:"AverageIngressReadyDuration"`
 	AverageIngressNetworkConfiguredSum       float64 `json:"AverageIngressNetworkConfiguredDuration"`
 	AverageIngressLoadBalancerReadySum       float64 `json:"AverageIngressLoadBalancerReadyDuration"`
+	AverageCertificateReadySum               float64 `json:"AverageCertificateReadyDuration"`
 	OverallTotal                             float64 `json:"Total"`
 	OverallAverage                           float64 `json:"Average"`
 	OverallMedian                            float64 `json:"Median"`
@@ -162,4 +1699,95 @@ type Result struct {
 	P95                                      float64 `json:"Percentile95"`
 	P98                                      float64 `json:"Percentile98"`
 	P99                                      float64 `json:"Percentile99"`
+	// Phases holds the statistics of every phase but the overall ready one, named like the columns of the
+	// measurement CSV file
+	Phases map[string]PhaseStatistics `json:",omitempty"`
+}
+
+// PhaseStatistics holds the statistics of the durations of a phase of the ready services in seconds
+type PhaseStatistics struct {
+	Min    float64
+	Max    float64
+	Median float64
+	P90    float64 `json:"Percentile90"`
+	P95    float64 `json:"Percentile95"`
+	P99    float64 `json:"Percentile99"`
+}
+
+// RevisionGCResult holds how long the serving controller took to garbage-collect the old revisions of the generated
+// services for every max-non-active-revisions setting
+type RevisionGCResult struct {
+	CreatedAt           string              `json:"createdAt"`
+	KnativeInfo         KnativeInfo         `json:"knativeInfo"`
+	Services            int                 `json:"services"`
+	RevisionsPerService int                 `json:"revisionsPerService"`
+	Settings            []RevisionGCSetting `json:"settings"`
+}
+
+// RevisionGCSetting is the garbage collection of the revisions after max-non-active-revisions was set. Revisions is
+// the number of revisions of the services when the setting was applied, Collected the number deleted until all
+// services were down to the setting or the timeout was over. Lag is the time from the change of the ConfigMap until
+// the last service was collected, ServiceLagP50 and ServiceLagMax are the statistics of the services. Durations are
+// in seconds.
+type RevisionGCSetting struct {
+	MaxNonActiveRevisions int     `json:"maxNonActiveRevisions"`
+	Revisions             int     `json:"revisions"`
+	Collected             int     `json:"collected"`
+	Remaining             int     `json:"remaining"`
+	Lag                   float64 `json:"lag"`
+	ServiceLagP50         float64 `json:"serviceLagPercentile50"`
+	ServiceLagMax         float64 `json:"serviceLagMax"`
+	TimedOut              bool    `json:"timedOut,omitempty"`
+}
+
+// NamespaceSetupResult holds how long the downstream controllers took to set up the generated namespaces
+type NamespaceSetupResult struct {
+	KnativeInfo KnativeInfo            `json:"knativeInfo"`
+	Namespaces  int                    `json:"namespaces"`
+	Probes      []NamespaceProbeResult `json:"probes"`
+	Measurement []NamespaceSetup       `json:"measurement"`
+}
+
+// NamespaceProbeResult are the statistics of a probe over the namespaces in seconds, Missing is the number of
+// namespaces the probe never became ready in, e.g. because the controller isn't installed
+type NamespaceProbeResult struct {
+	Probe   string  `json:"probe"`
+	Ready   int     `json:"ready"`
+	Missing int     `json:"missing"`
+	Average float64 `json:"average"`
+	P50     float64 `json:"percentile50"`
+	P95     float64 `json:"percentile95"`
+	Max     float64 `json:"max"`
+}
+
+// NamespaceSetup holds the durations from the creation of a namespace until every probe which became ready in it
+// was ready, in seconds
+type NamespaceSetup struct {
+	Namespace string             `json:"namespace"`
+	Probes    map[string]float64 `json:"probes"`
+}
+
+// WebhookMeasureResult holds the round-trip latencies of the dry run requests admitted by the webhooks, for every
+// operation over the whole run and for every interval of it
+type WebhookMeasureResult struct {
+	KnativeInfo KnativeInfo      `json:"knativeInfo"`
+	QPS         int              `json:"qps"`
+	Duration    float64          `json:"duration"`
+	Operations  []WebhookLatency `json:"operations"`
+	Windows     []WebhookLatency `json:"windows"`
+}
+
+// WebhookLatency are the statistics of the requests of an operation in seconds. Start is the offset of the interval
+// from the start of the run in seconds, and 0 for the whole run. Skipped is the number of requests not sent as all
+// workers were busy.
+type WebhookLatency struct {
+	Operation string  `json:"operation"`
+	Start     float64 `json:"start"`
+	Requests  int     `json:"requests"`
+	Errors    int     `json:"errors"`
+	Skipped   int     `json:"skipped,omitempty"`
+	P50       float64 `json:"percentile50"`
+	P95       float64 `json:"percentile95"`
+	P99       float64 `json:"percentile99"`
+	Max       float64 `json:"max"`
 }